@@ -0,0 +1,136 @@
+package bookapp
+
+import (
+	"context"
+
+	"github.com/kammeph/school-book-storage-service/application"
+	"github.com/kammeph/school-book-storage-service/domain"
+	"github.com/kammeph/school-book-storage-service/domain/bookdomain"
+)
+
+type BookCommandHandlers struct {
+	AddBookHandler           AddBookCommandHandler
+	AdjustBookMetaHandler    AdjustBookMetaCommandHandler
+	IncreaseBookPriceHandler IncreaseBookPriceCommandHandler
+	DecreaseBookPriceHandler DecreaseBookPriceCommandHandler
+}
+
+func NewBookCommandHandlers(store application.Store, publisher application.EventPublisher) BookCommandHandlers {
+	return BookCommandHandlers{
+		AddBookHandler:           NewAddBookCommandHandler(store, publisher),
+		AdjustBookMetaHandler:    NewAdjustBookMetaCommandHandler(store, publisher),
+		IncreaseBookPriceHandler: NewIncreaseBookPriceCommandHandler(store, publisher),
+		DecreaseBookPriceHandler: NewDecreaseBookPriceCommandHandler(store, publisher),
+	}
+}
+
+type AddBookCommand struct {
+	application.CommandModel
+	Isbn        string       `json:"isbn"`
+	Name        string       `json:"name"`
+	Description string       `json:"description"`
+	Price       domain.Money `json:"price"`
+	Grades      []int        `json:"grades"`
+}
+
+type AddBookCommandHandler struct {
+	*application.CommandHandlerModel
+}
+
+func NewAddBookCommandHandler(store application.Store, publisher application.EventPublisher) AddBookCommandHandler {
+	return AddBookCommandHandler{application.NewCommandHandlerModel(store, publisher)}
+}
+
+func (h AddBookCommandHandler) Handle(ctx context.Context, command AddBookCommand) (string, error) {
+	aggregate := bookdomain.NewSchoolBookAggregateWithID(command.AggregateID())
+	if err := h.LoadAggregate(ctx, aggregate); err != nil {
+		return "", err
+	}
+	bookID, err := aggregate.AddBook(command.Isbn, command.Name, command.Description, command.Price, command.Grades)
+	if err != nil {
+		return "", err
+	}
+	if err := h.SaveAndPublish(ctx, aggregate); err != nil {
+		return "", err
+	}
+	return bookID, nil
+}
+
+type AdjustBookMetaCommand struct {
+	application.CommandModel
+	BookID      string `json:"bookId"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Grades      []int  `json:"grades"`
+}
+
+type AdjustBookMetaCommandHandler struct {
+	*application.CommandHandlerModel
+}
+
+func NewAdjustBookMetaCommandHandler(store application.Store, publisher application.EventPublisher) AdjustBookMetaCommandHandler {
+	return AdjustBookMetaCommandHandler{application.NewCommandHandlerModel(store, publisher)}
+}
+
+func (h AdjustBookMetaCommandHandler) Handle(ctx context.Context, command AdjustBookMetaCommand) error {
+	aggregate := bookdomain.NewSchoolBookAggregateWithID(command.AggregateID())
+	if err := h.LoadAggregate(ctx, aggregate); err != nil {
+		return err
+	}
+	if err := aggregate.AdjustBookMeta(command.BookID, command.Name, command.Description, command.Grades); err != nil {
+		return err
+	}
+	return h.SaveAndPublish(ctx, aggregate)
+}
+
+type IncreaseBookPriceCommand struct {
+	application.CommandModel
+	BookID string       `json:"bookId"`
+	Price  domain.Money `json:"price"`
+	Reason string       `json:"reason"`
+}
+
+type IncreaseBookPriceCommandHandler struct {
+	*application.CommandHandlerModel
+}
+
+func NewIncreaseBookPriceCommandHandler(store application.Store, publisher application.EventPublisher) IncreaseBookPriceCommandHandler {
+	return IncreaseBookPriceCommandHandler{application.NewCommandHandlerModel(store, publisher)}
+}
+
+func (h IncreaseBookPriceCommandHandler) Handle(ctx context.Context, command IncreaseBookPriceCommand) error {
+	aggregate := bookdomain.NewSchoolBookAggregateWithID(command.AggregateID())
+	if err := h.LoadAggregate(ctx, aggregate); err != nil {
+		return err
+	}
+	if err := aggregate.IncreaseBookPrice(command.BookID, command.Price, command.Reason); err != nil {
+		return err
+	}
+	return h.SaveAndPublish(ctx, aggregate)
+}
+
+type DecreaseBookPriceCommand struct {
+	application.CommandModel
+	BookID string       `json:"bookId"`
+	Price  domain.Money `json:"price"`
+	Reason string       `json:"reason"`
+}
+
+type DecreaseBookPriceCommandHandler struct {
+	*application.CommandHandlerModel
+}
+
+func NewDecreaseBookPriceCommandHandler(store application.Store, publisher application.EventPublisher) DecreaseBookPriceCommandHandler {
+	return DecreaseBookPriceCommandHandler{application.NewCommandHandlerModel(store, publisher)}
+}
+
+func (h DecreaseBookPriceCommandHandler) Handle(ctx context.Context, command DecreaseBookPriceCommand) error {
+	aggregate := bookdomain.NewSchoolBookAggregateWithID(command.AggregateID())
+	if err := h.LoadAggregate(ctx, aggregate); err != nil {
+		return err
+	}
+	if err := aggregate.DecreaseBookPrice(command.BookID, command.Price, command.Reason); err != nil {
+		return err
+	}
+	return h.SaveAndPublish(ctx, aggregate)
+}
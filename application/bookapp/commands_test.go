@@ -0,0 +1,80 @@
+package bookapp_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kammeph/school-book-storage-service/application"
+	"github.com/kammeph/school-book-storage-service/application/bookapp"
+	"github.com/kammeph/school-book-storage-service/domain/bookdomain"
+	"github.com/kammeph/school-book-storage-service/infrastructure/memory"
+	"github.com/kammeph/school-book-storage-service/testing/fixtures"
+	"github.com/stretchr/testify/assert"
+)
+
+func loadBooks(t *testing.T, store application.Store) *bookdomain.SchoolBookAggregate {
+	aggregate := bookdomain.NewSchoolBookAggregateWithID("school")
+	assert.Nil(t, application.NewCommandHandlerModel(store, nil).LoadAggregate(context.Background(), aggregate))
+	return aggregate
+}
+
+func TestHandleAddBook(t *testing.T) {
+	ctx := context.Background()
+	store := memory.NewMemoryStore()
+	handler := bookapp.NewAddBookCommandHandler(store, nil)
+	command := bookapp.AddBookCommand{
+		CommandModel: application.CommandModel{ID: "school"},
+		Isbn:         "978-3-12-345678-7",
+		Name:         "Math 5",
+		Description:  "Math for grade 5",
+		Price:        fixtures.Euro(2450),
+		Grades:       []int{5},
+	}
+	bookID, err := handler.Handle(ctx, command)
+	assert.Nil(t, err)
+	assert.NotEqual(t, "", bookID)
+	_, err = handler.Handle(ctx, command)
+	assert.Error(t, err)
+	command.Isbn = "9783123456787"
+	command.Name = "Math 5 new edition"
+	_, err = handler.Handle(ctx, command)
+	assert.Error(t, err)
+
+	aggregate := loadBooks(t, store)
+	assert.Len(t, aggregate.Books, 1)
+	assert.Equal(t, bookID, aggregate.Books[0].ID)
+}
+
+func TestHandleChangeBook(t *testing.T) {
+	ctx := context.Background()
+	store := memory.NewMemoryStore()
+	handlers := bookapp.NewBookCommandHandlers(store, nil)
+	bookID, err := handlers.AddBookHandler.Handle(ctx, bookapp.AddBookCommand{
+		CommandModel: application.CommandModel{ID: "school"},
+		Isbn:         "978-3-12-345678-7",
+		Name:         "Math 5",
+		Price:        fixtures.Euro(2450),
+		Grades:       []int{5},
+	})
+	assert.Nil(t, err)
+
+	adjust := bookapp.AdjustBookMetaCommand{
+		CommandModel: application.CommandModel{ID: "school"},
+		BookID:       bookID,
+		Name:         "Math 5/6",
+		Description:  "Math for grade 5 and 6",
+		Grades:       []int{5, 6},
+	}
+	assert.Nil(t, handlers.AdjustBookMetaHandler.Handle(ctx, adjust))
+	increase := bookapp.IncreaseBookPriceCommand{CommandModel: application.CommandModel{ID: "school"}, BookID: bookID, Price: fixtures.Euro(2600), Reason: "new edition"}
+	assert.Nil(t, handlers.IncreaseBookPriceHandler.Handle(ctx, increase))
+	decrease := bookapp.DecreaseBookPriceCommand{CommandModel: application.CommandModel{ID: "school"}, BookID: bookID, Price: fixtures.Euro(2500)}
+	assert.Error(t, handlers.DecreaseBookPriceHandler.Handle(ctx, decrease))
+	decrease.Reason = "discount"
+	assert.Nil(t, handlers.DecreaseBookPriceHandler.Handle(ctx, decrease))
+
+	aggregate := loadBooks(t, store)
+	assert.Equal(t, "Math 5/6", aggregate.Books[0].Name)
+	assert.Equal(t, []int{5, 6}, aggregate.Books[0].Grades)
+	assert.Equal(t, fixtures.Euro(2500), aggregate.Books[0].Price)
+}
@@ -0,0 +1,102 @@
+package bookapp
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/kammeph/school-book-storage-service/application"
+	"github.com/kammeph/school-book-storage-service/domain"
+	"github.com/kammeph/school-book-storage-service/domain/bookdomain"
+)
+
+type BookEventHandler struct {
+	repository BookRepository
+}
+
+func NewBookEventHandler(repository BookRepository) application.EventHandler {
+	return &BookEventHandler{repository}
+}
+
+func (h BookEventHandler) Handle(ctx context.Context, eventBytes []byte) error {
+	event := &domain.EventModel{}
+	if err := json.Unmarshal(eventBytes, event); err != nil {
+		return err
+	}
+	switch event.EventType() {
+	case bookdomain.BookAdded:
+		return h.handleBookAdded(ctx, event)
+	case bookdomain.BookMetaAdjusted:
+		return h.handleBookMetaAdjusted(ctx, event)
+	case bookdomain.BookPriceIncreased:
+		return h.handleBookPriceIncreased(ctx, event)
+	case bookdomain.BookPriceDecreased:
+		return h.handleBookPriceDecreased(ctx, event)
+	default:
+		return nil
+	}
+}
+
+func (h BookEventHandler) handleBookAdded(ctx context.Context, event domain.Event) error {
+	bookAdded := bookdomain.BookAddedEvent{}
+	if err := event.GetJsonData(&bookAdded); err != nil {
+		return err
+	}
+	book := bookdomain.NewBookProjection(
+		bookAdded.SchoolID,
+		bookAdded.BookID,
+		bookAdded.Isbn,
+		bookAdded.Name,
+		bookAdded.Description,
+		bookAdded.Price,
+		bookAdded.Grades,
+		event.EventVersion())
+	book.PriceHistory = []bookdomain.PriceChange{{Price: bookAdded.Price, ValidFrom: event.EventAt()}}
+	return h.repository.UpsertBook(ctx, book)
+}
+
+func (h BookEventHandler) handleBookMetaAdjusted(ctx context.Context, event domain.Event) error {
+	metaAdjusted := bookdomain.BookMetaAdjustedEvent{}
+	if err := event.GetJsonData(&metaAdjusted); err != nil {
+		return err
+	}
+	return h.repository.UpdateBookMeta(
+		ctx,
+		metaAdjusted.BookID,
+		metaAdjusted.Name,
+		metaAdjusted.Description,
+		metaAdjusted.Grades,
+		event.EventVersion())
+}
+
+func (h BookEventHandler) handleBookPriceIncreased(ctx context.Context, event domain.Event) error {
+	priceIncreased := bookdomain.BookPriceIncreasedEvent{}
+	if err := event.GetJsonData(&priceIncreased); err != nil {
+		return err
+	}
+	return h.changePrice(ctx, event, priceIncreased.BookID, priceIncreased.Price)
+}
+
+func (h BookEventHandler) handleBookPriceDecreased(ctx context.Context, event domain.Event) error {
+	priceDecreased := bookdomain.BookPriceDecreasedEvent{}
+	if err := event.GetJsonData(&priceDecreased); err != nil {
+		return err
+	}
+	return h.changePrice(ctx, event, priceDecreased.BookID, priceDecreased.Price)
+}
+
+// changePrice sets the price of the book and appends it to the price history.
+// Events the book already reflects are skipped, so a redelivered change is not
+// recorded twice.
+func (h BookEventHandler) changePrice(ctx context.Context, event domain.Event, bookID string, price domain.Money) error {
+	book, err := h.repository.GetBookByID(ctx, event.AggregateID(), bookID)
+	if err != nil {
+		return err
+	}
+	if book.Version >= event.EventVersion() {
+		return nil
+	}
+	history := append(
+		append([]bookdomain.PriceChange{}, book.PriceHistory...),
+		bookdomain.PriceChange{Price: price, ValidFrom: event.EventAt()})
+	return h.repository.UpdateBookPrice(ctx, bookID, price, history, event.EventVersion())
+}
@@ -0,0 +1,58 @@
+package bookapp_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/kammeph/school-book-storage-service/application/bookapp"
+	"github.com/kammeph/school-book-storage-service/domain"
+	"github.com/kammeph/school-book-storage-service/domain/bookdomain"
+	"github.com/kammeph/school-book-storage-service/infrastructure/memory"
+	"github.com/kammeph/school-book-storage-service/testing/fixtures"
+	"github.com/stretchr/testify/assert"
+)
+
+func bookEvent(version int, eventType, data string) []byte {
+	eventBytes, _ := json.Marshal(domain.EventModel{
+		ID:      "school",
+		Type:    eventType,
+		Version: version,
+		At:      time.Now(),
+		Data:    data,
+	})
+	return eventBytes
+}
+
+func TestHandleBookEvents(t *testing.T) {
+	ctx := context.Background()
+	repository := memory.NewMemoryBookRepository()
+	handler := bookapp.NewBookEventHandler(repository)
+	events := [][]byte{
+		bookEvent(1, bookdomain.BookAdded, "{\"SchoolID\":\"school\",\"BookID\":\"book1\",\"Isbn\":\"123\",\"Name\":\"Math 5\",\"Price\":24.5,\"Grades\":[5]}"),
+		bookEvent(2, bookdomain.BookAdded, "{\"SchoolID\":\"school\",\"BookID\":\"book2\",\"Isbn\":\"456\",\"Name\":\"English 7\",\"Price\":19.9,\"Grades\":[7]}"),
+		bookEvent(3, bookdomain.BookMetaAdjusted, "{\"BookID\":\"book1\",\"Name\":\"Math 5/6\",\"Description\":\"Math\",\"Grades\":[5,6]}"),
+		bookEvent(4, bookdomain.BookPriceIncreased, "{\"BookID\":\"book1\",\"Price\":26,\"Reason\":\"new edition\"}"),
+		bookEvent(5, bookdomain.BookPriceDecreased, "{\"BookID\":\"book2\",\"Price\":{\"amount\":1750,\"currency\":\"EUR\"},\"Reason\":\"discount\"}"),
+	}
+	for _, event := range events {
+		assert.Nil(t, handler.Handle(ctx, event))
+	}
+	// an outdated event must not overwrite the newer price
+	assert.Nil(t, handler.Handle(ctx, bookEvent(3, bookdomain.BookPriceIncreased, "{\"BookID\":\"book1\",\"Price\":30,\"Reason\":\"test\"}")))
+
+	book, err := repository.GetBookByID(ctx, "school", "book1")
+	assert.Nil(t, err)
+	assert.Equal(t, "Math 5/6", book.Name)
+	assert.Equal(t, fixtures.Euro(2600), book.Price)
+	assert.Equal(t, []domain.Money{fixtures.Euro(2450), fixtures.Euro(2600)}, []domain.Money{book.PriceHistory[0].Price, book.PriceHistory[1].Price})
+	books, err := repository.GetBooksByGrade(ctx, "school", 6)
+	assert.Nil(t, err)
+	assert.Len(t, books, 1)
+	book, err = repository.GetBookByID(ctx, "school", "book2")
+	assert.Nil(t, err)
+	assert.Equal(t, fixtures.Euro(1750), book.Price)
+
+	assert.Error(t, handler.Handle(ctx, bookEvent(6, bookdomain.BookAdded, "{\"BookID\":")))
+}
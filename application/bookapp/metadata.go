@@ -0,0 +1,137 @@
+package bookapp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kammeph/school-book-storage-service/application"
+	"github.com/kammeph/school-book-storage-service/domain"
+	"github.com/kammeph/school-book-storage-service/domain/bookdomain"
+)
+
+var ErrMetadataNotFound = errors.New("no metadata found for isbn")
+
+func ErrMetadataWithIsbnNotFound(isbn domain.Isbn) error {
+	return fmt.Errorf("%w %s", ErrMetadataNotFound, isbn)
+}
+
+type BookMetadata struct {
+	Isbn      domain.Isbn `json:"isbn"`
+	Title     string      `json:"title"`
+	Authors   []string    `json:"authors"`
+	Publisher string      `json:"publisher"`
+	Edition   string      `json:"edition"`
+	CoverURL  string      `json:"coverUrl"`
+}
+
+// MetadataProvider looks up the bibliographic data of a book. It returns an
+// error wrapping ErrMetadataNotFound if the provider does not know the ISBN.
+type MetadataProvider interface {
+	Lookup(ctx context.Context, isbn domain.Isbn) (BookMetadata, error)
+}
+
+type cachedMetadata struct {
+	metadata  BookMetadata
+	expiresAt time.Time
+}
+
+// CachingMetadataProvider keeps the results of another provider for the given
+// time to live. Failed lookups are not cached.
+type CachingMetadataProvider struct {
+	provider MetadataProvider
+	ttl      time.Duration
+	mutex    sync.Mutex
+	entries  map[domain.Isbn]cachedMetadata
+}
+
+func NewCachingMetadataProvider(provider MetadataProvider, ttl time.Duration) *CachingMetadataProvider {
+	return &CachingMetadataProvider{provider: provider, ttl: ttl, entries: map[domain.Isbn]cachedMetadata{}}
+}
+
+func (p *CachingMetadataProvider) Lookup(ctx context.Context, isbn domain.Isbn) (BookMetadata, error) {
+	p.mutex.Lock()
+	entry, ok := p.entries[isbn]
+	p.mutex.Unlock()
+	if ok && time.Now().Before(entry.expiresAt) {
+		return entry.metadata, nil
+	}
+	metadata, err := p.provider.Lookup(ctx, isbn)
+	if err != nil {
+		return BookMetadata{}, err
+	}
+	p.mutex.Lock()
+	p.entries[isbn] = cachedMetadata{metadata, time.Now().Add(p.ttl)}
+	p.mutex.Unlock()
+	return metadata, nil
+}
+
+type GetAddBookTemplate struct {
+	application.QueryModel
+	Isbn string
+}
+
+func NewGetAddBookTemplate(aggregateID, isbn string) GetAddBookTemplate {
+	return GetAddBookTemplate{QueryModel: application.QueryModel{ID: aggregateID}, Isbn: isbn}
+}
+
+// AddBookTemplate is an AddBookCommand pre-filled with the metadata of the
+// book. Price and grades are left for the user to fill in.
+type AddBookTemplate struct {
+	Command  AddBookCommand `json:"command"`
+	Metadata BookMetadata   `json:"metadata"`
+}
+
+type GetAddBookTemplateQueryHandler struct {
+	repository BookRepository
+	provider   MetadataProvider
+}
+
+func NewGetAddBookTemplateQueryHandler(repository BookRepository, provider MetadataProvider) GetAddBookTemplateQueryHandler {
+	return GetAddBookTemplateQueryHandler{repository: repository, provider: provider}
+}
+
+func (h GetAddBookTemplateQueryHandler) Handle(ctx context.Context, query GetAddBookTemplate) (AddBookTemplate, error) {
+	isbn, err := domain.ParseIsbn(query.Isbn)
+	if err != nil {
+		return AddBookTemplate{}, err
+	}
+	books, err := h.repository.GetBooksBySchoolID(ctx, query.AggregateID())
+	if err != nil {
+		return AddBookTemplate{}, err
+	}
+	for _, book := range books {
+		if book.Isbn == isbn {
+			return AddBookTemplate{}, bookdomain.ErrBookAlreadyExists(isbn, book.Name)
+		}
+	}
+	metadata, err := h.provider.Lookup(ctx, isbn)
+	if err != nil {
+		return AddBookTemplate{}, err
+	}
+	command := AddBookCommand{
+		CommandModel: application.CommandModel{ID: query.AggregateID()},
+		Isbn:         isbn.Hyphenated(),
+		Name:         metadata.Title,
+		Description:  describe(metadata),
+		Price:        domain.NewMoney(0, domain.DefaultCurrency),
+		Grades:       []int{},
+	}
+	return AddBookTemplate{command, metadata}, nil
+}
+
+func describe(metadata BookMetadata) string {
+	parts := []string{}
+	if len(metadata.Authors) > 0 {
+		parts = append(parts, strings.Join(metadata.Authors, ", "))
+	}
+	for _, part := range []string{metadata.Publisher, metadata.Edition} {
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return strings.Join(parts, "; ")
+}
@@ -0,0 +1,86 @@
+package bookapp_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/kammeph/school-book-storage-service/application/bookapp"
+	"github.com/kammeph/school-book-storage-service/domain"
+	"github.com/kammeph/school-book-storage-service/domain/bookdomain"
+	"github.com/kammeph/school-book-storage-service/infrastructure/memory"
+	"github.com/kammeph/school-book-storage-service/testing/fixtures"
+	"github.com/stretchr/testify/assert"
+)
+
+var greenLine = bookapp.BookMetadata{
+	Isbn:      "978-3-12-732320-7",
+	Title:     "Green Line 1",
+	Authors:   []string{"Harald Weisshaar", "Marion Horner"},
+	Publisher: "Klett",
+	Edition:   "1. Auflage",
+}
+
+type countingMetadataProvider struct {
+	provider bookapp.MetadataProvider
+	lookups  int
+}
+
+func (p *countingMetadataProvider) Lookup(ctx context.Context, isbn domain.Isbn) (bookapp.BookMetadata, error) {
+	p.lookups++
+	return p.provider.Lookup(ctx, isbn)
+}
+
+func TestCachingMetadataProvider(t *testing.T) {
+	ctx := context.Background()
+	provider, err := memory.NewMemoryMetadataProvider(greenLine)
+	assert.NoError(t, err)
+	counting := &countingMetadataProvider{provider: provider}
+	cache := bookapp.NewCachingMetadataProvider(counting, time.Hour)
+
+	for i := 0; i < 2; i++ {
+		metadata, err := cache.Lookup(ctx, "9783127323207")
+		assert.NoError(t, err)
+		assert.Equal(t, "Green Line 1", metadata.Title)
+	}
+	assert.Equal(t, 1, counting.lookups)
+
+	for i := 0; i < 2; i++ {
+		_, err := cache.Lookup(ctx, "9783161484100")
+		assert.True(t, errors.Is(err, bookapp.ErrMetadataNotFound))
+	}
+	assert.Equal(t, 3, counting.lookups)
+
+	expiring := bookapp.NewCachingMetadataProvider(counting, 0)
+	expiring.Lookup(ctx, "9783127323207")
+	expiring.Lookup(ctx, "9783127323207")
+	assert.Equal(t, 5, counting.lookups)
+}
+
+func TestHandleGetAddBookTemplate(t *testing.T) {
+	ctx := context.Background()
+	repository := memory.NewMemoryBookRepository()
+	repository.UpsertBook(ctx, bookdomain.NewBookProjection("school", "book", "9783123456787", "Math 5", "", fixtures.Euro(2450), []int{5}, 1))
+	provider, err := memory.NewMemoryMetadataProvider(greenLine)
+	assert.NoError(t, err)
+	handler := bookapp.NewGetAddBookTemplateQueryHandler(repository, provider)
+
+	template, err := handler.Handle(ctx, bookapp.NewGetAddBookTemplate("school", "3-12-732320-4"))
+	assert.NoError(t, err)
+	assert.Equal(t, "school", template.Command.AggregateID())
+	assert.Equal(t, "978-3-12-732320-7", template.Command.Isbn)
+	assert.Equal(t, "Green Line 1", template.Command.Name)
+	assert.Equal(t, "Harald Weisshaar, Marion Horner; Klett; 1. Auflage", template.Command.Description)
+	assert.Equal(t, domain.NewMoney(0, domain.DefaultCurrency), template.Command.Price)
+	assert.Equal(t, domain.Isbn("9783127323207"), template.Metadata.Isbn)
+
+	_, err = handler.Handle(ctx, bookapp.NewGetAddBookTemplate("school", "978-3-12-345678-7"))
+	assert.Equal(t, bookdomain.ErrBookAlreadyExists("9783123456787", "Math 5"), err)
+
+	_, err = handler.Handle(ctx, bookapp.NewGetAddBookTemplate("school", "978-3-16-148410-0"))
+	assert.True(t, errors.Is(err, bookapp.ErrMetadataNotFound))
+
+	_, err = handler.Handle(ctx, bookapp.NewGetAddBookTemplate("school", "123"))
+	assert.Equal(t, domain.ErrInvalidIsbn, err)
+}
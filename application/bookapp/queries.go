@@ -0,0 +1,123 @@
+package bookapp
+
+import (
+	"context"
+
+	"github.com/kammeph/school-book-storage-service/application"
+	"github.com/kammeph/school-book-storage-service/application/labelapp"
+	"github.com/kammeph/school-book-storage-service/domain/bookdomain"
+)
+
+type BookQueryHandlers struct {
+	GetAllHandler      GetAllBooksQueryHandler
+	GetByGradeHandler  GetBooksByGradeQueryHandler
+	GetBookByIDHandler GetBookByIDQueryHandler
+	GetTemplateHandler GetAddBookTemplateQueryHandler
+	GetLabelsHandler   GetBookLabelsQueryHandler
+}
+
+func NewBookQueryHandlers(repository BookRepository, provider MetadataProvider) BookQueryHandlers {
+	return BookQueryHandlers{
+		GetAllHandler:      NewGetAllBooksQueryHandler(repository),
+		GetByGradeHandler:  NewGetBooksByGradeQueryHandler(repository),
+		GetBookByIDHandler: NewGetBookByIDQueryHandler(repository),
+		GetTemplateHandler: NewGetAddBookTemplateQueryHandler(repository, provider),
+		GetLabelsHandler:   NewGetBookLabelsQueryHandler(repository),
+	}
+}
+
+type GetAllBooks struct {
+	application.QueryModel
+}
+
+func NewGetAllBooks(aggregateID string) GetAllBooks {
+	return GetAllBooks{QueryModel: application.QueryModel{ID: aggregateID}}
+}
+
+type GetAllBooksQueryHandler struct {
+	repository BookRepository
+}
+
+func NewGetAllBooksQueryHandler(repository BookRepository) GetAllBooksQueryHandler {
+	return GetAllBooksQueryHandler{repository: repository}
+}
+
+func (h GetAllBooksQueryHandler) Handle(ctx context.Context, query GetAllBooks) ([]bookdomain.BookProjection, error) {
+	return h.repository.GetBooksBySchoolID(ctx, query.AggregateID())
+}
+
+type GetBooksByGrade struct {
+	application.QueryModel
+	Grade int
+}
+
+func NewGetBooksByGrade(aggregateID string, grade int) GetBooksByGrade {
+	return GetBooksByGrade{QueryModel: application.QueryModel{ID: aggregateID}, Grade: grade}
+}
+
+type GetBooksByGradeQueryHandler struct {
+	repository BookRepository
+}
+
+func NewGetBooksByGradeQueryHandler(repository BookRepository) GetBooksByGradeQueryHandler {
+	return GetBooksByGradeQueryHandler{repository: repository}
+}
+
+func (h GetBooksByGradeQueryHandler) Handle(ctx context.Context, query GetBooksByGrade) ([]bookdomain.BookProjection, error) {
+	return h.repository.GetBooksByGrade(ctx, query.AggregateID(), query.Grade)
+}
+
+type GetBookByID struct {
+	application.QueryModel
+	BookID string
+}
+
+func NewGetBookByID(aggregateID, bookID string) GetBookByID {
+	return GetBookByID{QueryModel: application.QueryModel{ID: aggregateID}, BookID: bookID}
+}
+
+type GetBookByIDQueryHandler struct {
+	repository BookRepository
+}
+
+func NewGetBookByIDQueryHandler(repository BookRepository) GetBookByIDQueryHandler {
+	return GetBookByIDQueryHandler{repository: repository}
+}
+
+func (h GetBookByIDQueryHandler) Handle(ctx context.Context, query GetBookByID) (bookdomain.BookProjection, error) {
+	return h.repository.GetBookByID(ctx, query.AggregateID(), query.BookID)
+}
+
+// GetBookLabels selects the books to print ISBN labels for. Copies is the
+// number of labels per book.
+type GetBookLabels struct {
+	application.QueryModel
+	BookIDs []string
+	Copies  int
+}
+
+func NewGetBookLabels(aggregateID string, bookIDs []string, copies int) GetBookLabels {
+	return GetBookLabels{QueryModel: application.QueryModel{ID: aggregateID}, BookIDs: bookIDs, Copies: copies}
+}
+
+type GetBookLabelsQueryHandler struct {
+	repository BookRepository
+}
+
+func NewGetBookLabelsQueryHandler(repository BookRepository) GetBookLabelsQueryHandler {
+	return GetBookLabelsQueryHandler{repository: repository}
+}
+
+func (h GetBookLabelsQueryHandler) Handle(ctx context.Context, query GetBookLabels) ([]labelapp.Label, error) {
+	labels := []labelapp.Label{}
+	for _, bookID := range query.BookIDs {
+		book, err := h.repository.GetBookByID(ctx, query.AggregateID(), bookID)
+		if err != nil {
+			return nil, err
+		}
+		for i := 0; i < query.Copies; i++ {
+			labels = append(labels, labelapp.NewIsbnLabel(book.Isbn, book.Name))
+		}
+	}
+	return labels, nil
+}
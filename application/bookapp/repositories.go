@@ -0,0 +1,17 @@
+package bookapp
+
+import (
+	"context"
+
+	"github.com/kammeph/school-book-storage-service/domain"
+	"github.com/kammeph/school-book-storage-service/domain/bookdomain"
+)
+
+type BookRepository interface {
+	GetBooksBySchoolID(ctx context.Context, schoolID string) ([]bookdomain.BookProjection, error)
+	GetBooksByGrade(ctx context.Context, schoolID string, grade int) ([]bookdomain.BookProjection, error)
+	GetBookByID(ctx context.Context, schoolID, bookID string) (bookdomain.BookProjection, error)
+	UpsertBook(ctx context.Context, book bookdomain.BookProjection) error
+	UpdateBookMeta(ctx context.Context, bookID, name, description string, grades []int, version int) error
+	UpdateBookPrice(ctx context.Context, bookID string, price domain.Money, history []bookdomain.PriceChange, version int) error
+}
@@ -0,0 +1,207 @@
+package chargeapp
+
+import (
+	"context"
+	"time"
+
+	"github.com/kammeph/school-book-storage-service/application"
+	"github.com/kammeph/school-book-storage-service/domain/bookdomain"
+	"github.com/kammeph/school-book-storage-service/domain/chargedomain"
+	"github.com/kammeph/school-book-storage-service/domain/loandomain"
+	"github.com/kammeph/school-book-storage-service/domain/pupildomain"
+	"github.com/kammeph/school-book-storage-service/fp"
+)
+
+type ChargeCommandHandlers struct {
+	ConfigureDepreciationHandler ConfigureDepreciationCommandHandler
+	CreateChargeHandler          CreateChargeCommandHandler
+	PayChargeHandler             PayChargeCommandHandler
+	WaiveChargeHandler           WaiveChargeCommandHandler
+}
+
+func NewChargeCommandHandlers(
+	store application.Store,
+	publisher application.EventPublisher,
+	bookStore application.Store,
+	pupilStore application.Store,
+	loanStore application.Store,
+) ChargeCommandHandlers {
+	return ChargeCommandHandlers{
+		ConfigureDepreciationHandler: NewConfigureDepreciationCommandHandler(store, publisher),
+		CreateChargeHandler:          NewCreateChargeCommandHandler(store, publisher, bookStore, pupilStore, loanStore),
+		PayChargeHandler:             NewPayChargeCommandHandler(store, publisher),
+		WaiveChargeHandler:           NewWaiveChargeCommandHandler(store, publisher),
+	}
+}
+
+type ConfigureDepreciationCommand struct {
+	application.CommandModel
+	RatePerYear float64 `json:"ratePerYear"`
+	Minimum     float64 `json:"minimum"`
+}
+
+type ConfigureDepreciationCommandHandler struct {
+	*application.CommandHandlerModel
+}
+
+func NewConfigureDepreciationCommandHandler(store application.Store, publisher application.EventPublisher) ConfigureDepreciationCommandHandler {
+	return ConfigureDepreciationCommandHandler{application.NewCommandHandlerModel(store, publisher)}
+}
+
+func (h ConfigureDepreciationCommandHandler) Handle(ctx context.Context, command ConfigureDepreciationCommand) error {
+	aggregate := chargedomain.NewSchoolChargeAggregateWithID(command.AggregateID())
+	if err := h.LoadAggregate(ctx, aggregate); err != nil {
+		return err
+	}
+	depreciation := chargedomain.Depreciation{RatePerYear: command.RatePerYear, Minimum: command.Minimum}
+	if err := aggregate.ConfigureDepreciation(depreciation); err != nil {
+		return err
+	}
+	return h.SaveAndPublish(ctx, aggregate)
+}
+
+type CreateChargeCommand struct {
+	application.CommandModel
+	PupilID     string                `json:"pupilId"`
+	BookID      string                `json:"bookId"`
+	Incident    chargedomain.Incident `json:"incident"`
+	LoanID      string                `json:"loanId"`
+	Description string                `json:"description"`
+	AcquiredAt  time.Time             `json:"acquiredAt"`
+}
+
+type CreateChargeCommandHandler struct {
+	*application.CommandHandlerModel
+	books  *application.CommandHandlerModel
+	pupils *application.CommandHandlerModel
+	loans  *application.CommandHandlerModel
+}
+
+func NewCreateChargeCommandHandler(
+	store application.Store,
+	publisher application.EventPublisher,
+	bookStore application.Store,
+	pupilStore application.Store,
+	loanStore application.Store,
+) CreateChargeCommandHandler {
+	return CreateChargeCommandHandler{
+		CommandHandlerModel: application.NewCommandHandlerModel(store, publisher),
+		books:               application.NewCommandHandlerModel(bookStore, nil),
+		pupils:              application.NewCommandHandlerModel(pupilStore, nil),
+		loans:               application.NewCommandHandlerModel(loanStore, nil),
+	}
+}
+
+// Handle charges the pupil for the book at its current price. A loan of the
+// incident has to be a loan of the book to the pupil. Without an acquisition
+// date the book is depreciated since it was added to the school.
+func (h CreateChargeCommandHandler) Handle(ctx context.Context, command CreateChargeCommand) (string, error) {
+	pupils := pupildomain.NewSchoolPupilAggregateWithID(command.AggregateID())
+	if err := h.pupils.LoadAggregate(ctx, pupils); err != nil {
+		return "", err
+	}
+	if !fp.Some(pupils.Pupils, func(p pupildomain.Pupil) bool { return p.ID == command.PupilID }) {
+		return "", pupildomain.ErrPupilWithIDNotFound(command.PupilID)
+	}
+	books := bookdomain.NewSchoolBookAggregateWithID(command.AggregateID())
+	if err := h.books.LoadAggregate(ctx, books); err != nil {
+		return "", err
+	}
+	book := fp.Find(books.Books, func(b bookdomain.Book) bool { return b.ID == command.BookID })
+	if book == nil {
+		return "", bookdomain.ErrBookWithIDNotFound(command.BookID)
+	}
+	if command.LoanID != "" {
+		loans := loandomain.NewSchoolLoanAggregateWithID(command.AggregateID())
+		if err := h.loans.LoadAggregate(ctx, loans); err != nil {
+			return "", err
+		}
+		if !fp.Some(loans.Loans, func(l loandomain.Loan) bool {
+			return l.ID == command.LoanID && l.PupilID == command.PupilID && l.BookID == command.BookID
+		}) {
+			return "", chargedomain.ErrLoanNotOfPupilAndBook(command.LoanID, command.PupilID, command.BookID)
+		}
+	}
+	acquiredAt := command.AcquiredAt
+	if acquiredAt.IsZero() {
+		acquiredAt = book.CreatedAt
+	}
+	aggregate := chargedomain.NewSchoolChargeAggregateWithID(command.AggregateID())
+	if err := h.LoadAggregate(ctx, aggregate); err != nil {
+		return "", err
+	}
+	chargeID, err := aggregate.CreateCharge(
+		command.PupilID,
+		book.ID,
+		book.Isbn,
+		book.Name,
+		command.Incident,
+		command.LoanID,
+		command.Description,
+		book.Price,
+		acquiredAt,
+		time.Now())
+	if err != nil {
+		return "", err
+	}
+	if err := h.SaveAndPublish(ctx, aggregate); err != nil {
+		return "", err
+	}
+	return chargeID, nil
+}
+
+type PayChargeCommand struct {
+	application.CommandModel
+	ChargeID string    `json:"chargeId"`
+	PaidAt   time.Time `json:"paidAt"`
+}
+
+type PayChargeCommandHandler struct {
+	*application.CommandHandlerModel
+}
+
+func NewPayChargeCommandHandler(store application.Store, publisher application.EventPublisher) PayChargeCommandHandler {
+	return PayChargeCommandHandler{application.NewCommandHandlerModel(store, publisher)}
+}
+
+// Handle records the payment of the charge. A missing payment date defaults to
+// now.
+func (h PayChargeCommandHandler) Handle(ctx context.Context, command PayChargeCommand) error {
+	aggregate := chargedomain.NewSchoolChargeAggregateWithID(command.AggregateID())
+	if err := h.LoadAggregate(ctx, aggregate); err != nil {
+		return err
+	}
+	paidAt := command.PaidAt
+	if paidAt.IsZero() {
+		paidAt = time.Now()
+	}
+	if err := aggregate.PayCharge(command.ChargeID, paidAt); err != nil {
+		return err
+	}
+	return h.SaveAndPublish(ctx, aggregate)
+}
+
+type WaiveChargeCommand struct {
+	application.CommandModel
+	ChargeID string `json:"chargeId"`
+	Reason   string `json:"reason"`
+}
+
+type WaiveChargeCommandHandler struct {
+	*application.CommandHandlerModel
+}
+
+func NewWaiveChargeCommandHandler(store application.Store, publisher application.EventPublisher) WaiveChargeCommandHandler {
+	return WaiveChargeCommandHandler{application.NewCommandHandlerModel(store, publisher)}
+}
+
+func (h WaiveChargeCommandHandler) Handle(ctx context.Context, command WaiveChargeCommand) error {
+	aggregate := chargedomain.NewSchoolChargeAggregateWithID(command.AggregateID())
+	if err := h.LoadAggregate(ctx, aggregate); err != nil {
+		return err
+	}
+	if err := aggregate.WaiveCharge(command.ChargeID, command.Reason); err != nil {
+		return err
+	}
+	return h.SaveAndPublish(ctx, aggregate)
+}
@@ -0,0 +1,148 @@
+package chargeapp_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/kammeph/school-book-storage-service/application"
+	"github.com/kammeph/school-book-storage-service/application/chargeapp"
+	"github.com/kammeph/school-book-storage-service/domain"
+	"github.com/kammeph/school-book-storage-service/domain/bookdomain"
+	"github.com/kammeph/school-book-storage-service/domain/chargedomain"
+	"github.com/kammeph/school-book-storage-service/domain/loandomain"
+	"github.com/kammeph/school-book-storage-service/domain/pupildomain"
+	"github.com/kammeph/school-book-storage-service/infrastructure/memory"
+	"github.com/kammeph/school-book-storage-service/testing/fixtures"
+	"github.com/stretchr/testify/assert"
+)
+
+var acquiredAt = time.Now().AddDate(-2, 0, -1)
+
+func newChargeCommandHandlers() (chargeapp.ChargeCommandHandlers, *memory.MemoryStore) {
+	bookStore := memory.NewMemoryStoreWithEvents([]domain.Event{
+		&domain.EventModel{
+			ID:      "school",
+			Type:    bookdomain.BookAdded,
+			Version: 1,
+			At:      acquiredAt,
+			Data:    "{\"SchoolID\":\"school\",\"BookID\":\"book\",\"Isbn\":\"123\",\"Name\":\"math\",\"Price\":25}",
+		},
+	})
+	pupilStore := memory.NewMemoryStoreWithEvents([]domain.Event{
+		&domain.EventModel{
+			ID:      "school",
+			Type:    pupildomain.PupilEnrolled,
+			Version: 1,
+			At:      time.Now(),
+			Data:    "{\"schoolId\":\"school\",\"pupilId\":\"pupil\",\"firstName\":\"Anna\",\"lastName\":\"Meier\",\"classId\":\"class\"}",
+		},
+	})
+	loanStore := memory.NewMemoryStoreWithEvents([]domain.Event{
+		&domain.EventModel{
+			ID:      "school",
+			Type:    loandomain.LoanIssued,
+			Version: 1,
+			At:      time.Now(),
+			Data:    "{\"schoolId\":\"school\",\"loanId\":\"loan\",\"pupilId\":\"pupil\",\"classId\":\"class\",\"bookId\":\"book\"}",
+		},
+	})
+	store := memory.NewMemoryStoreWithEvents([]domain.Event{
+		&domain.EventModel{
+			ID:      "school",
+			Type:    chargedomain.DepreciationConfigured,
+			Version: 1,
+			At:      time.Now(),
+			Data:    "{\"ratePerYear\":0.2,\"minimum\":0.3}",
+		},
+	})
+	return chargeapp.NewChargeCommandHandlers(store, nil, bookStore, pupilStore, loanStore), store
+}
+
+func loadCharges(t *testing.T, store application.Store) *chargedomain.SchoolChargeAggregate {
+	aggregate := chargedomain.NewSchoolChargeAggregateWithID("school")
+	assert.Nil(t, application.NewCommandHandlerModel(store, nil).LoadAggregate(context.Background(), aggregate))
+	return aggregate
+}
+
+func TestCreateCharge(t *testing.T) {
+	tests := []struct {
+		name        string
+		command     chargeapp.CreateChargeCommand
+		amount      domain.Money
+		expectError bool
+	}{
+		{
+			name:    "charge depreciated since the book was added",
+			command: chargeapp.CreateChargeCommand{PupilID: "pupil", BookID: "book", Incident: chargedomain.Lost, LoanID: "loan"},
+			amount:  fixtures.Euro(1500),
+		},
+		{
+			name:    "charge depreciated since the acquisition",
+			command: chargeapp.CreateChargeCommand{PupilID: "pupil", BookID: "book", Incident: chargedomain.Damaged, AcquiredAt: time.Now()},
+			amount:  fixtures.Euro(2500),
+		},
+		{
+			name:        "pupil not found",
+			command:     chargeapp.CreateChargeCommand{PupilID: "unknown", BookID: "book", Incident: chargedomain.Lost},
+			expectError: true,
+		},
+		{
+			name:        "book not found",
+			command:     chargeapp.CreateChargeCommand{PupilID: "pupil", BookID: "unknown", Incident: chargedomain.Lost},
+			expectError: true,
+		},
+		{
+			name:        "loan not found",
+			command:     chargeapp.CreateChargeCommand{PupilID: "pupil", BookID: "book", Incident: chargedomain.Lost, LoanID: "unknown"},
+			expectError: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			handlers, store := newChargeCommandHandlers()
+			test.command.ID = "school"
+			chargeID, err := handlers.CreateChargeHandler.Handle(context.Background(), test.command)
+			aggregate := loadCharges(t, store)
+			if test.expectError {
+				assert.Error(t, err)
+				assert.Empty(t, aggregate.Charges)
+				return
+			}
+			assert.Nil(t, err)
+			assert.Len(t, aggregate.Charges, 1)
+			assert.Equal(t, chargeID, aggregate.Charges[0].ID)
+			assert.Equal(t, "math", aggregate.Charges[0].Title)
+			assert.Equal(t, fixtures.Euro(2500), aggregate.Charges[0].Price)
+			assert.Equal(t, test.amount, aggregate.Charges[0].Amount)
+		})
+	}
+}
+
+func TestPayAndWaiveCharge(t *testing.T) {
+	ctx := context.Background()
+	handlers, store := newChargeCommandHandlers()
+	create := chargeapp.CreateChargeCommand{
+		CommandModel: application.CommandModel{ID: "school"},
+		PupilID:      "pupil",
+		BookID:       "book",
+		Incident:     chargedomain.Lost,
+	}
+	paidID, err := handlers.CreateChargeHandler.Handle(ctx, create)
+	assert.Nil(t, err)
+	waivedID, err := handlers.CreateChargeHandler.Handle(ctx, create)
+	assert.Nil(t, err)
+
+	pay := chargeapp.PayChargeCommand{CommandModel: application.CommandModel{ID: "school"}, ChargeID: paidID}
+	assert.Nil(t, handlers.PayChargeHandler.Handle(ctx, pay))
+	assert.Error(t, handlers.PayChargeHandler.Handle(ctx, pay))
+
+	waive := chargeapp.WaiveChargeCommand{CommandModel: application.CommandModel{ID: "school"}, ChargeID: waivedID, Reason: "found again"}
+	assert.Nil(t, handlers.WaiveChargeHandler.Handle(ctx, waive))
+	waive.ChargeID = paidID
+	assert.Error(t, handlers.WaiveChargeHandler.Handle(ctx, waive))
+
+	aggregate := loadCharges(t, store)
+	assert.Equal(t, chargedomain.Paid, aggregate.Charges[0].Status)
+	assert.Equal(t, chargedomain.Waived, aggregate.Charges[1].Status)
+}
@@ -0,0 +1,71 @@
+package chargeapp
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/kammeph/school-book-storage-service/application"
+	"github.com/kammeph/school-book-storage-service/domain"
+	"github.com/kammeph/school-book-storage-service/domain/chargedomain"
+)
+
+type ChargeEventHandler struct {
+	repository ChargeRepository
+}
+
+func NewChargeEventHandler(repository ChargeRepository) application.EventHandler {
+	return &ChargeEventHandler{repository}
+}
+
+func (h ChargeEventHandler) Handle(ctx context.Context, eventBytes []byte) error {
+	event := &domain.EventModel{}
+	if err := json.Unmarshal(eventBytes, event); err != nil {
+		return err
+	}
+	switch event.EventType() {
+	case chargedomain.ChargeCreated:
+		return h.handleChargeCreated(ctx, event)
+	case chargedomain.ChargePaid:
+		return h.handleChargePaid(ctx, event)
+	case chargedomain.ChargeWaived:
+		return h.handleChargeWaived(ctx, event)
+	default:
+		return nil
+	}
+}
+
+func (h ChargeEventHandler) handleChargeCreated(ctx context.Context, event domain.Event) error {
+	chargeCreated := chargedomain.ChargeCreatedEvent{}
+	if err := event.GetJsonData(&chargeCreated); err != nil {
+		return err
+	}
+	charge := chargedomain.NewCharge(
+		chargeCreated.ChargeID,
+		chargeCreated.PupilID,
+		chargeCreated.BookID,
+		chargeCreated.Isbn,
+		chargeCreated.Title,
+		chargeCreated.Incident,
+		chargeCreated.LoanID,
+		chargeCreated.Description,
+		chargeCreated.Price,
+		chargeCreated.Amount,
+		event.EventAt())
+	return h.repository.UpsertCharge(ctx, chargedomain.NewChargeProjection(chargeCreated.SchoolID, charge, event.EventVersion()))
+}
+
+func (h ChargeEventHandler) handleChargePaid(ctx context.Context, event domain.Event) error {
+	chargePaid := chargedomain.ChargePaidEvent{}
+	if err := event.GetJsonData(&chargePaid); err != nil {
+		return err
+	}
+	return h.repository.UpdateChargeStatus(ctx, chargePaid.ChargeID, chargedomain.Paid, event.EventVersion())
+}
+
+func (h ChargeEventHandler) handleChargeWaived(ctx context.Context, event domain.Event) error {
+	chargeWaived := chargedomain.ChargeWaivedEvent{}
+	if err := event.GetJsonData(&chargeWaived); err != nil {
+		return err
+	}
+	return h.repository.UpdateChargeStatus(ctx, chargeWaived.ChargeID, chargedomain.Waived, event.EventVersion())
+}
@@ -0,0 +1,60 @@
+package chargeapp_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/kammeph/school-book-storage-service/application/chargeapp"
+	"github.com/kammeph/school-book-storage-service/domain"
+	"github.com/kammeph/school-book-storage-service/domain/chargedomain"
+	"github.com/kammeph/school-book-storage-service/infrastructure/memory"
+	"github.com/kammeph/school-book-storage-service/testing/fixtures"
+	"github.com/stretchr/testify/assert"
+)
+
+func chargeEvent(version int, eventType, data string) []byte {
+	eventBytes, _ := json.Marshal(domain.EventModel{
+		ID:      "school",
+		Type:    eventType,
+		Version: version,
+		At:      time.Now(),
+		Data:    data,
+	})
+	return eventBytes
+}
+
+func TestHandleChargeEvents(t *testing.T) {
+	ctx := context.Background()
+	repository := memory.NewMemoryChargeRepository()
+	handler := chargeapp.NewChargeEventHandler(repository)
+	events := [][]byte{
+		chargeEvent(1, chargedomain.DepreciationConfigured, "{\"ratePerYear\":0.2,\"minimum\":0.3}"),
+		chargeEvent(2, chargedomain.ChargeCreated, "{\"schoolId\":\"school\",\"chargeId\":\"charge1\",\"pupilId\":\"pupil\",\"bookId\":\"book\",\"incident\":\"lost\",\"price\":25,\"amount\":15}"),
+		chargeEvent(3, chargedomain.ChargeCreated, "{\"schoolId\":\"school\",\"chargeId\":\"charge2\",\"pupilId\":\"pupil\",\"bookId\":\"book\",\"incident\":\"damaged\",\"price\":25,\"amount\":20}"),
+		chargeEvent(4, chargedomain.ChargeCreated, "{\"schoolId\":\"school\",\"chargeId\":\"charge3\",\"pupilId\":\"pupil\",\"bookId\":\"book\",\"incident\":\"lost\",\"price\":25,\"amount\":7.5}"),
+		chargeEvent(5, chargedomain.ChargePaid, "{\"chargeId\":\"charge1\"}"),
+		chargeEvent(6, chargedomain.ChargeWaived, "{\"chargeId\":\"charge2\",\"reason\":\"hardship\"}"),
+	}
+	for _, event := range events {
+		assert.Nil(t, handler.Handle(ctx, event))
+	}
+	// a redelivered creation must not reopen the paid charge
+	assert.Nil(t, handler.Handle(ctx, events[1]))
+
+	charges, err := repository.GetChargesBySchoolID(ctx, "school")
+	assert.Nil(t, err)
+	assert.Len(t, charges, 3)
+	charge, err := repository.GetChargeByID(ctx, "school", "charge1")
+	assert.Nil(t, err)
+	assert.Equal(t, chargedomain.Paid, charge.Status)
+
+	openCharges, err := chargeapp.NewGetOpenChargesQueryHandler(repository).Handle(ctx, chargeapp.NewGetOpenCharges("school"))
+	assert.Nil(t, err)
+	assert.Len(t, openCharges.Charges, 1)
+	assert.Equal(t, "charge3", openCharges.Charges[0].ChargeID)
+	assert.Equal(t, fixtures.Euro(750), openCharges.Total)
+
+	assert.Error(t, handler.Handle(ctx, chargeEvent(7, chargedomain.ChargeCreated, "{\"chargeId\":")))
+}
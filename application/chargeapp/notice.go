@@ -0,0 +1,37 @@
+package chargeapp
+
+import (
+	"fmt"
+	"io"
+)
+
+// WritePaymentNotice writes the notice as plain text that can be printed and
+// handed to the pupil.
+func WritePaymentNotice(w io.Writer, notice PaymentNotice) error {
+	charge := notice.Charge
+	_, err := fmt.Fprintf(w, `%s
+
+Payment notice for %s %s
+Date: %s
+Reference: %s
+
+The book "%s" (ISBN %s) was %s.
+%s
+Price of the book:  %12s
+Amount to pay:      %12s
+
+Please pay the amount to the school office.
+`,
+		notice.SchoolName,
+		notice.FirstName,
+		notice.LastName,
+		notice.IssuedAt.Format("2006-01-02"),
+		charge.ChargeID,
+		charge.Title,
+		charge.Isbn,
+		charge.Incident,
+		charge.Description,
+		charge.Price,
+		charge.Amount)
+	return err
+}
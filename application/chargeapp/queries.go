@@ -0,0 +1,144 @@
+package chargeapp
+
+import (
+	"context"
+	"time"
+
+	"github.com/kammeph/school-book-storage-service/application"
+	"github.com/kammeph/school-book-storage-service/application/pupilapp"
+	"github.com/kammeph/school-book-storage-service/application/schoolapp"
+	"github.com/kammeph/school-book-storage-service/domain"
+	"github.com/kammeph/school-book-storage-service/domain/chargedomain"
+)
+
+type ChargeQueryHandlers struct {
+	GetAllHandler           GetAllChargesQueryHandler
+	GetOpenHandler          GetOpenChargesQueryHandler
+	GetPaymentNoticeHandler GetPaymentNoticeQueryHandler
+}
+
+func NewChargeQueryHandlers(
+	repository ChargeRepository,
+	pupils pupilapp.PupilRepository,
+	schools schoolapp.SchoolRepository,
+) ChargeQueryHandlers {
+	return ChargeQueryHandlers{
+		GetAllHandler:           NewGetAllChargesQueryHandler(repository),
+		GetOpenHandler:          NewGetOpenChargesQueryHandler(repository),
+		GetPaymentNoticeHandler: NewGetPaymentNoticeQueryHandler(repository, pupils, schools),
+	}
+}
+
+type GetAllCharges struct {
+	application.QueryModel
+}
+
+func NewGetAllCharges(aggregateID string) GetAllCharges {
+	return GetAllCharges{QueryModel: application.QueryModel{ID: aggregateID}}
+}
+
+type GetAllChargesQueryHandler struct {
+	repository ChargeRepository
+}
+
+func NewGetAllChargesQueryHandler(repository ChargeRepository) GetAllChargesQueryHandler {
+	return GetAllChargesQueryHandler{repository: repository}
+}
+
+func (h GetAllChargesQueryHandler) Handle(ctx context.Context, query GetAllCharges) ([]chargedomain.ChargeProjection, error) {
+	return h.repository.GetChargesBySchoolID(ctx, query.AggregateID())
+}
+
+// GetOpenCharges asks for the open items of a school, the charges that are
+// neither paid nor waived.
+type GetOpenCharges struct {
+	application.QueryModel
+}
+
+func NewGetOpenCharges(aggregateID string) GetOpenCharges {
+	return GetOpenCharges{QueryModel: application.QueryModel{ID: aggregateID}}
+}
+
+type OpenCharges struct {
+	Charges []chargedomain.ChargeProjection `json:"charges"`
+	Total   domain.Money                    `json:"total"`
+}
+
+type GetOpenChargesQueryHandler struct {
+	repository ChargeRepository
+}
+
+func NewGetOpenChargesQueryHandler(repository ChargeRepository) GetOpenChargesQueryHandler {
+	return GetOpenChargesQueryHandler{repository: repository}
+}
+
+func (h GetOpenChargesQueryHandler) Handle(ctx context.Context, query GetOpenCharges) (OpenCharges, error) {
+	charges, err := h.repository.GetOpenCharges(ctx, query.AggregateID())
+	if err != nil {
+		return OpenCharges{}, err
+	}
+	openCharges := OpenCharges{Charges: charges}
+	for _, charge := range charges {
+		if openCharges.Total, err = openCharges.Total.Add(charge.Amount); err != nil {
+			return OpenCharges{}, err
+		}
+	}
+	return openCharges, nil
+}
+
+type GetPaymentNotice struct {
+	application.QueryModel
+	ChargeID string
+}
+
+func NewGetPaymentNotice(aggregateID, chargeID string) GetPaymentNotice {
+	return GetPaymentNotice{QueryModel: application.QueryModel{ID: aggregateID}, ChargeID: chargeID}
+}
+
+type PaymentNotice struct {
+	SchoolName string                        `json:"schoolName"`
+	FirstName  string                        `json:"firstName"`
+	LastName   string                        `json:"lastName"`
+	Charge     chargedomain.ChargeProjection `json:"charge"`
+	IssuedAt   time.Time                     `json:"issuedAt"`
+}
+
+type GetPaymentNoticeQueryHandler struct {
+	repository ChargeRepository
+	pupils     pupilapp.PupilRepository
+	schools    schoolapp.SchoolRepository
+}
+
+func NewGetPaymentNoticeQueryHandler(
+	repository ChargeRepository,
+	pupils pupilapp.PupilRepository,
+	schools schoolapp.SchoolRepository,
+) GetPaymentNoticeQueryHandler {
+	return GetPaymentNoticeQueryHandler{repository: repository, pupils: pupils, schools: schools}
+}
+
+// Handle builds the payment notice for an open charge addressed to the pupil.
+func (h GetPaymentNoticeQueryHandler) Handle(ctx context.Context, query GetPaymentNotice) (PaymentNotice, error) {
+	charge, err := h.repository.GetChargeByID(ctx, query.AggregateID(), query.ChargeID)
+	if err != nil {
+		return PaymentNotice{}, err
+	}
+	if charge.Status != chargedomain.Open {
+		return PaymentNotice{}, chargedomain.ErrChargeNotOpen(charge.ChargeID, charge.Status)
+	}
+	pupil, err := h.pupils.GetPupilByID(ctx, query.AggregateID(), charge.PupilID)
+	if err != nil {
+		return PaymentNotice{}, err
+	}
+	school, err := h.schools.GetSchoolByID(ctx, query.AggregateID())
+	if err != nil {
+		return PaymentNotice{}, err
+	}
+	return PaymentNotice{
+		SchoolName: school.Name,
+		FirstName:  pupil.FirstName,
+		LastName:   pupil.LastName,
+		Charge:     charge,
+		IssuedAt:   time.Now(),
+	}, nil
+}
@@ -0,0 +1,15 @@
+package chargeapp
+
+import (
+	"context"
+
+	"github.com/kammeph/school-book-storage-service/domain/chargedomain"
+)
+
+type ChargeRepository interface {
+	GetChargesBySchoolID(ctx context.Context, schoolID string) ([]chargedomain.ChargeProjection, error)
+	GetOpenCharges(ctx context.Context, schoolID string) ([]chargedomain.ChargeProjection, error)
+	GetChargeByID(ctx context.Context, schoolID, chargeID string) (chargedomain.ChargeProjection, error)
+	UpsertCharge(ctx context.Context, charge chargedomain.ChargeProjection) error
+	UpdateChargeStatus(ctx context.Context, chargeID string, status chargedomain.ChargeStatus, version int) error
+}
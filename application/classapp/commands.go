@@ -0,0 +1,386 @@
+package classapp
+
+import (
+	"context"
+	"time"
+
+	"github.com/kammeph/school-book-storage-service/application"
+	"github.com/kammeph/school-book-storage-service/domain/classdomain"
+	"github.com/kammeph/school-book-storage-service/domain/pupildomain"
+	"github.com/kammeph/school-book-storage-service/domain/reservationdomain"
+	"github.com/kammeph/school-book-storage-service/domain/storagedomain"
+	"github.com/kammeph/school-book-storage-service/fp"
+)
+
+type ClassCommandHandlers struct {
+	CreateClassHandler            CreateClassCommandHandler
+	IncreaseNumberOfPupilsHandler IncreaseNumberOfPupilsCommandHandler
+	DecreaseNumberOfPupilsHandler DecreaseNumberOfPupilsCommandHandler
+	LendBooksHandler              LendBooksCommandHandler
+	ReturnBooksHandler            ReturnBooksCommandHandler
+	ReturnAllBooksHandler         ReturnAllBooksCommandHandler
+	RolloverHandler               RolloverCommandHandler
+}
+
+func NewClassCommandHandlers(
+	store application.Store,
+	publisher application.EventPublisher,
+	storageStore application.Store,
+	storagePublisher application.EventPublisher,
+	pupilStore application.Store,
+	pupilPublisher application.EventPublisher,
+	reservationStore application.Store,
+) ClassCommandHandlers {
+	return ClassCommandHandlers{
+		CreateClassHandler:            NewCreateClassCommandHandler(store, publisher),
+		IncreaseNumberOfPupilsHandler: NewIncreaseNumberOfPupilsCommandHandler(store, publisher),
+		DecreaseNumberOfPupilsHandler: NewDecreaseNumberOfPupilsCommandHandler(store, publisher),
+		LendBooksHandler:              NewLendBooksCommandHandler(store, publisher, storageStore, storagePublisher, reservationStore),
+		ReturnBooksHandler:            NewReturnBooksCommandHandler(store, publisher, storageStore, storagePublisher),
+		ReturnAllBooksHandler:         NewReturnAllBooksCommandHandler(store, publisher, storageStore, storagePublisher),
+		RolloverHandler:               NewRolloverCommandHandler(store, publisher, pupilStore, pupilPublisher),
+	}
+}
+
+type CreateClassCommand struct {
+	application.CommandModel
+	Grade          int       `json:"grade"`
+	Letter         string    `json:"letter"`
+	NumberOfPupils int       `json:"numberOfPupils"`
+	DateFrom       time.Time `json:"dateFrom"`
+	DateTo         time.Time `json:"dateTo"`
+}
+
+type CreateClassCommandHandler struct {
+	*application.CommandHandlerModel
+}
+
+func NewCreateClassCommandHandler(store application.Store, publisher application.EventPublisher) CreateClassCommandHandler {
+	return CreateClassCommandHandler{application.NewCommandHandlerModel(store, publisher)}
+}
+
+func (h CreateClassCommandHandler) Handle(ctx context.Context, command CreateClassCommand) (string, error) {
+	aggregate := classdomain.NewSchoolClassAggregateWithID(command.AggregateID())
+	if err := h.LoadAggregate(ctx, aggregate); err != nil {
+		return "", err
+	}
+	classID, err := aggregate.CreateClass(command.Grade, command.Letter, command.NumberOfPupils, command.DateFrom, command.DateTo)
+	if err != nil {
+		return "", err
+	}
+	if err := h.SaveAndPublish(ctx, aggregate); err != nil {
+		return "", err
+	}
+	return classID, nil
+}
+
+type IncreaseNumberOfPupilsCommand struct {
+	application.CommandModel
+	ClassID string `json:"classId"`
+	Number  int    `json:"number"`
+	Reason  string `json:"reason"`
+}
+
+type IncreaseNumberOfPupilsCommandHandler struct {
+	*application.CommandHandlerModel
+}
+
+func NewIncreaseNumberOfPupilsCommandHandler(store application.Store, publisher application.EventPublisher) IncreaseNumberOfPupilsCommandHandler {
+	return IncreaseNumberOfPupilsCommandHandler{application.NewCommandHandlerModel(store, publisher)}
+}
+
+func (h IncreaseNumberOfPupilsCommandHandler) Handle(ctx context.Context, command IncreaseNumberOfPupilsCommand) error {
+	aggregate := classdomain.NewSchoolClassAggregateWithID(command.AggregateID())
+	if err := h.LoadAggregate(ctx, aggregate); err != nil {
+		return err
+	}
+	if err := aggregate.IncreaseNumberOfPupils(command.ClassID, command.Number, command.Reason); err != nil {
+		return err
+	}
+	return h.SaveAndPublish(ctx, aggregate)
+}
+
+type DecreaseNumberOfPupilsCommand struct {
+	application.CommandModel
+	ClassID string `json:"classId"`
+	Number  int    `json:"number"`
+	Reason  string `json:"reason"`
+}
+
+type DecreaseNumberOfPupilsCommandHandler struct {
+	*application.CommandHandlerModel
+}
+
+func NewDecreaseNumberOfPupilsCommandHandler(store application.Store, publisher application.EventPublisher) DecreaseNumberOfPupilsCommandHandler {
+	return DecreaseNumberOfPupilsCommandHandler{application.NewCommandHandlerModel(store, publisher)}
+}
+
+func (h DecreaseNumberOfPupilsCommandHandler) Handle(ctx context.Context, command DecreaseNumberOfPupilsCommand) error {
+	aggregate := classdomain.NewSchoolClassAggregateWithID(command.AggregateID())
+	if err := h.LoadAggregate(ctx, aggregate); err != nil {
+		return err
+	}
+	if err := aggregate.DecreaseNumberOfPupils(command.ClassID, command.Number, command.Reason); err != nil {
+		return err
+	}
+	return h.SaveAndPublish(ctx, aggregate)
+}
+
+// lendingModel loads and saves the class and the storage aggregate of a
+// school. Both aggregates are changed in memory first, so a lending that is
+// rejected by either of them leaves both untouched. The storage is saved
+// first as it guards the stock.
+type lendingModel struct {
+	classes  *application.CommandHandlerModel
+	storages *application.CommandHandlerModel
+}
+
+func newLendingModel(
+	store application.Store,
+	publisher application.EventPublisher,
+	storageStore application.Store,
+	storagePublisher application.EventPublisher,
+) lendingModel {
+	return lendingModel{
+		classes:  application.NewCommandHandlerModel(store, publisher),
+		storages: application.NewCommandHandlerModel(storageStore, storagePublisher),
+	}
+}
+
+func (m lendingModel) load(ctx context.Context, schoolID string) (*classdomain.SchoolClassAggregate, *storagedomain.SchoolStorageAggregate, error) {
+	classes := classdomain.NewSchoolClassAggregateWithID(schoolID)
+	if err := m.classes.LoadAggregate(ctx, classes); err != nil {
+		return nil, nil, err
+	}
+	storages := storagedomain.NewSchoolStorageAggregateWithID(schoolID)
+	if err := m.storages.LoadAggregate(ctx, storages); err != nil {
+		return nil, nil, err
+	}
+	return classes, storages, nil
+}
+
+func (m lendingModel) save(ctx context.Context, classes *classdomain.SchoolClassAggregate, storages *storagedomain.SchoolStorageAggregate) error {
+	if err := m.storages.SaveAndPublish(ctx, storages); err != nil {
+		return err
+	}
+	return m.classes.SaveAndPublish(ctx, classes)
+}
+
+type LendBooksCommand struct {
+	application.CommandModel
+	ClassID   string `json:"classId"`
+	StorageID string `json:"storageId"`
+	BookID    string `json:"bookId"`
+	Quantity  int    `json:"quantity"`
+}
+
+type LendBooksCommandHandler struct {
+	lendingModel
+	reservations *application.CommandHandlerModel
+}
+
+func NewLendBooksCommandHandler(
+	store application.Store,
+	publisher application.EventPublisher,
+	storageStore application.Store,
+	storagePublisher application.EventPublisher,
+	reservationStore application.Store,
+) LendBooksCommandHandler {
+	return LendBooksCommandHandler{
+		lendingModel: newLendingModel(store, publisher, storageStore, storagePublisher),
+		reservations: application.NewCommandHandlerModel(reservationStore, nil),
+	}
+}
+
+// Handle lends the books unless they are reserved for others. Books the class
+// reserved itself fulfil its reservations once the lending is published.
+func (h LendBooksCommandHandler) Handle(ctx context.Context, command LendBooksCommand) error {
+	classes, storages, err := h.load(ctx, command.AggregateID())
+	if err != nil {
+		return err
+	}
+	reservations := reservationdomain.NewSchoolReservationAggregateWithID(command.AggregateID())
+	if err := h.reservations.LoadAggregate(ctx, reservations); err != nil {
+		return err
+	}
+	storages.Holds = reservations.Holds(time.Now(), command.ClassID)
+	lent, err := storages.LendBooksToClass(command.StorageID, command.ClassID, command.BookID, command.Quantity)
+	if err != nil {
+		return err
+	}
+	book := classdomain.ClassBook{BookID: lent.BookID, Isbn: lent.Isbn, Title: lent.Title, Quantity: lent.Quantity}
+	if err := classes.ReceiveBooks(command.ClassID, command.StorageID, book); err != nil {
+		return err
+	}
+	return h.save(ctx, classes, storages)
+}
+
+type ReturnBooksCommand struct {
+	application.CommandModel
+	ClassID   string `json:"classId"`
+	StorageID string `json:"storageId"`
+	BookID    string `json:"bookId"`
+	Quantity  int    `json:"quantity"`
+}
+
+type ReturnBooksCommandHandler struct {
+	lendingModel
+}
+
+func NewReturnBooksCommandHandler(
+	store application.Store,
+	publisher application.EventPublisher,
+	storageStore application.Store,
+	storagePublisher application.EventPublisher,
+) ReturnBooksCommandHandler {
+	return ReturnBooksCommandHandler{newLendingModel(store, publisher, storageStore, storagePublisher)}
+}
+
+func (h ReturnBooksCommandHandler) Handle(ctx context.Context, command ReturnBooksCommand) error {
+	classes, storages, err := h.load(ctx, command.AggregateID())
+	if err != nil {
+		return err
+	}
+	if err := returnBooks(classes, storages, command.ClassID, command.StorageID, command.BookID, command.Quantity); err != nil {
+		return err
+	}
+	return h.save(ctx, classes, storages)
+}
+
+type ReturnAllBooksCommand struct {
+	application.CommandModel
+	ClassID   string `json:"classId"`
+	StorageID string `json:"storageId"`
+}
+
+type ReturnAllBooksCommandHandler struct {
+	lendingModel
+}
+
+func NewReturnAllBooksCommandHandler(
+	store application.Store,
+	publisher application.EventPublisher,
+	storageStore application.Store,
+	storagePublisher application.EventPublisher,
+) ReturnAllBooksCommandHandler {
+	return ReturnAllBooksCommandHandler{newLendingModel(store, publisher, storageStore, storagePublisher)}
+}
+
+// Handle returns every book the class holds to the storage, e.g. at the end
+// of the school year.
+func (h ReturnAllBooksCommandHandler) Handle(ctx context.Context, command ReturnAllBooksCommand) error {
+	classes, storages, err := h.load(ctx, command.AggregateID())
+	if err != nil {
+		return err
+	}
+	class := fp.Find(classes.Classes, func(c classdomain.Class) bool { return c.ID == command.ClassID })
+	if class == nil {
+		return classdomain.ErrClassWithIDNotFound(command.ClassID)
+	}
+	books := append([]classdomain.ClassBook{}, class.Books...)
+	for _, book := range books {
+		if err := returnBooks(classes, storages, command.ClassID, command.StorageID, book.BookID, book.Quantity); err != nil {
+			return err
+		}
+	}
+	return h.save(ctx, classes, storages)
+}
+
+func returnBooks(
+	classes *classdomain.SchoolClassAggregate,
+	storages *storagedomain.SchoolStorageAggregate,
+	classID, storageID, bookID string,
+	quantity int,
+) error {
+	returned, err := classes.ReturnBooks(classID, storageID, bookID, quantity)
+	if err != nil {
+		return err
+	}
+	stock := storagedomain.BookStock{BookID: returned.BookID, Isbn: returned.Isbn, Title: returned.Title, Quantity: returned.Quantity}
+	return storages.ReturnBooksFromClass(storageID, classID, stock)
+}
+
+// RolloverReason is the reason recorded for pupils moved to the successor of
+// their class.
+const RolloverReason = "school year rollover"
+
+// GraduationReason is the reason recorded for pupils leaving the school with
+// the final grade.
+const GraduationReason = "graduated"
+
+type RolloverCommand struct {
+	application.CommandModel
+	At                time.Time `json:"at"`
+	FinalGrade        int       `json:"finalGrade"`
+	FirstGradeLetters []string  `json:"firstGradeLetters"`
+	DryRun            bool      `json:"dryRun"`
+}
+
+// RolloverCommandHandler rolls the classes of a school over to the next school
+// year and moves their pupils along. The classes are saved first as the
+// pupils refer to the classes created by the rollover.
+type RolloverCommandHandler struct {
+	classes *application.CommandHandlerModel
+	pupils  *application.CommandHandlerModel
+}
+
+func NewRolloverCommandHandler(
+	store application.Store,
+	publisher application.EventPublisher,
+	pupilStore application.Store,
+	pupilPublisher application.EventPublisher,
+) RolloverCommandHandler {
+	return RolloverCommandHandler{
+		classes: application.NewCommandHandlerModel(store, publisher),
+		pupils:  application.NewCommandHandlerModel(pupilStore, pupilPublisher),
+	}
+}
+
+// Handle returns the report of the rollover. Pupils the successor already
+// holds are not moved again. A dry run applies the rollover to
+// the loaded aggregates only and saves nothing, so its report previews what
+// the rollover would do. A missing date defaults to now.
+func (h RolloverCommandHandler) Handle(ctx context.Context, command RolloverCommand) (classdomain.RolloverReport, error) {
+	classes := classdomain.NewSchoolClassAggregateWithID(command.AggregateID())
+	if err := h.classes.LoadAggregate(ctx, classes); err != nil {
+		return classdomain.RolloverReport{}, err
+	}
+	pupils := pupildomain.NewSchoolPupilAggregateWithID(command.AggregateID())
+	if err := h.pupils.LoadAggregate(ctx, pupils); err != nil {
+		return classdomain.RolloverReport{}, err
+	}
+	at := command.At
+	if at.IsZero() {
+		at = time.Now()
+	}
+	report, err := classes.Rollover(at, command.FinalGrade, command.FirstGradeLetters)
+	if err != nil {
+		return classdomain.RolloverReport{}, err
+	}
+	for _, closed := range report.ClosedClasses {
+		for _, pupilID := range closed.Pupils {
+			if closed.Graduated {
+				_, err = pupils.LeaveSchool(pupilID, closed.DateTo, GraduationReason)
+			} else if !inClass(pupils, pupilID, closed.SuccessorID) {
+				_, err = pupils.MovePupil(pupilID, closed.SuccessorID, RolloverReason)
+			}
+			if err != nil {
+				return classdomain.RolloverReport{}, err
+			}
+		}
+	}
+	if command.DryRun {
+		return report, nil
+	}
+	if err := h.classes.SaveAndPublish(ctx, classes); err != nil {
+		return classdomain.RolloverReport{}, err
+	}
+	if err := h.pupils.SaveAndPublish(ctx, pupils); err != nil {
+		return classdomain.RolloverReport{}, err
+	}
+	return report, nil
+}
+
+func inClass(pupils *pupildomain.SchoolPupilAggregate, pupilID, classID string) bool {
+	return fp.Some(pupils.Pupils, func(p pupildomain.Pupil) bool { return p.ID == pupilID && p.ClassID == classID })
+}
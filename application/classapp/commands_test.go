@@ -0,0 +1,436 @@
+package classapp_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/kammeph/school-book-storage-service/application"
+	"github.com/kammeph/school-book-storage-service/application/classapp"
+	"github.com/kammeph/school-book-storage-service/application/pupilapp"
+	"github.com/kammeph/school-book-storage-service/application/reservationapp"
+	"github.com/kammeph/school-book-storage-service/domain"
+	"github.com/kammeph/school-book-storage-service/domain/classdomain"
+	"github.com/kammeph/school-book-storage-service/domain/pupildomain"
+	"github.com/kammeph/school-book-storage-service/domain/reservationdomain"
+	"github.com/kammeph/school-book-storage-service/domain/storagedomain"
+	"github.com/kammeph/school-book-storage-service/fp"
+	"github.com/kammeph/school-book-storage-service/infrastructure/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+func newStores() (*memory.MemoryStore, *memory.MemoryStore) {
+	classStore := memory.NewMemoryStoreWithEvents([]domain.Event{
+		&domain.EventModel{
+			ID:      "school",
+			Type:    classdomain.ClassCreated,
+			Version: 1,
+			At:      time.Now(),
+			Data:    "{\"schoolId\":\"school\",\"classId\":\"class\",\"grade\":5,\"letter\":\"a\",\"numberOfPupils\":25}",
+		},
+	})
+	storageStore := memory.NewMemoryStoreWithEvents([]domain.Event{
+		&domain.EventModel{
+			ID:      "school",
+			Type:    storagedomain.StorageAdded,
+			Version: 1,
+			At:      time.Now(),
+			Data:    "{\"schoolId\":\"school\",\"storageId\":\"storage\",\"name\":\"closet\",\"location\":\"room 1\"}",
+		},
+		&domain.EventModel{
+			ID:      "school",
+			Type:    storagedomain.BooksPut,
+			Version: 2,
+			At:      time.Now(),
+			Data:    "{\"storageId\":\"storage\",\"bookId\":\"book\",\"isbn\":\"123\",\"title\":\"math\",\"quantity\":30}",
+		},
+	})
+	return classStore, storageStore
+}
+
+func loadAggregates(t *testing.T, classStore, storageStore application.Store) (*classdomain.SchoolClassAggregate, *storagedomain.SchoolStorageAggregate) {
+	ctx := context.Background()
+	classes := classdomain.NewSchoolClassAggregateWithID("school")
+	assert.Nil(t, application.NewCommandHandlerModel(classStore, nil).LoadAggregate(ctx, classes))
+	storages := storagedomain.NewSchoolStorageAggregateWithID("school")
+	assert.Nil(t, application.NewCommandHandlerModel(storageStore, nil).LoadAggregate(ctx, storages))
+	return classes, storages
+}
+
+func TestLendBooks(t *testing.T) {
+	tests := []struct {
+		name           string
+		command        classapp.LendBooksCommand
+		classQuantity  int
+		storedQuantity int
+		expectError    bool
+	}{
+		{
+			name:           "lend books",
+			command:        classapp.LendBooksCommand{ClassID: "class", StorageID: "storage", BookID: "book", Quantity: 25},
+			classQuantity:  25,
+			storedQuantity: 5,
+		},
+		{
+			name:           "not enough books in storage",
+			command:        classapp.LendBooksCommand{ClassID: "class", StorageID: "storage", BookID: "book", Quantity: 31},
+			storedQuantity: 30,
+			expectError:    true,
+		},
+		{
+			name:           "class not found",
+			command:        classapp.LendBooksCommand{ClassID: "unknown", StorageID: "storage", BookID: "book", Quantity: 25},
+			storedQuantity: 30,
+			expectError:    true,
+		},
+		{
+			name:           "storage not found",
+			command:        classapp.LendBooksCommand{ClassID: "class", StorageID: "unknown", BookID: "book", Quantity: 25},
+			storedQuantity: 30,
+			expectError:    true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			classStore, storageStore := newStores()
+			handler := classapp.NewLendBooksCommandHandler(classStore, nil, storageStore, nil, memory.NewMemoryStore())
+			test.command.ID = "school"
+			err := handler.Handle(context.Background(), test.command)
+			if test.expectError {
+				assert.Error(t, err)
+			} else {
+				assert.Nil(t, err)
+			}
+			classes, storages := loadAggregates(t, classStore, storageStore)
+			assert.Equal(t, test.classQuantity, classes.Classes[0].Quantity("book"))
+			assert.Equal(t, test.storedQuantity, storages.Storages[0].Quantity("book"))
+		})
+	}
+}
+
+func TestLendReservedBooks(t *testing.T) {
+	ctx := context.Background()
+	classStore, storageStore := newStores()
+	reservationStore := memory.NewMemoryStore()
+	reserve := reservationapp.ReserveBooksCommand{
+		TeacherID: "teacher",
+		BookID:    "book",
+		StorageID: "storage",
+		Quantity:  10,
+		From:      time.Now(),
+		Until:     time.Now().AddDate(0, 1, 0),
+	}
+	reserve.ID = "school"
+	reservations := reservationapp.NewReservationCommandHandlers(reservationStore, nil, storageStore, classStore)
+	_, err := reservations.ReserveBooksHandler.Handle(ctx, reserve)
+	assert.Nil(t, err)
+	reserve.TeacherID, reserve.ClassID = "", "class"
+	_, err = reservations.ReserveBooksHandler.Handle(ctx, reserve)
+	assert.Nil(t, err)
+
+	broker := memory.NewMemoryMessageBroker()
+	broker.Subscribe("storage", reservationapp.NewFulfilmentEventHandler(reservationStore, nil), application.NewSkipPolicy())
+	handler := classapp.NewLendBooksCommandHandler(classStore, nil, storageStore, broker, reservationStore)
+	lend := classapp.LendBooksCommand{ClassID: "class", StorageID: "storage", BookID: "book", Quantity: 21}
+	lend.ID = "school"
+	assert.Equal(t, storagedomain.ErrInsufficientStock("book", 20, 21), handler.Handle(ctx, lend))
+	lend.Quantity = 15
+	assert.Nil(t, handler.Handle(ctx, lend))
+
+	classes, storages := loadAggregates(t, classStore, storageStore)
+	assert.Equal(t, 15, classes.Classes[0].Quantity("book"))
+	assert.Equal(t, 15, storages.Storages[0].Quantity("book"))
+	aggregate := reservationdomain.NewSchoolReservationAggregateWithID("school")
+	assert.Nil(t, application.NewCommandHandlerModel(reservationStore, nil).LoadAggregate(ctx, aggregate))
+	assert.Equal(t, 0, aggregate.Reservations[0].Fulfilled)
+	assert.Equal(t, 10, aggregate.Reservations[1].Fulfilled)
+}
+
+func TestReturnBooks(t *testing.T) {
+	tests := []struct {
+		name           string
+		command        classapp.ReturnBooksCommand
+		classQuantity  int
+		storedQuantity int
+		expectError    bool
+	}{
+		{
+			name:           "return books",
+			command:        classapp.ReturnBooksCommand{ClassID: "class", StorageID: "storage", BookID: "book", Quantity: 10},
+			classQuantity:  15,
+			storedQuantity: 15,
+		},
+		{
+			name:           "return more books than lent",
+			command:        classapp.ReturnBooksCommand{ClassID: "class", StorageID: "storage", BookID: "book", Quantity: 26},
+			classQuantity:  25,
+			storedQuantity: 5,
+			expectError:    true,
+		},
+		{
+			name:           "storage not found",
+			command:        classapp.ReturnBooksCommand{ClassID: "class", StorageID: "unknown", BookID: "book", Quantity: 10},
+			classQuantity:  25,
+			storedQuantity: 5,
+			expectError:    true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := context.Background()
+			classStore, storageStore := newStores()
+			handlers := classapp.NewClassCommandHandlers(classStore, nil, storageStore, nil, memory.NewMemoryStore(), nil, memory.NewMemoryStore())
+			lend := classapp.LendBooksCommand{
+				CommandModel: application.CommandModel{ID: "school"},
+				ClassID:      "class",
+				StorageID:    "storage",
+				BookID:       "book",
+				Quantity:     25,
+			}
+			assert.Nil(t, handlers.LendBooksHandler.Handle(ctx, lend))
+			test.command.ID = "school"
+			err := handlers.ReturnBooksHandler.Handle(ctx, test.command)
+			if test.expectError {
+				assert.Error(t, err)
+			} else {
+				assert.Nil(t, err)
+			}
+			classes, storages := loadAggregates(t, classStore, storageStore)
+			assert.Equal(t, test.classQuantity, classes.Classes[0].Quantity("book"))
+			assert.Equal(t, test.storedQuantity, storages.Storages[0].Quantity("book"))
+		})
+	}
+}
+
+func TestReturnAllBooks(t *testing.T) {
+	ctx := context.Background()
+	classStore, storageStore := newStores()
+	handlers := classapp.NewClassCommandHandlers(classStore, nil, storageStore, nil, memory.NewMemoryStore(), nil, memory.NewMemoryStore())
+	lend := classapp.LendBooksCommand{
+		CommandModel: application.CommandModel{ID: "school"},
+		ClassID:      "class",
+		StorageID:    "storage",
+		BookID:       "book",
+		Quantity:     25,
+	}
+	assert.Nil(t, handlers.LendBooksHandler.Handle(ctx, lend))
+	returnAll := classapp.ReturnAllBooksCommand{
+		CommandModel: application.CommandModel{ID: "school"},
+		ClassID:      "class",
+		StorageID:    "storage",
+	}
+	assert.Nil(t, handlers.ReturnAllBooksHandler.Handle(ctx, returnAll))
+	classes, storages := loadAggregates(t, classStore, storageStore)
+	assert.Empty(t, classes.Classes[0].Books)
+	assert.Equal(t, 30, storages.Storages[0].Quantity("book"))
+
+	returnAll.ClassID = "unknown"
+	assert.Error(t, handlers.ReturnAllBooksHandler.Handle(ctx, returnAll))
+}
+
+func TestCreateClass(t *testing.T) {
+	tests := []struct {
+		name            string
+		command         classapp.CreateClassCommand
+		numberOfClasses int
+		expectError     bool
+	}{
+		{
+			name: "create class",
+			command: classapp.CreateClassCommand{
+				Grade:          6,
+				Letter:         "b",
+				NumberOfPupils: 24,
+				DateFrom:       time.Date(2022, 8, 1, 0, 0, 0, 0, time.UTC),
+				DateTo:         time.Date(2023, 7, 31, 0, 0, 0, 0, time.UTC),
+			},
+			numberOfClasses: 2,
+		},
+		{
+			name: "grade not set",
+			command: classapp.CreateClassCommand{
+				Letter:   "b",
+				DateFrom: time.Date(2022, 8, 1, 0, 0, 0, 0, time.UTC),
+				DateTo:   time.Date(2023, 7, 31, 0, 0, 0, 0, time.UTC),
+			},
+			numberOfClasses: 1,
+			expectError:     true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			classStore, storageStore := newStores()
+			handler := classapp.NewCreateClassCommandHandler(classStore, nil)
+			test.command.ID = "school"
+			classID, err := handler.Handle(context.Background(), test.command)
+			if test.expectError {
+				assert.Error(t, err)
+				assert.Empty(t, classID)
+			} else {
+				assert.Nil(t, err)
+				assert.NotEmpty(t, classID)
+			}
+			classes, _ := loadAggregates(t, classStore, storageStore)
+			assert.Len(t, classes.Classes, test.numberOfClasses)
+		})
+	}
+}
+
+func TestChangeNumberOfPupils(t *testing.T) {
+	ctx := context.Background()
+	classStore, storageStore := newStores()
+	handlers := classapp.NewClassCommandHandlers(classStore, nil, storageStore, nil, memory.NewMemoryStore(), nil, memory.NewMemoryStore())
+	increase := classapp.IncreaseNumberOfPupilsCommand{
+		CommandModel: application.CommandModel{ID: "school"},
+		ClassID:      "class",
+		Number:       3,
+		Reason:       "new pupils",
+	}
+	assert.Nil(t, handlers.IncreaseNumberOfPupilsHandler.Handle(ctx, increase))
+	decrease := classapp.DecreaseNumberOfPupilsCommand{
+		CommandModel: application.CommandModel{ID: "school"},
+		ClassID:      "class",
+		Number:       5,
+		Reason:       "pupils left",
+	}
+	assert.Nil(t, handlers.DecreaseNumberOfPupilsHandler.Handle(ctx, decrease))
+	classes, _ := loadAggregates(t, classStore, storageStore)
+	assert.Equal(t, 23, classes.Classes[0].NumberOfPupils)
+
+	decrease.ClassID = "unknown"
+	assert.Error(t, handlers.DecreaseNumberOfPupilsHandler.Handle(ctx, decrease))
+	increase.Reason = ""
+	assert.Error(t, handlers.IncreaseNumberOfPupilsHandler.Handle(ctx, increase))
+}
+
+func TestRollover(t *testing.T) {
+	ctx := context.Background()
+	classStore := memory.NewMemoryStore()
+	pupilStore := memory.NewMemoryStore()
+	handlers := classapp.NewClassCommandHandlers(classStore, nil, memory.NewMemoryStore(), nil, pupilStore, nil, memory.NewMemoryStore())
+	pupilHandlers := pupilapp.NewPupilCommandHandlers(pupilStore, nil, classStore, nil)
+	from := time.Date(2022, 8, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2023, 7, 31, 0, 0, 0, 0, time.UTC)
+	classIDs := []string{}
+	for _, grade := range []int{3, 4} {
+		create := classapp.CreateClassCommand{
+			CommandModel: application.CommandModel{ID: "school"},
+			Grade:        grade,
+			Letter:       "a",
+			DateFrom:     from,
+			DateTo:       to,
+		}
+		classID, err := handlers.CreateClassHandler.Handle(ctx, create)
+		assert.Nil(t, err)
+		enrol := pupilapp.EnrolPupilCommand{
+			CommandModel: application.CommandModel{ID: "school"},
+			FirstName:    "Max",
+			LastName:     "Mustermann",
+			DateOfBirth:  time.Date(2014-grade, 5, 1, 0, 0, 0, 0, time.UTC),
+			ClassID:      classID,
+			EntryDate:    from,
+		}
+		_, err = pupilHandlers.EnrolPupilHandler.Handle(ctx, enrol)
+		assert.Nil(t, err)
+		classIDs = append(classIDs, classID)
+	}
+	rollover := classapp.RolloverCommand{
+		CommandModel: application.CommandModel{ID: "school"},
+		At:           time.Date(2023, 8, 1, 0, 0, 0, 0, time.UTC),
+		FinalGrade:   4,
+		DryRun:       true,
+	}
+	preview, err := handlers.RolloverHandler.Handle(ctx, rollover)
+	assert.Nil(t, err)
+	assert.Len(t, preview.ClosedClasses, 2)
+	assert.Len(t, preview.CreatedClasses, 1)
+	classes := classdomain.NewSchoolClassAggregateWithID("school")
+	assert.Nil(t, application.NewCommandHandlerModel(classStore, nil).LoadAggregate(ctx, classes))
+	assert.Len(t, classes.Classes, 2)
+
+	rollover.DryRun = false
+	report, err := handlers.RolloverHandler.Handle(ctx, rollover)
+	assert.Nil(t, err)
+	assert.Len(t, report.ClosedClasses, 2)
+	successorID := report.CreatedClasses[0].ClassID
+	classes = classdomain.NewSchoolClassAggregateWithID("school")
+	assert.Nil(t, application.NewCommandHandlerModel(classStore, nil).LoadAggregate(ctx, classes))
+	assert.Len(t, classes.Classes, 3)
+	pupils := pupildomain.NewSchoolPupilAggregateWithID("school")
+	assert.Nil(t, application.NewCommandHandlerModel(pupilStore, nil).LoadAggregate(ctx, pupils))
+	for _, pupil := range pupils.Pupils {
+		if pupil.Left() {
+			assert.Equal(t, classIDs[1], pupil.ClassID)
+			continue
+		}
+		assert.Equal(t, successorID, pupil.ClassID)
+	}
+
+	report, err = handlers.RolloverHandler.Handle(ctx, rollover)
+	assert.Nil(t, err)
+	assert.Empty(t, report.ClosedClasses)
+}
+
+func TestRolloverIntoSuccessorHoldingPupil(t *testing.T) {
+	ctx := context.Background()
+	classStore := memory.NewMemoryStore()
+	pupilStore := memory.NewMemoryStore()
+	handlers := classapp.NewClassCommandHandlers(classStore, nil, memory.NewMemoryStore(), nil, pupilStore, nil, memory.NewMemoryStore())
+	pupilHandlers := pupilapp.NewPupilCommandHandlers(pupilStore, nil, classStore, nil)
+	from := time.Date(2022, 8, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2023, 7, 31, 0, 0, 0, 0, time.UTC)
+	classIDs := []string{}
+	for year := 0; year <= 1; year++ {
+		create := classapp.CreateClassCommand{
+			CommandModel: application.CommandModel{ID: "school"},
+			Grade:        3 + year,
+			Letter:       "a",
+			DateFrom:     from.AddDate(year, 0, 0),
+			DateTo:       to.AddDate(year, 0, 0),
+		}
+		classID, err := handlers.CreateClassHandler.Handle(ctx, create)
+		assert.Nil(t, err)
+		classIDs = append(classIDs, classID)
+	}
+	enrol := pupilapp.EnrolPupilCommand{
+		CommandModel: application.CommandModel{ID: "school"},
+		FirstName:    "Max",
+		LastName:     "Mustermann",
+		DateOfBirth:  time.Date(2014, 5, 1, 0, 0, 0, 0, time.UTC),
+		ClassID:      classIDs[0],
+		EntryDate:    from,
+	}
+	pupilID, err := pupilHandlers.EnrolPupilHandler.Handle(ctx, enrol)
+	assert.Nil(t, err)
+
+	// The pupil already went over to the successor, but is still listed in
+	// the class of the ending school year.
+	classes := classdomain.NewSchoolClassAggregateWithID("school")
+	classModel := application.NewCommandHandlerModel(classStore, nil)
+	assert.Nil(t, classModel.LoadAggregate(ctx, classes))
+	assert.Nil(t, classes.AddPupil(classIDs[1], pupilID))
+	assert.Nil(t, classModel.SaveAndPublish(ctx, classes))
+	pupils := pupildomain.NewSchoolPupilAggregateWithID("school")
+	pupilModel := application.NewCommandHandlerModel(pupilStore, nil)
+	assert.Nil(t, pupilModel.LoadAggregate(ctx, pupils))
+	_, err = pupils.MovePupil(pupilID, classIDs[1], "moved up early")
+	assert.Nil(t, err)
+	assert.Nil(t, pupilModel.SaveAndPublish(ctx, pupils))
+
+	rollover := classapp.RolloverCommand{
+		CommandModel: application.CommandModel{ID: "school"},
+		At:           time.Date(2023, 8, 1, 0, 0, 0, 0, time.UTC),
+		FinalGrade:   4,
+	}
+	report, err := handlers.RolloverHandler.Handle(ctx, rollover)
+	assert.Nil(t, err)
+	assert.Len(t, report.ClosedClasses, 1)
+	assert.Equal(t, classIDs[1], report.ClosedClasses[0].SuccessorID)
+	pupils = pupildomain.NewSchoolPupilAggregateWithID("school")
+	assert.Nil(t, pupilModel.LoadAggregate(ctx, pupils))
+	assert.Equal(t, classIDs[1], pupils.Pupils[0].ClassID)
+	classes = classdomain.NewSchoolClassAggregateWithID("school")
+	assert.Nil(t, classModel.LoadAggregate(ctx, classes))
+	successor := fp.Find(classes.Classes, func(c classdomain.Class) bool { return c.ID == classIDs[1] })
+	assert.True(t, successor.HasPupil(pupilID))
+}
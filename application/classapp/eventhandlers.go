@@ -0,0 +1,195 @@
+package classapp
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/kammeph/school-book-storage-service/application"
+	"github.com/kammeph/school-book-storage-service/domain"
+	"github.com/kammeph/school-book-storage-service/domain/classdomain"
+	"github.com/kammeph/school-book-storage-service/fp"
+)
+
+type ClassEventHandler struct {
+	repository ClassWithBooksRepository
+}
+
+func NewClassEventHandler(repository ClassWithBooksRepository) application.EventHandler {
+	return &ClassEventHandler{repository}
+}
+
+func (h ClassEventHandler) Handle(ctx context.Context, eventBytes []byte) error {
+	event := &domain.EventModel{}
+	if err := json.Unmarshal(eventBytes, event); err != nil {
+		return err
+	}
+	switch event.EventType() {
+	case classdomain.ClassCreated:
+		return h.handleClassCreated(ctx, event)
+	case classdomain.NumberOfPupilsIncreased:
+		return h.handleNumberOfPupilsIncreased(ctx, event)
+	case classdomain.NumberOfPupilsDecreased:
+		return h.handleNumberOfPupilsDecreased(ctx, event)
+	case classdomain.BooksReceived:
+		return h.handleBooksReceived(ctx, event)
+	case classdomain.BooksReturned:
+		return h.handleBooksReturned(ctx, event)
+	case classdomain.PupilAdded:
+		return h.handlePupilAdded(ctx, event)
+	case classdomain.PupilRemoved:
+		return h.handlePupilRemoved(ctx, event)
+	case classdomain.ClassClosed:
+		return h.handleClassClosed(ctx, event)
+	default:
+		return nil
+	}
+}
+
+func (h ClassEventHandler) handleClassCreated(ctx context.Context, event domain.Event) error {
+	classCreated := classdomain.ClassCreatedEvent{}
+	if err := event.GetJsonData(&classCreated); err != nil {
+		return err
+	}
+	class := classdomain.NewClassWithBooks(
+		classCreated.SchoolID,
+		classCreated.ClassID,
+		classCreated.Grade,
+		classCreated.Letter,
+		classCreated.NumberOfPupils,
+		classCreated.DateFrom,
+		classCreated.DateTo,
+		event.EventVersion())
+	return h.repository.UpsertClass(ctx, class)
+}
+
+func (h ClassEventHandler) handleNumberOfPupilsIncreased(ctx context.Context, event domain.Event) error {
+	pupilsIncreased := classdomain.NumberOfPupilsIncreasedEvent{}
+	if err := event.GetJsonData(&pupilsIncreased); err != nil {
+		return err
+	}
+	return h.updateNumberOfPupils(ctx, event, pupilsIncreased.ClassID, pupilsIncreased.Number)
+}
+
+func (h ClassEventHandler) handleNumberOfPupilsDecreased(ctx context.Context, event domain.Event) error {
+	pupilsDecreased := classdomain.NumberOfPupilsDecreasedEvent{}
+	if err := event.GetJsonData(&pupilsDecreased); err != nil {
+		return err
+	}
+	return h.updateNumberOfPupils(ctx, event, pupilsDecreased.ClassID, -pupilsDecreased.Number)
+}
+
+func (h ClassEventHandler) handleBooksReceived(ctx context.Context, event domain.Event) error {
+	booksReceived := classdomain.BooksReceivedEvent{}
+	if err := event.GetJsonData(&booksReceived); err != nil {
+		return err
+	}
+	return h.updateBooks(ctx, event, booksReceived.ClassID, func(books []classdomain.BookInClass) []classdomain.BookInClass {
+		for idx, book := range books {
+			if book.BookID == booksReceived.BookID {
+				books[idx].Quantity += booksReceived.Quantity
+				return books
+			}
+		}
+		return append(books, classdomain.BookInClass{
+			BookID:   booksReceived.BookID,
+			Isbn:     booksReceived.Isbn,
+			Title:    booksReceived.Title,
+			Quantity: booksReceived.Quantity,
+		})
+	})
+}
+
+func (h ClassEventHandler) handleBooksReturned(ctx context.Context, event domain.Event) error {
+	booksReturned := classdomain.BooksReturnedEvent{}
+	if err := event.GetJsonData(&booksReturned); err != nil {
+		return err
+	}
+	return h.updateBooks(ctx, event, booksReturned.ClassID, func(books []classdomain.BookInClass) []classdomain.BookInClass {
+		for idx, book := range books {
+			if book.BookID == booksReturned.BookID {
+				books[idx].Quantity -= booksReturned.Quantity
+				if books[idx].Quantity <= 0 {
+					return append(books[:idx], books[idx+1:]...)
+				}
+				return books
+			}
+		}
+		return books
+	})
+}
+
+func (h ClassEventHandler) handlePupilAdded(ctx context.Context, event domain.Event) error {
+	pupilAdded := classdomain.PupilAddedEvent{}
+	if err := event.GetJsonData(&pupilAdded); err != nil {
+		return err
+	}
+	return h.updatePupils(ctx, event, pupilAdded.ClassID, func(pupils []string) []string {
+		return append(pupils, pupilAdded.PupilID)
+	})
+}
+
+func (h ClassEventHandler) handlePupilRemoved(ctx context.Context, event domain.Event) error {
+	pupilRemoved := classdomain.PupilRemovedEvent{}
+	if err := event.GetJsonData(&pupilRemoved); err != nil {
+		return err
+	}
+	return h.updatePupils(ctx, event, pupilRemoved.ClassID, func(pupils []string) []string {
+		return fp.Remove(pupils, func(id string) bool { return id == pupilRemoved.PupilID })
+	})
+}
+
+func (h ClassEventHandler) handleClassClosed(ctx context.Context, event domain.Event) error {
+	classClosed := classdomain.ClassClosedEvent{}
+	if err := event.GetJsonData(&classClosed); err != nil {
+		return err
+	}
+	return h.repository.UpdateClassClosed(ctx, classClosed.ClassID, classClosed.SuccessorID, event.EventVersion())
+}
+
+// updateNumberOfPupils, updateBooks and updatePupils change the class relative to the
+// stored values. Events the class already reflects are skipped, so a
+// redelivered event is not counted twice.
+func (h ClassEventHandler) updateNumberOfPupils(ctx context.Context, event domain.Event, classID string, difference int) error {
+	class, err := h.repository.GetClassByID(ctx, event.AggregateID(), classID)
+	if err != nil {
+		return err
+	}
+	if class.Version >= event.EventVersion() {
+		return nil
+	}
+	return h.repository.UpdateClassNumberOfPupils(ctx, classID, class.NumberOfPupils+difference, event.EventVersion())
+}
+
+func (h ClassEventHandler) updateBooks(
+	ctx context.Context,
+	event domain.Event,
+	classID string,
+	update func(books []classdomain.BookInClass) []classdomain.BookInClass,
+) error {
+	class, err := h.repository.GetClassByID(ctx, event.AggregateID(), classID)
+	if err != nil {
+		return err
+	}
+	if class.Version >= event.EventVersion() {
+		return nil
+	}
+	books := append([]classdomain.BookInClass{}, class.Books...)
+	return h.repository.UpdateClassBooks(ctx, classID, update(books), event.EventVersion())
+}
+
+func (h ClassEventHandler) updatePupils(
+	ctx context.Context,
+	event domain.Event,
+	classID string,
+	update func(pupils []string) []string,
+) error {
+	class, err := h.repository.GetClassByID(ctx, event.AggregateID(), classID)
+	if err != nil {
+		return err
+	}
+	if class.Version >= event.EventVersion() {
+		return nil
+	}
+	pupils := append([]string{}, class.Pupils...)
+	return h.repository.UpdateClassPupils(ctx, classID, update(pupils), event.EventVersion())
+}
@@ -0,0 +1,91 @@
+package classapp_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/kammeph/school-book-storage-service/application/classapp"
+	"github.com/kammeph/school-book-storage-service/domain"
+	"github.com/kammeph/school-book-storage-service/domain/classdomain"
+	"github.com/kammeph/school-book-storage-service/infrastructure/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+func classEvent(version int, eventType, data string) []byte {
+	eventBytes, _ := json.Marshal(domain.EventModel{
+		ID:      "school",
+		Type:    eventType,
+		Version: version,
+		At:      time.Now(),
+		Data:    data,
+	})
+	return eventBytes
+}
+
+func TestHandleClassEvents(t *testing.T) {
+	ctx := context.Background()
+	repository := memory.NewMemoryClassRepository()
+	handler := classapp.NewClassEventHandler(repository)
+	events := [][]byte{
+		classEvent(1, classdomain.ClassCreated, "{\"schoolId\":\"school\",\"classId\":\"class\",\"grade\":5,\"letter\":\"a\",\"numberOfPupils\":25}"),
+		classEvent(2, classdomain.BooksReceived, "{\"classId\":\"class\",\"storageId\":\"storage\",\"bookId\":\"book\",\"isbn\":\"123\",\"title\":\"math\",\"quantity\":25}"),
+		classEvent(3, classdomain.BooksReturned, "{\"classId\":\"class\",\"storageId\":\"storage\",\"bookId\":\"book\",\"quantity\":5}"),
+	}
+	for _, event := range events {
+		assert.Nil(t, handler.Handle(ctx, event))
+	}
+	class, err := repository.GetClassByID(ctx, "school", "class")
+	assert.Nil(t, err)
+	assert.Equal(t, []classdomain.BookInClass{{BookID: "book", Isbn: "123", Title: "math", Quantity: 20}}, class.Books)
+	assert.Equal(t, 3, class.Version)
+
+	// redelivered events must not change the projection twice
+	assert.Nil(t, handler.Handle(ctx, events[2]))
+	class, _ = repository.GetClassByID(ctx, "school", "class")
+	assert.Equal(t, 20, class.Books[0].Quantity)
+
+	assert.Nil(t, handler.Handle(ctx, classEvent(4, classdomain.BooksReturned, "{\"classId\":\"class\",\"storageId\":\"storage\",\"bookId\":\"book\",\"quantity\":20}")))
+	class, _ = repository.GetClassByID(ctx, "school", "class")
+	assert.Empty(t, class.Books)
+
+	assert.Error(t, handler.Handle(ctx, classEvent(5, classdomain.BooksReceived, "{\"classId\":")))
+}
+
+func TestHandlePupilsOfClass(t *testing.T) {
+	ctx := context.Background()
+	repository := memory.NewMemoryClassRepository()
+	handler := classapp.NewClassEventHandler(repository)
+	events := [][]byte{
+		classEvent(1, classdomain.ClassCreated, "{\"schoolId\":\"school\",\"classId\":\"class\",\"grade\":5,\"letter\":\"a\",\"numberOfPupils\":25}"),
+		classEvent(2, classdomain.PupilAdded, "{\"classId\":\"class\",\"pupilId\":\"pupil1\"}"),
+		classEvent(3, classdomain.PupilAdded, "{\"classId\":\"class\",\"pupilId\":\"pupil2\"}"),
+		classEvent(4, classdomain.PupilRemoved, "{\"classId\":\"class\",\"pupilId\":\"pupil1\"}"),
+	}
+	for _, event := range events {
+		assert.Nil(t, handler.Handle(ctx, event))
+	}
+	assert.Nil(t, handler.Handle(ctx, events[2]))
+	class, err := repository.GetClassByID(ctx, "school", "class")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"pupil2"}, class.Pupils)
+	assert.Equal(t, 1, class.NumberOfPupils)
+}
+
+func TestHandleClassClosed(t *testing.T) {
+	ctx := context.Background()
+	repository := memory.NewMemoryClassRepository()
+	handler := classapp.NewClassEventHandler(repository)
+	events := [][]byte{
+		classEvent(1, classdomain.ClassCreated, "{\"schoolId\":\"school\",\"classId\":\"class\",\"grade\":5,\"letter\":\"a\",\"numberOfPupils\":0}"),
+		classEvent(2, classdomain.ClassClosed, "{\"classId\":\"class\",\"successorId\":\"successor\"}"),
+	}
+	for _, event := range events {
+		assert.Nil(t, handler.Handle(ctx, event))
+	}
+	class, err := repository.GetClassByID(ctx, "school", "class")
+	assert.Nil(t, err)
+	assert.True(t, class.Closed)
+	assert.Equal(t, "successor", class.SuccessorID)
+}
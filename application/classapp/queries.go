@@ -0,0 +1,86 @@
+package classapp
+
+import (
+	"context"
+
+	"github.com/kammeph/school-book-storage-service/application"
+	"github.com/kammeph/school-book-storage-service/domain/classdomain"
+)
+
+type ClassQueryHandlers struct {
+	GetAllHandler          GetAllClassesQueryHandler
+	GetBySchoolYearHandler GetClassesBySchoolYearQueryHandler
+	GetClassByIDHandler    GetClassByIDQueryHandler
+}
+
+func NewClassQueryHandlers(repository ClassWithBooksRepository) ClassQueryHandlers {
+	return ClassQueryHandlers{
+		GetAllHandler:          NewGetAllClassesQueryHandler(repository),
+		GetBySchoolYearHandler: NewGetClassesBySchoolYearQueryHandler(repository),
+		GetClassByIDHandler:    NewGetClassByIDQueryHandler(repository),
+	}
+}
+
+type GetAllClasses struct {
+	application.QueryModel
+}
+
+func NewGetAllClasses(aggregateID string) GetAllClasses {
+	return GetAllClasses{QueryModel: application.QueryModel{ID: aggregateID}}
+}
+
+type GetAllClassesQueryHandler struct {
+	repository ClassWithBooksRepository
+}
+
+func NewGetAllClassesQueryHandler(repository ClassWithBooksRepository) GetAllClassesQueryHandler {
+	return GetAllClassesQueryHandler{repository: repository}
+}
+
+func (h GetAllClassesQueryHandler) Handle(ctx context.Context, query GetAllClasses) ([]classdomain.ClassWithBooks, error) {
+	return h.repository.GetClassesBySchoolID(ctx, query.AggregateID())
+}
+
+// GetClassesBySchoolYear asks for the classes of the school year that starts
+// in the given year.
+type GetClassesBySchoolYear struct {
+	application.QueryModel
+	Year int
+}
+
+func NewGetClassesBySchoolYear(aggregateID string, year int) GetClassesBySchoolYear {
+	return GetClassesBySchoolYear{QueryModel: application.QueryModel{ID: aggregateID}, Year: year}
+}
+
+type GetClassesBySchoolYearQueryHandler struct {
+	repository ClassWithBooksRepository
+}
+
+func NewGetClassesBySchoolYearQueryHandler(repository ClassWithBooksRepository) GetClassesBySchoolYearQueryHandler {
+	return GetClassesBySchoolYearQueryHandler{repository: repository}
+}
+
+func (h GetClassesBySchoolYearQueryHandler) Handle(ctx context.Context, query GetClassesBySchoolYear) ([]classdomain.ClassWithBooks, error) {
+	return h.repository.GetClassesBySchoolYear(ctx, query.AggregateID(), query.Year)
+}
+
+type GetClassByID struct {
+	application.QueryModel
+	ClassID string
+}
+
+func NewGetClassByID(aggregateID, classID string) GetClassByID {
+	return GetClassByID{QueryModel: application.QueryModel{ID: aggregateID}, ClassID: classID}
+}
+
+type GetClassByIDQueryHandler struct {
+	repository ClassWithBooksRepository
+}
+
+func NewGetClassByIDQueryHandler(repository ClassWithBooksRepository) GetClassByIDQueryHandler {
+	return GetClassByIDQueryHandler{repository: repository}
+}
+
+func (h GetClassByIDQueryHandler) Handle(ctx context.Context, query GetClassByID) (classdomain.ClassWithBooks, error) {
+	return h.repository.GetClassByID(ctx, query.AggregateID(), query.ClassID)
+}
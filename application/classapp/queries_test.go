@@ -0,0 +1,45 @@
+package classapp_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/kammeph/school-book-storage-service/application/classapp"
+	"github.com/kammeph/school-book-storage-service/domain/classdomain"
+	"github.com/kammeph/school-book-storage-service/infrastructure/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetClassesBySchoolYear(t *testing.T) {
+	repository := memory.NewMemoryClassRepositoryWithClasses([]classdomain.ClassWithBooks{
+		classdomain.NewClassWithBooks("school1", "class1", 5, "a", 25,
+			time.Date(2021, 8, 1, 0, 0, 0, 0, time.UTC), time.Date(2022, 7, 31, 0, 0, 0, 0, time.UTC), 1),
+		classdomain.NewClassWithBooks("school1", "class2", 6, "a", 25,
+			time.Date(2022, 8, 1, 0, 0, 0, 0, time.UTC), time.Date(2023, 7, 31, 0, 0, 0, 0, time.UTC), 2),
+		classdomain.NewClassWithBooks("school1", "class3", 6, "b", 24,
+			time.Date(2022, 8, 1, 0, 0, 0, 0, time.UTC), time.Date(2023, 7, 31, 0, 0, 0, 0, time.UTC), 3),
+		classdomain.NewClassWithBooks("school2", "class4", 5, "a", 20,
+			time.Date(2022, 8, 1, 0, 0, 0, 0, time.UTC), time.Date(2023, 7, 31, 0, 0, 0, 0, time.UTC), 1),
+	})
+	tests := []struct {
+		name            string
+		schoolID        string
+		year            int
+		numberOfClasses int
+	}{
+		{name: "current school year", schoolID: "school1", year: 2022, numberOfClasses: 2},
+		{name: "previous school year", schoolID: "school1", year: 2021, numberOfClasses: 1},
+		{name: "other school", schoolID: "school2", year: 2022, numberOfClasses: 1},
+		{name: "no classes", schoolID: "school1", year: 2020, numberOfClasses: 0},
+	}
+	handlers := classapp.NewClassQueryHandlers(repository)
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			query := classapp.NewGetClassesBySchoolYear(test.schoolID, test.year)
+			classes, err := handlers.GetBySchoolYearHandler.Handle(context.Background(), query)
+			assert.Nil(t, err)
+			assert.Len(t, classes, test.numberOfClasses)
+		})
+	}
+}
@@ -0,0 +1,18 @@
+package classapp
+
+import (
+	"context"
+
+	"github.com/kammeph/school-book-storage-service/domain/classdomain"
+)
+
+type ClassWithBooksRepository interface {
+	GetClassesBySchoolID(ctx context.Context, schoolID string) ([]classdomain.ClassWithBooks, error)
+	GetClassesBySchoolYear(ctx context.Context, schoolID string, year int) ([]classdomain.ClassWithBooks, error)
+	GetClassByID(ctx context.Context, schoolID, classID string) (classdomain.ClassWithBooks, error)
+	UpsertClass(ctx context.Context, class classdomain.ClassWithBooks) error
+	UpdateClassNumberOfPupils(ctx context.Context, classID string, numberOfPupils, version int) error
+	UpdateClassBooks(ctx context.Context, classID string, books []classdomain.BookInClass, version int) error
+	UpdateClassPupils(ctx context.Context, classID string, pupils []string, version int) error
+	UpdateClassClosed(ctx context.Context, classID, successorID string, version int) error
+}
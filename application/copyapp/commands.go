@@ -0,0 +1,388 @@
+package copyapp
+
+import (
+	"context"
+	"time"
+
+	"github.com/kammeph/school-book-storage-service/application"
+	"github.com/kammeph/school-book-storage-service/domain/bookdomain"
+	"github.com/kammeph/school-book-storage-service/domain/classdomain"
+	"github.com/kammeph/school-book-storage-service/domain/copydomain"
+	"github.com/kammeph/school-book-storage-service/domain/pupildomain"
+	"github.com/kammeph/school-book-storage-service/domain/reservationdomain"
+	"github.com/kammeph/school-book-storage-service/domain/storagedomain"
+	"github.com/kammeph/school-book-storage-service/fp"
+)
+
+type CopyCommandHandlers struct {
+	ConfigureNumberingHandler ConfigureNumberingCommandHandler
+	RegisterCopiesHandler     RegisterCopiesCommandHandler
+	MoveCopyHandler           MoveCopyCommandHandler
+	GradeCopyHandler          GradeCopyCommandHandler
+	WriteOffCopyHandler       WriteOffCopyCommandHandler
+}
+
+func NewCopyCommandHandlers(
+	store application.Store,
+	publisher application.EventPublisher,
+	storageStore application.Store,
+	storagePublisher application.EventPublisher,
+	classStore application.Store,
+	classPublisher application.EventPublisher,
+	bookStore application.Store,
+	pupilStore application.Store,
+	reservationStore application.Store,
+) CopyCommandHandlers {
+	model := newInventoryModel(store, publisher, storageStore, storagePublisher, classStore, classPublisher)
+	return CopyCommandHandlers{
+		ConfigureNumberingHandler: NewConfigureNumberingCommandHandler(store, publisher),
+		RegisterCopiesHandler:     NewRegisterCopiesCommandHandler(model, bookStore),
+		MoveCopyHandler:           NewMoveCopyCommandHandler(model, pupilStore, reservationStore),
+		GradeCopyHandler:          NewGradeCopyCommandHandler(model),
+		WriteOffCopyHandler:       NewWriteOffCopyCommandHandler(model),
+	}
+}
+
+// MoveReason is the reason recorded on the stock of storages for copies that
+// are moved without a reason.
+const MoveReason = "copy moved"
+
+// RegisterReason is the reason recorded on the stock of storages for copies
+// that are registered in a worse condition than good.
+const RegisterReason = "copies registered"
+
+// inventoryModel loads and saves the copies together with the storages and
+// classes of a school, so the stock follows the copies. All aggregates are
+// changed in memory first and the storages are saved first as they guard the
+// stock.
+type inventoryModel struct {
+	copies   *application.CommandHandlerModel
+	storages *application.CommandHandlerModel
+	classes  *application.CommandHandlerModel
+}
+
+func newInventoryModel(
+	store application.Store,
+	publisher application.EventPublisher,
+	storageStore application.Store,
+	storagePublisher application.EventPublisher,
+	classStore application.Store,
+	classPublisher application.EventPublisher,
+) inventoryModel {
+	return inventoryModel{
+		copies:   application.NewCommandHandlerModel(store, publisher),
+		storages: application.NewCommandHandlerModel(storageStore, storagePublisher),
+		classes:  application.NewCommandHandlerModel(classStore, classPublisher),
+	}
+}
+
+func (m inventoryModel) load(
+	ctx context.Context,
+	schoolID string,
+) (*copydomain.SchoolCopyAggregate, *storagedomain.SchoolStorageAggregate, *classdomain.SchoolClassAggregate, error) {
+	copies := copydomain.NewSchoolCopyAggregateWithID(schoolID)
+	if err := m.copies.LoadAggregate(ctx, copies); err != nil {
+		return nil, nil, nil, err
+	}
+	storages := storagedomain.NewSchoolStorageAggregateWithID(schoolID)
+	if err := m.storages.LoadAggregate(ctx, storages); err != nil {
+		return nil, nil, nil, err
+	}
+	classes := classdomain.NewSchoolClassAggregateWithID(schoolID)
+	if err := m.classes.LoadAggregate(ctx, classes); err != nil {
+		return nil, nil, nil, err
+	}
+	return copies, storages, classes, nil
+}
+
+func (m inventoryModel) save(
+	ctx context.Context,
+	copies *copydomain.SchoolCopyAggregate,
+	storages *storagedomain.SchoolStorageAggregate,
+	classes *classdomain.SchoolClassAggregate,
+) error {
+	if err := m.storages.SaveAndPublish(ctx, storages); err != nil {
+		return err
+	}
+	if err := m.classes.SaveAndPublish(ctx, classes); err != nil {
+		return err
+	}
+	return m.copies.SaveAndPublish(ctx, copies)
+}
+
+type ConfigureNumberingCommand struct {
+	application.CommandModel
+	Prefix string `json:"prefix"`
+	Digits int    `json:"digits"`
+	Next   int    `json:"next"`
+}
+
+type ConfigureNumberingCommandHandler struct {
+	*application.CommandHandlerModel
+}
+
+func NewConfigureNumberingCommandHandler(store application.Store, publisher application.EventPublisher) ConfigureNumberingCommandHandler {
+	return ConfigureNumberingCommandHandler{application.NewCommandHandlerModel(store, publisher)}
+}
+
+// Handle configures the numbering scheme of the school. Without a next
+// number the numbering goes on where it is, for a new scheme it starts at one.
+func (h ConfigureNumberingCommandHandler) Handle(ctx context.Context, command ConfigureNumberingCommand) error {
+	aggregate := copydomain.NewSchoolCopyAggregateWithID(command.AggregateID())
+	if err := h.LoadAggregate(ctx, aggregate); err != nil {
+		return err
+	}
+	next := command.Next
+	if next == 0 {
+		next = aggregate.Numbering.Next
+	}
+	if next == 0 {
+		next = 1
+	}
+	scheme := copydomain.NumberingScheme{Prefix: command.Prefix, Digits: command.Digits, Next: next}
+	if err := aggregate.ConfigureNumbering(scheme); err != nil {
+		return err
+	}
+	return h.SaveAndPublish(ctx, aggregate)
+}
+
+type RegisterCopiesCommand struct {
+	application.CommandModel
+	BookID           string                  `json:"bookId"`
+	StorageID        string                  `json:"storageId"`
+	Condition        storagedomain.Condition `json:"condition"`
+	InventoryNumbers []string                `json:"inventoryNumbers"`
+	Quantity         int                     `json:"quantity"`
+}
+
+type RegisterCopiesCommandHandler struct {
+	inventoryModel
+	books *application.CommandHandlerModel
+}
+
+func NewRegisterCopiesCommandHandler(model inventoryModel, bookStore application.Store) RegisterCopiesCommandHandler {
+	return RegisterCopiesCommandHandler{model, application.NewCommandHandlerModel(bookStore, nil)}
+}
+
+// Handle registers the copies and puts them into the storage. It returns the
+// inventory numbers of the copies.
+func (h RegisterCopiesCommandHandler) Handle(ctx context.Context, command RegisterCopiesCommand) ([]string, error) {
+	books := bookdomain.NewSchoolBookAggregateWithID(command.AggregateID())
+	if err := h.books.LoadAggregate(ctx, books); err != nil {
+		return nil, err
+	}
+	book := fp.Find(books.Books, func(b bookdomain.Book) bool { return b.ID == command.BookID })
+	if book == nil {
+		return nil, bookdomain.ErrBookWithIDNotFound(command.BookID)
+	}
+	copies, storages, classes, err := h.load(ctx, command.AggregateID())
+	if err != nil {
+		return nil, err
+	}
+	inventoryNumbers, err := copies.RegisterCopies(
+		book.ID,
+		book.Isbn,
+		book.Name,
+		command.StorageID,
+		command.Condition,
+		command.InventoryNumbers,
+		command.Quantity)
+	if err != nil {
+		return nil, err
+	}
+	if err := storages.PutBooks(command.StorageID, book.ID, book.Isbn, book.Name, len(inventoryNumbers)); err != nil {
+		return nil, err
+	}
+	if command.Condition.WorseThan(storagedomain.Good) {
+		err := storages.RecordDamage(
+			command.StorageID,
+			book.ID,
+			storagedomain.Good,
+			command.Condition,
+			len(inventoryNumbers),
+			RegisterReason)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if err := h.save(ctx, copies, storages, classes); err != nil {
+		return nil, err
+	}
+	return inventoryNumbers, nil
+}
+
+type MoveCopyCommand struct {
+	application.CommandModel
+	InventoryNumber string              `json:"inventoryNumber"`
+	To              copydomain.Location `json:"to"`
+	Reason          string              `json:"reason"`
+}
+
+type MoveCopyCommandHandler struct {
+	inventoryModel
+	pupils       *application.CommandHandlerModel
+	reservations *application.CommandHandlerModel
+}
+
+func NewMoveCopyCommandHandler(
+	model inventoryModel,
+	pupilStore application.Store,
+	reservationStore application.Store,
+) MoveCopyCommandHandler {
+	return MoveCopyCommandHandler{
+		inventoryModel: model,
+		pupils:         application.NewCommandHandlerModel(pupilStore, nil),
+		reservations:   application.NewCommandHandlerModel(reservationStore, nil),
+	}
+}
+
+// Handle moves the copy and the stock along with it. A copy a class hands out
+// to a pupil or gets back stays in the stock of the class. Reserved copies
+// only leave their storage for the class they are reserved for.
+func (h MoveCopyCommandHandler) Handle(ctx context.Context, command MoveCopyCommand) error {
+	copies, storages, classes, err := h.load(ctx, command.AggregateID())
+	if err != nil {
+		return err
+	}
+	reservations := reservationdomain.NewSchoolReservationAggregateWithID(command.AggregateID())
+	if err := h.reservations.LoadAggregate(ctx, reservations); err != nil {
+		return err
+	}
+	moved, err := copies.MoveCopy(command.InventoryNumber, command.To, command.Reason)
+	if err != nil {
+		return err
+	}
+	reason := command.Reason
+	if reason == "" {
+		reason = MoveReason
+	}
+	from, to := moved.Location, command.To
+	at := time.Now()
+	switch {
+	case from.Kind == copydomain.InStorage && to.Kind == copydomain.InStorage:
+		storages.Holds = reservations.Holds(at, "")
+		err = storages.TransferBooks(from.ID, to.ID, moved.BookID, 1, reason)
+	case from.Kind == copydomain.InStorage && to.Kind == copydomain.InClass:
+		storages.Holds = reservations.Holds(at, to.ID)
+		err = lendCopy(storages, classes, from.ID, to.ID, moved.BookID)
+	case from.Kind == copydomain.InClass && to.Kind == copydomain.InStorage:
+		err = returnCopy(storages, classes, from.ID, to.ID, moved.BookID)
+	case to.Kind == copydomain.WithPupil:
+		err = h.checkPupil(ctx, command.AggregateID(), to.ID, moved.ClassID)
+	}
+	if err != nil {
+		return err
+	}
+	return h.save(ctx, copies, storages, classes)
+}
+
+func (h MoveCopyCommandHandler) checkPupil(ctx context.Context, schoolID, pupilID, classID string) error {
+	pupils := pupildomain.NewSchoolPupilAggregateWithID(schoolID)
+	if err := h.pupils.LoadAggregate(ctx, pupils); err != nil {
+		return err
+	}
+	pupil := fp.Find(pupils.Pupils, func(p pupildomain.Pupil) bool { return p.ID == pupilID })
+	if pupil == nil {
+		return pupildomain.ErrPupilWithIDNotFound(pupilID)
+	}
+	if pupil.Left() {
+		return pupildomain.ErrPupilAlreadyLeft(pupilID)
+	}
+	if pupil.ClassID != classID {
+		return copydomain.ErrPupilNotInClass(pupilID, classID)
+	}
+	return nil
+}
+
+func lendCopy(storages *storagedomain.SchoolStorageAggregate, classes *classdomain.SchoolClassAggregate, storageID, classID, bookID string) error {
+	lent, err := storages.LendBooksToClass(storageID, classID, bookID, 1)
+	if err != nil {
+		return err
+	}
+	book := classdomain.ClassBook{BookID: lent.BookID, Isbn: lent.Isbn, Title: lent.Title, Quantity: lent.Quantity}
+	return classes.ReceiveBooks(classID, storageID, book)
+}
+
+func returnCopy(storages *storagedomain.SchoolStorageAggregate, classes *classdomain.SchoolClassAggregate, classID, storageID, bookID string) error {
+	returned, err := classes.ReturnBooks(classID, storageID, bookID, 1)
+	if err != nil {
+		return err
+	}
+	stock := storagedomain.BookStock{BookID: returned.BookID, Isbn: returned.Isbn, Title: returned.Title, Quantity: returned.Quantity}
+	return storages.ReturnBooksFromClass(storageID, classID, stock)
+}
+
+type GradeCopyCommand struct {
+	application.CommandModel
+	InventoryNumber string                  `json:"inventoryNumber"`
+	Condition       storagedomain.Condition `json:"condition"`
+	Reason          string                  `json:"reason"`
+}
+
+type GradeCopyCommandHandler struct {
+	inventoryModel
+}
+
+func NewGradeCopyCommandHandler(model inventoryModel) GradeCopyCommandHandler {
+	return GradeCopyCommandHandler{model}
+}
+
+// Handle grades the copy and the stock of the storage it is in along with it.
+// The stock of storages does not tell new from good copies, the stock of
+// classes has no conditions at all.
+func (h GradeCopyCommandHandler) Handle(ctx context.Context, command GradeCopyCommand) error {
+	copies, storages, classes, err := h.load(ctx, command.AggregateID())
+	if err != nil {
+		return err
+	}
+	graded := copydomain.Copy{}
+	if bookCopy := fp.Find(copies.Copies, func(c copydomain.Copy) bool { return c.InventoryNumber == command.InventoryNumber }); bookCopy != nil {
+		graded = *bookCopy
+	}
+	if err := copies.GradeCopy(command.InventoryNumber, command.Condition, command.Reason); err != nil {
+		return err
+	}
+	from := graded.Condition
+	if from == storagedomain.New {
+		from = storagedomain.Good
+	}
+	if graded.Location.Kind == copydomain.InStorage && command.Condition.WorseThan(from) {
+		err := storages.RecordDamage(graded.Location.ID, graded.BookID, from, command.Condition, 1, command.Reason)
+		if err != nil {
+			return err
+		}
+	}
+	return h.save(ctx, copies, storages, classes)
+}
+
+type WriteOffCopyCommand struct {
+	application.CommandModel
+	InventoryNumber string `json:"inventoryNumber"`
+	Reason          string `json:"reason"`
+}
+
+type WriteOffCopyCommandHandler struct {
+	inventoryModel
+}
+
+func NewWriteOffCopyCommandHandler(model inventoryModel) WriteOffCopyCommandHandler {
+	return WriteOffCopyCommandHandler{model}
+}
+
+// Handle writes the copy off and writes it off the stock of its storage in its
+// condition.
+func (h WriteOffCopyCommandHandler) Handle(ctx context.Context, command WriteOffCopyCommand) error {
+	copies, storages, classes, err := h.load(ctx, command.AggregateID())
+	if err != nil {
+		return err
+	}
+	writtenOff, err := copies.WriteOffCopy(command.InventoryNumber, command.Reason)
+	if err != nil {
+		return err
+	}
+	err = storages.WriteOffBooks(writtenOff.Location.ID, writtenOff.BookID, writtenOff.Condition, 1, command.Reason)
+	if err != nil {
+		return err
+	}
+	return h.save(ctx, copies, storages, classes)
+}
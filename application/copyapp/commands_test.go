@@ -0,0 +1,242 @@
+package copyapp_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/kammeph/school-book-storage-service/application"
+	"github.com/kammeph/school-book-storage-service/application/copyapp"
+	"github.com/kammeph/school-book-storage-service/domain"
+	"github.com/kammeph/school-book-storage-service/domain/bookdomain"
+	"github.com/kammeph/school-book-storage-service/domain/classdomain"
+	"github.com/kammeph/school-book-storage-service/domain/copydomain"
+	"github.com/kammeph/school-book-storage-service/domain/pupildomain"
+	"github.com/kammeph/school-book-storage-service/domain/storagedomain"
+	"github.com/kammeph/school-book-storage-service/infrastructure/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	closet  = copydomain.StorageLocation("closet")
+	cellar  = copydomain.StorageLocation("cellar")
+	inClass = copydomain.Location{Kind: copydomain.InClass, ID: "class"}
+	anna    = copydomain.Location{Kind: copydomain.WithPupil, ID: "anna"}
+	ben     = copydomain.Location{Kind: copydomain.WithPupil, ID: "ben"}
+)
+
+type stores struct {
+	copies, storages, classes, books, pupils, reservations *memory.MemoryStore
+}
+
+func newStores() stores {
+	return stores{
+		copies: memory.NewMemoryStoreWithEvents([]domain.Event{
+			&domain.EventModel{
+				ID:      "school",
+				Type:    copydomain.NumberingConfigured,
+				Version: 1,
+				At:      time.Now(),
+				Data:    "{\"prefix\":\"LMF-\",\"digits\":4,\"next\":1}",
+			},
+		}),
+		storages: memory.NewMemoryStoreWithEvents([]domain.Event{
+			&domain.EventModel{
+				ID:      "school",
+				Type:    storagedomain.StorageAdded,
+				Version: 1,
+				At:      time.Now(),
+				Data:    "{\"schoolId\":\"school\",\"storageId\":\"closet\",\"name\":\"closet\",\"location\":\"room 1\"}",
+			},
+			&domain.EventModel{
+				ID:      "school",
+				Type:    storagedomain.StorageAdded,
+				Version: 2,
+				At:      time.Now(),
+				Data:    "{\"schoolId\":\"school\",\"storageId\":\"cellar\",\"name\":\"cellar\",\"location\":\"basement\"}",
+			},
+		}),
+		classes: memory.NewMemoryStoreWithEvents([]domain.Event{
+			&domain.EventModel{
+				ID:      "school",
+				Type:    classdomain.ClassCreated,
+				Version: 1,
+				At:      time.Now(),
+				Data:    "{\"schoolId\":\"school\",\"classId\":\"class\",\"grade\":5,\"letter\":\"a\",\"numberOfPupils\":25}",
+			},
+		}),
+		books: memory.NewMemoryStoreWithEvents([]domain.Event{
+			&domain.EventModel{
+				ID:      "school",
+				Type:    bookdomain.BookAdded,
+				Version: 1,
+				At:      time.Now(),
+				Data:    "{\"SchoolID\":\"school\",\"BookID\":\"book\",\"Isbn\":\"9783127323207\",\"Name\":\"Math\"}",
+			},
+		}),
+		pupils: memory.NewMemoryStoreWithEvents([]domain.Event{
+			&domain.EventModel{
+				ID:      "school",
+				Type:    pupildomain.PupilEnrolled,
+				Version: 1,
+				At:      time.Now(),
+				Data:    "{\"schoolId\":\"school\",\"pupilId\":\"anna\",\"firstName\":\"Anna\",\"lastName\":\"Meier\",\"classId\":\"class\"}",
+			},
+			&domain.EventModel{
+				ID:      "school",
+				Type:    pupildomain.PupilEnrolled,
+				Version: 2,
+				At:      time.Now(),
+				Data:    "{\"schoolId\":\"school\",\"pupilId\":\"ben\",\"firstName\":\"Ben\",\"lastName\":\"Huber\",\"classId\":\"other\"}",
+			},
+		}),
+		reservations: memory.NewMemoryStore(),
+	}
+}
+
+func (s stores) handlers() copyapp.CopyCommandHandlers {
+	return copyapp.NewCopyCommandHandlers(s.copies, nil, s.storages, nil, s.classes, nil, s.books, s.pupils, s.reservations)
+}
+
+func (s stores) load(t *testing.T) (*copydomain.SchoolCopyAggregate, *storagedomain.SchoolStorageAggregate, *classdomain.SchoolClassAggregate) {
+	ctx := context.Background()
+	copies := copydomain.NewSchoolCopyAggregateWithID("school")
+	assert.Nil(t, application.NewCommandHandlerModel(s.copies, nil).LoadAggregate(ctx, copies))
+	storages := storagedomain.NewSchoolStorageAggregateWithID("school")
+	assert.Nil(t, application.NewCommandHandlerModel(s.storages, nil).LoadAggregate(ctx, storages))
+	classes := classdomain.NewSchoolClassAggregateWithID("school")
+	assert.Nil(t, application.NewCommandHandlerModel(s.classes, nil).LoadAggregate(ctx, classes))
+	return copies, storages, classes
+}
+
+func registerCopies(t *testing.T, handlers copyapp.CopyCommandHandlers, quantity int) []string {
+	command := copyapp.RegisterCopiesCommand{BookID: "book", StorageID: "closet", Quantity: quantity}
+	command.ID = "school"
+	inventoryNumbers, err := handlers.RegisterCopiesHandler.Handle(context.Background(), command)
+	assert.Nil(t, err)
+	return inventoryNumbers
+}
+
+func moveCopy(handlers copyapp.CopyCommandHandlers, inventoryNumber string, to copydomain.Location) error {
+	command := copyapp.MoveCopyCommand{InventoryNumber: inventoryNumber, To: to}
+	command.ID = "school"
+	return handlers.MoveCopyHandler.Handle(context.Background(), command)
+}
+
+func TestConfigureNumbering(t *testing.T) {
+	stores := newStores()
+	registerCopies(t, stores.handlers(), 2)
+	command := copyapp.ConfigureNumberingCommand{Prefix: "INV", Digits: 6}
+	command.ID = "school"
+	assert.Nil(t, stores.handlers().ConfigureNumberingHandler.Handle(context.Background(), command))
+	copies, _, _ := stores.load(t)
+	assert.Equal(t, copydomain.NumberingScheme{Prefix: "INV", Digits: 6, Next: 3}, copies.Numbering)
+}
+
+func TestRegisterCopies(t *testing.T) {
+	stores := newStores()
+	handlers := stores.handlers()
+	assert.Equal(t, []string{"LMF-0001", "LMF-0002", "LMF-0003"}, registerCopies(t, handlers, 3))
+	copies, storages, _ := stores.load(t)
+	assert.Len(t, copies.Copies, 3)
+	assert.Equal(t, 3, storages.Storages[0].Quantity("book"))
+
+	command := copyapp.RegisterCopiesCommand{BookID: "unknown", StorageID: "closet", Quantity: 1}
+	command.ID = "school"
+	_, err := handlers.RegisterCopiesHandler.Handle(context.Background(), command)
+	assert.Equal(t, bookdomain.ErrBookWithIDNotFound("unknown"), err)
+
+	command = copyapp.RegisterCopiesCommand{BookID: "book", StorageID: "unknown", Quantity: 1}
+	command.ID = "school"
+	_, err = handlers.RegisterCopiesHandler.Handle(context.Background(), command)
+	assert.Equal(t, storagedomain.ErrStorageIDNotFound("unknown"), err)
+	copies, _, _ = stores.load(t)
+	assert.Len(t, copies.Copies, 3)
+}
+
+func TestMoveCopy(t *testing.T) {
+	stores := newStores()
+	handlers := stores.handlers()
+	inventoryNumber := registerCopies(t, handlers, 2)[0]
+	tests := []struct {
+		name          string
+		to            copydomain.Location
+		closet        int
+		cellar        int
+		classQuantity int
+		err           error
+	}{
+		{name: "transfer to another storage", to: cellar, closet: 1, cellar: 1},
+		{name: "hand out to a class", to: inClass, closet: 1, classQuantity: 1},
+		{name: "hand out to a pupil", to: anna, closet: 1, classQuantity: 1},
+		{
+			name:          "hand on to a pupil of another class",
+			to:            ben,
+			closet:        1,
+			classQuantity: 1,
+			err:           copydomain.ErrPupilNotInClass("ben", "class"),
+		},
+		{name: "give back to the class", to: inClass, closet: 1, classQuantity: 1},
+		{name: "take back from the class", to: closet, closet: 2},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := moveCopy(handlers, inventoryNumber, test.to)
+			assert.Equal(t, test.err, err)
+			copies, storages, classes := stores.load(t)
+			if test.err == nil {
+				assert.Equal(t, test.to, copies.Copies[0].Location)
+			}
+			assert.Equal(t, test.closet, storages.Storages[0].Quantity("book"))
+			assert.Equal(t, test.cellar, storages.Storages[1].Quantity("book"))
+			assert.Equal(t, test.classQuantity, classes.Classes[0].Quantity("book"))
+		})
+	}
+}
+
+func TestGradeCopy(t *testing.T) {
+	stores := newStores()
+	handlers := stores.handlers()
+	inventoryNumber := registerCopies(t, handlers, 2)[0]
+	grade := copyapp.GradeCopyCommand{InventoryNumber: inventoryNumber, Condition: storagedomain.Worn, Reason: "scribbled"}
+	grade.ID = "school"
+	assert.Nil(t, handlers.GradeCopyHandler.Handle(context.Background(), grade))
+	_, storages, _ := stores.load(t)
+	assert.Equal(t, 1, storages.Storages[0].InCondition("book", storagedomain.Good))
+	assert.Equal(t, 1, storages.Storages[0].InCondition("book", storagedomain.Worn))
+
+	grade.Condition = storagedomain.Damaged
+	assert.Nil(t, handlers.GradeCopyHandler.Handle(context.Background(), grade))
+	copies, storages, _ := stores.load(t)
+	assert.Equal(t, storagedomain.Damaged, copies.Copies[0].Condition)
+	assert.Equal(t, 0, storages.Storages[0].InCondition("book", storagedomain.Worn))
+	assert.Equal(t, 1, storages.Storages[0].InCondition("book", storagedomain.Damaged))
+
+	register := copyapp.RegisterCopiesCommand{BookID: "book", StorageID: "closet", Condition: storagedomain.Damaged, Quantity: 2}
+	register.ID = "school"
+	_, err := handlers.RegisterCopiesHandler.Handle(context.Background(), register)
+	assert.Nil(t, err)
+	_, storages, _ = stores.load(t)
+	assert.Equal(t, 3, storages.Storages[0].InCondition("book", storagedomain.Damaged))
+}
+
+func TestWriteOffCopy(t *testing.T) {
+	stores := newStores()
+	handlers := stores.handlers()
+	inventoryNumber := registerCopies(t, handlers, 2)[0]
+	command := copyapp.WriteOffCopyCommand{InventoryNumber: inventoryNumber, Reason: "torn"}
+	command.ID = "school"
+	assert.Equal(t, copydomain.ErrWriteOffUsableCopy, handlers.WriteOffCopyHandler.Handle(context.Background(), command))
+
+	grade := copyapp.GradeCopyCommand{InventoryNumber: inventoryNumber, Condition: storagedomain.Damaged, Reason: "torn"}
+	grade.ID = "school"
+	assert.Nil(t, handlers.GradeCopyHandler.Handle(context.Background(), grade))
+	assert.Nil(t, handlers.WriteOffCopyHandler.Handle(context.Background(), command))
+	copies, storages, _ := stores.load(t)
+	assert.True(t, copies.Copies[0].WrittenOff)
+	assert.Equal(t, 1, storages.Storages[0].Quantity("book"))
+	assert.Equal(t, 0, storages.Storages[0].InCondition("book", storagedomain.Damaged))
+	events, err := stores.storages.Load(context.Background(), "school")
+	assert.Nil(t, err)
+	assert.Equal(t, storagedomain.BooksWrittenOff, events[len(events)-1].EventType())
+}
@@ -0,0 +1,105 @@
+package copyapp
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/kammeph/school-book-storage-service/application"
+	"github.com/kammeph/school-book-storage-service/domain"
+	"github.com/kammeph/school-book-storage-service/domain/copydomain"
+)
+
+type CopyEventHandler struct {
+	repository CopyRepository
+}
+
+func NewCopyEventHandler(repository CopyRepository) application.EventHandler {
+	return &CopyEventHandler{repository}
+}
+
+func (h CopyEventHandler) Handle(ctx context.Context, eventBytes []byte) error {
+	event := &domain.EventModel{}
+	if err := json.Unmarshal(eventBytes, event); err != nil {
+		return err
+	}
+	switch event.EventType() {
+	case copydomain.CopyRegistered:
+		return h.handleCopyRegistered(ctx, event)
+	case copydomain.CopyMoved:
+		return h.handleCopyMoved(ctx, event)
+	case copydomain.CopyGraded:
+		return h.handleCopyGraded(ctx, event)
+	case copydomain.CopyWrittenOff:
+		return h.handleCopyWrittenOff(ctx, event)
+	default:
+		return nil
+	}
+}
+
+func (h CopyEventHandler) handleCopyRegistered(ctx context.Context, event domain.Event) error {
+	copyRegistered := copydomain.CopyRegisteredEvent{}
+	if err := event.GetJsonData(&copyRegistered); err != nil {
+		return err
+	}
+	bookCopy := copydomain.NewCopy(
+		copyRegistered.InventoryNumber,
+		copyRegistered.BookID,
+		copyRegistered.Isbn,
+		copyRegistered.Title,
+		copyRegistered.Location,
+		copyRegistered.Condition,
+		event.EventAt())
+	return h.repository.UpsertCopy(ctx, copydomain.NewCopyProjection(copyRegistered.SchoolID, bookCopy, event.EventVersion()))
+}
+
+func (h CopyEventHandler) handleCopyMoved(ctx context.Context, event domain.Event) error {
+	copyMoved := copydomain.CopyMovedEvent{}
+	if err := event.GetJsonData(&copyMoved); err != nil {
+		return err
+	}
+	return h.updateCopy(ctx, event, copyMoved.InventoryNumber, copyMoved.Reason, func(bookCopy *copydomain.CopyProjection) {
+		bookCopy.Location = copyMoved.To
+	})
+}
+
+func (h CopyEventHandler) handleCopyGraded(ctx context.Context, event domain.Event) error {
+	copyGraded := copydomain.CopyGradedEvent{}
+	if err := event.GetJsonData(&copyGraded); err != nil {
+		return err
+	}
+	return h.updateCopy(ctx, event, copyGraded.InventoryNumber, copyGraded.Reason, func(bookCopy *copydomain.CopyProjection) {
+		bookCopy.Condition = copyGraded.Condition
+	})
+}
+
+func (h CopyEventHandler) handleCopyWrittenOff(ctx context.Context, event domain.Event) error {
+	copyWrittenOff := copydomain.CopyWrittenOffEvent{}
+	if err := event.GetJsonData(&copyWrittenOff); err != nil {
+		return err
+	}
+	return h.updateCopy(ctx, event, copyWrittenOff.InventoryNumber, copyWrittenOff.Reason, func(bookCopy *copydomain.CopyProjection) {
+		bookCopy.WrittenOff = true
+	})
+}
+
+// updateCopy changes the stored copy and adds the event to its history. Events
+// the copy already reflects are skipped, so a redelivered event is not
+// recorded twice.
+func (h CopyEventHandler) updateCopy(
+	ctx context.Context,
+	event domain.Event,
+	inventoryNumber, reason string,
+	update func(bookCopy *copydomain.CopyProjection),
+) error {
+	bookCopy, err := h.repository.GetCopyByInventoryNumber(ctx, event.AggregateID(), inventoryNumber)
+	if err != nil {
+		return err
+	}
+	if bookCopy.Version >= event.EventVersion() {
+		return nil
+	}
+	bookCopy.History = append([]copydomain.CopyHistoryEntry{}, bookCopy.History...)
+	update(&bookCopy)
+	bookCopy.Record(event.EventAt(), event.EventType(), reason, event.EventVersion())
+	return h.repository.UpsertCopy(ctx, bookCopy)
+}
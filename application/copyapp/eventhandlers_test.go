@@ -0,0 +1,73 @@
+package copyapp_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/kammeph/school-book-storage-service/application/copyapp"
+	"github.com/kammeph/school-book-storage-service/domain"
+	"github.com/kammeph/school-book-storage-service/domain/copydomain"
+	"github.com/kammeph/school-book-storage-service/domain/storagedomain"
+	"github.com/kammeph/school-book-storage-service/infrastructure/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+func copyEvent(version int, eventType, data string) []byte {
+	eventBytes, _ := json.Marshal(domain.EventModel{
+		ID:      "school",
+		Type:    eventType,
+		Version: version,
+		At:      time.Now(),
+		Data:    data,
+	})
+	return eventBytes
+}
+
+func TestHandleCopyEvents(t *testing.T) {
+	ctx := context.Background()
+	repository := memory.NewMemoryCopyRepository()
+	handler := copyapp.NewCopyEventHandler(repository)
+	events := [][]byte{
+		copyEvent(1, copydomain.NumberingConfigured, "{\"prefix\":\"LMF-\",\"digits\":4,\"next\":1}"),
+		copyEvent(2, copydomain.CopyRegistered, "{\"schoolId\":\"school\",\"inventoryNumber\":\"LMF-0001\",\"sequence\":1,\"bookId\":\"book\",\"isbn\":\"9783127323207\",\"title\":\"Math\",\"location\":{\"kind\":\"storage\",\"id\":\"closet\"},\"condition\":\"good\"}"),
+		copyEvent(3, copydomain.CopyRegistered, "{\"schoolId\":\"school\",\"inventoryNumber\":\"LMF-0002\",\"sequence\":2,\"bookId\":\"book\",\"isbn\":\"9783127323207\",\"title\":\"Math\",\"location\":{\"kind\":\"storage\",\"id\":\"closet\"},\"condition\":\"good\"}"),
+		copyEvent(4, copydomain.CopyMoved, "{\"schoolId\":\"school\",\"inventoryNumber\":\"LMF-0001\",\"bookId\":\"book\",\"from\":{\"kind\":\"storage\",\"id\":\"closet\"},\"to\":{\"kind\":\"class\",\"id\":\"class\"}}"),
+		copyEvent(5, copydomain.CopyGraded, "{\"schoolId\":\"school\",\"inventoryNumber\":\"LMF-0002\",\"bookId\":\"book\",\"from\":\"good\",\"condition\":\"damaged\",\"reason\":\"torn\"}"),
+		copyEvent(6, copydomain.CopyWrittenOff, "{\"schoolId\":\"school\",\"inventoryNumber\":\"LMF-0002\",\"bookId\":\"book\",\"location\":{\"kind\":\"storage\",\"id\":\"closet\"},\"condition\":\"damaged\",\"reason\":\"torn\"}"),
+	}
+	for _, event := range events {
+		assert.Nil(t, handler.Handle(ctx, event))
+	}
+	// redelivered events must neither reset the copy nor repeat its history
+	assert.Nil(t, handler.Handle(ctx, events[1]))
+	assert.Nil(t, handler.Handle(ctx, events[3]))
+
+	moved, err := repository.GetCopyByInventoryNumber(ctx, "school", "LMF-0001")
+	assert.Nil(t, err)
+	assert.Equal(t, copydomain.Location{Kind: copydomain.InClass, ID: "class"}, moved.Location)
+	assert.Len(t, moved.History, 2)
+	assert.Equal(t, copydomain.CopyMoved, moved.History[1].EventType)
+
+	writtenOff, err := repository.GetCopyByInventoryNumber(ctx, "school", "LMF-0002")
+	assert.Nil(t, err)
+	assert.True(t, writtenOff.WrittenOff)
+	assert.Equal(t, storagedomain.Damaged, writtenOff.Condition)
+	assert.Len(t, writtenOff.History, 3)
+	assert.Equal(t, "torn", writtenOff.History[2].Reason)
+
+	inClass, err := copyapp.NewGetCopiesByLocationQueryHandler(repository).Handle(ctx, copyapp.NewGetCopiesByLocation("school", moved.Location))
+	assert.Nil(t, err)
+	assert.Len(t, inClass, 1)
+	inCloset, err := repository.GetCopiesByLocation(ctx, "school", copydomain.StorageLocation("closet"))
+	assert.Nil(t, err)
+	assert.Empty(t, inCloset)
+
+	labels, err := copyapp.NewGetCopyLabelsQueryHandler(repository).Handle(ctx, copyapp.NewGetCopyLabels("school", []string{"LMF-0001"}))
+	assert.Nil(t, err)
+	assert.Len(t, labels, 1)
+	assert.Equal(t, "LMF-0001", labels[0].Content)
+
+	assert.Error(t, handler.Handle(ctx, copyEvent(7, copydomain.CopyMoved, "{\"schoolId\":\"school\",\"inventoryNumber\":\"LMF-0003\"}")))
+}
@@ -0,0 +1,145 @@
+package copyapp
+
+import (
+	"context"
+
+	"github.com/kammeph/school-book-storage-service/application"
+	"github.com/kammeph/school-book-storage-service/application/labelapp"
+	"github.com/kammeph/school-book-storage-service/domain/copydomain"
+)
+
+type CopyQueryHandlers struct {
+	GetAllHandler               GetAllCopiesQueryHandler
+	GetByLocationHandler        GetCopiesByLocationQueryHandler
+	GetByBookIDHandler          GetCopiesByBookIDQueryHandler
+	GetByInventoryNumberHandler GetCopyByInventoryNumberQueryHandler
+	GetLabelsHandler            GetCopyLabelsQueryHandler
+}
+
+func NewCopyQueryHandlers(repository CopyRepository) CopyQueryHandlers {
+	return CopyQueryHandlers{
+		GetAllHandler:               NewGetAllCopiesQueryHandler(repository),
+		GetByLocationHandler:        NewGetCopiesByLocationQueryHandler(repository),
+		GetByBookIDHandler:          NewGetCopiesByBookIDQueryHandler(repository),
+		GetByInventoryNumberHandler: NewGetCopyByInventoryNumberQueryHandler(repository),
+		GetLabelsHandler:            NewGetCopyLabelsQueryHandler(repository),
+	}
+}
+
+type GetAllCopies struct {
+	application.QueryModel
+}
+
+func NewGetAllCopies(aggregateID string) GetAllCopies {
+	return GetAllCopies{QueryModel: application.QueryModel{ID: aggregateID}}
+}
+
+type GetAllCopiesQueryHandler struct {
+	repository CopyRepository
+}
+
+func NewGetAllCopiesQueryHandler(repository CopyRepository) GetAllCopiesQueryHandler {
+	return GetAllCopiesQueryHandler{repository: repository}
+}
+
+func (h GetAllCopiesQueryHandler) Handle(ctx context.Context, query GetAllCopies) ([]copydomain.CopyProjection, error) {
+	return h.repository.GetCopiesBySchoolID(ctx, query.AggregateID())
+}
+
+// GetCopiesByLocation asks for the copies a storage, a class or a pupil has
+// right now.
+type GetCopiesByLocation struct {
+	application.QueryModel
+	Location copydomain.Location
+}
+
+func NewGetCopiesByLocation(aggregateID string, location copydomain.Location) GetCopiesByLocation {
+	return GetCopiesByLocation{QueryModel: application.QueryModel{ID: aggregateID}, Location: location}
+}
+
+type GetCopiesByLocationQueryHandler struct {
+	repository CopyRepository
+}
+
+func NewGetCopiesByLocationQueryHandler(repository CopyRepository) GetCopiesByLocationQueryHandler {
+	return GetCopiesByLocationQueryHandler{repository: repository}
+}
+
+func (h GetCopiesByLocationQueryHandler) Handle(ctx context.Context, query GetCopiesByLocation) ([]copydomain.CopyProjection, error) {
+	if !query.Location.Valid() {
+		return nil, copydomain.ErrInvalidLocation
+	}
+	return h.repository.GetCopiesByLocation(ctx, query.AggregateID(), query.Location)
+}
+
+type GetCopiesByBookID struct {
+	application.QueryModel
+	BookID string
+}
+
+func NewGetCopiesByBookID(aggregateID, bookID string) GetCopiesByBookID {
+	return GetCopiesByBookID{QueryModel: application.QueryModel{ID: aggregateID}, BookID: bookID}
+}
+
+type GetCopiesByBookIDQueryHandler struct {
+	repository CopyRepository
+}
+
+func NewGetCopiesByBookIDQueryHandler(repository CopyRepository) GetCopiesByBookIDQueryHandler {
+	return GetCopiesByBookIDQueryHandler{repository: repository}
+}
+
+func (h GetCopiesByBookIDQueryHandler) Handle(ctx context.Context, query GetCopiesByBookID) ([]copydomain.CopyProjection, error) {
+	return h.repository.GetCopiesByBookID(ctx, query.AggregateID(), query.BookID)
+}
+
+type GetCopyByInventoryNumber struct {
+	application.QueryModel
+	InventoryNumber string
+}
+
+func NewGetCopyByInventoryNumber(aggregateID, inventoryNumber string) GetCopyByInventoryNumber {
+	return GetCopyByInventoryNumber{QueryModel: application.QueryModel{ID: aggregateID}, InventoryNumber: inventoryNumber}
+}
+
+type GetCopyByInventoryNumberQueryHandler struct {
+	repository CopyRepository
+}
+
+func NewGetCopyByInventoryNumberQueryHandler(repository CopyRepository) GetCopyByInventoryNumberQueryHandler {
+	return GetCopyByInventoryNumberQueryHandler{repository: repository}
+}
+
+func (h GetCopyByInventoryNumberQueryHandler) Handle(ctx context.Context, query GetCopyByInventoryNumber) (copydomain.CopyProjection, error) {
+	return h.repository.GetCopyByInventoryNumber(ctx, query.AggregateID(), query.InventoryNumber)
+}
+
+// GetCopyLabels selects the copies to print inventory labels for.
+type GetCopyLabels struct {
+	application.QueryModel
+	InventoryNumbers []string
+}
+
+func NewGetCopyLabels(aggregateID string, inventoryNumbers []string) GetCopyLabels {
+	return GetCopyLabels{QueryModel: application.QueryModel{ID: aggregateID}, InventoryNumbers: inventoryNumbers}
+}
+
+type GetCopyLabelsQueryHandler struct {
+	repository CopyRepository
+}
+
+func NewGetCopyLabelsQueryHandler(repository CopyRepository) GetCopyLabelsQueryHandler {
+	return GetCopyLabelsQueryHandler{repository: repository}
+}
+
+func (h GetCopyLabelsQueryHandler) Handle(ctx context.Context, query GetCopyLabels) ([]labelapp.Label, error) {
+	labels := []labelapp.Label{}
+	for _, inventoryNumber := range query.InventoryNumbers {
+		bookCopy, err := h.repository.GetCopyByInventoryNumber(ctx, query.AggregateID(), inventoryNumber)
+		if err != nil {
+			return nil, err
+		}
+		labels = append(labels, labelapp.NewInventoryLabel(bookCopy.InventoryNumber, bookCopy.Title))
+	}
+	return labels, nil
+}
@@ -0,0 +1,15 @@
+package copyapp
+
+import (
+	"context"
+
+	"github.com/kammeph/school-book-storage-service/domain/copydomain"
+)
+
+type CopyRepository interface {
+	GetCopiesBySchoolID(ctx context.Context, schoolID string) ([]copydomain.CopyProjection, error)
+	GetCopiesByLocation(ctx context.Context, schoolID string, location copydomain.Location) ([]copydomain.CopyProjection, error)
+	GetCopiesByBookID(ctx context.Context, schoolID, bookID string) ([]copydomain.CopyProjection, error)
+	GetCopyByInventoryNumber(ctx context.Context, schoolID, inventoryNumber string) (copydomain.CopyProjection, error)
+	UpsertCopy(ctx context.Context, bookCopy copydomain.CopyProjection) error
+}
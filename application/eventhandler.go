@@ -2,12 +2,85 @@ package application
 
 import (
 	"context"
+	"time"
+
+	"github.com/kammeph/school-book-storage-service/domain"
 )
 
 type EventHandler interface {
-	Handle(ctx context.Context, eventData []byte)
+	Handle(ctx context.Context, eventData []byte) error
 }
 
 type EventSubscriber interface {
-	Subscribe(exchange string, handler EventHandler) error
+	Subscribe(exchange string, handler EventHandler, policy FailurePolicy) error
+}
+
+type FailureMode string
+
+const (
+	// RetryOnFailure retries the handler and drops the event once all retries failed.
+	RetryOnFailure FailureMode = "RETRY"
+	// SkipOnFailure drops the event and continues with the next one.
+	SkipOnFailure FailureMode = "SKIP"
+	// StopOnFailure stops the subscription so no further events are handled. The
+	// failed event is parked in a dead letter queue where the broker supports it.
+	StopOnFailure FailureMode = "STOP"
+	// DeadLetterOnFailure retries the handler and parks the event in a dead letter
+	// queue once all retries failed.
+	DeadLetterOnFailure FailureMode = "DEAD_LETTER"
+)
+
+type FailurePolicy struct {
+	Mode       FailureMode
+	MaxRetries int
+	RetryDelay time.Duration
+}
+
+func NewRetryPolicy(maxRetries int, retryDelay time.Duration) FailurePolicy {
+	return FailurePolicy{Mode: RetryOnFailure, MaxRetries: maxRetries, RetryDelay: retryDelay}
+}
+
+func NewSkipPolicy() FailurePolicy {
+	return FailurePolicy{Mode: SkipOnFailure}
+}
+
+func NewStopPolicy() FailurePolicy {
+	return FailurePolicy{Mode: StopOnFailure}
+}
+
+func NewDeadLetterPolicy(maxRetries int, retryDelay time.Duration) FailurePolicy {
+	return FailurePolicy{Mode: DeadLetterOnFailure, MaxRetries: maxRetries, RetryDelay: retryDelay}
+}
+
+// Handle passes the event to the handler and retries it as often as the policy
+// allows. The error of the last attempt is returned, so the caller can decide
+// whether to skip, stop or dead-letter the event.
+func (p FailurePolicy) Handle(ctx context.Context, handler EventHandler, eventData []byte) error {
+	err := handler.Handle(ctx, eventData)
+	if err == nil || (p.Mode != RetryOnFailure && p.Mode != DeadLetterOnFailure) {
+		return err
+	}
+	for retry := 0; retry < p.MaxRetries; retry++ {
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(p.RetryDelay):
+		}
+		if err = handler.Handle(ctx, eventData); err == nil {
+			return nil
+		}
+	}
+	return err
+}
+
+// SchoolIDOf returns the school an event belongs to. Most aggregates are
+// identified by the ID of their school, others carry it in the event data.
+func SchoolIDOf(event domain.Event) string {
+	eventData := struct {
+		SchoolID string `json:"schoolId"`
+	}{}
+	if err := event.GetJsonData(&eventData); err == nil && eventData.SchoolID != "" {
+		return eventData.SchoolID
+	}
+	return event.AggregateID()
 }
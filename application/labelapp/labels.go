@@ -0,0 +1,236 @@
+package labelapp
+
+import (
+	"errors"
+	"fmt"
+	"image/color"
+	"sort"
+
+	"github.com/boombuler/barcode"
+	"github.com/boombuler/barcode/ean"
+	"github.com/boombuler/barcode/qr"
+	"github.com/kammeph/school-book-storage-service/domain"
+)
+
+var (
+	ErrNoLabels           = errors.New("no labels selected")
+	ErrUnknownSymbology   = errors.New("unknown symbology")
+	ErrUnknownSheetFormat = errors.New("unknown sheet format")
+	ErrUnknownOutput      = errors.New("unknown output format")
+)
+
+func ErrSheetFormatNotFound(name string) error {
+	return fmt.Errorf("%w %s", ErrUnknownSheetFormat, name)
+}
+
+func ErrOutputNotSupported(output string) error {
+	return fmt.Errorf("%w %s", ErrUnknownOutput, output)
+}
+
+type Symbology string
+
+const (
+	EAN13 Symbology = "ean13"
+	QR    Symbology = "qr"
+)
+
+// Label is the content of a single label: a code and the caption lines that
+// are printed next to or below it.
+type Label struct {
+	Symbology Symbology `json:"symbology"`
+	Content   string    `json:"content"`
+	Caption   []string  `json:"caption"`
+}
+
+func NewIsbnLabel(isbn domain.Isbn, title string) Label {
+	return Label{EAN13, isbn.Isbn13(), []string{title, "ISBN " + isbn.Hyphenated()}}
+}
+
+func NewStorageLabel(storageID, name, location string) Label {
+	return Label{QR, storageID, []string{name, location}}
+}
+
+func NewInventoryLabel(inventoryNumber, title string) Label {
+	return Label{QR, inventoryNumber, []string{inventoryNumber, title}}
+}
+
+func (l Label) encode() (barcode.Barcode, error) {
+	switch l.Symbology {
+	case EAN13:
+		return ean.Encode(l.Content)
+	case QR:
+		return qr.Encode(l.Content, qr.M, qr.Auto)
+	default:
+		return nil, fmt.Errorf("%w %s", ErrUnknownSymbology, l.Symbology)
+	}
+}
+
+// SheetFormat describes a label sheet. All lengths are in millimetres.
+type SheetFormat struct {
+	Name        string  `json:"name"`
+	PageWidth   float64 `json:"pageWidth"`
+	PageHeight  float64 `json:"pageHeight"`
+	Columns     int     `json:"columns"`
+	Rows        int     `json:"rows"`
+	LabelWidth  float64 `json:"labelWidth"`
+	LabelHeight float64 `json:"labelHeight"`
+	MarginLeft  float64 `json:"marginLeft"`
+	MarginTop   float64 `json:"marginTop"`
+	PitchX      float64 `json:"pitchX"`
+	PitchY      float64 `json:"pitchY"`
+}
+
+func (f SheetFormat) LabelsPerSheet() int {
+	return f.Columns * f.Rows
+}
+
+const DefaultSheetFormat = "avery-l7160"
+
+var sheetFormats = map[string]SheetFormat{
+	"avery-l7160": {"avery-l7160", 210, 297, 3, 7, 63.5, 38.1, 7.25, 15.15, 66.04, 38.1},
+	"avery-l7163": {"avery-l7163", 210, 297, 2, 7, 99.1, 38.1, 4.65, 15.15, 101.6, 38.1},
+	"avery-l7651": {"avery-l7651", 210, 297, 5, 13, 38.1, 21.2, 4.75, 10.7, 40.64, 21.2},
+	"avery-3475":  {"avery-3475", 210, 297, 3, 8, 70, 36, 0, 4.5, 70, 36},
+}
+
+func GetSheetFormat(name string) (SheetFormat, error) {
+	if name == "" {
+		name = DefaultSheetFormat
+	}
+	format, ok := sheetFormats[name]
+	if !ok {
+		return SheetFormat{}, ErrSheetFormatNotFound(name)
+	}
+	return format, nil
+}
+
+func SheetFormats() []SheetFormat {
+	formats := []SheetFormat{}
+	for _, format := range sheetFormats {
+		formats = append(formats, format)
+	}
+	sort.Slice(formats, func(i, j int) bool { return formats[i].Name < formats[j].Name })
+	return formats
+}
+
+type rect struct {
+	x, y, width, height float64
+}
+
+type text struct {
+	x, y, size float64
+	value      string
+}
+
+type page struct {
+	rects []rect
+	texts []text
+}
+
+const (
+	padding = 1.5
+	// EAN-13 requires a quiet zone of 11 modules on the left and 7 on the right.
+	eanQuietLeft  = 11
+	eanQuietRight = 7
+)
+
+// layout places the labels on as many sheets as needed and converts them into
+// filled rectangles and text lines that are drawn by the renderers.
+func layout(format SheetFormat, labels []Label) ([]page, error) {
+	if len(labels) == 0 {
+		return nil, ErrNoLabels
+	}
+	pages := []page{}
+	for idx, label := range labels {
+		position := idx % format.LabelsPerSheet()
+		if position == 0 {
+			pages = append(pages, page{})
+		}
+		x := format.MarginLeft + float64(position%format.Columns)*format.PitchX
+		y := format.MarginTop + float64(position/format.Columns)*format.PitchY
+		if err := pages[len(pages)-1].draw(label, x, y, format.LabelWidth, format.LabelHeight); err != nil {
+			return nil, err
+		}
+	}
+	return pages, nil
+}
+
+func (p *page) draw(label Label, x, y, width, height float64) error {
+	code, err := label.encode()
+	if err != nil {
+		return err
+	}
+	fontSize := height / 8
+	if fontSize > 3 {
+		fontSize = 3
+	}
+	lineHeight := fontSize * 1.25
+	if code.Metadata().Dimensions == 1 {
+		modules := float64(code.Bounds().Dx() + eanQuietLeft + eanQuietRight)
+		module := (width - 2*padding) / modules
+		barsX := x + padding + eanQuietLeft*module
+		barHeight := height - 2*padding - float64(len(label.Caption))*lineHeight
+		p.drawModules(code, barsX, y+padding, module, barHeight)
+		p.drawCaption(label.Caption, barsX, y+padding+barHeight, width-2*padding, fontSize, lineHeight)
+		return nil
+	}
+	side := height - 2*padding
+	if side > width/2 {
+		side = width / 2
+	}
+	module := side / float64(code.Bounds().Dx())
+	p.drawModules(code, x+padding, y+padding, module, module)
+	captionX := x + 2*padding + side
+	p.drawCaption(label.Caption, captionX, y+padding, x+width-padding-captionX, fontSize, lineHeight)
+	return nil
+}
+
+// drawModules adds a rectangle for every horizontal run of dark modules.
+func (p *page) drawModules(code barcode.Barcode, x, y, moduleWidth, moduleHeight float64) {
+	bounds := code.Bounds()
+	for row := bounds.Min.Y; row < bounds.Max.Y; row++ {
+		start := -1
+		for col := bounds.Min.X; col <= bounds.Max.X; col++ {
+			dark := col < bounds.Max.X && isDark(code.At(col, row))
+			if dark && start < 0 {
+				start = col
+			}
+			if !dark && start >= 0 {
+				p.rects = append(p.rects, rect{
+					x + float64(start-bounds.Min.X)*moduleWidth,
+					y + float64(row-bounds.Min.Y)*moduleHeight,
+					float64(col-start) * moduleWidth,
+					moduleHeight,
+				})
+				start = -1
+			}
+		}
+	}
+}
+
+func (p *page) drawCaption(lines []string, x, y, width, fontSize, lineHeight float64) {
+	for idx, line := range lines {
+		if line == "" {
+			continue
+		}
+		p.texts = append(p.texts, text{x, y + float64(idx+1)*lineHeight, fontSize, fit(line, width, fontSize)})
+	}
+}
+
+func isDark(c color.Color) bool {
+	return color.GrayModel.Convert(c).(color.Gray).Y < 128
+}
+
+// fit shortens a line that would not fit into the width, assuming an average
+// character width of half the font size.
+func fit(line string, width, fontSize float64) string {
+	maxChars := int(width / (fontSize / 2))
+	runes := []rune(line)
+	if len(runes) <= maxChars {
+		return line
+	}
+	if maxChars <= 3 {
+		return string(runes[:maxChars])
+	}
+	return string(runes[:maxChars-3]) + "..."
+}
@@ -0,0 +1,89 @@
+package labelapp_test
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/kammeph/school-book-storage-service/application/labelapp"
+	"github.com/stretchr/testify/assert"
+)
+
+func storageLabels(count int) []labelapp.Label {
+	labels := []labelapp.Label{}
+	for i := 0; i < count; i++ {
+		labels = append(labels, labelapp.NewStorageLabel("storage", "Closet 1 & 2", "Room 101"))
+	}
+	return labels
+}
+
+func TestGetSheetFormat(t *testing.T) {
+	format, err := labelapp.GetSheetFormat("")
+	assert.NoError(t, err)
+	assert.Equal(t, labelapp.DefaultSheetFormat, format.Name)
+	assert.Equal(t, 21, format.LabelsPerSheet())
+
+	format, err = labelapp.GetSheetFormat("avery-l7651")
+	assert.NoError(t, err)
+	assert.Equal(t, 65, format.LabelsPerSheet())
+
+	_, err = labelapp.GetSheetFormat("unknown")
+	assert.True(t, errors.Is(err, labelapp.ErrUnknownSheetFormat))
+
+	for _, format := range labelapp.SheetFormats() {
+		right := format.MarginLeft + float64(format.Columns-1)*format.PitchX + format.LabelWidth
+		bottom := format.MarginTop + float64(format.Rows-1)*format.PitchY + format.LabelHeight
+		assert.LessOrEqual(t, right, format.PageWidth, format.Name)
+		assert.LessOrEqual(t, bottom, format.PageHeight, format.Name)
+	}
+}
+
+func TestWriteSVG(t *testing.T) {
+	format, _ := labelapp.GetSheetFormat("avery-l7160")
+	var buffer bytes.Buffer
+
+	err := labelapp.WriteSVG(&buffer, format, storageLabels(22))
+	assert.NoError(t, err)
+	svg := buffer.String()
+	assert.True(t, strings.HasPrefix(svg, "<svg xmlns=\"http://www.w3.org/2000/svg\" width=\"210mm\" height=\"594mm\""))
+	assert.Equal(t, 2, strings.Count(svg, "<g transform"))
+	assert.Equal(t, 22, strings.Count(svg, ">Closet 1 &amp; 2</text>"))
+}
+
+func TestWriteEAN13(t *testing.T) {
+	format, _ := labelapp.GetSheetFormat("avery-l7160")
+	var buffer bytes.Buffer
+
+	err := labelapp.WriteSVG(&buffer, format, []labelapp.Label{labelapp.NewIsbnLabel("9783127323207", "Green Line 1")})
+	assert.NoError(t, err)
+	svg := buffer.String()
+	// 1 page background, 3 guard patterns with 2 bars each and 2 bars per digit
+	assert.Equal(t, 1+6+12*2, strings.Count(svg, "<rect"))
+	assert.Contains(t, svg, ">ISBN 978-3-12-732320-7</text>")
+
+	err = labelapp.WriteSVG(&buffer, format, []labelapp.Label{{Symbology: labelapp.EAN13, Content: "9783127323208"}})
+	assert.Error(t, err)
+}
+
+func TestWritePDF(t *testing.T) {
+	format, _ := labelapp.GetSheetFormat("avery-l7163")
+	labels := append(storageLabels(14), labelapp.NewInventoryLabel("B-000001", "Grüne Linie 1"))
+	var buffer bytes.Buffer
+
+	err := labelapp.WritePDF(&buffer, format, labels)
+	assert.NoError(t, err)
+	assert.True(t, bytes.HasPrefix(buffer.Bytes(), []byte("%PDF-")))
+	assert.Contains(t, buffer.String(), "/Count 2")
+}
+
+func TestWriteLabels(t *testing.T) {
+	format, _ := labelapp.GetSheetFormat("")
+	var buffer bytes.Buffer
+
+	assert.Equal(t, labelapp.ErrNoLabels, labelapp.WriteLabels(&buffer, labelapp.PDF, format, []labelapp.Label{}))
+	err := labelapp.WriteLabels(&buffer, "png", format, storageLabels(1))
+	assert.True(t, errors.Is(err, labelapp.ErrUnknownOutput))
+	err = labelapp.WriteLabels(&buffer, labelapp.SVG, format, []labelapp.Label{{Symbology: "code128", Content: "x"}})
+	assert.True(t, errors.Is(err, labelapp.ErrUnknownSymbology))
+}
@@ -0,0 +1,104 @@
+package labelapp
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/jung-kurt/gofpdf"
+)
+
+const (
+	PDF = "pdf"
+	SVG = "svg"
+)
+
+// ContentType returns the media type of an output format.
+func ContentType(output string) (string, error) {
+	switch output {
+	case PDF:
+		return "application/pdf", nil
+	case SVG:
+		return "image/svg+xml", nil
+	default:
+		return "", ErrOutputNotSupported(output)
+	}
+}
+
+// WriteLabels renders the labels on sheets of the given format as PDF or SVG.
+func WriteLabels(w io.Writer, output string, format SheetFormat, labels []Label) error {
+	switch output {
+	case PDF:
+		return WritePDF(w, format, labels)
+	case SVG:
+		return WriteSVG(w, format, labels)
+	default:
+		return ErrOutputNotSupported(output)
+	}
+}
+
+// WritePDF renders one page per sheet.
+func WritePDF(w io.Writer, format SheetFormat, labels []Label) error {
+	pages, err := layout(format, labels)
+	if err != nil {
+		return err
+	}
+	pdf := gofpdf.NewCustom(&gofpdf.InitType{
+		OrientationStr: "P",
+		UnitStr:        "mm",
+		Size:           gofpdf.SizeType{Wd: format.PageWidth, Ht: format.PageHeight},
+	})
+	pdf.SetMargins(0, 0, 0)
+	pdf.SetAutoPageBreak(false, 0)
+	pdf.SetFillColor(0, 0, 0)
+	pdf.SetFont("Helvetica", "", 8)
+	translate := pdf.UnicodeTranslatorFromDescriptor("")
+	for _, page := range pages {
+		pdf.AddPage()
+		for _, r := range page.rects {
+			pdf.Rect(r.x, r.y, r.width, r.height, "F")
+		}
+		for _, t := range page.texts {
+			pdf.SetFontUnitSize(t.size)
+			pdf.Text(t.x, t.y, translate(t.value))
+		}
+	}
+	return pdf.Output(w)
+}
+
+// WriteSVG renders all sheets into a single SVG document with the sheets
+// stacked on top of each other.
+func WriteSVG(w io.Writer, format SheetFormat, labels []Label) error {
+	pages, err := layout(format, labels)
+	if err != nil {
+		return err
+	}
+	height := format.PageHeight * float64(len(pages))
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "<svg xmlns=\"http://www.w3.org/2000/svg\" width=\"%smm\" height=\"%smm\" viewBox=\"0 0 %s %s\">\n",
+		number(format.PageWidth), number(height), number(format.PageWidth), number(height))
+	for idx, page := range pages {
+		fmt.Fprintf(&sb, "<g transform=\"translate(0 %s)\">\n", number(float64(idx)*format.PageHeight))
+		fmt.Fprintf(&sb, "<rect width=\"%s\" height=\"%s\" fill=\"white\"/>\n", number(format.PageWidth), number(format.PageHeight))
+		for _, r := range page.rects {
+			fmt.Fprintf(&sb, "<rect x=\"%s\" y=\"%s\" width=\"%s\" height=\"%s\"/>\n",
+				number(r.x), number(r.y), number(r.width), number(r.height))
+		}
+		for _, t := range page.texts {
+			fmt.Fprintf(&sb, "<text x=\"%s\" y=\"%s\" font-family=\"Helvetica, Arial, sans-serif\" font-size=\"%s\">",
+				number(t.x), number(t.y), number(t.size))
+			xml.EscapeText(&sb, []byte(t.value))
+			sb.WriteString("</text>\n")
+		}
+		sb.WriteString("</g>\n")
+	}
+	sb.WriteString("</svg>\n")
+	_, err = io.WriteString(w, sb.String())
+	return err
+}
+
+func number(value float64) string {
+	formatted := strings.TrimRight(fmt.Sprintf("%.3f", value), "0")
+	return strings.TrimSuffix(formatted, ".")
+}
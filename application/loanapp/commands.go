@@ -0,0 +1,270 @@
+package loanapp
+
+import (
+	"context"
+	"time"
+
+	"github.com/kammeph/school-book-storage-service/application"
+	"github.com/kammeph/school-book-storage-service/domain/bookdomain"
+	"github.com/kammeph/school-book-storage-service/domain/classdomain"
+	"github.com/kammeph/school-book-storage-service/domain/copydomain"
+	"github.com/kammeph/school-book-storage-service/domain/loandomain"
+	"github.com/kammeph/school-book-storage-service/domain/pupildomain"
+	"github.com/kammeph/school-book-storage-service/fp"
+)
+
+type LoanCommandHandlers struct {
+	IssueLoanHandler  IssueLoanCommandHandler
+	ExtendLoanHandler ExtendLoanCommandHandler
+	ReturnLoanHandler ReturnLoanCommandHandler
+}
+
+func NewLoanCommandHandlers(
+	store application.Store,
+	publisher application.EventPublisher,
+	classStore application.Store,
+	bookStore application.Store,
+	pupilStore application.Store,
+	copyStore application.Store,
+	copyPublisher application.EventPublisher,
+) LoanCommandHandlers {
+	return LoanCommandHandlers{
+		IssueLoanHandler:  NewIssueLoanCommandHandler(store, publisher, classStore, bookStore, pupilStore, copyStore, copyPublisher),
+		ExtendLoanHandler: NewExtendLoanCommandHandler(store, publisher),
+		ReturnLoanHandler: NewReturnLoanCommandHandler(store, publisher, copyStore, copyPublisher),
+	}
+}
+
+// LoanReason is the reason recorded on copies that are lent to a pupil.
+const LoanReason = "lent to pupil"
+
+// ReturnReason is the reason recorded on copies a pupil gives back to the
+// class.
+const ReturnReason = "loan returned"
+
+type IssueLoanCommand struct {
+	application.CommandModel
+	PupilID         string    `json:"pupilId"`
+	ClassID         string    `json:"classId"`
+	BookID          string    `json:"bookId"`
+	InventoryNumber string    `json:"inventoryNumber"`
+	IssuedAt        time.Time `json:"issuedAt"`
+	DueDate         time.Time `json:"dueDate"`
+}
+
+// IssueLoanCommandHandler issues loans. In schools that keep a copy inventory
+// the lent copy moves from the class to the pupil. The copies are saved first
+// as they guard where a copy is.
+type IssueLoanCommandHandler struct {
+	*application.CommandHandlerModel
+	classes *application.CommandHandlerModel
+	books   *application.CommandHandlerModel
+	pupils  *application.CommandHandlerModel
+	copies  *application.CommandHandlerModel
+}
+
+func NewIssueLoanCommandHandler(
+	store application.Store,
+	publisher application.EventPublisher,
+	classStore application.Store,
+	bookStore application.Store,
+	pupilStore application.Store,
+	copyStore application.Store,
+	copyPublisher application.EventPublisher,
+) IssueLoanCommandHandler {
+	return IssueLoanCommandHandler{
+		CommandHandlerModel: application.NewCommandHandlerModel(store, publisher),
+		classes:             application.NewCommandHandlerModel(classStore, nil),
+		books:               application.NewCommandHandlerModel(bookStore, nil),
+		pupils:              application.NewCommandHandlerModel(pupilStore, nil),
+		copies:              application.NewCommandHandlerModel(copyStore, copyPublisher),
+	}
+}
+
+// Handle issues the loan after checking that the class and the book exist in
+// the school and that the pupil is in the class. A missing issue date defaults
+// to now.
+func (h IssueLoanCommandHandler) Handle(ctx context.Context, command IssueLoanCommand) (string, error) {
+	classes := classdomain.NewSchoolClassAggregateWithID(command.AggregateID())
+	if err := h.classes.LoadAggregate(ctx, classes); err != nil {
+		return "", err
+	}
+	if !fp.Some(classes.Classes, func(c classdomain.Class) bool { return c.ID == command.ClassID }) {
+		return "", classdomain.ErrClassWithIDNotFound(command.ClassID)
+	}
+	books := bookdomain.NewSchoolBookAggregateWithID(command.AggregateID())
+	if err := h.books.LoadAggregate(ctx, books); err != nil {
+		return "", err
+	}
+	if !fp.Some(books.Books, func(b bookdomain.Book) bool { return b.ID == command.BookID }) {
+		return "", bookdomain.ErrBookWithIDNotFound(command.BookID)
+	}
+	if err := h.checkPupil(ctx, command.AggregateID(), command.PupilID, command.ClassID); err != nil {
+		return "", err
+	}
+	copies := copydomain.NewSchoolCopyAggregateWithID(command.AggregateID())
+	if err := h.copies.LoadAggregate(ctx, copies); err != nil {
+		return "", err
+	}
+	if err := checkCopy(copies, command.InventoryNumber, command.BookID, command.ClassID); err != nil {
+		return "", err
+	}
+	aggregate := loandomain.NewSchoolLoanAggregateWithID(command.AggregateID())
+	if err := h.LoadAggregate(ctx, aggregate); err != nil {
+		return "", err
+	}
+	issuedAt := command.IssuedAt
+	if issuedAt.IsZero() {
+		issuedAt = time.Now()
+	}
+	loanID, err := aggregate.IssueLoan(
+		command.PupilID,
+		command.ClassID,
+		command.BookID,
+		command.InventoryNumber,
+		issuedAt,
+		command.DueDate)
+	if err != nil {
+		return "", err
+	}
+	if copies.Numbering.Enabled() {
+		pupil := copydomain.Location{Kind: copydomain.WithPupil, ID: command.PupilID}
+		if _, err := copies.MoveCopy(command.InventoryNumber, pupil, LoanReason); err != nil {
+			return "", err
+		}
+	}
+	if err := h.copies.SaveAndPublish(ctx, copies); err != nil {
+		return "", err
+	}
+	if err := h.SaveAndPublish(ctx, aggregate); err != nil {
+		return "", err
+	}
+	return loanID, nil
+}
+
+func (h IssueLoanCommandHandler) checkPupil(ctx context.Context, schoolID, pupilID, classID string) error {
+	pupils := pupildomain.NewSchoolPupilAggregateWithID(schoolID)
+	if err := h.pupils.LoadAggregate(ctx, pupils); err != nil {
+		return err
+	}
+	pupil := fp.Find(pupils.Pupils, func(p pupildomain.Pupil) bool { return p.ID == pupilID })
+	if pupil == nil {
+		return pupildomain.ErrPupilWithIDNotFound(pupilID)
+	}
+	if pupil.Left() {
+		return pupildomain.ErrPupilAlreadyLeft(pupilID)
+	}
+	if pupil.ClassID != classID {
+		return loandomain.ErrPupilNotInClass(pupilID, classID)
+	}
+	return nil
+}
+
+// checkCopy makes sure a school that keeps a copy inventory lends one of its
+// copies of the book the class holds. Other schools write down the number
+// found in the book.
+func checkCopy(copies *copydomain.SchoolCopyAggregate, inventoryNumber, bookID, classID string) error {
+	if !copies.Numbering.Enabled() {
+		return nil
+	}
+	bookCopy := fp.Find(copies.Copies, func(c copydomain.Copy) bool { return c.InventoryNumber == inventoryNumber })
+	if bookCopy == nil {
+		return copydomain.ErrCopyNotFound(inventoryNumber)
+	}
+	if bookCopy.WrittenOff {
+		return copydomain.ErrCopyWrittenOff(inventoryNumber)
+	}
+	if bookCopy.BookID != bookID {
+		return loandomain.ErrCopyOfOtherBook(inventoryNumber, bookID)
+	}
+	if bookCopy.Location != (copydomain.Location{Kind: copydomain.InClass, ID: classID}) {
+		return loandomain.ErrCopyNotInClass(inventoryNumber, classID)
+	}
+	return nil
+}
+
+type ExtendLoanCommand struct {
+	application.CommandModel
+	LoanID  string    `json:"loanId"`
+	DueDate time.Time `json:"dueDate"`
+	Reason  string    `json:"reason"`
+}
+
+type ExtendLoanCommandHandler struct {
+	*application.CommandHandlerModel
+}
+
+func NewExtendLoanCommandHandler(store application.Store, publisher application.EventPublisher) ExtendLoanCommandHandler {
+	return ExtendLoanCommandHandler{application.NewCommandHandlerModel(store, publisher)}
+}
+
+func (h ExtendLoanCommandHandler) Handle(ctx context.Context, command ExtendLoanCommand) error {
+	aggregate := loandomain.NewSchoolLoanAggregateWithID(command.AggregateID())
+	if err := h.LoadAggregate(ctx, aggregate); err != nil {
+		return err
+	}
+	if err := aggregate.ExtendLoan(command.LoanID, command.DueDate, command.Reason); err != nil {
+		return err
+	}
+	return h.SaveAndPublish(ctx, aggregate)
+}
+
+type ReturnLoanCommand struct {
+	application.CommandModel
+	LoanID     string    `json:"loanId"`
+	ReturnedAt time.Time `json:"returnedAt"`
+}
+
+// ReturnLoanCommandHandler returns loans. In schools that keep a copy
+// inventory the copy moves from the pupil back to the class. The copies are
+// saved first as they guard where a copy is.
+type ReturnLoanCommandHandler struct {
+	*application.CommandHandlerModel
+	copies *application.CommandHandlerModel
+}
+
+func NewReturnLoanCommandHandler(
+	store application.Store,
+	publisher application.EventPublisher,
+	copyStore application.Store,
+	copyPublisher application.EventPublisher,
+) ReturnLoanCommandHandler {
+	return ReturnLoanCommandHandler{
+		CommandHandlerModel: application.NewCommandHandlerModel(store, publisher),
+		copies:              application.NewCommandHandlerModel(copyStore, copyPublisher),
+	}
+}
+
+// Handle returns the loan. A missing return date defaults to now. A copy that
+// is no longer with the pupil, for example because the class already took it
+// back, stays where it is.
+func (h ReturnLoanCommandHandler) Handle(ctx context.Context, command ReturnLoanCommand) error {
+	aggregate := loandomain.NewSchoolLoanAggregateWithID(command.AggregateID())
+	if err := h.LoadAggregate(ctx, aggregate); err != nil {
+		return err
+	}
+	copies := copydomain.NewSchoolCopyAggregateWithID(command.AggregateID())
+	if err := h.copies.LoadAggregate(ctx, copies); err != nil {
+		return err
+	}
+	returnedAt := command.ReturnedAt
+	if returnedAt.IsZero() {
+		returnedAt = time.Now()
+	}
+	if err := aggregate.ReturnLoan(command.LoanID, returnedAt); err != nil {
+		return err
+	}
+	loan := fp.Find(aggregate.Loans, func(l loandomain.Loan) bool { return l.ID == command.LoanID })
+	bookCopy := fp.Find(copies.Copies, func(c copydomain.Copy) bool { return c.InventoryNumber == loan.InventoryNumber })
+	if copies.Numbering.Enabled() && bookCopy != nil && !bookCopy.WrittenOff &&
+		bookCopy.Location == (copydomain.Location{Kind: copydomain.WithPupil, ID: loan.PupilID}) {
+		class := copydomain.Location{Kind: copydomain.InClass, ID: bookCopy.ClassID}
+		if _, err := copies.MoveCopy(loan.InventoryNumber, class, ReturnReason); err != nil {
+			return err
+		}
+	}
+	if err := h.copies.SaveAndPublish(ctx, copies); err != nil {
+		return err
+	}
+	return h.SaveAndPublish(ctx, aggregate)
+}
@@ -0,0 +1,221 @@
+package loanapp_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/kammeph/school-book-storage-service/application"
+	"github.com/kammeph/school-book-storage-service/application/loanapp"
+	"github.com/kammeph/school-book-storage-service/domain"
+	"github.com/kammeph/school-book-storage-service/domain/bookdomain"
+	"github.com/kammeph/school-book-storage-service/domain/classdomain"
+	"github.com/kammeph/school-book-storage-service/domain/copydomain"
+	"github.com/kammeph/school-book-storage-service/domain/loandomain"
+	"github.com/kammeph/school-book-storage-service/domain/pupildomain"
+	"github.com/kammeph/school-book-storage-service/infrastructure/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	issuedAt = time.Date(2022, 9, 1, 0, 0, 0, 0, time.UTC)
+	dueDate  = time.Date(2023, 7, 15, 0, 0, 0, 0, time.UTC)
+)
+
+func copyEvent(version int, eventType, data string) domain.Event {
+	return &domain.EventModel{ID: "school", Type: eventType, Version: version, At: time.Now(), Data: data}
+}
+
+var copyInventory = []domain.Event{
+	copyEvent(1, copydomain.NumberingConfigured, "{\"prefix\":\"LMF-\",\"digits\":4,\"next\":1}"),
+	copyEvent(2, copydomain.CopyRegistered, "{\"schoolId\":\"school\",\"inventoryNumber\":\"LMF-0001\",\"sequence\":1,\"bookId\":\"book\",\"isbn\":\"123\",\"title\":\"math\",\"location\":{\"kind\":\"storage\",\"id\":\"closet\"},\"condition\":\"good\"}"),
+	copyEvent(3, copydomain.CopyRegistered, "{\"schoolId\":\"school\",\"inventoryNumber\":\"LMF-0002\",\"sequence\":2,\"bookId\":\"other\",\"isbn\":\"456\",\"title\":\"english\",\"location\":{\"kind\":\"storage\",\"id\":\"closet\"},\"condition\":\"good\"}"),
+	copyEvent(4, copydomain.CopyRegistered, "{\"schoolId\":\"school\",\"inventoryNumber\":\"LMF-0003\",\"sequence\":3,\"bookId\":\"book\",\"isbn\":\"123\",\"title\":\"math\",\"location\":{\"kind\":\"storage\",\"id\":\"closet\"},\"condition\":\"good\"}"),
+	copyEvent(5, copydomain.CopyMoved, "{\"schoolId\":\"school\",\"inventoryNumber\":\"LMF-0001\",\"bookId\":\"book\",\"from\":{\"kind\":\"storage\",\"id\":\"closet\"},\"to\":{\"kind\":\"class\",\"id\":\"class\"}}"),
+	copyEvent(6, copydomain.CopyMoved, "{\"schoolId\":\"school\",\"inventoryNumber\":\"LMF-0002\",\"bookId\":\"other\",\"from\":{\"kind\":\"storage\",\"id\":\"closet\"},\"to\":{\"kind\":\"class\",\"id\":\"class\"}}"),
+}
+
+func newLoanCommandHandlers() (loanapp.LoanCommandHandlers, *memory.MemoryStore) {
+	return newLoanCommandHandlersWithCopies(memory.NewMemoryStoreWithEvents(copyInventory))
+}
+
+func newLoanCommandHandlersWithCopies(copyStore application.Store) (loanapp.LoanCommandHandlers, *memory.MemoryStore) {
+	classStore := memory.NewMemoryStoreWithEvents([]domain.Event{
+		&domain.EventModel{
+			ID:      "school",
+			Type:    classdomain.ClassCreated,
+			Version: 1,
+			At:      time.Now(),
+			Data:    "{\"schoolId\":\"school\",\"classId\":\"class\",\"grade\":5,\"letter\":\"a\",\"numberOfPupils\":25}",
+		},
+	})
+	bookStore := memory.NewMemoryStoreWithEvents([]domain.Event{
+		&domain.EventModel{
+			ID:      "school",
+			Type:    bookdomain.BookAdded,
+			Version: 1,
+			At:      time.Now(),
+			Data:    "{\"SchoolID\":\"school\",\"BookID\":\"book\",\"Isbn\":\"123\",\"Name\":\"math\"}",
+		},
+	})
+	pupilStore := memory.NewMemoryStoreWithEvents([]domain.Event{
+		&domain.EventModel{
+			ID:      "school",
+			Type:    pupildomain.PupilEnrolled,
+			Version: 1,
+			At:      time.Now(),
+			Data:    "{\"schoolId\":\"school\",\"pupilId\":\"pupil\",\"firstName\":\"Anna\",\"lastName\":\"Meier\",\"classId\":\"class\"}",
+		},
+		&domain.EventModel{
+			ID:      "school",
+			Type:    pupildomain.PupilEnrolled,
+			Version: 2,
+			At:      time.Now(),
+			Data:    "{\"schoolId\":\"school\",\"pupilId\":\"other\",\"firstName\":\"Ben\",\"lastName\":\"Huber\",\"classId\":\"other\"}",
+		},
+	})
+	store := memory.NewMemoryStore()
+	return loanapp.NewLoanCommandHandlers(store, nil, classStore, bookStore, pupilStore, copyStore, nil), store
+}
+
+func loadLoans(t *testing.T, store application.Store) *loandomain.SchoolLoanAggregate {
+	aggregate := loandomain.NewSchoolLoanAggregateWithID("school")
+	assert.Nil(t, application.NewCommandHandlerModel(store, nil).LoadAggregate(context.Background(), aggregate))
+	return aggregate
+}
+
+func TestIssueLoan(t *testing.T) {
+	tests := []struct {
+		name        string
+		command     loanapp.IssueLoanCommand
+		expectError bool
+	}{
+		{
+			name:    "issue loan",
+			command: loanapp.IssueLoanCommand{PupilID: "pupil", ClassID: "class", BookID: "book", InventoryNumber: "LMF-0001", IssuedAt: issuedAt, DueDate: dueDate},
+		},
+		{
+			name:        "class not found",
+			command:     loanapp.IssueLoanCommand{PupilID: "pupil", ClassID: "unknown", BookID: "book", InventoryNumber: "LMF-0001", IssuedAt: issuedAt, DueDate: dueDate},
+			expectError: true,
+		},
+		{
+			name:        "book not found",
+			command:     loanapp.IssueLoanCommand{PupilID: "pupil", ClassID: "class", BookID: "unknown", InventoryNumber: "LMF-0001", IssuedAt: issuedAt, DueDate: dueDate},
+			expectError: true,
+		},
+		{
+			name:        "due date before issue date",
+			command:     loanapp.IssueLoanCommand{PupilID: "pupil", ClassID: "class", BookID: "book", InventoryNumber: "LMF-0001", IssuedAt: dueDate, DueDate: issuedAt},
+			expectError: true,
+		},
+		{
+			name:        "pupil not found",
+			command:     loanapp.IssueLoanCommand{PupilID: "unknown", ClassID: "class", BookID: "book", InventoryNumber: "LMF-0001", IssuedAt: issuedAt, DueDate: dueDate},
+			expectError: true,
+		},
+		{
+			name:        "pupil of another class",
+			command:     loanapp.IssueLoanCommand{PupilID: "other", ClassID: "class", BookID: "book", InventoryNumber: "LMF-0001", IssuedAt: issuedAt, DueDate: dueDate},
+			expectError: true,
+		},
+		{
+			name:        "copy not in inventory",
+			command:     loanapp.IssueLoanCommand{PupilID: "pupil", ClassID: "class", BookID: "book", InventoryNumber: "LMF-0099", IssuedAt: issuedAt, DueDate: dueDate},
+			expectError: true,
+		},
+		{
+			name:        "copy not in the class",
+			command:     loanapp.IssueLoanCommand{PupilID: "pupil", ClassID: "class", BookID: "book", InventoryNumber: "LMF-0003", IssuedAt: issuedAt, DueDate: dueDate},
+			expectError: true,
+		},
+		{
+			name:        "copy of another book",
+			command:     loanapp.IssueLoanCommand{PupilID: "pupil", ClassID: "class", BookID: "book", InventoryNumber: "LMF-0002", IssuedAt: issuedAt, DueDate: dueDate},
+			expectError: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			handlers, store := newLoanCommandHandlers()
+			test.command.ID = "school"
+			loanID, err := handlers.IssueLoanHandler.Handle(context.Background(), test.command)
+			aggregate := loadLoans(t, store)
+			if test.expectError {
+				assert.Error(t, err)
+				assert.Empty(t, aggregate.Loans)
+				return
+			}
+			assert.Nil(t, err)
+			assert.Len(t, aggregate.Loans, 1)
+			assert.Equal(t, loanID, aggregate.Loans[0].ID)
+		})
+	}
+}
+
+func TestExtendAndReturnLoan(t *testing.T) {
+	ctx := context.Background()
+	handlers, store := newLoanCommandHandlers()
+	issue := loanapp.IssueLoanCommand{
+		CommandModel:    application.CommandModel{ID: "school"},
+		PupilID:         "pupil",
+		ClassID:         "class",
+		BookID:          "book",
+		InventoryNumber: "LMF-0001",
+		IssuedAt:        issuedAt,
+		DueDate:         dueDate,
+	}
+	loanID, err := handlers.IssueLoanHandler.Handle(ctx, issue)
+	assert.Nil(t, err)
+
+	extend := loanapp.ExtendLoanCommand{
+		CommandModel: application.CommandModel{ID: "school"},
+		LoanID:       loanID,
+		DueDate:      dueDate.AddDate(0, 1, 0),
+		Reason:       "summer course",
+	}
+	assert.Nil(t, handlers.ExtendLoanHandler.Handle(ctx, extend))
+	extend.DueDate = dueDate
+	assert.Error(t, handlers.ExtendLoanHandler.Handle(ctx, extend))
+
+	returnLoan := loanapp.ReturnLoanCommand{CommandModel: application.CommandModel{ID: "school"}, LoanID: loanID}
+	assert.Nil(t, handlers.ReturnLoanHandler.Handle(ctx, returnLoan))
+	assert.Error(t, handlers.ReturnLoanHandler.Handle(ctx, returnLoan))
+
+	aggregate := loadLoans(t, store)
+	assert.Equal(t, dueDate.AddDate(0, 1, 0), aggregate.Loans[0].DueDate)
+	assert.Equal(t, 1, aggregate.Loans[0].Extensions)
+	assert.True(t, aggregate.Loans[0].Returned())
+}
+
+func TestIssueLoanWithoutCopyInventory(t *testing.T) {
+	handlers, store := newLoanCommandHandlersWithCopies(memory.NewMemoryStore())
+	command := loanapp.IssueLoanCommand{PupilID: "pupil", ClassID: "class", BookID: "book", InventoryNumber: "17", IssuedAt: issuedAt, DueDate: dueDate}
+	command.ID = "school"
+	_, err := handlers.IssueLoanHandler.Handle(context.Background(), command)
+	assert.Nil(t, err)
+	assert.Equal(t, "17", loadLoans(t, store).Loans[0].InventoryNumber)
+}
+
+func TestLoanMovesCopy(t *testing.T) {
+	ctx := context.Background()
+	copyStore := memory.NewMemoryStoreWithEvents(copyInventory)
+	handlers, _ := newLoanCommandHandlersWithCopies(copyStore)
+	loadCopy := func() copydomain.Copy {
+		copies := copydomain.NewSchoolCopyAggregateWithID("school")
+		assert.Nil(t, application.NewCommandHandlerModel(copyStore, nil).LoadAggregate(ctx, copies))
+		return copies.Copies[0]
+	}
+	issue := loanapp.IssueLoanCommand{PupilID: "pupil", ClassID: "class", BookID: "book", InventoryNumber: "LMF-0001", IssuedAt: issuedAt, DueDate: dueDate}
+	issue.ID = "school"
+	loanID, err := handlers.IssueLoanHandler.Handle(ctx, issue)
+	assert.Nil(t, err)
+	assert.Equal(t, copydomain.Location{Kind: copydomain.WithPupil, ID: "pupil"}, loadCopy().Location)
+
+	returnLoan := loanapp.ReturnLoanCommand{CommandModel: application.CommandModel{ID: "school"}, LoanID: loanID}
+	assert.Nil(t, handlers.ReturnLoanHandler.Handle(ctx, returnLoan))
+	assert.Equal(t, copydomain.Location{Kind: copydomain.InClass, ID: "class"}, loadCopy().Location)
+
+	_, err = handlers.IssueLoanHandler.Handle(ctx, issue)
+	assert.Nil(t, err)
+}
@@ -0,0 +1,76 @@
+package loanapp
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/kammeph/school-book-storage-service/application"
+	"github.com/kammeph/school-book-storage-service/domain"
+	"github.com/kammeph/school-book-storage-service/domain/loandomain"
+)
+
+type LoanEventHandler struct {
+	repository LoanRepository
+}
+
+func NewLoanEventHandler(repository LoanRepository) application.EventHandler {
+	return &LoanEventHandler{repository}
+}
+
+func (h LoanEventHandler) Handle(ctx context.Context, eventBytes []byte) error {
+	event := &domain.EventModel{}
+	if err := json.Unmarshal(eventBytes, event); err != nil {
+		return err
+	}
+	switch event.EventType() {
+	case loandomain.LoanIssued:
+		return h.handleLoanIssued(ctx, event)
+	case loandomain.LoanExtended:
+		return h.handleLoanExtended(ctx, event)
+	case loandomain.LoanReturned:
+		return h.handleLoanReturned(ctx, event)
+	default:
+		return nil
+	}
+}
+
+func (h LoanEventHandler) handleLoanIssued(ctx context.Context, event domain.Event) error {
+	loanIssued := loandomain.LoanIssuedEvent{}
+	if err := event.GetJsonData(&loanIssued); err != nil {
+		return err
+	}
+	loan := loandomain.NewLoanProjection(
+		loanIssued.SchoolID,
+		loanIssued.LoanID,
+		loanIssued.PupilID,
+		loanIssued.ClassID,
+		loanIssued.BookID,
+		loanIssued.InventoryNumber,
+		loanIssued.IssuedAt,
+		loanIssued.DueDate,
+		event.EventVersion())
+	return h.repository.UpsertLoan(ctx, loan)
+}
+
+func (h LoanEventHandler) handleLoanExtended(ctx context.Context, event domain.Event) error {
+	loanExtended := loandomain.LoanExtendedEvent{}
+	if err := event.GetJsonData(&loanExtended); err != nil {
+		return err
+	}
+	loan, err := h.repository.GetLoanByID(ctx, event.AggregateID(), loanExtended.LoanID)
+	if err != nil {
+		return err
+	}
+	if loan.Version >= event.EventVersion() {
+		return nil
+	}
+	return h.repository.UpdateLoanDueDate(ctx, loan.LoanID, loanExtended.DueDate, loan.Extensions+1, event.EventVersion())
+}
+
+func (h LoanEventHandler) handleLoanReturned(ctx context.Context, event domain.Event) error {
+	loanReturned := loandomain.LoanReturnedEvent{}
+	if err := event.GetJsonData(&loanReturned); err != nil {
+		return err
+	}
+	return h.repository.UpdateLoanReturned(ctx, loanReturned.LoanID, loanReturned.ReturnedAt, event.EventVersion())
+}
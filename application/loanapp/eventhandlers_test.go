@@ -0,0 +1,60 @@
+package loanapp_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/kammeph/school-book-storage-service/application/loanapp"
+	"github.com/kammeph/school-book-storage-service/domain"
+	"github.com/kammeph/school-book-storage-service/domain/loandomain"
+	"github.com/kammeph/school-book-storage-service/infrastructure/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+func loanEvent(version int, eventType, data string) []byte {
+	eventBytes, _ := json.Marshal(domain.EventModel{
+		ID:      "school",
+		Type:    eventType,
+		Version: version,
+		At:      time.Now(),
+		Data:    data,
+	})
+	return eventBytes
+}
+
+func TestHandleLoanEvents(t *testing.T) {
+	ctx := context.Background()
+	repository := memory.NewMemoryLoanRepository()
+	handler := loanapp.NewLoanEventHandler(repository)
+	events := [][]byte{
+		loanEvent(1, loandomain.LoanIssued, "{\"schoolId\":\"school\",\"loanId\":\"loan1\",\"pupilId\":\"pupil1\",\"classId\":\"class1\",\"bookId\":\"book\",\"inventoryNumber\":\"LMF-0001\",\"issuedAt\":\"2022-09-01T00:00:00Z\",\"dueDate\":\"2023-07-15T00:00:00Z\"}"),
+		loanEvent(2, loandomain.LoanIssued, "{\"schoolId\":\"school\",\"loanId\":\"loan2\",\"pupilId\":\"pupil2\",\"classId\":\"class2\",\"bookId\":\"book\",\"issuedAt\":\"2022-09-01T00:00:00Z\",\"dueDate\":\"2023-07-15T00:00:00Z\"}"),
+		loanEvent(3, loandomain.LoanIssued, "{\"schoolId\":\"school\",\"loanId\":\"loan3\",\"pupilId\":\"pupil3\",\"classId\":\"class1\",\"bookId\":\"book\",\"issuedAt\":\"2022-09-01T00:00:00Z\",\"dueDate\":\"2023-07-15T00:00:00Z\"}"),
+		loanEvent(4, loandomain.LoanExtended, "{\"loanId\":\"loan1\",\"dueDate\":\"2023-08-15T00:00:00Z\",\"reason\":\"summer course\"}"),
+		loanEvent(5, loandomain.LoanReturned, "{\"loanId\":\"loan3\",\"returnedAt\":\"2023-07-01T00:00:00Z\"}"),
+	}
+	for _, event := range events {
+		assert.Nil(t, handler.Handle(ctx, event))
+	}
+	// redelivered events must not extend the loan twice
+	assert.Nil(t, handler.Handle(ctx, events[3]))
+
+	loan, err := repository.GetLoanByID(ctx, "school", "loan1")
+	assert.Nil(t, err)
+	assert.Equal(t, time.Date(2023, 8, 15, 0, 0, 0, 0, time.UTC), loan.DueDate)
+	assert.Equal(t, 1, loan.Extensions)
+	assert.Equal(t, "LMF-0001", loan.InventoryNumber)
+
+	at := time.Date(2023, 8, 1, 0, 0, 0, 0, time.UTC)
+	overdue, err := repository.GetOverdueLoans(ctx, "school", "", at)
+	assert.Nil(t, err)
+	assert.Len(t, overdue, 1)
+	assert.Equal(t, "loan2", overdue[0].LoanID)
+	overdue, err = repository.GetOverdueLoans(ctx, "school", "class1", at)
+	assert.Nil(t, err)
+	assert.Empty(t, overdue)
+
+	assert.Error(t, handler.Handle(ctx, loanEvent(6, loandomain.LoanIssued, "{\"loanId\":")))
+}
@@ -0,0 +1,88 @@
+package loanapp
+
+import (
+	"context"
+	"time"
+
+	"github.com/kammeph/school-book-storage-service/application"
+	"github.com/kammeph/school-book-storage-service/domain/loandomain"
+)
+
+type LoanQueryHandlers struct {
+	GetAllHandler     GetAllLoansQueryHandler
+	GetByPupilHandler GetLoansByPupilQueryHandler
+	GetOverdueHandler GetOverdueLoansQueryHandler
+}
+
+func NewLoanQueryHandlers(repository LoanRepository) LoanQueryHandlers {
+	return LoanQueryHandlers{
+		GetAllHandler:     NewGetAllLoansQueryHandler(repository),
+		GetByPupilHandler: NewGetLoansByPupilQueryHandler(repository),
+		GetOverdueHandler: NewGetOverdueLoansQueryHandler(repository),
+	}
+}
+
+type GetAllLoans struct {
+	application.QueryModel
+}
+
+func NewGetAllLoans(aggregateID string) GetAllLoans {
+	return GetAllLoans{QueryModel: application.QueryModel{ID: aggregateID}}
+}
+
+type GetAllLoansQueryHandler struct {
+	repository LoanRepository
+}
+
+func NewGetAllLoansQueryHandler(repository LoanRepository) GetAllLoansQueryHandler {
+	return GetAllLoansQueryHandler{repository: repository}
+}
+
+func (h GetAllLoansQueryHandler) Handle(ctx context.Context, query GetAllLoans) ([]loandomain.LoanProjection, error) {
+	return h.repository.GetLoansBySchoolID(ctx, query.AggregateID())
+}
+
+type GetLoansByPupil struct {
+	application.QueryModel
+	PupilID string
+}
+
+func NewGetLoansByPupil(aggregateID, pupilID string) GetLoansByPupil {
+	return GetLoansByPupil{QueryModel: application.QueryModel{ID: aggregateID}, PupilID: pupilID}
+}
+
+type GetLoansByPupilQueryHandler struct {
+	repository LoanRepository
+}
+
+func NewGetLoansByPupilQueryHandler(repository LoanRepository) GetLoansByPupilQueryHandler {
+	return GetLoansByPupilQueryHandler{repository: repository}
+}
+
+func (h GetLoansByPupilQueryHandler) Handle(ctx context.Context, query GetLoansByPupil) ([]loandomain.LoanProjection, error) {
+	return h.repository.GetLoansByPupilID(ctx, query.AggregateID(), query.PupilID)
+}
+
+// GetOverdueLoans asks for the loans that are not returned and were due
+// before the given time. An empty class ID selects the whole school.
+type GetOverdueLoans struct {
+	application.QueryModel
+	ClassID string
+	At      time.Time
+}
+
+func NewGetOverdueLoans(aggregateID, classID string, at time.Time) GetOverdueLoans {
+	return GetOverdueLoans{QueryModel: application.QueryModel{ID: aggregateID}, ClassID: classID, At: at}
+}
+
+type GetOverdueLoansQueryHandler struct {
+	repository LoanRepository
+}
+
+func NewGetOverdueLoansQueryHandler(repository LoanRepository) GetOverdueLoansQueryHandler {
+	return GetOverdueLoansQueryHandler{repository: repository}
+}
+
+func (h GetOverdueLoansQueryHandler) Handle(ctx context.Context, query GetOverdueLoans) ([]loandomain.LoanProjection, error) {
+	return h.repository.GetOverdueLoans(ctx, query.AggregateID(), query.ClassID, query.At)
+}
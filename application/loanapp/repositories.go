@@ -0,0 +1,18 @@
+package loanapp
+
+import (
+	"context"
+	"time"
+
+	"github.com/kammeph/school-book-storage-service/domain/loandomain"
+)
+
+type LoanRepository interface {
+	GetLoansBySchoolID(ctx context.Context, schoolID string) ([]loandomain.LoanProjection, error)
+	GetLoanByID(ctx context.Context, schoolID, loanID string) (loandomain.LoanProjection, error)
+	GetLoansByPupilID(ctx context.Context, schoolID, pupilID string) ([]loandomain.LoanProjection, error)
+	GetOverdueLoans(ctx context.Context, schoolID, classID string, at time.Time) ([]loandomain.LoanProjection, error)
+	UpsertLoan(ctx context.Context, loan loandomain.LoanProjection) error
+	UpdateLoanDueDate(ctx context.Context, loanID string, dueDate time.Time, extensions, version int) error
+	UpdateLoanReturned(ctx context.Context, loanID string, returnedAt time.Time, version int) error
+}
@@ -0,0 +1,211 @@
+package orderapp
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kammeph/school-book-storage-service/application"
+	"github.com/kammeph/school-book-storage-service/domain"
+	"github.com/kammeph/school-book-storage-service/domain/bookdomain"
+	"github.com/kammeph/school-book-storage-service/domain/orderdomain"
+	"github.com/kammeph/school-book-storage-service/domain/storagedomain"
+	"github.com/kammeph/school-book-storage-service/fp"
+)
+
+type OrderCommandHandlers struct {
+	CreateOrderHandler  CreateOrderCommandHandler
+	SendOrderHandler    SendOrderCommandHandler
+	ReceiveGoodsHandler ReceiveGoodsCommandHandler
+	CancelOrderHandler  CancelOrderCommandHandler
+}
+
+func NewOrderCommandHandlers(
+	store application.Store,
+	publisher application.EventPublisher,
+	bookStore application.Store,
+	storageStore application.Store,
+	storagePublisher application.EventPublisher,
+) OrderCommandHandlers {
+	return OrderCommandHandlers{
+		CreateOrderHandler:  NewCreateOrderCommandHandler(store, publisher, bookStore),
+		SendOrderHandler:    NewSendOrderCommandHandler(store, publisher),
+		ReceiveGoodsHandler: NewReceiveGoodsCommandHandler(store, publisher, storageStore, storagePublisher),
+		CancelOrderHandler:  NewCancelOrderCommandHandler(store, publisher),
+	}
+}
+
+type OrderLineCommand struct {
+	BookID    string       `json:"bookId"`
+	Quantity  int          `json:"quantity"`
+	UnitPrice domain.Money `json:"unitPrice"`
+}
+
+type CreateOrderCommand struct {
+	application.CommandModel
+	Supplier string             `json:"supplier"`
+	Lines    []OrderLineCommand `json:"lines"`
+}
+
+type CreateOrderCommandHandler struct {
+	*application.CommandHandlerModel
+	books *application.CommandHandlerModel
+}
+
+func NewCreateOrderCommandHandler(store application.Store, publisher application.EventPublisher, bookStore application.Store) CreateOrderCommandHandler {
+	return CreateOrderCommandHandler{
+		CommandHandlerModel: application.NewCommandHandlerModel(store, publisher),
+		books:               application.NewCommandHandlerModel(bookStore, nil),
+	}
+}
+
+// Handle creates a draft order. The ISBN and title of the lines are taken from
+// the books of the school.
+func (h CreateOrderCommandHandler) Handle(ctx context.Context, command CreateOrderCommand) (string, error) {
+	books := bookdomain.NewSchoolBookAggregateWithID(command.AggregateID())
+	if err := h.books.LoadAggregate(ctx, books); err != nil {
+		return "", err
+	}
+	lines := []orderdomain.OrderLine{}
+	for _, line := range command.Lines {
+		book := fp.Find(books.Books, func(b bookdomain.Book) bool { return b.ID == line.BookID })
+		if book == nil {
+			return "", bookdomain.ErrBookWithIDNotFound(line.BookID)
+		}
+		lines = append(lines, orderdomain.OrderLine{
+			BookID:    book.ID,
+			Isbn:      book.Isbn,
+			Title:     book.Name,
+			Quantity:  line.Quantity,
+			UnitPrice: line.UnitPrice,
+		})
+	}
+	aggregate := orderdomain.NewSchoolOrderAggregateWithID(command.AggregateID())
+	if err := h.LoadAggregate(ctx, aggregate); err != nil {
+		return "", err
+	}
+	orderID, err := aggregate.CreateOrder(command.Supplier, lines)
+	if err != nil {
+		return "", err
+	}
+	if err := h.SaveAndPublish(ctx, aggregate); err != nil {
+		return "", err
+	}
+	return orderID, nil
+}
+
+type SendOrderCommand struct {
+	application.CommandModel
+	OrderID string `json:"orderId"`
+}
+
+type SendOrderCommandHandler struct {
+	*application.CommandHandlerModel
+}
+
+func NewSendOrderCommandHandler(store application.Store, publisher application.EventPublisher) SendOrderCommandHandler {
+	return SendOrderCommandHandler{application.NewCommandHandlerModel(store, publisher)}
+}
+
+func (h SendOrderCommandHandler) Handle(ctx context.Context, command SendOrderCommand) error {
+	aggregate := orderdomain.NewSchoolOrderAggregateWithID(command.AggregateID())
+	if err := h.LoadAggregate(ctx, aggregate); err != nil {
+		return err
+	}
+	if err := aggregate.SendOrder(command.OrderID); err != nil {
+		return err
+	}
+	return h.SaveAndPublish(ctx, aggregate)
+}
+
+type ReceiveGoodsCommand struct {
+	application.CommandModel
+	OrderID   string `json:"orderId"`
+	StorageID string `json:"storageId"`
+	BookID    string `json:"bookId"`
+	Quantity  int    `json:"quantity"`
+}
+
+// ReceiveGoodsCommandHandler books delivered copies into the stock of a
+// storage. The storage is saved first as it guards the stock, so goods that
+// were not booked into a storage are still open on the order. The storage
+// books every receipt only once, so a receipt that is handled again after the
+// order could not be saved only completes the order.
+type ReceiveGoodsCommandHandler struct {
+	orders   *application.CommandHandlerModel
+	storages *application.CommandHandlerModel
+}
+
+func NewReceiveGoodsCommandHandler(
+	store application.Store,
+	publisher application.EventPublisher,
+	storageStore application.Store,
+	storagePublisher application.EventPublisher,
+) ReceiveGoodsCommandHandler {
+	return ReceiveGoodsCommandHandler{
+		orders:   application.NewCommandHandlerModel(store, publisher),
+		storages: application.NewCommandHandlerModel(storageStore, storagePublisher),
+	}
+}
+
+func (h ReceiveGoodsCommandHandler) Handle(ctx context.Context, command ReceiveGoodsCommand) error {
+	orders := orderdomain.NewSchoolOrderAggregateWithID(command.AggregateID())
+	if err := h.orders.LoadAggregate(ctx, orders); err != nil {
+		return err
+	}
+	storages := storagedomain.NewSchoolStorageAggregateWithID(command.AggregateID())
+	if err := h.storages.LoadAggregate(ctx, storages); err != nil {
+		return err
+	}
+	receiptID := receiptID(orders, command.OrderID, command.BookID)
+	line, err := orders.ReceiveGoods(command.OrderID, command.StorageID, command.BookID, command.Quantity)
+	if err != nil {
+		return err
+	}
+	if !storages.Received(receiptID) {
+		err := storages.ReceiveBooks(receiptID, command.StorageID, line.BookID, line.Isbn, line.Title, command.Quantity)
+		if err != nil {
+			return err
+		}
+	}
+	if err := h.storages.SaveAndPublish(ctx, storages); err != nil {
+		return err
+	}
+	return h.orders.SaveAndPublish(ctx, orders)
+}
+
+// receiptID identifies a receipt by the quantity of the line received before.
+// It only changes once the order is saved.
+func receiptID(orders *orderdomain.SchoolOrderAggregate, orderID, bookID string) string {
+	received := 0
+	if order := fp.Find(orders.Orders, func(o orderdomain.PurchaseOrder) bool { return o.ID == orderID }); order != nil {
+		if line := fp.Find(order.Lines, func(l orderdomain.OrderLine) bool { return l.BookID == bookID }); line != nil {
+			received = line.Received
+		}
+	}
+	return fmt.Sprintf("%s/%s/%d", orderID, bookID, received)
+}
+
+type CancelOrderCommand struct {
+	application.CommandModel
+	OrderID string `json:"orderId"`
+	Reason  string `json:"reason"`
+}
+
+type CancelOrderCommandHandler struct {
+	*application.CommandHandlerModel
+}
+
+func NewCancelOrderCommandHandler(store application.Store, publisher application.EventPublisher) CancelOrderCommandHandler {
+	return CancelOrderCommandHandler{application.NewCommandHandlerModel(store, publisher)}
+}
+
+func (h CancelOrderCommandHandler) Handle(ctx context.Context, command CancelOrderCommand) error {
+	aggregate := orderdomain.NewSchoolOrderAggregateWithID(command.AggregateID())
+	if err := h.LoadAggregate(ctx, aggregate); err != nil {
+		return err
+	}
+	if err := aggregate.CancelOrder(command.OrderID, command.Reason); err != nil {
+		return err
+	}
+	return h.SaveAndPublish(ctx, aggregate)
+}
@@ -0,0 +1,182 @@
+package orderapp_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/kammeph/school-book-storage-service/application"
+	"github.com/kammeph/school-book-storage-service/application/orderapp"
+	"github.com/kammeph/school-book-storage-service/domain"
+	"github.com/kammeph/school-book-storage-service/domain/bookdomain"
+	"github.com/kammeph/school-book-storage-service/domain/orderdomain"
+	"github.com/kammeph/school-book-storage-service/domain/storagedomain"
+	"github.com/kammeph/school-book-storage-service/infrastructure/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+func newOrderCommandHandlers() (orderapp.OrderCommandHandlers, *memory.MemoryStore, *memory.MemoryStore) {
+	bookStore := memory.NewMemoryStoreWithEvents([]domain.Event{
+		&domain.EventModel{
+			ID:      "school",
+			Type:    bookdomain.BookAdded,
+			Version: 1,
+			At:      time.Now(),
+			Data:    "{\"SchoolID\":\"school\",\"BookID\":\"book\",\"Isbn\":\"123\",\"Name\":\"math\"}",
+		},
+	})
+	storageStore := memory.NewMemoryStoreWithEvents([]domain.Event{
+		&domain.EventModel{
+			ID:      "school",
+			Type:    storagedomain.StorageAdded,
+			Version: 1,
+			At:      time.Now(),
+			Data:    "{\"schoolId\":\"school\",\"storageId\":\"storage\",\"name\":\"closet\",\"location\":\"room 1\"}",
+		},
+	})
+	store := memory.NewMemoryStore()
+	return orderapp.NewOrderCommandHandlers(store, nil, bookStore, storageStore, nil), store, storageStore
+}
+
+func createAndSendOrder(t *testing.T, handlers orderapp.OrderCommandHandlers) string {
+	ctx := context.Background()
+	create := orderapp.CreateOrderCommand{
+		CommandModel: application.CommandModel{ID: "school"},
+		Supplier:     "supplier",
+		Lines:        []orderapp.OrderLineCommand{{BookID: "book", Quantity: 20, UnitPrice: domain.NewMoney(1250, "EUR")}},
+	}
+	orderID, err := handlers.CreateOrderHandler.Handle(ctx, create)
+	assert.Nil(t, err)
+	send := orderapp.SendOrderCommand{CommandModel: application.CommandModel{ID: "school"}, OrderID: orderID}
+	assert.Nil(t, handlers.SendOrderHandler.Handle(ctx, send))
+	return orderID
+}
+
+func TestCreateOrder(t *testing.T) {
+	ctx := context.Background()
+	handlers, store, _ := newOrderCommandHandlers()
+	orderID := createAndSendOrder(t, handlers)
+	orders := orderdomain.NewSchoolOrderAggregateWithID("school")
+	assert.Nil(t, application.NewCommandHandlerModel(store, nil).LoadAggregate(ctx, orders))
+	assert.Equal(t, orderID, orders.Orders[0].ID)
+	assert.Equal(t, orderdomain.Sent, orders.Orders[0].Status)
+	assert.Equal(t, domain.Isbn("123"), orders.Orders[0].Lines[0].Isbn)
+	assert.Equal(t, "math", orders.Orders[0].Lines[0].Title)
+
+	create := orderapp.CreateOrderCommand{
+		CommandModel: application.CommandModel{ID: "school"},
+		Supplier:     "supplier",
+		Lines:        []orderapp.OrderLineCommand{{BookID: "unknown", Quantity: 20}},
+	}
+	_, err := handlers.CreateOrderHandler.Handle(ctx, create)
+	assert.Equal(t, bookdomain.ErrBookWithIDNotFound("unknown"), err)
+}
+
+func TestReceiveGoods(t *testing.T) {
+	tests := []struct {
+		name        string
+		storageID   string
+		quantity    int
+		stock       int
+		status      orderdomain.OrderStatus
+		expectError bool
+	}{
+		{name: "receive part", storageID: "storage", quantity: 15, stock: 15, status: orderdomain.PartiallyReceived},
+		{name: "receive all", storageID: "storage", quantity: 20, stock: 20, status: orderdomain.Received},
+		{name: "receive too many", storageID: "storage", quantity: 21, status: orderdomain.Sent, expectError: true},
+		{name: "storage not found", storageID: "unknown", quantity: 15, status: orderdomain.Sent, expectError: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := context.Background()
+			handlers, store, storageStore := newOrderCommandHandlers()
+			orderID := createAndSendOrder(t, handlers)
+			receive := orderapp.ReceiveGoodsCommand{
+				CommandModel: application.CommandModel{ID: "school"},
+				OrderID:      orderID,
+				StorageID:    test.storageID,
+				BookID:       "book",
+				Quantity:     test.quantity,
+			}
+			err := handlers.ReceiveGoodsHandler.Handle(ctx, receive)
+			if test.expectError {
+				assert.Error(t, err)
+			} else {
+				assert.Nil(t, err)
+			}
+			orders := orderdomain.NewSchoolOrderAggregateWithID("school")
+			assert.Nil(t, application.NewCommandHandlerModel(store, nil).LoadAggregate(ctx, orders))
+			assert.Equal(t, test.status, orders.Orders[0].Status)
+			storages := storagedomain.NewSchoolStorageAggregateWithID("school")
+			assert.Nil(t, application.NewCommandHandlerModel(storageStore, nil).LoadAggregate(ctx, storages))
+			assert.Equal(t, test.stock, storages.Storages[0].Quantity("book"))
+		})
+	}
+}
+
+type failingStore struct {
+	*memory.MemoryStore
+}
+
+func (s failingStore) Save(ctx context.Context, events []domain.Event) error {
+	return errors.New("store not available")
+}
+
+func TestReceiveGoodsKeepsOrderOpenWhenStorageNotSaved(t *testing.T) {
+	ctx := context.Background()
+	handlers, store, storageStore := newOrderCommandHandlers()
+	orderID := createAndSendOrder(t, handlers)
+	handlers = orderapp.NewOrderCommandHandlers(store, nil, memory.NewMemoryStore(), failingStore{storageStore}, nil)
+	receive := orderapp.ReceiveGoodsCommand{
+		CommandModel: application.CommandModel{ID: "school"},
+		OrderID:      orderID,
+		StorageID:    "storage",
+		BookID:       "book",
+		Quantity:     20,
+	}
+	assert.Error(t, handlers.ReceiveGoodsHandler.Handle(ctx, receive))
+	orders := orderdomain.NewSchoolOrderAggregateWithID("school")
+	assert.Nil(t, application.NewCommandHandlerModel(store, nil).LoadAggregate(ctx, orders))
+	assert.Equal(t, orderdomain.Sent, orders.Orders[0].Status)
+	assert.Equal(t, 0, orders.Orders[0].Lines[0].Received)
+}
+
+func TestReceiveGoodsAgainAfterOrderNotSaved(t *testing.T) {
+	ctx := context.Background()
+	handlers, store, storageStore := newOrderCommandHandlers()
+	orderID := createAndSendOrder(t, handlers)
+	receive := orderapp.ReceiveGoodsCommand{
+		CommandModel: application.CommandModel{ID: "school"},
+		OrderID:      orderID,
+		StorageID:    "storage",
+		BookID:       "book",
+		Quantity:     20,
+	}
+	failing := orderapp.NewOrderCommandHandlers(failingStore{store}, nil, memory.NewMemoryStore(), storageStore, nil)
+	assert.Error(t, failing.ReceiveGoodsHandler.Handle(ctx, receive))
+	assert.Nil(t, handlers.ReceiveGoodsHandler.Handle(ctx, receive))
+
+	orders := orderdomain.NewSchoolOrderAggregateWithID("school")
+	assert.Nil(t, application.NewCommandHandlerModel(store, nil).LoadAggregate(ctx, orders))
+	assert.Equal(t, orderdomain.Received, orders.Orders[0].Status)
+	storages := storagedomain.NewSchoolStorageAggregateWithID("school")
+	assert.Nil(t, application.NewCommandHandlerModel(storageStore, nil).LoadAggregate(ctx, storages))
+	assert.Equal(t, 20, storages.Storages[0].Quantity("book"))
+}
+
+func TestCancelOrder(t *testing.T) {
+	ctx := context.Background()
+	handlers, store, _ := newOrderCommandHandlers()
+	orderID := createAndSendOrder(t, handlers)
+	cancel := orderapp.CancelOrderCommand{
+		CommandModel: application.CommandModel{ID: "school"},
+		OrderID:      orderID,
+		Reason:       "not delivered",
+	}
+	assert.Nil(t, handlers.CancelOrderHandler.Handle(ctx, cancel))
+	orders := orderdomain.NewSchoolOrderAggregateWithID("school")
+	assert.Nil(t, application.NewCommandHandlerModel(store, nil).LoadAggregate(ctx, orders))
+	assert.Equal(t, orderdomain.Cancelled, orders.Orders[0].Status)
+	assert.Error(t, handlers.CancelOrderHandler.Handle(ctx, cancel))
+}
@@ -0,0 +1,92 @@
+package orderapp
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/kammeph/school-book-storage-service/application"
+	"github.com/kammeph/school-book-storage-service/domain"
+	"github.com/kammeph/school-book-storage-service/domain/orderdomain"
+)
+
+type OrderEventHandler struct {
+	repository PurchaseOrderRepository
+}
+
+func NewOrderEventHandler(repository PurchaseOrderRepository) application.EventHandler {
+	return &OrderEventHandler{repository}
+}
+
+func (h OrderEventHandler) Handle(ctx context.Context, eventBytes []byte) error {
+	event := &domain.EventModel{}
+	if err := json.Unmarshal(eventBytes, event); err != nil {
+		return err
+	}
+	switch event.EventType() {
+	case orderdomain.OrderCreated:
+		return h.handleOrderCreated(ctx, event)
+	case orderdomain.OrderSent:
+		return h.handleOrderSent(ctx, event)
+	case orderdomain.GoodsReceived:
+		return h.handleGoodsReceived(ctx, event)
+	case orderdomain.OrderCancelled:
+		return h.handleOrderCancelled(ctx, event)
+	default:
+		return nil
+	}
+}
+
+func (h OrderEventHandler) handleOrderCreated(ctx context.Context, event domain.Event) error {
+	orderCreated := orderdomain.OrderCreatedEvent{}
+	if err := event.GetJsonData(&orderCreated); err != nil {
+		return err
+	}
+	order := orderdomain.NewPurchaseOrderProjection(
+		orderCreated.SchoolID,
+		orderCreated.OrderID,
+		orderCreated.Supplier,
+		orderCreated.Lines,
+		event.EventAt(),
+		event.EventVersion())
+	return h.repository.UpsertOrder(ctx, order)
+}
+
+func (h OrderEventHandler) handleOrderSent(ctx context.Context, event domain.Event) error {
+	orderSent := orderdomain.OrderSentEvent{}
+	if err := event.GetJsonData(&orderSent); err != nil {
+		return err
+	}
+	return h.repository.UpdateOrderStatus(ctx, orderSent.OrderID, orderdomain.Sent, event.EventVersion())
+}
+
+// handleGoodsReceived adds the received copies to the stored line. Events the
+// order already reflects are skipped, so a redelivered event is not counted
+// twice.
+func (h OrderEventHandler) handleGoodsReceived(ctx context.Context, event domain.Event) error {
+	goodsReceived := orderdomain.GoodsReceivedEvent{}
+	if err := event.GetJsonData(&goodsReceived); err != nil {
+		return err
+	}
+	order, err := h.repository.GetOrderByID(ctx, event.AggregateID(), goodsReceived.OrderID)
+	if err != nil {
+		return err
+	}
+	if order.Version >= event.EventVersion() {
+		return nil
+	}
+	lines := append([]orderdomain.OrderLine{}, order.Lines...)
+	for idx, line := range lines {
+		if line.BookID == goodsReceived.BookID {
+			lines[idx].Received += goodsReceived.Quantity
+		}
+	}
+	return h.repository.UpdateOrderLines(ctx, order.OrderID, lines, orderdomain.ReceiptStatus(lines), event.EventVersion())
+}
+
+func (h OrderEventHandler) handleOrderCancelled(ctx context.Context, event domain.Event) error {
+	orderCancelled := orderdomain.OrderCancelledEvent{}
+	if err := event.GetJsonData(&orderCancelled); err != nil {
+		return err
+	}
+	return h.repository.UpdateOrderStatus(ctx, orderCancelled.OrderID, orderdomain.Cancelled, event.EventVersion())
+}
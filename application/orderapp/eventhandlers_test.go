@@ -0,0 +1,56 @@
+package orderapp_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/kammeph/school-book-storage-service/application/orderapp"
+	"github.com/kammeph/school-book-storage-service/domain"
+	"github.com/kammeph/school-book-storage-service/domain/orderdomain"
+	"github.com/kammeph/school-book-storage-service/infrastructure/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+func orderEvent(version int, eventType, data string) []byte {
+	eventBytes, _ := json.Marshal(domain.EventModel{
+		ID:      "school",
+		Type:    eventType,
+		Version: version,
+		At:      time.Now(),
+		Data:    data,
+	})
+	return eventBytes
+}
+
+func TestHandleOrderEvents(t *testing.T) {
+	ctx := context.Background()
+	repository := memory.NewMemoryOrderRepository()
+	handler := orderapp.NewOrderEventHandler(repository)
+	events := [][]byte{
+		orderEvent(1, orderdomain.OrderCreated, "{\"schoolId\":\"school\",\"orderId\":\"order\",\"supplier\":\"supplier\",\"lines\":[{\"bookId\":\"math\",\"quantity\":10,\"unitPrice\":12.5},{\"bookId\":\"art\",\"quantity\":5,\"unitPrice\":8}]}"),
+		orderEvent(2, orderdomain.OrderSent, "{\"orderId\":\"order\"}"),
+		orderEvent(3, orderdomain.GoodsReceived, "{\"orderId\":\"order\",\"storageId\":\"storage\",\"bookId\":\"math\",\"quantity\":10}"),
+	}
+	for _, event := range events {
+		assert.Nil(t, handler.Handle(ctx, event))
+	}
+	assert.Nil(t, handler.Handle(ctx, events[2]))
+	order, err := repository.GetOrderByID(ctx, "school", "order")
+	assert.Nil(t, err)
+	assert.Equal(t, orderdomain.PartiallyReceived, order.Status)
+	assert.Equal(t, 10, order.Lines[0].Received)
+	assert.Equal(t, domain.NewMoney(16500, domain.DefaultCurrency), order.Total)
+	open, err := repository.GetOpenOrders(ctx, "school")
+	assert.Nil(t, err)
+	assert.Len(t, open, 1)
+
+	assert.Nil(t, handler.Handle(ctx, orderEvent(4, orderdomain.GoodsReceived, "{\"orderId\":\"order\",\"storageId\":\"storage\",\"bookId\":\"art\",\"quantity\":5}")))
+	order, err = repository.GetOrderByID(ctx, "school", "order")
+	assert.Nil(t, err)
+	assert.Equal(t, orderdomain.Received, order.Status)
+	open, err = repository.GetOpenOrders(ctx, "school")
+	assert.Nil(t, err)
+	assert.Empty(t, open)
+}
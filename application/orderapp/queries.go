@@ -0,0 +1,85 @@
+package orderapp
+
+import (
+	"context"
+
+	"github.com/kammeph/school-book-storage-service/application"
+	"github.com/kammeph/school-book-storage-service/domain/orderdomain"
+)
+
+type OrderQueryHandlers struct {
+	GetAllHandler       GetAllOrdersQueryHandler
+	GetOpenHandler      GetOpenOrdersQueryHandler
+	GetOrderByIDHandler GetOrderByIDQueryHandler
+}
+
+func NewOrderQueryHandlers(repository PurchaseOrderRepository) OrderQueryHandlers {
+	return OrderQueryHandlers{
+		GetAllHandler:       NewGetAllOrdersQueryHandler(repository),
+		GetOpenHandler:      NewGetOpenOrdersQueryHandler(repository),
+		GetOrderByIDHandler: NewGetOrderByIDQueryHandler(repository),
+	}
+}
+
+type GetAllOrders struct {
+	application.QueryModel
+}
+
+func NewGetAllOrders(aggregateID string) GetAllOrders {
+	return GetAllOrders{QueryModel: application.QueryModel{ID: aggregateID}}
+}
+
+type GetAllOrdersQueryHandler struct {
+	repository PurchaseOrderRepository
+}
+
+func NewGetAllOrdersQueryHandler(repository PurchaseOrderRepository) GetAllOrdersQueryHandler {
+	return GetAllOrdersQueryHandler{repository: repository}
+}
+
+func (h GetAllOrdersQueryHandler) Handle(ctx context.Context, query GetAllOrders) ([]orderdomain.PurchaseOrderProjection, error) {
+	return h.repository.GetOrdersBySchoolID(ctx, query.AggregateID())
+}
+
+// GetOpenOrders asks for the orders that are neither received completely nor
+// cancelled.
+type GetOpenOrders struct {
+	application.QueryModel
+}
+
+func NewGetOpenOrders(aggregateID string) GetOpenOrders {
+	return GetOpenOrders{QueryModel: application.QueryModel{ID: aggregateID}}
+}
+
+type GetOpenOrdersQueryHandler struct {
+	repository PurchaseOrderRepository
+}
+
+func NewGetOpenOrdersQueryHandler(repository PurchaseOrderRepository) GetOpenOrdersQueryHandler {
+	return GetOpenOrdersQueryHandler{repository: repository}
+}
+
+func (h GetOpenOrdersQueryHandler) Handle(ctx context.Context, query GetOpenOrders) ([]orderdomain.PurchaseOrderProjection, error) {
+	return h.repository.GetOpenOrders(ctx, query.AggregateID())
+}
+
+type GetOrderByID struct {
+	application.QueryModel
+	OrderID string
+}
+
+func NewGetOrderByID(aggregateID, orderID string) GetOrderByID {
+	return GetOrderByID{QueryModel: application.QueryModel{ID: aggregateID}, OrderID: orderID}
+}
+
+type GetOrderByIDQueryHandler struct {
+	repository PurchaseOrderRepository
+}
+
+func NewGetOrderByIDQueryHandler(repository PurchaseOrderRepository) GetOrderByIDQueryHandler {
+	return GetOrderByIDQueryHandler{repository: repository}
+}
+
+func (h GetOrderByIDQueryHandler) Handle(ctx context.Context, query GetOrderByID) (orderdomain.PurchaseOrderProjection, error) {
+	return h.repository.GetOrderByID(ctx, query.AggregateID(), query.OrderID)
+}
@@ -0,0 +1,16 @@
+package orderapp
+
+import (
+	"context"
+
+	"github.com/kammeph/school-book-storage-service/domain/orderdomain"
+)
+
+type PurchaseOrderRepository interface {
+	GetOrdersBySchoolID(ctx context.Context, schoolID string) ([]orderdomain.PurchaseOrderProjection, error)
+	GetOpenOrders(ctx context.Context, schoolID string) ([]orderdomain.PurchaseOrderProjection, error)
+	GetOrderByID(ctx context.Context, schoolID, orderID string) (orderdomain.PurchaseOrderProjection, error)
+	UpsertOrder(ctx context.Context, order orderdomain.PurchaseOrderProjection) error
+	UpdateOrderStatus(ctx context.Context, orderID string, status orderdomain.OrderStatus, version int) error
+	UpdateOrderLines(ctx context.Context, orderID string, lines []orderdomain.OrderLine, status orderdomain.OrderStatus, version int) error
+}
@@ -0,0 +1,40 @@
+package planningapp
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"strings"
+)
+
+var bookDemandHeader = []string{"bookId", "isbn", "name", "grades", "required", "stock", "shortfall", "surplus"}
+
+// WriteBookDemandCSV writes the demands as CSV with a header row. The grades
+// of a book are separated by spaces.
+func WriteBookDemandCSV(w io.Writer, demands []BookDemand) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(bookDemandHeader); err != nil {
+		return err
+	}
+	for _, demand := range demands {
+		grades := make([]string, len(demand.Grades))
+		for idx, grade := range demand.Grades {
+			grades[idx] = strconv.Itoa(grade)
+		}
+		record := []string{
+			demand.BookID,
+			demand.Isbn.String(),
+			demand.Name,
+			strings.Join(grades, " "),
+			strconv.Itoa(demand.Required),
+			strconv.Itoa(demand.Stock),
+			strconv.Itoa(demand.Shortfall),
+			strconv.Itoa(demand.Surplus),
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
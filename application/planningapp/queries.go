@@ -0,0 +1,116 @@
+package planningapp
+
+import (
+	"context"
+	"sort"
+
+	"github.com/kammeph/school-book-storage-service/application"
+	"github.com/kammeph/school-book-storage-service/application/bookapp"
+	"github.com/kammeph/school-book-storage-service/application/classapp"
+	"github.com/kammeph/school-book-storage-service/application/storageapp"
+	"github.com/kammeph/school-book-storage-service/domain"
+	"github.com/kammeph/school-book-storage-service/domain/storagedomain"
+)
+
+// BookDemand compares the copies of a book the classes of a school year need
+// with the copies in the storages of the school.
+type BookDemand struct {
+	BookID    string      `json:"bookId"`
+	Isbn      domain.Isbn `json:"isbn"`
+	Name      string      `json:"name"`
+	Grades    []int       `json:"grades"`
+	Required  int         `json:"required"`
+	Stock     int         `json:"stock"`
+	Shortfall int         `json:"shortfall"`
+	Surplus   int         `json:"surplus"`
+}
+
+type PlanningQueryHandlers struct {
+	GetBookDemandHandler GetBookDemandQueryHandler
+}
+
+func NewPlanningQueryHandlers(
+	books bookapp.BookRepository,
+	classes classapp.ClassWithBooksRepository,
+	storages storageapp.StorageWithBooksRepository,
+) PlanningQueryHandlers {
+	return PlanningQueryHandlers{
+		GetBookDemandHandler: NewGetBookDemandQueryHandler(books, classes, storages),
+	}
+}
+
+// GetBookDemand asks for the demand of books of the school year that starts
+// in the given year.
+type GetBookDemand struct {
+	application.QueryModel
+	Year int
+}
+
+func NewGetBookDemand(aggregateID string, year int) GetBookDemand {
+	return GetBookDemand{QueryModel: application.QueryModel{ID: aggregateID}, Year: year}
+}
+
+type GetBookDemandQueryHandler struct {
+	books    bookapp.BookRepository
+	classes  classapp.ClassWithBooksRepository
+	storages storageapp.StorageWithBooksRepository
+}
+
+func NewGetBookDemandQueryHandler(
+	books bookapp.BookRepository,
+	classes classapp.ClassWithBooksRepository,
+	storages storageapp.StorageWithBooksRepository,
+) GetBookDemandQueryHandler {
+	return GetBookDemandQueryHandler{books, classes, storages}
+}
+
+// Handle requires one copy of a book for every pupil of a class whose grade
+// uses the book. Damaged and lost copies do not count as stock. The demands
+// are sorted by the name of the book.
+func (h GetBookDemandQueryHandler) Handle(ctx context.Context, query GetBookDemand) ([]BookDemand, error) {
+	books, err := h.books.GetBooksBySchoolID(ctx, query.AggregateID())
+	if err != nil {
+		return nil, err
+	}
+	classes, err := h.classes.GetClassesBySchoolYear(ctx, query.AggregateID(), query.Year)
+	if err != nil {
+		return nil, err
+	}
+	storages, err := h.storages.GetAllStoragesBySchoolID(ctx, query.AggregateID())
+	if err != nil {
+		return nil, err
+	}
+	pupilsPerGrade := map[int]int{}
+	for _, class := range classes {
+		pupilsPerGrade[class.Grade] += class.NumberOfPupils
+	}
+	stock := map[string]int{}
+	for _, storage := range storages {
+		for _, book := range storage.Books {
+			stock[book.BookID] += book.Quantity -
+				storagedomain.InCondition(book.Quantity, book.Conditions, storagedomain.Damaged) -
+				storagedomain.InCondition(book.Quantity, book.Conditions, storagedomain.Lost)
+		}
+	}
+	demands := []BookDemand{}
+	for _, book := range books {
+		demand := BookDemand{
+			BookID: book.BookID,
+			Isbn:   book.Isbn,
+			Name:   book.Name,
+			Grades: book.Grades,
+			Stock:  stock[book.BookID],
+		}
+		for _, grade := range book.Grades {
+			demand.Required += pupilsPerGrade[grade]
+		}
+		if demand.Required > demand.Stock {
+			demand.Shortfall = demand.Required - demand.Stock
+		} else {
+			demand.Surplus = demand.Stock - demand.Required
+		}
+		demands = append(demands, demand)
+	}
+	sort.SliceStable(demands, func(i, j int) bool { return demands[i].Name < demands[j].Name })
+	return demands, nil
+}
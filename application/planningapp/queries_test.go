@@ -0,0 +1,83 @@
+package planningapp_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/kammeph/school-book-storage-service/application/planningapp"
+	"github.com/kammeph/school-book-storage-service/domain"
+	"github.com/kammeph/school-book-storage-service/domain/bookdomain"
+	"github.com/kammeph/school-book-storage-service/domain/classdomain"
+	"github.com/kammeph/school-book-storage-service/domain/storagedomain"
+	"github.com/kammeph/school-book-storage-service/infrastructure/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+func newHandlers(t *testing.T) planningapp.PlanningQueryHandlers {
+	ctx := context.Background()
+	books := memory.NewMemoryBookRepository()
+	assert.Nil(t, books.UpsertBook(ctx, bookdomain.NewBookProjection("school", "math", "123", "Math", "", domain.NewMoney(1000, "EUR"), []int{5, 6}, 1)))
+	assert.Nil(t, books.UpsertBook(ctx, bookdomain.NewBookProjection("school", "art", "456", "Art", "", domain.NewMoney(1000, "EUR"), []int{7}, 2)))
+	from := time.Date(2022, 8, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2023, 7, 31, 0, 0, 0, 0, time.UTC)
+	classes := memory.NewMemoryClassRepositoryWithClasses([]classdomain.ClassWithBooks{
+		classdomain.NewClassWithBooks("school", "5a", 5, "a", 25, from, to, 1),
+		classdomain.NewClassWithBooks("school", "6a", 6, "a", 20, from, to, 2),
+		classdomain.NewClassWithBooks("school", "5b", 5, "b", 30, from.AddDate(-1, 0, 0), to.AddDate(-1, 0, 0), 3),
+	})
+	storage1 := storagedomain.NewStorageWithBooks("school", "storage1", "Closet 1", "Room 101", 1)
+	storage1.Books = []storagedomain.BookInStorage{{BookID: "math", Quantity: 30}, {BookID: "art", Quantity: 4}}
+	storage2 := storagedomain.NewStorageWithBooks("school", "storage2", "Closet 2", "Room 102", 1)
+	storage2.Books = []storagedomain.BookInStorage{{BookID: "math", Quantity: 10}}
+	storages := memory.NewMemoryRepositoryWithStorages([]storagedomain.StorageWithBooks{storage1, storage2})
+	return planningapp.NewPlanningQueryHandlers(books, classes, storages)
+}
+
+func TestGetBookDemand(t *testing.T) {
+	handlers := newHandlers(t)
+	demands, err := handlers.GetBookDemandHandler.Handle(context.Background(), planningapp.NewGetBookDemand("school", 2022))
+	assert.Nil(t, err)
+	assert.Equal(t, []planningapp.BookDemand{
+		{BookID: "art", Isbn: "456", Name: "Art", Grades: []int{7}, Required: 0, Stock: 4, Surplus: 4},
+		{BookID: "math", Isbn: "123", Name: "Math", Grades: []int{5, 6}, Required: 45, Stock: 40, Shortfall: 5},
+	}, demands)
+}
+
+func TestGetBookDemandCountsUsableCopies(t *testing.T) {
+	ctx := context.Background()
+	books := memory.NewMemoryBookRepository()
+	assert.Nil(t, books.UpsertBook(ctx, bookdomain.NewBookProjection("school", "math", "123", "Math", "", domain.NewMoney(1000, "EUR"), []int{5}, 1)))
+	from := time.Date(2022, 8, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2023, 7, 31, 0, 0, 0, 0, time.UTC)
+	classes := memory.NewMemoryClassRepositoryWithClasses([]classdomain.ClassWithBooks{
+		classdomain.NewClassWithBooks("school", "5a", 5, "a", 25, from, to, 1),
+	})
+	storage := storagedomain.NewStorageWithBooks("school", "storage", "Closet", "Room 101", 1)
+	storage.Books = []storagedomain.BookInStorage{{BookID: "math", Quantity: 30, Conditions: []storagedomain.ConditionCount{
+		{Condition: storagedomain.Worn, Quantity: 4},
+		{Condition: storagedomain.Damaged, Quantity: 3},
+		{Condition: storagedomain.Lost, Quantity: 5},
+	}}}
+	storages := memory.NewMemoryRepositoryWithStorages([]storagedomain.StorageWithBooks{storage})
+	handlers := planningapp.NewPlanningQueryHandlers(books, classes, storages)
+	demands, err := handlers.GetBookDemandHandler.Handle(ctx, planningapp.NewGetBookDemand("school", 2022))
+	assert.Nil(t, err)
+	assert.Equal(t, []planningapp.BookDemand{
+		{BookID: "math", Isbn: "123", Name: "Math", Grades: []int{5}, Required: 25, Stock: 22, Shortfall: 3},
+	}, demands)
+}
+
+func TestWriteBookDemandCSV(t *testing.T) {
+	handlers := newHandlers(t)
+	demands, err := handlers.GetBookDemandHandler.Handle(context.Background(), planningapp.NewGetBookDemand("school", 2022))
+	assert.Nil(t, err)
+	buffer := &bytes.Buffer{}
+	assert.Nil(t, planningapp.WriteBookDemandCSV(buffer, demands))
+	assert.Equal(t,
+		"bookId,isbn,name,grades,required,stock,shortfall,surplus\n"+
+			"art,456,Art,7,0,4,0,4\n"+
+			"math,123,Math,5 6,45,40,5,0\n",
+		buffer.String())
+}
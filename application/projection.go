@@ -0,0 +1,95 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+
+	"github.com/kammeph/school-book-storage-service/domain"
+	"github.com/kammeph/school-book-storage-service/fp"
+)
+
+// ProjectionState tracks up to which aggregate version a projection has been
+// built and which versions in between were never received.
+type ProjectionState struct {
+	Projection      string `json:"projection" bson:"projection"`
+	AggregateID     string `json:"aggregateId" bson:"aggregateId"`
+	Version         int    `json:"version" bson:"version"`
+	MissingVersions []int  `json:"missingVersions" bson:"missingVersions"`
+}
+
+func NewProjectionState(projection, aggregateID string) ProjectionState {
+	return ProjectionState{projection, aggregateID, 0, []int{}}
+}
+
+func (s ProjectionState) HasGaps() bool {
+	return len(s.MissingVersions) > 0
+}
+
+// Track registers the version of a received event. It returns false if the
+// version was already applied to the projection or is older than the latest
+// applied version. A missing version that arrives late is not applied either,
+// as the projections only take newer versions, and stays flagged as missing.
+func (s *ProjectionState) Track(version int) bool {
+	if version <= s.Version {
+		return false
+	}
+	for missing := s.Version + 1; missing < version; missing++ {
+		s.MissingVersions = append(s.MissingVersions, missing)
+	}
+	s.Version = version
+	return true
+}
+
+type ProjectionStateRepository interface {
+	GetProjectionState(ctx context.Context, projection, aggregateID string) (ProjectionState, error)
+	GetProjectionStatesWithGaps(ctx context.Context, projection string) ([]ProjectionState, error)
+	SaveProjectionState(ctx context.Context, state ProjectionState) error
+}
+
+// GapDetector wraps the event handler of a projection. It drops events that were
+// already applied and flags missing versions in the projection state. Gaps are
+// only reported, the projections of the affected aggregates have to be rebuilt
+// from the event store.
+type GapDetector struct {
+	projection string
+	states     ProjectionStateRepository
+	handler    EventHandler
+}
+
+func NewGapDetector(projection string, states ProjectionStateRepository, handler EventHandler) *GapDetector {
+	return &GapDetector{projection, states, handler}
+}
+
+func (d *GapDetector) Handle(ctx context.Context, eventBytes []byte) error {
+	event := &domain.EventModel{}
+	if err := json.Unmarshal(eventBytes, event); err != nil {
+		return err
+	}
+	state, err := d.states.GetProjectionState(ctx, d.projection, event.AggregateID())
+	if err != nil {
+		return err
+	}
+	missingBefore := len(state.MissingVersions)
+	if !state.Track(event.EventVersion()) {
+		if fp.Some(state.MissingVersions, func(v int) bool { return v == event.EventVersion() }) {
+			log.Printf(
+				"projection %s of aggregate %s received missing version %d too late, rebuild the projection",
+				d.projection, event.AggregateID(), event.EventVersion())
+		}
+		return nil
+	}
+	if len(state.MissingVersions) > missingBefore {
+		log.Printf(
+			"projection %s of aggregate %s is missing versions %v",
+			d.projection, event.AggregateID(), state.MissingVersions)
+	}
+	if err := d.handler.Handle(ctx, eventBytes); err != nil {
+		return err
+	}
+	return d.states.SaveProjectionState(ctx, state)
+}
+
+func (d *GapDetector) Gaps(ctx context.Context) ([]ProjectionState, error) {
+	return d.states.GetProjectionStatesWithGaps(ctx, d.projection)
+}
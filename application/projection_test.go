@@ -0,0 +1,90 @@
+package application_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/kammeph/school-book-storage-service/application"
+	"github.com/kammeph/school-book-storage-service/domain"
+	"github.com/kammeph/school-book-storage-service/infrastructure/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+type countingHandler struct {
+	versions []int
+}
+
+func (h *countingHandler) Handle(ctx context.Context, eventBytes []byte) error {
+	event := &domain.EventModel{}
+	if err := json.Unmarshal(eventBytes, event); err != nil {
+		return err
+	}
+	h.versions = append(h.versions, event.EventVersion())
+	return nil
+}
+
+func TestProjectionStateTrack(t *testing.T) {
+	tests := []struct {
+		name            string
+		versions        []int
+		applied         []bool
+		version         int
+		missingVersions []int
+	}{
+		{
+			name:            "versions in order",
+			versions:        []int{1, 2, 3},
+			applied:         []bool{true, true, true},
+			version:         3,
+			missingVersions: []int{},
+		},
+		{
+			name:            "redelivered version",
+			versions:        []int{1, 2, 2},
+			applied:         []bool{true, true, false},
+			version:         2,
+			missingVersions: []int{},
+		},
+		{
+			name:            "gap in versions",
+			versions:        []int{1, 4},
+			applied:         []bool{true, true},
+			version:         4,
+			missingVersions: []int{2, 3},
+		},
+		{
+			name:            "missing version arrives late",
+			versions:        []int{1, 4, 3, 3},
+			applied:         []bool{true, true, false, false},
+			version:         4,
+			missingVersions: []int{2, 3},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			state := application.NewProjectionState("test", "aggregate")
+			for idx, version := range test.versions {
+				assert.Equal(t, test.applied[idx], state.Track(version))
+			}
+			assert.Equal(t, test.version, state.Version)
+			assert.Equal(t, test.missingVersions, state.MissingVersions)
+			assert.Equal(t, len(test.missingVersions) > 0, state.HasGaps())
+		})
+	}
+}
+
+func TestGapDetector(t *testing.T) {
+	ctx := context.Background()
+	handler := &countingHandler{}
+	detector := application.NewGapDetector("test", memory.NewMemoryProjectionStateRepository(), handler)
+	for _, version := range []int{1, 2, 2, 5, 1, 3} {
+		eventBytes, _ := json.Marshal(domain.EventModel{ID: "aggregate", Version: version, Type: "TEST"})
+		assert.NoError(t, detector.Handle(ctx, eventBytes))
+	}
+	assert.Equal(t, []int{1, 2, 5}, handler.versions)
+	gaps, err := detector.Gaps(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, gaps, 1)
+	assert.Equal(t, []int{3, 4}, gaps[0].MissingVersions)
+}
@@ -0,0 +1,192 @@
+package pupilapp
+
+import (
+	"context"
+	"time"
+
+	"github.com/kammeph/school-book-storage-service/application"
+	"github.com/kammeph/school-book-storage-service/domain/classdomain"
+	"github.com/kammeph/school-book-storage-service/domain/pupildomain"
+)
+
+type PupilCommandHandlers struct {
+	EnrolPupilHandler  EnrolPupilCommandHandler
+	MovePupilHandler   MovePupilCommandHandler
+	LeaveSchoolHandler LeaveSchoolCommandHandler
+}
+
+func NewPupilCommandHandlers(
+	store application.Store,
+	publisher application.EventPublisher,
+	classStore application.Store,
+	classPublisher application.EventPublisher,
+) PupilCommandHandlers {
+	return PupilCommandHandlers{
+		EnrolPupilHandler:  NewEnrolPupilCommandHandler(store, publisher, classStore, classPublisher),
+		MovePupilHandler:   NewMovePupilCommandHandler(store, publisher, classStore, classPublisher),
+		LeaveSchoolHandler: NewLeaveSchoolCommandHandler(store, publisher, classStore, classPublisher),
+	}
+}
+
+// membershipModel loads and saves the pupil and the class aggregate of a
+// school. Every change of the class of a pupil is mirrored in the classes, so
+// their number of pupils follows the membership. The pupils are saved first
+// as they guard the membership.
+type membershipModel struct {
+	pupils  *application.CommandHandlerModel
+	classes *application.CommandHandlerModel
+}
+
+func newMembershipModel(
+	store application.Store,
+	publisher application.EventPublisher,
+	classStore application.Store,
+	classPublisher application.EventPublisher,
+) membershipModel {
+	return membershipModel{
+		pupils:  application.NewCommandHandlerModel(store, publisher),
+		classes: application.NewCommandHandlerModel(classStore, classPublisher),
+	}
+}
+
+func (m membershipModel) load(ctx context.Context, schoolID string) (*pupildomain.SchoolPupilAggregate, *classdomain.SchoolClassAggregate, error) {
+	pupils := pupildomain.NewSchoolPupilAggregateWithID(schoolID)
+	if err := m.pupils.LoadAggregate(ctx, pupils); err != nil {
+		return nil, nil, err
+	}
+	classes := classdomain.NewSchoolClassAggregateWithID(schoolID)
+	if err := m.classes.LoadAggregate(ctx, classes); err != nil {
+		return nil, nil, err
+	}
+	return pupils, classes, nil
+}
+
+func (m membershipModel) save(ctx context.Context, pupils *pupildomain.SchoolPupilAggregate, classes *classdomain.SchoolClassAggregate) error {
+	if err := m.pupils.SaveAndPublish(ctx, pupils); err != nil {
+		return err
+	}
+	return m.classes.SaveAndPublish(ctx, classes)
+}
+
+type EnrolPupilCommand struct {
+	application.CommandModel
+	FirstName   string    `json:"firstName"`
+	LastName    string    `json:"lastName"`
+	DateOfBirth time.Time `json:"dateOfBirth"`
+	ClassID     string    `json:"classId"`
+	EntryDate   time.Time `json:"entryDate"`
+}
+
+type EnrolPupilCommandHandler struct {
+	membershipModel
+}
+
+func NewEnrolPupilCommandHandler(
+	store application.Store,
+	publisher application.EventPublisher,
+	classStore application.Store,
+	classPublisher application.EventPublisher,
+) EnrolPupilCommandHandler {
+	return EnrolPupilCommandHandler{newMembershipModel(store, publisher, classStore, classPublisher)}
+}
+
+// Handle enrols the pupil in the class. A missing entry date defaults to now.
+func (h EnrolPupilCommandHandler) Handle(ctx context.Context, command EnrolPupilCommand) (string, error) {
+	pupils, classes, err := h.load(ctx, command.AggregateID())
+	if err != nil {
+		return "", err
+	}
+	entryDate := command.EntryDate
+	if entryDate.IsZero() {
+		entryDate = time.Now()
+	}
+	pupilID, err := pupils.EnrolPupil(command.FirstName, command.LastName, command.DateOfBirth, command.ClassID, entryDate)
+	if err != nil {
+		return "", err
+	}
+	if err := classes.AddPupil(command.ClassID, pupilID); err != nil {
+		return "", err
+	}
+	if err := h.save(ctx, pupils, classes); err != nil {
+		return "", err
+	}
+	return pupilID, nil
+}
+
+type MovePupilCommand struct {
+	application.CommandModel
+	PupilID string `json:"pupilId"`
+	ClassID string `json:"classId"`
+	Reason  string `json:"reason"`
+}
+
+type MovePupilCommandHandler struct {
+	membershipModel
+}
+
+func NewMovePupilCommandHandler(
+	store application.Store,
+	publisher application.EventPublisher,
+	classStore application.Store,
+	classPublisher application.EventPublisher,
+) MovePupilCommandHandler {
+	return MovePupilCommandHandler{newMembershipModel(store, publisher, classStore, classPublisher)}
+}
+
+func (h MovePupilCommandHandler) Handle(ctx context.Context, command MovePupilCommand) error {
+	pupils, classes, err := h.load(ctx, command.AggregateID())
+	if err != nil {
+		return err
+	}
+	fromClassID, err := pupils.MovePupil(command.PupilID, command.ClassID, command.Reason)
+	if err != nil {
+		return err
+	}
+	if err := classes.RemovePupil(fromClassID, command.PupilID); err != nil {
+		return err
+	}
+	if err := classes.AddPupil(command.ClassID, command.PupilID); err != nil {
+		return err
+	}
+	return h.save(ctx, pupils, classes)
+}
+
+type LeaveSchoolCommand struct {
+	application.CommandModel
+	PupilID  string    `json:"pupilId"`
+	ExitDate time.Time `json:"exitDate"`
+	Reason   string    `json:"reason"`
+}
+
+type LeaveSchoolCommandHandler struct {
+	membershipModel
+}
+
+func NewLeaveSchoolCommandHandler(
+	store application.Store,
+	publisher application.EventPublisher,
+	classStore application.Store,
+	classPublisher application.EventPublisher,
+) LeaveSchoolCommandHandler {
+	return LeaveSchoolCommandHandler{newMembershipModel(store, publisher, classStore, classPublisher)}
+}
+
+// Handle records the exit of the pupil. A missing exit date defaults to now.
+func (h LeaveSchoolCommandHandler) Handle(ctx context.Context, command LeaveSchoolCommand) error {
+	pupils, classes, err := h.load(ctx, command.AggregateID())
+	if err != nil {
+		return err
+	}
+	exitDate := command.ExitDate
+	if exitDate.IsZero() {
+		exitDate = time.Now()
+	}
+	classID, err := pupils.LeaveSchool(command.PupilID, exitDate, command.Reason)
+	if err != nil {
+		return err
+	}
+	if err := classes.RemovePupil(classID, command.PupilID); err != nil {
+		return err
+	}
+	return h.save(ctx, pupils, classes)
+}
@@ -0,0 +1,108 @@
+package pupilapp_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/kammeph/school-book-storage-service/application"
+	"github.com/kammeph/school-book-storage-service/application/pupilapp"
+	"github.com/kammeph/school-book-storage-service/domain"
+	"github.com/kammeph/school-book-storage-service/domain/classdomain"
+	"github.com/kammeph/school-book-storage-service/domain/pupildomain"
+	"github.com/kammeph/school-book-storage-service/infrastructure/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+var dateOfBirth = time.Date(2012, 3, 14, 0, 0, 0, 0, time.UTC)
+
+func newPupilCommandHandlers() (pupilapp.PupilCommandHandlers, *memory.MemoryStore, *memory.MemoryStore) {
+	classStore := memory.NewMemoryStoreWithEvents([]domain.Event{
+		&domain.EventModel{
+			ID:      "school",
+			Type:    classdomain.ClassCreated,
+			Version: 1,
+			At:      time.Now(),
+			Data:    "{\"schoolId\":\"school\",\"classId\":\"5a\",\"grade\":5,\"letter\":\"a\",\"numberOfPupils\":0}",
+		},
+		&domain.EventModel{
+			ID:      "school",
+			Type:    classdomain.ClassCreated,
+			Version: 2,
+			At:      time.Now(),
+			Data:    "{\"schoolId\":\"school\",\"classId\":\"5b\",\"grade\":5,\"letter\":\"b\",\"numberOfPupils\":0}",
+		},
+	})
+	store := memory.NewMemoryStore()
+	return pupilapp.NewPupilCommandHandlers(store, nil, classStore, nil), store, classStore
+}
+
+func loadAggregates(t *testing.T, store, classStore application.Store) (*pupildomain.SchoolPupilAggregate, *classdomain.SchoolClassAggregate) {
+	ctx := context.Background()
+	pupils := pupildomain.NewSchoolPupilAggregateWithID("school")
+	assert.Nil(t, application.NewCommandHandlerModel(store, nil).LoadAggregate(ctx, pupils))
+	classes := classdomain.NewSchoolClassAggregateWithID("school")
+	assert.Nil(t, application.NewCommandHandlerModel(classStore, nil).LoadAggregate(ctx, classes))
+	return pupils, classes
+}
+
+func enrol(handlers pupilapp.PupilCommandHandlers, firstName, classID string) (string, error) {
+	command := pupilapp.EnrolPupilCommand{
+		CommandModel: application.CommandModel{ID: "school"},
+		FirstName:    firstName,
+		LastName:     "Lovelace",
+		DateOfBirth:  dateOfBirth,
+		ClassID:      classID,
+	}
+	return handlers.EnrolPupilHandler.Handle(context.Background(), command)
+}
+
+func TestEnrolPupil(t *testing.T) {
+	handlers, store, classStore := newPupilCommandHandlers()
+	_, err := enrol(handlers, "Ada", "5a")
+	assert.Nil(t, err)
+	_, err = enrol(handlers, "Byron", "5a")
+	assert.Nil(t, err)
+	_, err = enrol(handlers, "Ada", "5b")
+	assert.Error(t, err)
+	_, err = enrol(handlers, "Grace", "unknown")
+	assert.Error(t, err)
+
+	pupils, classes := loadAggregates(t, store, classStore)
+	assert.Len(t, pupils.Pupils, 2)
+	assert.Equal(t, 2, classes.Classes[0].NumberOfPupils)
+	assert.Equal(t, 0, classes.Classes[1].NumberOfPupils)
+}
+
+func TestMovePupil(t *testing.T) {
+	ctx := context.Background()
+	handlers, store, classStore := newPupilCommandHandlers()
+	pupilID, err := enrol(handlers, "Ada", "5a")
+	assert.Nil(t, err)
+
+	move := pupilapp.MovePupilCommand{CommandModel: application.CommandModel{ID: "school"}, PupilID: pupilID, ClassID: "5b", Reason: "language course"}
+	assert.Nil(t, handlers.MovePupilHandler.Handle(ctx, move))
+	assert.Error(t, handlers.MovePupilHandler.Handle(ctx, move))
+	move.ClassID = "unknown"
+	assert.Error(t, handlers.MovePupilHandler.Handle(ctx, move))
+
+	pupils, classes := loadAggregates(t, store, classStore)
+	assert.Equal(t, "5b", pupils.Pupils[0].ClassID)
+	assert.Equal(t, 0, classes.Classes[0].NumberOfPupils)
+	assert.Equal(t, []string{pupilID}, classes.Classes[1].Pupils)
+}
+
+func TestLeaveSchool(t *testing.T) {
+	ctx := context.Background()
+	handlers, store, classStore := newPupilCommandHandlers()
+	pupilID, err := enrol(handlers, "Ada", "5a")
+	assert.Nil(t, err)
+
+	leave := pupilapp.LeaveSchoolCommand{CommandModel: application.CommandModel{ID: "school"}, PupilID: pupilID, Reason: "moved away"}
+	assert.Nil(t, handlers.LeaveSchoolHandler.Handle(ctx, leave))
+	assert.Error(t, handlers.LeaveSchoolHandler.Handle(ctx, leave))
+
+	pupils, classes := loadAggregates(t, store, classStore)
+	assert.True(t, pupils.Pupils[0].Left())
+	assert.Equal(t, 0, classes.Classes[0].NumberOfPupils)
+}
@@ -0,0 +1,68 @@
+package pupilapp
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/kammeph/school-book-storage-service/application"
+	"github.com/kammeph/school-book-storage-service/domain"
+	"github.com/kammeph/school-book-storage-service/domain/pupildomain"
+)
+
+type PupilEventHandler struct {
+	repository PupilRepository
+}
+
+func NewPupilEventHandler(repository PupilRepository) application.EventHandler {
+	return &PupilEventHandler{repository}
+}
+
+func (h PupilEventHandler) Handle(ctx context.Context, eventBytes []byte) error {
+	event := &domain.EventModel{}
+	if err := json.Unmarshal(eventBytes, event); err != nil {
+		return err
+	}
+	switch event.EventType() {
+	case pupildomain.PupilEnrolled:
+		return h.handlePupilEnrolled(ctx, event)
+	case pupildomain.PupilMoved:
+		return h.handlePupilMoved(ctx, event)
+	case pupildomain.PupilLeft:
+		return h.handlePupilLeft(ctx, event)
+	default:
+		return nil
+	}
+}
+
+func (h PupilEventHandler) handlePupilEnrolled(ctx context.Context, event domain.Event) error {
+	pupilEnrolled := pupildomain.PupilEnrolledEvent{}
+	if err := event.GetJsonData(&pupilEnrolled); err != nil {
+		return err
+	}
+	pupil := pupildomain.NewPupilProjection(
+		pupilEnrolled.SchoolID,
+		pupilEnrolled.PupilID,
+		pupilEnrolled.FirstName,
+		pupilEnrolled.LastName,
+		pupilEnrolled.DateOfBirth,
+		pupilEnrolled.ClassID,
+		pupilEnrolled.EntryDate,
+		event.EventVersion())
+	return h.repository.UpsertPupil(ctx, pupil)
+}
+
+func (h PupilEventHandler) handlePupilMoved(ctx context.Context, event domain.Event) error {
+	pupilMoved := pupildomain.PupilMovedEvent{}
+	if err := event.GetJsonData(&pupilMoved); err != nil {
+		return err
+	}
+	return h.repository.UpdatePupilClass(ctx, pupilMoved.PupilID, pupilMoved.ToClassID, event.EventVersion())
+}
+
+func (h PupilEventHandler) handlePupilLeft(ctx context.Context, event domain.Event) error {
+	pupilLeft := pupildomain.PupilLeftEvent{}
+	if err := event.GetJsonData(&pupilLeft); err != nil {
+		return err
+	}
+	return h.repository.UpdatePupilExitDate(ctx, pupilLeft.PupilID, pupilLeft.ExitDate, event.EventVersion())
+}
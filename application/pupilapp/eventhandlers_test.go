@@ -0,0 +1,56 @@
+package pupilapp_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/kammeph/school-book-storage-service/application/pupilapp"
+	"github.com/kammeph/school-book-storage-service/domain"
+	"github.com/kammeph/school-book-storage-service/domain/pupildomain"
+	"github.com/kammeph/school-book-storage-service/infrastructure/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+func pupilEvent(version int, eventType, data string) []byte {
+	eventBytes, _ := json.Marshal(domain.EventModel{
+		ID:      "school",
+		Type:    eventType,
+		Version: version,
+		At:      time.Now(),
+		Data:    data,
+	})
+	return eventBytes
+}
+
+func TestHandlePupilEvents(t *testing.T) {
+	ctx := context.Background()
+	repository := memory.NewMemoryPupilRepository()
+	handler := pupilapp.NewPupilEventHandler(repository)
+	events := [][]byte{
+		pupilEvent(1, pupildomain.PupilEnrolled, "{\"schoolId\":\"school\",\"pupilId\":\"pupil1\",\"firstName\":\"Ada\",\"lastName\":\"Lovelace\",\"dateOfBirth\":\"2012-03-14T00:00:00Z\",\"classId\":\"5a\",\"entryDate\":\"2022-08-01T00:00:00Z\"}"),
+		pupilEvent(2, pupildomain.PupilEnrolled, "{\"schoolId\":\"school\",\"pupilId\":\"pupil2\",\"firstName\":\"Grace\",\"lastName\":\"Hopper\",\"dateOfBirth\":\"2012-12-09T00:00:00Z\",\"classId\":\"5a\",\"entryDate\":\"2022-08-01T00:00:00Z\"}"),
+		pupilEvent(3, pupildomain.PupilMoved, "{\"pupilId\":\"pupil1\",\"fromClassId\":\"5a\",\"toClassId\":\"5b\",\"reason\":\"language course\"}"),
+		pupilEvent(4, pupildomain.PupilLeft, "{\"pupilId\":\"pupil2\",\"classId\":\"5a\",\"exitDate\":\"2023-07-31T00:00:00Z\",\"reason\":\"moved away\"}"),
+	}
+	for _, event := range events {
+		assert.Nil(t, handler.Handle(ctx, event))
+	}
+
+	pupils, err := repository.GetPupilsBySchoolID(ctx, "school")
+	assert.Nil(t, err)
+	assert.Len(t, pupils, 2)
+	inClass, err := repository.GetPupilsByClassID(ctx, "school", "5a")
+	assert.Nil(t, err)
+	assert.Empty(t, inClass)
+	inClass, err = repository.GetPupilsByClassID(ctx, "school", "5b")
+	assert.Nil(t, err)
+	assert.Len(t, inClass, 1)
+	assert.Equal(t, "pupil1", inClass[0].PupilID)
+	pupil, err := repository.GetPupilByID(ctx, "school", "pupil2")
+	assert.Nil(t, err)
+	assert.NotNil(t, pupil.ExitDate)
+
+	assert.Error(t, handler.Handle(ctx, pupilEvent(5, pupildomain.PupilEnrolled, "{\"pupilId\":")))
+}
@@ -0,0 +1,84 @@
+package pupilapp
+
+import (
+	"context"
+
+	"github.com/kammeph/school-book-storage-service/application"
+	"github.com/kammeph/school-book-storage-service/domain/pupildomain"
+)
+
+type PupilQueryHandlers struct {
+	GetAllHandler       GetAllPupilsQueryHandler
+	GetByClassHandler   GetPupilsByClassQueryHandler
+	GetPupilByIDHandler GetPupilByIDQueryHandler
+}
+
+func NewPupilQueryHandlers(repository PupilRepository) PupilQueryHandlers {
+	return PupilQueryHandlers{
+		GetAllHandler:       NewGetAllPupilsQueryHandler(repository),
+		GetByClassHandler:   NewGetPupilsByClassQueryHandler(repository),
+		GetPupilByIDHandler: NewGetPupilByIDQueryHandler(repository),
+	}
+}
+
+type GetAllPupils struct {
+	application.QueryModel
+}
+
+func NewGetAllPupils(aggregateID string) GetAllPupils {
+	return GetAllPupils{QueryModel: application.QueryModel{ID: aggregateID}}
+}
+
+type GetAllPupilsQueryHandler struct {
+	repository PupilRepository
+}
+
+func NewGetAllPupilsQueryHandler(repository PupilRepository) GetAllPupilsQueryHandler {
+	return GetAllPupilsQueryHandler{repository: repository}
+}
+
+func (h GetAllPupilsQueryHandler) Handle(ctx context.Context, query GetAllPupils) ([]pupildomain.PupilProjection, error) {
+	return h.repository.GetPupilsBySchoolID(ctx, query.AggregateID())
+}
+
+type GetPupilsByClass struct {
+	application.QueryModel
+	ClassID string
+}
+
+func NewGetPupilsByClass(aggregateID, classID string) GetPupilsByClass {
+	return GetPupilsByClass{QueryModel: application.QueryModel{ID: aggregateID}, ClassID: classID}
+}
+
+type GetPupilsByClassQueryHandler struct {
+	repository PupilRepository
+}
+
+func NewGetPupilsByClassQueryHandler(repository PupilRepository) GetPupilsByClassQueryHandler {
+	return GetPupilsByClassQueryHandler{repository: repository}
+}
+
+func (h GetPupilsByClassQueryHandler) Handle(ctx context.Context, query GetPupilsByClass) ([]pupildomain.PupilProjection, error) {
+	return h.repository.GetPupilsByClassID(ctx, query.AggregateID(), query.ClassID)
+}
+
+type GetPupilByID struct {
+	application.QueryModel
+	PupilID string
+}
+
+func NewGetPupilByID(aggregateID, pupilID string) GetPupilByID {
+	return GetPupilByID{QueryModel: application.QueryModel{ID: aggregateID}, PupilID: pupilID}
+}
+
+type GetPupilByIDQueryHandler struct {
+	repository PupilRepository
+}
+
+func NewGetPupilByIDQueryHandler(repository PupilRepository) GetPupilByIDQueryHandler {
+	return GetPupilByIDQueryHandler{repository: repository}
+}
+
+func (h GetPupilByIDQueryHandler) Handle(ctx context.Context, query GetPupilByID) (pupildomain.PupilProjection, error) {
+	return h.repository.GetPupilByID(ctx, query.AggregateID(), query.PupilID)
+}
@@ -0,0 +1,17 @@
+package pupilapp
+
+import (
+	"context"
+	"time"
+
+	"github.com/kammeph/school-book-storage-service/domain/pupildomain"
+)
+
+type PupilRepository interface {
+	GetPupilsBySchoolID(ctx context.Context, schoolID string) ([]pupildomain.PupilProjection, error)
+	GetPupilsByClassID(ctx context.Context, schoolID, classID string) ([]pupildomain.PupilProjection, error)
+	GetPupilByID(ctx context.Context, schoolID, pupilID string) (pupildomain.PupilProjection, error)
+	UpsertPupil(ctx context.Context, pupil pupildomain.PupilProjection) error
+	UpdatePupilClass(ctx context.Context, pupilID, classID string, version int) error
+	UpdatePupilExitDate(ctx context.Context, pupilID string, exitDate time.Time, version int) error
+}
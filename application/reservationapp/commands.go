@@ -0,0 +1,128 @@
+package reservationapp
+
+import (
+	"context"
+	"time"
+
+	"github.com/kammeph/school-book-storage-service/application"
+	"github.com/kammeph/school-book-storage-service/domain/classdomain"
+	"github.com/kammeph/school-book-storage-service/domain/reservationdomain"
+	"github.com/kammeph/school-book-storage-service/domain/storagedomain"
+	"github.com/kammeph/school-book-storage-service/fp"
+)
+
+type ReservationCommandHandlers struct {
+	ReserveBooksHandler      ReserveBooksCommandHandler
+	CancelReservationHandler CancelReservationCommandHandler
+}
+
+func NewReservationCommandHandlers(
+	store application.Store,
+	publisher application.EventPublisher,
+	storageStore application.Store,
+	classStore application.Store,
+) ReservationCommandHandlers {
+	return ReservationCommandHandlers{
+		ReserveBooksHandler:      NewReserveBooksCommandHandler(store, publisher, storageStore, classStore),
+		CancelReservationHandler: NewCancelReservationCommandHandler(store, publisher),
+	}
+}
+
+type ReserveBooksCommand struct {
+	application.CommandModel
+	ClassID   string    `json:"classId"`
+	TeacherID string    `json:"teacherId"`
+	BookID    string    `json:"bookId"`
+	StorageID string    `json:"storageId"`
+	Quantity  int       `json:"quantity"`
+	From      time.Time `json:"from"`
+	Until     time.Time `json:"until"`
+}
+
+type ReserveBooksCommandHandler struct {
+	*application.CommandHandlerModel
+	storages *application.CommandHandlerModel
+	classes  *application.CommandHandlerModel
+}
+
+func NewReserveBooksCommandHandler(
+	store application.Store,
+	publisher application.EventPublisher,
+	storageStore application.Store,
+	classStore application.Store,
+) ReserveBooksCommandHandler {
+	return ReserveBooksCommandHandler{
+		CommandHandlerModel: application.NewCommandHandlerModel(store, publisher),
+		storages:            application.NewCommandHandlerModel(storageStore, nil),
+		classes:             application.NewCommandHandlerModel(classStore, nil),
+	}
+}
+
+// Handle reserves the books after checking that the storage and, for a class,
+// the class exist in the school. The reservation has to fit into the copies the
+// storage has right now.
+func (h ReserveBooksCommandHandler) Handle(ctx context.Context, command ReserveBooksCommand) (string, error) {
+	storages := storagedomain.NewSchoolStorageAggregateWithID(command.AggregateID())
+	if err := h.storages.LoadAggregate(ctx, storages); err != nil {
+		return "", err
+	}
+	storage := fp.Find(storages.Storages, func(s storagedomain.Storage) bool { return s.ID == command.StorageID })
+	if storage == nil {
+		return "", storagedomain.ErrStorageIDNotFound(command.StorageID)
+	}
+	if command.ClassID != "" {
+		classes := classdomain.NewSchoolClassAggregateWithID(command.AggregateID())
+		if err := h.classes.LoadAggregate(ctx, classes); err != nil {
+			return "", err
+		}
+		if !fp.Some(classes.Classes, func(c classdomain.Class) bool { return c.ID == command.ClassID }) {
+			return "", classdomain.ErrClassWithIDNotFound(command.ClassID)
+		}
+	}
+	aggregate := reservationdomain.NewSchoolReservationAggregateWithID(command.AggregateID())
+	if err := h.LoadAggregate(ctx, aggregate); err != nil {
+		return "", err
+	}
+	reservationID, err := aggregate.Reserve(
+		command.ClassID,
+		command.TeacherID,
+		command.BookID,
+		command.StorageID,
+		command.Quantity,
+		command.From,
+		command.Until,
+		time.Now(),
+		storage.Available(command.BookID))
+	if err != nil {
+		return "", err
+	}
+	if err := h.SaveAndPublish(ctx, aggregate); err != nil {
+		return "", err
+	}
+	return reservationID, nil
+}
+
+type CancelReservationCommand struct {
+	application.CommandModel
+	ReservationID string `json:"reservationId"`
+	Reason        string `json:"reason"`
+}
+
+type CancelReservationCommandHandler struct {
+	*application.CommandHandlerModel
+}
+
+func NewCancelReservationCommandHandler(store application.Store, publisher application.EventPublisher) CancelReservationCommandHandler {
+	return CancelReservationCommandHandler{application.NewCommandHandlerModel(store, publisher)}
+}
+
+func (h CancelReservationCommandHandler) Handle(ctx context.Context, command CancelReservationCommand) error {
+	aggregate := reservationdomain.NewSchoolReservationAggregateWithID(command.AggregateID())
+	if err := h.LoadAggregate(ctx, aggregate); err != nil {
+		return err
+	}
+	if err := aggregate.CancelReservation(command.ReservationID, command.Reason, time.Now()); err != nil {
+		return err
+	}
+	return h.SaveAndPublish(ctx, aggregate)
+}
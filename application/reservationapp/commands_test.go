@@ -0,0 +1,128 @@
+package reservationapp_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/kammeph/school-book-storage-service/application"
+	"github.com/kammeph/school-book-storage-service/application/reservationapp"
+	"github.com/kammeph/school-book-storage-service/domain"
+	"github.com/kammeph/school-book-storage-service/domain/classdomain"
+	"github.com/kammeph/school-book-storage-service/domain/reservationdomain"
+	"github.com/kammeph/school-book-storage-service/domain/storagedomain"
+	"github.com/kammeph/school-book-storage-service/infrastructure/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	schoolYearStart = time.Now().AddDate(0, 1, 0)
+	schoolYearEnd   = time.Now().AddDate(1, 0, 0)
+)
+
+func newHandlers() (reservationapp.ReservationCommandHandlers, *memory.MemoryStore) {
+	store := memory.NewMemoryStore()
+	storageStore := memory.NewMemoryStoreWithEvents([]domain.Event{
+		&domain.EventModel{
+			ID:      "school",
+			Type:    storagedomain.StorageAdded,
+			Version: 1,
+			At:      time.Now(),
+			Data:    "{\"schoolId\":\"school\",\"storageId\":\"storage\",\"name\":\"closet\",\"location\":\"room 1\"}",
+		},
+		&domain.EventModel{
+			ID:      "school",
+			Type:    storagedomain.BooksPut,
+			Version: 2,
+			At:      time.Now(),
+			Data:    "{\"storageId\":\"storage\",\"bookId\":\"book\",\"isbn\":\"123\",\"title\":\"math\",\"quantity\":30}",
+		},
+	})
+	classStore := memory.NewMemoryStoreWithEvents([]domain.Event{
+		&domain.EventModel{
+			ID:      "school",
+			Type:    classdomain.ClassCreated,
+			Version: 1,
+			At:      time.Now(),
+			Data:    "{\"schoolId\":\"school\",\"classId\":\"class\",\"grade\":5,\"letter\":\"a\",\"numberOfPupils\":25}",
+		},
+	})
+	return reservationapp.NewReservationCommandHandlers(store, nil, storageStore, classStore), store
+}
+
+func reserve(handlers reservationapp.ReservationCommandHandlers, classID, storageID string, quantity int) (string, error) {
+	command := reservationapp.ReserveBooksCommand{
+		ClassID:   classID,
+		BookID:    "book",
+		StorageID: storageID,
+		Quantity:  quantity,
+		From:      schoolYearStart,
+		Until:     schoolYearEnd,
+	}
+	command.ID = "school"
+	return handlers.ReserveBooksHandler.Handle(context.Background(), command)
+}
+
+func loadReservations(t *testing.T, store application.Store) *reservationdomain.SchoolReservationAggregate {
+	reservations := reservationdomain.NewSchoolReservationAggregateWithID("school")
+	assert.Nil(t, application.NewCommandHandlerModel(store, nil).LoadAggregate(context.Background(), reservations))
+	return reservations
+}
+
+func TestReserveBooks(t *testing.T) {
+	tests := []struct {
+		name      string
+		classID   string
+		storageID string
+		quantity  int
+		err       error
+	}{
+		{name: "reserve books", classID: "class", storageID: "storage", quantity: 20},
+		{name: "class not found", classID: "unknown", storageID: "storage", quantity: 20, err: classdomain.ErrClassWithIDNotFound("unknown")},
+		{name: "storage not found", classID: "class", storageID: "unknown", quantity: 20, err: storagedomain.ErrStorageIDNotFound("unknown")},
+		{
+			name:      "more books than in storage",
+			classID:   "class",
+			storageID: "storage",
+			quantity:  31,
+			err:       reservationdomain.ErrReservationConflict("book", "storage", 30, 31),
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			handlers, store := newHandlers()
+			reservationID, err := reserve(handlers, test.classID, test.storageID, test.quantity)
+			assert.Equal(t, test.err, err)
+			reservations := loadReservations(t, store)
+			if test.err != nil {
+				assert.Empty(t, reservations.Reservations)
+				return
+			}
+			assert.Len(t, reservations.Reservations, 1)
+			assert.Equal(t, reservationID, reservations.Reservations[0].ID)
+		})
+	}
+}
+
+func TestReserveConflictingBooks(t *testing.T) {
+	handlers, store := newHandlers()
+	_, err := reserve(handlers, "class", "storage", 20)
+	assert.Nil(t, err)
+	_, err = reserve(handlers, "class", "storage", 11)
+	assert.Equal(t, reservationdomain.ErrReservationConflict("book", "storage", 30, 31), err)
+	assert.Len(t, loadReservations(t, store).Reservations, 1)
+}
+
+func TestCancelReservation(t *testing.T) {
+	handlers, store := newHandlers()
+	reservationID, err := reserve(handlers, "class", "storage", 20)
+	assert.Nil(t, err)
+	command := reservationapp.CancelReservationCommand{ReservationID: reservationID, Reason: "class dissolved"}
+	command.ID = "school"
+	assert.Nil(t, handlers.CancelReservationHandler.Handle(context.Background(), command))
+	assert.Equal(t, reservationdomain.Cancelled, loadReservations(t, store).Reservations[0].Status(time.Now()))
+	assert.Equal(t, reservationdomain.ErrReservationClosed(reservationID), handlers.CancelReservationHandler.Handle(context.Background(), command))
+
+	_, err = reserve(handlers, "class", "storage", 30)
+	assert.Nil(t, err)
+}
@@ -0,0 +1,83 @@
+package reservationapp
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/kammeph/school-book-storage-service/application"
+	"github.com/kammeph/school-book-storage-service/domain"
+	"github.com/kammeph/school-book-storage-service/domain/reservationdomain"
+)
+
+type ReservationEventHandler struct {
+	repository ReservationRepository
+}
+
+func NewReservationEventHandler(repository ReservationRepository) application.EventHandler {
+	return &ReservationEventHandler{repository}
+}
+
+func (h ReservationEventHandler) Handle(ctx context.Context, eventBytes []byte) error {
+	event := &domain.EventModel{}
+	if err := json.Unmarshal(eventBytes, event); err != nil {
+		return err
+	}
+	switch event.EventType() {
+	case reservationdomain.ReservationMade:
+		return h.handleReservationMade(ctx, event)
+	case reservationdomain.ReservationCancelled:
+		return h.handleReservationCancelled(ctx, event)
+	case reservationdomain.ReservationFulfilled:
+		return h.handleReservationFulfilled(ctx, event)
+	default:
+		return nil
+	}
+}
+
+func (h ReservationEventHandler) handleReservationMade(ctx context.Context, event domain.Event) error {
+	reservationMade := reservationdomain.ReservationMadeEvent{}
+	if err := event.GetJsonData(&reservationMade); err != nil {
+		return err
+	}
+	reservation := reservationdomain.NewReservationProjection(
+		reservationMade.SchoolID,
+		reservationMade.ReservationID,
+		reservationMade.ClassID,
+		reservationMade.TeacherID,
+		reservationMade.BookID,
+		reservationMade.StorageID,
+		reservationMade.Quantity,
+		reservationMade.From,
+		reservationMade.Until,
+		event.EventVersion())
+	return h.repository.UpsertReservation(ctx, reservation)
+}
+
+func (h ReservationEventHandler) handleReservationCancelled(ctx context.Context, event domain.Event) error {
+	reservationCancelled := reservationdomain.ReservationCancelledEvent{}
+	if err := event.GetJsonData(&reservationCancelled); err != nil {
+		return err
+	}
+	return h.repository.UpdateReservationCancelled(ctx, reservationCancelled.ReservationID, event.EventAt(), event.EventVersion())
+}
+
+// handleReservationFulfilled adds the fulfilled copies to the reservation.
+// Events the reservation already reflects are skipped.
+func (h ReservationEventHandler) handleReservationFulfilled(ctx context.Context, event domain.Event) error {
+	reservationFulfilled := reservationdomain.ReservationFulfilledEvent{}
+	if err := event.GetJsonData(&reservationFulfilled); err != nil {
+		return err
+	}
+	reservation, err := h.repository.GetReservationByID(ctx, event.AggregateID(), reservationFulfilled.ReservationID)
+	if err != nil {
+		return err
+	}
+	if reservation.Version >= event.EventVersion() {
+		return nil
+	}
+	return h.repository.UpdateReservationFulfilled(
+		ctx,
+		reservationFulfilled.ReservationID,
+		reservation.Fulfilled+reservationFulfilled.Quantity,
+		event.EventVersion())
+}
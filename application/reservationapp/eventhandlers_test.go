@@ -0,0 +1,111 @@
+package reservationapp_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/kammeph/school-book-storage-service/application"
+	"github.com/kammeph/school-book-storage-service/application/reservationapp"
+	"github.com/kammeph/school-book-storage-service/domain"
+	"github.com/kammeph/school-book-storage-service/domain/reservationdomain"
+	"github.com/kammeph/school-book-storage-service/domain/storagedomain"
+	"github.com/kammeph/school-book-storage-service/infrastructure/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+func reservationEvent(version int, eventType string, data interface{}) []byte {
+	jsonData, _ := json.Marshal(data)
+	eventBytes, _ := json.Marshal(domain.EventModel{
+		ID:      "school",
+		Type:    eventType,
+		Version: version,
+		At:      time.Now(),
+		Data:    string(jsonData),
+	})
+	return eventBytes
+}
+
+func reservationMade(reservationID, classID string, quantity int, from, until time.Time) reservationdomain.ReservationMadeEvent {
+	return reservationdomain.ReservationMadeEvent{
+		SchoolID:      "school",
+		ReservationID: reservationID,
+		ClassID:       classID,
+		BookID:        "book",
+		StorageID:     "storage",
+		Quantity:      quantity,
+		From:          from,
+		Until:         until,
+	}
+}
+
+func TestHandleReservationEvents(t *testing.T) {
+	ctx := context.Background()
+	repository := memory.NewMemoryReservationRepository()
+	handler := reservationapp.NewReservationEventHandler(repository)
+	now := time.Now()
+	events := [][]byte{
+		reservationEvent(1, reservationdomain.ReservationMade, reservationMade("first", "class", 20, now, schoolYearEnd)),
+		reservationEvent(2, reservationdomain.ReservationMade, reservationMade("second", "other", 10, schoolYearStart, schoolYearEnd)),
+		reservationEvent(3, reservationdomain.ReservationMade, reservationMade("past", "other", 5, now.AddDate(-1, 0, 0), now.AddDate(0, 0, -1))),
+		reservationEvent(4, reservationdomain.ReservationFulfilled, reservationdomain.ReservationFulfilledEvent{ReservationID: "first", Quantity: 5}),
+		reservationEvent(5, reservationdomain.ReservationCancelled, reservationdomain.ReservationCancelledEvent{ReservationID: "second", Reason: "class dissolved"}),
+	}
+	for _, event := range events {
+		assert.Nil(t, handler.Handle(ctx, event))
+	}
+	// a redelivered event must not fulfil the reservation twice
+	assert.Nil(t, handler.Handle(ctx, events[3]))
+
+	reservations, err := reservationapp.NewGetAllReservationsQueryHandler(repository).Handle(ctx, reservationapp.NewGetAllReservations("school"))
+	assert.Nil(t, err)
+	assert.Len(t, reservations, 3)
+	assert.Equal(t, 5, reservations[0].Fulfilled)
+	assert.Equal(t, reservationdomain.Active, reservations[0].Status)
+	assert.Equal(t, reservationdomain.Cancelled, reservations[1].Status)
+	assert.Equal(t, reservationdomain.Expired, reservations[2].Status)
+
+	storages := memory.NewMemoryRepositoryWithStorages([]storagedomain.StorageWithBooks{
+		{
+			SchoolID:  "school",
+			StorageID: "storage",
+			Books: []storagedomain.BookInStorage{
+				{
+					BookID:     "book",
+					Quantity:   16,
+					Conditions: []storagedomain.ConditionCount{{Condition: storagedomain.Lost, Quantity: 2}},
+				},
+			},
+		},
+	})
+	handlers := reservationapp.NewReservationQueryHandlers(repository, storages)
+	conflicts, err := handlers.GetConflictsHandler.Handle(ctx, reservationapp.NewGetReservationConflicts("school"))
+	assert.Nil(t, err)
+	assert.Len(t, conflicts, 1)
+	assert.Equal(t, 14, conflicts[0].Available)
+	assert.Equal(t, 15, conflicts[0].Reserved)
+	assert.Equal(t, []string{"first"}, conflicts[0].ReservationIDs)
+
+	assert.Error(t, handler.Handle(ctx, reservationEvent(6, reservationdomain.ReservationFulfilled, reservationdomain.ReservationFulfilledEvent{ReservationID: "unknown", Quantity: 1})))
+}
+
+func TestFulfilReservationsWithLentBooks(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	madeData, _ := json.Marshal(reservationMade("first", "class", 10, now.AddDate(0, 0, -1), now.AddDate(0, 1, 0)))
+	store := memory.NewMemoryStoreWithEvents([]domain.Event{
+		&domain.EventModel{ID: "school", Type: reservationdomain.ReservationMade, Version: 1, At: now, Data: string(madeData)},
+	})
+	handler := reservationapp.NewFulfilmentEventHandler(store, nil)
+	booksLent := storagedomain.BooksLentToClassEvent{StorageID: "storage", ClassID: "class", BookID: "book", Quantity: 4}
+	assert.NoError(t, handler.Handle(ctx, reservationEvent(7, storagedomain.BooksLentToClass, booksLent)))
+	// A redelivered event does not count the books again.
+	assert.NoError(t, handler.Handle(ctx, reservationEvent(7, storagedomain.BooksLentToClass, booksLent)))
+	assert.NoError(t, handler.Handle(ctx, reservationEvent(8, storagedomain.StorageAdded, storagedomain.StorageAddedEvent{})))
+
+	aggregate := reservationdomain.NewSchoolReservationAggregateWithID("school")
+	assert.NoError(t, application.NewCommandHandlerModel(store, nil).LoadAggregate(ctx, aggregate))
+	assert.Equal(t, 4, aggregate.Reservations[0].Fulfilled)
+	assert.Equal(t, 7, aggregate.LendingVersion)
+}
@@ -0,0 +1,51 @@
+package reservationapp
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/kammeph/school-book-storage-service/application"
+	"github.com/kammeph/school-book-storage-service/domain"
+	"github.com/kammeph/school-book-storage-service/domain/reservationdomain"
+	"github.com/kammeph/school-book-storage-service/domain/storagedomain"
+)
+
+// FulfilmentEventHandler counts the books lent to a class against the open
+// reservations of the class. It listens to the storage events, so lending
+// books only saves the storages and the classes.
+type FulfilmentEventHandler struct {
+	*application.CommandHandlerModel
+}
+
+func NewFulfilmentEventHandler(store application.Store, publisher application.EventPublisher) application.EventHandler {
+	return &FulfilmentEventHandler{application.NewCommandHandlerModel(store, publisher)}
+}
+
+func (h FulfilmentEventHandler) Handle(ctx context.Context, eventBytes []byte) error {
+	event := &domain.EventModel{}
+	if err := json.Unmarshal(eventBytes, event); err != nil {
+		return err
+	}
+	if event.EventType() != storagedomain.BooksLentToClass {
+		return nil
+	}
+	booksLent := storagedomain.BooksLentToClassEvent{}
+	if err := event.GetJsonData(&booksLent); err != nil {
+		return err
+	}
+	aggregate := reservationdomain.NewSchoolReservationAggregateWithID(event.AggregateID())
+	if err := h.LoadAggregate(ctx, aggregate); err != nil {
+		return err
+	}
+	err := aggregate.Fulfil(
+		event.EventVersion(),
+		booksLent.ClassID,
+		booksLent.StorageID,
+		booksLent.BookID,
+		booksLent.Quantity,
+		event.EventAt())
+	if err != nil {
+		return err
+	}
+	return h.SaveAndPublish(ctx, aggregate)
+}
@@ -0,0 +1,104 @@
+package reservationapp
+
+import (
+	"context"
+	"time"
+
+	"github.com/kammeph/school-book-storage-service/application"
+	"github.com/kammeph/school-book-storage-service/application/storageapp"
+	"github.com/kammeph/school-book-storage-service/domain/reservationdomain"
+	"github.com/kammeph/school-book-storage-service/domain/storagedomain"
+	"github.com/kammeph/school-book-storage-service/fp"
+)
+
+type ReservationQueryHandlers struct {
+	GetAllHandler       GetAllReservationsQueryHandler
+	GetConflictsHandler GetReservationConflictsQueryHandler
+}
+
+func NewReservationQueryHandlers(repository ReservationRepository, storages storageapp.StorageWithBooksRepository) ReservationQueryHandlers {
+	return ReservationQueryHandlers{
+		GetAllHandler:       NewGetAllReservationsQueryHandler(repository),
+		GetConflictsHandler: NewGetReservationConflictsQueryHandler(repository, storages),
+	}
+}
+
+type GetAllReservations struct {
+	application.QueryModel
+}
+
+func NewGetAllReservations(aggregateID string) GetAllReservations {
+	return GetAllReservations{QueryModel: application.QueryModel{ID: aggregateID}}
+}
+
+type GetAllReservationsQueryHandler struct {
+	repository ReservationRepository
+}
+
+func NewGetAllReservationsQueryHandler(repository ReservationRepository) GetAllReservationsQueryHandler {
+	return GetAllReservationsQueryHandler{repository: repository}
+}
+
+// Handle returns the reservations with their status right now, so expired
+// reservations show as such without being changed.
+func (h GetAllReservationsQueryHandler) Handle(ctx context.Context, query GetAllReservations) ([]reservationdomain.ReservationProjection, error) {
+	reservations, err := h.repository.GetReservationsBySchoolID(ctx, query.AggregateID())
+	if err != nil {
+		return nil, err
+	}
+	at := time.Now()
+	for idx := range reservations {
+		reservations[idx].Status = reservations[idx].Reservation().Status(at)
+	}
+	return reservations, nil
+}
+
+// GetReservationConflicts asks for the storages that have fewer copies than
+// their reservations need, e.g. because books were written off after they
+// were reserved.
+type GetReservationConflicts struct {
+	application.QueryModel
+}
+
+func NewGetReservationConflicts(aggregateID string) GetReservationConflicts {
+	return GetReservationConflicts{QueryModel: application.QueryModel{ID: aggregateID}}
+}
+
+type GetReservationConflictsQueryHandler struct {
+	reservations ReservationRepository
+	storages     storageapp.StorageWithBooksRepository
+}
+
+func NewGetReservationConflictsQueryHandler(
+	reservations ReservationRepository,
+	storages storageapp.StorageWithBooksRepository,
+) GetReservationConflictsQueryHandler {
+	return GetReservationConflictsQueryHandler{reservations: reservations, storages: storages}
+}
+
+func (h GetReservationConflictsQueryHandler) Handle(ctx context.Context, query GetReservationConflicts) ([]reservationdomain.Conflict, error) {
+	projections, err := h.reservations.GetReservationsBySchoolID(ctx, query.AggregateID())
+	if err != nil {
+		return nil, err
+	}
+	storages, err := h.storages.GetAllStoragesBySchoolID(ctx, query.AggregateID())
+	if err != nil {
+		return nil, err
+	}
+	reservations := []reservationdomain.Reservation{}
+	for _, projection := range projections {
+		reservations = append(reservations, projection.Reservation())
+	}
+	available := func(storageID, bookID string) int {
+		storage := fp.Find(storages, func(s storagedomain.StorageWithBooks) bool { return s.StorageID == storageID })
+		if storage == nil {
+			return 0
+		}
+		book := fp.Find(storage.Books, func(b storagedomain.BookInStorage) bool { return b.BookID == bookID })
+		if book == nil {
+			return 0
+		}
+		return book.Quantity - storagedomain.InCondition(book.Quantity, book.Conditions, storagedomain.Lost)
+	}
+	return reservationdomain.Conflicts(reservations, time.Now(), available), nil
+}
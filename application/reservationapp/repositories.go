@@ -0,0 +1,16 @@
+package reservationapp
+
+import (
+	"context"
+	"time"
+
+	"github.com/kammeph/school-book-storage-service/domain/reservationdomain"
+)
+
+type ReservationRepository interface {
+	GetReservationsBySchoolID(ctx context.Context, schoolID string) ([]reservationdomain.ReservationProjection, error)
+	GetReservationByID(ctx context.Context, schoolID, reservationID string) (reservationdomain.ReservationProjection, error)
+	UpsertReservation(ctx context.Context, reservation reservationdomain.ReservationProjection) error
+	UpdateReservationCancelled(ctx context.Context, reservationID string, cancelledAt time.Time, version int) error
+	UpdateReservationFulfilled(ctx context.Context, reservationID string, fulfilled, version int) error
+}
@@ -3,7 +3,6 @@ package schoolapp
 import (
 	"context"
 	"encoding/json"
-	"log"
 
 	"github.com/kammeph/school-book-storage-service/application"
 	"github.com/kammeph/school-book-storage-service/domain"
@@ -18,50 +17,44 @@ func NewSchoolEventHandler(repository SchoolRepository) application.EventHandler
 	return &SchoolEventHandler{repository}
 }
 
-func (h SchoolEventHandler) Handle(ctx context.Context, eventBytes []byte) {
+func (h SchoolEventHandler) Handle(ctx context.Context, eventBytes []byte) error {
 	event := &domain.EventModel{}
 	if err := json.Unmarshal(eventBytes, event); err != nil {
-		log.Println(err.Error())
+		return err
 	}
 	switch event.EventType() {
 	case schooldomain.SchoolAdded:
-		h.handleSchoolAdded(ctx, event)
+		return h.handleSchoolAdded(ctx, event)
 	case schooldomain.SchoolDeactivated:
-		h.handleSchoolDeactivated(ctx, event)
+		return h.handleSchoolDeactivated(ctx, event)
 	case schooldomain.SchoolRenamed:
-		h.handleSchoolRenamed(ctx, event)
+		return h.handleSchoolRenamed(ctx, event)
 	default:
-		return
+		return nil
 	}
 }
 
-func (h SchoolEventHandler) handleSchoolAdded(ctx context.Context, event domain.Event) {
+func (h SchoolEventHandler) handleSchoolAdded(ctx context.Context, event domain.Event) error {
 	schoolAdded := schooldomain.SchoolAddedEvent{}
 	if err := event.GetJsonData(&schoolAdded); err != nil {
-		log.Println(err.Error())
-	}
-	school := schooldomain.NewSchoolProjection(schoolAdded.SchoolID, schoolAdded.Name)
-	if err := h.repository.InsertSchool(ctx, school); err != nil {
-		log.Println(err.Error())
+		return err
 	}
+	school := schooldomain.NewSchoolProjection(schoolAdded.SchoolID, schoolAdded.Name, event.EventVersion())
+	return h.repository.UpsertSchool(ctx, school)
 }
 
-func (h SchoolEventHandler) handleSchoolDeactivated(ctx context.Context, event domain.Event) {
+func (h SchoolEventHandler) handleSchoolDeactivated(ctx context.Context, event domain.Event) error {
 	eventData := schooldomain.SchoolDeactivatedEvent{}
 	if err := event.GetJsonData(&eventData); err != nil {
-		log.Println(err.Error())
-	}
-	if err := h.repository.DeleteSchool(ctx, eventData.SchoolID); err != nil {
-		log.Println(err.Error())
+		return err
 	}
+	return h.repository.DeleteSchool(ctx, eventData.SchoolID, event.EventVersion())
 }
 
-func (h SchoolEventHandler) handleSchoolRenamed(ctx context.Context, event domain.Event) {
+func (h SchoolEventHandler) handleSchoolRenamed(ctx context.Context, event domain.Event) error {
 	eventData := schooldomain.SchoolRenamedEvent{}
 	if err := event.GetJsonData(&eventData); err != nil {
-		log.Println(err.Error())
-	}
-	if err := h.repository.UpdateSchoolName(ctx, eventData.SchoolID, eventData.Name); err != nil {
-		log.Println(err.Error())
+		return err
 	}
+	return h.repository.UpdateSchoolName(ctx, eventData.SchoolID, eventData.Name, event.EventVersion())
 }
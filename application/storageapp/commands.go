@@ -2,31 +2,65 @@ package storageapp
 
 import (
 	"context"
+	"time"
 
 	"github.com/kammeph/school-book-storage-service/application"
+	"github.com/kammeph/school-book-storage-service/domain"
+	"github.com/kammeph/school-book-storage-service/domain/reservationdomain"
 	"github.com/kammeph/school-book-storage-service/domain/storagedomain"
 )
 
 type StorageCommandHandlers struct {
-	AddStorageHandler      AddStorageCommandHandler
-	RemoveStorageHandler   RemoveStorageCommandHandler
-	RenameStorageHandler   RenameStorageCommandHandler
-	RelocateStorageHandler RelocateStorageCommandHandler
+	AddStorageHandler         AddStorageCommandHandler
+	RemoveStorageHandler      RemoveStorageCommandHandler
+	RenameStorageHandler      RenameStorageCommandHandler
+	RelocateStorageHandler    RelocateStorageCommandHandler
+	ChangeCapacityHandler     ChangeCapacityCommandHandler
+	AddLocationHandler        AddLocationCommandHandler
+	RenameLocationHandler     RenameLocationCommandHandler
+	RemoveLocationHandler     RemoveLocationCommandHandler
+	PutBooksHandler           PutBooksCommandHandler
+	TakeBooksHandler          TakeBooksCommandHandler
+	TransferBooksHandler      TransferBooksCommandHandler
+	OpenStocktakingHandler    OpenStocktakingCommandHandler
+	CountStockHandler         CountStockCommandHandler
+	ApproveStocktakingHandler ApproveStocktakingCommandHandler
+	RecordDamageHandler       RecordDamageCommandHandler
+	WriteOffBooksHandler      WriteOffBooksCommandHandler
 }
 
-func NewStorageCommandHandlers(store application.Store, publisher application.EventPublisher) StorageCommandHandlers {
+func NewStorageCommandHandlers(
+	store application.Store,
+	publisher application.EventPublisher,
+	reservationStore application.Store,
+) StorageCommandHandlers {
 	return StorageCommandHandlers{
-		AddStorageHandler:      NewAddStorageCommandHandler(store, publisher),
-		RemoveStorageHandler:   NewRemoveStorageCommandHandler(store, publisher),
-		RenameStorageHandler:   NewRenameStorageCommandHandler(store, publisher),
-		RelocateStorageHandler: NewRelocateStorageCommandHandler(store, publisher),
+		AddStorageHandler:         NewAddStorageCommandHandler(store, publisher),
+		RemoveStorageHandler:      NewRemoveStorageCommandHandler(store, publisher),
+		RenameStorageHandler:      NewRenameStorageCommandHandler(store, publisher),
+		RelocateStorageHandler:    NewRelocateStorageCommandHandler(store, publisher),
+		ChangeCapacityHandler:     NewChangeCapacityCommandHandler(store, publisher),
+		AddLocationHandler:        NewAddLocationCommandHandler(store, publisher),
+		RenameLocationHandler:     NewRenameLocationCommandHandler(store, publisher),
+		RemoveLocationHandler:     NewRemoveLocationCommandHandler(store, publisher),
+		PutBooksHandler:           NewPutBooksCommandHandler(store, publisher),
+		TakeBooksHandler:          NewTakeBooksCommandHandler(store, publisher, reservationStore),
+		TransferBooksHandler:      NewTransferBooksCommandHandler(store, publisher, reservationStore),
+		OpenStocktakingHandler:    NewOpenStocktakingCommandHandler(store, publisher),
+		CountStockHandler:         NewCountStockCommandHandler(store, publisher),
+		ApproveStocktakingHandler: NewApproveStocktakingCommandHandler(store, publisher),
+		RecordDamageHandler:       NewRecordDamageCommandHandler(store, publisher),
+		WriteOffBooksHandler:      NewWriteOffBooksCommandHandler(store, publisher),
 	}
 }
 
+// AddStorageCommand places the new storage either at one of the school's
+// locations or, without a location ID, at a free-text location.
 type AddStorageCommand struct {
 	application.CommandModel
-	Name     string `json:"name"`
-	Location string `json:"location"`
+	Name       string `json:"name"`
+	Location   string `json:"location"`
+	LocationID string `json:"locationId"`
 }
 
 type AddStorageCommandHandler struct {
@@ -42,7 +76,13 @@ func (h AddStorageCommandHandler) Handle(ctx context.Context, command AddStorage
 	if err := h.LoadAggregate(ctx, aggregate); err != nil {
 		return "", err
 	}
-	storageID, err := aggregate.AddStorage(command.Name, command.Location)
+	var storageID string
+	var err error
+	if command.LocationID != "" {
+		storageID, err = aggregate.AddStorageAt(command.Name, command.LocationID)
+	} else {
+		storageID, err = aggregate.AddStorage(command.Name, command.Location)
+	}
 	if err != nil {
 		return "", err
 	}
@@ -105,9 +145,10 @@ func (h RenameStorageCommandHandler) Handle(ctx context.Context, command RenameS
 
 type RelocateStorageCommand struct {
 	application.CommandModel
-	StorageID string `json:"storageId"`
-	Location  string `json:"location"`
-	Reason    string `json:"reason"`
+	StorageID  string `json:"storageId"`
+	Location   string `json:"location"`
+	LocationID string `json:"locationId"`
+	Reason     string `json:"reason"`
 }
 
 type RelocateStorageCommandHandler struct {
@@ -123,8 +164,410 @@ func (h RelocateStorageCommandHandler) Handle(ctx context.Context, command Reloc
 	if err := h.LoadAggregate(ctx, aggregate); err != nil {
 		return err
 	}
-	if err := aggregate.RelocateStorage(command.StorageID, command.Location, command.Reason); err != nil {
+	var err error
+	if command.LocationID != "" {
+		err = aggregate.PlaceStorage(command.StorageID, command.LocationID, command.Reason)
+	} else {
+		err = aggregate.RelocateStorage(command.StorageID, command.Location, command.Reason)
+	}
+	if err != nil {
+		return err
+	}
+	return h.SaveAndPublish(ctx, aggregate)
+}
+
+type ChangeCapacityCommand struct {
+	application.CommandModel
+	StorageID string `json:"storageId"`
+	Capacity  int    `json:"capacity"`
+	Reason    string `json:"reason"`
+}
+
+type ChangeCapacityCommandHandler struct {
+	*application.CommandHandlerModel
+}
+
+func NewChangeCapacityCommandHandler(store application.Store, publisher application.EventPublisher) ChangeCapacityCommandHandler {
+	return ChangeCapacityCommandHandler{application.NewCommandHandlerModel(store, publisher)}
+}
+
+func (h ChangeCapacityCommandHandler) Handle(ctx context.Context, command ChangeCapacityCommand) error {
+	aggregate := storagedomain.NewSchoolStorageAggregateWithID(command.AggregateID())
+	if err := h.LoadAggregate(ctx, aggregate); err != nil {
+		return err
+	}
+	if err := aggregate.ChangeCapacity(command.StorageID, command.Capacity, command.Reason); err != nil {
+		return err
+	}
+	return h.SaveAndPublish(ctx, aggregate)
+}
+
+type AddLocationCommand struct {
+	application.CommandModel
+	Kind     storagedomain.LocationKind `json:"kind"`
+	Name     string                     `json:"name"`
+	ParentID string                     `json:"parentId"`
+}
+
+type AddLocationCommandHandler struct {
+	*application.CommandHandlerModel
+}
+
+func NewAddLocationCommandHandler(store application.Store, publisher application.EventPublisher) AddLocationCommandHandler {
+	return AddLocationCommandHandler{application.NewCommandHandlerModel(store, publisher)}
+}
+
+func (h AddLocationCommandHandler) Handle(ctx context.Context, command AddLocationCommand) (string, error) {
+	aggregate := storagedomain.NewSchoolStorageAggregateWithID(command.AggregateID())
+	if err := h.LoadAggregate(ctx, aggregate); err != nil {
+		return "", err
+	}
+	locationID, err := aggregate.AddLocation(command.Kind, command.Name, command.ParentID)
+	if err != nil {
+		return "", err
+	}
+	if err := h.SaveAndPublish(ctx, aggregate); err != nil {
+		return "", err
+	}
+	return locationID, nil
+}
+
+type RenameLocationCommand struct {
+	application.CommandModel
+	LocationID string `json:"locationId"`
+	Name       string `json:"name"`
+	Reason     string `json:"reason"`
+}
+
+type RenameLocationCommandHandler struct {
+	*application.CommandHandlerModel
+}
+
+func NewRenameLocationCommandHandler(store application.Store, publisher application.EventPublisher) RenameLocationCommandHandler {
+	return RenameLocationCommandHandler{application.NewCommandHandlerModel(store, publisher)}
+}
+
+func (h RenameLocationCommandHandler) Handle(ctx context.Context, command RenameLocationCommand) error {
+	aggregate := storagedomain.NewSchoolStorageAggregateWithID(command.AggregateID())
+	if err := h.LoadAggregate(ctx, aggregate); err != nil {
+		return err
+	}
+	if err := aggregate.RenameLocation(command.LocationID, command.Name, command.Reason); err != nil {
+		return err
+	}
+	return h.SaveAndPublish(ctx, aggregate)
+}
+
+type RemoveLocationCommand struct {
+	application.CommandModel
+	LocationID string `json:"locationId"`
+	Reason     string `json:"reason"`
+}
+
+type RemoveLocationCommandHandler struct {
+	*application.CommandHandlerModel
+}
+
+func NewRemoveLocationCommandHandler(store application.Store, publisher application.EventPublisher) RemoveLocationCommandHandler {
+	return RemoveLocationCommandHandler{application.NewCommandHandlerModel(store, publisher)}
+}
+
+func (h RemoveLocationCommandHandler) Handle(ctx context.Context, command RemoveLocationCommand) error {
+	aggregate := storagedomain.NewSchoolStorageAggregateWithID(command.AggregateID())
+	if err := h.LoadAggregate(ctx, aggregate); err != nil {
+		return err
+	}
+	if err := aggregate.RemoveLocation(command.LocationID, command.Reason); err != nil {
+		return err
+	}
+	return h.SaveAndPublish(ctx, aggregate)
+}
+
+type PutBooksCommand struct {
+	application.CommandModel
+	StorageID string `json:"storageId"`
+	BookID    string `json:"bookId"`
+	Isbn      string `json:"isbn"`
+	Title     string `json:"title"`
+	Quantity  int    `json:"quantity"`
+}
+
+type PutBooksCommandHandler struct {
+	*application.CommandHandlerModel
+}
+
+func NewPutBooksCommandHandler(store application.Store, publisher application.EventPublisher) PutBooksCommandHandler {
+	return PutBooksCommandHandler{application.NewCommandHandlerModel(store, publisher)}
+}
+
+func (h PutBooksCommandHandler) Handle(ctx context.Context, command PutBooksCommand) error {
+	aggregate := storagedomain.NewSchoolStorageAggregateWithID(command.AggregateID())
+	if err := h.LoadAggregate(ctx, aggregate); err != nil {
+		return err
+	}
+	isbn, err := parseIsbn(command.Isbn)
+	if err != nil {
+		return err
+	}
+	if err := aggregate.PutBooks(command.StorageID, command.BookID, isbn, command.Title, command.Quantity); err != nil {
+		return err
+	}
+	return h.SaveAndPublish(ctx, aggregate)
+}
+
+type TakeBooksCommand struct {
+	application.CommandModel
+	StorageID string `json:"storageId"`
+	BookID    string `json:"bookId"`
+	Quantity  int    `json:"quantity"`
+	Reason    string `json:"reason"`
+}
+
+type TakeBooksCommandHandler struct {
+	*application.CommandHandlerModel
+	reservations *application.CommandHandlerModel
+}
+
+func NewTakeBooksCommandHandler(
+	store application.Store,
+	publisher application.EventPublisher,
+	reservationStore application.Store,
+) TakeBooksCommandHandler {
+	return TakeBooksCommandHandler{
+		CommandHandlerModel: application.NewCommandHandlerModel(store, publisher),
+		reservations:        application.NewCommandHandlerModel(reservationStore, nil),
+	}
+}
+
+// Handle takes the books unless they are reserved in the storage.
+func (h TakeBooksCommandHandler) Handle(ctx context.Context, command TakeBooksCommand) error {
+	aggregate := storagedomain.NewSchoolStorageAggregateWithID(command.AggregateID())
+	if err := h.LoadAggregate(ctx, aggregate); err != nil {
+		return err
+	}
+	reservations := reservationdomain.NewSchoolReservationAggregateWithID(command.AggregateID())
+	if err := h.reservations.LoadAggregate(ctx, reservations); err != nil {
+		return err
+	}
+	aggregate.Holds = reservations.Holds(time.Now(), "")
+	if err := aggregate.TakeBooks(command.StorageID, command.BookID, command.Quantity, command.Reason); err != nil {
+		return err
+	}
+	return h.SaveAndPublish(ctx, aggregate)
+}
+
+type TransferBooksCommand struct {
+	application.CommandModel
+	FromStorageID string `json:"fromStorageId"`
+	ToStorageID   string `json:"toStorageId"`
+	BookID        string `json:"bookId"`
+	Quantity      int    `json:"quantity"`
+	Reason        string `json:"reason"`
+}
+
+type TransferBooksCommandHandler struct {
+	*application.CommandHandlerModel
+	reservations *application.CommandHandlerModel
+}
+
+func NewTransferBooksCommandHandler(
+	store application.Store,
+	publisher application.EventPublisher,
+	reservationStore application.Store,
+) TransferBooksCommandHandler {
+	return TransferBooksCommandHandler{
+		CommandHandlerModel: application.NewCommandHandlerModel(store, publisher),
+		reservations:        application.NewCommandHandlerModel(reservationStore, nil),
+	}
+}
+
+// Handle transfers the books unless they are reserved in the storage they are
+// taken from.
+func (h TransferBooksCommandHandler) Handle(ctx context.Context, command TransferBooksCommand) error {
+	aggregate := storagedomain.NewSchoolStorageAggregateWithID(command.AggregateID())
+	if err := h.LoadAggregate(ctx, aggregate); err != nil {
+		return err
+	}
+	reservations := reservationdomain.NewSchoolReservationAggregateWithID(command.AggregateID())
+	if err := h.reservations.LoadAggregate(ctx, reservations); err != nil {
+		return err
+	}
+	aggregate.Holds = reservations.Holds(time.Now(), "")
+	err := aggregate.TransferBooks(command.FromStorageID, command.ToStorageID, command.BookID, command.Quantity, command.Reason)
+	if err != nil {
+		return err
+	}
+	return h.SaveAndPublish(ctx, aggregate)
+}
+
+type OpenStocktakingCommand struct {
+	application.CommandModel
+	StorageIDs []string `json:"storageIds"`
+}
+
+type OpenStocktakingCommandHandler struct {
+	*application.CommandHandlerModel
+}
+
+func NewOpenStocktakingCommandHandler(store application.Store, publisher application.EventPublisher) OpenStocktakingCommandHandler {
+	return OpenStocktakingCommandHandler{application.NewCommandHandlerModel(store, publisher)}
+}
+
+func (h OpenStocktakingCommandHandler) Handle(ctx context.Context, command OpenStocktakingCommand) (string, error) {
+	aggregate := storagedomain.NewSchoolStorageAggregateWithID(command.AggregateID())
+	if err := h.LoadAggregate(ctx, aggregate); err != nil {
+		return "", err
+	}
+	stocktakingID, err := aggregate.OpenStocktaking(command.StorageIDs)
+	if err != nil {
+		return "", err
+	}
+	if err := h.SaveAndPublish(ctx, aggregate); err != nil {
+		return "", err
+	}
+	return stocktakingID, nil
+}
+
+type CountStockCommand struct {
+	application.CommandModel
+	StocktakingID string `json:"stocktakingId"`
+	StorageID     string `json:"storageId"`
+	BookID        string `json:"bookId"`
+	Isbn          string `json:"isbn"`
+	Title         string `json:"title"`
+	Quantity      int    `json:"quantity"`
+}
+
+type CountStockCommandHandler struct {
+	*application.CommandHandlerModel
+}
+
+func NewCountStockCommandHandler(store application.Store, publisher application.EventPublisher) CountStockCommandHandler {
+	return CountStockCommandHandler{application.NewCommandHandlerModel(store, publisher)}
+}
+
+func (h CountStockCommandHandler) Handle(ctx context.Context, command CountStockCommand) error {
+	aggregate := storagedomain.NewSchoolStorageAggregateWithID(command.AggregateID())
+	if err := h.LoadAggregate(ctx, aggregate); err != nil {
+		return err
+	}
+	isbn, err := parseIsbn(command.Isbn)
+	if err != nil {
+		return err
+	}
+	count := storagedomain.StockCount{
+		StorageID: command.StorageID,
+		BookID:    command.BookID,
+		Isbn:      isbn,
+		Title:     command.Title,
+		Quantity:  command.Quantity,
+	}
+	if err := aggregate.CountStock(command.StocktakingID, count); err != nil {
+		return err
+	}
+	return h.SaveAndPublish(ctx, aggregate)
+}
+
+type ApproveStocktakingCommand struct {
+	application.CommandModel
+	StocktakingID string `json:"stocktakingId"`
+	Reason        string `json:"reason"`
+}
+
+type ApproveStocktakingCommandHandler struct {
+	*application.CommandHandlerModel
+}
+
+func NewApproveStocktakingCommandHandler(store application.Store, publisher application.EventPublisher) ApproveStocktakingCommandHandler {
+	return ApproveStocktakingCommandHandler{application.NewCommandHandlerModel(store, publisher)}
+}
+
+// Handle corrects the stock to the counted copies and returns the corrected
+// discrepancies.
+func (h ApproveStocktakingCommandHandler) Handle(ctx context.Context, command ApproveStocktakingCommand) ([]storagedomain.Discrepancy, error) {
+	aggregate := storagedomain.NewSchoolStorageAggregateWithID(command.AggregateID())
+	if err := h.LoadAggregate(ctx, aggregate); err != nil {
+		return nil, err
+	}
+	discrepancies, err := aggregate.ApproveStocktaking(command.StocktakingID, command.Reason)
+	if err != nil {
+		return nil, err
+	}
+	if err := h.SaveAndPublish(ctx, aggregate); err != nil {
+		return nil, err
+	}
+	return discrepancies, nil
+}
+
+type RecordDamageCommand struct {
+	application.CommandModel
+	StorageID string                  `json:"storageId"`
+	BookID    string                  `json:"bookId"`
+	From      storagedomain.Condition `json:"from"`
+	Condition storagedomain.Condition `json:"condition"`
+	Quantity  int                     `json:"quantity"`
+	Reason    string                  `json:"reason"`
+}
+
+type RecordDamageCommandHandler struct {
+	*application.CommandHandlerModel
+}
+
+func NewRecordDamageCommandHandler(store application.Store, publisher application.EventPublisher) RecordDamageCommandHandler {
+	return RecordDamageCommandHandler{application.NewCommandHandlerModel(store, publisher)}
+}
+
+// Handle grades the copies down. Without a previous condition the copies are
+// taken from the good ones.
+func (h RecordDamageCommandHandler) Handle(ctx context.Context, command RecordDamageCommand) error {
+	aggregate := storagedomain.NewSchoolStorageAggregateWithID(command.AggregateID())
+	if err := h.LoadAggregate(ctx, aggregate); err != nil {
+		return err
+	}
+	from := command.From
+	if from == "" {
+		from = storagedomain.Good
+	}
+	err := aggregate.RecordDamage(command.StorageID, command.BookID, from, command.Condition, command.Quantity, command.Reason)
+	if err != nil {
+		return err
+	}
+	return h.SaveAndPublish(ctx, aggregate)
+}
+
+type WriteOffBooksCommand struct {
+	application.CommandModel
+	StorageID string                  `json:"storageId"`
+	BookID    string                  `json:"bookId"`
+	Condition storagedomain.Condition `json:"condition"`
+	Quantity  int                     `json:"quantity"`
+	Reason    string                  `json:"reason"`
+}
+
+type WriteOffBooksCommandHandler struct {
+	*application.CommandHandlerModel
+}
+
+func NewWriteOffBooksCommandHandler(store application.Store, publisher application.EventPublisher) WriteOffBooksCommandHandler {
+	return WriteOffBooksCommandHandler{application.NewCommandHandlerModel(store, publisher)}
+}
+
+func (h WriteOffBooksCommandHandler) Handle(ctx context.Context, command WriteOffBooksCommand) error {
+	aggregate := storagedomain.NewSchoolStorageAggregateWithID(command.AggregateID())
+	if err := h.LoadAggregate(ctx, aggregate); err != nil {
+		return err
+	}
+	err := aggregate.WriteOffBooks(command.StorageID, command.BookID, command.Condition, command.Quantity, command.Reason)
+	if err != nil {
 		return err
 	}
 	return h.SaveAndPublish(ctx, aggregate)
 }
+
+// parseIsbn normalizes the ISBN given with a command. The ISBN is optional.
+func parseIsbn(value string) (domain.Isbn, error) {
+	if value == "" {
+		return "", nil
+	}
+	return domain.ParseIsbn(value)
+}
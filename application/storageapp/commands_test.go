@@ -3,12 +3,14 @@ package storageapp_test
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"testing"
 	"time"
 
 	"github.com/kammeph/school-book-storage-service/application"
 	"github.com/kammeph/school-book-storage-service/application/storageapp"
 	"github.com/kammeph/school-book-storage-service/domain"
+	"github.com/kammeph/school-book-storage-service/domain/reservationdomain"
 	"github.com/kammeph/school-book-storage-service/domain/storagedomain"
 	"github.com/kammeph/school-book-storage-service/infrastructure/memory"
 	"github.com/stretchr/testify/assert"
@@ -88,3 +90,223 @@ func TestHandleSetStorageLocation(t *testing.T) {
 	err := handler.Handle(ctx, command)
 	assert.Nil(t, err)
 }
+
+func TestHandlePutAndTakeBooks(t *testing.T) {
+	ctx := context.Background()
+	commandHandlers := storageapp.NewStorageCommandHandlers(store, nil, memory.NewMemoryStore())
+	put := storageapp.PutBooksCommand{
+		CommandModel: application.CommandModel{ID: "school"},
+		StorageID:    "testUpdate",
+		BookID:       "book",
+		Isbn:         "978-3-12-732320-7",
+		Title:        "Green Line 1",
+		Quantity:     10,
+	}
+	assert.Nil(t, commandHandlers.PutBooksHandler.Handle(ctx, put))
+	take := storageapp.TakeBooksCommand{
+		CommandModel: application.CommandModel{ID: "school"},
+		StorageID:    "testUpdate",
+		BookID:       "book",
+		Quantity:     4,
+		Reason:       "handed out",
+	}
+	assert.Nil(t, commandHandlers.TakeBooksHandler.Handle(ctx, take))
+	take.Quantity = 7
+	assert.Equal(t, storagedomain.ErrInsufficientStock("book", 6, 7), commandHandlers.TakeBooksHandler.Handle(ctx, take))
+}
+
+func TestHandleTakeReservedBooks(t *testing.T) {
+	ctx := context.Background()
+	reservationStore := memory.NewMemoryStoreWithEvents([]domain.Event{
+		&domain.EventModel{
+			ID:      "school",
+			Type:    reservationdomain.ReservationMade,
+			Version: 1,
+			At:      time.Now(),
+			Data: fmt.Sprintf(
+				"{\"schoolId\":\"school\",\"reservationId\":\"reservation\",\"classId\":\"class\",\"bookId\":\"book\",\"storageId\":\"testUpdate\",\"quantity\":8,\"from\":%q,\"until\":%q}",
+				time.Now().AddDate(0, 0, -1).Format(time.RFC3339),
+				time.Now().AddDate(1, 0, 0).Format(time.RFC3339),
+			),
+		},
+	})
+	commandHandlers := storageapp.NewStorageCommandHandlers(newMemoryStoreWithDefaultEvents(), nil, reservationStore)
+	put := storageapp.PutBooksCommand{
+		CommandModel: application.CommandModel{ID: "school"},
+		StorageID:    "testUpdate",
+		BookID:       "book",
+		Quantity:     10,
+	}
+	assert.Nil(t, commandHandlers.PutBooksHandler.Handle(ctx, put))
+	take := storageapp.TakeBooksCommand{
+		CommandModel: application.CommandModel{ID: "school"},
+		StorageID:    "testUpdate",
+		BookID:       "book",
+		Quantity:     3,
+		Reason:       "handed out",
+	}
+	assert.Equal(t, storagedomain.ErrInsufficientStock("book", 2, 3), commandHandlers.TakeBooksHandler.Handle(ctx, take))
+	take.Quantity = 2
+	assert.Nil(t, commandHandlers.TakeBooksHandler.Handle(ctx, take))
+}
+
+func TestHandleTransferBooks(t *testing.T) {
+	ctx := context.Background()
+	commandHandlers := storageapp.NewStorageCommandHandlers(newMemoryStoreWithDefaultEvents(), nil, memory.NewMemoryStore())
+	put := storageapp.PutBooksCommand{
+		CommandModel: application.CommandModel{ID: "school"},
+		StorageID:    "testRemove",
+		BookID:       "book",
+		Quantity:     30,
+	}
+	assert.Nil(t, commandHandlers.PutBooksHandler.Handle(ctx, put))
+	transfer := storageapp.TransferBooksCommand{
+		CommandModel:  application.CommandModel{ID: "school"},
+		FromStorageID: "testRemove",
+		ToStorageID:   "testUpdate",
+		BookID:        "book",
+		Quantity:      30,
+		Reason:        "moved to room 101",
+	}
+	assert.Nil(t, commandHandlers.TransferBooksHandler.Handle(ctx, transfer))
+	assert.Equal(t, storagedomain.ErrInsufficientStock("book", 0, 30), commandHandlers.TransferBooksHandler.Handle(ctx, transfer))
+}
+
+func TestHandleStocktaking(t *testing.T) {
+	ctx := context.Background()
+	commandHandlers := storageapp.NewStorageCommandHandlers(newMemoryStoreWithDefaultEvents(), nil, memory.NewMemoryStore())
+	put := storageapp.PutBooksCommand{
+		CommandModel: application.CommandModel{ID: "school"},
+		StorageID:    "testUpdate",
+		BookID:       "book",
+		Title:        "Green Line 1",
+		Quantity:     10,
+	}
+	assert.Nil(t, commandHandlers.PutBooksHandler.Handle(ctx, put))
+	stocktakingID, err := commandHandlers.OpenStocktakingHandler.Handle(ctx, storageapp.OpenStocktakingCommand{
+		CommandModel: application.CommandModel{ID: "school"},
+		StorageIDs:   []string{"testUpdate"},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, storagedomain.ErrStorageFrozen("testUpdate"), commandHandlers.PutBooksHandler.Handle(ctx, put))
+	count := storageapp.CountStockCommand{
+		CommandModel:  application.CommandModel{ID: "school"},
+		StocktakingID: stocktakingID,
+		StorageID:     "testUpdate",
+		BookID:        "book",
+		Quantity:      9,
+	}
+	assert.Nil(t, commandHandlers.CountStockHandler.Handle(ctx, count))
+	discrepancies, err := commandHandlers.ApproveStocktakingHandler.Handle(ctx, storageapp.ApproveStocktakingCommand{
+		CommandModel:  application.CommandModel{ID: "school"},
+		StocktakingID: stocktakingID,
+		Reason:        "yearly stocktaking",
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, []storagedomain.Discrepancy{
+		{StorageID: "testUpdate", BookID: "book", Title: "Green Line 1", Expected: 10, Counted: 9, Difference: -1},
+	}, discrepancies)
+	assert.Nil(t, commandHandlers.PutBooksHandler.Handle(ctx, put))
+}
+
+func TestHandleRecordDamageAndWriteOff(t *testing.T) {
+	ctx := context.Background()
+	commandHandlers := storageapp.NewStorageCommandHandlers(newMemoryStoreWithDefaultEvents(), nil, memory.NewMemoryStore())
+	put := storageapp.PutBooksCommand{
+		CommandModel: application.CommandModel{ID: "school"},
+		StorageID:    "testUpdate",
+		BookID:       "book",
+		Quantity:     10,
+	}
+	assert.Nil(t, commandHandlers.PutBooksHandler.Handle(ctx, put))
+	damage := storageapp.RecordDamageCommand{
+		CommandModel: application.CommandModel{ID: "school"},
+		StorageID:    "testUpdate",
+		BookID:       "book",
+		Condition:    storagedomain.Damaged,
+		Quantity:     3,
+		Reason:       "water damage",
+	}
+	assert.Nil(t, commandHandlers.RecordDamageHandler.Handle(ctx, damage))
+	writeOff := storageapp.WriteOffBooksCommand{
+		CommandModel: application.CommandModel{ID: "school"},
+		StorageID:    "testUpdate",
+		BookID:       "book",
+		Condition:    storagedomain.Damaged,
+		Quantity:     3,
+		Reason:       "water damage",
+	}
+	assert.Nil(t, commandHandlers.WriteOffBooksHandler.Handle(ctx, writeOff))
+	assert.Equal(
+		t,
+		storagedomain.ErrInsufficientBooksInCondition("book", storagedomain.Damaged, 0, 3),
+		commandHandlers.WriteOffBooksHandler.Handle(ctx, writeOff))
+}
+
+func TestHandleLocationsAndCapacity(t *testing.T) {
+	ctx := context.Background()
+	store := newMemoryStoreWithDefaultEvents()
+	commandHandlers := storageapp.NewStorageCommandHandlers(store, nil, memory.NewMemoryStore())
+	addBuilding := storageapp.AddLocationCommand{
+		CommandModel: application.CommandModel{ID: "school"},
+		Kind:         storagedomain.Building,
+		Name:         "Main building",
+	}
+	buildingID, err := commandHandlers.AddLocationHandler.Handle(ctx, addBuilding)
+	assert.Nil(t, err)
+	addRoom := storageapp.AddLocationCommand{
+		CommandModel: application.CommandModel{ID: "school"},
+		Kind:         storagedomain.Room,
+		Name:         "Room 101",
+		ParentID:     buildingID,
+	}
+	roomID, err := commandHandlers.AddLocationHandler.Handle(ctx, addRoom)
+	assert.Nil(t, err)
+	addStorage := storageapp.AddStorageCommand{CommandModel: application.CommandModel{ID: "school"}, Name: "closet", LocationID: roomID}
+	storageID, err := commandHandlers.AddStorageHandler.Handle(ctx, addStorage)
+	assert.Nil(t, err)
+	relocate := storageapp.RelocateStorageCommand{
+		CommandModel: application.CommandModel{ID: "school"},
+		StorageID:    "testUpdate",
+		LocationID:   roomID,
+		Reason:       "moved",
+	}
+	assert.Nil(t, commandHandlers.RelocateStorageHandler.Handle(ctx, relocate))
+
+	capacity := storageapp.ChangeCapacityCommand{
+		CommandModel: application.CommandModel{ID: "school"},
+		StorageID:    storageID,
+		Capacity:     20,
+		Reason:       "small closet",
+	}
+	assert.Nil(t, commandHandlers.ChangeCapacityHandler.Handle(ctx, capacity))
+	put := storageapp.PutBooksCommand{
+		CommandModel: application.CommandModel{ID: "school"},
+		StorageID:    storageID,
+		BookID:       "book",
+		Quantity:     21,
+	}
+	assert.Equal(t, storagedomain.ErrCapacityExceeded(storageID, 20, 0, 21), commandHandlers.PutBooksHandler.Handle(ctx, put))
+
+	rename := storageapp.RenameLocationCommand{
+		CommandModel: application.CommandModel{ID: "school"},
+		LocationID:   buildingID,
+		Name:         "Old building",
+		Reason:       "new building opened",
+	}
+	assert.Nil(t, commandHandlers.RenameLocationHandler.Handle(ctx, rename))
+	remove := storageapp.RemoveLocationCommand{
+		CommandModel: application.CommandModel{ID: "school"},
+		LocationID:   roomID,
+		Reason:       "closed",
+	}
+	assert.Equal(t, storagedomain.ErrLocationNotEmpty(roomID), commandHandlers.RemoveLocationHandler.Handle(ctx, remove))
+
+	aggregate := storagedomain.NewSchoolStorageAggregateWithID("school")
+	assert.Nil(t, application.NewCommandHandlerModel(store, nil).LoadAggregate(ctx, aggregate))
+	for _, storage := range aggregate.Storages[1:] {
+		assert.Equal(t, "Old building / Room 101", storage.Location)
+		assert.Equal(t, roomID, storage.LocationID)
+	}
+	assert.Equal(t, 20, aggregate.Storages[2].Capacity)
+}
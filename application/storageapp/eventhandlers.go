@@ -18,71 +18,264 @@ func NewStorageEventHandler(repository StorageWithBooksRepository) application.E
 	return &StorageEventHandler{repository}
 }
 
-func (h StorageEventHandler) Handle(ctx context.Context, eventBytes []byte) {
+func (h StorageEventHandler) Handle(ctx context.Context, eventBytes []byte) error {
 	event := &domain.EventModel{}
 	if err := json.Unmarshal(eventBytes, event); err != nil {
-		fmt.Print(err)
+		return err
 	}
 	switch event.EventType() {
 	case storagedomain.StorageAdded:
-		h.handleStorageAdded(ctx, event)
-		return
+		return h.handleStorageAdded(ctx, event)
 	case storagedomain.StorageRemoved:
-		h.handleStorageRemoved(ctx, event)
-		return
+		return h.handleStorageRemoved(ctx, event)
 	case storagedomain.StorageRenamed:
-		h.handleStorageRenamed(ctx, event)
-		return
+		return h.handleStorageRenamed(ctx, event)
 	case storagedomain.StorageRelocated:
-		h.handleStorageRelocated(ctx, event)
-		return
+		return h.handleStorageRelocated(ctx, event)
+	case storagedomain.StorageCapacityChanged:
+		return h.handleStorageCapacityChanged(ctx, event)
+	case storagedomain.BooksPut:
+		return h.handleBooksPut(ctx, event)
+	case storagedomain.BooksTaken:
+		return h.handleBooksTaken(ctx, event)
+	case storagedomain.BooksTransferred:
+		return h.handleBooksTransferred(ctx, event)
+	case storagedomain.BooksLentToClass:
+		return h.handleBooksLentToClass(ctx, event)
+	case storagedomain.BooksReturnedFromClass:
+		return h.handleBooksReturnedFromClass(ctx, event)
+	case storagedomain.StockCorrected:
+		return h.handleStockCorrected(ctx, event)
+	case storagedomain.BooksDamaged:
+		return h.handleBooksDamaged(ctx, event)
+	case storagedomain.BooksWrittenOff:
+		return h.handleBooksWrittenOff(ctx, event)
 	default:
-		return
+		return nil
 	}
 }
 
-func (h StorageEventHandler) handleStorageAdded(ctx context.Context, event domain.Event) {
+func (h StorageEventHandler) handleStorageAdded(ctx context.Context, event domain.Event) error {
 	storageAdded := storagedomain.StorageAddedEvent{}
-	if err := json.Unmarshal([]byte(event.EventData()), &storageAdded); err != nil {
-		fmt.Print(err)
+	if err := event.GetJsonData(&storageAdded); err != nil {
+		return err
 	}
 	storage := storagedomain.NewStorageWithBooks(
 		storageAdded.SchoolID,
 		storageAdded.StorageID,
 		storageAdded.Name,
-		storageAdded.Location)
-	h.repository.InsertStorage(ctx, storage)
+		storageAdded.Location,
+		event.EventVersion())
+	storage.LocationID = storageAdded.LocationID
+	return h.repository.UpsertStorage(ctx, storage)
 }
 
-func (h StorageEventHandler) handleStorageRemoved(ctx context.Context, event domain.Event) {
+func (h StorageEventHandler) handleStorageRemoved(ctx context.Context, event domain.Event) error {
 	storageRemoved := storagedomain.StorageRemovedEvent{}
-	err := json.Unmarshal([]byte(event.EventData()), &storageRemoved)
-	if err != nil {
-		fmt.Print(err)
+	if err := event.GetJsonData(&storageRemoved); err != nil {
+		return err
 	}
-	h.repository.DeleteStorage(ctx, storageRemoved.StorageID)
+	return h.repository.DeleteStorage(ctx, storageRemoved.StorageID, event.EventVersion())
 }
 
-func (h StorageEventHandler) handleStorageRenamed(ctx context.Context, event domain.Event) {
+func (h StorageEventHandler) handleStorageRenamed(ctx context.Context, event domain.Event) error {
 	storageRenamed := storagedomain.StorageRenamedEvent{}
-	err := json.Unmarshal([]byte(event.EventData()), &storageRenamed)
-	if err != nil {
-		fmt.Print(err)
+	if err := event.GetJsonData(&storageRenamed); err != nil {
+		return err
 	}
-	h.repository.UpdateStorageName(ctx, storageRenamed.StorageID, storageRenamed.Name)
+	return h.repository.UpdateStorageName(ctx, storageRenamed.StorageID, storageRenamed.Name, event.EventVersion())
 }
 
-func (h StorageEventHandler) handleStorageRelocated(ctx context.Context, event domain.Event) {
+func (h StorageEventHandler) handleStorageRelocated(ctx context.Context, event domain.Event) error {
 	storageRelocated := storagedomain.StorageRelocatedEvent{}
-	err := json.Unmarshal([]byte(event.EventData()), &storageRelocated)
+	if err := event.GetJsonData(&storageRelocated); err != nil {
+		return err
+	}
+	return h.repository.UpdateStorageLocation(
+		ctx,
+		storageRelocated.StorageID,
+		storageRelocated.Location,
+		storageRelocated.LocationID,
+		event.EventVersion())
+}
+
+func (h StorageEventHandler) handleStorageCapacityChanged(ctx context.Context, event domain.Event) error {
+	capacityChanged := storagedomain.StorageCapacityChangedEvent{}
+	if err := event.GetJsonData(&capacityChanged); err != nil {
+		return err
+	}
+	return h.repository.UpdateStorageCapacity(ctx, capacityChanged.StorageID, capacityChanged.Capacity, event.EventVersion())
+}
+
+func (h StorageEventHandler) handleBooksPut(ctx context.Context, event domain.Event) error {
+	booksPut := storagedomain.BooksPutEvent{}
+	if err := event.GetJsonData(&booksPut); err != nil {
+		return err
+	}
+	book := storagedomain.BookInStorage{
+		BookID:   booksPut.BookID,
+		Isbn:     booksPut.Isbn,
+		Title:    booksPut.Title,
+		Quantity: booksPut.Quantity,
+	}
+	return h.updateBooks(ctx, event, booksPut.StorageID, func(books []storagedomain.BookInStorage) []storagedomain.BookInStorage {
+		return putBooks(books, book)
+	})
+}
+
+func (h StorageEventHandler) handleBooksTaken(ctx context.Context, event domain.Event) error {
+	booksTaken := storagedomain.BooksTakenEvent{}
+	if err := event.GetJsonData(&booksTaken); err != nil {
+		return err
+	}
+	return h.updateBooks(ctx, event, booksTaken.StorageID, func(books []storagedomain.BookInStorage) []storagedomain.BookInStorage {
+		return takeBooks(books, booksTaken.BookID, booksTaken.Quantity)
+	})
+}
+
+func (h StorageEventHandler) handleBooksTransferred(ctx context.Context, event domain.Event) error {
+	booksTransferred := storagedomain.BooksTransferredEvent{}
+	if err := event.GetJsonData(&booksTransferred); err != nil {
+		return err
+	}
+	err := h.updateBooks(ctx, event, booksTransferred.FromStorageID, func(books []storagedomain.BookInStorage) []storagedomain.BookInStorage {
+		return takeBooks(books, booksTransferred.BookID, booksTransferred.Quantity)
+	})
 	if err != nil {
-		fmt.Print(err)
+		return err
+	}
+	book := storagedomain.BookInStorage{
+		BookID:   booksTransferred.BookID,
+		Isbn:     booksTransferred.Isbn,
+		Title:    booksTransferred.Title,
+		Quantity: booksTransferred.Quantity,
+	}
+	return h.updateBooks(ctx, event, booksTransferred.ToStorageID, func(books []storagedomain.BookInStorage) []storagedomain.BookInStorage {
+		return putBooks(books, book)
+	})
+}
+
+func (h StorageEventHandler) handleBooksLentToClass(ctx context.Context, event domain.Event) error {
+	booksLent := storagedomain.BooksLentToClassEvent{}
+	if err := event.GetJsonData(&booksLent); err != nil {
+		return err
+	}
+	return h.updateBooks(ctx, event, booksLent.StorageID, func(books []storagedomain.BookInStorage) []storagedomain.BookInStorage {
+		return takeBooks(books, booksLent.BookID, booksLent.Quantity)
+	})
+}
+
+func (h StorageEventHandler) handleBooksReturnedFromClass(ctx context.Context, event domain.Event) error {
+	booksReturned := storagedomain.BooksReturnedFromClassEvent{}
+	if err := event.GetJsonData(&booksReturned); err != nil {
+		return err
+	}
+	book := storagedomain.BookInStorage{
+		BookID:   booksReturned.BookID,
+		Isbn:     booksReturned.Isbn,
+		Title:    booksReturned.Title,
+		Quantity: booksReturned.Quantity,
+	}
+	return h.updateBooks(ctx, event, booksReturned.StorageID, func(books []storagedomain.BookInStorage) []storagedomain.BookInStorage {
+		return putBooks(books, book)
+	})
+}
+
+func (h StorageEventHandler) handleStockCorrected(ctx context.Context, event domain.Event) error {
+	stockCorrected := storagedomain.StockCorrectedEvent{}
+	if err := event.GetJsonData(&stockCorrected); err != nil {
+		return err
+	}
+	return h.updateBooks(ctx, event, stockCorrected.StorageID, func(books []storagedomain.BookInStorage) []storagedomain.BookInStorage {
+		if stockCorrected.Difference < 0 {
+			return takeBooks(books, stockCorrected.BookID, -stockCorrected.Difference)
+		}
+		return putBooks(books, storagedomain.BookInStorage{
+			BookID:   stockCorrected.BookID,
+			Isbn:     stockCorrected.Isbn,
+			Title:    stockCorrected.Title,
+			Quantity: stockCorrected.Difference,
+		})
+	})
+}
+
+func (h StorageEventHandler) handleBooksDamaged(ctx context.Context, event domain.Event) error {
+	booksDamaged := storagedomain.BooksDamagedEvent{}
+	if err := event.GetJsonData(&booksDamaged); err != nil {
+		return err
+	}
+	return h.updateBooks(ctx, event, booksDamaged.StorageID, func(books []storagedomain.BookInStorage) []storagedomain.BookInStorage {
+		return regradeBooks(books, booksDamaged.BookID, booksDamaged.From, booksDamaged.Condition, booksDamaged.Quantity)
+	})
+}
+
+func (h StorageEventHandler) handleBooksWrittenOff(ctx context.Context, event domain.Event) error {
+	booksWrittenOff := storagedomain.BooksWrittenOffEvent{}
+	if err := event.GetJsonData(&booksWrittenOff); err != nil {
+		return err
+	}
+	return h.updateBooks(ctx, event, booksWrittenOff.StorageID, func(books []storagedomain.BookInStorage) []storagedomain.BookInStorage {
+		books = regradeBooks(books, booksWrittenOff.BookID, booksWrittenOff.Condition, storagedomain.Good, booksWrittenOff.Quantity)
+		return takeBooks(books, booksWrittenOff.BookID, booksWrittenOff.Quantity)
+	})
+}
+
+func putBooks(books []storagedomain.BookInStorage, book storagedomain.BookInStorage) []storagedomain.BookInStorage {
+	for idx, b := range books {
+		if b.BookID == book.BookID {
+			books[idx].Quantity += book.Quantity
+			return books
+		}
+	}
+	return append(books, book)
+}
+
+func takeBooks(books []storagedomain.BookInStorage, bookID string, quantity int) []storagedomain.BookInStorage {
+	for idx, book := range books {
+		if book.BookID == bookID {
+			books[idx].Quantity -= quantity
+			if books[idx].Quantity <= 0 {
+				return append(books[:idx], books[idx+1:]...)
+			}
+			books[idx].Conditions = storagedomain.Withdraw(books[idx].Conditions, books[idx].Quantity)
+			return books
+		}
+	}
+	return books
+}
+
+func regradeBooks(books []storagedomain.BookInStorage, bookID string, from, to storagedomain.Condition, quantity int) []storagedomain.BookInStorage {
+	for idx, book := range books {
+		if book.BookID == bookID {
+			books[idx].Conditions = storagedomain.Regrade(book.Conditions, from, to, quantity)
+		}
+	}
+	return books
+}
+
+// updateBooks changes the books of a storage relative to the stored quantities.
+// Events the storage already reflects are skipped, so a redelivered movement is
+// not counted twice.
+func (h StorageEventHandler) updateBooks(
+	ctx context.Context,
+	event domain.Event,
+	storageID string,
+	update func(books []storagedomain.BookInStorage) []storagedomain.BookInStorage,
+) error {
+	storage, err := h.repository.GetStorageByID(ctx, event.AggregateID(), storageID)
+	if err != nil {
+		return err
+	}
+	if storage.Version >= event.EventVersion() {
+		return nil
 	}
-	h.repository.UpdateStorageLocation(ctx, storageRelocated.StorageID, storageRelocated.Location)
+	books := append([]storagedomain.BookInStorage{}, storage.Books...)
+	return h.repository.UpdateStorageBooks(ctx, storageID, update(books), event.EventVersion())
 }
 
 type TestHandler struct{}
 
-func (h TestHandler) Handle(ctx context.Context, eventBytes []byte) {
+func (h TestHandler) Handle(ctx context.Context, eventBytes []byte) error {
 	fmt.Printf("%s", eventBytes)
+	return nil
 }
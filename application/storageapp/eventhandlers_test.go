@@ -6,6 +6,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/kammeph/school-book-storage-service/application"
 	"github.com/kammeph/school-book-storage-service/application/storageapp"
 	"github.com/kammeph/school-book-storage-service/domain"
 	"github.com/kammeph/school-book-storage-service/domain/storagedomain"
@@ -43,12 +44,20 @@ var (
 		Type:    storagedomain.StorageRemoved,
 		Data:    "{\"storageId\":\"storage1\",\"reason\":\"test\"}",
 	}
+	invalidEventData = domain.EventModel{
+		ID:      "school1",
+		Version: 5,
+		At:      time.Now(),
+		Type:    storagedomain.StorageAdded,
+		Data:    "{\"storageId\":",
+	}
 )
 
 func TestHandle(t *testing.T) {
 	tests := []struct {
-		name  string
-		event domain.Event
+		name        string
+		event       domain.Event
+		expectError bool
 	}{
 		{
 			name:  "storage added",
@@ -66,11 +75,257 @@ func TestHandle(t *testing.T) {
 			name:  "storage removed",
 			event: &storageRemoved,
 		},
+		{
+			name:        "invalid event data",
+			event:       &invalidEventData,
+			expectError: true,
+		},
 	}
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
 			eventBytes, _ := json.Marshal(test.event)
-			assert.NotPanics(t, func() { eventHandler.Handle(context.Background(), eventBytes) })
+			err := eventHandler.Handle(context.Background(), eventBytes)
+			if test.expectError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
 		})
 	}
 }
+
+func TestHandleRedeliveredEvents(t *testing.T) {
+	ctx := context.Background()
+	repository := memory.NewMemoryRepository()
+	handler := storageapp.NewStorageEventHandler(repository)
+	for _, event := range []domain.Event{&storageAdded, &storageRenamed, &storageAdded, &storageRenamed} {
+		eventBytes, _ := json.Marshal(event)
+		assert.NoError(t, handler.Handle(ctx, eventBytes))
+	}
+	storage, err := repository.GetStorageByID(ctx, "school1", "storage1")
+	assert.NoError(t, err)
+	assert.Equal(t, "closet renamed", storage.Name)
+	assert.Equal(t, storageRenamed.Version, storage.Version)
+}
+
+func TestHandleBookMovements(t *testing.T) {
+	ctx := context.Background()
+	repository := memory.NewMemoryRepository()
+	handler := storageapp.NewStorageEventHandler(repository)
+	booksPut := domain.EventModel{
+		ID:      "school1",
+		Version: 2,
+		At:      time.Now(),
+		Type:    storagedomain.BooksPut,
+		Data:    "{\"storageId\":\"storage1\",\"bookId\":\"book1\",\"isbn\":\"978-3-12-732320-7\",\"title\":\"Green Line 1\",\"quantity\":10}",
+	}
+	booksTaken := domain.EventModel{
+		ID:      "school1",
+		Version: 3,
+		At:      time.Now(),
+		Type:    storagedomain.BooksTaken,
+		Data:    "{\"storageId\":\"storage1\",\"bookId\":\"book1\",\"quantity\":4,\"reason\":\"test\"}",
+	}
+	for _, event := range []domain.Event{&storageAdded, &booksPut, &booksTaken, &booksPut, &booksTaken} {
+		eventBytes, _ := json.Marshal(event)
+		assert.NoError(t, handler.Handle(ctx, eventBytes))
+	}
+	storage, err := repository.GetStorageByID(ctx, "school1", "storage1")
+	assert.NoError(t, err)
+	assert.Equal(t, []storagedomain.BookInStorage{{BookID: "book1", Isbn: "9783127323207", Title: "Green Line 1", Quantity: 6}}, storage.Books)
+	assert.Equal(t, booksTaken.Version, storage.Version)
+}
+
+func TestHandleBooksTransferred(t *testing.T) {
+	ctx := context.Background()
+	repository := memory.NewMemoryRepositoryWithStorages([]storagedomain.StorageWithBooks{
+		{SchoolID: "school1", StorageID: "from", Books: []storagedomain.BookInStorage{{BookID: "book1", Title: "Green Line 1", Quantity: 10}}, Version: 1},
+		{SchoolID: "school1", StorageID: "to", Books: []storagedomain.BookInStorage{}, Version: 2},
+	})
+	handler := storageapp.NewStorageEventHandler(repository)
+	booksTransferred := domain.EventModel{
+		ID:      "school1",
+		Version: 3,
+		At:      time.Now(),
+		Type:    storagedomain.BooksTransferred,
+		Data:    "{\"fromStorageId\":\"from\",\"toStorageId\":\"to\",\"bookId\":\"book1\",\"title\":\"Green Line 1\",\"quantity\":4,\"reason\":\"test\"}",
+	}
+	for idx := 0; idx < 2; idx++ {
+		eventBytes, _ := json.Marshal(&booksTransferred)
+		assert.NoError(t, handler.Handle(ctx, eventBytes))
+	}
+	from, err := repository.GetStorageByID(ctx, "school1", "from")
+	assert.NoError(t, err)
+	assert.Equal(t, 6, from.Books[0].Quantity)
+	to, err := repository.GetStorageByID(ctx, "school1", "to")
+	assert.NoError(t, err)
+	assert.Equal(t, []storagedomain.BookInStorage{{BookID: "book1", Title: "Green Line 1", Quantity: 4}}, to.Books)
+}
+
+func TestHandleStockCorrected(t *testing.T) {
+	ctx := context.Background()
+	repository := memory.NewMemoryRepositoryWithStorages([]storagedomain.StorageWithBooks{
+		{SchoolID: "school1", StorageID: "storage1", Books: []storagedomain.BookInStorage{{BookID: "book1", Title: "Green Line 1", Quantity: 10}}, Version: 1},
+	})
+	handler := storageapp.NewStorageEventHandler(repository)
+	events := []domain.EventModel{
+		{
+			ID:      "school1",
+			Version: 2,
+			At:      time.Now(),
+			Type:    storagedomain.StockCorrected,
+			Data:    "{\"stocktakingId\":\"stocktaking1\",\"storageId\":\"storage1\",\"bookId\":\"book1\",\"title\":\"Green Line 1\",\"difference\":-3,\"reason\":\"test\"}",
+		},
+		{
+			ID:      "school1",
+			Version: 3,
+			At:      time.Now(),
+			Type:    storagedomain.StockCorrected,
+			Data:    "{\"stocktakingId\":\"stocktaking1\",\"storageId\":\"storage1\",\"bookId\":\"book2\",\"title\":\"Green Line 2\",\"difference\":2,\"reason\":\"test\"}",
+		},
+	}
+	for _, event := range append(events, events...) {
+		eventBytes, _ := json.Marshal(&event)
+		assert.NoError(t, handler.Handle(ctx, eventBytes))
+	}
+	storage, err := repository.GetStorageByID(ctx, "school1", "storage1")
+	assert.NoError(t, err)
+	assert.Equal(t, []storagedomain.BookInStorage{
+		{BookID: "book1", Title: "Green Line 1", Quantity: 7},
+		{BookID: "book2", Title: "Green Line 2", Quantity: 2},
+	}, storage.Books)
+}
+
+func TestHandleStocktakingEvents(t *testing.T) {
+	ctx := context.Background()
+	repository := memory.NewMemoryStocktakingRepository()
+	handler := storageapp.NewStocktakingEventHandler(repository)
+	events := []domain.EventModel{
+		{
+			ID:      "school1",
+			Version: 1,
+			At:      time.Now(),
+			Type:    storagedomain.StocktakingOpened,
+			Data:    "{\"schoolId\":\"school1\",\"stocktakingId\":\"stocktaking1\",\"storageIds\":[\"storage1\"]}",
+		},
+		{
+			ID:      "school1",
+			Version: 2,
+			At:      time.Now(),
+			Type:    storagedomain.StockCounted,
+			Data:    "{\"stocktakingId\":\"stocktaking1\",\"storageId\":\"storage1\",\"bookId\":\"book1\",\"quantity\":3}",
+		},
+		{
+			ID:      "school1",
+			Version: 3,
+			At:      time.Now(),
+			Type:    storagedomain.StockCounted,
+			Data:    "{\"stocktakingId\":\"stocktaking1\",\"storageId\":\"storage1\",\"bookId\":\"book1\",\"quantity\":4}",
+		},
+		{
+			ID:      "school1",
+			Version: 4,
+			At:      time.Now(),
+			Type:    storagedomain.StocktakingClosed,
+			Data:    "{\"stocktakingId\":\"stocktaking1\"}",
+		},
+	}
+	for _, event := range append(events, events...) {
+		eventBytes, _ := json.Marshal(&event)
+		assert.NoError(t, handler.Handle(ctx, eventBytes))
+	}
+	stocktaking, err := repository.GetStocktakingByID(ctx, "school1", "stocktaking1")
+	assert.NoError(t, err)
+	assert.Equal(t, []storagedomain.StockCount{{StorageID: "storage1", BookID: "book1", Quantity: 4}}, stocktaking.Counts)
+	assert.True(t, stocktaking.Closed)
+	assert.Equal(t, 4, stocktaking.Version)
+}
+
+func TestHandleBooksDamagedAndWrittenOff(t *testing.T) {
+	ctx := context.Background()
+	repository := memory.NewMemoryRepositoryWithStorages([]storagedomain.StorageWithBooks{
+		{SchoolID: "school1", StorageID: "storage1", Books: []storagedomain.BookInStorage{{BookID: "book1", Title: "Green Line 1", Quantity: 10}}, Version: 1},
+	})
+	writeOffs := memory.NewMemoryWriteOffRepository()
+	handlers := []application.EventHandler{storageapp.NewStorageEventHandler(repository), storageapp.NewWriteOffEventHandler(writeOffs)}
+	writtenOffAt := time.Date(2022, time.October, 1, 0, 0, 0, 0, time.UTC)
+	events := []domain.EventModel{
+		{
+			ID:      "school1",
+			Version: 2,
+			At:      writtenOffAt,
+			Type:    storagedomain.BooksDamaged,
+			Data:    "{\"storageId\":\"storage1\",\"bookId\":\"book1\",\"from\":\"good\",\"condition\":\"lost\",\"quantity\":4,\"reason\":\"test\"}",
+		},
+		{
+			ID:      "school1",
+			Version: 3,
+			At:      writtenOffAt,
+			Type:    storagedomain.BooksWrittenOff,
+			Data:    "{\"schoolId\":\"school1\",\"storageId\":\"storage1\",\"bookId\":\"book1\",\"title\":\"Green Line 1\",\"condition\":\"lost\",\"quantity\":3,\"reason\":\"test\"}",
+		},
+	}
+	for _, event := range append(events, events...) {
+		eventBytes, _ := json.Marshal(&event)
+		for _, handler := range handlers {
+			assert.NoError(t, handler.Handle(ctx, eventBytes))
+		}
+	}
+	storage, err := repository.GetStorageByID(ctx, "school1", "storage1")
+	assert.NoError(t, err)
+	assert.Equal(t, []storagedomain.BookInStorage{{
+		BookID:     "book1",
+		Title:      "Green Line 1",
+		Quantity:   7,
+		Conditions: []storagedomain.ConditionCount{{Condition: storagedomain.Lost, Quantity: 1}},
+	}}, storage.Books)
+	written, err := writeOffs.GetWriteOffs(ctx, "school1", writtenOffAt, writtenOffAt.AddDate(0, 0, 1))
+	assert.NoError(t, err)
+	assert.Len(t, written, 1)
+	assert.Equal(t, 3, written[0].Quantity)
+}
+
+func TestHandleLocationEvents(t *testing.T) {
+	ctx := context.Background()
+	repository := memory.NewMemoryLocationRepository()
+	handler := storageapp.NewLocationEventHandler(repository)
+	events := []domain.EventModel{
+		{
+			ID:      "school1",
+			Version: 1,
+			At:      time.Now(),
+			Type:    storagedomain.LocationAdded,
+			Data:    "{\"schoolId\":\"school1\",\"locationId\":\"main\",\"kind\":\"building\",\"name\":\"Main building\"}",
+		},
+		{
+			ID:      "school1",
+			Version: 2,
+			At:      time.Now(),
+			Type:    storagedomain.LocationAdded,
+			Data:    "{\"schoolId\":\"school1\",\"locationId\":\"101\",\"kind\":\"room\",\"name\":\"Room 101\",\"parentId\":\"main\"}",
+		},
+		{
+			ID:      "school1",
+			Version: 3,
+			At:      time.Now(),
+			Type:    storagedomain.LocationRenamed,
+			Data:    "{\"locationId\":\"main\",\"name\":\"Old building\",\"reason\":\"test\"}",
+		},
+		{
+			ID:      "school1",
+			Version: 4,
+			At:      time.Now(),
+			Type:    storagedomain.LocationRemoved,
+			Data:    "{\"locationId\":\"101\",\"reason\":\"test\"}",
+		},
+	}
+	for _, event := range append(events, events...) {
+		eventBytes, _ := json.Marshal(&event)
+		assert.NoError(t, handler.Handle(ctx, eventBytes))
+	}
+	locations, err := repository.GetLocationsBySchoolID(ctx, "school1")
+	assert.NoError(t, err)
+	assert.Equal(t, []storagedomain.LocationProjection{
+		{SchoolID: "school1", LocationID: "main", Kind: storagedomain.Building, Name: "Old building", Version: 3},
+	}, locations)
+}
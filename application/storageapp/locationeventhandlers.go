@@ -0,0 +1,66 @@
+package storageapp
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/kammeph/school-book-storage-service/application"
+	"github.com/kammeph/school-book-storage-service/domain"
+	"github.com/kammeph/school-book-storage-service/domain/storagedomain"
+)
+
+type LocationEventHandler struct {
+	repository LocationRepository
+}
+
+func NewLocationEventHandler(repository LocationRepository) application.EventHandler {
+	return &LocationEventHandler{repository}
+}
+
+func (h LocationEventHandler) Handle(ctx context.Context, eventBytes []byte) error {
+	event := &domain.EventModel{}
+	if err := json.Unmarshal(eventBytes, event); err != nil {
+		return err
+	}
+	switch event.EventType() {
+	case storagedomain.LocationAdded:
+		return h.handleLocationAdded(ctx, event)
+	case storagedomain.LocationRenamed:
+		return h.handleLocationRenamed(ctx, event)
+	case storagedomain.LocationRemoved:
+		return h.handleLocationRemoved(ctx, event)
+	default:
+		return nil
+	}
+}
+
+func (h LocationEventHandler) handleLocationAdded(ctx context.Context, event domain.Event) error {
+	locationAdded := storagedomain.LocationAddedEvent{}
+	if err := event.GetJsonData(&locationAdded); err != nil {
+		return err
+	}
+	location := storagedomain.NewLocationProjection(
+		locationAdded.SchoolID,
+		locationAdded.LocationID,
+		locationAdded.Kind,
+		locationAdded.Name,
+		locationAdded.ParentID,
+		event.EventVersion())
+	return h.repository.UpsertLocation(ctx, location)
+}
+
+func (h LocationEventHandler) handleLocationRenamed(ctx context.Context, event domain.Event) error {
+	locationRenamed := storagedomain.LocationRenamedEvent{}
+	if err := event.GetJsonData(&locationRenamed); err != nil {
+		return err
+	}
+	return h.repository.UpdateLocationName(ctx, locationRenamed.LocationID, locationRenamed.Name, event.EventVersion())
+}
+
+func (h LocationEventHandler) handleLocationRemoved(ctx context.Context, event domain.Event) error {
+	locationRemoved := storagedomain.LocationRemovedEvent{}
+	if err := event.GetJsonData(&locationRemoved); err != nil {
+		return err
+	}
+	return h.repository.DeleteLocation(ctx, locationRemoved.LocationID, event.EventVersion())
+}
@@ -2,22 +2,45 @@ package storageapp
 
 import (
 	"context"
+	"time"
 
 	"github.com/kammeph/school-book-storage-service/application"
+	"github.com/kammeph/school-book-storage-service/application/bookapp"
+	"github.com/kammeph/school-book-storage-service/application/labelapp"
+	"github.com/kammeph/school-book-storage-service/domain"
 	"github.com/kammeph/school-book-storage-service/domain/storagedomain"
+	"github.com/kammeph/school-book-storage-service/fp"
 )
 
 type StorageQueryHandlers struct {
-	GetAllHandler           GetAllStoragesQueryHandler
-	GetStorageByIDHandler   GetStorageByIDQueryHandler
-	GetStorageByNameHandler GetStorageByNameQueryHandler
+	GetAllHandler              GetAllStoragesQueryHandler
+	GetStorageByIDHandler      GetStorageByIDQueryHandler
+	GetStorageByNameHandler    GetStorageByNameQueryHandler
+	GetStocktakingsHandler     GetStocktakingsQueryHandler
+	GetDiscrepanciesHandler    GetDiscrepanciesQueryHandler
+	GetWriteOffsHandler        GetWriteOffReportQueryHandler
+	GetLabelsHandler           GetStorageLabelsQueryHandler
+	GetLocationsHandler        GetLocationsQueryHandler
+	GetLocationContentsHandler GetLocationContentsQueryHandler
 }
 
-func NewStorageQueryHandlers(repository StorageWithBooksRepository) StorageQueryHandlers {
+func NewStorageQueryHandlers(
+	repository StorageWithBooksRepository,
+	stocktakings StocktakingRepository,
+	locations LocationRepository,
+	writeOffs WriteOffRepository,
+	books bookapp.BookRepository,
+) StorageQueryHandlers {
 	return StorageQueryHandlers{
-		GetAllHandler:           NewGetAllStoragesQueryHandler(repository),
-		GetStorageByIDHandler:   NewGetStorageByIDQueryHandler(repository),
-		GetStorageByNameHandler: NewGetStorageByNameQueryHandler(repository),
+		GetAllHandler:              NewGetAllStoragesQueryHandler(repository),
+		GetStorageByIDHandler:      NewGetStorageByIDQueryHandler(repository),
+		GetStorageByNameHandler:    NewGetStorageByNameQueryHandler(repository),
+		GetStocktakingsHandler:     NewGetStocktakingsQueryHandler(stocktakings),
+		GetDiscrepanciesHandler:    NewGetDiscrepanciesQueryHandler(repository, stocktakings),
+		GetWriteOffsHandler:        NewGetWriteOffReportQueryHandler(writeOffs, books),
+		GetLabelsHandler:           NewGetStorageLabelsQueryHandler(repository),
+		GetLocationsHandler:        NewGetLocationsQueryHandler(locations),
+		GetLocationContentsHandler: NewGetLocationContentsQueryHandler(locations, repository),
 	}
 }
 
@@ -62,6 +85,48 @@ func (h GetStorageByIDQueryHandler) Handle(ctx context.Context, query GetStorage
 	return h.repository.GetStorageByID(ctx, query.AggregateID(), query.StorageID)
 }
 
+// GetStorageLabels selects the storages to print labels for. All storages of
+// the school are selected if no IDs are given.
+type GetStorageLabels struct {
+	application.QueryModel
+	StorageIDs []string
+}
+
+func NewGetStorageLabels(aggregateID string, storageIDs []string) GetStorageLabels {
+	return GetStorageLabels{QueryModel: application.QueryModel{ID: aggregateID}, StorageIDs: storageIDs}
+}
+
+type GetStorageLabelsQueryHandler struct {
+	repository StorageWithBooksRepository
+}
+
+func NewGetStorageLabelsQueryHandler(repository StorageWithBooksRepository) GetStorageLabelsQueryHandler {
+	return GetStorageLabelsQueryHandler{repository: repository}
+}
+
+func (h GetStorageLabelsQueryHandler) Handle(ctx context.Context, query GetStorageLabels) ([]labelapp.Label, error) {
+	storages := []storagedomain.StorageWithBooks{}
+	if len(query.StorageIDs) == 0 {
+		all, err := h.repository.GetAllStoragesBySchoolID(ctx, query.AggregateID())
+		if err != nil {
+			return nil, err
+		}
+		storages = all
+	}
+	for _, storageID := range query.StorageIDs {
+		storage, err := h.repository.GetStorageByID(ctx, query.AggregateID(), storageID)
+		if err != nil {
+			return nil, err
+		}
+		storages = append(storages, storage)
+	}
+	labels := []labelapp.Label{}
+	for _, storage := range storages {
+		labels = append(labels, labelapp.NewStorageLabel(storage.StorageID, storage.Name, storage.Location))
+	}
+	return labels, nil
+}
+
 type GetStorageByName struct {
 	application.QueryModel
 	Name string
@@ -82,3 +147,221 @@ func NewGetStorageByNameQueryHandler(repositoty StorageWithBooksRepository) GetS
 func (h GetStorageByNameQueryHandler) Handle(ctx context.Context, query GetStorageByName) (storagedomain.StorageWithBooks, error) {
 	return h.repository.GetStorageByName(ctx, query.AggregateID(), query.Name)
 }
+
+type GetStocktakings struct {
+	application.QueryModel
+}
+
+func NewGetStocktakings(aggregateID string) GetStocktakings {
+	return GetStocktakings{QueryModel: application.QueryModel{ID: aggregateID}}
+}
+
+type GetStocktakingsQueryHandler struct {
+	repository StocktakingRepository
+}
+
+func NewGetStocktakingsQueryHandler(repository StocktakingRepository) GetStocktakingsQueryHandler {
+	return GetStocktakingsQueryHandler{repository: repository}
+}
+
+func (h GetStocktakingsQueryHandler) Handle(ctx context.Context, query GetStocktakings) ([]storagedomain.StocktakingProjection, error) {
+	return h.repository.GetStocktakingsBySchoolID(ctx, query.AggregateID())
+}
+
+type GetLocations struct {
+	application.QueryModel
+}
+
+func NewGetLocations(aggregateID string) GetLocations {
+	return GetLocations{QueryModel: application.QueryModel{ID: aggregateID}}
+}
+
+type GetLocationsQueryHandler struct {
+	repository LocationRepository
+}
+
+func NewGetLocationsQueryHandler(repository LocationRepository) GetLocationsQueryHandler {
+	return GetLocationsQueryHandler{repository: repository}
+}
+
+func (h GetLocationsQueryHandler) Handle(ctx context.Context, query GetLocations) ([]storagedomain.LocationProjection, error) {
+	return h.repository.GetLocationsBySchoolID(ctx, query.AggregateID())
+}
+
+// LocationContents lists everything within a building, floor, room or shelf:
+// the locations nested in it, the storages placed there and the books they
+// hold together.
+type LocationContents struct {
+	Location  storagedomain.LocationProjection   `json:"location"`
+	Path      string                             `json:"path"`
+	Locations []storagedomain.LocationProjection `json:"locations"`
+	Storages  []storagedomain.StorageWithBooks   `json:"storages"`
+	Books     []storagedomain.BookInStorage      `json:"books"`
+}
+
+type GetLocationContents struct {
+	application.QueryModel
+	LocationID string
+}
+
+func NewGetLocationContents(aggregateID, locationID string) GetLocationContents {
+	return GetLocationContents{QueryModel: application.QueryModel{ID: aggregateID}, LocationID: locationID}
+}
+
+type GetLocationContentsQueryHandler struct {
+	locations LocationRepository
+	storages  StorageWithBooksRepository
+}
+
+func NewGetLocationContentsQueryHandler(locations LocationRepository, storages StorageWithBooksRepository) GetLocationContentsQueryHandler {
+	return GetLocationContentsQueryHandler{locations: locations, storages: storages}
+}
+
+func (h GetLocationContentsQueryHandler) Handle(ctx context.Context, query GetLocationContents) (LocationContents, error) {
+	projections, err := h.locations.GetLocationsBySchoolID(ctx, query.AggregateID())
+	if err != nil {
+		return LocationContents{}, err
+	}
+	locations := []storagedomain.Location{}
+	for _, projection := range projections {
+		locations = append(locations, projection.Location())
+	}
+	location := fp.Find(projections, func(l storagedomain.LocationProjection) bool { return l.LocationID == query.LocationID })
+	if location == nil {
+		return LocationContents{}, storagedomain.ErrLocationIDNotFound(query.LocationID)
+	}
+	within := storagedomain.LocationsWithin(locations, query.LocationID)
+	storages, err := h.storages.GetStoragesByLocationIDs(ctx, query.AggregateID(), within)
+	if err != nil {
+		return LocationContents{}, err
+	}
+	contents := LocationContents{
+		Location: *location,
+		Path:     storagedomain.LocationPath(locations, query.LocationID),
+		Locations: fp.Filter(projections, func(l storagedomain.LocationProjection) bool {
+			return l.LocationID != query.LocationID && fp.Some(within, func(id string) bool { return id == l.LocationID })
+		}),
+		Storages: storages,
+		Books:    []storagedomain.BookInStorage{},
+	}
+	for _, storage := range storages {
+		for _, book := range storage.Books {
+			contents.Books = putBooks(contents.Books, storagedomain.BookInStorage{
+				BookID:   book.BookID,
+				Isbn:     book.Isbn,
+				Title:    book.Title,
+				Quantity: book.Quantity,
+			})
+		}
+	}
+	return contents, nil
+}
+
+type GetDiscrepancies struct {
+	application.QueryModel
+	StocktakingID string
+}
+
+func NewGetDiscrepancies(aggregateID, stocktakingID string) GetDiscrepancies {
+	return GetDiscrepancies{QueryModel: application.QueryModel{ID: aggregateID}, StocktakingID: stocktakingID}
+}
+
+type GetDiscrepanciesQueryHandler struct {
+	storages     StorageWithBooksRepository
+	stocktakings StocktakingRepository
+}
+
+func NewGetDiscrepanciesQueryHandler(storages StorageWithBooksRepository, stocktakings StocktakingRepository) GetDiscrepanciesQueryHandler {
+	return GetDiscrepanciesQueryHandler{storages: storages, stocktakings: stocktakings}
+}
+
+// Handle compares the counts of the stocktaking with the recorded stock of its
+// storages. The report is only meaningful while the stocktaking is open.
+func (h GetDiscrepanciesQueryHandler) Handle(ctx context.Context, query GetDiscrepancies) ([]storagedomain.Discrepancy, error) {
+	stocktaking, err := h.stocktakings.GetStocktakingByID(ctx, query.AggregateID(), query.StocktakingID)
+	if err != nil {
+		return nil, err
+	}
+	stock := map[string][]storagedomain.BookStock{}
+	for _, storageID := range stocktaking.StorageIDs {
+		storage, err := h.storages.GetStorageByID(ctx, query.AggregateID(), storageID)
+		if err != nil {
+			return nil, err
+		}
+		for _, book := range storage.Books {
+			stock[storageID] = append(stock[storageID], storagedomain.BookStock{
+				BookID:   book.BookID,
+				Isbn:     book.Isbn,
+				Title:    book.Title,
+				Quantity: book.Quantity,
+			})
+		}
+	}
+	return storagedomain.Discrepancies(
+		stocktaking.StorageIDs,
+		stocktaking.Counts,
+		func(storageID string) []storagedomain.BookStock { return stock[storageID] }), nil
+}
+
+// WrittenOffBooks are copies of a book written off at once, valued at the
+// price the book had at that time.
+type WrittenOffBooks struct {
+	storagedomain.WriteOffProjection
+	UnitPrice domain.Money `json:"unitPrice"`
+	Value     domain.Money `json:"value"`
+}
+
+type WriteOffReport struct {
+	Year      int               `json:"year"`
+	WriteOffs []WrittenOffBooks `json:"writeOffs"`
+	Total     domain.Money      `json:"total"`
+}
+
+// GetWriteOffReport asks for the books written off in the school year that
+// starts in the given year. A school year starts on the first of August.
+type GetWriteOffReport struct {
+	application.QueryModel
+	Year int
+}
+
+func NewGetWriteOffReport(aggregateID string, year int) GetWriteOffReport {
+	return GetWriteOffReport{QueryModel: application.QueryModel{ID: aggregateID}, Year: year}
+}
+
+type GetWriteOffReportQueryHandler struct {
+	writeOffs WriteOffRepository
+	books     bookapp.BookRepository
+}
+
+func NewGetWriteOffReportQueryHandler(writeOffs WriteOffRepository, books bookapp.BookRepository) GetWriteOffReportQueryHandler {
+	return GetWriteOffReportQueryHandler{writeOffs: writeOffs, books: books}
+}
+
+// Handle values the written off books with the price history of the books.
+// Books that are no longer in the catalogue are valued at zero.
+func (h GetWriteOffReportQueryHandler) Handle(ctx context.Context, query GetWriteOffReport) (WriteOffReport, error) {
+	from := time.Date(query.Year, time.August, 1, 0, 0, 0, 0, time.UTC)
+	writeOffs, err := h.writeOffs.GetWriteOffs(ctx, query.AggregateID(), from, from.AddDate(1, 0, 0))
+	if err != nil {
+		return WriteOffReport{}, err
+	}
+	books, err := h.books.GetBooksBySchoolID(ctx, query.AggregateID())
+	if err != nil {
+		return WriteOffReport{}, err
+	}
+	report := WriteOffReport{Year: query.Year, WriteOffs: []WrittenOffBooks{}}
+	for _, writeOff := range writeOffs {
+		line := WrittenOffBooks{WriteOffProjection: writeOff}
+		for _, book := range books {
+			if book.BookID == writeOff.BookID {
+				line.UnitPrice = book.PriceAt(writeOff.WrittenOffAt)
+			}
+		}
+		line.Value = line.UnitPrice.Times(writeOff.Quantity)
+		if report.Total, err = report.Total.Add(line.Value); err != nil {
+			return WriteOffReport{}, err
+		}
+		report.WriteOffs = append(report.WriteOffs, line)
+	}
+	return report, nil
+}
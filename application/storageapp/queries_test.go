@@ -3,17 +3,22 @@ package storageapp_test
 import (
 	"context"
 	"testing"
+	"time"
 
+	"github.com/kammeph/school-book-storage-service/application/labelapp"
 	"github.com/kammeph/school-book-storage-service/application/storageapp"
+	"github.com/kammeph/school-book-storage-service/domain"
+	"github.com/kammeph/school-book-storage-service/domain/bookdomain"
 	"github.com/kammeph/school-book-storage-service/domain/storagedomain"
 	"github.com/kammeph/school-book-storage-service/infrastructure/memory"
+	"github.com/kammeph/school-book-storage-service/testing/fixtures"
 	"github.com/stretchr/testify/assert"
 )
 
 var (
-	storage1School1        = storagedomain.NewStorageWithBooks("school1", "storage1School1", "Closet 1", "Room 101")
-	storage2School1        = storagedomain.NewStorageWithBooks("school1", "storage2School1", "Closet 2", "Room 101")
-	storage1School2        = storagedomain.NewStorageWithBooks("school2", "storage1School2", "Closet 1", "Room 203")
+	storage1School1        = storagedomain.NewStorageWithBooks("school1", "storage1School1", "Closet 1", "Room 101", 1)
+	storage2School1        = storagedomain.NewStorageWithBooks("school1", "storage2School1", "Closet 2", "Room 101", 1)
+	storage1School2        = storagedomain.NewStorageWithBooks("school2", "storage1School2", "Closet 1", "Room 203", 1)
 	emptyRepository        = memory.NewMemoryRepository()
 	repositoryWithStorages = memory.NewMemoryRepositoryWithStorages(
 		[]storagedomain.StorageWithBooks{storage1School1, storage2School1, storage1School2})
@@ -179,3 +184,114 @@ func TestGetStoragesByName(t *testing.T) {
 		})
 	}
 }
+
+func TestGetDiscrepancies(t *testing.T) {
+	ctx := context.Background()
+	storages := memory.NewMemoryRepositoryWithStorages([]storagedomain.StorageWithBooks{
+		{SchoolID: "school1", StorageID: "storage1", Books: []storagedomain.BookInStorage{{BookID: "book1", Title: "Green Line 1", Quantity: 10}}},
+	})
+	stocktakings := memory.NewMemoryStocktakingRepository()
+	stocktaking := storagedomain.NewStocktakingProjection("school1", "stocktaking1", []string{"storage1"}, time.Now(), 1)
+	stocktaking.Counts = []storagedomain.StockCount{{StorageID: "storage1", BookID: "book1", Quantity: 8}}
+	assert.NoError(t, stocktakings.UpsertStocktaking(ctx, stocktaking))
+	handler := storageapp.NewGetDiscrepanciesQueryHandler(storages, stocktakings)
+
+	discrepancies, err := handler.Handle(ctx, storageapp.NewGetDiscrepancies("school1", "stocktaking1"))
+	assert.NoError(t, err)
+	assert.Equal(t, []storagedomain.Discrepancy{
+		{StorageID: "storage1", BookID: "book1", Title: "Green Line 1", Expected: 10, Counted: 8, Difference: -2},
+	}, discrepancies)
+
+	_, err = handler.Handle(ctx, storageapp.NewGetDiscrepancies("school1", "unknown"))
+	assert.Error(t, err)
+}
+
+func TestGetWriteOffReport(t *testing.T) {
+	ctx := context.Background()
+	added := time.Date(2022, time.January, 1, 0, 0, 0, 0, time.UTC)
+	increased := time.Date(2022, time.September, 1, 0, 0, 0, 0, time.UTC)
+	books := memory.NewMemoryBookRepository()
+	book := bookdomain.NewBookProjection("school1", "book1", "123", "Green Line 1", "", fixtures.Euro(3000), []int{5}, 2)
+	book.PriceHistory = []bookdomain.PriceChange{{Price: fixtures.Euro(2000), ValidFrom: added}, {Price: fixtures.Euro(3000), ValidFrom: increased}}
+	assert.NoError(t, books.UpsertBook(ctx, book))
+	writeOffs := memory.NewMemoryWriteOffRepository()
+	for idx, writtenOffAt := range []time.Time{
+		time.Date(2022, time.July, 31, 0, 0, 0, 0, time.UTC),
+		time.Date(2022, time.August, 15, 0, 0, 0, 0, time.UTC),
+		time.Date(2023, time.March, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2023, time.March, 1, 0, 0, 0, 0, time.UTC),
+	} {
+		bookID := "book1"
+		if idx == 3 {
+			bookID = "removed"
+		}
+		assert.NoError(t, writeOffs.InsertWriteOff(ctx, storagedomain.WriteOffProjection{
+			SchoolID:     "school1",
+			BookID:       bookID,
+			Quantity:     2,
+			WrittenOffAt: writtenOffAt,
+			Version:      idx + 1,
+		}))
+	}
+	handler := storageapp.NewGetWriteOffReportQueryHandler(writeOffs, books)
+
+	report, err := handler.Handle(ctx, storageapp.NewGetWriteOffReport("school1", 2022))
+	assert.NoError(t, err)
+	assert.Len(t, report.WriteOffs, 3)
+	assert.Equal(t, fixtures.Euro(2000), report.WriteOffs[0].UnitPrice)
+	assert.Equal(t, fixtures.Euro(6000), report.WriteOffs[1].Value)
+	assert.Equal(t, domain.Money{}, report.WriteOffs[2].Value)
+	assert.Equal(t, fixtures.Euro(10000), report.Total)
+}
+
+func TestGetStorageLabels(t *testing.T) {
+	ctx := context.Background()
+	handler := storageapp.NewGetStorageLabelsQueryHandler(repositoryWithStorages)
+
+	labels, err := handler.Handle(ctx, storageapp.NewGetStorageLabels("school1", []string{"storage2School1"}))
+	assert.NoError(t, err)
+	assert.Equal(t, []labelapp.Label{labelapp.NewStorageLabel("storage2School1", "Closet 2", "Room 101")}, labels)
+
+	labels, err = handler.Handle(ctx, storageapp.NewGetStorageLabels("school1", []string{}))
+	assert.NoError(t, err)
+	assert.Len(t, labels, 2)
+
+	_, err = handler.Handle(ctx, storageapp.NewGetStorageLabels("school1", []string{"storage1School2"}))
+	assert.Error(t, err)
+}
+
+func TestGetLocationContents(t *testing.T) {
+	ctx := context.Background()
+	locations := memory.NewMemoryLocationRepository()
+	for _, location := range []storagedomain.LocationProjection{
+		storagedomain.NewLocationProjection("school1", "main", storagedomain.Building, "Main building", "", 1),
+		storagedomain.NewLocationProjection("school1", "first", storagedomain.Floor, "1st floor", "main", 2),
+		storagedomain.NewLocationProjection("school1", "101", storagedomain.Room, "Room 101", "first", 3),
+		storagedomain.NewLocationProjection("school1", "gym", storagedomain.Building, "Gym", "", 4),
+	} {
+		assert.NoError(t, locations.UpsertLocation(ctx, location))
+	}
+	storages := memory.NewMemoryRepositoryWithStorages([]storagedomain.StorageWithBooks{
+		{SchoolID: "school1", StorageID: "closet", LocationID: "101", Books: []storagedomain.BookInStorage{{BookID: "book1", Title: "Green Line 1", Quantity: 10}}},
+		{SchoolID: "school1", StorageID: "shelf", LocationID: "first", Books: []storagedomain.BookInStorage{{BookID: "book1", Title: "Green Line 1", Quantity: 5}}},
+		{SchoolID: "school1", StorageID: "cabinet", LocationID: "gym", Books: []storagedomain.BookInStorage{{BookID: "book2", Title: "Math", Quantity: 3}}},
+		{SchoolID: "school1", StorageID: "cellar", Location: "basement"},
+	})
+	handler := storageapp.NewGetLocationContentsQueryHandler(locations, storages)
+
+	contents, err := handler.Handle(ctx, storageapp.NewGetLocationContents("school1", "main"))
+	assert.NoError(t, err)
+	assert.Equal(t, "Main building", contents.Path)
+	assert.Len(t, contents.Locations, 2)
+	assert.Len(t, contents.Storages, 2)
+	assert.Equal(t, []storagedomain.BookInStorage{{BookID: "book1", Title: "Green Line 1", Quantity: 15}}, contents.Books)
+
+	contents, err = handler.Handle(ctx, storageapp.NewGetLocationContents("school1", "101"))
+	assert.NoError(t, err)
+	assert.Equal(t, "Main building / 1st floor / Room 101", contents.Path)
+	assert.Empty(t, contents.Locations)
+	assert.Len(t, contents.Storages, 1)
+
+	_, err = handler.Handle(ctx, storageapp.NewGetLocationContents("school1", "unknown"))
+	assert.Equal(t, storagedomain.ErrLocationIDNotFound("unknown"), err)
+}
@@ -2,6 +2,7 @@ package storageapp
 
 import (
 	"context"
+	"time"
 
 	"github.com/kammeph/school-book-storage-service/domain/storagedomain"
 )
@@ -10,8 +11,31 @@ type StorageWithBooksRepository interface {
 	GetAllStoragesBySchoolID(ctx context.Context, schoolID string) ([]storagedomain.StorageWithBooks, error)
 	GetStorageByID(ctx context.Context, schoolID, storageID string) (storagedomain.StorageWithBooks, error)
 	GetStorageByName(ctx context.Context, schoolID, name string) (storagedomain.StorageWithBooks, error)
-	InsertStorage(ctx context.Context, storage storagedomain.StorageWithBooks) error
-	DeleteStorage(ctx context.Context, storageID string) error
-	UpdateStorageName(ctx context.Context, storageID, name string) error
-	UpdateStorageLocation(ctx context.Context, storageID, location string) error
+	GetStoragesByLocationIDs(ctx context.Context, schoolID string, locationIDs []string) ([]storagedomain.StorageWithBooks, error)
+	UpsertStorage(ctx context.Context, storage storagedomain.StorageWithBooks) error
+	DeleteStorage(ctx context.Context, storageID string, version int) error
+	UpdateStorageName(ctx context.Context, storageID, name string, version int) error
+	UpdateStorageLocation(ctx context.Context, storageID, location, locationID string, version int) error
+	UpdateStorageCapacity(ctx context.Context, storageID string, capacity, version int) error
+	UpdateStorageBooks(ctx context.Context, storageID string, books []storagedomain.BookInStorage, version int) error
+}
+
+type StocktakingRepository interface {
+	GetStocktakingsBySchoolID(ctx context.Context, schoolID string) ([]storagedomain.StocktakingProjection, error)
+	GetStocktakingByID(ctx context.Context, schoolID, stocktakingID string) (storagedomain.StocktakingProjection, error)
+	UpsertStocktaking(ctx context.Context, stocktaking storagedomain.StocktakingProjection) error
+	UpdateStocktakingCounts(ctx context.Context, stocktakingID string, counts []storagedomain.StockCount, version int) error
+	UpdateStocktakingClosed(ctx context.Context, stocktakingID string, version int) error
+}
+
+type LocationRepository interface {
+	GetLocationsBySchoolID(ctx context.Context, schoolID string) ([]storagedomain.LocationProjection, error)
+	UpsertLocation(ctx context.Context, location storagedomain.LocationProjection) error
+	UpdateLocationName(ctx context.Context, locationID, name string, version int) error
+	DeleteLocation(ctx context.Context, locationID string, version int) error
+}
+
+type WriteOffRepository interface {
+	GetWriteOffs(ctx context.Context, schoolID string, from, to time.Time) ([]storagedomain.WriteOffProjection, error)
+	InsertWriteOff(ctx context.Context, writeOff storagedomain.WriteOffProjection) error
 }
@@ -0,0 +1,85 @@
+package storageapp
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/kammeph/school-book-storage-service/application"
+	"github.com/kammeph/school-book-storage-service/domain"
+	"github.com/kammeph/school-book-storage-service/domain/storagedomain"
+	"github.com/kammeph/school-book-storage-service/fp"
+)
+
+type StocktakingEventHandler struct {
+	repository StocktakingRepository
+}
+
+func NewStocktakingEventHandler(repository StocktakingRepository) application.EventHandler {
+	return &StocktakingEventHandler{repository}
+}
+
+func (h StocktakingEventHandler) Handle(ctx context.Context, eventBytes []byte) error {
+	event := &domain.EventModel{}
+	if err := json.Unmarshal(eventBytes, event); err != nil {
+		return err
+	}
+	switch event.EventType() {
+	case storagedomain.StocktakingOpened:
+		return h.handleStocktakingOpened(ctx, event)
+	case storagedomain.StockCounted:
+		return h.handleStockCounted(ctx, event)
+	case storagedomain.StocktakingClosed:
+		return h.handleStocktakingClosed(ctx, event)
+	default:
+		return nil
+	}
+}
+
+func (h StocktakingEventHandler) handleStocktakingOpened(ctx context.Context, event domain.Event) error {
+	stocktakingOpened := storagedomain.StocktakingOpenedEvent{}
+	if err := event.GetJsonData(&stocktakingOpened); err != nil {
+		return err
+	}
+	stocktaking := storagedomain.NewStocktakingProjection(
+		stocktakingOpened.SchoolID,
+		stocktakingOpened.StocktakingID,
+		stocktakingOpened.StorageIDs,
+		event.EventAt(),
+		event.EventVersion())
+	return h.repository.UpsertStocktaking(ctx, stocktaking)
+}
+
+// handleStockCounted replaces an earlier count of the book. Events the
+// stocktaking already reflects are skipped.
+func (h StocktakingEventHandler) handleStockCounted(ctx context.Context, event domain.Event) error {
+	stockCounted := storagedomain.StockCountedEvent{}
+	if err := event.GetJsonData(&stockCounted); err != nil {
+		return err
+	}
+	stocktaking, err := h.repository.GetStocktakingByID(ctx, event.AggregateID(), stockCounted.StocktakingID)
+	if err != nil {
+		return err
+	}
+	if stocktaking.Version >= event.EventVersion() {
+		return nil
+	}
+	count := storagedomain.StockCount{
+		StorageID: stockCounted.StorageID,
+		BookID:    stockCounted.BookID,
+		Isbn:      stockCounted.Isbn,
+		Title:     stockCounted.Title,
+		Quantity:  stockCounted.Quantity,
+	}
+	counts := fp.Remove(append([]storagedomain.StockCount{}, stocktaking.Counts...), func(c storagedomain.StockCount) bool {
+		return c.StorageID == count.StorageID && c.BookID == count.BookID
+	})
+	return h.repository.UpdateStocktakingCounts(ctx, stocktaking.StocktakingID, append(counts, count), event.EventVersion())
+}
+
+func (h StocktakingEventHandler) handleStocktakingClosed(ctx context.Context, event domain.Event) error {
+	stocktakingClosed := storagedomain.StocktakingClosedEvent{}
+	if err := event.GetJsonData(&stocktakingClosed); err != nil {
+		return err
+	}
+	return h.repository.UpdateStocktakingClosed(ctx, stocktakingClosed.StocktakingID, event.EventVersion())
+}
@@ -0,0 +1,44 @@
+package storageapp
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/kammeph/school-book-storage-service/application"
+	"github.com/kammeph/school-book-storage-service/domain"
+	"github.com/kammeph/school-book-storage-service/domain/storagedomain"
+)
+
+type WriteOffEventHandler struct {
+	repository WriteOffRepository
+}
+
+func NewWriteOffEventHandler(repository WriteOffRepository) application.EventHandler {
+	return &WriteOffEventHandler{repository}
+}
+
+func (h WriteOffEventHandler) Handle(ctx context.Context, eventBytes []byte) error {
+	event := &domain.EventModel{}
+	if err := json.Unmarshal(eventBytes, event); err != nil {
+		return err
+	}
+	if event.EventType() != storagedomain.BooksWrittenOff {
+		return nil
+	}
+	booksWrittenOff := storagedomain.BooksWrittenOffEvent{}
+	if err := event.GetJsonData(&booksWrittenOff); err != nil {
+		return err
+	}
+	return h.repository.InsertWriteOff(ctx, storagedomain.WriteOffProjection{
+		SchoolID:     booksWrittenOff.SchoolID,
+		StorageID:    booksWrittenOff.StorageID,
+		BookID:       booksWrittenOff.BookID,
+		Isbn:         booksWrittenOff.Isbn,
+		Title:        booksWrittenOff.Title,
+		Condition:    booksWrittenOff.Condition,
+		Quantity:     booksWrittenOff.Quantity,
+		Reason:       booksWrittenOff.Reason,
+		WrittenOffAt: event.EventAt(),
+		Version:      event.EventVersion(),
+	})
+}
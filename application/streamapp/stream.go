@@ -0,0 +1,150 @@
+package streamapp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kammeph/school-book-storage-service/application"
+	"github.com/kammeph/school-book-storage-service/domain"
+)
+
+type StreamEvent struct {
+	ID          string          `json:"id"`
+	SchoolID    string          `json:"schoolId"`
+	Type        string          `json:"type"`
+	AggregateID string          `json:"aggregateId"`
+	Version     int             `json:"version"`
+	At          time.Time       `json:"at"`
+	Data        json.RawMessage `json:"data"`
+	sequence    int64
+}
+
+type Client struct {
+	SchoolID string
+	Events   chan StreamEvent
+}
+
+// EventStream fans the events received from the event subscriber out to the
+// connected clients of a school. The latest events are kept in a buffer so a
+// client that reconnects with the ID of the last event it received gets the
+// events it missed. IDs are prefixed with the start time of the stream, as
+// the sequence starts over after a restart.
+type EventStream struct {
+	mutex      sync.Mutex
+	epoch      string
+	sequence   int64
+	buffer     []StreamEvent
+	bufferSize int
+	clientSize int
+	clients    map[*Client]struct{}
+}
+
+func NewEventStream(bufferSize, clientSize int) *EventStream {
+	return &EventStream{
+		epoch:      strconv.FormatInt(time.Now().UnixNano(), 36),
+		buffer:     []StreamEvent{},
+		bufferSize: bufferSize,
+		clientSize: clientSize,
+		clients:    map[*Client]struct{}{},
+	}
+}
+
+func (s *EventStream) Handle(ctx context.Context, eventBytes []byte) error {
+	event := &domain.EventModel{}
+	if err := json.Unmarshal(eventBytes, event); err != nil {
+		return err
+	}
+	data := json.RawMessage(event.EventData())
+	if !json.Valid(data) {
+		data = json.RawMessage("null")
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.sequence++
+	streamEvent := StreamEvent{
+		ID:          fmt.Sprintf("%s-%d", s.epoch, s.sequence),
+		SchoolID:    application.SchoolIDOf(event),
+		Type:        event.EventType(),
+		AggregateID: event.AggregateID(),
+		Version:     event.EventVersion(),
+		At:          event.EventAt(),
+		Data:        data,
+		sequence:    s.sequence,
+	}
+	s.buffer = append(s.buffer, streamEvent)
+	if len(s.buffer) > s.bufferSize {
+		s.buffer = s.buffer[len(s.buffer)-s.bufferSize:]
+	}
+	for client := range s.clients {
+		if client.SchoolID != streamEvent.SchoolID {
+			continue
+		}
+		select {
+		case client.Events <- streamEvent:
+		default:
+			// The client does not keep up. Closing its channel ends the
+			// response and the client resumes with its last event ID.
+			s.disconnect(client)
+		}
+	}
+	return nil
+}
+
+// Connect registers a client for the events of a school. If a last event ID
+// is given, the buffered events after it are returned. The returned flag is
+// false if the missed events are no longer available, in which case the
+// client has to reload its state.
+func (s *EventStream) Connect(schoolID, lastEventID string) (*Client, []StreamEvent, bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	client := &Client{SchoolID: schoolID, Events: make(chan StreamEvent, s.clientSize)}
+	s.clients[client] = struct{}{}
+	if lastEventID == "" {
+		return client, []StreamEvent{}, true
+	}
+	sequence, ok := s.parseID(lastEventID)
+	if !ok || sequence > s.sequence {
+		return client, []StreamEvent{}, false
+	}
+	if len(s.buffer) > 0 && sequence < s.buffer[0].sequence-1 {
+		return client, []StreamEvent{}, false
+	}
+	missed := []StreamEvent{}
+	for _, event := range s.buffer {
+		if event.sequence > sequence && event.SchoolID == schoolID {
+			missed = append(missed, event)
+		}
+	}
+	return client, missed, true
+}
+
+func (s *EventStream) Disconnect(client *Client) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.disconnect(client)
+}
+
+func (s *EventStream) disconnect(client *Client) {
+	if _, ok := s.clients[client]; !ok {
+		return
+	}
+	delete(s.clients, client)
+	close(client.Events)
+}
+
+func (s *EventStream) parseID(id string) (int64, bool) {
+	epoch, sequence, found := strings.Cut(id, "-")
+	if !found || epoch != s.epoch {
+		return 0, false
+	}
+	value, err := strconv.ParseInt(sequence, 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return value, true
+}
@@ -0,0 +1,113 @@
+package streamapp_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/kammeph/school-book-storage-service/application/streamapp"
+	"github.com/kammeph/school-book-storage-service/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func eventFor(schoolID string, version int) []byte {
+	eventBytes, _ := json.Marshal(domain.EventModel{
+		ID:      schoolID,
+		Type:    "STORAGE_ADDED",
+		Version: version,
+		At:      time.Now(),
+		Data:    `{"storageId":"storage"}`,
+	})
+	return eventBytes
+}
+
+func TestStreamEventsOfSchool(t *testing.T) {
+	ctx := context.Background()
+	stream := streamapp.NewEventStream(10, 10)
+	client, missed, resumed := stream.Connect("school", "")
+	assert.True(t, resumed)
+	assert.Len(t, missed, 0)
+
+	assert.NoError(t, stream.Handle(ctx, eventFor("other", 1)))
+	assert.NoError(t, stream.Handle(ctx, eventFor("school", 1)))
+	assert.Len(t, client.Events, 1)
+	event := <-client.Events
+	assert.Equal(t, "school", event.SchoolID)
+	assert.Equal(t, "STORAGE_ADDED", event.Type)
+	assert.JSONEq(t, `{"storageId":"storage"}`, string(event.Data))
+
+	stream.Disconnect(client)
+	_, ok := <-client.Events
+	assert.False(t, ok)
+}
+
+func TestResumeStream(t *testing.T) {
+	ctx := context.Background()
+	stream := streamapp.NewEventStream(3, 10)
+	client, _, _ := stream.Connect("school", "")
+	for version := 1; version <= 2; version++ {
+		assert.NoError(t, stream.Handle(ctx, eventFor("school", version)))
+	}
+	first := <-client.Events
+	stream.Disconnect(client)
+
+	tests := []struct {
+		name        string
+		lastEventID string
+		events      int
+		missed      []int
+		resumed     bool
+	}{
+		{
+			name:        "resume after last received event",
+			lastEventID: first.ID,
+			events:      0,
+			missed:      []int{2},
+			resumed:     true,
+		},
+		{
+			name:        "resume with ID of another stream",
+			lastEventID: "other-1",
+			events:      0,
+			missed:      []int{},
+			resumed:     false,
+		},
+		{
+			name:        "resume after missed events left the buffer",
+			lastEventID: first.ID,
+			events:      3,
+			missed:      []int{},
+			resumed:     false,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			for version := 3; version < 3+test.events; version++ {
+				assert.NoError(t, stream.Handle(ctx, eventFor("school", version)))
+			}
+			client, missed, resumed := stream.Connect("school", test.lastEventID)
+			defer stream.Disconnect(client)
+			assert.Equal(t, test.resumed, resumed)
+			versions := []int{}
+			for _, event := range missed {
+				versions = append(versions, event.Version)
+			}
+			assert.Equal(t, test.missed, versions)
+		})
+	}
+}
+
+func TestDisconnectSlowClient(t *testing.T) {
+	ctx := context.Background()
+	stream := streamapp.NewEventStream(10, 1)
+	client, _, _ := stream.Connect("school", "")
+	assert.NoError(t, stream.Handle(ctx, eventFor("school", 1)))
+	assert.NoError(t, stream.Handle(ctx, eventFor("school", 2)))
+	event, ok := <-client.Events
+	assert.True(t, ok)
+	assert.Equal(t, 1, event.Version)
+	_, ok = <-client.Events
+	assert.False(t, ok)
+	stream.Disconnect(client)
+}
@@ -0,0 +1,151 @@
+package webhookapp
+
+import (
+	"context"
+
+	"github.com/kammeph/school-book-storage-service/application"
+	"github.com/kammeph/school-book-storage-service/domain/webhookdomain"
+)
+
+type WebhookCommandHandlers struct {
+	RegisterWebhookHandler RegisterWebhookCommandHandler
+	RemoveWebhookHandler   RemoveWebhookCommandHandler
+	DisableWebhookHandler  DisableWebhookCommandHandler
+	EnableWebhookHandler   EnableWebhookCommandHandler
+}
+
+func NewWebhookCommandHandlers(
+	store application.Store,
+	publisher application.EventPublisher,
+	secrets WebhookSecretStore,
+) WebhookCommandHandlers {
+	return WebhookCommandHandlers{
+		RegisterWebhookHandler: NewRegisterWebhookCommandHandler(store, publisher, secrets),
+		RemoveWebhookHandler:   NewRemoveWebhookCommandHandler(store, publisher, secrets),
+		DisableWebhookHandler:  NewDisableWebhookCommandHandler(store, publisher),
+		EnableWebhookHandler:   NewEnableWebhookCommandHandler(store, publisher),
+	}
+}
+
+type RegisterWebhookCommand struct {
+	application.CommandModel
+	URL        string   `json:"url"`
+	EventTypes []string `json:"eventTypes"`
+}
+
+type RegisterWebhookCommandHandler struct {
+	*application.CommandHandlerModel
+	secrets WebhookSecretStore
+}
+
+func NewRegisterWebhookCommandHandler(
+	store application.Store,
+	publisher application.EventPublisher,
+	secrets WebhookSecretStore,
+) RegisterWebhookCommandHandler {
+	return RegisterWebhookCommandHandler{application.NewCommandHandlerModel(store, publisher), secrets}
+}
+
+// Handle registers the webhook and returns its ID together with the secret the
+// receiver uses to verify the signature of the deliveries. The secret is saved
+// to the secret store before the webhook, so no delivery goes out unsigned.
+func (h RegisterWebhookCommandHandler) Handle(ctx context.Context, command RegisterWebhookCommand) (string, string, error) {
+	aggregate := webhookdomain.NewSchoolWebhookAggregateWithID(command.AggregateID())
+	if err := h.LoadAggregate(ctx, aggregate); err != nil {
+		return "", "", err
+	}
+	webhookID, secret, err := aggregate.RegisterWebhook(command.URL, command.EventTypes)
+	if err != nil {
+		return "", "", err
+	}
+	if err := h.secrets.SaveSecret(ctx, webhookID, secret); err != nil {
+		return "", "", err
+	}
+	if err := h.SaveAndPublish(ctx, aggregate); err != nil {
+		return "", "", err
+	}
+	return webhookID, secret, nil
+}
+
+type RemoveWebhookCommand struct {
+	application.CommandModel
+	WebhookID string `json:"webhookId"`
+	Reason    string `json:"reason"`
+}
+
+type RemoveWebhookCommandHandler struct {
+	*application.CommandHandlerModel
+	secrets WebhookSecretStore
+}
+
+func NewRemoveWebhookCommandHandler(
+	store application.Store,
+	publisher application.EventPublisher,
+	secrets WebhookSecretStore,
+) RemoveWebhookCommandHandler {
+	return RemoveWebhookCommandHandler{application.NewCommandHandlerModel(store, publisher), secrets}
+}
+
+// Handle removes the webhook and afterwards its secret.
+func (h RemoveWebhookCommandHandler) Handle(ctx context.Context, command RemoveWebhookCommand) error {
+	aggregate := webhookdomain.NewSchoolWebhookAggregateWithID(command.AggregateID())
+	if err := h.LoadAggregate(ctx, aggregate); err != nil {
+		return err
+	}
+	if err := aggregate.RemoveWebhook(command.WebhookID, command.Reason); err != nil {
+		return err
+	}
+	if err := h.SaveAndPublish(ctx, aggregate); err != nil {
+		return err
+	}
+	return h.secrets.DeleteSecret(ctx, command.WebhookID)
+}
+
+type DisableWebhookCommand struct {
+	application.CommandModel
+	WebhookID string `json:"webhookId"`
+	Reason    string `json:"reason"`
+}
+
+type DisableWebhookCommandHandler struct {
+	*application.CommandHandlerModel
+}
+
+func NewDisableWebhookCommandHandler(store application.Store, publisher application.EventPublisher) DisableWebhookCommandHandler {
+	return DisableWebhookCommandHandler{application.NewCommandHandlerModel(store, publisher)}
+}
+
+func (h DisableWebhookCommandHandler) Handle(ctx context.Context, command DisableWebhookCommand) error {
+	aggregate := webhookdomain.NewSchoolWebhookAggregateWithID(command.AggregateID())
+	if err := h.LoadAggregate(ctx, aggregate); err != nil {
+		return err
+	}
+	if err := aggregate.DisableWebhook(command.WebhookID, command.Reason); err != nil {
+		return err
+	}
+	return h.SaveAndPublish(ctx, aggregate)
+}
+
+type EnableWebhookCommand struct {
+	application.CommandModel
+	WebhookID string `json:"webhookId"`
+}
+
+type EnableWebhookCommandHandler struct {
+	*application.CommandHandlerModel
+}
+
+func NewEnableWebhookCommandHandler(store application.Store, publisher application.EventPublisher) EnableWebhookCommandHandler {
+	return EnableWebhookCommandHandler{application.NewCommandHandlerModel(store, publisher)}
+}
+
+func (h EnableWebhookCommandHandler) Handle(ctx context.Context, command EnableWebhookCommand) error {
+	aggregate := webhookdomain.NewSchoolWebhookAggregateWithID(command.AggregateID())
+	if err := h.LoadAggregate(ctx, aggregate); err != nil {
+		return err
+	}
+	if err := aggregate.EnableWebhook(command.WebhookID); err != nil {
+		return err
+	}
+	return h.SaveAndPublish(ctx, aggregate)
+}
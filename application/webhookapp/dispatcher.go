@@ -0,0 +1,286 @@
+package webhookapp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/kammeph/school-book-storage-service/application"
+	"github.com/kammeph/school-book-storage-service/domain"
+	"github.com/kammeph/school-book-storage-service/domain/webhookdomain"
+	"github.com/kammeph/school-book-storage-service/fp"
+)
+
+// WebhookSender posts the signed payload to the URL of a webhook and returns
+// the HTTP status code of the response.
+type WebhookSender interface {
+	Send(ctx context.Context, url, secret string, payload []byte) (int, error)
+}
+
+type WebhookMessage struct {
+	EventType   string          `json:"eventType"`
+	SchoolID    string          `json:"schoolId"`
+	AggregateID string          `json:"aggregateId"`
+	Version     int             `json:"version"`
+	At          time.Time       `json:"at"`
+	Data        json.RawMessage `json:"data"`
+}
+
+type DispatcherOptions struct {
+	MaxAttempts int
+	Backoff     time.Duration
+	MaxFailures int
+	Workers     int
+	QueueSize   int
+}
+
+func DefaultDispatcherOptions() DispatcherOptions {
+	return DispatcherOptions{MaxAttempts: 5, Backoff: time.Second, MaxFailures: 10, Workers: 4, QueueSize: 1000}
+}
+
+type delivery struct {
+	schoolID  string
+	webhookID string
+	message   WebhookMessage
+	payload   []byte
+	attempt   int
+}
+
+// WebhookDispatcher delivers the events of a school to the webhooks registered
+// for the event type. Deliveries are queued and sent by workers, so a slow or
+// unreachable webhook does not hold up the events. Every attempt is written to
+// the delivery log. A failed attempt is retried with an exponential backoff and
+// a webhook is disabled after MaxFailures deliveries in a row did not succeed.
+// Deliveries that find the queue full or the dispatcher stopped are dropped and
+// written to the delivery log as well.
+type WebhookDispatcher struct {
+	webhooks   WebhookRepository
+	deliveries DeliveryRepository
+	secrets    WebhookSecretStore
+	sender     WebhookSender
+	disable    DisableWebhookCommandHandler
+	options    DispatcherOptions
+	queue      chan delivery
+	pending    sync.WaitGroup
+	mutex      sync.Mutex
+	stopped    bool
+	stop       chan struct{}
+}
+
+func NewWebhookDispatcher(
+	webhooks WebhookRepository,
+	deliveries DeliveryRepository,
+	secrets WebhookSecretStore,
+	sender WebhookSender,
+	disable DisableWebhookCommandHandler,
+	options DispatcherOptions,
+) *WebhookDispatcher {
+	dispatcher := &WebhookDispatcher{
+		webhooks:   webhooks,
+		deliveries: deliveries,
+		secrets:    secrets,
+		sender:     sender,
+		disable:    disable,
+		options:    options,
+		queue:      make(chan delivery, options.QueueSize),
+		stop:       make(chan struct{}),
+	}
+	for worker := 0; worker < options.Workers; worker++ {
+		go dispatcher.work()
+	}
+	return dispatcher
+}
+
+// Handle queues the event for the webhooks that subscribe to it and returns
+// without waiting for the deliveries.
+func (d *WebhookDispatcher) Handle(ctx context.Context, eventBytes []byte) error {
+	event := &domain.EventModel{}
+	if err := json.Unmarshal(eventBytes, event); err != nil {
+		return err
+	}
+	schoolID := application.SchoolIDOf(event)
+	webhooks, err := d.webhooks.GetWebhooksBySchoolID(ctx, schoolID)
+	if err != nil {
+		return err
+	}
+	message := WebhookMessage{
+		EventType:   event.EventType(),
+		SchoolID:    schoolID,
+		AggregateID: event.AggregateID(),
+		Version:     event.EventVersion(),
+		At:          event.EventAt(),
+		Data:        json.RawMessage(event.EventData()),
+	}
+	if !json.Valid(message.Data) {
+		message.Data = json.RawMessage("null")
+	}
+	payload, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+	for _, webhook := range webhooks {
+		if !webhook.Active || !webhook.Subscribes(event.EventType()) {
+			continue
+		}
+		d.enqueue(delivery{schoolID, webhook.WebhookID, message, payload, 1})
+	}
+	return nil
+}
+
+// Wait blocks until all queued deliveries and their retries are done.
+func (d *WebhookDispatcher) Wait() {
+	d.pending.Wait()
+}
+
+// Stop stops the workers. Queued deliveries and retries that are still due are
+// dropped.
+func (d *WebhookDispatcher) Stop() {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	if d.stopped {
+		return
+	}
+	d.stopped = true
+	close(d.stop)
+}
+
+// enqueue queues the delivery without blocking.
+func (d *WebhookDispatcher) enqueue(next delivery) {
+	d.pending.Add(1)
+	d.mutex.Lock()
+	reason := ""
+	if d.stopped {
+		reason = "the dispatcher is stopped"
+	} else {
+		select {
+		case d.queue <- next:
+		default:
+			reason = "the delivery queue is full"
+		}
+	}
+	d.mutex.Unlock()
+	if reason != "" {
+		d.drop(next, reason)
+	}
+}
+
+// drop writes the delivery to the delivery log as failed without sending it.
+func (d *WebhookDispatcher) drop(next delivery, reason string) {
+	defer d.pending.Done()
+	log.Printf("dropping %s for webhook %s: %s", next.message.EventType, next.webhookID, reason)
+	dropped := webhookdomain.Delivery{
+		DeliveryID:  uuid.NewString(),
+		SchoolID:    next.schoolID,
+		WebhookID:   next.webhookID,
+		EventType:   next.message.EventType,
+		AggregateID: next.message.AggregateID,
+		Version:     next.message.Version,
+		Attempt:     next.attempt,
+		Error:       "dropped: " + reason,
+		DeliveredAt: time.Now(),
+	}
+	if err := d.deliveries.InsertDelivery(context.Background(), dropped); err != nil {
+		log.Printf("error while logging the dropped delivery to webhook %s: %s", next.webhookID, err)
+	}
+}
+
+func (d *WebhookDispatcher) work() {
+	for {
+		select {
+		case next := <-d.queue:
+			if err := d.deliver(context.Background(), next); err != nil {
+				log.Printf("error while delivering %s to webhook %s: %s", next.message.EventType, next.webhookID, err)
+			}
+			d.pending.Done()
+		case <-d.stop:
+			for {
+				select {
+				case next := <-d.queue:
+					d.drop(next, "the dispatcher is stopped")
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+// deliver makes one attempt to deliver the message and schedules the next
+// attempt if it fails. The webhook is read again for every attempt, so a
+// webhook that was disabled or removed in the meantime gets no more retries.
+func (d *WebhookDispatcher) deliver(ctx context.Context, next delivery) error {
+	webhooks, err := d.webhooks.GetWebhooksBySchoolID(ctx, next.schoolID)
+	if err != nil {
+		return err
+	}
+	webhook := fp.Find(webhooks, func(w webhookdomain.WebhookProjection) bool { return w.WebhookID == next.webhookID })
+	if webhook == nil || !webhook.Active {
+		return nil
+	}
+	secret, err := d.secrets.GetSecret(ctx, webhook.WebhookID)
+	if err != nil {
+		return err
+	}
+	attempt := webhookdomain.Delivery{
+		DeliveryID:  uuid.NewString(),
+		SchoolID:    webhook.SchoolID,
+		WebhookID:   webhook.WebhookID,
+		EventType:   next.message.EventType,
+		AggregateID: next.message.AggregateID,
+		Version:     next.message.Version,
+		Attempt:     next.attempt,
+	}
+	statusCode, err := d.sender.Send(ctx, webhook.URL, secret, next.payload)
+	attempt.StatusCode = statusCode
+	attempt.DeliveredAt = time.Now()
+	attempt.Success = err == nil && statusCode >= 200 && statusCode < 300
+	if err != nil {
+		attempt.Error = err.Error()
+	} else if !attempt.Success {
+		attempt.Error = fmt.Sprintf("unexpected status code %d", statusCode)
+	}
+	if err := d.deliveries.InsertDelivery(ctx, attempt); err != nil {
+		return err
+	}
+	if attempt.Success {
+		if webhook.Failures == 0 {
+			return nil
+		}
+		return d.webhooks.UpdateWebhookFailures(ctx, webhook.WebhookID, 0)
+	}
+	if next.attempt == d.options.MaxAttempts {
+		return d.failed(ctx, *webhook)
+	}
+	delay := d.options.Backoff << (next.attempt - 1)
+	next.attempt++
+	d.pending.Add(1)
+	time.AfterFunc(delay, func() {
+		defer d.pending.Done()
+		d.enqueue(next)
+	})
+	return nil
+}
+
+func (d *WebhookDispatcher) failed(ctx context.Context, webhook webhookdomain.WebhookProjection) error {
+	failures := webhook.Failures + 1
+	if err := d.webhooks.UpdateWebhookFailures(ctx, webhook.WebhookID, failures); err != nil {
+		return err
+	}
+	if failures < d.options.MaxFailures {
+		return nil
+	}
+	log.Printf("disabling webhook %s after %d failed deliveries", webhook.WebhookID, failures)
+	command := DisableWebhookCommand{
+		CommandModel: application.CommandModel{ID: webhook.SchoolID},
+		WebhookID:    webhook.WebhookID,
+		Reason:       fmt.Sprintf("%d deliveries in a row failed", failures),
+	}
+	if err := d.disable.Handle(ctx, command); err != nil && err != webhookdomain.ErrWebhookNotActive {
+		return err
+	}
+	return nil
+}
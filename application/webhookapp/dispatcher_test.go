@@ -0,0 +1,298 @@
+package webhookapp_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/kammeph/school-book-storage-service/application"
+	"github.com/kammeph/school-book-storage-service/application/webhookapp"
+	"github.com/kammeph/school-book-storage-service/domain"
+	"github.com/kammeph/school-book-storage-service/domain/storagedomain"
+	"github.com/kammeph/school-book-storage-service/domain/webhookdomain"
+	"github.com/kammeph/school-book-storage-service/infrastructure/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockSender struct {
+	responses []int
+	payloads  [][]byte
+	secrets   []string
+}
+
+func (s *mockSender) Send(ctx context.Context, url, secret string, payload []byte) (int, error) {
+	s.payloads = append(s.payloads, payload)
+	s.secrets = append(s.secrets, secret)
+	if len(s.responses) == 0 {
+		return 0, errors.New("connection refused")
+	}
+	statusCode := s.responses[0]
+	s.responses = s.responses[1:]
+	return statusCode, nil
+}
+
+type blockingSender struct {
+	release chan struct{}
+}
+
+func (s *blockingSender) Send(ctx context.Context, url, secret string, payload []byte) (int, error) {
+	<-s.release
+	return http.StatusOK, nil
+}
+
+func storageAddedEvent(version int) []byte {
+	eventData, _ := json.Marshal(storagedomain.StorageAddedEvent{
+		SchoolID:  "school",
+		StorageID: "storage",
+		Name:      "Closet",
+		Location:  "Room 12",
+	})
+	eventBytes, _ := json.Marshal(domain.EventModel{
+		ID:      "school",
+		Type:    storagedomain.StorageAdded,
+		Version: version,
+		At:      time.Now(),
+		Data:    string(eventData),
+	})
+	return eventBytes
+}
+
+func TestDispatch(t *testing.T) {
+	tests := []struct {
+		name        string
+		eventTypes  []string
+		responses   []int
+		events      int
+		maxFailures int
+		sent        int
+		successful  int
+		active      bool
+	}{
+		{
+			name:        "deliver subscribed event",
+			eventTypes:  []string{storagedomain.StorageAdded},
+			responses:   []int{http.StatusOK},
+			events:      1,
+			maxFailures: 2,
+			sent:        1,
+			successful:  1,
+			active:      true,
+		},
+		{
+			name:        "skip not subscribed event",
+			eventTypes:  []string{storagedomain.StorageRemoved},
+			responses:   []int{http.StatusOK},
+			events:      1,
+			maxFailures: 2,
+			sent:        0,
+			successful:  0,
+			active:      true,
+		},
+		{
+			name:        "retry failed delivery",
+			eventTypes:  []string{storagedomain.StorageAdded},
+			responses:   []int{http.StatusInternalServerError, http.StatusBadGateway, http.StatusNoContent},
+			events:      1,
+			maxFailures: 2,
+			sent:        3,
+			successful:  1,
+			active:      true,
+		},
+		{
+			name:        "disable webhook after repeated failures",
+			eventTypes:  []string{"*"},
+			responses:   []int{},
+			events:      3,
+			maxFailures: 2,
+			sent:        6,
+			successful:  0,
+			active:      false,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := context.Background()
+			repository := memory.NewMemoryWebhookRepository()
+			secrets := memory.NewMemoryWebhookSecretStore()
+			broker := memory.NewMemoryMessageBroker()
+			broker.Subscribe("webhook", webhookapp.NewWebhookEventHandler(repository, secrets), application.NewSkipPolicy())
+			commandHandlers := webhookapp.NewWebhookCommandHandlers(memory.NewMemoryStore(), broker, secrets)
+			command := webhookapp.RegisterWebhookCommand{
+				CommandModel: application.CommandModel{ID: "school"},
+				URL:          "https://example.com/hook",
+				EventTypes:   test.eventTypes,
+			}
+			webhookID, secret, err := commandHandlers.RegisterWebhookHandler.Handle(ctx, command)
+			assert.NoError(t, err)
+
+			sender := &mockSender{responses: test.responses}
+			options := webhookapp.DispatcherOptions{
+				MaxAttempts: 3,
+				Backoff:     time.Millisecond,
+				MaxFailures: test.maxFailures,
+				Workers:     1,
+				QueueSize:   10,
+			}
+			dispatcher := webhookapp.NewWebhookDispatcher(repository, repository, secrets, sender, commandHandlers.DisableWebhookHandler, options)
+			for version := 1; version <= test.events; version++ {
+				assert.NoError(t, dispatcher.Handle(ctx, storageAddedEvent(version)))
+				dispatcher.Wait()
+			}
+
+			assert.Len(t, sender.payloads, test.sent)
+			deliveries, err := repository.GetDeliveriesByWebhookID(ctx, "school", webhookID)
+			assert.NoError(t, err)
+			assert.Len(t, deliveries, test.sent)
+			successful := 0
+			for _, delivery := range deliveries {
+				if delivery.Success {
+					successful++
+				}
+			}
+			assert.Equal(t, test.successful, successful)
+			webhooks, err := repository.GetWebhooksBySchoolID(ctx, "school")
+			assert.NoError(t, err)
+			assert.Equal(t, test.active, webhooks[0].Active)
+			if test.sent > 0 {
+				message := webhookapp.WebhookMessage{}
+				assert.NoError(t, json.Unmarshal(sender.payloads[0], &message))
+				assert.Equal(t, storagedomain.StorageAdded, message.EventType)
+				assert.Equal(t, "school", message.SchoolID)
+				assert.Equal(t, secret, sender.secrets[0])
+			}
+		})
+	}
+}
+
+func TestDispatchDoesNotWaitForDelivery(t *testing.T) {
+	ctx := context.Background()
+	repository := memory.NewMemoryWebhookRepository()
+	secrets := memory.NewMemoryWebhookSecretStore()
+	commandHandlers, webhookID := registerWebhook(t, repository, secrets)
+	sender := &blockingSender{release: make(chan struct{})}
+	dispatcher := webhookapp.NewWebhookDispatcher(
+		repository,
+		repository,
+		secrets,
+		sender,
+		commandHandlers.DisableWebhookHandler,
+		webhookapp.DefaultDispatcherOptions())
+	handled := make(chan error)
+	go func() { handled <- dispatcher.Handle(ctx, storageAddedEvent(1)) }()
+	select {
+	case err := <-handled:
+		assert.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("handler waited for the delivery")
+	}
+
+	close(sender.release)
+	dispatcher.Wait()
+	deliveries, err := repository.GetDeliveriesByWebhookID(ctx, "school", webhookID)
+	assert.NoError(t, err)
+	assert.Len(t, deliveries, 1)
+	assert.True(t, deliveries[0].Success)
+}
+
+func registerWebhook(
+	t *testing.T,
+	repository *memory.MemoryWebhookRepository,
+	secrets webhookapp.WebhookSecretStore,
+) (webhookapp.WebhookCommandHandlers, string) {
+	broker := memory.NewMemoryMessageBroker()
+	broker.Subscribe("webhook", webhookapp.NewWebhookEventHandler(repository, secrets), application.NewSkipPolicy())
+	commandHandlers := webhookapp.NewWebhookCommandHandlers(memory.NewMemoryStore(), broker, secrets)
+	command := webhookapp.RegisterWebhookCommand{
+		CommandModel: application.CommandModel{ID: "school"},
+		URL:          "https://example.com/hook",
+		EventTypes:   []string{"*"},
+	}
+	webhookID, _, err := commandHandlers.RegisterWebhookHandler.Handle(context.Background(), command)
+	assert.NoError(t, err)
+	return commandHandlers, webhookID
+}
+
+func TestDispatchDropsDeliveriesWhenQueueIsFull(t *testing.T) {
+	ctx := context.Background()
+	repository := memory.NewMemoryWebhookRepository()
+	secrets := memory.NewMemoryWebhookSecretStore()
+	commandHandlers, webhookID := registerWebhook(t, repository, secrets)
+	options := webhookapp.DispatcherOptions{MaxAttempts: 1, MaxFailures: 10, Workers: 0, QueueSize: 1}
+	dispatcher := webhookapp.NewWebhookDispatcher(repository, repository, secrets, &mockSender{}, commandHandlers.DisableWebhookHandler, options)
+	assert.NoError(t, dispatcher.Handle(ctx, storageAddedEvent(1)))
+	assert.NoError(t, dispatcher.Handle(ctx, storageAddedEvent(2)))
+	deliveries, err := repository.GetDeliveriesByWebhookID(ctx, "school", webhookID)
+	assert.NoError(t, err)
+	assert.Len(t, deliveries, 1)
+	assert.Equal(t, 2, deliveries[0].Version)
+	assert.False(t, deliveries[0].Success)
+	assert.Equal(t, "dropped: the delivery queue is full", deliveries[0].Error)
+}
+
+func TestDispatchDropsRetriesAfterStop(t *testing.T) {
+	ctx := context.Background()
+	repository := memory.NewMemoryWebhookRepository()
+	secrets := memory.NewMemoryWebhookSecretStore()
+	commandHandlers, webhookID := registerWebhook(t, repository, secrets)
+	options := webhookapp.DispatcherOptions{MaxAttempts: 3, Backoff: 10 * time.Millisecond, MaxFailures: 10, Workers: 1, QueueSize: 10}
+	dispatcher := webhookapp.NewWebhookDispatcher(repository, repository, secrets, &mockSender{}, commandHandlers.DisableWebhookHandler, options)
+	assert.NoError(t, dispatcher.Handle(ctx, storageAddedEvent(1)))
+	dispatcher.Stop()
+	dispatcher.Wait()
+	deliveries, err := repository.GetDeliveriesByWebhookID(ctx, "school", webhookID)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, deliveries)
+	assert.Equal(t, "dropped: the dispatcher is stopped", deliveries[len(deliveries)-1].Error)
+
+	assert.NoError(t, dispatcher.Handle(ctx, storageAddedEvent(2)))
+	deliveries, err = repository.GetDeliveriesByWebhookID(ctx, "school", webhookID)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, deliveries[len(deliveries)-1].Version)
+	assert.Equal(t, "dropped: the dispatcher is stopped", deliveries[len(deliveries)-1].Error)
+}
+
+func TestRemoveWebhookDeletesSecret(t *testing.T) {
+	ctx := context.Background()
+	repository := memory.NewMemoryWebhookRepository()
+	secrets := memory.NewMemoryWebhookSecretStore()
+	commandHandlers, webhookID := registerWebhook(t, repository, secrets)
+	_, err := secrets.GetSecret(ctx, webhookID)
+	assert.NoError(t, err)
+
+	command := webhookapp.RemoveWebhookCommand{
+		CommandModel: application.CommandModel{ID: "school"},
+		WebhookID:    webhookID,
+		Reason:       "not needed anymore",
+	}
+	assert.NoError(t, commandHandlers.RemoveWebhookHandler.Handle(ctx, command))
+	_, err = secrets.GetSecret(ctx, webhookID)
+	assert.Error(t, err)
+}
+
+func TestLegacySecretMovesToSecretStore(t *testing.T) {
+	ctx := context.Background()
+	repository := memory.NewMemoryWebhookRepository()
+	secrets := memory.NewMemoryWebhookSecretStore()
+	eventData, _ := json.Marshal(webhookdomain.WebhookRegisteredEvent{
+		SchoolID:   "school",
+		WebhookID:  "webhook",
+		URL:        "https://example.com/hook",
+		EventTypes: []string{"*"},
+		Secret:     "legacy",
+	})
+	eventBytes, _ := json.Marshal(domain.EventModel{
+		ID:      "school",
+		Type:    webhookdomain.WebhookRegistered,
+		Version: 1,
+		At:      time.Now(),
+		Data:    string(eventData),
+	})
+	handler := webhookapp.NewWebhookEventHandler(repository, secrets)
+	assert.NoError(t, handler.Handle(ctx, eventBytes))
+	secret, err := secrets.GetSecret(ctx, "webhook")
+	assert.NoError(t, err)
+	assert.Equal(t, "legacy", secret)
+}
@@ -0,0 +1,83 @@
+package webhookapp
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/kammeph/school-book-storage-service/application"
+	"github.com/kammeph/school-book-storage-service/domain"
+	"github.com/kammeph/school-book-storage-service/domain/webhookdomain"
+)
+
+type WebhookEventHandler struct {
+	repository WebhookRepository
+	secrets    WebhookSecretStore
+}
+
+func NewWebhookEventHandler(repository WebhookRepository, secrets WebhookSecretStore) application.EventHandler {
+	return &WebhookEventHandler{repository, secrets}
+}
+
+func (h WebhookEventHandler) Handle(ctx context.Context, eventBytes []byte) error {
+	event := &domain.EventModel{}
+	if err := json.Unmarshal(eventBytes, event); err != nil {
+		return err
+	}
+	switch event.EventType() {
+	case webhookdomain.WebhookRegistered:
+		return h.handleWebhookRegistered(ctx, event)
+	case webhookdomain.WebhookRemoved:
+		return h.handleWebhookRemoved(ctx, event)
+	case webhookdomain.WebhookDisabled:
+		return h.handleWebhookDisabled(ctx, event)
+	case webhookdomain.WebhookEnabled:
+		return h.handleWebhookEnabled(ctx, event)
+	default:
+		return nil
+	}
+}
+
+func (h WebhookEventHandler) handleWebhookRegistered(ctx context.Context, event domain.Event) error {
+	webhookRegistered := webhookdomain.WebhookRegisteredEvent{}
+	if err := event.GetJsonData(&webhookRegistered); err != nil {
+		return err
+	}
+	// Webhooks registered before the secret store carry their secret in the
+	// event, it is moved to the secret store.
+	if webhookRegistered.Secret != "" {
+		if err := h.secrets.SaveSecret(ctx, webhookRegistered.WebhookID, webhookRegistered.Secret); err != nil {
+			return err
+		}
+	}
+	webhook := webhookdomain.NewWebhookProjection(
+		webhookRegistered.SchoolID,
+		webhookRegistered.WebhookID,
+		webhookRegistered.URL,
+		webhookRegistered.EventTypes,
+		event.EventVersion())
+	return h.repository.UpsertWebhook(ctx, webhook)
+}
+
+func (h WebhookEventHandler) handleWebhookRemoved(ctx context.Context, event domain.Event) error {
+	webhookRemoved := webhookdomain.WebhookRemovedEvent{}
+	if err := event.GetJsonData(&webhookRemoved); err != nil {
+		return err
+	}
+	return h.repository.DeleteWebhook(ctx, webhookRemoved.WebhookID, event.EventVersion())
+}
+
+func (h WebhookEventHandler) handleWebhookDisabled(ctx context.Context, event domain.Event) error {
+	webhookDisabled := webhookdomain.WebhookDisabledEvent{}
+	if err := event.GetJsonData(&webhookDisabled); err != nil {
+		return err
+	}
+	return h.repository.UpdateWebhookActive(ctx, webhookDisabled.WebhookID, false, event.EventVersion())
+}
+
+func (h WebhookEventHandler) handleWebhookEnabled(ctx context.Context, event domain.Event) error {
+	webhookEnabled := webhookdomain.WebhookEnabledEvent{}
+	if err := event.GetJsonData(&webhookEnabled); err != nil {
+		return err
+	}
+	return h.repository.UpdateWebhookActive(ctx, webhookEnabled.WebhookID, true, event.EventVersion())
+}
@@ -0,0 +1,61 @@
+package webhookapp
+
+import (
+	"context"
+
+	"github.com/kammeph/school-book-storage-service/application"
+	"github.com/kammeph/school-book-storage-service/domain/webhookdomain"
+)
+
+type WebhookQueryHandlers struct {
+	GetWebhooksHandler   GetWebhooksQueryHandler
+	GetDeliveriesHandler GetDeliveriesQueryHandler
+}
+
+func NewWebhookQueryHandlers(webhooks WebhookRepository, deliveries DeliveryRepository) WebhookQueryHandlers {
+	return WebhookQueryHandlers{
+		GetWebhooksHandler:   NewGetWebhooksQueryHandler(webhooks),
+		GetDeliveriesHandler: NewGetDeliveriesQueryHandler(deliveries),
+	}
+}
+
+type GetWebhooks struct {
+	application.QueryModel
+}
+
+func NewGetWebhooks(aggregateID string) GetWebhooks {
+	return GetWebhooks{QueryModel: application.QueryModel{ID: aggregateID}}
+}
+
+type GetWebhooksQueryHandler struct {
+	repository WebhookRepository
+}
+
+func NewGetWebhooksQueryHandler(repository WebhookRepository) GetWebhooksQueryHandler {
+	return GetWebhooksQueryHandler{repository: repository}
+}
+
+func (h GetWebhooksQueryHandler) Handle(ctx context.Context, query GetWebhooks) ([]webhookdomain.WebhookProjection, error) {
+	return h.repository.GetWebhooksBySchoolID(ctx, query.AggregateID())
+}
+
+type GetDeliveries struct {
+	application.QueryModel
+	WebhookID string
+}
+
+func NewGetDeliveries(aggregateID, webhookID string) GetDeliveries {
+	return GetDeliveries{QueryModel: application.QueryModel{ID: aggregateID}, WebhookID: webhookID}
+}
+
+type GetDeliveriesQueryHandler struct {
+	repository DeliveryRepository
+}
+
+func NewGetDeliveriesQueryHandler(repository DeliveryRepository) GetDeliveriesQueryHandler {
+	return GetDeliveriesQueryHandler{repository: repository}
+}
+
+func (h GetDeliveriesQueryHandler) Handle(ctx context.Context, query GetDeliveries) ([]webhookdomain.Delivery, error) {
+	return h.repository.GetDeliveriesByWebhookID(ctx, query.AggregateID(), query.WebhookID)
+}
@@ -0,0 +1,29 @@
+package webhookapp
+
+import (
+	"context"
+
+	"github.com/kammeph/school-book-storage-service/domain/webhookdomain"
+)
+
+type WebhookRepository interface {
+	GetWebhooksBySchoolID(ctx context.Context, schoolID string) ([]webhookdomain.WebhookProjection, error)
+	UpsertWebhook(ctx context.Context, webhook webhookdomain.WebhookProjection) error
+	DeleteWebhook(ctx context.Context, webhookID string, version int) error
+	UpdateWebhookActive(ctx context.Context, webhookID string, active bool, version int) error
+	UpdateWebhookFailures(ctx context.Context, webhookID string, failures int) error
+}
+
+type DeliveryRepository interface {
+	GetDeliveriesByWebhookID(ctx context.Context, schoolID, webhookID string) ([]webhookdomain.Delivery, error)
+	InsertDelivery(ctx context.Context, delivery webhookdomain.Delivery) error
+}
+
+// WebhookSecretStore keeps the secrets the deliveries are signed with apart
+// from the events, so they are neither recorded in the event store nor
+// published.
+type WebhookSecretStore interface {
+	SaveSecret(ctx context.Context, webhookID, secret string) error
+	GetSecret(ctx context.Context, webhookID string) (string, error)
+	DeleteSecret(ctx context.Context, webhookID string) error
+}
@@ -17,6 +17,12 @@ func NewSchoolBookAggregate() *SchoolBookAggregate {
 	return aggregate
 }
 
+func NewSchoolBookAggregateWithID(id string) *SchoolBookAggregate {
+	aggregate := NewSchoolBookAggregate()
+	aggregate.ID = id
+	return aggregate
+}
+
 func (a *SchoolBookAggregate) On(event domain.Event) error {
 	switch event.EventType() {
 	case BookAdded:
@@ -7,6 +7,7 @@ import (
 	"github.com/kammeph/school-book-storage-service/domain"
 	"github.com/kammeph/school-book-storage-service/domain/bookdomain"
 	"github.com/kammeph/school-book-storage-service/fp"
+	"github.com/kammeph/school-book-storage-service/testing/fixtures"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -22,10 +23,10 @@ func TestSchoolBookAggregateOn(t *testing.T) {
 		eventAt         time.Time
 		schoolID        string
 		bookID          string
-		isbn            string
+		isbn            domain.Isbn
 		bookName        string
 		bookDescription string
-		price           float64
+		price           domain.Money
 		grades          []int
 		reason          string
 		err             error
@@ -42,7 +43,7 @@ func TestSchoolBookAggregateOn(t *testing.T) {
 			bookID:          "book",
 			bookName:        "test book",
 			bookDescription: "test description",
-			price:           30.00,
+			price:           fixtures.Euro(3000),
 			grades:          []int{3, 4},
 			err:             nil,
 			expectError:     false,
@@ -89,7 +90,7 @@ func TestSchoolBookAggregateOn(t *testing.T) {
 			eventVersion:    21,
 			eventAt:         time.Now(),
 			bookID:          "book",
-			price:           46,
+			price:           fixtures.Euro(4600),
 			err:             nil,
 			expectError:     false,
 			addDefaultBooks: true,
@@ -110,7 +111,7 @@ func TestSchoolBookAggregateOn(t *testing.T) {
 			eventVersion:    21,
 			eventAt:         time.Now(),
 			bookID:          "book",
-			price:           26.32,
+			price:           fixtures.Euro(2632),
 			err:             nil,
 			expectError:     false,
 			addDefaultBooks: true,
@@ -202,7 +203,7 @@ func TestSchoolBookAggregateOn(t *testing.T) {
 					Isbn:        test.isbn,
 					Name:        "biologie book",
 					Description: "book for biologie lessons",
-					Price:       59.99,
+					Price:       fixtures.Euro(5999),
 					Grades:      []int{5},
 				})
 			}
@@ -240,3 +241,20 @@ func TestSchoolBookAggregateOn(t *testing.T) {
 		})
 	}
 }
+
+func TestUpcastLegacyPrices(t *testing.T) {
+	aggregate := bookdomain.NewSchoolBookAggregate()
+	events := []domain.Event{
+		&domain.EventModel{Version: 1, Type: bookdomain.BookAdded, Data: "{\"BookID\":\"book\",\"Name\":\"Math\",\"Price\":24.99}"},
+		&domain.EventModel{Version: 2, Type: bookdomain.BookPriceIncreased, Data: "{\"BookID\":\"book\",\"Price\":26.32}"},
+	}
+	assert.NoError(t, aggregate.On(events[0]))
+	assert.Equal(t, fixtures.Euro(2499), aggregate.Books[0].Price)
+	assert.NoError(t, aggregate.On(events[1]))
+	assert.Equal(t, fixtures.Euro(2632), aggregate.Books[0].Price)
+
+	event := &domain.EventModel{Version: 3, Type: bookdomain.BookPriceDecreased}
+	assert.NoError(t, event.SetJsonData(bookdomain.BookPriceDecreasedEvent{BookID: "book", Price: domain.NewMoney(2000, "CHF")}))
+	assert.NoError(t, aggregate.On(event))
+	assert.Equal(t, domain.NewMoney(2000, "CHF"), aggregate.Books[0].Price)
+}
@@ -6,13 +6,22 @@ import (
 	"github.com/kammeph/school-book-storage-service/fp"
 )
 
-func (a *SchoolBookAggregate) AddBook(isbn, name, description string, price float64, grades []int) (string, error) {
-	if isbn == "" {
+// AddBook adds a book to the catalogue of the school. The ISBN is normalized,
+// so a book can not be added twice with different notations of its ISBN.
+func (a *SchoolBookAggregate) AddBook(rawIsbn, name, description string, price domain.Money, grades []int) (string, error) {
+	if rawIsbn == "" {
 		return "", ErrIsbnNotSet
 	}
+	isbn, err := domain.ParseIsbn(rawIsbn)
+	if err != nil {
+		return "", err
+	}
 	if name == "" {
 		return "", ErrBookNameNotSet
 	}
+	if err := validatePrice(price); err != nil {
+		return "", err
+	}
 	book := fp.Find(a.Books, func(b Book) bool { return b.Name == name || b.Isbn == isbn })
 	if book != nil {
 		return "", ErrBookAlreadyExists(isbn, name)
@@ -50,17 +59,24 @@ func (a *SchoolBookAggregate) AdjustBookMeta(bookID, name, description string, g
 	return nil
 }
 
-func (a *SchoolBookAggregate) IncreaseBookPrice(bookID string, price float64, reason string) error {
+func (a *SchoolBookAggregate) IncreaseBookPrice(bookID string, price domain.Money, reason string) error {
 	if reason == "" {
 		return domain.ErrReasonNotSpecified
 	}
-	if price < 0.0 {
-		return ErrBookPriceLessThanZero
+	if err := validatePrice(price); err != nil {
+		return err
 	}
 	book := fp.Find(a.Books, func(b Book) bool { return b.ID == bookID })
 	if book == nil {
 		return ErrBookWithIDNotFound(bookID)
 	}
+	comparison, err := price.Compare(book.Price)
+	if err != nil {
+		return err
+	}
+	if comparison != 1 {
+		return ErrPriceNotIncreased
+	}
 	event, err := NewBookPriceIncreasedEvent(a, bookID, reason, price)
 	if err != nil {
 		return err
@@ -71,17 +87,24 @@ func (a *SchoolBookAggregate) IncreaseBookPrice(bookID string, price float64, re
 	return nil
 }
 
-func (a *SchoolBookAggregate) DecreaseBookPrice(bookID string, price float64, reason string) error {
+func (a *SchoolBookAggregate) DecreaseBookPrice(bookID string, price domain.Money, reason string) error {
 	if reason == "" {
 		return domain.ErrReasonNotSpecified
 	}
-	if price < 0.0 {
-		return ErrBookPriceLessThanZero
+	if err := validatePrice(price); err != nil {
+		return err
 	}
 	book := fp.Find(a.Books, func(b Book) bool { return b.ID == bookID })
 	if book == nil {
 		return ErrBookWithIDNotFound(bookID)
 	}
+	comparison, err := price.Compare(book.Price)
+	if err != nil {
+		return err
+	}
+	if comparison != -1 {
+		return ErrPriceNotDecreased
+	}
 	event, err := NewBookPriceDecreasedEvent(a, bookID, reason, price)
 	if err != nil {
 		return err
@@ -91,3 +114,13 @@ func (a *SchoolBookAggregate) DecreaseBookPrice(bookID string, price float64, re
 	}
 	return nil
 }
+
+func validatePrice(price domain.Money) error {
+	if err := price.Validate(); err != nil {
+		return err
+	}
+	if price.IsNegative() {
+		return ErrBookPriceLessThanZero
+	}
+	return nil
+}
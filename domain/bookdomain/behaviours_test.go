@@ -5,6 +5,7 @@ import (
 
 	"github.com/kammeph/school-book-storage-service/domain"
 	"github.com/kammeph/school-book-storage-service/domain/bookdomain"
+	"github.com/kammeph/school-book-storage-service/testing/fixtures"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -21,7 +22,7 @@ func TestAddBook(t *testing.T) {
 		isbn        string
 		bookName    string
 		description string
-		price       float64
+		price       domain.Money
 		grades      []int
 		err         error
 		expectError bool
@@ -29,10 +30,10 @@ func TestAddBook(t *testing.T) {
 		{
 			name:        "add Book",
 			books:       []bookdomain.Book{},
-			isbn:        "123456",
+			isbn:        "978-3-12-345678-7",
 			bookName:    "English Book",
 			description: "Book for english lessons",
-			price:       29.99,
+			price:       fixtures.Euro(2999),
 			grades:      []int{1},
 			err:         nil,
 			expectError: false,
@@ -43,18 +44,29 @@ func TestAddBook(t *testing.T) {
 			isbn:        "",
 			bookName:    "English Book",
 			description: "Book for english lessons",
-			price:       29.99,
+			price:       fixtures.Euro(2999),
 			grades:      []int{1},
 			err:         bookdomain.ErrIsbnNotSet,
 			expectError: true,
 		},
+		{
+			name:        "add Book isbn invalid",
+			books:       []bookdomain.Book{},
+			isbn:        "978-3-12-345678-9",
+			bookName:    "English Book",
+			description: "Book for english lessons",
+			price:       fixtures.Euro(2999),
+			grades:      []int{1},
+			err:         domain.ErrInvalidIsbn,
+			expectError: true,
+		},
 		{
 			name:        "add Book name not set",
 			books:       []bookdomain.Book{},
-			isbn:        "123456",
+			isbn:        "978-3-12-345678-7",
 			bookName:    "",
 			description: "Book for english lessons",
-			price:       29.99,
+			price:       fixtures.Euro(2999),
 			grades:      []int{1},
 			err:         bookdomain.ErrBookNameNotSet,
 			expectError: true,
@@ -63,15 +75,15 @@ func TestAddBook(t *testing.T) {
 			name: "book with isbn exists",
 			books: []bookdomain.Book{
 				{
-					Isbn: "123456",
+					Isbn: "9783123456787",
 				},
 			},
-			isbn:        "123456",
+			isbn:        "3-12-345678-1",
 			bookName:    "English Book",
 			description: "Book for english lessons",
-			price:       29.99,
+			price:       fixtures.Euro(2999),
 			grades:      []int{1},
-			err:         bookdomain.ErrBookAlreadyExists("123456", "English Book"),
+			err:         bookdomain.ErrBookAlreadyExists("9783123456787", "English Book"),
 			expectError: true,
 		},
 		{
@@ -81,12 +93,12 @@ func TestAddBook(t *testing.T) {
 					Name: "English Book",
 				},
 			},
-			isbn:        "123456",
+			isbn:        "978-3-12-345678-7",
 			bookName:    "English Book",
 			description: "Book for english lessons",
-			price:       29.99,
+			price:       fixtures.Euro(2999),
 			grades:      []int{1},
-			err:         bookdomain.ErrBookAlreadyExists("123456", "English Book"),
+			err:         bookdomain.ErrBookAlreadyExists("9783123456787", "English Book"),
 			expectError: true,
 		},
 	}
@@ -197,7 +209,7 @@ func TestIncreaseBookPrice(t *testing.T) {
 		name        string
 		books       []bookdomain.Book
 		BookID      string
-		price       float64
+		price       domain.Money
 		reason      string
 		err         error
 		expectError bool
@@ -207,11 +219,11 @@ func TestIncreaseBookPrice(t *testing.T) {
 			books: []bookdomain.Book{
 				{
 					ID:    "book",
-					Price: 29.99,
+					Price: fixtures.Euro(2999),
 				},
 			},
 			BookID:      "book",
-			price:       49.99,
+			price:       fixtures.Euro(4999),
 			reason:      "test",
 			err:         nil,
 			expectError: false,
@@ -221,11 +233,11 @@ func TestIncreaseBookPrice(t *testing.T) {
 			books: []bookdomain.Book{
 				{
 					ID:    "book",
-					Price: 29.99,
+					Price: fixtures.Euro(2999),
 				},
 			},
 			BookID:      "book",
-			price:       49.99,
+			price:       fixtures.Euro(4999),
 			reason:      "",
 			err:         domain.ErrReasonNotSpecified,
 			expectError: true,
@@ -235,20 +247,62 @@ func TestIncreaseBookPrice(t *testing.T) {
 			books: []bookdomain.Book{
 				{
 					ID:    "book",
-					Price: 29.99,
+					Price: fixtures.Euro(2999),
 				},
 			},
 			BookID:      "book",
-			price:       -29.99,
+			price:       fixtures.Euro(-2999),
 			reason:      "test",
 			err:         bookdomain.ErrBookPriceLessThanZero,
 			expectError: true,
 		},
+		{
+			name: "increase book price, price not higher",
+			books: []bookdomain.Book{
+				{
+					ID:    "book",
+					Price: fixtures.Euro(2999),
+				},
+			},
+			BookID:      "book",
+			price:       fixtures.Euro(2999),
+			reason:      "test",
+			err:         bookdomain.ErrPriceNotIncreased,
+			expectError: true,
+		},
+		{
+			name: "increase book price, other currency",
+			books: []bookdomain.Book{
+				{
+					ID:    "book",
+					Price: fixtures.Euro(2999),
+				},
+			},
+			BookID:      "book",
+			price:       domain.NewMoney(3999, "CHF"),
+			reason:      "test",
+			err:         domain.ErrCurrencyMismatch,
+			expectError: true,
+		},
+		{
+			name: "increase book price, invalid currency",
+			books: []bookdomain.Book{
+				{
+					ID:    "book",
+					Price: fixtures.Euro(2999),
+				},
+			},
+			BookID:      "book",
+			price:       domain.NewMoney(3999, "euro"),
+			reason:      "test",
+			err:         domain.ErrInvalidCurrency,
+			expectError: true,
+		},
 		{
 			name:        "increase book price, book not found",
 			books:       []bookdomain.Book{},
 			BookID:      "book",
-			price:       39.99,
+			price:       fixtures.Euro(3999),
 			reason:      "test",
 			err:         bookdomain.ErrBookWithIDNotFound("book"),
 			expectError: true,
@@ -276,7 +330,7 @@ func TestDecreaseBookPrice(t *testing.T) {
 		name        string
 		books       []bookdomain.Book
 		BookID      string
-		price       float64
+		price       domain.Money
 		reason      string
 		err         error
 		expectError bool
@@ -286,11 +340,11 @@ func TestDecreaseBookPrice(t *testing.T) {
 			books: []bookdomain.Book{
 				{
 					ID:    "book",
-					Price: 29.99,
+					Price: fixtures.Euro(2999),
 				},
 			},
 			BookID:      "book",
-			price:       49.99,
+			price:       fixtures.Euro(1999),
 			reason:      "test",
 			err:         nil,
 			expectError: false,
@@ -300,11 +354,11 @@ func TestDecreaseBookPrice(t *testing.T) {
 			books: []bookdomain.Book{
 				{
 					ID:    "book",
-					Price: 29.99,
+					Price: fixtures.Euro(2999),
 				},
 			},
 			BookID:      "book",
-			price:       19.99,
+			price:       fixtures.Euro(1999),
 			reason:      "",
 			err:         domain.ErrReasonNotSpecified,
 			expectError: true,
@@ -314,20 +368,34 @@ func TestDecreaseBookPrice(t *testing.T) {
 			books: []bookdomain.Book{
 				{
 					ID:    "book",
-					Price: 29.99,
+					Price: fixtures.Euro(2999),
 				},
 			},
 			BookID:      "book",
-			price:       -19.99,
+			price:       fixtures.Euro(-1999),
 			reason:      "test",
 			err:         bookdomain.ErrBookPriceLessThanZero,
 			expectError: true,
 		},
+		{
+			name: "decrease book price, price not lower",
+			books: []bookdomain.Book{
+				{
+					ID:    "book",
+					Price: fixtures.Euro(2999),
+				},
+			},
+			BookID:      "book",
+			price:       fixtures.Euro(4999),
+			reason:      "test",
+			err:         bookdomain.ErrPriceNotDecreased,
+			expectError: true,
+		},
 		{
 			name:        "decrease book price, book not found",
 			books:       []bookdomain.Book{},
 			BookID:      "book",
-			price:       19.99,
+			price:       fixtures.Euro(1999),
 			reason:      "test",
 			err:         bookdomain.ErrBookWithIDNotFound("book"),
 			expectError: true,
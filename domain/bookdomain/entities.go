@@ -1,19 +1,23 @@
 package bookdomain
 
-import "time"
+import (
+	"time"
+
+	"github.com/kammeph/school-book-storage-service/domain"
+)
 
 type Book struct {
 	ID          string
-	Isbn        string
+	Isbn        domain.Isbn
 	Name        string
 	Description string
-	Price       float64
+	Price       domain.Money
 	Grades      []int
 	CreatedAt   time.Time
 	UpdatedAt   time.Time
 }
 
-func NewBook(id, isbn, name, description string, price float64, grades []int, timestamp time.Time) Book {
+func NewBook(id string, isbn domain.Isbn, name, description string, price domain.Money, grades []int, timestamp time.Time) Book {
 	return Book{
 		ID:          id,
 		Isbn:        isbn,
@@ -4,16 +4,18 @@ import (
 	"testing"
 	"time"
 
+	"github.com/kammeph/school-book-storage-service/domain"
 	"github.com/kammeph/school-book-storage-service/domain/bookdomain"
+	"github.com/kammeph/school-book-storage-service/testing/fixtures"
 	"github.com/stretchr/testify/assert"
 )
 
 func TestNewBook(t *testing.T) {
 	id := "masterbook"
-	isbn := "12345"
+	isbn := domain.Isbn("9783161484100")
 	name := "English Book"
 	description := "book for english lessons"
-	price := 25.90
+	price := fixtures.Euro(2590)
 	grades := []int{1, 2}
 	timestamp := time.Now()
 	book := bookdomain.NewBook(id, isbn, name, description, price, grades, timestamp)
@@ -27,3 +29,16 @@ func TestNewBook(t *testing.T) {
 	assert.Equal(t, timestamp, book.CreatedAt)
 	assert.Zero(t, book.UpdatedAt)
 }
+
+func TestPriceAt(t *testing.T) {
+	added := time.Date(2022, time.August, 1, 0, 0, 0, 0, time.UTC)
+	increased := added.AddDate(0, 6, 0)
+	book := bookdomain.NewBookProjection("school", "book", "123", "Math", "", fixtures.Euro(3000), []int{5}, 2)
+	assert.Equal(t, fixtures.Euro(3000), book.PriceAt(added))
+
+	book.PriceHistory = []bookdomain.PriceChange{{Price: fixtures.Euro(2500), ValidFrom: added}, {Price: fixtures.Euro(3000), ValidFrom: increased}}
+	assert.Equal(t, fixtures.Euro(2500), book.PriceAt(added.AddDate(0, 0, -1)))
+	assert.Equal(t, fixtures.Euro(2500), book.PriceAt(added.AddDate(0, 1, 0)))
+	assert.Equal(t, fixtures.Euro(3000), book.PriceAt(increased))
+	assert.Equal(t, fixtures.Euro(3000), book.PriceAt(increased.AddDate(1, 0, 0)))
+}
@@ -3,6 +3,8 @@ package bookdomain
 import (
 	"errors"
 	"fmt"
+
+	"github.com/kammeph/school-book-storage-service/domain"
 )
 
 var (
@@ -10,6 +12,8 @@ var (
 	ErrBookNameNotSet        = errors.New("Book name not set")
 	ErrBookDescriptionNotSet = errors.New("Book description not set")
 	ErrBookPriceLessThanZero = errors.New("Book price is less than zero")
+	ErrPriceNotIncreased     = errors.New("the new price is not higher than the current price")
+	ErrPriceNotDecreased     = errors.New("the new price is not lower than the current price")
 )
 
 func ErrApplyEventBookAlreadyExists(eventType, id string) error {
@@ -24,7 +28,7 @@ func ErrBookWithIDNotFound(bookID string) error {
 	return fmt.Errorf("book with ID %s not found", bookID)
 }
 
-func ErrBookAlreadyExists(isbn, name string) error {
+func ErrBookAlreadyExists(isbn domain.Isbn, name string) error {
 	return fmt.Errorf("there is already a book with the ISBN %s or name %s", isbn, name)
 }
 
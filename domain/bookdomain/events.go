@@ -12,17 +12,19 @@ var (
 type BookAddedEvent struct {
 	SchoolID    string
 	BookID      string
-	Isbn        string
+	Isbn        domain.Isbn
 	Name        string
 	Description string
-	Price       float64
+	Price       domain.Money
 	Grades      []int
 }
 
 func NewBookAddedEvent(
 	aggregate *SchoolBookAggregate,
-	schoolID, bookID, isbn, name, description string,
-	price float64,
+	schoolID, bookID string,
+	isbn domain.Isbn,
+	name, description string,
+	price domain.Money,
 	grades []int) (domain.Event, error) {
 	eventData := BookAddedEvent{schoolID, bookID, isbn, name, description, price, grades}
 	event := domain.NewEvent(aggregate, BookAdded)
@@ -50,11 +52,11 @@ func NewBookMetaAdjustedEvent(aggregate *SchoolBookAggregate, bookID, name, desc
 
 type BookPriceIncreasedEvent struct {
 	BookID string
-	Price  float64
+	Price  domain.Money
 	Reason string
 }
 
-func NewBookPriceIncreasedEvent(aggregate *SchoolBookAggregate, bookID, reason string, price float64) (domain.Event, error) {
+func NewBookPriceIncreasedEvent(aggregate *SchoolBookAggregate, bookID, reason string, price domain.Money) (domain.Event, error) {
 	eventData := BookPriceIncreasedEvent{bookID, price, reason}
 	event := domain.NewEvent(aggregate, BookPriceIncreased)
 	if err := event.SetJsonData(eventData); err != nil {
@@ -65,11 +67,11 @@ func NewBookPriceIncreasedEvent(aggregate *SchoolBookAggregate, bookID, reason s
 
 type BookPriceDecreasedEvent struct {
 	BookID string
-	Price  float64
+	Price  domain.Money
 	Reason string
 }
 
-func NewBookPriceDecreasedEvent(aggregate *SchoolBookAggregate, bookID, reason string, price float64) (domain.Event, error) {
+func NewBookPriceDecreasedEvent(aggregate *SchoolBookAggregate, bookID, reason string, price domain.Money) (domain.Event, error) {
 	eventData := BookPriceDecreasedEvent{bookID, price, reason}
 	event := domain.NewEvent(aggregate, BookPriceDecreased)
 	if err := event.SetJsonData(eventData); err != nil {
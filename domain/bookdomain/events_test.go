@@ -3,17 +3,19 @@ package bookdomain_test
 import (
 	"testing"
 
+	"github.com/kammeph/school-book-storage-service/domain"
 	"github.com/kammeph/school-book-storage-service/domain/bookdomain"
+	"github.com/kammeph/school-book-storage-service/testing/fixtures"
 	"github.com/stretchr/testify/assert"
 )
 
 func TestNewBookAddedEvent(t *testing.T) {
 	schoolID := "school"
 	bookID := "masterBook"
-	isbn := "12345"
+	isbn := domain.Isbn("9783161484100")
 	name := "English Book"
 	description := "book for english lessons"
-	price := 20.99
+	price := fixtures.Euro(2099)
 	grades := []int{2, 3}
 	aggregate := bookdomain.NewSchoolBookAggregate()
 	aggregate.Version = 4
@@ -44,7 +46,7 @@ func TestNewBookMetaAdjustedEvent(t *testing.T) {
 
 func TestNewBookPriceIncreasedEvent(t *testing.T) {
 	bookID := "masterbook"
-	price := 19.99
+	price := fixtures.Euro(1999)
 	reason := "test"
 	aggregate := bookdomain.NewSchoolBookAggregate()
 	aggregate.Version = 7
@@ -59,7 +61,7 @@ func TestNewBookPriceIncreasedEvent(t *testing.T) {
 
 func TestNewBookPriceDecreasedEvent(t *testing.T) {
 	bookID := "masterbook"
-	price := 19.99
+	price := fixtures.Euro(1999)
 	reason := "test"
 	aggregate := bookdomain.NewSchoolBookAggregate()
 	aggregate.Version = 7
@@ -0,0 +1,44 @@
+package bookdomain
+
+import (
+	"time"
+
+	"github.com/kammeph/school-book-storage-service/domain"
+)
+
+type PriceChange struct {
+	Price     domain.Money `json:"price" bson:"price"`
+	ValidFrom time.Time    `json:"validFrom" bson:"validFrom"`
+}
+
+type BookProjection struct {
+	SchoolID     string        `json:"schoolId" bson:"schoolId"`
+	BookID       string        `json:"bookId" bson:"bookId"`
+	Isbn         domain.Isbn   `json:"isbn" bson:"isbn"`
+	Name         string        `json:"name" bson:"name"`
+	Description  string        `json:"description" bson:"description"`
+	Price        domain.Money  `json:"price" bson:"price"`
+	PriceHistory []PriceChange `json:"priceHistory" bson:"priceHistory"`
+	Grades       []int         `json:"grades" bson:"grades"`
+	Version      int           `json:"version" bson:"version"`
+}
+
+func NewBookProjection(schoolID, bookID string, isbn domain.Isbn, name, description string, price domain.Money, grades []int, version int) BookProjection {
+	return BookProjection{schoolID, bookID, isbn, name, description, price, []PriceChange{}, grades, version}
+}
+
+// PriceAt returns the price the book had at the given time. Before the first
+// known price and without a history the earliest known price applies.
+func (b BookProjection) PriceAt(at time.Time) domain.Money {
+	if len(b.PriceHistory) == 0 {
+		return b.Price
+	}
+	price := b.PriceHistory[0].Price
+	for _, change := range b.PriceHistory {
+		if change.ValidFrom.After(at) {
+			break
+		}
+		price = change.Price
+	}
+	return price
+}
@@ -0,0 +1,12 @@
+package bookdomain
+
+import "github.com/kammeph/school-book-storage-service/domain"
+
+// Prices used to be stored as plain numbers without a currency and ISBNs as
+// entered by the user.
+func init() {
+	domain.RegisterUpcaster(BookAdded, domain.IsbnUpcaster("Isbn"))
+	domain.RegisterUpcaster(BookAdded, domain.MoneyUpcaster("Price"))
+	domain.RegisterUpcaster(BookPriceIncreased, domain.MoneyUpcaster("Price"))
+	domain.RegisterUpcaster(BookPriceDecreased, domain.MoneyUpcaster("Price"))
+}
@@ -0,0 +1,104 @@
+package chargedomain
+
+import (
+	"github.com/kammeph/school-book-storage-service/domain"
+	"github.com/kammeph/school-book-storage-service/fp"
+)
+
+type SchoolChargeAggregate struct {
+	*domain.AggregateModel
+	Depreciation Depreciation
+	Charges      []Charge
+}
+
+func NewSchoolChargeAggregate() *SchoolChargeAggregate {
+	aggregate := &SchoolChargeAggregate{
+		Charges: []Charge{},
+	}
+	model := domain.NewAggregateModel(aggregate.On)
+	aggregate.AggregateModel = &model
+	return aggregate
+}
+
+func NewSchoolChargeAggregateWithID(id string) *SchoolChargeAggregate {
+	aggregate := NewSchoolChargeAggregate()
+	aggregate.ID = id
+	return aggregate
+}
+
+func (a *SchoolChargeAggregate) On(event domain.Event) error {
+	switch event.EventType() {
+	case DepreciationConfigured:
+		return a.onDepreciationConfigured(event)
+	case ChargeCreated:
+		return a.onChargeCreated(event)
+	case ChargePaid:
+		return a.onChargePaid(event)
+	case ChargeWaived:
+		return a.onChargeWaived(event)
+	default:
+		return domain.ErrUnknownEvent(event)
+	}
+}
+
+func (a *SchoolChargeAggregate) onDepreciationConfigured(event domain.Event) error {
+	eventData := DepreciationConfiguredEvent{}
+	if err := event.GetJsonData(&eventData); err != nil {
+		return err
+	}
+	a.Version = event.EventVersion()
+	a.Depreciation = Depreciation{eventData.RatePerYear, eventData.Minimum}
+	return nil
+}
+
+func (a *SchoolChargeAggregate) onChargeCreated(event domain.Event) error {
+	eventData := ChargeCreatedEvent{}
+	if err := event.GetJsonData(&eventData); err != nil {
+		return err
+	}
+	if fp.Some(a.Charges, func(c Charge) bool { return c.ID == eventData.ChargeID }) {
+		return ErrApplyEventChargeAlreadyExists(event.EventType(), eventData.ChargeID)
+	}
+	charge := NewCharge(
+		eventData.ChargeID,
+		eventData.PupilID,
+		eventData.BookID,
+		eventData.Isbn,
+		eventData.Title,
+		eventData.Incident,
+		eventData.LoanID,
+		eventData.Description,
+		eventData.Price,
+		eventData.Amount,
+		event.EventAt())
+	a.Version = event.EventVersion()
+	a.Charges = append(a.Charges, charge)
+	return nil
+}
+
+func (a *SchoolChargeAggregate) onChargePaid(event domain.Event) error {
+	eventData := ChargePaidEvent{}
+	if err := event.GetJsonData(&eventData); err != nil {
+		return err
+	}
+	return a.settle(event, eventData.ChargeID, Paid)
+}
+
+func (a *SchoolChargeAggregate) onChargeWaived(event domain.Event) error {
+	eventData := ChargeWaivedEvent{}
+	if err := event.GetJsonData(&eventData); err != nil {
+		return err
+	}
+	return a.settle(event, eventData.ChargeID, Waived)
+}
+
+func (a *SchoolChargeAggregate) settle(event domain.Event, chargeID string, status ChargeStatus) error {
+	charge := fp.Find(a.Charges, func(c Charge) bool { return c.ID == chargeID })
+	if charge == nil {
+		return ErrApplyEventChargeNotFound(event.EventType(), chargeID)
+	}
+	a.Version = event.EventVersion()
+	charge.Status = status
+	charge.UpdatedAt = event.EventAt()
+	return nil
+}
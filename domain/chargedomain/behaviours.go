@@ -0,0 +1,100 @@
+package chargedomain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/kammeph/school-book-storage-service/domain"
+	"github.com/kammeph/school-book-storage-service/fp"
+)
+
+func (a *SchoolChargeAggregate) ConfigureDepreciation(depreciation Depreciation) error {
+	if depreciation.RatePerYear < 0 || depreciation.RatePerYear > 1 ||
+		depreciation.Minimum < 0 || depreciation.Minimum > 1 {
+		return ErrInvalidDepreciation
+	}
+	event, err := NewDepreciationConfigured(a, depreciation)
+	if err != nil {
+		return err
+	}
+	return a.Apply(event)
+}
+
+// CreateCharge charges the pupil the replacement fee of a lost or damaged
+// book. The fee is the price of the book depreciated for the years since the
+// book was acquired.
+func (a *SchoolChargeAggregate) CreateCharge(
+	pupilID, bookID string,
+	isbn domain.Isbn,
+	title string,
+	incident Incident,
+	loanID, description string,
+	price domain.Money,
+	acquiredAt, at time.Time,
+) (string, error) {
+	if pupilID == "" {
+		return "", ErrPupilIDNotSet
+	}
+	if bookID == "" {
+		return "", ErrBookIDNotSet
+	}
+	if incident != Lost && incident != Damaged {
+		return "", ErrInvalidIncident(incident)
+	}
+	if err := price.Validate(); err != nil {
+		return "", err
+	}
+	if price.IsNegative() {
+		return "", ErrPriceNegative
+	}
+	amount := a.Depreciation.Fee(price, acquiredAt, at)
+	charge := NewCharge(uuid.NewString(), pupilID, bookID, isbn, title, incident, loanID, description, price, amount, at)
+	event, err := NewChargeCreated(a, charge)
+	if err != nil {
+		return "", err
+	}
+	if err := a.Apply(event); err != nil {
+		return "", err
+	}
+	return charge.ID, nil
+}
+
+func (a *SchoolChargeAggregate) PayCharge(chargeID string, paidAt time.Time) error {
+	charge, err := a.openCharge(chargeID)
+	if err != nil {
+		return err
+	}
+	if paidAt.Before(charge.CreatedAt) {
+		return ErrPaymentBeforeCharged
+	}
+	event, err := NewChargePaid(a, chargeID, paidAt)
+	if err != nil {
+		return err
+	}
+	return a.Apply(event)
+}
+
+func (a *SchoolChargeAggregate) WaiveCharge(chargeID, reason string) error {
+	if reason == "" {
+		return domain.ErrReasonNotSpecified
+	}
+	if _, err := a.openCharge(chargeID); err != nil {
+		return err
+	}
+	event, err := NewChargeWaived(a, chargeID, reason)
+	if err != nil {
+		return err
+	}
+	return a.Apply(event)
+}
+
+func (a *SchoolChargeAggregate) openCharge(chargeID string) (*Charge, error) {
+	charge := fp.Find(a.Charges, func(c Charge) bool { return c.ID == chargeID })
+	if charge == nil {
+		return nil, ErrChargeWithIDNotFound(chargeID)
+	}
+	if charge.Status != Open {
+		return nil, ErrChargeNotOpen(chargeID, charge.Status)
+	}
+	return charge, nil
+}
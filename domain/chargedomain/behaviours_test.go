@@ -0,0 +1,243 @@
+package chargedomain_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kammeph/school-book-storage-service/domain"
+	"github.com/kammeph/school-book-storage-service/domain/chargedomain"
+	"github.com/kammeph/school-book-storage-service/testing/fixtures"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	acquiredAt = time.Date(2020, 8, 1, 0, 0, 0, 0, time.UTC)
+	chargedAt  = time.Date(2022, 9, 1, 0, 0, 0, 0, time.UTC)
+)
+
+func initSchoolChargeAggregate(charges []chargedomain.Charge) *chargedomain.SchoolChargeAggregate {
+	aggregate := chargedomain.NewSchoolChargeAggregateWithID("school")
+	aggregate.Charges = charges
+	return aggregate
+}
+
+func openCharge() chargedomain.Charge {
+	return chargedomain.NewCharge("charge", "pupil", "book", "123", "Math", chargedomain.Lost, "", "", fixtures.Euro(2000), fixtures.Euro(2000), chargedAt)
+}
+
+func TestFee(t *testing.T) {
+	depreciation := chargedomain.Depreciation{RatePerYear: 0.2, Minimum: 0.3}
+	assert.Equal(t, fixtures.Euro(2490), depreciation.Fee(fixtures.Euro(2490), acquiredAt, acquiredAt.AddDate(0, 11, 0)))
+	assert.Equal(t, fixtures.Euro(1992), depreciation.Fee(fixtures.Euro(2490), acquiredAt, acquiredAt.AddDate(1, 0, 0)))
+	assert.Equal(t, fixtures.Euro(1494), depreciation.Fee(fixtures.Euro(2490), acquiredAt, chargedAt))
+	assert.Equal(t, fixtures.Euro(747), depreciation.Fee(fixtures.Euro(2490), acquiredAt, acquiredAt.AddDate(10, 0, 0)))
+	assert.Equal(t, fixtures.Euro(2490), chargedomain.Depreciation{}.Fee(fixtures.Euro(2490), acquiredAt, chargedAt))
+}
+
+func TestConfigureDepreciation(t *testing.T) {
+	tests := []struct {
+		name         string
+		depreciation chargedomain.Depreciation
+		err          error
+	}{
+		{
+			name:         "configure depreciation",
+			depreciation: chargedomain.Depreciation{RatePerYear: 0.2, Minimum: 0.3},
+		},
+		{
+			name:         "negative rate",
+			depreciation: chargedomain.Depreciation{RatePerYear: -0.1},
+			err:          chargedomain.ErrInvalidDepreciation,
+		},
+		{
+			name:         "minimum above price",
+			depreciation: chargedomain.Depreciation{RatePerYear: 0.1, Minimum: 1.5},
+			err:          chargedomain.ErrInvalidDepreciation,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			aggregate := initSchoolChargeAggregate(nil)
+			err := aggregate.ConfigureDepreciation(test.depreciation)
+			if test.err != nil {
+				assert.Equal(t, test.err, err)
+				assert.Zero(t, aggregate.Depreciation)
+				return
+			}
+			assert.Nil(t, err)
+			assert.Equal(t, test.depreciation, aggregate.Depreciation)
+			assert.Len(t, aggregate.Events, 1)
+		})
+	}
+}
+
+func TestCreateCharge(t *testing.T) {
+	tests := []struct {
+		name     string
+		pupilID  string
+		bookID   string
+		incident chargedomain.Incident
+		price    domain.Money
+		amount   domain.Money
+		err      error
+	}{
+		{
+			name:     "charge lost book",
+			pupilID:  "pupil",
+			bookID:   "book",
+			incident: chargedomain.Lost,
+			price:    fixtures.Euro(2500),
+			amount:   fixtures.Euro(1500),
+		},
+		{
+			name:     "charge damaged book",
+			pupilID:  "pupil",
+			bookID:   "book",
+			incident: chargedomain.Damaged,
+			price:    fixtures.Euro(2500),
+			amount:   fixtures.Euro(1500),
+		},
+		{
+			name:     "pupil not set",
+			bookID:   "book",
+			incident: chargedomain.Lost,
+			price:    fixtures.Euro(2500),
+			err:      chargedomain.ErrPupilIDNotSet,
+		},
+		{
+			name:     "book not set",
+			pupilID:  "pupil",
+			incident: chargedomain.Lost,
+			price:    fixtures.Euro(2500),
+			err:      chargedomain.ErrBookIDNotSet,
+		},
+		{
+			name:     "invalid incident",
+			pupilID:  "pupil",
+			bookID:   "book",
+			incident: "stolen",
+			price:    fixtures.Euro(2500),
+			err:      chargedomain.ErrInvalidIncident("stolen"),
+		},
+		{
+			name:     "negative price",
+			pupilID:  "pupil",
+			bookID:   "book",
+			incident: chargedomain.Lost,
+			price:    fixtures.Euro(-100),
+			err:      chargedomain.ErrPriceNegative,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			aggregate := initSchoolChargeAggregate(nil)
+			aggregate.Depreciation = chargedomain.Depreciation{RatePerYear: 0.2, Minimum: 0.3}
+			chargeID, err := aggregate.CreateCharge(test.pupilID, test.bookID, "123", "Math", test.incident, "", "", test.price, acquiredAt, chargedAt)
+			if test.err != nil {
+				assert.Equal(t, test.err, err)
+				assert.Empty(t, aggregate.Charges)
+				return
+			}
+			assert.Nil(t, err)
+			assert.Len(t, aggregate.Charges, 1)
+			assert.Equal(t, chargeID, aggregate.Charges[0].ID)
+			assert.Equal(t, test.price, aggregate.Charges[0].Price)
+			assert.Equal(t, test.amount, aggregate.Charges[0].Amount)
+			assert.Equal(t, chargedomain.Open, aggregate.Charges[0].Status)
+		})
+	}
+}
+
+func TestPayCharge(t *testing.T) {
+	tests := []struct {
+		name     string
+		charges  []chargedomain.Charge
+		chargeID string
+		paidAt   time.Time
+		err      error
+	}{
+		{
+			name:     "pay charge",
+			charges:  []chargedomain.Charge{openCharge()},
+			chargeID: "charge",
+			paidAt:   chargedAt.AddDate(0, 0, 7),
+		},
+		{
+			name:     "charge not found",
+			charges:  []chargedomain.Charge{openCharge()},
+			chargeID: "unknown",
+			paidAt:   chargedAt.AddDate(0, 0, 7),
+			err:      chargedomain.ErrChargeWithIDNotFound("unknown"),
+		},
+		{
+			name:     "charge already waived",
+			charges:  []chargedomain.Charge{{ID: "charge", Status: chargedomain.Waived, CreatedAt: chargedAt}},
+			chargeID: "charge",
+			paidAt:   chargedAt.AddDate(0, 0, 7),
+			err:      chargedomain.ErrChargeNotOpen("charge", chargedomain.Waived),
+		},
+		{
+			name:     "paid before charged",
+			charges:  []chargedomain.Charge{openCharge()},
+			chargeID: "charge",
+			paidAt:   chargedAt.AddDate(0, 0, -1),
+			err:      chargedomain.ErrPaymentBeforeCharged,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			aggregate := initSchoolChargeAggregate(test.charges)
+			err := aggregate.PayCharge(test.chargeID, test.paidAt)
+			if test.err != nil {
+				assert.Equal(t, test.err, err)
+				assert.Empty(t, aggregate.Events)
+				return
+			}
+			assert.Nil(t, err)
+			assert.Equal(t, chargedomain.Paid, aggregate.Charges[0].Status)
+		})
+	}
+}
+
+func TestWaiveCharge(t *testing.T) {
+	tests := []struct {
+		name     string
+		charges  []chargedomain.Charge
+		chargeID string
+		reason   string
+		err      error
+	}{
+		{
+			name:     "waive charge",
+			charges:  []chargedomain.Charge{openCharge()},
+			chargeID: "charge",
+			reason:   "hardship",
+		},
+		{
+			name:     "reason not specified",
+			charges:  []chargedomain.Charge{openCharge()},
+			chargeID: "charge",
+			err:      domain.ErrReasonNotSpecified,
+		},
+		{
+			name:     "charge already paid",
+			charges:  []chargedomain.Charge{{ID: "charge", Status: chargedomain.Paid, CreatedAt: chargedAt}},
+			chargeID: "charge",
+			reason:   "hardship",
+			err:      chargedomain.ErrChargeNotOpen("charge", chargedomain.Paid),
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			aggregate := initSchoolChargeAggregate(test.charges)
+			err := aggregate.WaiveCharge(test.chargeID, test.reason)
+			if test.err != nil {
+				assert.Equal(t, test.err, err)
+				assert.Empty(t, aggregate.Events)
+				return
+			}
+			assert.Nil(t, err)
+			assert.Equal(t, chargedomain.Waived, aggregate.Charges[0].Status)
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package chargedomain
+
+import (
+	"time"
+
+	"github.com/kammeph/school-book-storage-service/domain"
+)
+
+type ChargeStatus string
+
+const (
+	Open   ChargeStatus = "open"
+	Paid   ChargeStatus = "paid"
+	Waived ChargeStatus = "waived"
+)
+
+type Incident string
+
+const (
+	Lost    Incident = "lost"
+	Damaged Incident = "damaged"
+)
+
+// Depreciation reduces the replacement fee of a book by a rate for every full
+// year the book was in use. The fee never drops below the minimum share of
+// the price.
+type Depreciation struct {
+	RatePerYear float64 `json:"ratePerYear"`
+	Minimum     float64 `json:"minimum"`
+}
+
+// Fee returns the replacement fee of a book with the price that was acquired
+// at the given time, rounded to cents.
+func (d Depreciation) Fee(price domain.Money, acquiredAt, at time.Time) domain.Money {
+	share := 1 - d.RatePerYear*float64(fullYears(acquiredAt, at))
+	if share < d.Minimum {
+		share = d.Minimum
+	}
+	if share > 1 {
+		share = 1
+	}
+	return price.Scale(share)
+}
+
+func fullYears(from, to time.Time) int {
+	years := 0
+	for !from.AddDate(years+1, 0, 0).After(to) {
+		years++
+	}
+	return years
+}
+
+type Charge struct {
+	ID          string
+	PupilID     string
+	BookID      string
+	Isbn        domain.Isbn
+	Title       string
+	Incident    Incident
+	LoanID      string
+	Description string
+	Price       domain.Money
+	Amount      domain.Money
+	Status      ChargeStatus
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+func NewCharge(id, pupilID, bookID string, isbn domain.Isbn, title string, incident Incident, loanID, description string, price, amount domain.Money, timeStamp time.Time) Charge {
+	return Charge{
+		ID:          id,
+		PupilID:     pupilID,
+		BookID:      bookID,
+		Isbn:        isbn,
+		Title:       title,
+		Incident:    incident,
+		LoanID:      loanID,
+		Description: description,
+		Price:       price,
+		Amount:      amount,
+		Status:      Open,
+		CreatedAt:   timeStamp,
+	}
+}
@@ -0,0 +1,38 @@
+package chargedomain
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrPupilIDNotSet        = errors.New("pupil ID not set")
+	ErrBookIDNotSet         = errors.New("book ID not set")
+	ErrPriceNegative        = errors.New("the price must not be negative")
+	ErrInvalidDepreciation  = errors.New("the depreciation rate and minimum must be between 0 and 1")
+	ErrPaymentBeforeCharged = errors.New("a charge can not be paid before it was created")
+)
+
+func ErrInvalidIncident(incident Incident) error {
+	return fmt.Errorf("%s is not a valid incident", incident)
+}
+
+func ErrApplyEventChargeAlreadyExists(eventType, chargeID string) error {
+	return fmt.Errorf("can not apply %s: charge with ID %s already exists", eventType, chargeID)
+}
+
+func ErrApplyEventChargeNotFound(eventType, chargeID string) error {
+	return fmt.Errorf("can not apply %s: charge with ID %s not found", eventType, chargeID)
+}
+
+func ErrChargeWithIDNotFound(id string) error {
+	return fmt.Errorf("charge with ID %s not found", id)
+}
+
+func ErrChargeNotOpen(id string, status ChargeStatus) error {
+	return fmt.Errorf("charge %s is already %s", id, status)
+}
+
+func ErrLoanNotOfPupilAndBook(loanID, pupilID, bookID string) error {
+	return fmt.Errorf("loan %s is not a loan of book %s to pupil %s", loanID, bookID, pupilID)
+}
@@ -0,0 +1,100 @@
+package chargedomain
+
+import (
+	"time"
+
+	"github.com/kammeph/school-book-storage-service/domain"
+)
+
+var (
+	DepreciationConfigured = "CHARGE_DEPRECIATION_CONFIGURED"
+	ChargeCreated          = "CHARGE_CREATED"
+	ChargePaid             = "CHARGE_PAID"
+	ChargeWaived           = "CHARGE_WAIVED"
+)
+
+type DepreciationConfiguredEvent struct {
+	RatePerYear float64 `json:"ratePerYear"`
+	Minimum     float64 `json:"minimum"`
+}
+
+func NewDepreciationConfigured(aggregate *SchoolChargeAggregate, depreciation Depreciation) (domain.Event, error) {
+	eventData := DepreciationConfiguredEvent{
+		RatePerYear: depreciation.RatePerYear,
+		Minimum:     depreciation.Minimum,
+	}
+	event := domain.NewEvent(aggregate, DepreciationConfigured)
+	if err := event.SetJsonData(eventData); err != nil {
+		return nil, err
+	}
+	return event, nil
+}
+
+type ChargeCreatedEvent struct {
+	SchoolID    string       `json:"schoolId"`
+	ChargeID    string       `json:"chargeId"`
+	PupilID     string       `json:"pupilId"`
+	BookID      string       `json:"bookId"`
+	Isbn        domain.Isbn  `json:"isbn"`
+	Title       string       `json:"title"`
+	Incident    Incident     `json:"incident"`
+	LoanID      string       `json:"loanId"`
+	Description string       `json:"description"`
+	Price       domain.Money `json:"price"`
+	Amount      domain.Money `json:"amount"`
+}
+
+func NewChargeCreated(aggregate *SchoolChargeAggregate, charge Charge) (domain.Event, error) {
+	eventData := ChargeCreatedEvent{
+		SchoolID:    aggregate.AggregateID(),
+		ChargeID:    charge.ID,
+		PupilID:     charge.PupilID,
+		BookID:      charge.BookID,
+		Isbn:        charge.Isbn,
+		Title:       charge.Title,
+		Incident:    charge.Incident,
+		LoanID:      charge.LoanID,
+		Description: charge.Description,
+		Price:       charge.Price,
+		Amount:      charge.Amount,
+	}
+	event := domain.NewEvent(aggregate, ChargeCreated)
+	if err := event.SetJsonData(eventData); err != nil {
+		return nil, err
+	}
+	return event, nil
+}
+
+type ChargePaidEvent struct {
+	ChargeID string    `json:"chargeId"`
+	PaidAt   time.Time `json:"paidAt"`
+}
+
+func NewChargePaid(aggregate *SchoolChargeAggregate, chargeID string, paidAt time.Time) (domain.Event, error) {
+	eventData := ChargePaidEvent{
+		ChargeID: chargeID,
+		PaidAt:   paidAt,
+	}
+	event := domain.NewEvent(aggregate, ChargePaid)
+	if err := event.SetJsonData(eventData); err != nil {
+		return nil, err
+	}
+	return event, nil
+}
+
+type ChargeWaivedEvent struct {
+	ChargeID string `json:"chargeId"`
+	Reason   string `json:"reason"`
+}
+
+func NewChargeWaived(aggregate *SchoolChargeAggregate, chargeID, reason string) (domain.Event, error) {
+	eventData := ChargeWaivedEvent{
+		ChargeID: chargeID,
+		Reason:   reason,
+	}
+	event := domain.NewEvent(aggregate, ChargeWaived)
+	if err := event.SetJsonData(eventData); err != nil {
+		return nil, err
+	}
+	return event, nil
+}
@@ -0,0 +1,43 @@
+package chargedomain
+
+import (
+	"time"
+
+	"github.com/kammeph/school-book-storage-service/domain"
+)
+
+type ChargeProjection struct {
+	SchoolID    string       `json:"schoolId" bson:"schoolId"`
+	ChargeID    string       `json:"chargeId" bson:"chargeId"`
+	PupilID     string       `json:"pupilId" bson:"pupilId"`
+	BookID      string       `json:"bookId" bson:"bookId"`
+	Isbn        domain.Isbn  `json:"isbn" bson:"isbn"`
+	Title       string       `json:"title" bson:"title"`
+	Incident    Incident     `json:"incident" bson:"incident"`
+	LoanID      string       `json:"loanId" bson:"loanId"`
+	Description string       `json:"description" bson:"description"`
+	Price       domain.Money `json:"price" bson:"price"`
+	Amount      domain.Money `json:"amount" bson:"amount"`
+	Status      ChargeStatus `json:"status" bson:"status"`
+	CreatedAt   time.Time    `json:"createdAt" bson:"createdAt"`
+	Version     int          `json:"version" bson:"version"`
+}
+
+func NewChargeProjection(schoolID string, charge Charge, version int) ChargeProjection {
+	return ChargeProjection{
+		SchoolID:    schoolID,
+		ChargeID:    charge.ID,
+		PupilID:     charge.PupilID,
+		BookID:      charge.BookID,
+		Isbn:        charge.Isbn,
+		Title:       charge.Title,
+		Incident:    charge.Incident,
+		LoanID:      charge.LoanID,
+		Description: charge.Description,
+		Price:       charge.Price,
+		Amount:      charge.Amount,
+		Status:      charge.Status,
+		CreatedAt:   charge.CreatedAt,
+		Version:     version,
+	}
+}
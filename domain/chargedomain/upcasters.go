@@ -0,0 +1,10 @@
+package chargedomain
+
+import "github.com/kammeph/school-book-storage-service/domain"
+
+// Prices and amounts used to be stored as plain numbers without a currency and
+// ISBNs as entered by the user.
+func init() {
+	domain.RegisterUpcaster(ChargeCreated, domain.MoneyUpcaster("price", "amount"))
+	domain.RegisterUpcaster(ChargeCreated, domain.IsbnUpcaster("isbn"))
+}
@@ -19,6 +19,12 @@ func NewSchoolClassAggregate() *SchoolClassAggregate {
 	return aggregate
 }
 
+func NewSchoolClassAggregateWithID(id string) *SchoolClassAggregate {
+	aggregate := NewSchoolClassAggregate()
+	aggregate.ID = id
+	return aggregate
+}
+
 func (a *SchoolClassAggregate) On(event domain.Event) error {
 	switch event.EventType() {
 	case ClassCreated:
@@ -27,6 +33,16 @@ func (a *SchoolClassAggregate) On(event domain.Event) error {
 		return a.onNumberOfPupilsIncreased(event)
 	case NumberOfPupilsDecreased:
 		return a.onNumberOfPupilsDecreased(event)
+	case BooksReceived:
+		return a.onBooksReceived(event)
+	case BooksReturned:
+		return a.onBooksReturned(event)
+	case PupilAdded:
+		return a.onPupilAdded(event)
+	case PupilRemoved:
+		return a.onPupilRemoved(event)
+	case ClassClosed:
+		return a.onClassClosed(event)
 	default:
 		return domain.ErrUnknownEvent(event)
 	}
@@ -82,3 +98,95 @@ func (a *SchoolClassAggregate) onNumberOfPupilsDecreased(event domain.Event) err
 	class.NumberOfPupils -= eventData.Number
 	return nil
 }
+
+func (a *SchoolClassAggregate) onBooksReceived(event domain.Event) error {
+	eventData := BooksReceivedEvent{}
+	if err := event.GetJsonData(&eventData); err != nil {
+		return err
+	}
+	class := fp.Find(a.Classes, func(c Class) bool { return c.ID == eventData.ClassID })
+	if class == nil {
+		return ErrApplyEventClassNotFound(event.EventType(), eventData.ClassID)
+	}
+	book := fp.Find(class.Books, func(b ClassBook) bool { return b.BookID == eventData.BookID })
+	if book == nil {
+		class.Books = append(class.Books, ClassBook{eventData.BookID, eventData.Isbn, eventData.Title, eventData.Quantity})
+	} else {
+		book.Quantity += eventData.Quantity
+	}
+	a.Version = event.EventVersion()
+	class.UpdatedAt = event.EventAt()
+	return nil
+}
+
+func (a *SchoolClassAggregate) onBooksReturned(event domain.Event) error {
+	eventData := BooksReturnedEvent{}
+	if err := event.GetJsonData(&eventData); err != nil {
+		return err
+	}
+	class := fp.Find(a.Classes, func(c Class) bool { return c.ID == eventData.ClassID })
+	if class == nil {
+		return ErrApplyEventClassNotFound(event.EventType(), eventData.ClassID)
+	}
+	book := fp.Find(class.Books, func(b ClassBook) bool { return b.BookID == eventData.BookID })
+	if book == nil {
+		return ErrNotEnoughBooksInClass(eventData.BookID, 0, eventData.Quantity)
+	}
+	book.Quantity -= eventData.Quantity
+	if book.Quantity <= 0 {
+		class.Books = fp.Remove(class.Books, func(b ClassBook) bool { return b.BookID == eventData.BookID })
+	}
+	a.Version = event.EventVersion()
+	class.UpdatedAt = event.EventAt()
+	return nil
+}
+
+// onPupilAdded and onPupilRemoved derive the number of pupils from the
+// members of the class.
+func (a *SchoolClassAggregate) onPupilAdded(event domain.Event) error {
+	eventData := PupilAddedEvent{}
+	if err := event.GetJsonData(&eventData); err != nil {
+		return err
+	}
+	class := fp.Find(a.Classes, func(c Class) bool { return c.ID == eventData.ClassID })
+	if class == nil {
+		return ErrApplyEventClassNotFound(event.EventType(), eventData.ClassID)
+	}
+	a.Version = event.EventVersion()
+	class.Pupils = append(class.Pupils, eventData.PupilID)
+	class.NumberOfPupils = len(class.Pupils)
+	class.UpdatedAt = event.EventAt()
+	return nil
+}
+
+func (a *SchoolClassAggregate) onPupilRemoved(event domain.Event) error {
+	eventData := PupilRemovedEvent{}
+	if err := event.GetJsonData(&eventData); err != nil {
+		return err
+	}
+	class := fp.Find(a.Classes, func(c Class) bool { return c.ID == eventData.ClassID })
+	if class == nil {
+		return ErrApplyEventClassNotFound(event.EventType(), eventData.ClassID)
+	}
+	a.Version = event.EventVersion()
+	class.Pupils = fp.Remove(class.Pupils, func(id string) bool { return id == eventData.PupilID })
+	class.NumberOfPupils = len(class.Pupils)
+	class.UpdatedAt = event.EventAt()
+	return nil
+}
+
+func (a *SchoolClassAggregate) onClassClosed(event domain.Event) error {
+	eventData := ClassClosedEvent{}
+	if err := event.GetJsonData(&eventData); err != nil {
+		return err
+	}
+	class := fp.Find(a.Classes, func(c Class) bool { return c.ID == eventData.ClassID })
+	if class == nil {
+		return ErrApplyEventClassNotFound(event.EventType(), eventData.ClassID)
+	}
+	a.Version = event.EventVersion()
+	class.Closed = true
+	class.SuccessorID = eventData.SuccessorID
+	class.UpdatedAt = event.EventAt()
+	return nil
+}
@@ -18,18 +18,13 @@ func (a *SchoolClassAggregate) CreateClass(grade int, letter string, numberOfPup
 	if len(letter) > 1 {
 		return "", ErrLetterToLong
 	}
-	if numberOfPupils < 1 {
-		return "", ErrNumberOfPupilsGreaterZero
+	if numberOfPupils < 0 {
+		return "", ErrNumberOfPupilsNegative
 	}
 	if dateTo.Sub(dateFrom) < 0 {
 		return "", ErrInvalidDates
 	}
-	if fp.Some(a.Classes, func(c Class) bool {
-		return c.Grade == grade &&
-			c.Letter == letter &&
-			c.DateFrom.Year() == dateFrom.Year() &&
-			c.DateTo.Year() == dateTo.Year()
-	}) {
+	if a.classOfYear(grade, letter, dateFrom, dateTo) != nil {
 		return "", ErrClassAlreadyExists(grade, letter, dateFrom.Year(), dateTo.Year())
 	}
 	classID := uuid.NewString()
@@ -43,6 +38,11 @@ func (a *SchoolClassAggregate) CreateClass(grade int, letter string, numberOfPup
 	return classID, nil
 }
 
+// IncreaseNumberOfPupils changes the number of pupils of a class whose pupils
+// are not enrolled.
+//
+// Deprecated: enrol the pupils instead, the number of pupils is then derived
+// from the members of the class.
 func (a *SchoolClassAggregate) IncreaseNumberOfPupils(classID string, number int, reason string) error {
 	if number < 1 {
 		return ErrIncreasePupilsGreaterZero
@@ -54,6 +54,9 @@ func (a *SchoolClassAggregate) IncreaseNumberOfPupils(classID string, number int
 	if class == nil {
 		return ErrClassWithIDNotFound(classID)
 	}
+	if len(class.Pupils) > 0 {
+		return ErrPupilsManagedByMembership(classID)
+	}
 	event, err := NewNumberOfPupilsIncreased(a, classID, number, reason)
 	if err != nil {
 		return err
@@ -64,6 +67,11 @@ func (a *SchoolClassAggregate) IncreaseNumberOfPupils(classID string, number int
 	return nil
 }
 
+// DecreaseNumberOfPupils changes the number of pupils of a class whose pupils
+// are not enrolled.
+//
+// Deprecated: let the pupils leave or move instead, the number of pupils is
+// then derived from the members of the class.
 func (a *SchoolClassAggregate) DecreaseNumberOfPupils(classID string, number int, reason string) error {
 	if number < 1 {
 		return ErrDecreasePupilsGreaterZero
@@ -75,6 +83,9 @@ func (a *SchoolClassAggregate) DecreaseNumberOfPupils(classID string, number int
 	if class == nil {
 		return ErrClassWithIDNotFound(classID)
 	}
+	if len(class.Pupils) > 0 {
+		return ErrPupilsManagedByMembership(classID)
+	}
 	event, err := NewNumberOfPupilsDecreased(a, classID, number, reason)
 	if err != nil {
 		return err
@@ -84,3 +95,208 @@ func (a *SchoolClassAggregate) DecreaseNumberOfPupils(classID string, number int
 	}
 	return nil
 }
+
+func (a *SchoolClassAggregate) ReceiveBooks(classID, storageID string, book ClassBook) error {
+	class := fp.Find(a.Classes, func(c Class) bool { return c.ID == classID })
+	if class == nil {
+		return ErrClassWithIDNotFound(classID)
+	}
+	if class.Closed {
+		return ErrClassClosed(classID)
+	}
+	if storageID == "" {
+		return ErrStorageIDNotSet
+	}
+	if book.BookID == "" {
+		return ErrBookIDNotSet
+	}
+	if book.Quantity < 1 {
+		return ErrQuantityGreaterZero
+	}
+	event, err := NewBooksReceived(a, classID, storageID, book)
+	if err != nil {
+		return err
+	}
+	return a.Apply(event)
+}
+
+// ReturnBooks hands copies of a book back to a storage and returns the returned
+// books.
+func (a *SchoolClassAggregate) ReturnBooks(classID, storageID, bookID string, quantity int) (ClassBook, error) {
+	class := fp.Find(a.Classes, func(c Class) bool { return c.ID == classID })
+	if class == nil {
+		return ClassBook{}, ErrClassWithIDNotFound(classID)
+	}
+	if storageID == "" {
+		return ClassBook{}, ErrStorageIDNotSet
+	}
+	if bookID == "" {
+		return ClassBook{}, ErrBookIDNotSet
+	}
+	if quantity < 1 {
+		return ClassBook{}, ErrQuantityGreaterZero
+	}
+	book := fp.Find(class.Books, func(b ClassBook) bool { return b.BookID == bookID })
+	if book == nil || book.Quantity < quantity {
+		return ClassBook{}, ErrNotEnoughBooksInClass(bookID, class.Quantity(bookID), quantity)
+	}
+	returned := ClassBook{book.BookID, book.Isbn, book.Title, quantity}
+	event, err := NewBooksReturned(a, classID, storageID, bookID, quantity)
+	if err != nil {
+		return ClassBook{}, err
+	}
+	if err := a.Apply(event); err != nil {
+		return ClassBook{}, err
+	}
+	return returned, nil
+}
+
+func (a *SchoolClassAggregate) AddPupil(classID, pupilID string) error {
+	if pupilID == "" {
+		return ErrPupilIDNotSet
+	}
+	class := fp.Find(a.Classes, func(c Class) bool { return c.ID == classID })
+	if class == nil {
+		return ErrClassWithIDNotFound(classID)
+	}
+	if class.Closed {
+		return ErrClassClosed(classID)
+	}
+	if class.HasPupil(pupilID) {
+		return ErrPupilAlreadyInClass(pupilID, classID)
+	}
+	event, err := NewPupilAdded(a, classID, pupilID)
+	if err != nil {
+		return err
+	}
+	return a.Apply(event)
+}
+
+func (a *SchoolClassAggregate) RemovePupil(classID, pupilID string) error {
+	class := fp.Find(a.Classes, func(c Class) bool { return c.ID == classID })
+	if class == nil {
+		return ErrClassWithIDNotFound(classID)
+	}
+	if !class.HasPupil(pupilID) {
+		return ErrPupilNotInClass(pupilID, classID)
+	}
+	event, err := NewPupilRemoved(a, classID, pupilID)
+	if err != nil {
+		return err
+	}
+	return a.Apply(event)
+}
+
+// Rollover closes every open class whose school year ended before the given
+// date. Classes below the final grade get a successor with the next grade for
+// the following school year that takes over their pupils, the pupils of the
+// final grade graduate. For every letter in firstGradeLetters a new first
+// grade class is created. Without letters the letters of the closed first
+// grade classes are used again. Classes of the following school year that
+// already exist are not created again, the pupils join the existing class.
+func (a *SchoolClassAggregate) Rollover(at time.Time, finalGrade int, firstGradeLetters []string) (RolloverReport, error) {
+	report := RolloverReport{ClosedClasses: []ClosedClass{}, CreatedClasses: []CreatedClass{}}
+	if finalGrade < 1 {
+		return report, ErrFinalGradeGreaterZero
+	}
+	ending := fp.Filter(a.Classes, func(c Class) bool { return !c.Closed && c.DateTo.Before(at) })
+	if len(ending) == 0 {
+		return report, nil
+	}
+	for _, class := range ending {
+		if quantity := booksHeld(class); quantity > 0 {
+			return report, ErrClassHoldsBooks(class.ID, quantity)
+		}
+	}
+	// New first grade classes start in the school year after the latest one
+	// that ended, classes that were left open for longer do not move it back.
+	latest := ending[0]
+	for _, class := range ending {
+		if class.DateTo.After(latest.DateTo) {
+			latest = class
+		}
+	}
+	dateFrom := latest.DateFrom.AddDate(1, 0, 0)
+	dateTo := latest.DateTo.AddDate(1, 0, 0)
+	if firstGradeLetters == nil {
+		for _, class := range ending {
+			if class.Grade == 1 {
+				firstGradeLetters = append(firstGradeLetters, class.Letter)
+			}
+		}
+	}
+	for _, letter := range firstGradeLetters {
+		if a.classOfYear(1, letter, dateFrom, dateTo) != nil {
+			continue
+		}
+		classID, err := a.CreateClass(1, letter, 0, dateFrom, dateTo)
+		if err != nil {
+			return report, err
+		}
+		report.CreatedClasses = append(report.CreatedClasses, CreatedClass{classID, 1, letter, dateFrom, dateTo, []string{}})
+	}
+	for _, class := range ending {
+		pupils := append([]string{}, class.Pupils...)
+		closed := ClosedClass{class.ID, class.Grade, class.Letter, class.DateTo, pupils, class.Grade >= finalGrade, ""}
+		if !closed.Graduated {
+			from, to := class.DateFrom.AddDate(1, 0, 0), class.DateTo.AddDate(1, 0, 0)
+			if successor := a.classOfYear(class.Grade+1, class.Letter, from, to); successor != nil {
+				closed.SuccessorID = successor.ID
+			} else {
+				// Without enrolled pupils the successor takes over the number of pupils,
+				// otherwise it is derived from the pupils added below.
+				numberOfPupils := 0
+				if len(pupils) == 0 {
+					numberOfPupils = class.NumberOfPupils
+				}
+				successorID, err := a.CreateClass(class.Grade+1, class.Letter, numberOfPupils, from, to)
+				if err != nil {
+					return report, err
+				}
+				closed.SuccessorID = successorID
+				report.CreatedClasses = append(report.CreatedClasses, CreatedClass{successorID, class.Grade + 1, class.Letter, from, to, pupils})
+			}
+		}
+		for _, pupilID := range pupils {
+			if err := a.RemovePupil(class.ID, pupilID); err != nil {
+				return report, err
+			}
+			if closed.Graduated {
+				continue
+			}
+			successor := fp.Find(a.Classes, func(c Class) bool { return c.ID == closed.SuccessorID })
+			if successor.HasPupil(pupilID) {
+				continue
+			}
+			if err := a.AddPupil(closed.SuccessorID, pupilID); err != nil {
+				return report, err
+			}
+		}
+		event, err := NewClassClosed(a, class.ID, closed.SuccessorID)
+		if err != nil {
+			return report, err
+		}
+		if err := a.Apply(event); err != nil {
+			return report, err
+		}
+		report.ClosedClasses = append(report.ClosedClasses, closed)
+	}
+	return report, nil
+}
+
+func (a *SchoolClassAggregate) classOfYear(grade int, letter string, dateFrom, dateTo time.Time) *Class {
+	return fp.Find(a.Classes, func(c Class) bool {
+		return c.Grade == grade &&
+			c.Letter == letter &&
+			c.DateFrom.Year() == dateFrom.Year() &&
+			c.DateTo.Year() == dateTo.Year()
+	})
+}
+
+func booksHeld(class Class) int {
+	quantity := 0
+	for _, book := range class.Books {
+		quantity += book.Quantity
+	}
+	return quantity
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/kammeph/school-book-storage-service/domain"
 	"github.com/kammeph/school-book-storage-service/domain/classdomain"
+	"github.com/kammeph/school-book-storage-service/fp"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -72,14 +73,25 @@ func TestAddSchool(t *testing.T) {
 			expectError: true,
 		},
 		{
-			name:        "create class pupils less than one",
+			name:        "create class without pupils",
 			classes:     []classdomain.Class{},
 			grade:       4,
 			letter:      "A",
 			number:      0,
 			dateFrom:    time.Date(2022, 8, 1, 0, 0, 0, 0, time.UTC),
 			dateTo:      time.Date(2023, 8, 1, 0, 0, 0, 0, time.UTC),
-			err:         classdomain.ErrNumberOfPupilsGreaterZero,
+			err:         nil,
+			expectError: false,
+		},
+		{
+			name:        "create class negative number of pupils",
+			classes:     []classdomain.Class{},
+			grade:       4,
+			letter:      "A",
+			number:      -1,
+			dateFrom:    time.Date(2022, 8, 1, 0, 0, 0, 0, time.UTC),
+			dateTo:      time.Date(2023, 8, 1, 0, 0, 0, 0, time.UTC),
+			err:         classdomain.ErrNumberOfPupilsNegative,
 			expectError: true,
 		},
 		{
@@ -185,6 +197,15 @@ func TestIncreaseNumberOfPupils(t *testing.T) {
 			err:         classdomain.ErrClassWithIDNotFound("4A"),
 			expectError: true,
 		},
+		{
+			name:        "increase number of pupils of class with enrolled pupils",
+			classes:     []classdomain.Class{{ID: "4A", NumberOfPupils: 1, Pupils: []string{"pupil"}}},
+			classID:     "4A",
+			number:      16,
+			reason:      "test",
+			err:         classdomain.ErrPupilsManagedByMembership("4A"),
+			expectError: true,
+		},
 	}
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
@@ -273,3 +294,380 @@ func TestDecreaseNumberOfPupils(t *testing.T) {
 		})
 	}
 }
+
+func TestReceiveBooks(t *testing.T) {
+	tests := []struct {
+		name        string
+		books       []classdomain.ClassBook
+		classID     string
+		storageID   string
+		book        classdomain.ClassBook
+		expected    int
+		err         error
+		expectError bool
+	}{
+		{
+			name:        "receive books",
+			books:       []classdomain.ClassBook{},
+			classID:     "class",
+			storageID:   "storage",
+			book:        classdomain.ClassBook{BookID: "book", Title: "title", Quantity: 25},
+			expected:    25,
+			err:         nil,
+			expectError: false,
+		},
+		{
+			name:        "receive more copies",
+			books:       []classdomain.ClassBook{{BookID: "book", Quantity: 20}},
+			classID:     "class",
+			storageID:   "storage",
+			book:        classdomain.ClassBook{BookID: "book", Quantity: 5},
+			expected:    25,
+			err:         nil,
+			expectError: false,
+		},
+		{
+			name:        "receive books for not existing class",
+			books:       []classdomain.ClassBook{},
+			classID:     "unknown",
+			storageID:   "storage",
+			book:        classdomain.ClassBook{BookID: "book", Quantity: 5},
+			err:         classdomain.ErrClassWithIDNotFound("unknown"),
+			expectError: true,
+		},
+		{
+			name:        "receive books without storage",
+			books:       []classdomain.ClassBook{},
+			classID:     "class",
+			storageID:   "",
+			book:        classdomain.ClassBook{BookID: "book", Quantity: 5},
+			err:         classdomain.ErrStorageIDNotSet,
+			expectError: true,
+		},
+		{
+			name:        "receive no books",
+			books:       []classdomain.ClassBook{},
+			classID:     "class",
+			storageID:   "storage",
+			book:        classdomain.ClassBook{BookID: "book", Quantity: 0},
+			err:         classdomain.ErrQuantityGreaterZero,
+			expectError: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			aggregate := initSchoolClassAggregate([]classdomain.Class{{ID: "class", Books: test.books}})
+			err := aggregate.ReceiveBooks(test.classID, test.storageID, test.book)
+			if test.expectError {
+				assert.Error(t, err)
+				assert.Equal(t, test.err, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, classdomain.BooksReceived, aggregate.DomainEvents()[0].EventType())
+			assert.Equal(t, test.expected, aggregate.Classes[0].Quantity("book"))
+		})
+	}
+}
+
+func TestReturnBooks(t *testing.T) {
+	tests := []struct {
+		name        string
+		books       []classdomain.ClassBook
+		quantity    int
+		expected    int
+		err         error
+		expectError bool
+	}{
+		{
+			name:        "return some books",
+			books:       []classdomain.ClassBook{{BookID: "book", Title: "title", Quantity: 25}},
+			quantity:    5,
+			expected:    20,
+			err:         nil,
+			expectError: false,
+		},
+		{
+			name:        "return all books",
+			books:       []classdomain.ClassBook{{BookID: "book", Title: "title", Quantity: 25}},
+			quantity:    25,
+			expected:    0,
+			err:         nil,
+			expectError: false,
+		},
+		{
+			name:        "return more books than received",
+			books:       []classdomain.ClassBook{{BookID: "book", Title: "title", Quantity: 25}},
+			quantity:    26,
+			err:         classdomain.ErrNotEnoughBooksInClass("book", 25, 26),
+			expectError: true,
+		},
+		{
+			name:        "return books the class does not hold",
+			books:       []classdomain.ClassBook{},
+			quantity:    1,
+			err:         classdomain.ErrNotEnoughBooksInClass("book", 0, 1),
+			expectError: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			aggregate := initSchoolClassAggregate([]classdomain.Class{{ID: "class", Books: test.books}})
+			returned, err := aggregate.ReturnBooks("class", "storage", "book", test.quantity)
+			if test.expectError {
+				assert.Error(t, err)
+				assert.Equal(t, test.err, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, classdomain.ClassBook{BookID: "book", Title: "title", Quantity: test.quantity}, returned)
+			assert.Equal(t, classdomain.BooksReturned, aggregate.DomainEvents()[0].EventType())
+			assert.Equal(t, test.expected, aggregate.Classes[0].Quantity("book"))
+		})
+	}
+}
+
+func TestAddPupil(t *testing.T) {
+	tests := []struct {
+		name        string
+		pupils      []string
+		classID     string
+		pupilID     string
+		err         error
+		expectError bool
+	}{
+		{
+			name:        "add pupil",
+			pupils:      []string{"pupil1"},
+			classID:     "class",
+			pupilID:     "pupil2",
+			err:         nil,
+			expectError: false,
+		},
+		{
+			name:        "pupil not set",
+			pupils:      []string{},
+			classID:     "class",
+			err:         classdomain.ErrPupilIDNotSet,
+			expectError: true,
+		},
+		{
+			name:        "class not found",
+			pupils:      []string{},
+			classID:     "unknown",
+			pupilID:     "pupil1",
+			err:         classdomain.ErrClassWithIDNotFound("unknown"),
+			expectError: true,
+		},
+		{
+			name:        "pupil already in class",
+			pupils:      []string{"pupil1"},
+			classID:     "class",
+			pupilID:     "pupil1",
+			err:         classdomain.ErrPupilAlreadyInClass("pupil1", "class"),
+			expectError: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			aggregate := initSchoolClassAggregate([]classdomain.Class{{ID: "class", NumberOfPupils: 20, Pupils: test.pupils}})
+			err := aggregate.AddPupil(test.classID, test.pupilID)
+			if test.expectError {
+				assert.Error(t, err)
+				assert.Equal(t, test.err, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, classdomain.PupilAdded, aggregate.DomainEvents()[0].EventType())
+			assert.Equal(t, len(test.pupils)+1, aggregate.Classes[0].NumberOfPupils)
+			assert.True(t, aggregate.Classes[0].HasPupil(test.pupilID))
+		})
+	}
+}
+
+func TestRemovePupil(t *testing.T) {
+	tests := []struct {
+		name        string
+		classID     string
+		pupilID     string
+		err         error
+		expectError bool
+	}{
+		{
+			name:        "remove pupil",
+			classID:     "class",
+			pupilID:     "pupil1",
+			err:         nil,
+			expectError: false,
+		},
+		{
+			name:        "class not found",
+			classID:     "unknown",
+			pupilID:     "pupil1",
+			err:         classdomain.ErrClassWithIDNotFound("unknown"),
+			expectError: true,
+		},
+		{
+			name:        "pupil not in class",
+			classID:     "class",
+			pupilID:     "pupil3",
+			err:         classdomain.ErrPupilNotInClass("pupil3", "class"),
+			expectError: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			aggregate := initSchoolClassAggregate([]classdomain.Class{{ID: "class", NumberOfPupils: 2, Pupils: []string{"pupil1", "pupil2"}}})
+			err := aggregate.RemovePupil(test.classID, test.pupilID)
+			if test.expectError {
+				assert.Error(t, err)
+				assert.Equal(t, test.err, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, classdomain.PupilRemoved, aggregate.DomainEvents()[0].EventType())
+			assert.Equal(t, 1, aggregate.Classes[0].NumberOfPupils)
+			assert.False(t, aggregate.Classes[0].HasPupil(test.pupilID))
+		})
+	}
+}
+
+func TestRollover(t *testing.T) {
+	from := time.Date(2022, 8, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2023, 7, 31, 0, 0, 0, 0, time.UTC)
+	at := time.Date(2023, 8, 1, 0, 0, 0, 0, time.UTC)
+	classes := func() []classdomain.Class {
+		return []classdomain.Class{
+			{ID: "1a", Grade: 1, Letter: "a", DateFrom: from, DateTo: to, Pupils: []string{"pupil1"}},
+			{ID: "4a", Grade: 4, Letter: "a", DateFrom: from, DateTo: to, Pupils: []string{"pupil2", "pupil3"}},
+			{ID: "closed", Grade: 3, Letter: "a", DateFrom: from, DateTo: to, Closed: true},
+			{ID: "next", Grade: 2, Letter: "b", DateFrom: from.AddDate(1, 0, 0), DateTo: to.AddDate(1, 0, 0)},
+		}
+	}
+	tests := []struct {
+		name              string
+		classes           []classdomain.Class
+		at                time.Time
+		finalGrade        int
+		firstGradeLetters []string
+		closedClasses     int
+		createdClasses    int
+		err               error
+		expectError       bool
+	}{
+		{
+			name:           "rollover",
+			classes:        classes(),
+			at:             at,
+			finalGrade:     4,
+			closedClasses:  2,
+			createdClasses: 2,
+		},
+		{
+			name:              "rollover with first grade letters",
+			classes:           classes(),
+			at:                at,
+			finalGrade:        4,
+			firstGradeLetters: []string{"a", "b", "c"},
+			closedClasses:     2,
+			createdClasses:    4,
+		},
+		{
+			name: "rollover into existing successor",
+			classes: append(classes(),
+				classdomain.Class{ID: "2a", Grade: 2, Letter: "a", DateFrom: from.AddDate(1, 0, 0), DateTo: to.AddDate(1, 0, 0)},
+				classdomain.Class{ID: "1a-next", Grade: 1, Letter: "a", DateFrom: from.AddDate(1, 0, 0), DateTo: to.AddDate(1, 0, 0)},
+			),
+			at:            at,
+			finalGrade:    4,
+			closedClasses: 2,
+		},
+		{
+			name:       "school year not ended",
+			classes:    classes(),
+			at:         to.AddDate(0, 0, -1),
+			finalGrade: 4,
+		},
+		{
+			name:        "final grade not set",
+			classes:     classes(),
+			at:          at,
+			err:         classdomain.ErrFinalGradeGreaterZero,
+			expectError: true,
+		},
+		{
+			name: "class holds books",
+			classes: []classdomain.Class{
+				{ID: "1a", Grade: 1, Letter: "a", DateFrom: from, DateTo: to, Books: []classdomain.ClassBook{{BookID: "book", Quantity: 2}}},
+			},
+			at:          at,
+			finalGrade:  4,
+			err:         classdomain.ErrClassHoldsBooks("1a", 2),
+			expectError: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			aggregate := initSchoolClassAggregate(test.classes)
+			report, err := aggregate.Rollover(test.at, test.finalGrade, test.firstGradeLetters)
+			if test.expectError {
+				assert.Error(t, err)
+				assert.Equal(t, test.err, err)
+				assert.Empty(t, aggregate.DomainEvents())
+				return
+			}
+			assert.NoError(t, err)
+			assert.Len(t, report.ClosedClasses, test.closedClasses)
+			assert.Len(t, report.CreatedClasses, test.createdClasses)
+			for _, closed := range report.ClosedClasses {
+				class := fp.Find(aggregate.Classes, func(c classdomain.Class) bool { return c.ID == closed.ClassID })
+				assert.True(t, class.Closed)
+				assert.Empty(t, class.Pupils)
+				if closed.Graduated {
+					assert.Empty(t, closed.SuccessorID)
+					continue
+				}
+				successor := fp.Find(aggregate.Classes, func(c classdomain.Class) bool { return c.ID == closed.SuccessorID })
+				assert.Equal(t, closed.Grade+1, successor.Grade)
+				assert.Equal(t, closed.Letter, successor.Letter)
+				assert.Equal(t, closed.Pupils, successor.Pupils)
+				assert.Equal(t, to.AddDate(1, 0, 0), successor.DateTo)
+			}
+		})
+	}
+}
+
+func TestRolloverTakesOverNumberOfPupils(t *testing.T) {
+	from := time.Date(2022, 8, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2023, 7, 31, 0, 0, 0, 0, time.UTC)
+	aggregate := initSchoolClassAggregate([]classdomain.Class{
+		{ID: "1a", Grade: 1, Letter: "a", NumberOfPupils: 24, DateFrom: from, DateTo: to},
+		{ID: "1b", Grade: 1, Letter: "b", NumberOfPupils: 24, DateFrom: from, DateTo: to, Pupils: []string{"pupil1"}},
+	})
+	report, err := aggregate.Rollover(time.Date(2023, 8, 1, 0, 0, 0, 0, time.UTC), 4, []string{})
+	assert.NoError(t, err)
+	assert.Len(t, report.CreatedClasses, 2)
+	for _, closed := range report.ClosedClasses {
+		successor := fp.Find(aggregate.Classes, func(c classdomain.Class) bool { return c.ID == closed.SuccessorID })
+		if closed.ClassID == "1a" {
+			assert.Equal(t, 24, successor.NumberOfPupils)
+		} else {
+			assert.Equal(t, 1, successor.NumberOfPupils)
+		}
+	}
+}
+
+func TestRolloverCreatesFirstGradeAfterLatestSchoolYear(t *testing.T) {
+	from := time.Date(2022, 8, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2023, 7, 31, 0, 0, 0, 0, time.UTC)
+	aggregate := initSchoolClassAggregate([]classdomain.Class{
+		{ID: "old", Grade: 2, Letter: "b", DateFrom: from.AddDate(-1, 0, 0), DateTo: to.AddDate(-1, 0, 0)},
+		{ID: "1a", Grade: 1, Letter: "a", DateFrom: from, DateTo: to},
+	})
+	report, err := aggregate.Rollover(time.Date(2023, 8, 1, 0, 0, 0, 0, time.UTC), 4, []string{"a"})
+	assert.NoError(t, err)
+	firstGrade := fp.Find(report.CreatedClasses, func(c classdomain.CreatedClass) bool { return c.Grade == 1 })
+	assert.NotNil(t, firstGrade)
+	assert.Equal(t, from.AddDate(1, 0, 0), firstGrade.DateFrom)
+	assert.Equal(t, to.AddDate(1, 0, 0), firstGrade.DateTo)
+}
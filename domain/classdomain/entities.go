@@ -1,6 +1,17 @@
 package classdomain
 
-import "time"
+import (
+	"time"
+
+	"github.com/kammeph/school-book-storage-service/domain"
+)
+
+type ClassBook struct {
+	BookID   string
+	Isbn     domain.Isbn
+	Title    string
+	Quantity int
+}
 
 type Class struct {
 	ID             string
@@ -9,6 +20,10 @@ type Class struct {
 	NumberOfPupils int
 	DateFrom       time.Time
 	DateTo         time.Time
+	Books          []ClassBook
+	Pupils         []string
+	Closed         bool
+	SuccessorID    string
 	CreatedAt      time.Time
 	UpdatedAt      time.Time
 }
@@ -21,6 +36,54 @@ func NewClass(id string, grade int, letter string, pupils int, from, to, timeSta
 		NumberOfPupils: pupils,
 		DateFrom:       from,
 		DateTo:         to,
+		Books:          []ClassBook{},
+		Pupils:         []string{},
 		CreatedAt:      timeStamp,
 	}
 }
+
+func (c Class) HasPupil(pupilID string) bool {
+	for _, id := range c.Pupils {
+		if id == pupilID {
+			return true
+		}
+	}
+	return false
+}
+
+// Quantity returns how many copies of the book the class holds.
+func (c Class) Quantity(bookID string) int {
+	for _, book := range c.Books {
+		if book.BookID == bookID {
+			return book.Quantity
+		}
+	}
+	return 0
+}
+
+// ClosedClass is a class closed by the school year rollover. Pupils of a
+// graduating class leave the school, all others move to the successor.
+type ClosedClass struct {
+	ClassID     string    `json:"classId"`
+	Grade       int       `json:"grade"`
+	Letter      string    `json:"letter"`
+	DateTo      time.Time `json:"dateTo"`
+	Pupils      []string  `json:"pupils"`
+	Graduated   bool      `json:"graduated"`
+	SuccessorID string    `json:"successorId"`
+}
+
+// CreatedClass is a class created by the school year rollover.
+type CreatedClass struct {
+	ClassID  string    `json:"classId"`
+	Grade    int       `json:"grade"`
+	Letter   string    `json:"letter"`
+	DateFrom time.Time `json:"dateFrom"`
+	DateTo   time.Time `json:"dateTo"`
+	Pupils   []string  `json:"pupils"`
+}
+
+type RolloverReport struct {
+	ClosedClasses  []ClosedClass  `json:"closedClasses"`
+	CreatedClasses []CreatedClass `json:"createdClasses"`
+}
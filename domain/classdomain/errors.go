@@ -9,10 +9,15 @@ var (
 	ErrGradeGreaterZero          = errors.New("the grade must be greater than zero")
 	ErrLetterNotSet              = errors.New("letter is not set")
 	ErrLetterToLong              = errors.New("the class letter should only have on place")
-	ErrNumberOfPupilsGreaterZero = errors.New("the number of pupils must be greater than zero")
+	ErrNumberOfPupilsNegative    = errors.New("the number of pupils must not be negative")
 	ErrInvalidDates              = errors.New("the end date must be greater the the start date")
 	ErrIncreasePupilsGreaterZero = errors.New("the number of pupils should at minimum increased by one")
 	ErrDecreasePupilsGreaterZero = errors.New("the number of pupils should at minimum decreased by one")
+	ErrBookIDNotSet              = errors.New("book ID not set")
+	ErrStorageIDNotSet           = errors.New("storage ID not set")
+	ErrQuantityGreaterZero       = errors.New("the quantity must be greater than zero")
+	ErrPupilIDNotSet             = errors.New("pupil ID not set")
+	ErrFinalGradeGreaterZero     = errors.New("the final grade must be greater than zero")
 )
 
 func ErrApplyEventClassAlreadyExists(eventType, classID string) error {
@@ -30,3 +35,27 @@ func ErrClassAlreadyExists(grade int, letter string, yearFrom, yearTo int) error
 func ErrClassWithIDNotFound(id string) error {
 	return fmt.Errorf("class with ID %s not found", id)
 }
+
+func ErrNotEnoughBooksInClass(bookID string, available, requested int) error {
+	return fmt.Errorf("can not return %d copies of book %s, the class only holds %d", requested, bookID, available)
+}
+
+func ErrPupilAlreadyInClass(pupilID, classID string) error {
+	return fmt.Errorf("pupil %s is already in class %s", pupilID, classID)
+}
+
+func ErrPupilNotInClass(pupilID, classID string) error {
+	return fmt.Errorf("pupil %s is not in class %s", pupilID, classID)
+}
+
+func ErrPupilsManagedByMembership(classID string) error {
+	return fmt.Errorf("the number of pupils of class %s is derived from its pupils and can not be changed manually", classID)
+}
+
+func ErrClassClosed(classID string) error {
+	return fmt.Errorf("class %s is closed", classID)
+}
+
+func ErrClassHoldsBooks(classID string, quantity int) error {
+	return fmt.Errorf("class %s can not be closed, it still holds %d books", classID, quantity)
+}
@@ -10,6 +10,11 @@ var (
 	ClassCreated            = "CLASS_CREATED"
 	NumberOfPupilsIncreased = "NUMBER_OF_PUPILS_INCREASED"
 	NumberOfPupilsDecreased = "NUMBER_OF_PUPILS_DECREASED"
+	BooksReceived           = "CLASS_BOOKS_RECEIVED"
+	BooksReturned           = "CLASS_BOOKS_RETURNED"
+	PupilAdded              = "CLASS_PUPIL_ADDED"
+	PupilRemoved            = "CLASS_PUPIL_REMOVED"
+	ClassClosed             = "CLASS_CLOSED"
 )
 
 type ClassCreatedEvent struct {
@@ -30,12 +35,13 @@ func NewClassCreated(
 	numberOfPupils int,
 	dateFrom, dateTo time.Time) (domain.Event, error) {
 	eventData := ClassCreatedEvent{
-		SchoolID: aggregate.AggregateID(),
-		ClassID:  classID,
-		Grade:    grade,
-		Letter:   letter,
-		DateFrom: dateFrom,
-		DateTo:   dateTo,
+		SchoolID:       aggregate.AggregateID(),
+		ClassID:        classID,
+		Grade:          grade,
+		Letter:         letter,
+		NumberOfPupils: numberOfPupils,
+		DateFrom:       dateFrom,
+		DateTo:         dateTo,
 	}
 	event := domain.NewEvent(aggregate, ClassCreated)
 	if err := event.SetJsonData(eventData); err != nil {
@@ -81,3 +87,100 @@ func NewNumberOfPupilsDecreased(aggregate *SchoolClassAggregate, classID string,
 	}
 	return event, nil
 }
+
+type BooksReceivedEvent struct {
+	ClassID   string      `json:"classId"`
+	StorageID string      `json:"storageId"`
+	BookID    string      `json:"bookId"`
+	Isbn      domain.Isbn `json:"isbn"`
+	Title     string      `json:"title"`
+	Quantity  int         `json:"quantity"`
+}
+
+func NewBooksReceived(aggregate *SchoolClassAggregate, classID, storageID string, book ClassBook) (domain.Event, error) {
+	eventData := BooksReceivedEvent{
+		ClassID:   classID,
+		StorageID: storageID,
+		BookID:    book.BookID,
+		Isbn:      book.Isbn,
+		Title:     book.Title,
+		Quantity:  book.Quantity,
+	}
+	event := domain.NewEvent(aggregate, BooksReceived)
+	if err := event.SetJsonData(eventData); err != nil {
+		return nil, err
+	}
+	return event, nil
+}
+
+type BooksReturnedEvent struct {
+	ClassID   string `json:"classId"`
+	StorageID string `json:"storageId"`
+	BookID    string `json:"bookId"`
+	Quantity  int    `json:"quantity"`
+}
+
+func NewBooksReturned(aggregate *SchoolClassAggregate, classID, storageID, bookID string, quantity int) (domain.Event, error) {
+	eventData := BooksReturnedEvent{
+		ClassID:   classID,
+		StorageID: storageID,
+		BookID:    bookID,
+		Quantity:  quantity,
+	}
+	event := domain.NewEvent(aggregate, BooksReturned)
+	if err := event.SetJsonData(eventData); err != nil {
+		return nil, err
+	}
+	return event, nil
+}
+
+type PupilAddedEvent struct {
+	ClassID string `json:"classId"`
+	PupilID string `json:"pupilId"`
+}
+
+func NewPupilAdded(aggregate *SchoolClassAggregate, classID, pupilID string) (domain.Event, error) {
+	eventData := PupilAddedEvent{
+		ClassID: classID,
+		PupilID: pupilID,
+	}
+	event := domain.NewEvent(aggregate, PupilAdded)
+	if err := event.SetJsonData(eventData); err != nil {
+		return nil, err
+	}
+	return event, nil
+}
+
+type PupilRemovedEvent struct {
+	ClassID string `json:"classId"`
+	PupilID string `json:"pupilId"`
+}
+
+func NewPupilRemoved(aggregate *SchoolClassAggregate, classID, pupilID string) (domain.Event, error) {
+	eventData := PupilRemovedEvent{
+		ClassID: classID,
+		PupilID: pupilID,
+	}
+	event := domain.NewEvent(aggregate, PupilRemoved)
+	if err := event.SetJsonData(eventData); err != nil {
+		return nil, err
+	}
+	return event, nil
+}
+
+type ClassClosedEvent struct {
+	ClassID     string `json:"classId"`
+	SuccessorID string `json:"successorId"`
+}
+
+func NewClassClosed(aggregate *SchoolClassAggregate, classID, successorID string) (domain.Event, error) {
+	eventData := ClassClosedEvent{
+		ClassID:     classID,
+		SuccessorID: successorID,
+	}
+	event := domain.NewEvent(aggregate, ClassClosed)
+	if err := event.SetJsonData(eventData); err != nil {
+		return nil, err
+	}
+	return event, nil
+}
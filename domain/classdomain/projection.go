@@ -1,12 +1,16 @@
 package classdomain
 
-import "time"
+import (
+	"time"
+
+	"github.com/kammeph/school-book-storage-service/domain"
+)
 
 type BookInClass struct {
-	BookID   string `json:"bookId" bson:"bookId"`
-	Isbn     string `json:"isbn" bson:"isbn"`
-	Title    string `json:"title" bson:"title"`
-	Quantity int    `json:"quantity" bson:"quantity"`
+	BookID   string      `json:"bookId" bson:"bookId"`
+	Isbn     domain.Isbn `json:"isbn" bson:"isbn"`
+	Title    string      `json:"title" bson:"title"`
+	Quantity int         `json:"quantity" bson:"quantity"`
 }
 
 type ClassWithBooks struct {
@@ -18,8 +22,12 @@ type ClassWithBooks struct {
 	DateFrom       time.Time     `json:"dateFrom" bson:"dateFrom"`
 	DateTo         time.Time     `json:"dateTo" bson:"dateTo"`
 	Books          []BookInClass `json:"books" bson:"books"`
+	Pupils         []string      `json:"pupils" bson:"pupils"`
+	Closed         bool          `json:"closed" bson:"closed"`
+	SuccessorID    string        `json:"successorId" bson:"successorId"`
+	Version        int           `json:"version" bson:"version"`
 }
 
-func NewClassWithBooks(schoolID, classID string, grade int, letter string, numberOfPupils int, dateFrom, dateTo time.Time) ClassWithBooks {
-	return ClassWithBooks{schoolID, classID, grade, letter, numberOfPupils, dateFrom, dateTo, []BookInClass{}}
+func NewClassWithBooks(schoolID, classID string, grade int, letter string, numberOfPupils int, dateFrom, dateTo time.Time, version int) ClassWithBooks {
+	return ClassWithBooks{schoolID, classID, grade, letter, numberOfPupils, dateFrom, dateTo, []BookInClass{}, []string{}, false, "", version}
 }
@@ -16,7 +16,8 @@ func TestNewClassWithBooks(t *testing.T) {
 	pupils := 15
 	from := time.Date(2022, 8, 1, 0, 0, 0, 0, time.UTC)
 	to := time.Date(2023, 7, 31, 0, 0, 0, 0, time.UTC)
-	class := classdomain.NewClassWithBooks(schoolID, classID, grade, letter, pupils, from, to)
+	version := 3
+	class := classdomain.NewClassWithBooks(schoolID, classID, grade, letter, pupils, from, to, version)
 	assert.Equal(t, schoolID, class.SchoolID)
 	assert.Equal(t, classID, class.ClassID)
 	assert.Equal(t, grade, class.Grade)
@@ -26,4 +27,6 @@ func TestNewClassWithBooks(t *testing.T) {
 	assert.Equal(t, to, class.DateTo)
 	assert.NotNil(t, class.Books)
 	assert.Len(t, class.Books, 0)
+	assert.Empty(t, class.Pupils)
+	assert.Equal(t, version, class.Version)
 }
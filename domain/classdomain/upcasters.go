@@ -0,0 +1,8 @@
+package classdomain
+
+import "github.com/kammeph/school-book-storage-service/domain"
+
+// ISBNs used to be stored as entered by the user.
+func init() {
+	domain.RegisterUpcaster(BooksReceived, domain.IsbnUpcaster("isbn"))
+}
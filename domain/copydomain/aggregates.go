@@ -0,0 +1,133 @@
+package copydomain
+
+import (
+	"github.com/kammeph/school-book-storage-service/domain"
+	"github.com/kammeph/school-book-storage-service/fp"
+)
+
+type SchoolCopyAggregate struct {
+	*domain.AggregateModel
+	Numbering NumberingScheme
+	Copies    []Copy
+}
+
+func NewSchoolCopyAggregate() *SchoolCopyAggregate {
+	aggregate := &SchoolCopyAggregate{
+		Copies: []Copy{},
+	}
+	model := domain.NewAggregateModel(aggregate.On)
+	aggregate.AggregateModel = &model
+	return aggregate
+}
+
+func NewSchoolCopyAggregateWithID(id string) *SchoolCopyAggregate {
+	aggregate := NewSchoolCopyAggregate()
+	aggregate.ID = id
+	return aggregate
+}
+
+func (a *SchoolCopyAggregate) On(event domain.Event) error {
+	switch event.EventType() {
+	case NumberingConfigured:
+		return a.onNumberingConfigured(event)
+	case CopyRegistered:
+		return a.onCopyRegistered(event)
+	case CopyMoved:
+		return a.onCopyMoved(event)
+	case CopyGraded:
+		return a.onCopyGraded(event)
+	case CopyWrittenOff:
+		return a.onCopyWrittenOff(event)
+	default:
+		return domain.ErrUnknownEvent(event)
+	}
+}
+
+func (a *SchoolCopyAggregate) onNumberingConfigured(event domain.Event) error {
+	eventData := NumberingConfiguredEvent{}
+	if err := event.GetJsonData(&eventData); err != nil {
+		return err
+	}
+	a.Version = event.EventVersion()
+	a.Numbering = NumberingScheme{eventData.Prefix, eventData.Digits, eventData.Next}
+	return nil
+}
+
+func (a *SchoolCopyAggregate) onCopyRegistered(event domain.Event) error {
+	eventData := CopyRegisteredEvent{}
+	if err := event.GetJsonData(&eventData); err != nil {
+		return err
+	}
+	if a.Exists(eventData.InventoryNumber) {
+		return ErrApplyEventCopyAlreadyExists(event.EventType(), eventData.InventoryNumber)
+	}
+	bookCopy := NewCopy(
+		eventData.InventoryNumber,
+		eventData.BookID,
+		eventData.Isbn,
+		eventData.Title,
+		eventData.Location,
+		eventData.Condition,
+		event.EventAt())
+	if eventData.Sequence >= a.Numbering.Next {
+		a.Numbering.Next = eventData.Sequence + 1
+	}
+	a.Version = event.EventVersion()
+	a.Copies = append(a.Copies, bookCopy)
+	return nil
+}
+
+func (a *SchoolCopyAggregate) onCopyMoved(event domain.Event) error {
+	eventData := CopyMovedEvent{}
+	if err := event.GetJsonData(&eventData); err != nil {
+		return err
+	}
+	bookCopy := fp.Find(a.Copies, func(c Copy) bool { return c.InventoryNumber == eventData.InventoryNumber })
+	if bookCopy == nil {
+		return ErrApplyEventCopyNotFound(event.EventType(), eventData.InventoryNumber)
+	}
+	a.Version = event.EventVersion()
+	bookCopy.Location = eventData.To
+	switch eventData.To.Kind {
+	case InClass:
+		bookCopy.ClassID = eventData.To.ID
+	case InStorage:
+		bookCopy.ClassID = ""
+	}
+	bookCopy.UpdatedAt = event.EventAt()
+	return nil
+}
+
+func (a *SchoolCopyAggregate) onCopyGraded(event domain.Event) error {
+	eventData := CopyGradedEvent{}
+	if err := event.GetJsonData(&eventData); err != nil {
+		return err
+	}
+	bookCopy := fp.Find(a.Copies, func(c Copy) bool { return c.InventoryNumber == eventData.InventoryNumber })
+	if bookCopy == nil {
+		return ErrApplyEventCopyNotFound(event.EventType(), eventData.InventoryNumber)
+	}
+	a.Version = event.EventVersion()
+	bookCopy.Condition = eventData.Condition
+	bookCopy.UpdatedAt = event.EventAt()
+	return nil
+}
+
+func (a *SchoolCopyAggregate) onCopyWrittenOff(event domain.Event) error {
+	eventData := CopyWrittenOffEvent{}
+	if err := event.GetJsonData(&eventData); err != nil {
+		return err
+	}
+	bookCopy := fp.Find(a.Copies, func(c Copy) bool { return c.InventoryNumber == eventData.InventoryNumber })
+	if bookCopy == nil {
+		return ErrApplyEventCopyNotFound(event.EventType(), eventData.InventoryNumber)
+	}
+	a.Version = event.EventVersion()
+	bookCopy.WrittenOff = true
+	bookCopy.UpdatedAt = event.EventAt()
+	return nil
+}
+
+func (a *SchoolCopyAggregate) Exists(inventoryNumber string) bool {
+	return fp.Some(a.Copies, func(c Copy) bool { return c.InventoryNumber == inventoryNumber })
+}
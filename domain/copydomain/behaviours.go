@@ -0,0 +1,201 @@
+package copydomain
+
+import (
+	"github.com/kammeph/school-book-storage-service/domain"
+	"github.com/kammeph/school-book-storage-service/domain/storagedomain"
+	"github.com/kammeph/school-book-storage-service/fp"
+)
+
+// ConfigureNumbering sets the numbering scheme of the school and thereby
+// enables tracking single copies.
+func (a *SchoolCopyAggregate) ConfigureNumbering(scheme NumberingScheme) error {
+	if !scheme.Valid() {
+		return ErrInvalidNumberingScheme
+	}
+	event, err := NewNumberingConfigured(a, scheme)
+	if err != nil {
+		return err
+	}
+	return a.Apply(event)
+}
+
+// RegisterCopies registers new copies of a book in a storage. Copies that are
+// already stamped keep their inventory numbers, otherwise quantity numbers are
+// generated from the numbering scheme. Copies without a condition are in good
+// condition.
+func (a *SchoolCopyAggregate) RegisterCopies(
+	bookID string,
+	isbn domain.Isbn,
+	title, storageID string,
+	condition storagedomain.Condition,
+	inventoryNumbers []string,
+	quantity int,
+) ([]string, error) {
+	if !a.Numbering.Enabled() {
+		return nil, ErrCopyTrackingNotEnabled
+	}
+	if bookID == "" {
+		return nil, ErrBookIDNotSet
+	}
+	if storageID == "" {
+		return nil, ErrStorageIDNotSet
+	}
+	if condition == "" {
+		condition = storagedomain.Good
+	}
+	if !condition.Valid() || condition == storagedomain.Lost {
+		return nil, ErrInvalidCondition(condition)
+	}
+	sequences := []int{}
+	if len(inventoryNumbers) > 0 {
+		for idx, inventoryNumber := range inventoryNumbers {
+			if inventoryNumber == "" {
+				return nil, ErrInventoryNumberNotSet
+			}
+			if a.Exists(inventoryNumber) || fp.Some(inventoryNumbers[:idx], func(n string) bool { return n == inventoryNumber }) {
+				return nil, ErrInventoryNumberExists(inventoryNumber)
+			}
+			sequences = append(sequences, 0)
+		}
+	} else {
+		if quantity <= 0 {
+			return nil, ErrQuantityNotPositive
+		}
+		sequence := a.Numbering.Next
+		for len(inventoryNumbers) < quantity {
+			if sequence > a.Numbering.Max() {
+				return nil, ErrNumberingExhausted
+			}
+			if inventoryNumber := a.Numbering.Format(sequence); !a.Exists(inventoryNumber) {
+				inventoryNumbers = append(inventoryNumbers, inventoryNumber)
+				sequences = append(sequences, sequence)
+			}
+			sequence++
+		}
+	}
+	for idx, inventoryNumber := range inventoryNumbers {
+		bookCopy := Copy{
+			InventoryNumber: inventoryNumber,
+			BookID:          bookID,
+			Isbn:            isbn,
+			Title:           title,
+			Location:        StorageLocation(storageID),
+			Condition:       condition,
+		}
+		event, err := NewCopyRegistered(a, bookCopy, sequences[idx])
+		if err != nil {
+			return nil, err
+		}
+		if err := a.Apply(event); err != nil {
+			return nil, err
+		}
+	}
+	return inventoryNumbers, nil
+}
+
+// MoveCopy records that a copy was taken to another storage, handed out to a
+// class or a pupil or given back. A class hands its copies out to pupils and
+// gets them back before they return to a storage, so the stock of storages and
+// classes can follow the copies. It returns the copy as it was before.
+func (a *SchoolCopyAggregate) MoveCopy(inventoryNumber string, to Location, reason string) (Copy, error) {
+	bookCopy, err := a.activeCopy(inventoryNumber)
+	if err != nil {
+		return Copy{}, err
+	}
+	if !to.Valid() {
+		return Copy{}, ErrInvalidLocation
+	}
+	if bookCopy.Location == to {
+		return Copy{}, ErrCopyAlreadyAt(inventoryNumber, to)
+	}
+	switch bookCopy.Location.Kind {
+	case InStorage:
+		if to.Kind == WithPupil {
+			return Copy{}, ErrPupilCopyNotFromClass
+		}
+	case InClass:
+		if to.Kind == InClass {
+			return Copy{}, ErrCopyInClass(inventoryNumber, bookCopy.ClassID)
+		}
+	case WithPupil:
+		if to.Kind == InStorage || (to.Kind == InClass && to.ID != bookCopy.ClassID) {
+			return Copy{}, ErrCopyWithPupil(inventoryNumber, bookCopy.ClassID)
+		}
+	}
+	moved := *bookCopy
+	event, err := NewCopyMoved(a, moved, to, reason)
+	if err != nil {
+		return Copy{}, err
+	}
+	if err := a.Apply(event); err != nil {
+		return Copy{}, err
+	}
+	return moved, nil
+}
+
+// GradeCopy records that a copy got into a worse condition.
+func (a *SchoolCopyAggregate) GradeCopy(inventoryNumber string, condition storagedomain.Condition, reason string) error {
+	bookCopy, err := a.activeCopy(inventoryNumber)
+	if err != nil {
+		return err
+	}
+	if reason == "" {
+		return domain.ErrReasonNotSpecified
+	}
+	if !condition.Valid() {
+		return ErrInvalidCondition(condition)
+	}
+	if !condition.WorseThan(bookCopy.Condition) {
+		return ErrConditionNotWorse
+	}
+	event, err := NewCopyGraded(a, *bookCopy, condition, reason)
+	if err != nil {
+		return err
+	}
+	return a.Apply(event)
+}
+
+// WriteOffCopy removes a damaged or lost copy from the inventory for good and
+// returns it. Copies a class or its pupils hold have to be taken back to a
+// storage first, so the stock of the class stays in line with its copies.
+func (a *SchoolCopyAggregate) WriteOffCopy(inventoryNumber, reason string) (Copy, error) {
+	bookCopy, err := a.activeCopy(inventoryNumber)
+	if err != nil {
+		return Copy{}, err
+	}
+	if reason == "" {
+		return Copy{}, domain.ErrReasonNotSpecified
+	}
+	if bookCopy.Condition != storagedomain.Damaged && bookCopy.Condition != storagedomain.Lost {
+		return Copy{}, ErrWriteOffUsableCopy
+	}
+	switch bookCopy.Location.Kind {
+	case InClass:
+		return Copy{}, ErrCopyInClass(inventoryNumber, bookCopy.ClassID)
+	case WithPupil:
+		return Copy{}, ErrCopyWithPupil(inventoryNumber, bookCopy.ClassID)
+	}
+	writtenOff := *bookCopy
+	event, err := NewCopyWrittenOff(a, writtenOff, reason)
+	if err != nil {
+		return Copy{}, err
+	}
+	if err := a.Apply(event); err != nil {
+		return Copy{}, err
+	}
+	return writtenOff, nil
+}
+
+func (a *SchoolCopyAggregate) activeCopy(inventoryNumber string) (*Copy, error) {
+	if inventoryNumber == "" {
+		return nil, ErrInventoryNumberNotSet
+	}
+	bookCopy := fp.Find(a.Copies, func(c Copy) bool { return c.InventoryNumber == inventoryNumber })
+	if bookCopy == nil {
+		return nil, ErrCopyNotFound(inventoryNumber)
+	}
+	if bookCopy.WrittenOff {
+		return nil, ErrCopyWrittenOff(inventoryNumber)
+	}
+	return bookCopy, nil
+}
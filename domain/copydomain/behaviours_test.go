@@ -0,0 +1,287 @@
+package copydomain_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kammeph/school-book-storage-service/domain"
+	"github.com/kammeph/school-book-storage-service/domain/copydomain"
+	"github.com/kammeph/school-book-storage-service/domain/storagedomain"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	scheme     = copydomain.NumberingScheme{Prefix: "LMF-", Digits: 4, Next: 1}
+	registered = time.Date(2022, 8, 1, 0, 0, 0, 0, time.UTC)
+	inStorage  = copydomain.StorageLocation("storage")
+	inClass    = copydomain.Location{Kind: copydomain.InClass, ID: "class"}
+	withPupil  = copydomain.Location{Kind: copydomain.WithPupil, ID: "pupil"}
+)
+
+func initSchoolCopyAggregate(copies ...copydomain.Copy) *copydomain.SchoolCopyAggregate {
+	aggregate := copydomain.NewSchoolCopyAggregateWithID("school")
+	aggregate.Numbering = scheme
+	aggregate.Copies = copies
+	return aggregate
+}
+
+func bookCopy(inventoryNumber string, location copydomain.Location, condition storagedomain.Condition) copydomain.Copy {
+	bookCopy := copydomain.NewCopy(inventoryNumber, "book", "9783127323207", "Math", location, condition, registered)
+	if location.Kind != copydomain.InStorage {
+		bookCopy.ClassID = inClass.ID
+	}
+	return bookCopy
+}
+
+func TestNumberingScheme(t *testing.T) {
+	assert.Equal(t, "LMF-0042", scheme.Format(42))
+	assert.Equal(t, 9999, scheme.Max())
+	assert.True(t, scheme.Valid())
+	assert.True(t, copydomain.NumberingScheme{Digits: 6, Next: 1}.Valid())
+	assert.False(t, copydomain.NumberingScheme{Prefix: "LMF 1", Digits: 4, Next: 1}.Valid())
+	assert.False(t, copydomain.NumberingScheme{Prefix: "LMF-", Digits: 10, Next: 1}.Valid())
+	assert.False(t, copydomain.NumberingScheme{Prefix: "LMF-", Digits: 2, Next: 100}.Valid())
+	assert.False(t, copydomain.NumberingScheme{}.Enabled())
+}
+
+func TestConfigureNumbering(t *testing.T) {
+	aggregate := copydomain.NewSchoolCopyAggregateWithID("school")
+	assert.Equal(t, copydomain.ErrInvalidNumberingScheme, aggregate.ConfigureNumbering(copydomain.NumberingScheme{Digits: 4}))
+	assert.Nil(t, aggregate.ConfigureNumbering(scheme))
+	assert.Equal(t, scheme, aggregate.Numbering)
+	assert.Len(t, aggregate.Events, 1)
+}
+
+func TestRegisterCopies(t *testing.T) {
+	tests := []struct {
+		name             string
+		aggregate        *copydomain.SchoolCopyAggregate
+		storageID        string
+		condition        storagedomain.Condition
+		inventoryNumbers []string
+		quantity         int
+		expected         []string
+		next             int
+		err              error
+	}{
+		{
+			name:      "generate inventory numbers",
+			aggregate: initSchoolCopyAggregate(),
+			storageID: "storage",
+			quantity:  2,
+			expected:  []string{"LMF-0001", "LMF-0002"},
+			next:      3,
+		},
+		{
+			name:      "skip inventory numbers in use",
+			aggregate: initSchoolCopyAggregate(bookCopy("LMF-0001", inStorage, storagedomain.Good)),
+			storageID: "storage",
+			quantity:  1,
+			expected:  []string{"LMF-0002"},
+			next:      3,
+		},
+		{
+			name:             "register stamped copies",
+			aggregate:        initSchoolCopyAggregate(),
+			storageID:        "storage",
+			condition:        storagedomain.Worn,
+			inventoryNumbers: []string{"OLD-17", "OLD-18"},
+			expected:         []string{"OLD-17", "OLD-18"},
+			next:             1,
+		},
+		{
+			name:      "tracking not enabled",
+			aggregate: copydomain.NewSchoolCopyAggregateWithID("school"),
+			storageID: "storage",
+			quantity:  1,
+			err:       copydomain.ErrCopyTrackingNotEnabled,
+		},
+		{
+			name:      "storage not set",
+			aggregate: initSchoolCopyAggregate(),
+			quantity:  1,
+			err:       copydomain.ErrStorageIDNotSet,
+		},
+		{
+			name:      "lost copies",
+			aggregate: initSchoolCopyAggregate(),
+			storageID: "storage",
+			condition: storagedomain.Lost,
+			quantity:  1,
+			err:       copydomain.ErrInvalidCondition(storagedomain.Lost),
+		},
+		{
+			name:             "duplicate inventory number",
+			aggregate:        initSchoolCopyAggregate(bookCopy("OLD-17", inStorage, storagedomain.Good)),
+			storageID:        "storage",
+			inventoryNumbers: []string{"OLD-17"},
+			err:              copydomain.ErrInventoryNumberExists("OLD-17"),
+		},
+		{
+			name:      "quantity not positive",
+			aggregate: initSchoolCopyAggregate(),
+			storageID: "storage",
+			err:       copydomain.ErrQuantityNotPositive,
+		},
+		{
+			name: "numbering exhausted",
+			aggregate: &copydomain.SchoolCopyAggregate{
+				AggregateModel: initSchoolCopyAggregate().AggregateModel,
+				Numbering:      copydomain.NumberingScheme{Prefix: "A", Digits: 1, Next: 9},
+			},
+			storageID: "storage",
+			quantity:  2,
+			err:       copydomain.ErrNumberingExhausted,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			inventoryNumbers, err := test.aggregate.RegisterCopies("book", "9783127323207", "Math", test.storageID, test.condition, test.inventoryNumbers, test.quantity)
+			if test.err != nil {
+				assert.Equal(t, test.err, err)
+				return
+			}
+			assert.Nil(t, err)
+			assert.Equal(t, test.expected, inventoryNumbers)
+			assert.Equal(t, test.next, test.aggregate.Numbering.Next)
+			for _, inventoryNumber := range inventoryNumbers {
+				assert.True(t, test.aggregate.Exists(inventoryNumber))
+			}
+			expectedCondition := test.condition
+			if expectedCondition == "" {
+				expectedCondition = storagedomain.Good
+			}
+			last := test.aggregate.Copies[len(test.aggregate.Copies)-1]
+			assert.Equal(t, inStorage, last.Location)
+			assert.Equal(t, expectedCondition, last.Condition)
+		})
+	}
+}
+
+func TestMoveCopy(t *testing.T) {
+	tests := []struct {
+		name string
+		copy copydomain.Copy
+		to   copydomain.Location
+		err  error
+	}{
+		{name: "hand out to a class", copy: bookCopy("LMF-0001", inStorage, storagedomain.Good), to: inClass},
+		{name: "hand out to a pupil", copy: bookCopy("LMF-0001", inClass, storagedomain.Good), to: withPupil},
+		{name: "give back to the class", copy: bookCopy("LMF-0001", withPupil, storagedomain.Good), to: inClass},
+		{name: "take back from the class", copy: bookCopy("LMF-0001", inClass, storagedomain.Good), to: inStorage},
+		{
+			name: "hand out to a pupil from a storage",
+			copy: bookCopy("LMF-0001", inStorage, storagedomain.Good),
+			to:   withPupil,
+			err:  copydomain.ErrPupilCopyNotFromClass,
+		},
+		{
+			name: "hand on to another class",
+			copy: bookCopy("LMF-0001", inClass, storagedomain.Good),
+			to:   copydomain.Location{Kind: copydomain.InClass, ID: "other"},
+			err:  copydomain.ErrCopyInClass("LMF-0001", "class"),
+		},
+		{
+			name: "take back from a pupil",
+			copy: bookCopy("LMF-0001", withPupil, storagedomain.Good),
+			to:   inStorage,
+			err:  copydomain.ErrCopyWithPupil("LMF-0001", "class"),
+		},
+		{
+			name: "already there",
+			copy: bookCopy("LMF-0001", inStorage, storagedomain.Good),
+			to:   inStorage,
+			err:  copydomain.ErrCopyAlreadyAt("LMF-0001", inStorage),
+		},
+		{
+			name: "invalid location",
+			copy: bookCopy("LMF-0001", inStorage, storagedomain.Good),
+			to:   copydomain.Location{Kind: "attic", ID: "attic"},
+			err:  copydomain.ErrInvalidLocation,
+		},
+		{
+			name: "written off",
+			copy: copydomain.Copy{InventoryNumber: "LMF-0001", Location: inStorage, WrittenOff: true},
+			to:   inClass,
+			err:  copydomain.ErrCopyWrittenOff("LMF-0001"),
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			aggregate := initSchoolCopyAggregate(test.copy)
+			before, err := aggregate.MoveCopy("LMF-0001", test.to, "")
+			if test.err != nil {
+				assert.Equal(t, test.err, err)
+				assert.Equal(t, test.copy.Location, aggregate.Copies[0].Location)
+				return
+			}
+			assert.Nil(t, err)
+			assert.Equal(t, test.copy.Location, before.Location)
+			assert.Equal(t, test.to, aggregate.Copies[0].Location)
+		})
+	}
+	_, err := initSchoolCopyAggregate().MoveCopy("LMF-0001", inClass, "")
+	assert.Equal(t, copydomain.ErrCopyNotFound("LMF-0001"), err)
+}
+
+func TestGradeCopy(t *testing.T) {
+	aggregate := initSchoolCopyAggregate(bookCopy("LMF-0001", withPupil, storagedomain.Good))
+	assert.Equal(t, domain.ErrReasonNotSpecified, aggregate.GradeCopy("LMF-0001", storagedomain.Damaged, ""))
+	assert.Equal(t, copydomain.ErrConditionNotWorse, aggregate.GradeCopy("LMF-0001", storagedomain.New, "repaired"))
+	assert.Nil(t, aggregate.GradeCopy("LMF-0001", storagedomain.Damaged, "water damage"))
+	assert.Equal(t, storagedomain.Damaged, aggregate.Copies[0].Condition)
+}
+
+func TestWriteOffCopy(t *testing.T) {
+	tests := []struct {
+		name string
+		copy copydomain.Copy
+		err  error
+	}{
+		{name: "write off a damaged copy", copy: bookCopy("LMF-0001", inStorage, storagedomain.Damaged)},
+		{name: "write off a lost copy", copy: bookCopy("LMF-0001", inStorage, storagedomain.Lost)},
+		{
+			name: "copy with a pupil",
+			copy: bookCopy("LMF-0001", withPupil, storagedomain.Lost),
+			err:  copydomain.ErrCopyWithPupil("LMF-0001", "class"),
+		},
+		{
+			name: "usable copy",
+			copy: bookCopy("LMF-0001", inStorage, storagedomain.Worn),
+			err:  copydomain.ErrWriteOffUsableCopy,
+		},
+		{
+			name: "copy in a class",
+			copy: bookCopy("LMF-0001", inClass, storagedomain.Damaged),
+			err:  copydomain.ErrCopyInClass("LMF-0001", "class"),
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			aggregate := initSchoolCopyAggregate(test.copy)
+			writtenOff, err := aggregate.WriteOffCopy("LMF-0001", "beyond repair")
+			if test.err != nil {
+				assert.Equal(t, test.err, err)
+				assert.False(t, aggregate.Copies[0].WrittenOff)
+				return
+			}
+			assert.Nil(t, err)
+			assert.Equal(t, test.copy.Location, writtenOff.Location)
+			assert.True(t, aggregate.Copies[0].WrittenOff)
+		})
+	}
+}
+
+func TestMoveCopyKeepsClass(t *testing.T) {
+	aggregate := initSchoolCopyAggregate(bookCopy("LMF-0001", inStorage, storagedomain.Good))
+	for _, to := range []copydomain.Location{inClass, withPupil} {
+		_, err := aggregate.MoveCopy("LMF-0001", to, "")
+		assert.Nil(t, err)
+		assert.Equal(t, "class", aggregate.Copies[0].ClassID)
+	}
+	_, err := aggregate.MoveCopy("LMF-0001", inClass, "")
+	assert.Nil(t, err)
+	_, err = aggregate.MoveCopy("LMF-0001", inStorage, "")
+	assert.Nil(t, err)
+	assert.Empty(t, aggregate.Copies[0].ClassID)
+}
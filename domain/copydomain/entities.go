@@ -0,0 +1,100 @@
+package copydomain
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"time"
+
+	"github.com/kammeph/school-book-storage-service/domain"
+	"github.com/kammeph/school-book-storage-service/domain/storagedomain"
+)
+
+var prefixPattern = regexp.MustCompile(`^[A-Za-z0-9/-]{0,10}$`)
+
+// NumberingScheme generates the inventory numbers of a school from a prefix
+// and a zero padded sequence, e.g. LMF-000042 for the prefix LMF- and six
+// digits. Copies are only tracked once a school configured its scheme.
+type NumberingScheme struct {
+	Prefix string
+	Digits int
+	Next   int
+}
+
+func (s NumberingScheme) Enabled() bool {
+	return s.Digits > 0
+}
+
+func (s NumberingScheme) Valid() bool {
+	return prefixPattern.MatchString(s.Prefix) && s.Digits > 0 && s.Digits <= 9 && s.Next > 0 && s.Next <= s.Max()
+}
+
+// Max returns the highest sequence that fits into the digits.
+func (s NumberingScheme) Max() int {
+	return int(math.Pow10(s.Digits)) - 1
+}
+
+func (s NumberingScheme) Format(sequence int) string {
+	return fmt.Sprintf("%s%0*d", s.Prefix, s.Digits, sequence)
+}
+
+type LocationKind string
+
+const (
+	InStorage LocationKind = "storage"
+	InClass   LocationKind = "class"
+	WithPupil LocationKind = "pupil"
+)
+
+// Location is where a copy currently is: a storage, a class or a pupil.
+type Location struct {
+	Kind LocationKind `json:"kind" bson:"kind"`
+	ID   string       `json:"id" bson:"id"`
+}
+
+func StorageLocation(storageID string) Location {
+	return Location{InStorage, storageID}
+}
+
+func (l Location) Valid() bool {
+	return (l.Kind == InStorage || l.Kind == InClass || l.Kind == WithPupil) && l.ID != ""
+}
+
+func (l Location) String() string {
+	return fmt.Sprintf("%s %s", l.Kind, l.ID)
+}
+
+// Copy is a single physical copy of a book stamped with an inventory number.
+// ClassID is the class that handed the copy out while a class or one of its
+// pupils has it.
+type Copy struct {
+	InventoryNumber string
+	BookID          string
+	Isbn            domain.Isbn
+	Title           string
+	Location        Location
+	ClassID         string
+	Condition       storagedomain.Condition
+	WrittenOff      bool
+	RegisteredAt    time.Time
+	UpdatedAt       time.Time
+}
+
+func NewCopy(
+	inventoryNumber, bookID string,
+	isbn domain.Isbn,
+	title string,
+	location Location,
+	condition storagedomain.Condition,
+	timeStamp time.Time,
+) Copy {
+	return Copy{
+		InventoryNumber: inventoryNumber,
+		BookID:          bookID,
+		Isbn:            isbn,
+		Title:           title,
+		Location:        location,
+		Condition:       condition,
+		RegisteredAt:    timeStamp,
+	}
+}
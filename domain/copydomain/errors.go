@@ -0,0 +1,62 @@
+package copydomain
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/kammeph/school-book-storage-service/domain/storagedomain"
+)
+
+var (
+	ErrInvalidNumberingScheme = errors.New("the prefix may only contain up to 10 letters, digits, - or /, the digits must be between 1 and 9 and the next number must fit into the digits")
+	ErrCopyTrackingNotEnabled = errors.New("copies can only be registered after a numbering scheme was configured")
+	ErrNumberingExhausted     = errors.New("the numbering scheme has no inventory numbers left")
+	ErrBookIDNotSet           = errors.New("book ID not set")
+	ErrStorageIDNotSet        = errors.New("storage ID not set")
+	ErrInventoryNumberNotSet  = errors.New("inventory number not set")
+	ErrQuantityNotPositive    = errors.New("the quantity must be greater than zero")
+	ErrInvalidLocation        = errors.New("a copy can only be in a storage, a class or with a pupil")
+	ErrConditionNotWorse      = errors.New("the condition of a copy can only get worse")
+	ErrWriteOffUsableCopy     = errors.New("only damaged or lost copies can be written off")
+	ErrPupilCopyNotFromClass  = errors.New("pupils get their copies from their class")
+)
+
+func ErrInvalidCondition(condition storagedomain.Condition) error {
+	return fmt.Errorf("%s is not a valid condition for a copy", condition)
+}
+
+func ErrInventoryNumberExists(inventoryNumber string) error {
+	return fmt.Errorf("a copy with the inventory number %s already exists", inventoryNumber)
+}
+
+func ErrCopyNotFound(inventoryNumber string) error {
+	return fmt.Errorf("copy with the inventory number %s not found", inventoryNumber)
+}
+
+func ErrCopyWrittenOff(inventoryNumber string) error {
+	return fmt.Errorf("copy %s was written off", inventoryNumber)
+}
+
+func ErrCopyAlreadyAt(inventoryNumber string, location Location) error {
+	return fmt.Errorf("copy %s is already at %s", inventoryNumber, location)
+}
+
+func ErrCopyInClass(inventoryNumber, classID string) error {
+	return fmt.Errorf("copy %s has to be taken back from class %s first", inventoryNumber, classID)
+}
+
+func ErrCopyWithPupil(inventoryNumber, classID string) error {
+	return fmt.Errorf("copy %s has to be given back to class %s first", inventoryNumber, classID)
+}
+
+func ErrPupilNotInClass(pupilID, classID string) error {
+	return fmt.Errorf("pupil %s is not in class %s", pupilID, classID)
+}
+
+func ErrApplyEventCopyAlreadyExists(eventType, inventoryNumber string) error {
+	return fmt.Errorf("can not apply %s: copy %s already exists", eventType, inventoryNumber)
+}
+
+func ErrApplyEventCopyNotFound(eventType, inventoryNumber string) error {
+	return fmt.Errorf("can not apply %s: copy %s not found", eventType, inventoryNumber)
+}
@@ -0,0 +1,139 @@
+package copydomain
+
+import (
+	"github.com/kammeph/school-book-storage-service/domain"
+	"github.com/kammeph/school-book-storage-service/domain/storagedomain"
+)
+
+var (
+	NumberingConfigured = "COPY_NUMBERING_CONFIGURED"
+	CopyRegistered      = "COPY_REGISTERED"
+	CopyMoved           = "COPY_MOVED"
+	CopyGraded          = "COPY_GRADED"
+	CopyWrittenOff      = "COPY_WRITTEN_OFF"
+)
+
+type NumberingConfiguredEvent struct {
+	Prefix string `json:"prefix"`
+	Digits int    `json:"digits"`
+	Next   int    `json:"next"`
+}
+
+func NewNumberingConfigured(aggregate *SchoolCopyAggregate, scheme NumberingScheme) (domain.Event, error) {
+	eventData := NumberingConfiguredEvent{
+		Prefix: scheme.Prefix,
+		Digits: scheme.Digits,
+		Next:   scheme.Next,
+	}
+	event := domain.NewEvent(aggregate, NumberingConfigured)
+	if err := event.SetJsonData(eventData); err != nil {
+		return nil, err
+	}
+	return event, nil
+}
+
+// CopyRegisteredEvent carries the sequence the inventory number was generated
+// from. It is zero for copies that were stamped before they were registered.
+type CopyRegisteredEvent struct {
+	SchoolID        string                  `json:"schoolId"`
+	InventoryNumber string                  `json:"inventoryNumber"`
+	Sequence        int                     `json:"sequence"`
+	BookID          string                  `json:"bookId"`
+	Isbn            domain.Isbn             `json:"isbn"`
+	Title           string                  `json:"title"`
+	Location        Location                `json:"location"`
+	Condition       storagedomain.Condition `json:"condition"`
+}
+
+func NewCopyRegistered(aggregate *SchoolCopyAggregate, bookCopy Copy, sequence int) (domain.Event, error) {
+	eventData := CopyRegisteredEvent{
+		SchoolID:        aggregate.AggregateID(),
+		InventoryNumber: bookCopy.InventoryNumber,
+		Sequence:        sequence,
+		BookID:          bookCopy.BookID,
+		Isbn:            bookCopy.Isbn,
+		Title:           bookCopy.Title,
+		Location:        bookCopy.Location,
+		Condition:       bookCopy.Condition,
+	}
+	event := domain.NewEvent(aggregate, CopyRegistered)
+	if err := event.SetJsonData(eventData); err != nil {
+		return nil, err
+	}
+	return event, nil
+}
+
+type CopyMovedEvent struct {
+	SchoolID        string   `json:"schoolId"`
+	InventoryNumber string   `json:"inventoryNumber"`
+	BookID          string   `json:"bookId"`
+	From            Location `json:"from"`
+	To              Location `json:"to"`
+	Reason          string   `json:"reason"`
+}
+
+func NewCopyMoved(aggregate *SchoolCopyAggregate, bookCopy Copy, to Location, reason string) (domain.Event, error) {
+	eventData := CopyMovedEvent{
+		SchoolID:        aggregate.AggregateID(),
+		InventoryNumber: bookCopy.InventoryNumber,
+		BookID:          bookCopy.BookID,
+		From:            bookCopy.Location,
+		To:              to,
+		Reason:          reason,
+	}
+	event := domain.NewEvent(aggregate, CopyMoved)
+	if err := event.SetJsonData(eventData); err != nil {
+		return nil, err
+	}
+	return event, nil
+}
+
+type CopyGradedEvent struct {
+	SchoolID        string                  `json:"schoolId"`
+	InventoryNumber string                  `json:"inventoryNumber"`
+	BookID          string                  `json:"bookId"`
+	From            storagedomain.Condition `json:"from"`
+	Condition       storagedomain.Condition `json:"condition"`
+	Reason          string                  `json:"reason"`
+}
+
+func NewCopyGraded(aggregate *SchoolCopyAggregate, bookCopy Copy, condition storagedomain.Condition, reason string) (domain.Event, error) {
+	eventData := CopyGradedEvent{
+		SchoolID:        aggregate.AggregateID(),
+		InventoryNumber: bookCopy.InventoryNumber,
+		BookID:          bookCopy.BookID,
+		From:            bookCopy.Condition,
+		Condition:       condition,
+		Reason:          reason,
+	}
+	event := domain.NewEvent(aggregate, CopyGraded)
+	if err := event.SetJsonData(eventData); err != nil {
+		return nil, err
+	}
+	return event, nil
+}
+
+type CopyWrittenOffEvent struct {
+	SchoolID        string                  `json:"schoolId"`
+	InventoryNumber string                  `json:"inventoryNumber"`
+	BookID          string                  `json:"bookId"`
+	Location        Location                `json:"location"`
+	Condition       storagedomain.Condition `json:"condition"`
+	Reason          string                  `json:"reason"`
+}
+
+func NewCopyWrittenOff(aggregate *SchoolCopyAggregate, bookCopy Copy, reason string) (domain.Event, error) {
+	eventData := CopyWrittenOffEvent{
+		SchoolID:        aggregate.AggregateID(),
+		InventoryNumber: bookCopy.InventoryNumber,
+		BookID:          bookCopy.BookID,
+		Location:        bookCopy.Location,
+		Condition:       bookCopy.Condition,
+		Reason:          reason,
+	}
+	event := domain.NewEvent(aggregate, CopyWrittenOff)
+	if err := event.SetJsonData(eventData); err != nil {
+		return nil, err
+	}
+	return event, nil
+}
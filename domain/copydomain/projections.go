@@ -0,0 +1,65 @@
+package copydomain
+
+import (
+	"time"
+
+	"github.com/kammeph/school-book-storage-service/domain"
+	"github.com/kammeph/school-book-storage-service/domain/storagedomain"
+)
+
+// CopyHistoryEntry records where a copy was and in which condition after one
+// of its events.
+type CopyHistoryEntry struct {
+	At        time.Time               `json:"at" bson:"at"`
+	EventType string                  `json:"eventType" bson:"eventType"`
+	Location  Location                `json:"location" bson:"location"`
+	Condition storagedomain.Condition `json:"condition" bson:"condition"`
+	Reason    string                  `json:"reason" bson:"reason"`
+}
+
+type CopyProjection struct {
+	SchoolID        string                  `json:"schoolId" bson:"schoolId"`
+	InventoryNumber string                  `json:"inventoryNumber" bson:"inventoryNumber"`
+	BookID          string                  `json:"bookId" bson:"bookId"`
+	Isbn            domain.Isbn             `json:"isbn" bson:"isbn"`
+	Title           string                  `json:"title" bson:"title"`
+	Location        Location                `json:"location" bson:"location"`
+	Condition       storagedomain.Condition `json:"condition" bson:"condition"`
+	WrittenOff      bool                    `json:"writtenOff" bson:"writtenOff"`
+	RegisteredAt    time.Time               `json:"registeredAt" bson:"registeredAt"`
+	History         []CopyHistoryEntry      `json:"history" bson:"history"`
+	Version         int                     `json:"version" bson:"version"`
+}
+
+func NewCopyProjection(schoolID string, bookCopy Copy, version int) CopyProjection {
+	return CopyProjection{
+		SchoolID:        schoolID,
+		InventoryNumber: bookCopy.InventoryNumber,
+		BookID:          bookCopy.BookID,
+		Isbn:            bookCopy.Isbn,
+		Title:           bookCopy.Title,
+		Location:        bookCopy.Location,
+		Condition:       bookCopy.Condition,
+		RegisteredAt:    bookCopy.RegisteredAt,
+		History: []CopyHistoryEntry{{
+			At:        bookCopy.RegisteredAt,
+			EventType: CopyRegistered,
+			Location:  bookCopy.Location,
+			Condition: bookCopy.Condition,
+		}},
+		Version: version,
+	}
+}
+
+// Record appends the current location and condition of the copy to its
+// history.
+func (p *CopyProjection) Record(at time.Time, eventType, reason string, version int) {
+	p.History = append(p.History, CopyHistoryEntry{
+		At:        at,
+		EventType: eventType,
+		Location:  p.Location,
+		Condition: p.Condition,
+		Reason:    reason,
+	})
+	p.Version = version
+}
@@ -70,8 +70,14 @@ func (m EventModel) EventData() string {
 	return m.Data
 }
 
+// GetJsonData decodes the event data after migrating it with the upcasters
+// registered for the event type.
 func (m EventModel) GetJsonData(data interface{}) error {
-	return json.Unmarshal([]byte(m.Data), data)
+	eventData, err := upcast(m.Type, []byte(m.Data))
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(eventData, data)
 }
 
 func (m *EventModel) SetJsonData(data interface{}) error {
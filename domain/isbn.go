@@ -0,0 +1,195 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+)
+
+var (
+	ErrInvalidIsbn        = errors.New("the ISBN must have 10 or 13 digits with a valid check digit")
+	ErrIsbnNotConvertible = errors.New("only ISBNs with the prefix 978 can be converted to ISBN-10")
+)
+
+var isbnSeparators = strings.NewReplacer("-", "", " ", "")
+
+// Isbn is an ISBN in its normalized form, the 13 digits of the ISBN-13
+// without hyphens. ISBN-10s are converted to ISBN-13 when they are parsed, so
+// both forms of a book compare equal.
+type Isbn string
+
+// ParseIsbn validates an ISBN-10 or ISBN-13 that may contain hyphens or
+// spaces and returns it normalized.
+func ParseIsbn(value string) (Isbn, error) {
+	digits := strings.ToUpper(isbnSeparators.Replace(value))
+	switch len(digits) {
+	case 10:
+		if !isDigits(digits[:9]) || isbn10CheckDigit(digits[:9]) != digits[9] {
+			return "", ErrInvalidIsbn
+		}
+		body := "978" + digits[:9]
+		return Isbn(body + string(isbn13CheckDigit(body))), nil
+	case 13:
+		if !isDigits(digits) || isbn13CheckDigit(digits[:12]) != digits[12] {
+			return "", ErrInvalidIsbn
+		}
+		return Isbn(digits), nil
+	default:
+		return "", ErrInvalidIsbn
+	}
+}
+
+func (i Isbn) Isbn13() string {
+	return string(i)
+}
+
+// Isbn10 returns the ISBN-10 of the ISBN. Only ISBNs with the prefix 978 have
+// one.
+func (i Isbn) Isbn10() (string, error) {
+	if len(i) != 13 || !strings.HasPrefix(string(i), "978") {
+		return "", ErrIsbnNotConvertible
+	}
+	body := string(i[3:12])
+	return body + string(isbn10CheckDigit(body)), nil
+}
+
+// Hyphenated formats the ISBN-13 as prefix, registration group, publisher,
+// title and check digit. If the publisher ranges of the group are not known,
+// publisher and title are not separated. Unknown registration groups keep
+// everything between prefix and check digit together.
+func (i Isbn) Hyphenated() string {
+	value := string(i)
+	if len(value) != 13 {
+		return value
+	}
+	prefix, rest, check := value[:3], value[3:12], value[12:]
+	groupLength := rangeLength(registrationGroups[prefix], rest)
+	if groupLength == 0 {
+		return strings.Join([]string{prefix, rest, check}, "-")
+	}
+	group, rest := rest[:groupLength], rest[groupLength:]
+	publisherLength := rangeLength(publisherRanges[prefix+"-"+group], rest)
+	if publisherLength == 0 || publisherLength >= len(rest) {
+		return strings.Join([]string{prefix, group, rest, check}, "-")
+	}
+	return strings.Join([]string{prefix, group, rest[:publisherLength], rest[publisherLength:], check}, "-")
+}
+
+func (i Isbn) String() string {
+	return string(i)
+}
+
+func isDigits(value string) bool {
+	for _, r := range value {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func isbn10CheckDigit(body string) byte {
+	sum := 0
+	for idx, r := range body {
+		sum += (10 - idx) * int(r-'0')
+	}
+	check := (11 - sum%11) % 11
+	if check == 10 {
+		return 'X'
+	}
+	return byte('0' + check)
+}
+
+func isbn13CheckDigit(body string) byte {
+	sum := 0
+	for idx, r := range body {
+		weight := 1
+		if idx%2 == 1 {
+			weight = 3
+		}
+		sum += weight * int(r-'0')
+	}
+	return byte('0' + (10-sum%10)%10)
+}
+
+// isbnRange assigns the length of an element to the digits that follow the
+// preceding elements, compared by their first seven digits. A length of zero
+// marks a range that is not in use.
+type isbnRange struct {
+	from, to string
+	length   int
+}
+
+func rangeLength(ranges []isbnRange, digits string) int {
+	key := (digits + "0000000")[:7]
+	for _, r := range ranges {
+		if key >= r.from && key <= r.to {
+			return r.length
+		}
+	}
+	return 0
+}
+
+var registrationGroups = map[string][]isbnRange{"978": groups978, "979": groups979}
+
+// publisherRanges are only known for the largest registration groups.
+var publisherRanges = map[string][]isbnRange{"978-0": publishers0, "978-2": publishers2, "978-3": publishers3}
+
+var groups978 = []isbnRange{
+	{"0000000", "5999999", 1},
+	{"6000000", "6499999", 3},
+	{"6500000", "6599999", 2},
+	{"6600000", "6999999", 0},
+	{"7000000", "7999999", 1},
+	{"8000000", "9499999", 2},
+	{"9500000", "9899999", 3},
+	{"9900000", "9989999", 4},
+	{"9990000", "9999999", 5},
+}
+
+var groups979 = []isbnRange{
+	{"1000000", "1299999", 2},
+	{"8000000", "8099999", 1},
+}
+
+// English language area
+var publishers0 = []isbnRange{
+	{"0000000", "1999999", 2},
+	{"2000000", "2279999", 3},
+	{"2280000", "2289999", 4},
+	{"2290000", "6479999", 3},
+	{"6480000", "6489999", 7},
+	{"6490000", "6999999", 3},
+	{"7000000", "8499999", 4},
+	{"8500000", "8999999", 5},
+	{"9000000", "9499999", 6},
+	{"9500000", "9999999", 7},
+}
+
+// French language area
+var publishers2 = []isbnRange{
+	{"0000000", "1999999", 2},
+	{"2000000", "3499999", 3},
+	{"3500000", "3999999", 5},
+	{"4000000", "6999999", 3},
+	{"7000000", "8399999", 4},
+	{"8400000", "8999999", 5},
+	{"9000000", "9499999", 6},
+	{"9500000", "9999999", 7},
+}
+
+// German language area
+var publishers3 = []isbnRange{
+	{"0000000", "0299999", 2},
+	{"0300000", "0339999", 3},
+	{"0340000", "0369999", 4},
+	{"0370000", "0399999", 5},
+	{"0400000", "1999999", 2},
+	{"2000000", "6999999", 3},
+	{"7000000", "8499999", 4},
+	{"8500000", "8999999", 5},
+	{"9000000", "9499999", 6},
+	{"9500000", "9539999", 7},
+	{"9540000", "9699999", 5},
+	{"9700000", "9849999", 7},
+	{"9850000", "9999999", 5},
+}
@@ -0,0 +1,65 @@
+package domain_test
+
+import (
+	"testing"
+
+	"github.com/kammeph/school-book-storage-service/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseIsbn(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		isbn  domain.Isbn
+		err   error
+	}{
+		{name: "hyphenated ISBN-13", value: "978-3-16-148410-0", isbn: "9783161484100"},
+		{name: "compact ISBN-13", value: "9783161484100", isbn: "9783161484100"},
+		{name: "ISBN-10 with check digit X", value: "3-16-148410-x", isbn: "9783161484100"},
+		{name: "ISBN-10 with spaces", value: "0 306 40615 2", isbn: "9780306406157"},
+		{name: "wrong check digit", value: "978-3-16-148410-1", err: domain.ErrInvalidIsbn},
+		{name: "wrong length", value: "978-3-16-14841", err: domain.ErrInvalidIsbn},
+		{name: "letters", value: "97831614841AB", err: domain.ErrInvalidIsbn},
+		{name: "empty", value: "", err: domain.ErrInvalidIsbn},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			isbn, err := domain.ParseIsbn(test.value)
+			assert.Equal(t, test.err, err)
+			assert.Equal(t, test.isbn, isbn)
+		})
+	}
+}
+
+func TestIsbn10(t *testing.T) {
+	isbn10, err := domain.Isbn("9783161484100").Isbn10()
+	assert.NoError(t, err)
+	assert.Equal(t, "316148410X", isbn10)
+	_, err = domain.Isbn("9791090636071").Isbn10()
+	assert.Equal(t, domain.ErrIsbnNotConvertible, err)
+}
+
+func TestHyphenated(t *testing.T) {
+	assert.Equal(t, "978-3-16-148410-0", domain.Isbn("9783161484100").Hyphenated())
+	assert.Equal(t, "978-3-12-345678-9", domain.Isbn("9783123456789").Hyphenated())
+	assert.Equal(t, "978-0-306-40615-7", domain.Isbn("9780306406157").Hyphenated())
+	assert.Equal(t, "978-2-07-036024-5", domain.Isbn("9782070360245").Hyphenated())
+	assert.Equal(t, "978-88-0466823-7", domain.Isbn("9788804668237").Hyphenated())
+	assert.Equal(t, "979-10-9063607-1", domain.Isbn("9791090636071").Hyphenated())
+	assert.Equal(t, "979-500000000-6", domain.Isbn("9795000000006").Hyphenated())
+}
+
+func TestIsbnUpcaster(t *testing.T) {
+	domain.RegisterUpcaster("TEST_ISBN", domain.IsbnUpcaster("isbn"))
+	data := struct {
+		Isbn domain.Isbn `json:"isbn"`
+	}{}
+	event := domain.EventModel{Type: "TEST_ISBN", Data: "{\"isbn\":\"3-16-148410-X\"}"}
+	assert.NoError(t, event.GetJsonData(&data))
+	assert.Equal(t, domain.Isbn("9783161484100"), data.Isbn)
+
+	event = domain.EventModel{Type: "TEST_ISBN", Data: "{\"isbn\":\"123\"}"}
+	assert.NoError(t, event.GetJsonData(&data))
+	assert.Equal(t, domain.Isbn("123"), data.Isbn)
+}
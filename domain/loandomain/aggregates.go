@@ -0,0 +1,92 @@
+package loandomain
+
+import (
+	"github.com/kammeph/school-book-storage-service/domain"
+	"github.com/kammeph/school-book-storage-service/fp"
+)
+
+type SchoolLoanAggregate struct {
+	*domain.AggregateModel
+	Loans []Loan
+}
+
+func NewSchoolLoanAggregate() *SchoolLoanAggregate {
+	aggregate := &SchoolLoanAggregate{
+		Loans: []Loan{},
+	}
+	model := domain.NewAggregateModel(aggregate.On)
+	aggregate.AggregateModel = &model
+	return aggregate
+}
+
+func NewSchoolLoanAggregateWithID(id string) *SchoolLoanAggregate {
+	aggregate := NewSchoolLoanAggregate()
+	aggregate.ID = id
+	return aggregate
+}
+
+func (a *SchoolLoanAggregate) On(event domain.Event) error {
+	switch event.EventType() {
+	case LoanIssued:
+		return a.onLoanIssued(event)
+	case LoanExtended:
+		return a.onLoanExtended(event)
+	case LoanReturned:
+		return a.onLoanReturned(event)
+	default:
+		return domain.ErrUnknownEvent(event)
+	}
+}
+
+func (a *SchoolLoanAggregate) onLoanIssued(event domain.Event) error {
+	eventData := LoanIssuedEvent{}
+	if err := event.GetJsonData(&eventData); err != nil {
+		return err
+	}
+	if fp.Some(a.Loans, func(l Loan) bool { return l.ID == eventData.LoanID }) {
+		return ErrApplyEventLoanAlreadyExists(event.EventType(), eventData.LoanID)
+	}
+	loan := NewLoan(
+		eventData.LoanID,
+		eventData.PupilID,
+		eventData.ClassID,
+		eventData.BookID,
+		eventData.InventoryNumber,
+		eventData.IssuedAt,
+		eventData.DueDate,
+		event.EventAt())
+	a.Version = event.EventVersion()
+	a.Loans = append(a.Loans, loan)
+	return nil
+}
+
+func (a *SchoolLoanAggregate) onLoanExtended(event domain.Event) error {
+	eventData := LoanExtendedEvent{}
+	if err := event.GetJsonData(&eventData); err != nil {
+		return err
+	}
+	loan := fp.Find(a.Loans, func(l Loan) bool { return l.ID == eventData.LoanID })
+	if loan == nil {
+		return ErrApplyEventLoanNotFound(event.EventType(), eventData.LoanID)
+	}
+	a.Version = event.EventVersion()
+	loan.DueDate = eventData.DueDate
+	loan.Extensions++
+	loan.UpdatedAt = event.EventAt()
+	return nil
+}
+
+func (a *SchoolLoanAggregate) onLoanReturned(event domain.Event) error {
+	eventData := LoanReturnedEvent{}
+	if err := event.GetJsonData(&eventData); err != nil {
+		return err
+	}
+	loan := fp.Find(a.Loans, func(l Loan) bool { return l.ID == eventData.LoanID })
+	if loan == nil {
+		return ErrApplyEventLoanNotFound(event.EventType(), eventData.LoanID)
+	}
+	a.Version = event.EventVersion()
+	loan.ReturnedAt = eventData.ReturnedAt
+	loan.UpdatedAt = event.EventAt()
+	return nil
+}
@@ -0,0 +1,84 @@
+package loandomain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/kammeph/school-book-storage-service/domain"
+	"github.com/kammeph/school-book-storage-service/fp"
+)
+
+func (a *SchoolLoanAggregate) IssueLoan(pupilID, classID, bookID, inventoryNumber string, issuedAt, dueDate time.Time) (string, error) {
+	if pupilID == "" {
+		return "", ErrPupilIDNotSet
+	}
+	if classID == "" {
+		return "", ErrClassIDNotSet
+	}
+	if bookID == "" {
+		return "", ErrBookIDNotSet
+	}
+	if inventoryNumber == "" {
+		return "", ErrInventoryNumberNotSet
+	}
+	if !dueDate.After(issuedAt) {
+		return "", ErrDueDateBeforeIssue
+	}
+	if fp.Some(a.Loans, func(l Loan) bool { return l.PupilID == pupilID && l.BookID == bookID && !l.Returned() }) {
+		return "", ErrBookAlreadyLentToPupil(bookID, pupilID)
+	}
+	if loan := fp.Find(a.Loans, func(l Loan) bool { return l.InventoryNumber == inventoryNumber && !l.Returned() }); loan != nil {
+		return "", ErrCopyAlreadyLent(inventoryNumber, loan.PupilID)
+	}
+	loanID := uuid.NewString()
+	event, err := NewLoanIssued(a, loanID, pupilID, classID, bookID, inventoryNumber, issuedAt, dueDate)
+	if err != nil {
+		return "", err
+	}
+	if err := a.Apply(event); err != nil {
+		return "", err
+	}
+	return loanID, nil
+}
+
+func (a *SchoolLoanAggregate) ExtendLoan(loanID string, dueDate time.Time, reason string) error {
+	if reason == "" {
+		return domain.ErrReasonNotSpecified
+	}
+	loan := fp.Find(a.Loans, func(l Loan) bool { return l.ID == loanID })
+	if loan == nil {
+		return ErrLoanWithIDNotFound(loanID)
+	}
+	if loan.Returned() {
+		return ErrLoanAlreadyReturned(loanID)
+	}
+	if loan.Extensions >= MaxExtensions {
+		return ErrMaxExtensionsReached(loanID)
+	}
+	if !dueDate.After(loan.DueDate) {
+		return ErrDueDateNotExtended(loan.DueDate, dueDate)
+	}
+	event, err := NewLoanExtended(a, loanID, dueDate, reason)
+	if err != nil {
+		return err
+	}
+	return a.Apply(event)
+}
+
+func (a *SchoolLoanAggregate) ReturnLoan(loanID string, returnedAt time.Time) error {
+	loan := fp.Find(a.Loans, func(l Loan) bool { return l.ID == loanID })
+	if loan == nil {
+		return ErrLoanWithIDNotFound(loanID)
+	}
+	if loan.Returned() {
+		return ErrLoanAlreadyReturned(loanID)
+	}
+	if returnedAt.Before(loan.IssuedAt) {
+		return ErrReturnBeforeIssue(loanID)
+	}
+	event, err := NewLoanReturned(a, loanID, returnedAt)
+	if err != nil {
+		return err
+	}
+	return a.Apply(event)
+}
@@ -0,0 +1,280 @@
+package loandomain_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kammeph/school-book-storage-service/domain"
+	"github.com/kammeph/school-book-storage-service/domain/loandomain"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	issuedAt = time.Date(2022, 9, 1, 0, 0, 0, 0, time.UTC)
+	dueDate  = time.Date(2023, 7, 15, 0, 0, 0, 0, time.UTC)
+)
+
+func initSchoolLoanAggregate(loans []loandomain.Loan) *loandomain.SchoolLoanAggregate {
+	aggregate := loandomain.NewSchoolLoanAggregateWithID("school")
+	aggregate.Loans = loans
+	return aggregate
+}
+
+func activeLoan() loandomain.Loan {
+	return loandomain.NewLoan("loan", "pupil", "class", "book", "LMF-0001", issuedAt, dueDate, issuedAt)
+}
+
+func TestIssueLoan(t *testing.T) {
+	tests := []struct {
+		name            string
+		loans           []loandomain.Loan
+		pupilID         string
+		classID         string
+		bookID          string
+		inventoryNumber string
+		dueDate         time.Time
+		err             error
+	}{
+		{
+			name:            "issue loan",
+			loans:           []loandomain.Loan{},
+			pupilID:         "pupil",
+			classID:         "class",
+			bookID:          "book",
+			inventoryNumber: "LMF-0002",
+			dueDate:         dueDate,
+		},
+		{
+			name:            "issue loan of a returned book again",
+			loans:           []loandomain.Loan{{ID: "loan", PupilID: "pupil", BookID: "book", ReturnedAt: dueDate}},
+			pupilID:         "pupil",
+			classID:         "class",
+			bookID:          "book",
+			inventoryNumber: "LMF-0002",
+			dueDate:         dueDate,
+		},
+		{
+			name:            "pupil not set",
+			loans:           []loandomain.Loan{},
+			classID:         "class",
+			bookID:          "book",
+			inventoryNumber: "LMF-0002",
+			dueDate:         dueDate,
+			err:             loandomain.ErrPupilIDNotSet,
+		},
+		{
+			name:            "class not set",
+			loans:           []loandomain.Loan{},
+			pupilID:         "pupil",
+			bookID:          "book",
+			inventoryNumber: "LMF-0002",
+			dueDate:         dueDate,
+			err:             loandomain.ErrClassIDNotSet,
+		},
+		{
+			name:    "book not set",
+			loans:   []loandomain.Loan{},
+			pupilID: "pupil",
+			classID: "class",
+			dueDate: dueDate,
+			err:     loandomain.ErrBookIDNotSet,
+		},
+		{
+			name:            "due date before issue date",
+			loans:           []loandomain.Loan{},
+			pupilID:         "pupil",
+			classID:         "class",
+			bookID:          "book",
+			inventoryNumber: "LMF-0002",
+			dueDate:         issuedAt.AddDate(0, 0, -1),
+			err:             loandomain.ErrDueDateBeforeIssue,
+		},
+		{
+			name:            "book already lent to pupil",
+			loans:           []loandomain.Loan{activeLoan()},
+			pupilID:         "pupil",
+			classID:         "class",
+			bookID:          "book",
+			inventoryNumber: "LMF-0002",
+			dueDate:         dueDate,
+			err:             loandomain.ErrBookAlreadyLentToPupil("book", "pupil"),
+		},
+		{
+			name:    "inventory number not set",
+			loans:   []loandomain.Loan{},
+			pupilID: "pupil",
+			classID: "class",
+			bookID:  "book",
+			dueDate: dueDate,
+			err:     loandomain.ErrInventoryNumberNotSet,
+		},
+		{
+			name:            "copy already lent to another pupil",
+			loans:           []loandomain.Loan{activeLoan()},
+			pupilID:         "other",
+			classID:         "class",
+			bookID:          "book",
+			inventoryNumber: "LMF-0001",
+			dueDate:         dueDate,
+			err:             loandomain.ErrCopyAlreadyLent("LMF-0001", "pupil"),
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			aggregate := initSchoolLoanAggregate(test.loans)
+			loanID, err := aggregate.IssueLoan(test.pupilID, test.classID, test.bookID, test.inventoryNumber, issuedAt, test.dueDate)
+			if test.err != nil {
+				assert.Equal(t, test.err, err)
+				assert.Len(t, aggregate.DomainEvents(), 0)
+				return
+			}
+			assert.NoError(t, err)
+			assert.NotEqual(t, "", loanID)
+			assert.Len(t, aggregate.DomainEvents(), 1)
+			assert.Equal(t, loandomain.LoanIssued, aggregate.DomainEvents()[0].EventType())
+			loan := aggregate.Loans[len(aggregate.Loans)-1]
+			assert.Equal(t, loanID, loan.ID)
+			assert.Equal(t, test.dueDate, loan.DueDate)
+			assert.Equal(t, test.inventoryNumber, loan.InventoryNumber)
+		})
+	}
+}
+
+func TestExtendLoan(t *testing.T) {
+	returned := activeLoan()
+	returned.ReturnedAt = dueDate
+	extended := activeLoan()
+	extended.Extensions = loandomain.MaxExtensions
+	tests := []struct {
+		name    string
+		loan    loandomain.Loan
+		loanID  string
+		dueDate time.Time
+		reason  string
+		err     error
+	}{
+		{
+			name:    "extend loan",
+			loan:    activeLoan(),
+			loanID:  "loan",
+			dueDate: dueDate.AddDate(0, 1, 0),
+			reason:  "summer course",
+		},
+		{
+			name:    "reason not set",
+			loan:    activeLoan(),
+			loanID:  "loan",
+			dueDate: dueDate.AddDate(0, 1, 0),
+			err:     domain.ErrReasonNotSpecified,
+		},
+		{
+			name:    "loan not found",
+			loan:    activeLoan(),
+			loanID:  "unknown",
+			dueDate: dueDate.AddDate(0, 1, 0),
+			reason:  "summer course",
+			err:     loandomain.ErrLoanWithIDNotFound("unknown"),
+		},
+		{
+			name:    "loan already returned",
+			loan:    returned,
+			loanID:  "loan",
+			dueDate: dueDate.AddDate(0, 1, 0),
+			reason:  "summer course",
+			err:     loandomain.ErrLoanAlreadyReturned("loan"),
+		},
+		{
+			name:    "maximum extensions reached",
+			loan:    extended,
+			loanID:  "loan",
+			dueDate: dueDate.AddDate(0, 1, 0),
+			reason:  "summer course",
+			err:     loandomain.ErrMaxExtensionsReached("loan"),
+		},
+		{
+			name:    "due date not extended",
+			loan:    activeLoan(),
+			loanID:  "loan",
+			dueDate: dueDate,
+			reason:  "summer course",
+			err:     loandomain.ErrDueDateNotExtended(dueDate, dueDate),
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			aggregate := initSchoolLoanAggregate([]loandomain.Loan{test.loan})
+			err := aggregate.ExtendLoan(test.loanID, test.dueDate, test.reason)
+			if test.err != nil {
+				assert.Equal(t, test.err, err)
+				assert.Len(t, aggregate.DomainEvents(), 0)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Len(t, aggregate.DomainEvents(), 1)
+			assert.Equal(t, loandomain.LoanExtended, aggregate.DomainEvents()[0].EventType())
+			assert.Equal(t, test.dueDate, aggregate.Loans[0].DueDate)
+			assert.Equal(t, test.loan.Extensions+1, aggregate.Loans[0].Extensions)
+		})
+	}
+}
+
+func TestReturnLoan(t *testing.T) {
+	returned := activeLoan()
+	returned.ReturnedAt = dueDate
+	tests := []struct {
+		name       string
+		loan       loandomain.Loan
+		loanID     string
+		returnedAt time.Time
+		err        error
+	}{
+		{
+			name:       "return loan",
+			loan:       activeLoan(),
+			loanID:     "loan",
+			returnedAt: dueDate,
+		},
+		{
+			name:       "loan not found",
+			loan:       activeLoan(),
+			loanID:     "unknown",
+			returnedAt: dueDate,
+			err:        loandomain.ErrLoanWithIDNotFound("unknown"),
+		},
+		{
+			name:       "loan already returned",
+			loan:       returned,
+			loanID:     "loan",
+			returnedAt: dueDate,
+			err:        loandomain.ErrLoanAlreadyReturned("loan"),
+		},
+		{
+			name:       "returned before issued",
+			loan:       activeLoan(),
+			loanID:     "loan",
+			returnedAt: issuedAt.AddDate(0, 0, -1),
+			err:        loandomain.ErrReturnBeforeIssue("loan"),
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			aggregate := initSchoolLoanAggregate([]loandomain.Loan{test.loan})
+			err := aggregate.ReturnLoan(test.loanID, test.returnedAt)
+			if test.err != nil {
+				assert.Equal(t, test.err, err)
+				assert.Len(t, aggregate.DomainEvents(), 0)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Len(t, aggregate.DomainEvents(), 1)
+			assert.True(t, aggregate.Loans[0].Returned())
+			assert.False(t, aggregate.Loans[0].IsOverdue(dueDate.AddDate(1, 0, 0)))
+		})
+	}
+}
+
+func TestIsOverdue(t *testing.T) {
+	loan := activeLoan()
+	assert.False(t, loan.IsOverdue(dueDate))
+	assert.True(t, loan.IsOverdue(dueDate.AddDate(0, 0, 1)))
+}
@@ -0,0 +1,43 @@
+package loandomain
+
+import "time"
+
+// MaxExtensions limits how often the due date of a loan can be extended.
+const MaxExtensions = 2
+
+// Loan is a copy of a book a pupil holds. InventoryNumber identifies the copy,
+// either from the copy inventory of the school or as written in the book.
+type Loan struct {
+	ID              string
+	PupilID         string
+	ClassID         string
+	BookID          string
+	InventoryNumber string
+	IssuedAt        time.Time
+	DueDate         time.Time
+	Extensions      int
+	ReturnedAt      time.Time
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+}
+
+func NewLoan(id, pupilID, classID, bookID, inventoryNumber string, issuedAt, dueDate, timeStamp time.Time) Loan {
+	return Loan{
+		ID:              id,
+		PupilID:         pupilID,
+		ClassID:         classID,
+		BookID:          bookID,
+		InventoryNumber: inventoryNumber,
+		IssuedAt:        issuedAt,
+		DueDate:         dueDate,
+		CreatedAt:       timeStamp,
+	}
+}
+
+func (l Loan) Returned() bool {
+	return !l.ReturnedAt.IsZero()
+}
+
+func (l Loan) IsOverdue(at time.Time) bool {
+	return !l.Returned() && l.DueDate.Before(at)
+}
@@ -0,0 +1,63 @@
+package loandomain
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	ErrPupilIDNotSet         = errors.New("pupil ID not set")
+	ErrClassIDNotSet         = errors.New("class ID not set")
+	ErrBookIDNotSet          = errors.New("book ID not set")
+	ErrInventoryNumberNotSet = errors.New("inventory number not set")
+	ErrDueDateBeforeIssue    = errors.New("the due date must be after the issue date")
+)
+
+func ErrApplyEventLoanAlreadyExists(eventType, loanID string) error {
+	return fmt.Errorf("can not apply %s: loan with ID %s already exists", eventType, loanID)
+}
+
+func ErrApplyEventLoanNotFound(eventType, loanID string) error {
+	return fmt.Errorf("can not apply %s: loan with ID %s not found", eventType, loanID)
+}
+
+func ErrLoanWithIDNotFound(id string) error {
+	return fmt.Errorf("loan with ID %s not found", id)
+}
+
+func ErrLoanAlreadyReturned(id string) error {
+	return fmt.Errorf("loan with ID %s is already returned", id)
+}
+
+func ErrBookAlreadyLentToPupil(bookID, pupilID string) error {
+	return fmt.Errorf("book %s is already lent to pupil %s", bookID, pupilID)
+}
+
+func ErrCopyAlreadyLent(inventoryNumber, pupilID string) error {
+	return fmt.Errorf("copy %s is already lent to pupil %s", inventoryNumber, pupilID)
+}
+
+func ErrCopyOfOtherBook(inventoryNumber, bookID string) error {
+	return fmt.Errorf("copy %s is not a copy of book %s", inventoryNumber, bookID)
+}
+
+func ErrCopyNotInClass(inventoryNumber, classID string) error {
+	return fmt.Errorf("copy %s is not in class %s", inventoryNumber, classID)
+}
+
+func ErrPupilNotInClass(pupilID, classID string) error {
+	return fmt.Errorf("pupil %s is not in class %s", pupilID, classID)
+}
+
+func ErrDueDateNotExtended(current, requested time.Time) error {
+	return fmt.Errorf("the new due date %s must be after the current due date %s", requested.Format("2006-01-02"), current.Format("2006-01-02"))
+}
+
+func ErrMaxExtensionsReached(id string) error {
+	return fmt.Errorf("loan with ID %s can not be extended more than %d times", id, MaxExtensions)
+}
+
+func ErrReturnBeforeIssue(id string) error {
+	return fmt.Errorf("loan with ID %s can not be returned before it was issued", id)
+}
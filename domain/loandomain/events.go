@@ -0,0 +1,84 @@
+package loandomain
+
+import (
+	"time"
+
+	"github.com/kammeph/school-book-storage-service/domain"
+)
+
+var (
+	LoanIssued   = "LOAN_ISSUED"
+	LoanExtended = "LOAN_EXTENDED"
+	LoanReturned = "LOAN_RETURNED"
+)
+
+// LoanIssuedEvent has no inventory number for loans issued before copies were
+// recorded.
+type LoanIssuedEvent struct {
+	SchoolID        string    `json:"schoolId"`
+	LoanID          string    `json:"loanId"`
+	PupilID         string    `json:"pupilId"`
+	ClassID         string    `json:"classId"`
+	BookID          string    `json:"bookId"`
+	InventoryNumber string    `json:"inventoryNumber,omitempty"`
+	IssuedAt        time.Time `json:"issuedAt"`
+	DueDate         time.Time `json:"dueDate"`
+}
+
+func NewLoanIssued(
+	aggregate *SchoolLoanAggregate,
+	loanID, pupilID, classID, bookID, inventoryNumber string,
+	issuedAt, dueDate time.Time,
+) (domain.Event, error) {
+	eventData := LoanIssuedEvent{
+		SchoolID:        aggregate.AggregateID(),
+		LoanID:          loanID,
+		PupilID:         pupilID,
+		ClassID:         classID,
+		BookID:          bookID,
+		InventoryNumber: inventoryNumber,
+		IssuedAt:        issuedAt,
+		DueDate:         dueDate,
+	}
+	event := domain.NewEvent(aggregate, LoanIssued)
+	if err := event.SetJsonData(eventData); err != nil {
+		return nil, err
+	}
+	return event, nil
+}
+
+type LoanExtendedEvent struct {
+	LoanID  string    `json:"loanId"`
+	DueDate time.Time `json:"dueDate"`
+	Reason  string    `json:"reason"`
+}
+
+func NewLoanExtended(aggregate *SchoolLoanAggregate, loanID string, dueDate time.Time, reason string) (domain.Event, error) {
+	eventData := LoanExtendedEvent{
+		LoanID:  loanID,
+		DueDate: dueDate,
+		Reason:  reason,
+	}
+	event := domain.NewEvent(aggregate, LoanExtended)
+	if err := event.SetJsonData(eventData); err != nil {
+		return nil, err
+	}
+	return event, nil
+}
+
+type LoanReturnedEvent struct {
+	LoanID     string    `json:"loanId"`
+	ReturnedAt time.Time `json:"returnedAt"`
+}
+
+func NewLoanReturned(aggregate *SchoolLoanAggregate, loanID string, returnedAt time.Time) (domain.Event, error) {
+	eventData := LoanReturnedEvent{
+		LoanID:     loanID,
+		ReturnedAt: returnedAt,
+	}
+	event := domain.NewEvent(aggregate, LoanReturned)
+	if err := event.SetJsonData(eventData); err != nil {
+		return nil, err
+	}
+	return event, nil
+}
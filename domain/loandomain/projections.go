@@ -0,0 +1,29 @@
+package loandomain
+
+import "time"
+
+type LoanProjection struct {
+	SchoolID        string     `json:"schoolId" bson:"schoolId"`
+	LoanID          string     `json:"loanId" bson:"loanId"`
+	PupilID         string     `json:"pupilId" bson:"pupilId"`
+	ClassID         string     `json:"classId" bson:"classId"`
+	BookID          string     `json:"bookId" bson:"bookId"`
+	InventoryNumber string     `json:"inventoryNumber,omitempty" bson:"inventoryNumber,omitempty"`
+	IssuedAt        time.Time  `json:"issuedAt" bson:"issuedAt"`
+	DueDate         time.Time  `json:"dueDate" bson:"dueDate"`
+	Extensions      int        `json:"extensions" bson:"extensions"`
+	ReturnedAt      *time.Time `json:"returnedAt,omitempty" bson:"returnedAt,omitempty"`
+	Version         int        `json:"version" bson:"version"`
+}
+
+func NewLoanProjection(
+	schoolID, loanID, pupilID, classID, bookID, inventoryNumber string,
+	issuedAt, dueDate time.Time,
+	version int,
+) LoanProjection {
+	return LoanProjection{schoolID, loanID, pupilID, classID, bookID, inventoryNumber, issuedAt, dueDate, 0, nil, version}
+}
+
+func (l LoanProjection) IsOverdue(at time.Time) bool {
+	return l.ReturnedAt == nil && l.DueDate.Before(at)
+}
@@ -0,0 +1,92 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+)
+
+// DefaultCurrency is the currency of amounts that were stored before amounts
+// carried a currency.
+const DefaultCurrency = "EUR"
+
+var (
+	ErrInvalidCurrency  = errors.New("the currency must be an ISO 4217 code")
+	ErrCurrencyMismatch = errors.New("amounts in different currencies can not be combined")
+)
+
+var currencyCode = regexp.MustCompile("^[A-Z]{3}$")
+
+// Money is an amount in the minor unit of its currency, e.g. cents. All
+// currencies are assumed to have two decimal places. The zero value is a
+// neutral amount without currency that can be added to any amount.
+type Money struct {
+	Amount   int64  `json:"amount" bson:"amount"`
+	Currency string `json:"currency" bson:"currency"`
+}
+
+func NewMoney(amount int64, currency string) Money {
+	return Money{Amount: amount, Currency: currency}
+}
+
+// MoneyFromFloat rounds the amount given in major units to the minor unit.
+func MoneyFromFloat(amount float64, currency string) Money {
+	return Money{Amount: int64(math.Round(amount * 100)), Currency: currency}
+}
+
+func (m Money) Validate() error {
+	if !currencyCode.MatchString(m.Currency) {
+		return ErrInvalidCurrency
+	}
+	return nil
+}
+
+func (m Money) IsNegative() bool {
+	return m.Amount < 0
+}
+
+func (m Money) Add(other Money) (Money, error) {
+	if m.Currency == "" {
+		return other, nil
+	}
+	if other.Currency != "" && other.Currency != m.Currency {
+		return Money{}, ErrCurrencyMismatch
+	}
+	return Money{Amount: m.Amount + other.Amount, Currency: m.Currency}, nil
+}
+
+func (m Money) Times(quantity int) Money {
+	return Money{Amount: m.Amount * int64(quantity), Currency: m.Currency}
+}
+
+// Scale multiplies the amount by the factor and rounds to the minor unit.
+func (m Money) Scale(factor float64) Money {
+	return Money{Amount: int64(math.Round(float64(m.Amount) * factor)), Currency: m.Currency}
+}
+
+// Compare returns -1, 0 or 1 if the amount is less than, equal to or greater
+// than the other amount of the same currency.
+func (m Money) Compare(other Money) (int, error) {
+	if m.Currency != other.Currency {
+		return 0, ErrCurrencyMismatch
+	}
+	switch {
+	case m.Amount < other.Amount:
+		return -1, nil
+	case m.Amount > other.Amount:
+		return 1, nil
+	default:
+		return 0, nil
+	}
+}
+
+func (m Money) String() string {
+	sign := ""
+	amount := m.Amount
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+	return fmt.Sprintf("%s%d.%02d %s", sign, amount/100, amount%100, m.Currency)
+}
@@ -0,0 +1,47 @@
+package domain_test
+
+import (
+	"testing"
+
+	"github.com/kammeph/school-book-storage-service/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMoney(t *testing.T) {
+	price := domain.MoneyFromFloat(26.32, "EUR")
+	assert.Equal(t, domain.NewMoney(2632, "EUR"), price)
+	assert.Equal(t, "26.32 EUR", price.String())
+	assert.Equal(t, "-0.05 EUR", domain.NewMoney(-5, "EUR").String())
+	assert.Equal(t, domain.NewMoney(7896, "EUR"), price.Times(3))
+	assert.Equal(t, domain.NewMoney(1316, "EUR"), price.Scale(0.5))
+	assert.NoError(t, price.Validate())
+	assert.Equal(t, domain.ErrInvalidCurrency, domain.NewMoney(100, "eur").Validate())
+
+	sum, err := domain.Money{}.Add(price)
+	assert.NoError(t, err)
+	sum, err = sum.Add(price)
+	assert.NoError(t, err)
+	assert.Equal(t, domain.NewMoney(5264, "EUR"), sum)
+	_, err = sum.Add(domain.NewMoney(100, "CHF"))
+	assert.Equal(t, domain.ErrCurrencyMismatch, err)
+
+	comparison, err := price.Compare(sum)
+	assert.NoError(t, err)
+	assert.Equal(t, -1, comparison)
+	_, err = price.Compare(domain.NewMoney(2632, "CHF"))
+	assert.Equal(t, domain.ErrCurrencyMismatch, err)
+}
+
+func TestMoneyUpcaster(t *testing.T) {
+	domain.RegisterUpcaster("TEST_PRICED", domain.MoneyUpcaster("price"))
+	data := struct {
+		Price domain.Money `json:"price"`
+	}{}
+	legacy := domain.EventModel{Type: "TEST_PRICED", Data: "{\"price\":19.9}"}
+	assert.NoError(t, legacy.GetJsonData(&data))
+	assert.Equal(t, domain.NewMoney(1990, domain.DefaultCurrency), data.Price)
+
+	current := domain.EventModel{Type: "TEST_PRICED", Data: "{\"price\":{\"amount\":500,\"currency\":\"CHF\"}}"}
+	assert.NoError(t, current.GetJsonData(&data))
+	assert.Equal(t, domain.NewMoney(500, "CHF"), data.Price)
+}
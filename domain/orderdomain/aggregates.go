@@ -0,0 +1,105 @@
+package orderdomain
+
+import (
+	"github.com/kammeph/school-book-storage-service/domain"
+	"github.com/kammeph/school-book-storage-service/fp"
+)
+
+type SchoolOrderAggregate struct {
+	*domain.AggregateModel
+	Orders []PurchaseOrder
+}
+
+func NewSchoolOrderAggregate() *SchoolOrderAggregate {
+	aggregate := &SchoolOrderAggregate{
+		Orders: []PurchaseOrder{},
+	}
+	model := domain.NewAggregateModel(aggregate.On)
+	aggregate.AggregateModel = &model
+	return aggregate
+}
+
+func NewSchoolOrderAggregateWithID(id string) *SchoolOrderAggregate {
+	aggregate := NewSchoolOrderAggregate()
+	aggregate.ID = id
+	return aggregate
+}
+
+func (a *SchoolOrderAggregate) On(event domain.Event) error {
+	switch event.EventType() {
+	case OrderCreated:
+		return a.onOrderCreated(event)
+	case OrderSent:
+		return a.onOrderSent(event)
+	case GoodsReceived:
+		return a.onGoodsReceived(event)
+	case OrderCancelled:
+		return a.onOrderCancelled(event)
+	default:
+		return domain.ErrUnknownEvent(event)
+	}
+}
+
+func (a *SchoolOrderAggregate) onOrderCreated(event domain.Event) error {
+	eventData := OrderCreatedEvent{}
+	if err := event.GetJsonData(&eventData); err != nil {
+		return err
+	}
+	if fp.Some(a.Orders, func(o PurchaseOrder) bool { return o.ID == eventData.OrderID }) {
+		return ErrApplyEventOrderAlreadyExists(event.EventType(), eventData.OrderID)
+	}
+	order := NewPurchaseOrder(eventData.OrderID, eventData.Supplier, eventData.Lines, event.EventAt())
+	a.Version = event.EventVersion()
+	a.Orders = append(a.Orders, order)
+	return nil
+}
+
+func (a *SchoolOrderAggregate) onOrderSent(event domain.Event) error {
+	eventData := OrderSentEvent{}
+	if err := event.GetJsonData(&eventData); err != nil {
+		return err
+	}
+	order := fp.Find(a.Orders, func(o PurchaseOrder) bool { return o.ID == eventData.OrderID })
+	if order == nil {
+		return ErrApplyEventOrderNotFound(event.EventType(), eventData.OrderID)
+	}
+	a.Version = event.EventVersion()
+	order.Status = Sent
+	order.UpdatedAt = event.EventAt()
+	return nil
+}
+
+func (a *SchoolOrderAggregate) onGoodsReceived(event domain.Event) error {
+	eventData := GoodsReceivedEvent{}
+	if err := event.GetJsonData(&eventData); err != nil {
+		return err
+	}
+	order := fp.Find(a.Orders, func(o PurchaseOrder) bool { return o.ID == eventData.OrderID })
+	if order == nil {
+		return ErrApplyEventOrderNotFound(event.EventType(), eventData.OrderID)
+	}
+	line := fp.Find(order.Lines, func(l OrderLine) bool { return l.BookID == eventData.BookID })
+	if line == nil {
+		return ErrBookNotOrdered(eventData.OrderID, eventData.BookID)
+	}
+	a.Version = event.EventVersion()
+	line.Received += eventData.Quantity
+	order.Status = ReceiptStatus(order.Lines)
+	order.UpdatedAt = event.EventAt()
+	return nil
+}
+
+func (a *SchoolOrderAggregate) onOrderCancelled(event domain.Event) error {
+	eventData := OrderCancelledEvent{}
+	if err := event.GetJsonData(&eventData); err != nil {
+		return err
+	}
+	order := fp.Find(a.Orders, func(o PurchaseOrder) bool { return o.ID == eventData.OrderID })
+	if order == nil {
+		return ErrApplyEventOrderNotFound(event.EventType(), eventData.OrderID)
+	}
+	a.Version = event.EventVersion()
+	order.Status = Cancelled
+	order.UpdatedAt = event.EventAt()
+	return nil
+}
@@ -0,0 +1,117 @@
+package orderdomain
+
+import (
+	"github.com/google/uuid"
+	"github.com/kammeph/school-book-storage-service/domain"
+	"github.com/kammeph/school-book-storage-service/fp"
+)
+
+// CreateOrder creates a draft order. The received quantities of the lines are
+// ignored.
+func (a *SchoolOrderAggregate) CreateOrder(supplier string, lines []OrderLine) (string, error) {
+	if supplier == "" {
+		return "", ErrSupplierNotSet
+	}
+	if len(lines) == 0 {
+		return "", ErrNoOrderLines
+	}
+	orderLines := []OrderLine{}
+	for _, line := range lines {
+		if line.BookID == "" {
+			return "", ErrBookIDNotSet
+		}
+		if line.Quantity < 1 {
+			return "", ErrQuantityGreaterZero
+		}
+		if err := line.UnitPrice.Validate(); err != nil {
+			return "", err
+		}
+		if line.UnitPrice.IsNegative() {
+			return "", ErrUnitPriceNegative
+		}
+		if line.UnitPrice.Currency != lines[0].UnitPrice.Currency {
+			return "", domain.ErrCurrencyMismatch
+		}
+		if fp.Some(orderLines, func(l OrderLine) bool { return l.BookID == line.BookID }) {
+			return "", ErrBookOrderedTwice(line.BookID)
+		}
+		line.Received = 0
+		orderLines = append(orderLines, line)
+	}
+	orderID := uuid.NewString()
+	event, err := NewOrderCreated(a, orderID, supplier, orderLines)
+	if err != nil {
+		return "", err
+	}
+	if err := a.Apply(event); err != nil {
+		return "", err
+	}
+	return orderID, nil
+}
+
+func (a *SchoolOrderAggregate) SendOrder(orderID string) error {
+	order := fp.Find(a.Orders, func(o PurchaseOrder) bool { return o.ID == orderID })
+	if order == nil {
+		return ErrOrderWithIDNotFound(orderID)
+	}
+	if order.Status != Draft {
+		return ErrInvalidOrderStatus(orderID, order.Status, "send")
+	}
+	event, err := NewOrderSent(a, orderID)
+	if err != nil {
+		return err
+	}
+	return a.Apply(event)
+}
+
+// ReceiveGoods records copies of an ordered book delivered into a storage and
+// returns the line of the book.
+func (a *SchoolOrderAggregate) ReceiveGoods(orderID, storageID, bookID string, quantity int) (OrderLine, error) {
+	order := fp.Find(a.Orders, func(o PurchaseOrder) bool { return o.ID == orderID })
+	if order == nil {
+		return OrderLine{}, ErrOrderWithIDNotFound(orderID)
+	}
+	if order.Status != Sent && order.Status != PartiallyReceived {
+		return OrderLine{}, ErrInvalidOrderStatus(orderID, order.Status, "receive goods for")
+	}
+	if storageID == "" {
+		return OrderLine{}, ErrStorageIDNotSet
+	}
+	if quantity < 1 {
+		return OrderLine{}, ErrQuantityGreaterZero
+	}
+	line := fp.Find(order.Lines, func(l OrderLine) bool { return l.BookID == bookID })
+	if line == nil {
+		return OrderLine{}, ErrBookNotOrdered(orderID, bookID)
+	}
+	if quantity > line.Open() {
+		return OrderLine{}, ErrReceivedMoreThanOrdered(bookID, line.Open(), quantity)
+	}
+	event, err := NewGoodsReceived(a, orderID, storageID, bookID, quantity)
+	if err != nil {
+		return OrderLine{}, err
+	}
+	if err := a.Apply(event); err != nil {
+		return OrderLine{}, err
+	}
+	return *line, nil
+}
+
+// CancelOrder cancels an order of which no goods were received yet.
+func (a *SchoolOrderAggregate) CancelOrder(orderID, reason string) error {
+	if reason == "" {
+		return domain.ErrReasonNotSpecified
+	}
+	order := fp.Find(a.Orders, func(o PurchaseOrder) bool { return o.ID == orderID })
+	if order == nil {
+		return ErrOrderWithIDNotFound(orderID)
+	}
+	if order.Status != Draft && order.Status != Sent {
+		return ErrInvalidOrderStatus(orderID, order.Status, "cancel")
+	}
+	event, err := NewOrderCancelled(a, orderID, reason)
+	if err != nil {
+		return err
+	}
+	return a.Apply(event)
+}
@@ -0,0 +1,208 @@
+package orderdomain_test
+
+import (
+	"testing"
+
+	"github.com/kammeph/school-book-storage-service/domain"
+	"github.com/kammeph/school-book-storage-service/domain/orderdomain"
+	"github.com/kammeph/school-book-storage-service/testing/fixtures"
+	"github.com/stretchr/testify/assert"
+)
+
+func initSchoolOrderAggregate(orders []orderdomain.PurchaseOrder) *orderdomain.SchoolOrderAggregate {
+	aggregate := orderdomain.NewSchoolOrderAggregateWithID("school")
+	aggregate.Orders = orders
+	return aggregate
+}
+
+func order(status orderdomain.OrderStatus, received int) orderdomain.PurchaseOrder {
+	return orderdomain.PurchaseOrder{
+		ID:       "order",
+		Supplier: "supplier",
+		Lines: []orderdomain.OrderLine{
+			{BookID: "math", Quantity: 10, UnitPrice: fixtures.Euro(1250), Received: received},
+			{BookID: "art", Quantity: 5, UnitPrice: fixtures.Euro(800)},
+		},
+		Status: status,
+	}
+}
+
+func TestCreateOrder(t *testing.T) {
+	tests := []struct {
+		name     string
+		supplier string
+		lines    []orderdomain.OrderLine
+		err      error
+	}{
+		{
+			name:     "create order",
+			supplier: "supplier",
+			lines:    []orderdomain.OrderLine{{BookID: "math", Quantity: 10, UnitPrice: fixtures.Euro(1250), Received: 3}},
+		},
+		{
+			name:  "supplier not set",
+			lines: []orderdomain.OrderLine{{BookID: "math", Quantity: 10}},
+			err:   orderdomain.ErrSupplierNotSet,
+		},
+		{
+			name:     "no lines",
+			supplier: "supplier",
+			lines:    []orderdomain.OrderLine{},
+			err:      orderdomain.ErrNoOrderLines,
+		},
+		{
+			name:     "quantity not positive",
+			supplier: "supplier",
+			lines:    []orderdomain.OrderLine{{BookID: "math"}},
+			err:      orderdomain.ErrQuantityGreaterZero,
+		},
+		{
+			name:     "negative unit price",
+			supplier: "supplier",
+			lines:    []orderdomain.OrderLine{{BookID: "math", Quantity: 1, UnitPrice: fixtures.Euro(-100)}},
+			err:      orderdomain.ErrUnitPriceNegative,
+		},
+		{
+			name:     "book ordered twice",
+			supplier: "supplier",
+			lines:    []orderdomain.OrderLine{{BookID: "math", Quantity: 1, UnitPrice: fixtures.Euro(800)}, {BookID: "math", Quantity: 2, UnitPrice: fixtures.Euro(800)}},
+			err:      orderdomain.ErrBookOrderedTwice("math"),
+		},
+		{
+			name:     "lines in different currencies",
+			supplier: "supplier",
+			lines:    []orderdomain.OrderLine{{BookID: "math", Quantity: 1, UnitPrice: fixtures.Euro(800)}, {BookID: "art", Quantity: 2, UnitPrice: domain.NewMoney(900, "CHF")}},
+			err:      domain.ErrCurrencyMismatch,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			aggregate := initSchoolOrderAggregate([]orderdomain.PurchaseOrder{})
+			orderID, err := aggregate.CreateOrder(test.supplier, test.lines)
+			if test.err != nil {
+				assert.Equal(t, test.err, err)
+				assert.Empty(t, aggregate.Orders)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, orderID, aggregate.Orders[0].ID)
+			assert.Equal(t, orderdomain.Draft, aggregate.Orders[0].Status)
+			assert.Equal(t, 0, aggregate.Orders[0].Lines[0].Received)
+			assert.Equal(t, fixtures.Euro(12500), aggregate.Orders[0].Total())
+		})
+	}
+}
+
+func TestSendOrder(t *testing.T) {
+	aggregate := initSchoolOrderAggregate([]orderdomain.PurchaseOrder{order(orderdomain.Draft, 0)})
+	assert.NoError(t, aggregate.SendOrder("order"))
+	assert.Equal(t, orderdomain.Sent, aggregate.Orders[0].Status)
+	assert.Equal(t, orderdomain.ErrInvalidOrderStatus("order", orderdomain.Sent, "send"), aggregate.SendOrder("order"))
+	assert.Equal(t, orderdomain.ErrOrderWithIDNotFound("unknown"), aggregate.SendOrder("unknown"))
+}
+
+func TestReceiveGoods(t *testing.T) {
+	tests := []struct {
+		name      string
+		order     orderdomain.PurchaseOrder
+		storageID string
+		bookID    string
+		quantity  int
+		status    orderdomain.OrderStatus
+		err       error
+	}{
+		{
+			name:      "receive part of the order",
+			order:     order(orderdomain.Sent, 0),
+			storageID: "storage",
+			bookID:    "math",
+			quantity:  10,
+			status:    orderdomain.PartiallyReceived,
+		},
+		{
+			name:      "receive rest of the order",
+			order:     order(orderdomain.PartiallyReceived, 10),
+			storageID: "storage",
+			bookID:    "art",
+			quantity:  5,
+			status:    orderdomain.Received,
+		},
+		{
+			name:      "order not sent",
+			order:     order(orderdomain.Draft, 0),
+			storageID: "storage",
+			bookID:    "math",
+			quantity:  10,
+			err:       orderdomain.ErrInvalidOrderStatus("order", orderdomain.Draft, "receive goods for"),
+		},
+		{
+			name:     "storage not set",
+			order:    order(orderdomain.Sent, 0),
+			bookID:   "math",
+			quantity: 10,
+			err:      orderdomain.ErrStorageIDNotSet,
+		},
+		{
+			name:      "book not ordered",
+			order:     order(orderdomain.Sent, 0),
+			storageID: "storage",
+			bookID:    "music",
+			quantity:  1,
+			err:       orderdomain.ErrBookNotOrdered("order", "music"),
+		},
+		{
+			name:      "more than ordered",
+			order:     order(orderdomain.PartiallyReceived, 8),
+			storageID: "storage",
+			bookID:    "math",
+			quantity:  3,
+			err:       orderdomain.ErrReceivedMoreThanOrdered("math", 2, 3),
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			aggregate := initSchoolOrderAggregate([]orderdomain.PurchaseOrder{test.order})
+			line, err := aggregate.ReceiveGoods("order", test.storageID, test.bookID, test.quantity)
+			if test.err != nil {
+				assert.Equal(t, test.err, err)
+				assert.Empty(t, aggregate.DomainEvents())
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.bookID, line.BookID)
+			assert.Equal(t, 0, line.Open())
+			assert.Equal(t, test.status, aggregate.Orders[0].Status)
+		})
+	}
+}
+
+func TestCancelOrder(t *testing.T) {
+	tests := []struct {
+		name   string
+		order  orderdomain.PurchaseOrder
+		reason string
+		err    error
+	}{
+		{name: "cancel draft", order: order(orderdomain.Draft, 0), reason: "not needed"},
+		{name: "cancel sent order", order: order(orderdomain.Sent, 0), reason: "not delivered"},
+		{name: "reason not set", order: order(orderdomain.Sent, 0), err: domain.ErrReasonNotSpecified},
+		{
+			name:   "goods already received",
+			order:  order(orderdomain.PartiallyReceived, 2),
+			reason: "not needed",
+			err:    orderdomain.ErrInvalidOrderStatus("order", orderdomain.PartiallyReceived, "cancel"),
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			aggregate := initSchoolOrderAggregate([]orderdomain.PurchaseOrder{test.order})
+			err := aggregate.CancelOrder("order", test.reason)
+			if test.err != nil {
+				assert.Equal(t, test.err, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, orderdomain.Cancelled, aggregate.Orders[0].Status)
+		})
+	}
+}
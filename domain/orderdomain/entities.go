@@ -0,0 +1,76 @@
+package orderdomain
+
+import (
+	"time"
+
+	"github.com/kammeph/school-book-storage-service/domain"
+)
+
+type OrderStatus string
+
+const (
+	Draft             OrderStatus = "draft"
+	Sent              OrderStatus = "sent"
+	PartiallyReceived OrderStatus = "partially received"
+	Received          OrderStatus = "received"
+	Cancelled         OrderStatus = "cancelled"
+)
+
+type OrderLine struct {
+	BookID    string       `json:"bookId" bson:"bookId"`
+	Isbn      domain.Isbn  `json:"isbn" bson:"isbn"`
+	Title     string       `json:"title" bson:"title"`
+	Quantity  int          `json:"quantity" bson:"quantity"`
+	UnitPrice domain.Money `json:"unitPrice" bson:"unitPrice"`
+	Received  int          `json:"received" bson:"received"`
+}
+
+// Open returns how many copies of the line are still to be received.
+func (l OrderLine) Open() int {
+	return l.Quantity - l.Received
+}
+
+type PurchaseOrder struct {
+	ID        string
+	Supplier  string
+	Lines     []OrderLine
+	Status    OrderStatus
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func NewPurchaseOrder(id, supplier string, lines []OrderLine, timeStamp time.Time) PurchaseOrder {
+	return PurchaseOrder{
+		ID:        id,
+		Supplier:  supplier,
+		Lines:     lines,
+		Status:    Draft,
+		CreatedAt: timeStamp,
+	}
+}
+
+// Total returns the price of all ordered copies.
+func (o PurchaseOrder) Total() domain.Money {
+	return Total(o.Lines)
+}
+
+// Total sums up the lines of an order, which all share one currency.
+func Total(lines []OrderLine) domain.Money {
+	total := domain.Money{}
+	for _, line := range lines {
+		total.Amount += line.UnitPrice.Times(line.Quantity).Amount
+		total.Currency = line.UnitPrice.Currency
+	}
+	return total
+}
+
+// ReceiptStatus returns the status of an order whose goods are received
+// according to the lines.
+func ReceiptStatus(lines []OrderLine) OrderStatus {
+	for _, line := range lines {
+		if line.Open() > 0 {
+			return PartiallyReceived
+		}
+	}
+	return Received
+}
@@ -0,0 +1,43 @@
+package orderdomain
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrSupplierNotSet      = errors.New("supplier not set")
+	ErrNoOrderLines        = errors.New("an order needs at least one line")
+	ErrBookIDNotSet        = errors.New("book ID not set")
+	ErrStorageIDNotSet     = errors.New("storage ID not set")
+	ErrQuantityGreaterZero = errors.New("the quantity must be greater than zero")
+	ErrUnitPriceNegative   = errors.New("the unit price must not be negative")
+)
+
+func ErrApplyEventOrderAlreadyExists(eventType, orderID string) error {
+	return fmt.Errorf("can not apply %s: purchase order with ID %s already exists", eventType, orderID)
+}
+
+func ErrApplyEventOrderNotFound(eventType, orderID string) error {
+	return fmt.Errorf("can not apply %s: purchase order with ID %s not found", eventType, orderID)
+}
+
+func ErrOrderWithIDNotFound(id string) error {
+	return fmt.Errorf("purchase order with ID %s not found", id)
+}
+
+func ErrBookOrderedTwice(bookID string) error {
+	return fmt.Errorf("book %s is ordered in more than one line", bookID)
+}
+
+func ErrBookNotOrdered(orderID, bookID string) error {
+	return fmt.Errorf("book %s is not ordered with purchase order %s", bookID, orderID)
+}
+
+func ErrInvalidOrderStatus(id string, status OrderStatus, action string) error {
+	return fmt.Errorf("can not %s purchase order %s with status %s", action, id, status)
+}
+
+func ErrReceivedMoreThanOrdered(bookID string, open, received int) error {
+	return fmt.Errorf("can not receive %d copies of book %s, only %d copies are open", received, bookID, open)
+}
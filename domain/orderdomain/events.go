@@ -0,0 +1,86 @@
+package orderdomain
+
+import (
+	"github.com/kammeph/school-book-storage-service/domain"
+)
+
+var (
+	OrderCreated   = "PURCHASE_ORDER_CREATED"
+	OrderSent      = "PURCHASE_ORDER_SENT"
+	GoodsReceived  = "PURCHASE_ORDER_GOODS_RECEIVED"
+	OrderCancelled = "PURCHASE_ORDER_CANCELLED"
+)
+
+type OrderCreatedEvent struct {
+	SchoolID string      `json:"schoolId"`
+	OrderID  string      `json:"orderId"`
+	Supplier string      `json:"supplier"`
+	Lines    []OrderLine `json:"lines"`
+}
+
+func NewOrderCreated(aggregate *SchoolOrderAggregate, orderID, supplier string, lines []OrderLine) (domain.Event, error) {
+	eventData := OrderCreatedEvent{
+		SchoolID: aggregate.AggregateID(),
+		OrderID:  orderID,
+		Supplier: supplier,
+		Lines:    lines,
+	}
+	event := domain.NewEvent(aggregate, OrderCreated)
+	if err := event.SetJsonData(eventData); err != nil {
+		return nil, err
+	}
+	return event, nil
+}
+
+type OrderSentEvent struct {
+	OrderID string `json:"orderId"`
+}
+
+func NewOrderSent(aggregate *SchoolOrderAggregate, orderID string) (domain.Event, error) {
+	eventData := OrderSentEvent{
+		OrderID: orderID,
+	}
+	event := domain.NewEvent(aggregate, OrderSent)
+	if err := event.SetJsonData(eventData); err != nil {
+		return nil, err
+	}
+	return event, nil
+}
+
+type GoodsReceivedEvent struct {
+	OrderID   string `json:"orderId"`
+	StorageID string `json:"storageId"`
+	BookID    string `json:"bookId"`
+	Quantity  int    `json:"quantity"`
+}
+
+func NewGoodsReceived(aggregate *SchoolOrderAggregate, orderID, storageID, bookID string, quantity int) (domain.Event, error) {
+	eventData := GoodsReceivedEvent{
+		OrderID:   orderID,
+		StorageID: storageID,
+		BookID:    bookID,
+		Quantity:  quantity,
+	}
+	event := domain.NewEvent(aggregate, GoodsReceived)
+	if err := event.SetJsonData(eventData); err != nil {
+		return nil, err
+	}
+	return event, nil
+}
+
+type OrderCancelledEvent struct {
+	OrderID string `json:"orderId"`
+	Reason  string `json:"reason"`
+}
+
+func NewOrderCancelled(aggregate *SchoolOrderAggregate, orderID, reason string) (domain.Event, error) {
+	eventData := OrderCancelledEvent{
+		OrderID: orderID,
+		Reason:  reason,
+	}
+	event := domain.NewEvent(aggregate, OrderCancelled)
+	if err := event.SetJsonData(eventData); err != nil {
+		return nil, err
+	}
+	return event, nil
+}
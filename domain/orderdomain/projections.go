@@ -0,0 +1,27 @@
+package orderdomain
+
+import (
+	"time"
+
+	"github.com/kammeph/school-book-storage-service/domain"
+)
+
+type PurchaseOrderProjection struct {
+	SchoolID  string       `json:"schoolId" bson:"schoolId"`
+	OrderID   string       `json:"orderId" bson:"orderId"`
+	Supplier  string       `json:"supplier" bson:"supplier"`
+	Lines     []OrderLine  `json:"lines" bson:"lines"`
+	Status    OrderStatus  `json:"status" bson:"status"`
+	Total     domain.Money `json:"total" bson:"total"`
+	CreatedAt time.Time    `json:"createdAt" bson:"createdAt"`
+	Version   int          `json:"version" bson:"version"`
+}
+
+func NewPurchaseOrderProjection(schoolID, orderID, supplier string, lines []OrderLine, createdAt time.Time, version int) PurchaseOrderProjection {
+	return PurchaseOrderProjection{schoolID, orderID, supplier, lines, Draft, Total(lines), createdAt, version}
+}
+
+// IsOpen reports whether goods of the order are still to be received.
+func (o PurchaseOrderProjection) IsOpen() bool {
+	return o.Status == Draft || o.Status == Sent || o.Status == PartiallyReceived
+}
@@ -0,0 +1,39 @@
+package orderdomain
+
+import (
+	"encoding/json"
+
+	"github.com/kammeph/school-book-storage-service/domain"
+)
+
+// Unit prices used to be stored as plain numbers without a currency and ISBNs
+// as entered by the user.
+func init() {
+	domain.RegisterUpcaster(OrderCreated, upcastLines)
+}
+
+func upcastLines(data map[string]json.RawMessage) error {
+	if _, ok := data["lines"]; !ok {
+		return nil
+	}
+	lines := []map[string]json.RawMessage{}
+	if err := json.Unmarshal(data["lines"], &lines); err != nil {
+		return err
+	}
+	upcastUnitPrice := domain.MoneyUpcaster("unitPrice")
+	upcastIsbn := domain.IsbnUpcaster("isbn")
+	for _, line := range lines {
+		if err := upcastUnitPrice(line); err != nil {
+			return err
+		}
+		if err := upcastIsbn(line); err != nil {
+			return err
+		}
+	}
+	linesData, err := json.Marshal(lines)
+	if err != nil {
+		return err
+	}
+	data["lines"] = linesData
+	return nil
+}
@@ -0,0 +1,90 @@
+package pupildomain
+
+import (
+	"github.com/kammeph/school-book-storage-service/domain"
+	"github.com/kammeph/school-book-storage-service/fp"
+)
+
+type SchoolPupilAggregate struct {
+	*domain.AggregateModel
+	Pupils []Pupil
+}
+
+func NewSchoolPupilAggregate() *SchoolPupilAggregate {
+	aggregate := &SchoolPupilAggregate{
+		Pupils: []Pupil{},
+	}
+	model := domain.NewAggregateModel(aggregate.On)
+	aggregate.AggregateModel = &model
+	return aggregate
+}
+
+func NewSchoolPupilAggregateWithID(id string) *SchoolPupilAggregate {
+	aggregate := NewSchoolPupilAggregate()
+	aggregate.ID = id
+	return aggregate
+}
+
+func (a *SchoolPupilAggregate) On(event domain.Event) error {
+	switch event.EventType() {
+	case PupilEnrolled:
+		return a.onPupilEnrolled(event)
+	case PupilMoved:
+		return a.onPupilMoved(event)
+	case PupilLeft:
+		return a.onPupilLeft(event)
+	default:
+		return domain.ErrUnknownEvent(event)
+	}
+}
+
+func (a *SchoolPupilAggregate) onPupilEnrolled(event domain.Event) error {
+	eventData := PupilEnrolledEvent{}
+	if err := event.GetJsonData(&eventData); err != nil {
+		return err
+	}
+	if fp.Some(a.Pupils, func(p Pupil) bool { return p.ID == eventData.PupilID }) {
+		return ErrApplyEventPupilAlreadyExists(event.EventType(), eventData.PupilID)
+	}
+	pupil := NewPupil(
+		eventData.PupilID,
+		eventData.FirstName,
+		eventData.LastName,
+		eventData.DateOfBirth,
+		eventData.ClassID,
+		eventData.EntryDate,
+		event.EventAt())
+	a.Version = event.EventVersion()
+	a.Pupils = append(a.Pupils, pupil)
+	return nil
+}
+
+func (a *SchoolPupilAggregate) onPupilMoved(event domain.Event) error {
+	eventData := PupilMovedEvent{}
+	if err := event.GetJsonData(&eventData); err != nil {
+		return err
+	}
+	pupil := fp.Find(a.Pupils, func(p Pupil) bool { return p.ID == eventData.PupilID })
+	if pupil == nil {
+		return ErrApplyEventPupilNotFound(event.EventType(), eventData.PupilID)
+	}
+	a.Version = event.EventVersion()
+	pupil.ClassID = eventData.ToClassID
+	pupil.UpdatedAt = event.EventAt()
+	return nil
+}
+
+func (a *SchoolPupilAggregate) onPupilLeft(event domain.Event) error {
+	eventData := PupilLeftEvent{}
+	if err := event.GetJsonData(&eventData); err != nil {
+		return err
+	}
+	pupil := fp.Find(a.Pupils, func(p Pupil) bool { return p.ID == eventData.PupilID })
+	if pupil == nil {
+		return ErrApplyEventPupilNotFound(event.EventType(), eventData.PupilID)
+	}
+	a.Version = event.EventVersion()
+	pupil.ExitDate = eventData.ExitDate
+	pupil.UpdatedAt = event.EventAt()
+	return nil
+}
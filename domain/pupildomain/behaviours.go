@@ -0,0 +1,101 @@
+package pupildomain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/kammeph/school-book-storage-service/domain"
+	"github.com/kammeph/school-book-storage-service/fp"
+)
+
+func (a *SchoolPupilAggregate) EnrolPupil(firstName, lastName string, dateOfBirth time.Time, classID string, entryDate time.Time) (string, error) {
+	if firstName == "" {
+		return "", ErrFirstNameNotSet
+	}
+	if lastName == "" {
+		return "", ErrLastNameNotSet
+	}
+	if dateOfBirth.IsZero() {
+		return "", ErrDateOfBirthNotSet
+	}
+	if classID == "" {
+		return "", ErrClassIDNotSet
+	}
+	if !entryDate.After(dateOfBirth) {
+		return "", ErrEntryBeforeBirth
+	}
+	if fp.Some(a.Pupils, func(p Pupil) bool {
+		return !p.Left() &&
+			p.FirstName == firstName &&
+			p.LastName == lastName &&
+			p.DateOfBirth.Equal(dateOfBirth)
+	}) {
+		return "", ErrPupilAlreadyEnrolled
+	}
+	pupilID := uuid.NewString()
+	event, err := NewPupilEnrolled(a, pupilID, firstName, lastName, dateOfBirth, classID, entryDate)
+	if err != nil {
+		return "", err
+	}
+	if err := a.Apply(event); err != nil {
+		return "", err
+	}
+	return pupilID, nil
+}
+
+// MovePupil moves the pupil to another class and returns the class the pupil
+// was in before.
+func (a *SchoolPupilAggregate) MovePupil(pupilID, classID, reason string) (string, error) {
+	if classID == "" {
+		return "", ErrClassIDNotSet
+	}
+	if reason == "" {
+		return "", domain.ErrReasonNotSpecified
+	}
+	pupil := fp.Find(a.Pupils, func(p Pupil) bool { return p.ID == pupilID })
+	if pupil == nil {
+		return "", ErrPupilWithIDNotFound(pupilID)
+	}
+	if pupil.Left() {
+		return "", ErrPupilAlreadyLeft(pupilID)
+	}
+	if pupil.ClassID == classID {
+		return "", ErrPupilAlreadyInClass
+	}
+	fromClassID := pupil.ClassID
+	event, err := NewPupilMoved(a, pupilID, fromClassID, classID, reason)
+	if err != nil {
+		return "", err
+	}
+	if err := a.Apply(event); err != nil {
+		return "", err
+	}
+	return fromClassID, nil
+}
+
+// LeaveSchool records the exit of the pupil and returns the class the pupil
+// was in.
+func (a *SchoolPupilAggregate) LeaveSchool(pupilID string, exitDate time.Time, reason string) (string, error) {
+	if reason == "" {
+		return "", domain.ErrReasonNotSpecified
+	}
+	pupil := fp.Find(a.Pupils, func(p Pupil) bool { return p.ID == pupilID })
+	if pupil == nil {
+		return "", ErrPupilWithIDNotFound(pupilID)
+	}
+	if pupil.Left() {
+		return "", ErrPupilAlreadyLeft(pupilID)
+	}
+	if exitDate.Before(pupil.EntryDate) {
+		return "", ErrExitBeforeEntry
+	}
+	classID := pupil.ClassID
+	event, err := NewPupilLeft(a, pupilID, classID, exitDate, reason)
+	if err != nil {
+		return "", err
+	}
+	if err := a.Apply(event); err != nil {
+		return "", err
+	}
+	return classID, nil
+}
@@ -0,0 +1,265 @@
+package pupildomain_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kammeph/school-book-storage-service/domain"
+	"github.com/kammeph/school-book-storage-service/domain/pupildomain"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	dateOfBirth = time.Date(2012, 3, 14, 0, 0, 0, 0, time.UTC)
+	entryDate   = time.Date(2022, 8, 1, 0, 0, 0, 0, time.UTC)
+)
+
+func initSchoolPupilAggregate(pupils []pupildomain.Pupil) *pupildomain.SchoolPupilAggregate {
+	aggregate := pupildomain.NewSchoolPupilAggregateWithID("school")
+	aggregate.Pupils = pupils
+	return aggregate
+}
+
+func enrolledPupil() pupildomain.Pupil {
+	return pupildomain.NewPupil("pupil", "Ada", "Lovelace", dateOfBirth, "5a", entryDate, entryDate)
+}
+
+func TestEnrolPupil(t *testing.T) {
+	left := enrolledPupil()
+	left.ExitDate = entryDate.AddDate(1, 0, 0)
+	tests := []struct {
+		name        string
+		pupils      []pupildomain.Pupil
+		firstName   string
+		lastName    string
+		dateOfBirth time.Time
+		classID     string
+		err         error
+	}{
+		{
+			name:        "enrol pupil",
+			pupils:      []pupildomain.Pupil{},
+			firstName:   "Ada",
+			lastName:    "Lovelace",
+			dateOfBirth: dateOfBirth,
+			classID:     "5a",
+		},
+		{
+			name:        "enrol pupil again after leaving",
+			pupils:      []pupildomain.Pupil{left},
+			firstName:   "Ada",
+			lastName:    "Lovelace",
+			dateOfBirth: dateOfBirth,
+			classID:     "5a",
+		},
+		{
+			name:        "first name not set",
+			pupils:      []pupildomain.Pupil{},
+			lastName:    "Lovelace",
+			dateOfBirth: dateOfBirth,
+			classID:     "5a",
+			err:         pupildomain.ErrFirstNameNotSet,
+		},
+		{
+			name:        "last name not set",
+			pupils:      []pupildomain.Pupil{},
+			firstName:   "Ada",
+			dateOfBirth: dateOfBirth,
+			classID:     "5a",
+			err:         pupildomain.ErrLastNameNotSet,
+		},
+		{
+			name:      "date of birth not set",
+			pupils:    []pupildomain.Pupil{},
+			firstName: "Ada",
+			lastName:  "Lovelace",
+			classID:   "5a",
+			err:       pupildomain.ErrDateOfBirthNotSet,
+		},
+		{
+			name:        "class not set",
+			pupils:      []pupildomain.Pupil{},
+			firstName:   "Ada",
+			lastName:    "Lovelace",
+			dateOfBirth: dateOfBirth,
+			err:         pupildomain.ErrClassIDNotSet,
+		},
+		{
+			name:        "entry before birth",
+			pupils:      []pupildomain.Pupil{},
+			firstName:   "Ada",
+			lastName:    "Lovelace",
+			dateOfBirth: entryDate.AddDate(0, 0, 1),
+			classID:     "5a",
+			err:         pupildomain.ErrEntryBeforeBirth,
+		},
+		{
+			name:        "pupil already enrolled",
+			pupils:      []pupildomain.Pupil{enrolledPupil()},
+			firstName:   "Ada",
+			lastName:    "Lovelace",
+			dateOfBirth: dateOfBirth,
+			classID:     "5b",
+			err:         pupildomain.ErrPupilAlreadyEnrolled,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			aggregate := initSchoolPupilAggregate(test.pupils)
+			pupilID, err := aggregate.EnrolPupil(test.firstName, test.lastName, test.dateOfBirth, test.classID, entryDate)
+			if test.err != nil {
+				assert.Equal(t, test.err, err)
+				assert.Len(t, aggregate.DomainEvents(), 0)
+				return
+			}
+			assert.NoError(t, err)
+			assert.NotEqual(t, "", pupilID)
+			assert.Len(t, aggregate.DomainEvents(), 1)
+			assert.Equal(t, pupildomain.PupilEnrolled, aggregate.DomainEvents()[0].EventType())
+			pupil := aggregate.Pupils[len(aggregate.Pupils)-1]
+			assert.Equal(t, pupilID, pupil.ID)
+			assert.Equal(t, test.classID, pupil.ClassID)
+		})
+	}
+}
+
+func TestMovePupil(t *testing.T) {
+	left := enrolledPupil()
+	left.ExitDate = entryDate.AddDate(1, 0, 0)
+	tests := []struct {
+		name    string
+		pupil   pupildomain.Pupil
+		pupilID string
+		classID string
+		reason  string
+		err     error
+	}{
+		{
+			name:    "move pupil",
+			pupil:   enrolledPupil(),
+			pupilID: "pupil",
+			classID: "5b",
+			reason:  "language course",
+		},
+		{
+			name:    "class not set",
+			pupil:   enrolledPupil(),
+			pupilID: "pupil",
+			reason:  "language course",
+			err:     pupildomain.ErrClassIDNotSet,
+		},
+		{
+			name:    "reason not set",
+			pupil:   enrolledPupil(),
+			pupilID: "pupil",
+			classID: "5b",
+			err:     domain.ErrReasonNotSpecified,
+		},
+		{
+			name:    "pupil not found",
+			pupil:   enrolledPupil(),
+			pupilID: "unknown",
+			classID: "5b",
+			reason:  "language course",
+			err:     pupildomain.ErrPupilWithIDNotFound("unknown"),
+		},
+		{
+			name:    "pupil already left",
+			pupil:   left,
+			pupilID: "pupil",
+			classID: "5b",
+			reason:  "language course",
+			err:     pupildomain.ErrPupilAlreadyLeft("pupil"),
+		},
+		{
+			name:    "pupil already in class",
+			pupil:   enrolledPupil(),
+			pupilID: "pupil",
+			classID: "5a",
+			reason:  "language course",
+			err:     pupildomain.ErrPupilAlreadyInClass,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			aggregate := initSchoolPupilAggregate([]pupildomain.Pupil{test.pupil})
+			fromClassID, err := aggregate.MovePupil(test.pupilID, test.classID, test.reason)
+			if test.err != nil {
+				assert.Equal(t, test.err, err)
+				assert.Len(t, aggregate.DomainEvents(), 0)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, "5a", fromClassID)
+			assert.Equal(t, pupildomain.PupilMoved, aggregate.DomainEvents()[0].EventType())
+			assert.Equal(t, test.classID, aggregate.Pupils[0].ClassID)
+		})
+	}
+}
+
+func TestLeaveSchool(t *testing.T) {
+	left := enrolledPupil()
+	left.ExitDate = entryDate.AddDate(1, 0, 0)
+	tests := []struct {
+		name     string
+		pupil    pupildomain.Pupil
+		pupilID  string
+		exitDate time.Time
+		reason   string
+		err      error
+	}{
+		{
+			name:     "leave school",
+			pupil:    enrolledPupil(),
+			pupilID:  "pupil",
+			exitDate: entryDate.AddDate(4, 0, 0),
+			reason:   "graduated",
+		},
+		{
+			name:     "reason not set",
+			pupil:    enrolledPupil(),
+			pupilID:  "pupil",
+			exitDate: entryDate.AddDate(4, 0, 0),
+			err:      domain.ErrReasonNotSpecified,
+		},
+		{
+			name:     "pupil not found",
+			pupil:    enrolledPupil(),
+			pupilID:  "unknown",
+			exitDate: entryDate.AddDate(4, 0, 0),
+			reason:   "graduated",
+			err:      pupildomain.ErrPupilWithIDNotFound("unknown"),
+		},
+		{
+			name:     "pupil already left",
+			pupil:    left,
+			pupilID:  "pupil",
+			exitDate: entryDate.AddDate(4, 0, 0),
+			reason:   "graduated",
+			err:      pupildomain.ErrPupilAlreadyLeft("pupil"),
+		},
+		{
+			name:     "exit before entry",
+			pupil:    enrolledPupil(),
+			pupilID:  "pupil",
+			exitDate: entryDate.AddDate(0, 0, -1),
+			reason:   "graduated",
+			err:      pupildomain.ErrExitBeforeEntry,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			aggregate := initSchoolPupilAggregate([]pupildomain.Pupil{test.pupil})
+			classID, err := aggregate.LeaveSchool(test.pupilID, test.exitDate, test.reason)
+			if test.err != nil {
+				assert.Equal(t, test.err, err)
+				assert.Len(t, aggregate.DomainEvents(), 0)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, "5a", classID)
+			assert.Equal(t, pupildomain.PupilLeft, aggregate.DomainEvents()[0].EventType())
+			assert.True(t, aggregate.Pupils[0].Left())
+		})
+	}
+}
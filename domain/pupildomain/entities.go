@@ -0,0 +1,31 @@
+package pupildomain
+
+import "time"
+
+type Pupil struct {
+	ID          string
+	FirstName   string
+	LastName    string
+	DateOfBirth time.Time
+	ClassID     string
+	EntryDate   time.Time
+	ExitDate    time.Time
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+func NewPupil(id, firstName, lastName string, dateOfBirth time.Time, classID string, entryDate, timeStamp time.Time) Pupil {
+	return Pupil{
+		ID:          id,
+		FirstName:   firstName,
+		LastName:    lastName,
+		DateOfBirth: dateOfBirth,
+		ClassID:     classID,
+		EntryDate:   entryDate,
+		CreatedAt:   timeStamp,
+	}
+}
+
+func (p Pupil) Left() bool {
+	return !p.ExitDate.IsZero()
+}
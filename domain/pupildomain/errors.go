@@ -0,0 +1,33 @@
+package pupildomain
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrFirstNameNotSet      = errors.New("first name not set")
+	ErrLastNameNotSet       = errors.New("last name not set")
+	ErrDateOfBirthNotSet    = errors.New("date of birth not set")
+	ErrClassIDNotSet        = errors.New("class ID not set")
+	ErrEntryBeforeBirth     = errors.New("the entry date must be after the date of birth")
+	ErrExitBeforeEntry      = errors.New("the exit date must not be before the entry date")
+	ErrPupilAlreadyEnrolled = errors.New("a pupil with the same name and date of birth is already enrolled")
+	ErrPupilAlreadyInClass  = errors.New("the pupil is already in this class")
+)
+
+func ErrApplyEventPupilAlreadyExists(eventType, pupilID string) error {
+	return fmt.Errorf("can not apply %s: pupil with ID %s already exists", eventType, pupilID)
+}
+
+func ErrApplyEventPupilNotFound(eventType, pupilID string) error {
+	return fmt.Errorf("can not apply %s: pupil with ID %s not found", eventType, pupilID)
+}
+
+func ErrPupilWithIDNotFound(id string) error {
+	return fmt.Errorf("pupil with ID %s not found", id)
+}
+
+func ErrPupilAlreadyLeft(id string) error {
+	return fmt.Errorf("pupil with ID %s already left the school", id)
+}
@@ -0,0 +1,87 @@
+package pupildomain
+
+import (
+	"time"
+
+	"github.com/kammeph/school-book-storage-service/domain"
+)
+
+var (
+	PupilEnrolled = "PUPIL_ENROLLED"
+	PupilMoved    = "PUPIL_MOVED"
+	PupilLeft     = "PUPIL_LEFT"
+)
+
+type PupilEnrolledEvent struct {
+	SchoolID    string    `json:"schoolId"`
+	PupilID     string    `json:"pupilId"`
+	FirstName   string    `json:"firstName"`
+	LastName    string    `json:"lastName"`
+	DateOfBirth time.Time `json:"dateOfBirth"`
+	ClassID     string    `json:"classId"`
+	EntryDate   time.Time `json:"entryDate"`
+}
+
+func NewPupilEnrolled(
+	aggregate *SchoolPupilAggregate,
+	pupilID, firstName, lastName string,
+	dateOfBirth time.Time,
+	classID string,
+	entryDate time.Time) (domain.Event, error) {
+	eventData := PupilEnrolledEvent{
+		SchoolID:    aggregate.AggregateID(),
+		PupilID:     pupilID,
+		FirstName:   firstName,
+		LastName:    lastName,
+		DateOfBirth: dateOfBirth,
+		ClassID:     classID,
+		EntryDate:   entryDate,
+	}
+	event := domain.NewEvent(aggregate, PupilEnrolled)
+	if err := event.SetJsonData(eventData); err != nil {
+		return nil, err
+	}
+	return event, nil
+}
+
+type PupilMovedEvent struct {
+	PupilID     string `json:"pupilId"`
+	FromClassID string `json:"fromClassId"`
+	ToClassID   string `json:"toClassId"`
+	Reason      string `json:"reason"`
+}
+
+func NewPupilMoved(aggregate *SchoolPupilAggregate, pupilID, fromClassID, toClassID, reason string) (domain.Event, error) {
+	eventData := PupilMovedEvent{
+		PupilID:     pupilID,
+		FromClassID: fromClassID,
+		ToClassID:   toClassID,
+		Reason:      reason,
+	}
+	event := domain.NewEvent(aggregate, PupilMoved)
+	if err := event.SetJsonData(eventData); err != nil {
+		return nil, err
+	}
+	return event, nil
+}
+
+type PupilLeftEvent struct {
+	PupilID  string    `json:"pupilId"`
+	ClassID  string    `json:"classId"`
+	ExitDate time.Time `json:"exitDate"`
+	Reason   string    `json:"reason"`
+}
+
+func NewPupilLeft(aggregate *SchoolPupilAggregate, pupilID, classID string, exitDate time.Time, reason string) (domain.Event, error) {
+	eventData := PupilLeftEvent{
+		PupilID:  pupilID,
+		ClassID:  classID,
+		ExitDate: exitDate,
+		Reason:   reason,
+	}
+	event := domain.NewEvent(aggregate, PupilLeft)
+	if err := event.SetJsonData(eventData); err != nil {
+		return nil, err
+	}
+	return event, nil
+}
@@ -0,0 +1,19 @@
+package pupildomain
+
+import "time"
+
+type PupilProjection struct {
+	SchoolID    string     `json:"schoolId" bson:"schoolId"`
+	PupilID     string     `json:"pupilId" bson:"pupilId"`
+	FirstName   string     `json:"firstName" bson:"firstName"`
+	LastName    string     `json:"lastName" bson:"lastName"`
+	DateOfBirth time.Time  `json:"dateOfBirth" bson:"dateOfBirth"`
+	ClassID     string     `json:"classId" bson:"classId"`
+	EntryDate   time.Time  `json:"entryDate" bson:"entryDate"`
+	ExitDate    *time.Time `json:"exitDate,omitempty" bson:"exitDate,omitempty"`
+	Version     int        `json:"version" bson:"version"`
+}
+
+func NewPupilProjection(schoolID, pupilID, firstName, lastName string, dateOfBirth time.Time, classID string, entryDate time.Time, version int) PupilProjection {
+	return PupilProjection{schoolID, pupilID, firstName, lastName, dateOfBirth, classID, entryDate, nil, version}
+}
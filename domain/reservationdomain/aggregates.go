@@ -0,0 +1,96 @@
+package reservationdomain
+
+import (
+	"github.com/kammeph/school-book-storage-service/domain"
+	"github.com/kammeph/school-book-storage-service/fp"
+)
+
+type SchoolReservationAggregate struct {
+	*domain.AggregateModel
+	Reservations   []Reservation
+	LendingVersion int
+}
+
+func NewSchoolReservationAggregate() *SchoolReservationAggregate {
+	aggregate := &SchoolReservationAggregate{
+		Reservations: []Reservation{},
+	}
+	model := domain.NewAggregateModel(aggregate.On)
+	aggregate.AggregateModel = &model
+	return aggregate
+}
+
+func NewSchoolReservationAggregateWithID(id string) *SchoolReservationAggregate {
+	aggregate := NewSchoolReservationAggregate()
+	aggregate.ID = id
+	return aggregate
+}
+
+func (a *SchoolReservationAggregate) On(event domain.Event) error {
+	switch event.EventType() {
+	case ReservationMade:
+		return a.onReservationMade(event)
+	case ReservationCancelled:
+		return a.onReservationCancelled(event)
+	case ReservationFulfilled:
+		return a.onReservationFulfilled(event)
+	default:
+		return domain.ErrUnknownEvent(event)
+	}
+}
+
+func (a *SchoolReservationAggregate) onReservationMade(event domain.Event) error {
+	eventData := ReservationMadeEvent{}
+	if err := event.GetJsonData(&eventData); err != nil {
+		return err
+	}
+	if fp.Some(a.Reservations, func(r Reservation) bool { return r.ID == eventData.ReservationID }) {
+		return ErrApplyEventReservationAlreadyExists(event.EventType(), eventData.ReservationID)
+	}
+	reservation := NewReservation(
+		eventData.ReservationID,
+		eventData.ClassID,
+		eventData.TeacherID,
+		eventData.BookID,
+		eventData.StorageID,
+		eventData.Quantity,
+		eventData.From,
+		eventData.Until,
+		event.EventAt())
+	a.Version = event.EventVersion()
+	a.Reservations = append(a.Reservations, reservation)
+	return nil
+}
+
+func (a *SchoolReservationAggregate) onReservationCancelled(event domain.Event) error {
+	eventData := ReservationCancelledEvent{}
+	if err := event.GetJsonData(&eventData); err != nil {
+		return err
+	}
+	reservation := fp.Find(a.Reservations, func(r Reservation) bool { return r.ID == eventData.ReservationID })
+	if reservation == nil {
+		return ErrApplyEventReservationNotFound(event.EventType(), eventData.ReservationID)
+	}
+	a.Version = event.EventVersion()
+	reservation.CancelledAt = event.EventAt()
+	reservation.UpdatedAt = event.EventAt()
+	return nil
+}
+
+func (a *SchoolReservationAggregate) onReservationFulfilled(event domain.Event) error {
+	eventData := ReservationFulfilledEvent{}
+	if err := event.GetJsonData(&eventData); err != nil {
+		return err
+	}
+	reservation := fp.Find(a.Reservations, func(r Reservation) bool { return r.ID == eventData.ReservationID })
+	if reservation == nil {
+		return ErrApplyEventReservationNotFound(event.EventType(), eventData.ReservationID)
+	}
+	a.Version = event.EventVersion()
+	if eventData.LendingVersion > a.LendingVersion {
+		a.LendingVersion = eventData.LendingVersion
+	}
+	reservation.Fulfilled += eventData.Quantity
+	reservation.UpdatedAt = event.EventAt()
+	return nil
+}
@@ -0,0 +1,130 @@
+package reservationdomain
+
+import (
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/kammeph/school-book-storage-service/domain"
+	"github.com/kammeph/school-book-storage-service/domain/storagedomain"
+	"github.com/kammeph/school-book-storage-service/fp"
+)
+
+// Reserve keeps copies of the book in the storage for a class or a teacher.
+// Available is how many copies the storage has. The reservation is rejected if
+// the storage can not keep them together with the reservations it overlaps.
+func (a *SchoolReservationAggregate) Reserve(
+	classID, teacherID, bookID, storageID string,
+	quantity int,
+	from, until, at time.Time,
+	available int,
+) (string, error) {
+	if classID == "" && teacherID == "" {
+		return "", ErrHolderNotSet
+	}
+	if bookID == "" {
+		return "", ErrBookIDNotSet
+	}
+	if storageID == "" {
+		return "", ErrStorageIDNotSet
+	}
+	if quantity <= 0 {
+		return "", ErrQuantityNotPositive
+	}
+	if !until.After(from) {
+		return "", ErrUntilBeforeFrom
+	}
+	if !until.After(at) {
+		return "", ErrReservationInPast
+	}
+	reservationID := uuid.NewString()
+	reservations := append(
+		append([]Reservation{}, a.Reservations...),
+		NewReservation(reservationID, classID, teacherID, bookID, storageID, quantity, from, until, at))
+	for _, demand := range demands(reservations, storageID, bookID, at, "") {
+		overlaps := fp.Some(demand.ReservationIDs, func(id string) bool { return id == reservationID })
+		if overlaps && demand.Quantity > available {
+			return "", ErrReservationConflict(bookID, storageID, available, demand.Quantity)
+		}
+	}
+	event, err := NewReservationMade(a, reservationID, classID, teacherID, bookID, storageID, quantity, from, until)
+	if err != nil {
+		return "", err
+	}
+	if err := a.Apply(event); err != nil {
+		return "", err
+	}
+	return reservationID, nil
+}
+
+func (a *SchoolReservationAggregate) CancelReservation(reservationID, reason string, at time.Time) error {
+	reservation := fp.Find(a.Reservations, func(r Reservation) bool { return r.ID == reservationID })
+	if reservation == nil {
+		return ErrReservationWithIDNotFound(reservationID)
+	}
+	if !reservation.Open(at) {
+		return ErrReservationClosed(reservationID)
+	}
+	if reason == "" {
+		return domain.ErrReasonNotSpecified
+	}
+	event, err := NewReservationCancelled(a, reservationID, reason)
+	if err != nil {
+		return err
+	}
+	return a.Apply(event)
+}
+
+// Fulfil counts copies of the book lent to the class from the storage against
+// the open reservations of the class, starting with the earliest. Lendings
+// are counted in the order of their storage event versions, a lending with a
+// version that was already counted is ignored.
+func (a *SchoolReservationAggregate) Fulfil(lendingVersion int, classID, storageID, bookID string, quantity int, at time.Time) error {
+	if lendingVersion <= a.LendingVersion {
+		return nil
+	}
+	open := fp.Filter(a.Reservations, func(r Reservation) bool {
+		return r.ClassID == classID && r.StorageID == storageID && r.BookID == bookID && r.Open(at)
+	})
+	sort.SliceStable(open, func(i, j int) bool { return open[i].From.Before(open[j].From) })
+	for _, reservation := range open {
+		if quantity <= 0 {
+			return nil
+		}
+		fulfilled := reservation.Remaining()
+		if fulfilled > quantity {
+			fulfilled = quantity
+		}
+		event, err := NewReservationFulfilled(a, reservation.ID, fulfilled, lendingVersion)
+		if err != nil {
+			return err
+		}
+		if err := a.Apply(event); err != nil {
+			return err
+		}
+		quantity -= fulfilled
+	}
+	return nil
+}
+
+// Holds returns the copies the open reservations keep in the storages. The
+// reservations of the class are left out, so it can take its reserved copies.
+func (a *SchoolReservationAggregate) Holds(at time.Time, exceptClassID string) []storagedomain.Hold {
+	holds := []storagedomain.Hold{}
+	for _, reservation := range a.Reservations {
+		if !reservation.Open(at) || fp.Some(holds, func(h storagedomain.Hold) bool {
+			return h.StorageID == reservation.StorageID && h.BookID == reservation.BookID
+		}) {
+			continue
+		}
+		demand := PeakDemand(a.Reservations, reservation.StorageID, reservation.BookID, at, exceptClassID)
+		if demand.Quantity > 0 {
+			holds = append(holds, storagedomain.Hold{
+				StorageID: reservation.StorageID,
+				BookID:    reservation.BookID,
+				Quantity:  demand.Quantity,
+			})
+		}
+	}
+	return holds
+}
@@ -0,0 +1,176 @@
+package reservationdomain_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kammeph/school-book-storage-service/domain"
+	"github.com/kammeph/school-book-storage-service/domain/reservationdomain"
+	"github.com/kammeph/school-book-storage-service/domain/storagedomain"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	now        = time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+	schoolYear = time.Date(2023, 9, 1, 0, 0, 0, 0, time.UTC)
+	summer     = time.Date(2024, 7, 15, 0, 0, 0, 0, time.UTC)
+	nextYear   = time.Date(2024, 9, 1, 0, 0, 0, 0, time.UTC)
+)
+
+func initSchoolReservationAggregate(reservations []reservationdomain.Reservation) *reservationdomain.SchoolReservationAggregate {
+	aggregate := reservationdomain.NewSchoolReservationAggregateWithID("school")
+	aggregate.Reservations = reservations
+	return aggregate
+}
+
+func reservation(id, classID string, quantity int, from, until time.Time) reservationdomain.Reservation {
+	return reservationdomain.NewReservation(id, classID, "", "book", "storage", quantity, from, until, now)
+}
+
+func TestReserve(t *testing.T) {
+	tests := []struct {
+		name         string
+		reservations []reservationdomain.Reservation
+		classID      string
+		teacherID    string
+		quantity     int
+		from         time.Time
+		until        time.Time
+		err          error
+	}{
+		{
+			name:     "reserve for a class",
+			classID:  "class",
+			quantity: 25,
+			from:     schoolYear,
+			until:    summer,
+		},
+		{
+			name:      "reserve for a teacher",
+			teacherID: "teacher",
+			quantity:  1,
+			from:      schoolYear,
+			until:     summer,
+		},
+		{
+			name:     "reserve without class or teacher",
+			quantity: 1,
+			from:     schoolYear,
+			until:    summer,
+			err:      reservationdomain.ErrHolderNotSet,
+		},
+		{
+			name:    "reserve no copies",
+			classID: "class",
+			from:    schoolYear,
+			until:   summer,
+			err:     reservationdomain.ErrQuantityNotPositive,
+		},
+		{
+			name:     "reserve with end before start",
+			classID:  "class",
+			quantity: 1,
+			from:     summer,
+			until:    schoolYear,
+			err:      reservationdomain.ErrUntilBeforeFrom,
+		},
+		{
+			name:     "reserve in the past",
+			classID:  "class",
+			quantity: 1,
+			from:     now.AddDate(-1, 0, 0),
+			until:    now,
+			err:      reservationdomain.ErrReservationInPast,
+		},
+		{
+			name:         "reserve more copies than left",
+			reservations: []reservationdomain.Reservation{reservation("other", "other", 20, schoolYear, summer)},
+			classID:      "class",
+			quantity:     11,
+			from:         schoolYear,
+			until:        summer,
+			err:          reservationdomain.ErrReservationConflict("book", "storage", 30, 31),
+		},
+		{
+			name:         "reserve copies reserved for another time",
+			reservations: []reservationdomain.Reservation{reservation("other", "other", 20, summer, nextYear)},
+			classID:      "class",
+			quantity:     30,
+			from:         schoolYear,
+			until:        summer,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			aggregate := initSchoolReservationAggregate(test.reservations)
+			reservationID, err := aggregate.Reserve(test.classID, test.teacherID, "book", "storage", test.quantity, test.from, test.until, now, 30)
+			assert.Equal(t, test.err, err)
+			if test.err != nil {
+				return
+			}
+			assert.Equal(t, reservationdomain.ReservationMade, aggregate.DomainEvents()[0].EventType())
+			assert.Equal(t, reservationID, aggregate.Reservations[len(aggregate.Reservations)-1].ID)
+			assert.Equal(t, reservationdomain.Upcoming, aggregate.Reservations[len(aggregate.Reservations)-1].Status(now))
+		})
+	}
+}
+
+func TestCancelReservation(t *testing.T) {
+	aggregate := initSchoolReservationAggregate([]reservationdomain.Reservation{reservation("reservation", "class", 25, schoolYear, summer)})
+	assert.Equal(t, reservationdomain.ErrReservationWithIDNotFound("unknown"), aggregate.CancelReservation("unknown", "class split", now))
+	assert.Equal(t, domain.ErrReasonNotSpecified, aggregate.CancelReservation("reservation", "", now))
+	assert.Equal(t, reservationdomain.ErrReservationClosed("reservation"), aggregate.CancelReservation("reservation", "class split", summer))
+
+	assert.NoError(t, aggregate.CancelReservation("reservation", "class split", now))
+	assert.Equal(t, reservationdomain.Cancelled, aggregate.Reservations[0].Status(now))
+	assert.Empty(t, aggregate.Holds(now, ""))
+}
+
+func TestFulfil(t *testing.T) {
+	aggregate := initSchoolReservationAggregate([]reservationdomain.Reservation{
+		reservation("later", "class", 10, summer, nextYear),
+		reservation("earlier", "class", 20, schoolYear, summer),
+		reservation("other", "other", 5, schoolYear, summer),
+	})
+	assert.NoError(t, aggregate.Fulfil(3, "class", "storage", "book", 25, schoolYear))
+	assert.Len(t, aggregate.DomainEvents(), 2)
+	assert.Equal(t, reservationdomain.Fulfilled, aggregate.Reservations[1].Status(schoolYear))
+	assert.Equal(t, 5, aggregate.Reservations[0].Remaining())
+	assert.Equal(t, 5, aggregate.Reservations[2].Remaining())
+	assert.Equal(t, 3, aggregate.LendingVersion)
+
+	assert.NoError(t, aggregate.Fulfil(3, "class", "storage", "book", 5, schoolYear))
+	assert.Len(t, aggregate.DomainEvents(), 2)
+	assert.Equal(t, 5, aggregate.Reservations[0].Remaining())
+}
+
+func TestHolds(t *testing.T) {
+	aggregate := initSchoolReservationAggregate([]reservationdomain.Reservation{
+		reservation("first", "class", 20, schoolYear, summer),
+		reservation("second", "other", 15, schoolYear, summer),
+		reservation("third", "third", 30, summer, nextYear),
+		reservation("expired", "class", 50, now.AddDate(-1, 0, 0), now),
+	})
+	assert.Equal(t, []storagedomain.Hold{{StorageID: "storage", BookID: "book", Quantity: 35}}, aggregate.Holds(now, ""))
+	assert.Equal(t, []storagedomain.Hold{{StorageID: "storage", BookID: "book", Quantity: 30}}, aggregate.Holds(now, "class"))
+	assert.Equal(t, []storagedomain.Hold{{StorageID: "storage", BookID: "book", Quantity: 30}}, aggregate.Holds(summer, ""))
+	assert.Empty(t, aggregate.Holds(nextYear, ""))
+}
+
+func TestConflicts(t *testing.T) {
+	reservations := []reservationdomain.Reservation{
+		reservation("first", "class", 20, schoolYear, summer),
+		reservation("second", "other", 15, schoolYear, summer),
+		reservation("third", "third", 30, summer, nextYear),
+	}
+	available := func(storageID, bookID string) int { return 32 }
+	assert.Equal(t, []reservationdomain.Conflict{{
+		StorageID:      "storage",
+		BookID:         "book",
+		At:             schoolYear,
+		Available:      32,
+		Reserved:       35,
+		ReservationIDs: []string{"first", "second"},
+	}}, reservationdomain.Conflicts(reservations, now, available))
+	assert.Empty(t, reservationdomain.Conflicts(reservations, summer, available))
+}
@@ -0,0 +1,168 @@
+package reservationdomain
+
+import "time"
+
+type Status string
+
+const (
+	Upcoming  Status = "upcoming"
+	Active    Status = "active"
+	Fulfilled Status = "fulfilled"
+	Expired   Status = "expired"
+	Cancelled Status = "cancelled"
+)
+
+// Reservation keeps copies of a book in a storage for a class or a teacher
+// from the time it is made until the end of its date range. Copies lent to the
+// class fulfil it.
+type Reservation struct {
+	ID          string
+	ClassID     string
+	TeacherID   string
+	BookID      string
+	StorageID   string
+	Quantity    int
+	Fulfilled   int
+	From        time.Time
+	Until       time.Time
+	CancelledAt time.Time
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+func NewReservation(id, classID, teacherID, bookID, storageID string, quantity int, from, until, timeStamp time.Time) Reservation {
+	return Reservation{
+		ID:        id,
+		ClassID:   classID,
+		TeacherID: teacherID,
+		BookID:    bookID,
+		StorageID: storageID,
+		Quantity:  quantity,
+		From:      from,
+		Until:     until,
+		CreatedAt: timeStamp,
+	}
+}
+
+func (r Reservation) Cancelled() bool {
+	return !r.CancelledAt.IsZero()
+}
+
+func (r Reservation) Remaining() int {
+	return r.Quantity - r.Fulfilled
+}
+
+// Open reports whether the reservation still keeps copies at the time. It
+// expires at the end of its date range.
+func (r Reservation) Open(at time.Time) bool {
+	return !r.Cancelled() && r.Remaining() > 0 && r.Until.After(at)
+}
+
+func (r Reservation) Status(at time.Time) Status {
+	switch {
+	case r.Cancelled():
+		return Cancelled
+	case r.Remaining() <= 0:
+		return Fulfilled
+	case !r.Until.After(at):
+		return Expired
+	case r.From.After(at):
+		return Upcoming
+	default:
+		return Active
+	}
+}
+
+func (r Reservation) covers(at time.Time) bool {
+	return !r.From.After(at) && r.Until.After(at)
+}
+
+// Demand is the most copies of a book in a storage that open reservations need
+// at the same time.
+type Demand struct {
+	At             time.Time
+	Quantity       int
+	ReservationIDs []string
+}
+
+// PeakDemand looks from the time on for the moment the open reservations of
+// the book in the storage need the most copies. Reservations of the class are
+// left out, as the class may take its reserved copies.
+func PeakDemand(reservations []Reservation, storageID, bookID string, at time.Time, exceptClassID string) Demand {
+	peak := Demand{At: at, ReservationIDs: []string{}}
+	for _, demand := range demands(reservations, storageID, bookID, at, exceptClassID) {
+		if demand.Quantity > peak.Quantity {
+			peak = demand
+		}
+	}
+	return peak
+}
+
+// demands returns what the open reservations need at the time and whenever
+// another reservation starts later on. The demand only rises at these times.
+func demands(reservations []Reservation, storageID, bookID string, at time.Time, exceptClassID string) []Demand {
+	open := []Reservation{}
+	for _, reservation := range reservations {
+		if reservation.StorageID == storageID &&
+			reservation.BookID == bookID &&
+			reservation.Open(at) &&
+			(exceptClassID == "" || reservation.ClassID != exceptClassID) {
+			open = append(open, reservation)
+		}
+	}
+	points := []time.Time{at}
+	for _, reservation := range open {
+		if reservation.From.After(at) {
+			points = append(points, reservation.From)
+		}
+	}
+	result := []Demand{}
+	for _, point := range points {
+		demand := Demand{At: point, ReservationIDs: []string{}}
+		for _, reservation := range open {
+			if reservation.covers(point) {
+				demand.Quantity += reservation.Remaining()
+				demand.ReservationIDs = append(demand.ReservationIDs, reservation.ID)
+			}
+		}
+		result = append(result, demand)
+	}
+	return result
+}
+
+// Conflict reports that a storage has fewer copies of a book than its
+// reservations need at the same time.
+type Conflict struct {
+	StorageID      string    `json:"storageId"`
+	BookID         string    `json:"bookId"`
+	At             time.Time `json:"at"`
+	Available      int       `json:"available"`
+	Reserved       int       `json:"reserved"`
+	ReservationIDs []string  `json:"reservationIds"`
+}
+
+// Conflicts compares the peak demand of the open reservations with the copies
+// available in their storages.
+func Conflicts(reservations []Reservation, at time.Time, available func(storageID, bookID string) int) []Conflict {
+	conflicts := []Conflict{}
+	checked := map[[2]string]bool{}
+	for _, reservation := range reservations {
+		key := [2]string{reservation.StorageID, reservation.BookID}
+		if checked[key] || !reservation.Open(at) {
+			continue
+		}
+		checked[key] = true
+		demand := PeakDemand(reservations, reservation.StorageID, reservation.BookID, at, "")
+		if stock := available(reservation.StorageID, reservation.BookID); demand.Quantity > stock {
+			conflicts = append(conflicts, Conflict{
+				StorageID:      reservation.StorageID,
+				BookID:         reservation.BookID,
+				At:             demand.At,
+				Available:      stock,
+				Reserved:       demand.Quantity,
+				ReservationIDs: demand.ReservationIDs,
+			})
+		}
+	}
+	return conflicts
+}
@@ -0,0 +1,35 @@
+package reservationdomain
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrHolderNotSet        = errors.New("a reservation needs a class or a teacher")
+	ErrBookIDNotSet        = errors.New("book ID not set")
+	ErrStorageIDNotSet     = errors.New("storage ID not set")
+	ErrQuantityNotPositive = errors.New("quantity must be greater than zero")
+	ErrUntilBeforeFrom     = errors.New("a reservation must end after it starts")
+	ErrReservationInPast   = errors.New("a reservation must end in the future")
+)
+
+func ErrApplyEventReservationAlreadyExists(eventType, reservationID string) error {
+	return fmt.Errorf("can not apply %s: reservation with ID %s already exists", eventType, reservationID)
+}
+
+func ErrApplyEventReservationNotFound(eventType, reservationID string) error {
+	return fmt.Errorf("can not apply %s: reservation with ID %s not found", eventType, reservationID)
+}
+
+func ErrReservationWithIDNotFound(id string) error {
+	return fmt.Errorf("reservation with ID %s not found", id)
+}
+
+func ErrReservationClosed(id string) error {
+	return fmt.Errorf("reservation with ID %s is already cancelled, fulfilled or expired", id)
+}
+
+func ErrReservationConflict(bookID, storageID string, available, reserved int) error {
+	return fmt.Errorf("reservations of book %s in storage %s would need %d copies, only %d are available", bookID, storageID, reserved, available)
+}
@@ -0,0 +1,87 @@
+package reservationdomain
+
+import (
+	"time"
+
+	"github.com/kammeph/school-book-storage-service/domain"
+)
+
+var (
+	ReservationMade      = "RESERVATION_MADE"
+	ReservationCancelled = "RESERVATION_CANCELLED"
+	ReservationFulfilled = "RESERVATION_FULFILLED"
+)
+
+type ReservationMadeEvent struct {
+	SchoolID      string    `json:"schoolId"`
+	ReservationID string    `json:"reservationId"`
+	ClassID       string    `json:"classId,omitempty"`
+	TeacherID     string    `json:"teacherId,omitempty"`
+	BookID        string    `json:"bookId"`
+	StorageID     string    `json:"storageId"`
+	Quantity      int       `json:"quantity"`
+	From          time.Time `json:"from"`
+	Until         time.Time `json:"until"`
+}
+
+func NewReservationMade(
+	aggregate *SchoolReservationAggregate,
+	reservationID, classID, teacherID, bookID, storageID string,
+	quantity int,
+	from, until time.Time,
+) (domain.Event, error) {
+	eventData := ReservationMadeEvent{
+		SchoolID:      aggregate.AggregateID(),
+		ReservationID: reservationID,
+		ClassID:       classID,
+		TeacherID:     teacherID,
+		BookID:        bookID,
+		StorageID:     storageID,
+		Quantity:      quantity,
+		From:          from,
+		Until:         until,
+	}
+	event := domain.NewEvent(aggregate, ReservationMade)
+	if err := event.SetJsonData(eventData); err != nil {
+		return nil, err
+	}
+	return event, nil
+}
+
+type ReservationCancelledEvent struct {
+	ReservationID string `json:"reservationId"`
+	Reason        string `json:"reason"`
+}
+
+func NewReservationCancelled(aggregate *SchoolReservationAggregate, reservationID, reason string) (domain.Event, error) {
+	eventData := ReservationCancelledEvent{
+		ReservationID: reservationID,
+		Reason:        reason,
+	}
+	event := domain.NewEvent(aggregate, ReservationCancelled)
+	if err := event.SetJsonData(eventData); err != nil {
+		return nil, err
+	}
+	return event, nil
+}
+
+// ReservationFulfilledEvent counts lent copies against a reservation.
+// LendingVersion is the version of the storage event that lent the copies.
+type ReservationFulfilledEvent struct {
+	ReservationID  string `json:"reservationId"`
+	Quantity       int    `json:"quantity"`
+	LendingVersion int    `json:"lendingVersion,omitempty"`
+}
+
+func NewReservationFulfilled(aggregate *SchoolReservationAggregate, reservationID string, quantity, lendingVersion int) (domain.Event, error) {
+	eventData := ReservationFulfilledEvent{
+		ReservationID:  reservationID,
+		Quantity:       quantity,
+		LendingVersion: lendingVersion,
+	}
+	event := domain.NewEvent(aggregate, ReservationFulfilled)
+	if err := event.SetJsonData(eventData); err != nil {
+		return nil, err
+	}
+	return event, nil
+}
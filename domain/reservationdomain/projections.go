@@ -0,0 +1,48 @@
+package reservationdomain
+
+import "time"
+
+type ReservationProjection struct {
+	SchoolID      string     `json:"schoolId" bson:"schoolId"`
+	ReservationID string     `json:"reservationId" bson:"reservationId"`
+	ClassID       string     `json:"classId,omitempty" bson:"classId,omitempty"`
+	TeacherID     string     `json:"teacherId,omitempty" bson:"teacherId,omitempty"`
+	BookID        string     `json:"bookId" bson:"bookId"`
+	StorageID     string     `json:"storageId" bson:"storageId"`
+	Quantity      int        `json:"quantity" bson:"quantity"`
+	Fulfilled     int        `json:"fulfilled" bson:"fulfilled"`
+	From          time.Time  `json:"from" bson:"from"`
+	Until         time.Time  `json:"until" bson:"until"`
+	CancelledAt   *time.Time `json:"cancelledAt,omitempty" bson:"cancelledAt,omitempty"`
+	Status        Status     `json:"status" bson:"-"`
+	Version       int        `json:"version" bson:"version"`
+}
+
+func NewReservationProjection(
+	schoolID, reservationID, classID, teacherID, bookID, storageID string,
+	quantity int,
+	from, until time.Time,
+	version int,
+) ReservationProjection {
+	return ReservationProjection{
+		SchoolID:      schoolID,
+		ReservationID: reservationID,
+		ClassID:       classID,
+		TeacherID:     teacherID,
+		BookID:        bookID,
+		StorageID:     storageID,
+		Quantity:      quantity,
+		From:          from,
+		Until:         until,
+		Version:       version,
+	}
+}
+
+func (p ReservationProjection) Reservation() Reservation {
+	reservation := NewReservation(p.ReservationID, p.ClassID, p.TeacherID, p.BookID, p.StorageID, p.Quantity, p.From, p.Until, time.Time{})
+	reservation.Fulfilled = p.Fulfilled
+	if p.CancelledAt != nil {
+		reservation.CancelledAt = *p.CancelledAt
+	}
+	return reservation
+}
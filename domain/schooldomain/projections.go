@@ -3,8 +3,9 @@ package schooldomain
 type SchoolProjection struct {
 	SchoolID string `json:"schoolId" bson:"schoolId"`
 	Name     string `json:"name" bson:"name"`
+	Version  int    `json:"version" bson:"version"`
 }
 
-func NewSchoolProjection(id, name string) SchoolProjection {
-	return SchoolProjection{id, name}
+func NewSchoolProjection(id, name string, version int) SchoolProjection {
+	return SchoolProjection{id, name, version}
 }
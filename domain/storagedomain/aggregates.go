@@ -1,18 +1,30 @@
 package storagedomain
 
 import (
+	"time"
+
 	"github.com/kammeph/school-book-storage-service/domain"
 	"github.com/kammeph/school-book-storage-service/fp"
 )
 
+// SchoolStorageAggregate holds the storages of a school. Holds are not part of
+// its history, callers set them from other aggregates before stock is handed
+// out or transferred.
 type SchoolStorageAggregate struct {
 	*domain.AggregateModel
-	Storages []Storage
+	Storages     []Storage
+	Stocktakings []Stocktaking
+	Locations    []Location
+	Holds        []Hold
+	Receipts     []string
 }
 
 func NewSchoolStorageAggregate() *SchoolStorageAggregate {
 	aggregate := &SchoolStorageAggregate{
-		Storages: []Storage{},
+		Storages:     []Storage{},
+		Stocktakings: []Stocktaking{},
+		Locations:    []Location{},
+		Receipts:     []string{},
 	}
 	model := domain.NewAggregateModel(aggregate.On)
 	aggregate.AggregateModel = &model
@@ -35,6 +47,36 @@ func (s *SchoolStorageAggregate) On(event domain.Event) error {
 		return s.onStorageRenamed(event)
 	case StorageRelocated:
 		return s.onStorageRelocated(event)
+	case BooksPut:
+		return s.onBooksPut(event)
+	case BooksTaken:
+		return s.onBooksTaken(event)
+	case BooksTransferred:
+		return s.onBooksTransferred(event)
+	case BooksLentToClass:
+		return s.onBooksLentToClass(event)
+	case BooksReturnedFromClass:
+		return s.onBooksReturnedFromClass(event)
+	case StocktakingOpened:
+		return s.onStocktakingOpened(event)
+	case StockCounted:
+		return s.onStockCounted(event)
+	case StockCorrected:
+		return s.onStockCorrected(event)
+	case StocktakingClosed:
+		return s.onStocktakingClosed(event)
+	case BooksDamaged:
+		return s.onBooksDamaged(event)
+	case BooksWrittenOff:
+		return s.onBooksWrittenOff(event)
+	case StorageCapacityChanged:
+		return s.onStorageCapacityChanged(event)
+	case LocationAdded:
+		return s.onLocationAdded(event)
+	case LocationRenamed:
+		return s.onLocationRenamed(event)
+	case LocationRemoved:
+		return s.onLocationRemoved(event)
 	default:
 		return domain.ErrUnknownEvent(event)
 	}
@@ -49,6 +91,7 @@ func (a *SchoolStorageAggregate) onStorageAdded(event domain.Event) error {
 		return ErrStoragesWithIdAlreadyExists(eventData.StorageID)
 	}
 	storage := NewStorage(eventData.StorageID, eventData.Name, eventData.Location, event.EventAt())
+	storage.LocationID = eventData.LocationID
 	a.Version = event.EventVersion()
 	a.Storages = append(a.Storages, storage)
 	return nil
@@ -91,5 +134,268 @@ func (a *SchoolStorageAggregate) onStorageRelocated(event domain.Event) error {
 	a.Version = event.EventVersion()
 	storage.UpdatedAt = event.EventAt()
 	storage.Location = eventData.Location
+	storage.LocationID = eventData.LocationID
+	return nil
+}
+
+func (a *SchoolStorageAggregate) onBooksPut(event domain.Event) error {
+	eventData := BooksPutEvent{}
+	if err := event.GetJsonData(&eventData); err != nil {
+		return err
+	}
+	stock := BookStock{BookID: eventData.BookID, Isbn: eventData.Isbn, Title: eventData.Title, Quantity: eventData.Quantity}
+	if err := a.putStock(eventData.StorageID, stock, event.EventAt()); err != nil {
+		return err
+	}
+	if eventData.ReceiptID != "" {
+		a.Receipts = append(a.Receipts, eventData.ReceiptID)
+	}
+	a.Version = event.EventVersion()
+	return nil
+}
+
+func (a *SchoolStorageAggregate) onBooksTaken(event domain.Event) error {
+	eventData := BooksTakenEvent{}
+	if err := event.GetJsonData(&eventData); err != nil {
+		return err
+	}
+	if err := a.takeStock(eventData.StorageID, eventData.BookID, eventData.Quantity, event.EventAt()); err != nil {
+		return err
+	}
+	a.Version = event.EventVersion()
+	return nil
+}
+
+func (a *SchoolStorageAggregate) onBooksTransferred(event domain.Event) error {
+	eventData := BooksTransferredEvent{}
+	if err := event.GetJsonData(&eventData); err != nil {
+		return err
+	}
+	if !fp.Some(a.Storages, func(s Storage) bool { return s.ID == eventData.ToStorageID }) {
+		return ErrStorageIDNotFound(eventData.ToStorageID)
+	}
+	if err := a.takeStock(eventData.FromStorageID, eventData.BookID, eventData.Quantity, event.EventAt()); err != nil {
+		return err
+	}
+	stock := BookStock{BookID: eventData.BookID, Isbn: eventData.Isbn, Title: eventData.Title, Quantity: eventData.Quantity}
+	if err := a.putStock(eventData.ToStorageID, stock, event.EventAt()); err != nil {
+		return err
+	}
+	a.Version = event.EventVersion()
+	return nil
+}
+
+func (a *SchoolStorageAggregate) onBooksLentToClass(event domain.Event) error {
+	eventData := BooksLentToClassEvent{}
+	if err := event.GetJsonData(&eventData); err != nil {
+		return err
+	}
+	if err := a.takeStock(eventData.StorageID, eventData.BookID, eventData.Quantity, event.EventAt()); err != nil {
+		return err
+	}
+	a.Version = event.EventVersion()
+	return nil
+}
+
+func (a *SchoolStorageAggregate) onBooksReturnedFromClass(event domain.Event) error {
+	eventData := BooksReturnedFromClassEvent{}
+	if err := event.GetJsonData(&eventData); err != nil {
+		return err
+	}
+	stock := BookStock{BookID: eventData.BookID, Isbn: eventData.Isbn, Title: eventData.Title, Quantity: eventData.Quantity}
+	if err := a.putStock(eventData.StorageID, stock, event.EventAt()); err != nil {
+		return err
+	}
+	a.Version = event.EventVersion()
+	return nil
+}
+
+func (a *SchoolStorageAggregate) onStocktakingOpened(event domain.Event) error {
+	eventData := StocktakingOpenedEvent{}
+	if err := event.GetJsonData(&eventData); err != nil {
+		return err
+	}
+	a.Version = event.EventVersion()
+	a.Stocktakings = append(a.Stocktakings, NewStocktaking(eventData.StocktakingID, eventData.StorageIDs, event.EventAt()))
+	return nil
+}
+
+func (a *SchoolStorageAggregate) onStockCounted(event domain.Event) error {
+	eventData := StockCountedEvent{}
+	if err := event.GetJsonData(&eventData); err != nil {
+		return err
+	}
+	stocktaking := fp.Find(a.Stocktakings, func(s Stocktaking) bool { return s.ID == eventData.StocktakingID })
+	if stocktaking == nil {
+		return ErrStocktakingWithIDNotFound(eventData.StocktakingID)
+	}
+	count := StockCount{eventData.StorageID, eventData.BookID, eventData.Isbn, eventData.Title, eventData.Quantity}
+	stocktaking.Counts = append(
+		fp.Remove(stocktaking.Counts, func(c StockCount) bool { return c.StorageID == count.StorageID && c.BookID == count.BookID }),
+		count)
+	a.Version = event.EventVersion()
+	return nil
+}
+
+func (a *SchoolStorageAggregate) onStockCorrected(event domain.Event) error {
+	eventData := StockCorrectedEvent{}
+	if err := event.GetJsonData(&eventData); err != nil {
+		return err
+	}
+	if eventData.Difference > 0 {
+		stock := BookStock{BookID: eventData.BookID, Isbn: eventData.Isbn, Title: eventData.Title, Quantity: eventData.Difference}
+		if err := a.putStock(eventData.StorageID, stock, event.EventAt()); err != nil {
+			return err
+		}
+	} else {
+		if err := a.takeStock(eventData.StorageID, eventData.BookID, -eventData.Difference, event.EventAt()); err != nil {
+			return err
+		}
+	}
+	a.Version = event.EventVersion()
+	return nil
+}
+
+func (a *SchoolStorageAggregate) onStocktakingClosed(event domain.Event) error {
+	eventData := StocktakingClosedEvent{}
+	if err := event.GetJsonData(&eventData); err != nil {
+		return err
+	}
+	stocktaking := fp.Find(a.Stocktakings, func(s Stocktaking) bool { return s.ID == eventData.StocktakingID })
+	if stocktaking == nil {
+		return ErrStocktakingWithIDNotFound(eventData.StocktakingID)
+	}
+	stocktaking.Closed = true
+	stocktaking.ClosedAt = event.EventAt()
+	a.Version = event.EventVersion()
+	return nil
+}
+
+func (a *SchoolStorageAggregate) onBooksDamaged(event domain.Event) error {
+	eventData := BooksDamagedEvent{}
+	if err := event.GetJsonData(&eventData); err != nil {
+		return err
+	}
+	storage, stock, err := a.findStock(eventData.StorageID, eventData.BookID)
+	if err != nil {
+		return err
+	}
+	stock.Conditions = Regrade(stock.Conditions, eventData.From, eventData.Condition, eventData.Quantity)
+	storage.UpdatedAt = event.EventAt()
+	a.Version = event.EventVersion()
+	return nil
+}
+
+func (a *SchoolStorageAggregate) onBooksWrittenOff(event domain.Event) error {
+	eventData := BooksWrittenOffEvent{}
+	if err := event.GetJsonData(&eventData); err != nil {
+		return err
+	}
+	storage, stock, err := a.findStock(eventData.StorageID, eventData.BookID)
+	if err != nil {
+		return err
+	}
+	stock.Conditions = Regrade(stock.Conditions, eventData.Condition, Good, eventData.Quantity)
+	if err := a.takeStock(eventData.StorageID, eventData.BookID, eventData.Quantity, event.EventAt()); err != nil {
+		return err
+	}
+	storage.UpdatedAt = event.EventAt()
+	a.Version = event.EventVersion()
+	return nil
+}
+
+func (a *SchoolStorageAggregate) findStock(storageID, bookID string) (*Storage, *BookStock, error) {
+	storage := fp.Find(a.Storages, func(s Storage) bool { return s.ID == storageID })
+	if storage == nil {
+		return nil, nil, ErrStorageIDNotFound(storageID)
+	}
+	stock := fp.Find(storage.Stock, func(s BookStock) bool { return s.BookID == bookID })
+	if stock == nil {
+		return nil, nil, ErrInsufficientStock(bookID, 0, 1)
+	}
+	return storage, stock, nil
+}
+
+func (a *SchoolStorageAggregate) putStock(storageID string, book BookStock, at time.Time) error {
+	storage := fp.Find(a.Storages, func(s Storage) bool { return s.ID == storageID })
+	if storage == nil {
+		return ErrStorageIDNotFound(storageID)
+	}
+	stock := fp.Find(storage.Stock, func(s BookStock) bool { return s.BookID == book.BookID })
+	if stock == nil {
+		storage.Stock = append(storage.Stock, book)
+	} else {
+		stock.Quantity += book.Quantity
+	}
+	storage.UpdatedAt = at
+	return nil
+}
+
+func (a *SchoolStorageAggregate) takeStock(storageID, bookID string, quantity int, at time.Time) error {
+	storage := fp.Find(a.Storages, func(s Storage) bool { return s.ID == storageID })
+	if storage == nil {
+		return ErrStorageIDNotFound(storageID)
+	}
+	stock := fp.Find(storage.Stock, func(s BookStock) bool { return s.BookID == bookID })
+	if stock == nil {
+		return ErrInsufficientStock(bookID, 0, quantity)
+	}
+	stock.Quantity -= quantity
+	stock.Conditions = Withdraw(stock.Conditions, stock.Quantity)
+	if stock.Quantity <= 0 {
+		storage.Stock = fp.Remove(storage.Stock, func(s BookStock) bool { return s.BookID == bookID })
+	}
+	storage.UpdatedAt = at
+	return nil
+}
+
+func (a *SchoolStorageAggregate) onStorageCapacityChanged(event domain.Event) error {
+	eventData := StorageCapacityChangedEvent{}
+	if err := event.GetJsonData(&eventData); err != nil {
+		return err
+	}
+	storage := fp.Find(a.Storages, func(s Storage) bool { return s.ID == eventData.StorageID })
+	if storage == nil {
+		return ErrStorageIDNotFound(eventData.StorageID)
+	}
+	a.Version = event.EventVersion()
+	storage.UpdatedAt = event.EventAt()
+	storage.Capacity = eventData.Capacity
+	return nil
+}
+
+func (a *SchoolStorageAggregate) onLocationAdded(event domain.Event) error {
+	eventData := LocationAddedEvent{}
+	if err := event.GetJsonData(&eventData); err != nil {
+		return err
+	}
+	location := NewLocation(eventData.LocationID, eventData.Kind, eventData.Name, eventData.ParentID, event.EventAt())
+	a.Version = event.EventVersion()
+	a.Locations = append(a.Locations, location)
+	return nil
+}
+
+func (a *SchoolStorageAggregate) onLocationRenamed(event domain.Event) error {
+	eventData := LocationRenamedEvent{}
+	if err := event.GetJsonData(&eventData); err != nil {
+		return err
+	}
+	location := fp.Find(a.Locations, func(l Location) bool { return l.ID == eventData.LocationID })
+	if location == nil {
+		return ErrApplyEventLocationNotFound(event.EventType(), eventData.LocationID)
+	}
+	a.Version = event.EventVersion()
+	location.UpdatedAt = event.EventAt()
+	location.Name = eventData.Name
+	return nil
+}
+
+func (a *SchoolStorageAggregate) onLocationRemoved(event domain.Event) error {
+	eventData := LocationRemovedEvent{}
+	if err := event.GetJsonData(&eventData); err != nil {
+		return err
+	}
+	a.Locations = fp.Remove(a.Locations, func(l Location) bool { return l.ID == eventData.LocationID })
+	a.Version = event.EventVersion()
 	return nil
 }
@@ -153,7 +153,7 @@ func TestOn(t *testing.T) {
 					At:      test.eventAt,
 					Type:    test.eventType,
 				}
-				eventData := storagedomain.StorageAddedEvent{"storageAggregate", storageID, test.storageName, test.storageLocation}
+				eventData := storagedomain.StorageAddedEvent{"storageAggregate", storageID, test.storageName, test.storageLocation, ""}
 				event.SetJsonData(eventData)
 			case storagedomain.StorageRemoved:
 				event = &domain.EventModel{
@@ -177,7 +177,7 @@ func TestOn(t *testing.T) {
 					At:      test.eventAt,
 					Type:    test.eventType,
 				}
-				eventData := storagedomain.StorageRelocatedEvent{storageID, test.storageLocation, test.reason}
+				eventData := storagedomain.StorageRelocatedEvent{storageID, test.storageLocation, "", test.reason}
 				event.SetJsonData(eventData)
 			default:
 				event = &UnknownEvent{}
@@ -7,19 +7,31 @@ import (
 )
 
 func (a *SchoolStorageAggregate) AddStorage(name, location string) (string, error) {
-	if name == "" {
-		return "", ErrStorageNameNotSet
-	}
 	if location == "" {
 		return "", ErrStorageLocationNotSet
 	}
+	return a.addStorage(name, location, "")
+}
+
+// AddStorageAt adds a storage to one of the locations of the school.
+func (a *SchoolStorageAggregate) AddStorageAt(name, locationID string) (string, error) {
+	if !fp.Some(a.Locations, func(l Location) bool { return l.ID == locationID }) {
+		return "", ErrLocationIDNotFound(locationID)
+	}
+	return a.addStorage(name, LocationPath(a.Locations, locationID), locationID)
+}
+
+func (a *SchoolStorageAggregate) addStorage(name, location, locationID string) (string, error) {
+	if name == "" {
+		return "", ErrStorageNameNotSet
+	}
 	for _, storage := range a.Storages {
 		if storage.Name == name && storage.Location == location {
 			return "", ErrStorageAlreadyExists(name, location)
 		}
 	}
 	storageID := uuid.NewString()
-	event, err := NewStorageAdded(a, storageID, name, location)
+	event, err := NewStorageAdded(a, storageID, name, location, locationID)
 	if err != nil {
 		return "", err
 	}
@@ -36,6 +48,9 @@ func (a *SchoolStorageAggregate) RemoveStorage(storageID string, reason string)
 	if reason == "" {
 		return domain.ErrReasonNotSpecified
 	}
+	if a.Frozen(storageID) {
+		return ErrStorageFrozen(storageID)
+	}
 	event, err := NewStorageRemoved(a, storageID, reason)
 	if err != nil {
 		return err
@@ -76,13 +91,25 @@ func (a *SchoolStorageAggregate) RenameStorage(storageID string, name string, re
 }
 
 func (a *SchoolStorageAggregate) RelocateStorage(storageID string, location string, reason string) error {
+	if location == "" {
+		return ErrStorageLocationNotSet
+	}
+	return a.relocateStorage(storageID, location, "", reason)
+}
+
+// PlaceStorage moves a storage to one of the locations of the school.
+func (a *SchoolStorageAggregate) PlaceStorage(storageID, locationID, reason string) error {
+	if !fp.Some(a.Locations, func(l Location) bool { return l.ID == locationID }) {
+		return ErrLocationIDNotFound(locationID)
+	}
+	return a.relocateStorage(storageID, LocationPath(a.Locations, locationID), locationID, reason)
+}
+
+func (a *SchoolStorageAggregate) relocateStorage(storageID, location, locationID, reason string) error {
 	storage := fp.Find(a.Storages, func(s Storage) bool { return s.ID == storageID })
 	if storage == nil {
 		return ErrStorageIDNotFound(storageID)
 	}
-	if location == "" {
-		return ErrStorageLocationNotSet
-	}
 	if reason == "" {
 		return domain.ErrReasonNotSpecified
 	}
@@ -94,12 +121,505 @@ func (a *SchoolStorageAggregate) RelocateStorage(storageID string, location stri
 			}
 		}
 	}
-	event, err := NewStorageRelocated(a, storageID, location, reason)
+	event, err := NewStorageRelocated(a, storageID, location, locationID, reason)
+	if err != nil {
+		return err
+	}
+	if err := a.Apply(event); err != nil {
+		return err
+	}
+	return nil
+}
+
+// ChangeCapacity limits how many copies the storage takes. A capacity of zero
+// lifts the limit.
+func (a *SchoolStorageAggregate) ChangeCapacity(storageID string, capacity int, reason string) error {
+	storage := fp.Find(a.Storages, func(s Storage) bool { return s.ID == storageID })
+	if storage == nil {
+		return ErrStorageIDNotFound(storageID)
+	}
+	if capacity < 0 {
+		return ErrCapacityNegative
+	}
+	if reason == "" {
+		return domain.ErrReasonNotSpecified
+	}
+	if stored := storage.Stored(); capacity > 0 && stored > capacity {
+		return ErrCapacityBelowStock(storageID, stored, capacity)
+	}
+	event, err := NewStorageCapacityChanged(a, storageID, capacity, reason)
+	if err != nil {
+		return err
+	}
+	return a.Apply(event)
+}
+
+func (a *SchoolStorageAggregate) PutBooks(storageID, bookID string, isbn domain.Isbn, title string, quantity int) error {
+	return a.putBooks(storageID, bookID, isbn, title, quantity, "")
+}
+
+// ReceiveBooks puts delivered books into a storage. A receipt is booked only
+// once, so a delivery that is booked again is rejected.
+func (a *SchoolStorageAggregate) ReceiveBooks(receiptID, storageID, bookID string, isbn domain.Isbn, title string, quantity int) error {
+	if receiptID == "" {
+		return ErrReceiptIDNotSet
+	}
+	if a.Received(receiptID) {
+		return ErrReceiptAlreadyBooked(receiptID)
+	}
+	return a.putBooks(storageID, bookID, isbn, title, quantity, receiptID)
+}
+
+// Received reports whether the books of the receipt are already booked.
+func (a *SchoolStorageAggregate) Received(receiptID string) bool {
+	return fp.Some(a.Receipts, func(r string) bool { return r == receiptID })
+}
+
+func (a *SchoolStorageAggregate) putBooks(storageID, bookID string, isbn domain.Isbn, title string, quantity int, receiptID string) error {
+	storage := fp.Find(a.Storages, func(s Storage) bool { return s.ID == storageID })
+	if storage == nil {
+		return ErrStorageIDNotFound(storageID)
+	}
+	if bookID == "" {
+		return ErrBookIDNotSet
+	}
+	if quantity <= 0 {
+		return ErrQuantityNotPositive
+	}
+	if a.Frozen(storageID) {
+		return ErrStorageFrozen(storageID)
+	}
+	if !storage.Fits(quantity) {
+		return ErrCapacityExceeded(storageID, storage.Capacity, storage.Stored(), quantity)
+	}
+	event, err := NewBooksPut(a, storageID, bookID, isbn, title, quantity, receiptID)
+	if err != nil {
+		return err
+	}
+	return a.Apply(event)
+}
+
+func (a *SchoolStorageAggregate) TakeBooks(storageID, bookID string, quantity int, reason string) error {
+	storage := fp.Find(a.Storages, func(s Storage) bool { return s.ID == storageID })
+	if storage == nil {
+		return ErrStorageIDNotFound(storageID)
+	}
+	if bookID == "" {
+		return ErrBookIDNotSet
+	}
+	if quantity <= 0 {
+		return ErrQuantityNotPositive
+	}
+	if reason == "" {
+		return domain.ErrReasonNotSpecified
+	}
+	if a.Frozen(storageID) {
+		return ErrStorageFrozen(storageID)
+	}
+	if unheld := a.Unheld(storageID, bookID); unheld < quantity {
+		return ErrInsufficientStock(bookID, unheld, quantity)
+	}
+	event, err := NewBooksTaken(a, storageID, bookID, quantity, reason)
+	if err != nil {
+		return err
+	}
+	return a.Apply(event)
+}
+
+func (a *SchoolStorageAggregate) TransferBooks(fromStorageID, toStorageID, bookID string, quantity int, reason string) error {
+	from := fp.Find(a.Storages, func(s Storage) bool { return s.ID == fromStorageID })
+	if from == nil {
+		return ErrStorageIDNotFound(fromStorageID)
+	}
+	to := fp.Find(a.Storages, func(s Storage) bool { return s.ID == toStorageID })
+	if to == nil {
+		return ErrStorageIDNotFound(toStorageID)
+	}
+	if fromStorageID == toStorageID {
+		return ErrTransferToSameStorage
+	}
+	if bookID == "" {
+		return ErrBookIDNotSet
+	}
+	if quantity <= 0 {
+		return ErrQuantityNotPositive
+	}
+	if reason == "" {
+		return domain.ErrReasonNotSpecified
+	}
+	for _, storageID := range []string{fromStorageID, toStorageID} {
+		if a.Frozen(storageID) {
+			return ErrStorageFrozen(storageID)
+		}
+	}
+	stock := fp.Find(from.Stock, func(s BookStock) bool { return s.BookID == bookID })
+	if stock == nil || a.Unheld(fromStorageID, bookID) < quantity {
+		return ErrInsufficientStock(bookID, a.Unheld(fromStorageID, bookID), quantity)
+	}
+	if !to.Fits(quantity) {
+		return ErrCapacityExceeded(toStorageID, to.Capacity, to.Stored(), quantity)
+	}
+	event, err := NewBooksTransferred(a, fromStorageID, toStorageID, *stock, quantity, reason)
+	if err != nil {
+		return err
+	}
+	return a.Apply(event)
+}
+
+// LendBooksToClass takes copies of a book out of a storage to hand them out to
+// a class and returns the lent books.
+func (a *SchoolStorageAggregate) LendBooksToClass(storageID, classID, bookID string, quantity int) (BookStock, error) {
+	storage := fp.Find(a.Storages, func(s Storage) bool { return s.ID == storageID })
+	if storage == nil {
+		return BookStock{}, ErrStorageIDNotFound(storageID)
+	}
+	if classID == "" {
+		return BookStock{}, ErrClassIDNotSet
+	}
+	if bookID == "" {
+		return BookStock{}, ErrBookIDNotSet
+	}
+	if quantity <= 0 {
+		return BookStock{}, ErrQuantityNotPositive
+	}
+	if a.Frozen(storageID) {
+		return BookStock{}, ErrStorageFrozen(storageID)
+	}
+	stock := fp.Find(storage.Stock, func(s BookStock) bool { return s.BookID == bookID })
+	if stock == nil || a.Unheld(storageID, bookID) < quantity {
+		return BookStock{}, ErrInsufficientStock(bookID, a.Unheld(storageID, bookID), quantity)
+	}
+	lent := BookStock{BookID: stock.BookID, Isbn: stock.Isbn, Title: stock.Title, Quantity: quantity}
+	event, err := NewBooksLentToClass(a, storageID, classID, bookID, quantity)
+	if err != nil {
+		return BookStock{}, err
+	}
+	if err := a.Apply(event); err != nil {
+		return BookStock{}, err
+	}
+	return lent, nil
+}
+
+func (a *SchoolStorageAggregate) ReturnBooksFromClass(storageID, classID string, books BookStock) error {
+	storage := fp.Find(a.Storages, func(s Storage) bool { return s.ID == storageID })
+	if storage == nil {
+		return ErrStorageIDNotFound(storageID)
+	}
+	if classID == "" {
+		return ErrClassIDNotSet
+	}
+	if books.BookID == "" {
+		return ErrBookIDNotSet
+	}
+	if books.Quantity <= 0 {
+		return ErrQuantityNotPositive
+	}
+	if a.Frozen(storageID) {
+		return ErrStorageFrozen(storageID)
+	}
+	if !storage.Fits(books.Quantity) {
+		return ErrCapacityExceeded(storageID, storage.Capacity, storage.Stored(), books.Quantity)
+	}
+	event, err := NewBooksReturnedFromClass(a, storageID, classID, books)
+	if err != nil {
+		return err
+	}
+	return a.Apply(event)
+}
+
+// RecordDamage grades copies of a book in a storage down to a worse
+// condition. Copies that were not graded before are in good condition.
+func (a *SchoolStorageAggregate) RecordDamage(storageID, bookID string, from, condition Condition, quantity int, reason string) error {
+	storage := fp.Find(a.Storages, func(s Storage) bool { return s.ID == storageID })
+	if storage == nil {
+		return ErrStorageIDNotFound(storageID)
+	}
+	if bookID == "" {
+		return ErrBookIDNotSet
+	}
+	if quantity <= 0 {
+		return ErrQuantityNotPositive
+	}
+	if reason == "" {
+		return domain.ErrReasonNotSpecified
+	}
+	for _, c := range []Condition{from, condition} {
+		if !c.Valid() {
+			return ErrInvalidCondition(c)
+		}
+	}
+	if !condition.WorseThan(from) {
+		return ErrConditionNotWorse
+	}
+	if a.Frozen(storageID) {
+		return ErrStorageFrozen(storageID)
+	}
+	if graded := storage.InCondition(bookID, from); graded < quantity {
+		return ErrInsufficientBooksInCondition(bookID, from, graded, quantity)
+	}
+	event, err := NewBooksDamaged(a, storageID, bookID, from, condition, quantity, reason)
+	if err != nil {
+		return err
+	}
+	return a.Apply(event)
+}
+
+// WriteOffBooks removes damaged or lost copies of a book from a storage for
+// good.
+func (a *SchoolStorageAggregate) WriteOffBooks(storageID, bookID string, condition Condition, quantity int, reason string) error {
+	storage := fp.Find(a.Storages, func(s Storage) bool { return s.ID == storageID })
+	if storage == nil {
+		return ErrStorageIDNotFound(storageID)
+	}
+	if bookID == "" {
+		return ErrBookIDNotSet
+	}
+	if quantity <= 0 {
+		return ErrQuantityNotPositive
+	}
+	if reason == "" {
+		return domain.ErrReasonNotSpecified
+	}
+	if condition != Damaged && condition != Lost {
+		return ErrWriteOffUsableBooks
+	}
+	if a.Frozen(storageID) {
+		return ErrStorageFrozen(storageID)
+	}
+	if graded := storage.InCondition(bookID, condition); graded < quantity {
+		return ErrInsufficientBooksInCondition(bookID, condition, graded, quantity)
+	}
+	stock := fp.Find(storage.Stock, func(s BookStock) bool { return s.BookID == bookID })
+	books := BookStock{BookID: stock.BookID, Isbn: stock.Isbn, Title: stock.Title, Quantity: quantity}
+	event, err := NewBooksWrittenOff(a, storageID, books, condition, reason)
+	if err != nil {
+		return err
+	}
+	return a.Apply(event)
+}
+
+// AddLocation adds a building, floor, room or shelf to the school. Buildings
+// stand on their own, every other location lies within a location of an outer
+// kind. Locations within the same location have distinct names.
+func (a *SchoolStorageAggregate) AddLocation(kind LocationKind, name, parentID string) (string, error) {
+	if !kind.Valid() {
+		return "", ErrInvalidLocationKind(kind)
+	}
+	if name == "" {
+		return "", ErrLocationNameNotSet
+	}
+	if kind == Building && parentID != "" {
+		return "", ErrBuildingWithinLocation
+	}
+	if kind != Building {
+		parent := fp.Find(a.Locations, func(l Location) bool { return l.ID == parentID })
+		if parent == nil {
+			return "", ErrLocationIDNotFound(parentID)
+		}
+		if !parent.Kind.Contains(kind) {
+			return "", ErrLocationCanNotContain(parent.Kind, kind)
+		}
+	}
+	if fp.Some(a.Locations, func(l Location) bool { return l.ParentID == parentID && l.Name == name }) {
+		return "", ErrLocationAlreadyExists(name)
+	}
+	locationID := uuid.NewString()
+	event, err := NewLocationAdded(a, locationID, kind, name, parentID)
+	if err != nil {
+		return "", err
+	}
+	if err := a.Apply(event); err != nil {
+		return "", err
+	}
+	return locationID, nil
+}
+
+// RenameLocation renames the location and relocates the storages within it,
+// so their locations show the new name.
+func (a *SchoolStorageAggregate) RenameLocation(locationID, name, reason string) error {
+	location := fp.Find(a.Locations, func(l Location) bool { return l.ID == locationID })
+	if location == nil {
+		return ErrLocationIDNotFound(locationID)
+	}
+	if name == "" {
+		return ErrLocationNameNotSet
+	}
+	if reason == "" {
+		return domain.ErrReasonNotSpecified
+	}
+	if fp.Some(a.Locations, func(l Location) bool {
+		return l.ID != locationID && l.ParentID == location.ParentID && l.Name == name
+	}) {
+		return ErrLocationAlreadyExists(name)
+	}
+	event, err := NewLocationRenamed(a, locationID, name, reason)
 	if err != nil {
 		return err
 	}
 	if err := a.Apply(event); err != nil {
 		return err
 	}
+	within := LocationsWithin(a.Locations, locationID)
+	for _, storage := range a.Storages {
+		if storage.LocationID == "" || !fp.Some(within, func(id string) bool { return id == storage.LocationID }) {
+			continue
+		}
+		event, err := NewStorageRelocated(a, storage.ID, LocationPath(a.Locations, storage.LocationID), storage.LocationID, reason)
+		if err != nil {
+			return err
+		}
+		if err := a.Apply(event); err != nil {
+			return err
+		}
+	}
 	return nil
 }
+
+// RemoveLocation removes a location that neither holds other locations nor
+// storages.
+func (a *SchoolStorageAggregate) RemoveLocation(locationID, reason string) error {
+	if !fp.Some(a.Locations, func(l Location) bool { return l.ID == locationID }) {
+		return ErrLocationIDNotFound(locationID)
+	}
+	if reason == "" {
+		return domain.ErrReasonNotSpecified
+	}
+	if fp.Some(a.Locations, func(l Location) bool { return l.ParentID == locationID }) ||
+		fp.Some(a.Storages, func(s Storage) bool { return s.LocationID == locationID }) {
+		return ErrLocationNotEmpty(locationID)
+	}
+	event, err := NewLocationRemoved(a, locationID, reason)
+	if err != nil {
+		return err
+	}
+	return a.Apply(event)
+}
+
+// Unheld returns how many of the available copies of the book in the storage
+// are not held.
+func (a *SchoolStorageAggregate) Unheld(storageID, bookID string) int {
+	storage := fp.Find(a.Storages, func(s Storage) bool { return s.ID == storageID })
+	if storage == nil {
+		return 0
+	}
+	unheld := storage.Available(bookID)
+	for _, hold := range a.Holds {
+		if hold.StorageID == storageID && hold.BookID == bookID {
+			unheld -= hold.Quantity
+		}
+	}
+	if unheld < 0 {
+		return 0
+	}
+	return unheld
+}
+
+// Frozen reports whether the storage is counted by an open stocktaking.
+func (a *SchoolStorageAggregate) Frozen(storageID string) bool {
+	return fp.Some(a.Stocktakings, func(s Stocktaking) bool { return !s.Closed && s.Includes(storageID) })
+}
+
+// OpenStocktaking starts counting the storages and freezes their stock until
+// the stocktaking is approved.
+func (a *SchoolStorageAggregate) OpenStocktaking(storageIDs []string) (string, error) {
+	if len(storageIDs) == 0 {
+		return "", ErrNoStoragesToCount
+	}
+	for _, storageID := range storageIDs {
+		if !fp.Some(a.Storages, func(s Storage) bool { return s.ID == storageID }) {
+			return "", ErrStorageIDNotFound(storageID)
+		}
+		if a.Frozen(storageID) {
+			return "", ErrStorageFrozen(storageID)
+		}
+	}
+	stocktakingID := uuid.NewString()
+	event, err := NewStocktakingOpened(a, stocktakingID, storageIDs)
+	if err != nil {
+		return "", err
+	}
+	if err := a.Apply(event); err != nil {
+		return "", err
+	}
+	return stocktakingID, nil
+}
+
+// CountStock records the counted copies of a book in a storage. A later count
+// of the same book replaces the earlier one.
+func (a *SchoolStorageAggregate) CountStock(stocktakingID string, count StockCount) error {
+	stocktaking, err := a.openStocktaking(stocktakingID)
+	if err != nil {
+		return err
+	}
+	if !stocktaking.Includes(count.StorageID) {
+		return ErrStorageNotInStocktaking(count.StorageID, stocktakingID)
+	}
+	if count.BookID == "" {
+		return ErrBookIDNotSet
+	}
+	if count.Quantity < 0 {
+		return ErrQuantityNegative
+	}
+	event, err := NewStockCounted(a, stocktakingID, count)
+	if err != nil {
+		return err
+	}
+	return a.Apply(event)
+}
+
+func (a *SchoolStorageAggregate) StocktakingDiscrepancies(stocktakingID string) ([]Discrepancy, error) {
+	stocktaking := fp.Find(a.Stocktakings, func(s Stocktaking) bool { return s.ID == stocktakingID })
+	if stocktaking == nil {
+		return nil, ErrStocktakingWithIDNotFound(stocktakingID)
+	}
+	return Discrepancies(stocktaking.StorageIDs, stocktaking.Counts, a.stock), nil
+}
+
+// ApproveStocktaking corrects the stock of the storages to the counted copies,
+// closes the stocktaking and returns the corrected discrepancies.
+func (a *SchoolStorageAggregate) ApproveStocktaking(stocktakingID, reason string) ([]Discrepancy, error) {
+	if reason == "" {
+		return nil, domain.ErrReasonNotSpecified
+	}
+	stocktaking, err := a.openStocktaking(stocktakingID)
+	if err != nil {
+		return nil, err
+	}
+	discrepancies := Discrepancies(stocktaking.StorageIDs, stocktaking.Counts, a.stock)
+	for _, discrepancy := range discrepancies {
+		event, err := NewStockCorrected(a, stocktakingID, discrepancy, reason)
+		if err != nil {
+			return nil, err
+		}
+		if err := a.Apply(event); err != nil {
+			return nil, err
+		}
+	}
+	event, err := NewStocktakingClosed(a, stocktakingID)
+	if err != nil {
+		return nil, err
+	}
+	if err := a.Apply(event); err != nil {
+		return nil, err
+	}
+	return discrepancies, nil
+}
+
+func (a *SchoolStorageAggregate) openStocktaking(stocktakingID string) (*Stocktaking, error) {
+	stocktaking := fp.Find(a.Stocktakings, func(s Stocktaking) bool { return s.ID == stocktakingID })
+	if stocktaking == nil {
+		return nil, ErrStocktakingWithIDNotFound(stocktakingID)
+	}
+	if stocktaking.Closed {
+		return nil, ErrStocktakingClosed(stocktakingID)
+	}
+	return stocktaking, nil
+}
+
+func (a *SchoolStorageAggregate) stock(storageID string) []BookStock {
+	storage := fp.Find(a.Storages, func(s Storage) bool { return s.ID == storageID })
+	if storage == nil {
+		return []BookStock{}
+	}
+	return storage.Stock
+}
@@ -287,3 +287,792 @@ func TestSetStorageLocation(t *testing.T) {
 		})
 	}
 }
+
+func TestPutBooks(t *testing.T) {
+	storageID := uuid.NewString()
+	tests := []struct {
+		name        string
+		stock       []storagedomain.BookStock
+		storageID   string
+		bookID      string
+		quantity    int
+		expected    int
+		err         error
+		expectError bool
+	}{
+		{
+			name:        "put new book",
+			stock:       []storagedomain.BookStock{},
+			storageID:   storageID,
+			bookID:      "book",
+			quantity:    5,
+			expected:    5,
+			err:         nil,
+			expectError: false,
+		},
+		{
+			name:        "put more copies of a book",
+			stock:       []storagedomain.BookStock{{BookID: "book", Quantity: 3}},
+			storageID:   storageID,
+			bookID:      "book",
+			quantity:    5,
+			expected:    8,
+			err:         nil,
+			expectError: false,
+		},
+		{
+			name:        "put books into not existing storage",
+			stock:       []storagedomain.BookStock{},
+			storageID:   "unknown",
+			bookID:      "book",
+			quantity:    5,
+			err:         storagedomain.ErrStorageIDNotFound("unknown"),
+			expectError: true,
+		},
+		{
+			name:        "put books without book ID",
+			stock:       []storagedomain.BookStock{},
+			storageID:   storageID,
+			bookID:      "",
+			quantity:    5,
+			err:         storagedomain.ErrBookIDNotSet,
+			expectError: true,
+		},
+		{
+			name:        "put no books",
+			stock:       []storagedomain.BookStock{},
+			storageID:   storageID,
+			bookID:      "book",
+			quantity:    0,
+			err:         storagedomain.ErrQuantityNotPositive,
+			expectError: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			aggregate := initStorageAggregate([]storagedomain.Storage{{ID: storageID, Stock: test.stock}})
+			err := aggregate.PutBooks(test.storageID, test.bookID, "978-3-12-732320-7", "title", test.quantity)
+			if test.expectError {
+				assert.Error(t, err)
+				assert.Equal(t, test.err, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, storagedomain.BooksPut, aggregate.DomainEvents()[0].EventType())
+			assert.Equal(t, test.expected, aggregate.Storages[0].Quantity(test.bookID))
+		})
+	}
+}
+
+func TestReceiveBooks(t *testing.T) {
+	aggregate := initStorageAggregate([]storagedomain.Storage{{ID: "storage"}})
+	assert.Equal(t, storagedomain.ErrReceiptIDNotSet, aggregate.ReceiveBooks("", "storage", "book", "", "title", 5))
+	assert.NoError(t, aggregate.ReceiveBooks("order/book/0", "storage", "book", "", "title", 5))
+	assert.True(t, aggregate.Received("order/book/0"))
+	assert.Equal(t, storagedomain.ErrReceiptAlreadyBooked("order/book/0"), aggregate.ReceiveBooks("order/book/0", "storage", "book", "", "title", 5))
+	assert.NoError(t, aggregate.ReceiveBooks("order/book/5", "storage", "book", "", "title", 5))
+	assert.Equal(t, 10, aggregate.Storages[0].Quantity("book"))
+	assert.Len(t, aggregate.DomainEvents(), 2)
+}
+
+func TestTakeBooks(t *testing.T) {
+	storageID := uuid.NewString()
+	tests := []struct {
+		name        string
+		stock       []storagedomain.BookStock
+		quantity    int
+		reason      string
+		expected    int
+		stockLen    int
+		err         error
+		expectError bool
+	}{
+		{
+			name:        "take books",
+			stock:       []storagedomain.BookStock{{BookID: "book", Quantity: 5}},
+			quantity:    3,
+			reason:      "handed out",
+			expected:    2,
+			stockLen:    1,
+			err:         nil,
+			expectError: false,
+		},
+		{
+			name:        "take all books",
+			stock:       []storagedomain.BookStock{{BookID: "book", Quantity: 5}},
+			quantity:    5,
+			reason:      "handed out",
+			expected:    0,
+			stockLen:    0,
+			err:         nil,
+			expectError: false,
+		},
+		{
+			name:        "take more books than in stock",
+			stock:       []storagedomain.BookStock{{BookID: "book", Quantity: 2}},
+			quantity:    3,
+			reason:      "handed out",
+			err:         storagedomain.ErrInsufficientStock("book", 2, 3),
+			expectError: true,
+		},
+		{
+			name:        "take books not in stock",
+			stock:       []storagedomain.BookStock{},
+			quantity:    1,
+			reason:      "handed out",
+			err:         storagedomain.ErrInsufficientStock("book", 0, 1),
+			expectError: true,
+		},
+		{
+			name:        "take negative quantity",
+			stock:       []storagedomain.BookStock{{BookID: "book", Quantity: 2}},
+			quantity:    -1,
+			reason:      "handed out",
+			err:         storagedomain.ErrQuantityNotPositive,
+			expectError: true,
+		},
+		{
+			name:        "take books without reason",
+			stock:       []storagedomain.BookStock{{BookID: "book", Quantity: 2}},
+			quantity:    1,
+			reason:      "",
+			err:         domain.ErrReasonNotSpecified,
+			expectError: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			aggregate := initStorageAggregate([]storagedomain.Storage{{ID: storageID, Stock: test.stock}})
+			err := aggregate.TakeBooks(storageID, "book", test.quantity, test.reason)
+			if test.expectError {
+				assert.Error(t, err)
+				assert.Equal(t, test.err, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, storagedomain.BooksTaken, aggregate.DomainEvents()[0].EventType())
+			assert.Equal(t, test.expected, aggregate.Storages[0].Quantity("book"))
+			assert.Len(t, aggregate.Storages[0].Stock, test.stockLen)
+		})
+	}
+}
+
+func TestTransferBooks(t *testing.T) {
+	fromID := uuid.NewString()
+	toID := uuid.NewString()
+	tests := []struct {
+		name        string
+		toID        string
+		quantity    int
+		reason      string
+		err         error
+		expectError bool
+	}{
+		{
+			name:        "transfer books",
+			toID:        toID,
+			quantity:    3,
+			reason:      "moved to classroom",
+			err:         nil,
+			expectError: false,
+		},
+		{
+			name:        "transfer more books than in stock",
+			toID:        toID,
+			quantity:    6,
+			reason:      "moved to classroom",
+			err:         storagedomain.ErrInsufficientStock("book", 5, 6),
+			expectError: true,
+		},
+		{
+			name:        "transfer to not existing storage",
+			toID:        "unknown",
+			quantity:    3,
+			reason:      "moved to classroom",
+			err:         storagedomain.ErrStorageIDNotFound("unknown"),
+			expectError: true,
+		},
+		{
+			name:        "transfer to same storage",
+			toID:        fromID,
+			quantity:    3,
+			reason:      "moved to classroom",
+			err:         storagedomain.ErrTransferToSameStorage,
+			expectError: true,
+		},
+		{
+			name:        "transfer without reason",
+			toID:        toID,
+			quantity:    3,
+			reason:      "",
+			err:         domain.ErrReasonNotSpecified,
+			expectError: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			aggregate := initStorageAggregate([]storagedomain.Storage{
+				{ID: fromID, Stock: []storagedomain.BookStock{{BookID: "book", Isbn: "isbn", Title: "title", Quantity: 5}}},
+				{ID: toID, Stock: []storagedomain.BookStock{}},
+			})
+			err := aggregate.TransferBooks(fromID, test.toID, "book", test.quantity, test.reason)
+			if test.expectError {
+				assert.Error(t, err)
+				assert.Equal(t, test.err, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Len(t, aggregate.DomainEvents(), 1)
+			assert.Equal(t, storagedomain.BooksTransferred, aggregate.DomainEvents()[0].EventType())
+			assert.Equal(t, 2, aggregate.Storages[0].Quantity("book"))
+			assert.Equal(t, 3, aggregate.Storages[1].Quantity("book"))
+			assert.Equal(t, "title", aggregate.Storages[1].Stock[0].Title)
+		})
+	}
+}
+
+func TestLendBooksToClass(t *testing.T) {
+	storageID := uuid.NewString()
+	tests := []struct {
+		name        string
+		classID     string
+		quantity    int
+		err         error
+		expectError bool
+	}{
+		{
+			name:        "lend books",
+			classID:     "class",
+			quantity:    3,
+			err:         nil,
+			expectError: false,
+		},
+		{
+			name:        "lend more books than in stock",
+			classID:     "class",
+			quantity:    6,
+			err:         storagedomain.ErrInsufficientStock("book", 5, 6),
+			expectError: true,
+		},
+		{
+			name:        "lend books without class",
+			classID:     "",
+			quantity:    3,
+			err:         storagedomain.ErrClassIDNotSet,
+			expectError: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			aggregate := initStorageAggregate([]storagedomain.Storage{
+				{ID: storageID, Stock: []storagedomain.BookStock{{BookID: "book", Isbn: "isbn", Title: "title", Quantity: 5}}},
+			})
+			lent, err := aggregate.LendBooksToClass(storageID, test.classID, "book", test.quantity)
+			if test.expectError {
+				assert.Error(t, err)
+				assert.Equal(t, test.err, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, storagedomain.BookStock{BookID: "book", Isbn: "isbn", Title: "title", Quantity: test.quantity}, lent)
+			assert.Equal(t, storagedomain.BooksLentToClass, aggregate.DomainEvents()[0].EventType())
+			assert.Equal(t, 5-test.quantity, aggregate.Storages[0].Quantity("book"))
+		})
+	}
+}
+
+func TestReturnBooksFromClass(t *testing.T) {
+	storageID := uuid.NewString()
+	tests := []struct {
+		name        string
+		storageID   string
+		quantity    int
+		err         error
+		expectError bool
+	}{
+		{
+			name:        "return books",
+			storageID:   storageID,
+			quantity:    3,
+			err:         nil,
+			expectError: false,
+		},
+		{
+			name:        "return books to not existing storage",
+			storageID:   "unknown",
+			quantity:    3,
+			err:         storagedomain.ErrStorageIDNotFound("unknown"),
+			expectError: true,
+		},
+		{
+			name:        "return no books",
+			storageID:   storageID,
+			quantity:    0,
+			err:         storagedomain.ErrQuantityNotPositive,
+			expectError: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			aggregate := initStorageAggregate([]storagedomain.Storage{{ID: storageID, Stock: []storagedomain.BookStock{}}})
+			books := storagedomain.BookStock{BookID: "book", Title: "title", Quantity: test.quantity}
+			err := aggregate.ReturnBooksFromClass(test.storageID, "class", books)
+			if test.expectError {
+				assert.Error(t, err)
+				assert.Equal(t, test.err, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, storagedomain.BooksReturnedFromClass, aggregate.DomainEvents()[0].EventType())
+			assert.Equal(t, test.quantity, aggregate.Storages[0].Quantity("book"))
+		})
+	}
+}
+
+func TestOpenStocktaking(t *testing.T) {
+	tests := []struct {
+		name        string
+		storageIDs  []string
+		err         error
+		expectError bool
+	}{
+		{
+			name:        "open stocktaking",
+			storageIDs:  []string{"storage"},
+			err:         nil,
+			expectError: false,
+		},
+		{
+			name:        "open stocktaking without storages",
+			storageIDs:  []string{},
+			err:         storagedomain.ErrNoStoragesToCount,
+			expectError: true,
+		},
+		{
+			name:        "open stocktaking of not existing storage",
+			storageIDs:  []string{"unknown"},
+			err:         storagedomain.ErrStorageIDNotFound("unknown"),
+			expectError: true,
+		},
+		{
+			name:        "open stocktaking of frozen storage",
+			storageIDs:  []string{"frozen"},
+			err:         storagedomain.ErrStorageFrozen("frozen"),
+			expectError: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			aggregate := initStorageAggregate([]storagedomain.Storage{{ID: "storage"}, {ID: "frozen"}})
+			aggregate.Stocktakings = []storagedomain.Stocktaking{{ID: "open", StorageIDs: []string{"frozen"}}}
+			stocktakingID, err := aggregate.OpenStocktaking(test.storageIDs)
+			if test.expectError {
+				assert.Error(t, err)
+				assert.Equal(t, test.err, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.NotEmpty(t, stocktakingID)
+			assert.Equal(t, storagedomain.StocktakingOpened, aggregate.DomainEvents()[0].EventType())
+			assert.True(t, aggregate.Frozen("storage"))
+			assert.Equal(t, storagedomain.ErrStorageFrozen("storage"), aggregate.PutBooks("storage", "book", "isbn", "title", 1))
+		})
+	}
+}
+
+func TestCountStock(t *testing.T) {
+	tests := []struct {
+		name          string
+		stocktakingID string
+		count         storagedomain.StockCount
+		err           error
+		expectError   bool
+	}{
+		{
+			name:          "count stock",
+			stocktakingID: "open",
+			count:         storagedomain.StockCount{StorageID: "storage", BookID: "book", Quantity: 3},
+			err:           nil,
+			expectError:   false,
+		},
+		{
+			name:          "count stock of closed stocktaking",
+			stocktakingID: "closed",
+			count:         storagedomain.StockCount{StorageID: "storage", BookID: "book", Quantity: 3},
+			err:           storagedomain.ErrStocktakingClosed("closed"),
+			expectError:   true,
+		},
+		{
+			name:          "count stock of not existing stocktaking",
+			stocktakingID: "unknown",
+			count:         storagedomain.StockCount{StorageID: "storage", BookID: "book", Quantity: 3},
+			err:           storagedomain.ErrStocktakingWithIDNotFound("unknown"),
+			expectError:   true,
+		},
+		{
+			name:          "count stock of storage not in stocktaking",
+			stocktakingID: "open",
+			count:         storagedomain.StockCount{StorageID: "other", BookID: "book", Quantity: 3},
+			err:           storagedomain.ErrStorageNotInStocktaking("other", "open"),
+			expectError:   true,
+		},
+		{
+			name:          "count negative stock",
+			stocktakingID: "open",
+			count:         storagedomain.StockCount{StorageID: "storage", BookID: "book", Quantity: -1},
+			err:           storagedomain.ErrQuantityNegative,
+			expectError:   true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			aggregate := initStorageAggregate([]storagedomain.Storage{{ID: "storage"}, {ID: "other"}})
+			aggregate.Stocktakings = []storagedomain.Stocktaking{
+				{ID: "open", StorageIDs: []string{"storage"}},
+				{ID: "closed", StorageIDs: []string{"storage"}, Closed: true},
+			}
+			err := aggregate.CountStock(test.stocktakingID, test.count)
+			if test.expectError {
+				assert.Error(t, err)
+				assert.Equal(t, test.err, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, storagedomain.StockCounted, aggregate.DomainEvents()[0].EventType())
+			assert.Equal(t, []storagedomain.StockCount{test.count}, aggregate.Stocktakings[0].Counts)
+		})
+	}
+}
+
+func TestApproveStocktaking(t *testing.T) {
+	aggregate := initStorageAggregate([]storagedomain.Storage{{ID: "storage", Stock: []storagedomain.BookStock{
+		{BookID: "missing", Title: "missing", Quantity: 10},
+		{BookID: "uncounted", Title: "uncounted", Quantity: 2},
+		{BookID: "exact", Title: "exact", Quantity: 5},
+	}}})
+	stocktakingID, err := aggregate.OpenStocktaking([]string{"storage"})
+	assert.NoError(t, err)
+	for _, count := range []storagedomain.StockCount{
+		{StorageID: "storage", BookID: "missing", Quantity: 8},
+		{StorageID: "storage", BookID: "exact", Quantity: 5},
+		{StorageID: "storage", BookID: "found", Title: "found", Quantity: 1},
+	} {
+		assert.NoError(t, aggregate.CountStock(stocktakingID, count))
+	}
+	_, err = aggregate.ApproveStocktaking(stocktakingID, "")
+	assert.Equal(t, domain.ErrReasonNotSpecified, err)
+
+	discrepancies, err := aggregate.ApproveStocktaking(stocktakingID, "yearly stocktaking")
+	assert.NoError(t, err)
+	assert.Equal(t, []storagedomain.Discrepancy{
+		{StorageID: "storage", BookID: "missing", Title: "missing", Expected: 10, Counted: 8, Difference: -2},
+		{StorageID: "storage", BookID: "uncounted", Title: "uncounted", Expected: 2, Counted: 0, Difference: -2},
+		{StorageID: "storage", BookID: "found", Title: "found", Expected: 0, Counted: 1, Difference: 1},
+	}, discrepancies)
+	assert.Equal(t, 8, aggregate.Storages[0].Quantity("missing"))
+	assert.Equal(t, 0, aggregate.Storages[0].Quantity("uncounted"))
+	assert.Equal(t, 5, aggregate.Storages[0].Quantity("exact"))
+	assert.Equal(t, 1, aggregate.Storages[0].Quantity("found"))
+	assert.False(t, aggregate.Frozen("storage"))
+	_, err = aggregate.ApproveStocktaking(stocktakingID, "yearly stocktaking")
+	assert.Equal(t, storagedomain.ErrStocktakingClosed(stocktakingID), err)
+}
+
+func TestRecordDamage(t *testing.T) {
+	tests := []struct {
+		name        string
+		from        storagedomain.Condition
+		condition   storagedomain.Condition
+		quantity    int
+		reason      string
+		err         error
+		expectError bool
+	}{
+		{
+			name:        "record damage",
+			from:        storagedomain.Good,
+			condition:   storagedomain.Damaged,
+			quantity:    3,
+			reason:      "torn pages",
+			err:         nil,
+			expectError: false,
+		},
+		{
+			name:        "record better condition",
+			from:        storagedomain.Worn,
+			condition:   storagedomain.Good,
+			quantity:    1,
+			reason:      "repaired",
+			err:         storagedomain.ErrConditionNotWorse,
+			expectError: true,
+		},
+		{
+			name:        "record invalid condition",
+			from:        storagedomain.Good,
+			condition:   "broken",
+			quantity:    1,
+			reason:      "torn pages",
+			err:         storagedomain.ErrInvalidCondition("broken"),
+			expectError: true,
+		},
+		{
+			name:        "record damage of more books than in condition",
+			from:        storagedomain.Worn,
+			condition:   storagedomain.Damaged,
+			quantity:    3,
+			reason:      "torn pages",
+			err:         storagedomain.ErrInsufficientBooksInCondition("book", storagedomain.Worn, 2, 3),
+			expectError: true,
+		},
+		{
+			name:        "record damage without reason",
+			from:        storagedomain.Good,
+			condition:   storagedomain.Damaged,
+			quantity:    3,
+			reason:      "",
+			err:         domain.ErrReasonNotSpecified,
+			expectError: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			aggregate := initStorageAggregate([]storagedomain.Storage{{ID: "storage", Stock: []storagedomain.BookStock{{
+				BookID:     "book",
+				Quantity:   10,
+				Conditions: []storagedomain.ConditionCount{{Condition: storagedomain.Worn, Quantity: 2}},
+			}}}})
+			err := aggregate.RecordDamage("storage", "book", test.from, test.condition, test.quantity, test.reason)
+			if test.expectError {
+				assert.Error(t, err)
+				assert.Equal(t, test.err, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, storagedomain.BooksDamaged, aggregate.DomainEvents()[0].EventType())
+			assert.Equal(t, test.quantity, aggregate.Storages[0].InCondition("book", test.condition))
+			assert.Equal(t, 10, aggregate.Storages[0].Quantity("book"))
+		})
+	}
+}
+
+func TestWriteOffBooks(t *testing.T) {
+	tests := []struct {
+		name        string
+		condition   storagedomain.Condition
+		quantity    int
+		err         error
+		expectError bool
+	}{
+		{
+			name:        "write off lost books",
+			condition:   storagedomain.Lost,
+			quantity:    2,
+			err:         nil,
+			expectError: false,
+		},
+		{
+			name:        "write off usable books",
+			condition:   storagedomain.Worn,
+			quantity:    1,
+			err:         storagedomain.ErrWriteOffUsableBooks,
+			expectError: true,
+		},
+		{
+			name:        "write off more books than lost",
+			condition:   storagedomain.Lost,
+			quantity:    3,
+			err:         storagedomain.ErrInsufficientBooksInCondition("book", storagedomain.Lost, 2, 3),
+			expectError: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			aggregate := initStorageAggregate([]storagedomain.Storage{{ID: "storage", Stock: []storagedomain.BookStock{{
+				BookID:     "book",
+				Quantity:   10,
+				Conditions: []storagedomain.ConditionCount{{Condition: storagedomain.Lost, Quantity: 2}},
+			}}}})
+			assert.Equal(t, storagedomain.ErrInsufficientStock("book", 8, 9), aggregate.TakeBooks("storage", "book", 9, "handed out"))
+			err := aggregate.WriteOffBooks("storage", "book", test.condition, test.quantity, "lost by pupils")
+			if test.expectError {
+				assert.Error(t, err)
+				assert.Equal(t, test.err, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, storagedomain.BooksWrittenOff, aggregate.DomainEvents()[0].EventType())
+			assert.Equal(t, 8, aggregate.Storages[0].Quantity("book"))
+			assert.Equal(t, 8, aggregate.Storages[0].Available("book"))
+		})
+	}
+}
+
+func initLocations() []storagedomain.Location {
+	return []storagedomain.Location{
+		{ID: "main", Kind: storagedomain.Building, Name: "Main building"},
+		{ID: "first", Kind: storagedomain.Floor, Name: "1st floor", ParentID: "main"},
+		{ID: "101", Kind: storagedomain.Room, Name: "Room 101", ParentID: "first"},
+	}
+}
+
+func TestAddLocation(t *testing.T) {
+	tests := []struct {
+		name        string
+		kind        storagedomain.LocationKind
+		location    string
+		parentID    string
+		err         error
+		expectError bool
+	}{
+		{
+			name:     "add building",
+			kind:     storagedomain.Building,
+			location: "Gym",
+		},
+		{
+			name:     "add room within a building",
+			kind:     storagedomain.Room,
+			location: "Library",
+			parentID: "main",
+		},
+		{
+			name:        "add location of unknown kind",
+			kind:        "cupboard",
+			location:    "Cupboard",
+			parentID:    "101",
+			err:         storagedomain.ErrInvalidLocationKind("cupboard"),
+			expectError: true,
+		},
+		{
+			name:        "add location without name",
+			kind:        storagedomain.Shelf,
+			parentID:    "101",
+			err:         storagedomain.ErrLocationNameNotSet,
+			expectError: true,
+		},
+		{
+			name:        "add building within a building",
+			kind:        storagedomain.Building,
+			location:    "Annex",
+			parentID:    "main",
+			err:         storagedomain.ErrBuildingWithinLocation,
+			expectError: true,
+		},
+		{
+			name:        "add room within an unknown location",
+			kind:        storagedomain.Room,
+			location:    "Room 102",
+			parentID:    "unknown",
+			err:         storagedomain.ErrLocationIDNotFound("unknown"),
+			expectError: true,
+		},
+		{
+			name:        "add floor within a room",
+			kind:        storagedomain.Floor,
+			location:    "2nd floor",
+			parentID:    "101",
+			err:         storagedomain.ErrLocationCanNotContain(storagedomain.Room, storagedomain.Floor),
+			expectError: true,
+		},
+		{
+			name:        "add room twice",
+			kind:        storagedomain.Room,
+			location:    "Room 101",
+			parentID:    "first",
+			err:         storagedomain.ErrLocationAlreadyExists("Room 101"),
+			expectError: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			aggregate := initStorageAggregate([]storagedomain.Storage{})
+			aggregate.Locations = initLocations()
+			locationID, err := aggregate.AddLocation(test.kind, test.location, test.parentID)
+			if test.expectError {
+				assert.Error(t, err)
+				assert.Equal(t, test.err, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, storagedomain.LocationAdded, aggregate.DomainEvents()[0].EventType())
+			assert.Len(t, aggregate.Locations, 4)
+			assert.Equal(t, locationID, aggregate.Locations[3].ID)
+		})
+	}
+}
+
+func TestAddStorageAt(t *testing.T) {
+	aggregate := initStorageAggregate([]storagedomain.Storage{})
+	aggregate.Locations = initLocations()
+	_, err := aggregate.AddStorageAt("closet", "unknown")
+	assert.Equal(t, storagedomain.ErrLocationIDNotFound("unknown"), err)
+	_, err = aggregate.AddStorageAt("closet", "101")
+	assert.NoError(t, err)
+	assert.Equal(t, "Main building / 1st floor / Room 101", aggregate.Storages[0].Location)
+	assert.Equal(t, "101", aggregate.Storages[0].LocationID)
+}
+
+func TestRenameLocation(t *testing.T) {
+	aggregate := initStorageAggregate([]storagedomain.Storage{
+		{ID: "closet", Name: "closet", Location: "Main building / 1st floor / Room 101", LocationID: "101"},
+		{ID: "cellar", Name: "cellar", Location: "basement"},
+	})
+	aggregate.Locations = initLocations()
+	assert.Equal(t, storagedomain.ErrLocationIDNotFound("unknown"), aggregate.RenameLocation("unknown", "Old building", "renovated"))
+	assert.Equal(t, storagedomain.ErrLocationNameNotSet, aggregate.RenameLocation("main", "", "renovated"))
+	assert.Equal(t, domain.ErrReasonNotSpecified, aggregate.RenameLocation("main", "Old building", ""))
+
+	assert.NoError(t, aggregate.RenameLocation("main", "Old building", "renovated"))
+	assert.Len(t, aggregate.DomainEvents(), 2)
+	assert.Equal(t, storagedomain.LocationRenamed, aggregate.DomainEvents()[0].EventType())
+	assert.Equal(t, storagedomain.StorageRelocated, aggregate.DomainEvents()[1].EventType())
+	assert.Equal(t, "Old building / 1st floor / Room 101", aggregate.Storages[0].Location)
+	assert.Equal(t, "101", aggregate.Storages[0].LocationID)
+	assert.Equal(t, "basement", aggregate.Storages[1].Location)
+}
+
+func TestRemoveLocation(t *testing.T) {
+	aggregate := initStorageAggregate([]storagedomain.Storage{{ID: "closet", LocationID: "101"}})
+	aggregate.Locations = initLocations()
+	assert.Equal(t, storagedomain.ErrLocationIDNotFound("unknown"), aggregate.RemoveLocation("unknown", "demolished"))
+	assert.Equal(t, domain.ErrReasonNotSpecified, aggregate.RemoveLocation("101", ""))
+	assert.Equal(t, storagedomain.ErrLocationNotEmpty("first"), aggregate.RemoveLocation("first", "demolished"))
+	assert.Equal(t, storagedomain.ErrLocationNotEmpty("101"), aggregate.RemoveLocation("101", "demolished"))
+
+	assert.NoError(t, aggregate.PlaceStorage("closet", "first", "moved"))
+	assert.NoError(t, aggregate.RemoveLocation("101", "demolished"))
+	assert.Len(t, aggregate.Locations, 2)
+}
+
+func TestChangeCapacity(t *testing.T) {
+	aggregate := initStorageAggregate([]storagedomain.Storage{{
+		ID:    "storage",
+		Stock: []storagedomain.BookStock{{BookID: "book", Quantity: 5}, {BookID: "other", Quantity: 3}},
+	}})
+	assert.Equal(t, storagedomain.ErrStorageIDNotFound("unknown"), aggregate.ChangeCapacity("unknown", 10, "new shelf"))
+	assert.Equal(t, storagedomain.ErrCapacityNegative, aggregate.ChangeCapacity("storage", -1, "new shelf"))
+	assert.Equal(t, storagedomain.ErrCapacityBelowStock("storage", 8, 7), aggregate.ChangeCapacity("storage", 7, "new shelf"))
+
+	assert.NoError(t, aggregate.ChangeCapacity("storage", 10, "new shelf"))
+	assert.Equal(t, 10, aggregate.Storages[0].Capacity)
+	assert.Equal(t, storagedomain.ErrCapacityExceeded("storage", 10, 8, 3), aggregate.PutBooks("storage", "book", "", "title", 3))
+	assert.NoError(t, aggregate.PutBooks("storage", "book", "", "title", 2))
+
+	assert.NoError(t, aggregate.ChangeCapacity("storage", 0, "shelf removed"))
+	assert.NoError(t, aggregate.PutBooks("storage", "book", "", "title", 20))
+}
+
+func TestHeldBooks(t *testing.T) {
+	aggregate := initStorageAggregate([]storagedomain.Storage{
+		{ID: "storage", Stock: []storagedomain.BookStock{{BookID: "book", Quantity: 30}}},
+		{ID: "other"},
+	})
+	aggregate.Holds = []storagedomain.Hold{{StorageID: "storage", BookID: "book", Quantity: 25}}
+	assert.Equal(t, 5, aggregate.Unheld("storage", "book"))
+	_, err := aggregate.LendBooksToClass("storage", "class", "book", 6)
+	assert.Equal(t, storagedomain.ErrInsufficientStock("book", 5, 6), err)
+	assert.Equal(t, storagedomain.ErrInsufficientStock("book", 5, 6), aggregate.TransferBooks("storage", "other", "book", 6, "moved"))
+	assert.Equal(t, storagedomain.ErrInsufficientStock("book", 5, 6), aggregate.TakeBooks("storage", "book", 6, "handed out"))
+	assert.NoError(t, aggregate.TransferBooks("storage", "other", "book", 5, "moved"))
+	assert.Equal(t, 0, aggregate.Unheld("storage", "book"))
+}
@@ -1,22 +1,382 @@
 package storagedomain
 
 import (
+	"strings"
 	"time"
+
+	"github.com/kammeph/school-book-storage-service/domain"
 )
 
+// BookStock is a lot of copies of a book in a storage. Copies that are not
+// graded otherwise are in good condition.
+type BookStock struct {
+	BookID     string
+	Isbn       domain.Isbn
+	Title      string
+	Quantity   int
+	Conditions []ConditionCount
+}
+
+// Available returns the copies of the lot that can be handed out. Lost copies
+// stay in the stock until they are written off.
+func (s BookStock) Available() int {
+	return s.Quantity - InCondition(s.Quantity, s.Conditions, Lost)
+}
+
+// Storage is a place books are kept in. Location describes where the storage
+// is and is the path of the referenced location if LocationID is set. A
+// storage without a capacity takes any number of books.
 type Storage struct {
+	ID         string
+	Name       string
+	Location   string
+	LocationID string
+	Capacity   int
+	Stock      []BookStock
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+func NewStorage(id, name, location string, timeStamp time.Time) Storage {
+	return Storage{
+		ID:        id,
+		Name:      name,
+		Location:  location,
+		Stock:     []BookStock{},
+		CreatedAt: timeStamp,
+	}
+}
+
+// Quantity returns how many copies of the book are in the storage.
+func (s Storage) Quantity(bookID string) int {
+	for _, stock := range s.Stock {
+		if stock.BookID == bookID {
+			return stock.Quantity
+		}
+	}
+	return 0
+}
+
+// Stored returns how many copies of all books are in the storage.
+func (s Storage) Stored() int {
+	stored := 0
+	for _, stock := range s.Stock {
+		stored += stock.Quantity
+	}
+	return stored
+}
+
+// Fits reports whether the quantity of copies can be added to the storage
+// without exceeding its capacity.
+func (s Storage) Fits(quantity int) bool {
+	return s.Capacity == 0 || s.Stored()+quantity <= s.Capacity
+}
+
+// Hold keeps copies of a book in a storage from being handed out or moved
+// elsewhere, e.g. because they are reserved.
+type Hold struct {
+	StorageID string
+	BookID    string
+	Quantity  int
+}
+
+// Available returns how many copies of the book in the storage can be handed
+// out.
+func (s Storage) Available(bookID string) int {
+	for _, stock := range s.Stock {
+		if stock.BookID == bookID {
+			return stock.Available()
+		}
+	}
+	return 0
+}
+
+// InCondition returns how many copies of the book in the storage are in the
+// condition.
+func (s Storage) InCondition(bookID string, condition Condition) int {
+	for _, stock := range s.Stock {
+		if stock.BookID == bookID {
+			return InCondition(stock.Quantity, stock.Conditions, condition)
+		}
+	}
+	return 0
+}
+
+type LocationKind string
+
+const (
+	Building LocationKind = "building"
+	Floor    LocationKind = "floor"
+	Room     LocationKind = "room"
+	Shelf    LocationKind = "shelf"
+)
+
+// LocationKinds are ordered from the outermost to the innermost kind.
+var LocationKinds = []LocationKind{Building, Floor, Room, Shelf}
+
+func (k LocationKind) rank() int {
+	for idx, kind := range LocationKinds {
+		if kind == k {
+			return idx
+		}
+	}
+	return -1
+}
+
+func (k LocationKind) Valid() bool {
+	return k.rank() >= 0
+}
+
+// Contains reports whether a location of the kind can hold a location of the
+// other kind, e.g. a building a room without a floor in between.
+func (k LocationKind) Contains(other LocationKind) bool {
+	return k.Valid() && other.rank() > k.rank()
+}
+
+// Location is a building, floor, room or shelf of a school. Every location but
+// a building lies within a location of an outer kind.
+type Location struct {
 	ID        string
+	Kind      LocationKind
 	Name      string
-	Location  string
+	ParentID  string
 	CreatedAt time.Time
 	UpdatedAt time.Time
 }
 
-func NewStorage(id, name, location string, timeStamp time.Time) Storage {
-	return Storage{
+func NewLocation(id string, kind LocationKind, name, parentID string, timeStamp time.Time) Location {
+	return Location{
 		ID:        id,
+		Kind:      kind,
 		Name:      name,
-		Location:  location,
+		ParentID:  parentID,
 		CreatedAt: timeStamp,
 	}
 }
+
+// LocationPath joins the names of the location and the locations it lies
+// within from the outermost to the innermost, e.g. Main building / 1st floor /
+// Room 101.
+func LocationPath(locations []Location, locationID string) string {
+	names := []string{}
+	for id := locationID; id != ""; {
+		location := findLocation(locations, id)
+		if location == nil {
+			break
+		}
+		names = append([]string{location.Name}, names...)
+		id = location.ParentID
+	}
+	return strings.Join(names, " / ")
+}
+
+// LocationsWithin returns the IDs of the location and of all locations that
+// lie within it.
+func LocationsWithin(locations []Location, locationID string) []string {
+	ids := []string{locationID}
+	for idx := 0; idx < len(ids); idx++ {
+		for _, location := range locations {
+			if location.ParentID == ids[idx] {
+				ids = append(ids, location.ID)
+			}
+		}
+	}
+	return ids
+}
+
+func findLocation(locations []Location, locationID string) *Location {
+	for idx := range locations {
+		if locations[idx].ID == locationID {
+			return &locations[idx]
+		}
+	}
+	return nil
+}
+
+type Condition string
+
+const (
+	New     Condition = "new"
+	Good    Condition = "good"
+	Worn    Condition = "worn"
+	Damaged Condition = "damaged"
+	Lost    Condition = "lost"
+)
+
+// Conditions are ordered from the best to the worst condition.
+var Conditions = []Condition{New, Good, Worn, Damaged, Lost}
+
+func (c Condition) rank() int {
+	for idx, condition := range Conditions {
+		if condition == c {
+			return idx
+		}
+	}
+	return -1
+}
+
+func (c Condition) Valid() bool {
+	return c.rank() >= 0
+}
+
+func (c Condition) WorseThan(other Condition) bool {
+	return c.rank() > other.rank()
+}
+
+type ConditionCount struct {
+	Condition Condition `json:"condition" bson:"condition"`
+	Quantity  int       `json:"quantity" bson:"quantity"`
+}
+
+// InCondition returns how many of the copies are in the condition. Only
+// graded copies are counted, the rest of the copies is in good condition.
+func InCondition(quantity int, counts []ConditionCount, condition Condition) int {
+	if condition == Good {
+		graded := 0
+		for _, count := range counts {
+			graded += count.Quantity
+		}
+		return quantity - graded
+	}
+	for _, count := range counts {
+		if count.Condition == condition {
+			return count.Quantity
+		}
+	}
+	return 0
+}
+
+// Regrade moves copies from one condition to another.
+func Regrade(counts []ConditionCount, from, to Condition, quantity int) []ConditionCount {
+	return addCondition(addCondition(counts, from, -quantity), to, quantity)
+}
+
+// Withdraw keeps the graded copies in line with the copies that are left after
+// some were taken. Good copies leave first, then the others from the best to
+// the worst condition.
+func Withdraw(counts []ConditionCount, quantity int) []ConditionCount {
+	surplus := -InCondition(quantity, counts, Good)
+	for _, condition := range Conditions {
+		if surplus <= 0 {
+			break
+		}
+		taken := InCondition(quantity, counts, condition)
+		if condition == Good || taken == 0 {
+			continue
+		}
+		if taken > surplus {
+			taken = surplus
+		}
+		counts = addCondition(counts, condition, -taken)
+		surplus -= taken
+	}
+	return counts
+}
+
+func addCondition(counts []ConditionCount, condition Condition, quantity int) []ConditionCount {
+	if condition == Good || quantity == 0 {
+		return counts
+	}
+	result := []ConditionCount{}
+	found := false
+	for _, count := range counts {
+		if count.Condition == condition {
+			count.Quantity += quantity
+			found = true
+		}
+		if count.Quantity > 0 {
+			result = append(result, count)
+		}
+	}
+	if !found && quantity > 0 {
+		result = append(result, ConditionCount{condition, quantity})
+	}
+	return result
+}
+
+type StockCount struct {
+	StorageID string      `json:"storageId" bson:"storageId"`
+	BookID    string      `json:"bookId" bson:"bookId"`
+	Isbn      domain.Isbn `json:"isbn" bson:"isbn"`
+	Title     string      `json:"title" bson:"title"`
+	Quantity  int         `json:"quantity" bson:"quantity"`
+}
+
+// Stocktaking is a count of the books in some storages. The stock of the
+// storages can not change while the stocktaking is open.
+type Stocktaking struct {
+	ID         string
+	StorageIDs []string
+	Counts     []StockCount
+	Closed     bool
+	OpenedAt   time.Time
+	ClosedAt   time.Time
+}
+
+func NewStocktaking(id string, storageIDs []string, timeStamp time.Time) Stocktaking {
+	return Stocktaking{
+		ID:         id,
+		StorageIDs: storageIDs,
+		Counts:     []StockCount{},
+		OpenedAt:   timeStamp,
+	}
+}
+
+func (s Stocktaking) Includes(storageID string) bool {
+	for _, id := range s.StorageIDs {
+		if id == storageID {
+			return true
+		}
+	}
+	return false
+}
+
+// Discrepancy is the difference between the counted and the recorded copies
+// of a book in a storage.
+type Discrepancy struct {
+	StorageID  string      `json:"storageId"`
+	BookID     string      `json:"bookId"`
+	Isbn       domain.Isbn `json:"isbn"`
+	Title      string      `json:"title"`
+	Expected   int         `json:"expected"`
+	Counted    int         `json:"counted"`
+	Difference int         `json:"difference"`
+}
+
+// Discrepancies compares the counts with the recorded stock of the storages.
+// Recorded books that were not counted are missing.
+func Discrepancies(storageIDs []string, counts []StockCount, stock func(storageID string) []BookStock) []Discrepancy {
+	discrepancies := []Discrepancy{}
+	for _, storageID := range storageIDs {
+		recorded := stock(storageID)
+		for _, book := range recorded {
+			counted := 0
+			for _, count := range counts {
+				if count.StorageID == storageID && count.BookID == book.BookID {
+					counted = count.Quantity
+				}
+			}
+			if counted != book.Quantity {
+				discrepancies = append(discrepancies, Discrepancy{storageID, book.BookID, book.Isbn, book.Title, book.Quantity, counted, counted - book.Quantity})
+			}
+		}
+		for _, count := range counts {
+			if count.StorageID != storageID || count.Quantity == 0 {
+				continue
+			}
+			if !containsBook(recorded, count.BookID) {
+				discrepancies = append(discrepancies, Discrepancy{storageID, count.BookID, count.Isbn, count.Title, 0, count.Quantity, count.Quantity})
+			}
+		}
+	}
+	return discrepancies
+}
+
+func containsBook(stock []BookStock, bookID string) bool {
+	for _, book := range stock {
+		if book.BookID == bookID {
+			return true
+		}
+	}
+	return false
+}
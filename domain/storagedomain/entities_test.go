@@ -0,0 +1,26 @@
+package storagedomain_test
+
+import (
+	"testing"
+
+	"github.com/kammeph/school-book-storage-service/domain/storagedomain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConditions(t *testing.T) {
+	counts := storagedomain.Regrade(nil, storagedomain.Good, storagedomain.Damaged, 3)
+	counts = storagedomain.Regrade(counts, storagedomain.Good, storagedomain.Lost, 2)
+	counts = storagedomain.Regrade(counts, storagedomain.Damaged, storagedomain.Lost, 1)
+	assert.Equal(t, []storagedomain.ConditionCount{{Condition: storagedomain.Damaged, Quantity: 2}, {Condition: storagedomain.Lost, Quantity: 3}}, counts)
+	assert.Equal(t, 5, storagedomain.InCondition(10, counts, storagedomain.Good))
+	assert.Equal(t, 7, storagedomain.BookStock{Quantity: 10, Conditions: counts}.Available())
+
+	// good copies are taken first, then the damaged ones
+	assert.Equal(t, counts, storagedomain.Withdraw(counts, 5))
+	assert.Equal(t, []storagedomain.ConditionCount{{Condition: storagedomain.Damaged, Quantity: 1}, {Condition: storagedomain.Lost, Quantity: 3}}, storagedomain.Withdraw(counts, 4))
+	assert.Equal(t, []storagedomain.ConditionCount{{Condition: storagedomain.Lost, Quantity: 3}}, storagedomain.Withdraw(counts, 3))
+
+	assert.True(t, storagedomain.Worn.WorseThan(storagedomain.New))
+	assert.False(t, storagedomain.Good.WorseThan(storagedomain.Damaged))
+	assert.False(t, storagedomain.Condition("broken").Valid())
+}
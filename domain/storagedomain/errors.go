@@ -6,8 +6,20 @@ import (
 )
 
 var (
-	ErrStorageNameNotSet     = errors.New("storage name not set")
-	ErrStorageLocationNotSet = errors.New("storage location not set")
+	ErrStorageNameNotSet      = errors.New("storage name not set")
+	ErrStorageLocationNotSet  = errors.New("storage location not set")
+	ErrBookIDNotSet           = errors.New("book ID not set")
+	ErrQuantityNotPositive    = errors.New("quantity must be greater than zero")
+	ErrTransferToSameStorage  = errors.New("books can not be transferred to the storage they are in")
+	ErrClassIDNotSet          = errors.New("class ID not set")
+	ErrNoStoragesToCount      = errors.New("a stocktaking needs at least one storage")
+	ErrQuantityNegative       = errors.New("quantity must not be negative")
+	ErrConditionNotWorse      = errors.New("damaged books must be in a worse condition than before")
+	ErrWriteOffUsableBooks    = errors.New("only damaged or lost books can be written off")
+	ErrCapacityNegative       = errors.New("capacity must not be negative")
+	ErrLocationNameNotSet     = errors.New("location name not set")
+	ErrBuildingWithinLocation = errors.New("a building can not lie within another location")
+	ErrReceiptIDNotSet        = errors.New("receipt ID not set")
 )
 
 func ErrStoragesWithIdAlreadyExists(id string) error {
@@ -29,3 +41,67 @@ func ErrStorageByNameNotFound(name string) error {
 func ErrMultipleStoragesWithNameFound(name string) error {
 	return fmt.Errorf("there are more than one storage with the name %s", name)
 }
+
+func ErrInsufficientStock(bookID string, available, requested int) error {
+	return fmt.Errorf("can not take %d copies of book %s, only %d in storage", requested, bookID, available)
+}
+
+func ErrStorageFrozen(storageID string) error {
+	return fmt.Errorf("the stock of storage %s is frozen by an open stocktaking", storageID)
+}
+
+func ErrStocktakingWithIDNotFound(id string) error {
+	return fmt.Errorf("stocktaking with ID %s not found", id)
+}
+
+func ErrStocktakingClosed(id string) error {
+	return fmt.Errorf("stocktaking with ID %s is closed", id)
+}
+
+func ErrStorageNotInStocktaking(storageID, stocktakingID string) error {
+	return fmt.Errorf("storage %s is not counted in stocktaking %s", storageID, stocktakingID)
+}
+
+func ErrInvalidCondition(condition Condition) error {
+	return fmt.Errorf("%s is not a valid condition", condition)
+}
+
+func ErrInsufficientBooksInCondition(bookID string, condition Condition, available, requested int) error {
+	return fmt.Errorf("can not grade %d copies of book %s, only %d are %s", requested, bookID, available, condition)
+}
+
+func ErrCapacityExceeded(storageID string, capacity, stored, requested int) error {
+	return fmt.Errorf("can not store %d more copies in storage %s, it holds %d of %d", requested, storageID, stored, capacity)
+}
+
+func ErrCapacityBelowStock(storageID string, stored, capacity int) error {
+	return fmt.Errorf("storage %s holds %d copies, more than a capacity of %d", storageID, stored, capacity)
+}
+
+func ErrInvalidLocationKind(kind LocationKind) error {
+	return fmt.Errorf("%s is not a valid kind of location", kind)
+}
+
+func ErrLocationIDNotFound(id string) error {
+	return fmt.Errorf("location with ID %s not found", id)
+}
+
+func ErrLocationCanNotContain(kind, other LocationKind) error {
+	return fmt.Errorf("a %s can not lie within a %s", other, kind)
+}
+
+func ErrLocationAlreadyExists(name string) error {
+	return fmt.Errorf("location with name %s already exists", name)
+}
+
+func ErrLocationNotEmpty(id string) error {
+	return fmt.Errorf("location with ID %s still holds locations or storages", id)
+}
+
+func ErrApplyEventLocationNotFound(eventType, locationID string) error {
+	return fmt.Errorf("can not apply %s: location %s not found", eventType, locationID)
+}
+
+func ErrReceiptAlreadyBooked(receiptID string) error {
+	return fmt.Errorf("the books of receipt %s are already booked", receiptID)
+}
@@ -3,25 +3,42 @@ package storagedomain
 import "github.com/kammeph/school-book-storage-service/domain"
 
 var (
-	StorageAdded     = "STORAGE_ADDED"
-	StorageRemoved   = "STORAGE_REMOVED"
-	StorageRenamed   = "STORAGE_RENAMED"
-	StorageRelocated = "STORAGE_RELOCATED"
+	StorageAdded           = "STORAGE_ADDED"
+	StorageRemoved         = "STORAGE_REMOVED"
+	StorageRenamed         = "STORAGE_RENAMED"
+	StorageRelocated       = "STORAGE_RELOCATED"
+	BooksPut               = "BOOKS_PUT"
+	BooksTaken             = "BOOKS_TAKEN"
+	BooksTransferred       = "BOOKS_TRANSFERRED"
+	BooksLentToClass       = "BOOKS_LENT_TO_CLASS"
+	BooksReturnedFromClass = "BOOKS_RETURNED_FROM_CLASS"
+	StocktakingOpened      = "STOCKTAKING_OPENED"
+	StockCounted           = "STOCK_COUNTED"
+	StockCorrected         = "STOCK_CORRECTED"
+	StocktakingClosed      = "STOCKTAKING_CLOSED"
+	BooksDamaged           = "BOOKS_DAMAGED"
+	BooksWrittenOff        = "BOOKS_WRITTEN_OFF"
+	LocationAdded          = "LOCATION_ADDED"
+	LocationRenamed        = "LOCATION_RENAMED"
+	LocationRemoved        = "LOCATION_REMOVED"
+	StorageCapacityChanged = "STORAGE_CAPACITY_CHANGED"
 )
 
 type StorageAddedEvent struct {
-	SchoolID  string `json:"schoolId"`
-	StorageID string `json:"storageId"`
-	Name      string `json:"name"`
-	Location  string `json:"location"`
+	SchoolID   string `json:"schoolId"`
+	StorageID  string `json:"storageId"`
+	Name       string `json:"name"`
+	Location   string `json:"location"`
+	LocationID string `json:"locationId,omitempty"`
 }
 
-func NewStorageAdded(aggregate *SchoolStorageAggregate, storageID, name, location string) (domain.Event, error) {
+func NewStorageAdded(aggregate *SchoolStorageAggregate, storageID, name, location, locationID string) (domain.Event, error) {
 	eventData := StorageAddedEvent{
-		SchoolID:  aggregate.AggregateID(),
-		StorageID: storageID,
-		Name:      name,
-		Location:  location,
+		SchoolID:   aggregate.AggregateID(),
+		StorageID:  storageID,
+		Name:       name,
+		Location:   location,
+		LocationID: locationID,
 	}
 	event := domain.NewEvent(aggregate, StorageAdded)
 	if err := event.SetJsonData(eventData); err != nil {
@@ -67,18 +84,366 @@ func NewStorageRenamed(aggregate *SchoolStorageAggregate, storageID string, name
 }
 
 type StorageRelocatedEvent struct {
+	StorageID  string `json:"storageId"`
+	Location   string `json:"location"`
+	LocationID string `json:"locationId,omitempty"`
+	Reason     string `json:"reason"`
+}
+
+func NewStorageRelocated(aggregate *SchoolStorageAggregate, storageID string, location, locationID, reason string) (domain.Event, error) {
+	eventData := StorageRelocatedEvent{
+		StorageID:  storageID,
+		Location:   location,
+		LocationID: locationID,
+		Reason:     reason,
+	}
+	event := domain.NewEvent(aggregate, StorageRelocated)
+	if err := event.SetJsonData(eventData); err != nil {
+		return nil, err
+	}
+	return event, nil
+}
+
+type BooksPutEvent struct {
+	StorageID string      `json:"storageId"`
+	BookID    string      `json:"bookId"`
+	Isbn      domain.Isbn `json:"isbn"`
+	Title     string      `json:"title"`
+	Quantity  int         `json:"quantity"`
+	ReceiptID string      `json:"receiptId,omitempty"`
+}
+
+func NewBooksPut(
+	aggregate *SchoolStorageAggregate,
+	storageID, bookID string,
+	isbn domain.Isbn,
+	title string,
+	quantity int,
+	receiptID string,
+) (domain.Event, error) {
+	eventData := BooksPutEvent{
+		StorageID: storageID,
+		BookID:    bookID,
+		Isbn:      isbn,
+		Title:     title,
+		Quantity:  quantity,
+		ReceiptID: receiptID,
+	}
+	event := domain.NewEvent(aggregate, BooksPut)
+	if err := event.SetJsonData(eventData); err != nil {
+		return nil, err
+	}
+	return event, nil
+}
+
+type BooksTakenEvent struct {
 	StorageID string `json:"storageId"`
-	Location  string `json:"location"`
+	BookID    string `json:"bookId"`
+	Quantity  int    `json:"quantity"`
 	Reason    string `json:"reason"`
 }
 
-func NewStorageRelocated(aggregate *SchoolStorageAggregate, storageID string, location, reason string) (domain.Event, error) {
-	eventData := StorageRelocatedEvent{
+func NewBooksTaken(aggregate *SchoolStorageAggregate, storageID, bookID string, quantity int, reason string) (domain.Event, error) {
+	eventData := BooksTakenEvent{
 		StorageID: storageID,
-		Location:  location,
+		BookID:    bookID,
+		Quantity:  quantity,
 		Reason:    reason,
 	}
-	event := domain.NewEvent(aggregate, StorageRelocated)
+	event := domain.NewEvent(aggregate, BooksTaken)
+	if err := event.SetJsonData(eventData); err != nil {
+		return nil, err
+	}
+	return event, nil
+}
+
+type BooksTransferredEvent struct {
+	FromStorageID string      `json:"fromStorageId"`
+	ToStorageID   string      `json:"toStorageId"`
+	BookID        string      `json:"bookId"`
+	Isbn          domain.Isbn `json:"isbn"`
+	Title         string      `json:"title"`
+	Quantity      int         `json:"quantity"`
+	Reason        string      `json:"reason"`
+}
+
+func NewBooksTransferred(aggregate *SchoolStorageAggregate, fromStorageID, toStorageID string, stock BookStock, quantity int, reason string) (domain.Event, error) {
+	eventData := BooksTransferredEvent{
+		FromStorageID: fromStorageID,
+		ToStorageID:   toStorageID,
+		BookID:        stock.BookID,
+		Isbn:          stock.Isbn,
+		Title:         stock.Title,
+		Quantity:      quantity,
+		Reason:        reason,
+	}
+	event := domain.NewEvent(aggregate, BooksTransferred)
+	if err := event.SetJsonData(eventData); err != nil {
+		return nil, err
+	}
+	return event, nil
+}
+
+type BooksLentToClassEvent struct {
+	StorageID string `json:"storageId"`
+	ClassID   string `json:"classId"`
+	BookID    string `json:"bookId"`
+	Quantity  int    `json:"quantity"`
+}
+
+func NewBooksLentToClass(aggregate *SchoolStorageAggregate, storageID, classID, bookID string, quantity int) (domain.Event, error) {
+	eventData := BooksLentToClassEvent{
+		StorageID: storageID,
+		ClassID:   classID,
+		BookID:    bookID,
+		Quantity:  quantity,
+	}
+	event := domain.NewEvent(aggregate, BooksLentToClass)
+	if err := event.SetJsonData(eventData); err != nil {
+		return nil, err
+	}
+	return event, nil
+}
+
+type BooksReturnedFromClassEvent struct {
+	StorageID string      `json:"storageId"`
+	ClassID   string      `json:"classId"`
+	BookID    string      `json:"bookId"`
+	Isbn      domain.Isbn `json:"isbn"`
+	Title     string      `json:"title"`
+	Quantity  int         `json:"quantity"`
+}
+
+func NewBooksReturnedFromClass(aggregate *SchoolStorageAggregate, storageID, classID string, stock BookStock) (domain.Event, error) {
+	eventData := BooksReturnedFromClassEvent{
+		StorageID: storageID,
+		ClassID:   classID,
+		BookID:    stock.BookID,
+		Isbn:      stock.Isbn,
+		Title:     stock.Title,
+		Quantity:  stock.Quantity,
+	}
+	event := domain.NewEvent(aggregate, BooksReturnedFromClass)
+	if err := event.SetJsonData(eventData); err != nil {
+		return nil, err
+	}
+	return event, nil
+}
+
+type StocktakingOpenedEvent struct {
+	SchoolID      string   `json:"schoolId"`
+	StocktakingID string   `json:"stocktakingId"`
+	StorageIDs    []string `json:"storageIds"`
+}
+
+func NewStocktakingOpened(aggregate *SchoolStorageAggregate, stocktakingID string, storageIDs []string) (domain.Event, error) {
+	eventData := StocktakingOpenedEvent{
+		SchoolID:      aggregate.AggregateID(),
+		StocktakingID: stocktakingID,
+		StorageIDs:    storageIDs,
+	}
+	event := domain.NewEvent(aggregate, StocktakingOpened)
+	if err := event.SetJsonData(eventData); err != nil {
+		return nil, err
+	}
+	return event, nil
+}
+
+type StockCountedEvent struct {
+	StocktakingID string      `json:"stocktakingId"`
+	StorageID     string      `json:"storageId"`
+	BookID        string      `json:"bookId"`
+	Isbn          domain.Isbn `json:"isbn"`
+	Title         string      `json:"title"`
+	Quantity      int         `json:"quantity"`
+}
+
+func NewStockCounted(aggregate *SchoolStorageAggregate, stocktakingID string, count StockCount) (domain.Event, error) {
+	eventData := StockCountedEvent{
+		StocktakingID: stocktakingID,
+		StorageID:     count.StorageID,
+		BookID:        count.BookID,
+		Isbn:          count.Isbn,
+		Title:         count.Title,
+		Quantity:      count.Quantity,
+	}
+	event := domain.NewEvent(aggregate, StockCounted)
+	if err := event.SetJsonData(eventData); err != nil {
+		return nil, err
+	}
+	return event, nil
+}
+
+type StockCorrectedEvent struct {
+	StocktakingID string      `json:"stocktakingId"`
+	StorageID     string      `json:"storageId"`
+	BookID        string      `json:"bookId"`
+	Isbn          domain.Isbn `json:"isbn"`
+	Title         string      `json:"title"`
+	Difference    int         `json:"difference"`
+	Reason        string      `json:"reason"`
+}
+
+func NewStockCorrected(aggregate *SchoolStorageAggregate, stocktakingID string, discrepancy Discrepancy, reason string) (domain.Event, error) {
+	eventData := StockCorrectedEvent{
+		StocktakingID: stocktakingID,
+		StorageID:     discrepancy.StorageID,
+		BookID:        discrepancy.BookID,
+		Isbn:          discrepancy.Isbn,
+		Title:         discrepancy.Title,
+		Difference:    discrepancy.Difference,
+		Reason:        reason,
+	}
+	event := domain.NewEvent(aggregate, StockCorrected)
+	if err := event.SetJsonData(eventData); err != nil {
+		return nil, err
+	}
+	return event, nil
+}
+
+type StocktakingClosedEvent struct {
+	StocktakingID string `json:"stocktakingId"`
+}
+
+func NewStocktakingClosed(aggregate *SchoolStorageAggregate, stocktakingID string) (domain.Event, error) {
+	eventData := StocktakingClosedEvent{
+		StocktakingID: stocktakingID,
+	}
+	event := domain.NewEvent(aggregate, StocktakingClosed)
+	if err := event.SetJsonData(eventData); err != nil {
+		return nil, err
+	}
+	return event, nil
+}
+
+type BooksDamagedEvent struct {
+	StorageID string    `json:"storageId"`
+	BookID    string    `json:"bookId"`
+	From      Condition `json:"from"`
+	Condition Condition `json:"condition"`
+	Quantity  int       `json:"quantity"`
+	Reason    string    `json:"reason"`
+}
+
+func NewBooksDamaged(aggregate *SchoolStorageAggregate, storageID, bookID string, from, condition Condition, quantity int, reason string) (domain.Event, error) {
+	eventData := BooksDamagedEvent{
+		StorageID: storageID,
+		BookID:    bookID,
+		From:      from,
+		Condition: condition,
+		Quantity:  quantity,
+		Reason:    reason,
+	}
+	event := domain.NewEvent(aggregate, BooksDamaged)
+	if err := event.SetJsonData(eventData); err != nil {
+		return nil, err
+	}
+	return event, nil
+}
+
+type BooksWrittenOffEvent struct {
+	SchoolID  string      `json:"schoolId"`
+	StorageID string      `json:"storageId"`
+	BookID    string      `json:"bookId"`
+	Isbn      domain.Isbn `json:"isbn"`
+	Title     string      `json:"title"`
+	Condition Condition   `json:"condition"`
+	Quantity  int         `json:"quantity"`
+	Reason    string      `json:"reason"`
+}
+
+func NewBooksWrittenOff(aggregate *SchoolStorageAggregate, storageID string, book BookStock, condition Condition, reason string) (domain.Event, error) {
+	eventData := BooksWrittenOffEvent{
+		SchoolID:  aggregate.AggregateID(),
+		StorageID: storageID,
+		BookID:    book.BookID,
+		Isbn:      book.Isbn,
+		Title:     book.Title,
+		Condition: condition,
+		Quantity:  book.Quantity,
+		Reason:    reason,
+	}
+	event := domain.NewEvent(aggregate, BooksWrittenOff)
+	if err := event.SetJsonData(eventData); err != nil {
+		return nil, err
+	}
+	return event, nil
+}
+
+type LocationAddedEvent struct {
+	SchoolID   string       `json:"schoolId"`
+	LocationID string       `json:"locationId"`
+	Kind       LocationKind `json:"kind"`
+	Name       string       `json:"name"`
+	ParentID   string       `json:"parentId"`
+}
+
+func NewLocationAdded(aggregate *SchoolStorageAggregate, locationID string, kind LocationKind, name, parentID string) (domain.Event, error) {
+	eventData := LocationAddedEvent{
+		SchoolID:   aggregate.AggregateID(),
+		LocationID: locationID,
+		Kind:       kind,
+		Name:       name,
+		ParentID:   parentID,
+	}
+	event := domain.NewEvent(aggregate, LocationAdded)
+	if err := event.SetJsonData(eventData); err != nil {
+		return nil, err
+	}
+	return event, nil
+}
+
+type LocationRenamedEvent struct {
+	LocationID string `json:"locationId"`
+	Name       string `json:"name"`
+	Reason     string `json:"reason"`
+}
+
+func NewLocationRenamed(aggregate *SchoolStorageAggregate, locationID, name, reason string) (domain.Event, error) {
+	eventData := LocationRenamedEvent{
+		LocationID: locationID,
+		Name:       name,
+		Reason:     reason,
+	}
+	event := domain.NewEvent(aggregate, LocationRenamed)
+	if err := event.SetJsonData(eventData); err != nil {
+		return nil, err
+	}
+	return event, nil
+}
+
+type LocationRemovedEvent struct {
+	LocationID string `json:"locationId"`
+	Reason     string `json:"reason"`
+}
+
+func NewLocationRemoved(aggregate *SchoolStorageAggregate, locationID, reason string) (domain.Event, error) {
+	eventData := LocationRemovedEvent{
+		LocationID: locationID,
+		Reason:     reason,
+	}
+	event := domain.NewEvent(aggregate, LocationRemoved)
+	if err := event.SetJsonData(eventData); err != nil {
+		return nil, err
+	}
+	return event, nil
+}
+
+// StorageCapacityChangedEvent carries a capacity of zero for storages without
+// a limit.
+type StorageCapacityChangedEvent struct {
+	StorageID string `json:"storageId"`
+	Capacity  int    `json:"capacity"`
+	Reason    string `json:"reason"`
+}
+
+func NewStorageCapacityChanged(aggregate *SchoolStorageAggregate, storageID string, capacity int, reason string) (domain.Event, error) {
+	eventData := StorageCapacityChangedEvent{
+		StorageID: storageID,
+		Capacity:  capacity,
+		Reason:    reason,
+	}
+	event := domain.NewEvent(aggregate, StorageCapacityChanged)
 	if err := event.SetJsonData(eventData); err != nil {
 		return nil, err
 	}
@@ -59,6 +59,7 @@ func TestEventCreation(t *testing.T) {
 					test.storageID,
 					test.storageName,
 					test.storageLocation,
+					"",
 				)
 			case storagedomain.StorageRemoved:
 				event, err = storagedomain.NewStorageRemoved(
@@ -78,6 +79,7 @@ func TestEventCreation(t *testing.T) {
 					aggregate,
 					test.storageID,
 					test.storageLocation,
+					"",
 					test.reason,
 				)
 			default:
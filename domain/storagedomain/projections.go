@@ -1,20 +1,81 @@
 package storagedomain
 
+import (
+	"time"
+
+	"github.com/kammeph/school-book-storage-service/domain"
+)
+
 type BookInStorage struct {
-	BookID   string `json:"bookId" bson:"bookId"`
-	Isbn     string `json:"isbn" bson:"isbn"`
-	Title    string `json:"title" bson:"title"`
-	Quantity int    `json:"quantity" bson:"quantity"`
+	BookID     string           `json:"bookId" bson:"bookId"`
+	Isbn       domain.Isbn      `json:"isbn" bson:"isbn"`
+	Title      string           `json:"title" bson:"title"`
+	Quantity   int              `json:"quantity" bson:"quantity"`
+	Conditions []ConditionCount `json:"conditions,omitempty" bson:"conditions,omitempty"`
 }
 
 type StorageWithBooks struct {
-	SchoolID  string          `json:"schoolId" bson:"schoolId"`
-	StorageID string          `json:"storageId" bson:"storageId"`
-	Name      string          `json:"name" bson:"name"`
-	Location  string          `json:"location" bson:"location"`
-	Books     []BookInStorage `json:"books" bson:"books"`
+	SchoolID   string          `json:"schoolId" bson:"schoolId"`
+	StorageID  string          `json:"storageId" bson:"storageId"`
+	Name       string          `json:"name" bson:"name"`
+	Location   string          `json:"location" bson:"location"`
+	LocationID string          `json:"locationId,omitempty" bson:"locationId,omitempty"`
+	Capacity   int             `json:"capacity,omitempty" bson:"capacity,omitempty"`
+	Books      []BookInStorage `json:"books" bson:"books"`
+	Version    int             `json:"version" bson:"version"`
+}
+
+func NewStorageWithBooks(schoolID, storageID, name, location string, version int) StorageWithBooks {
+	return StorageWithBooks{
+		SchoolID:  schoolID,
+		StorageID: storageID,
+		Name:      name,
+		Location:  location,
+		Books:     []BookInStorage{},
+		Version:   version,
+	}
+}
+
+type LocationProjection struct {
+	SchoolID   string       `json:"schoolId" bson:"schoolId"`
+	LocationID string       `json:"locationId" bson:"locationId"`
+	Kind       LocationKind `json:"kind" bson:"kind"`
+	Name       string       `json:"name" bson:"name"`
+	ParentID   string       `json:"parentId,omitempty" bson:"parentId,omitempty"`
+	Version    int          `json:"version" bson:"version"`
+}
+
+func NewLocationProjection(schoolID, locationID string, kind LocationKind, name, parentID string, version int) LocationProjection {
+	return LocationProjection{schoolID, locationID, kind, name, parentID, version}
+}
+
+func (p LocationProjection) Location() Location {
+	return Location{ID: p.LocationID, Kind: p.Kind, Name: p.Name, ParentID: p.ParentID}
+}
+
+type StocktakingProjection struct {
+	SchoolID      string       `json:"schoolId" bson:"schoolId"`
+	StocktakingID string       `json:"stocktakingId" bson:"stocktakingId"`
+	StorageIDs    []string     `json:"storageIds" bson:"storageIds"`
+	Counts        []StockCount `json:"counts" bson:"counts"`
+	Closed        bool         `json:"closed" bson:"closed"`
+	OpenedAt      time.Time    `json:"openedAt" bson:"openedAt"`
+	Version       int          `json:"version" bson:"version"`
+}
+
+func NewStocktakingProjection(schoolID, stocktakingID string, storageIDs []string, openedAt time.Time, version int) StocktakingProjection {
+	return StocktakingProjection{schoolID, stocktakingID, storageIDs, []StockCount{}, false, openedAt, version}
 }
 
-func NewStorageWithBooks(schoolID, storageID, name, location string) StorageWithBooks {
-	return StorageWithBooks{schoolID, storageID, name, location, []BookInStorage{}}
+type WriteOffProjection struct {
+	SchoolID     string      `json:"schoolId" bson:"schoolId"`
+	StorageID    string      `json:"storageId" bson:"storageId"`
+	BookID       string      `json:"bookId" bson:"bookId"`
+	Isbn         domain.Isbn `json:"isbn" bson:"isbn"`
+	Title        string      `json:"title" bson:"title"`
+	Condition    Condition   `json:"condition" bson:"condition"`
+	Quantity     int         `json:"quantity" bson:"quantity"`
+	Reason       string      `json:"reason" bson:"reason"`
+	WrittenOffAt time.Time   `json:"writtenOffAt" bson:"writtenOffAt"`
+	Version      int         `json:"version" bson:"version"`
 }
@@ -0,0 +1,10 @@
+package storagedomain
+
+import "github.com/kammeph/school-book-storage-service/domain"
+
+// ISBNs used to be stored as entered by the user.
+func init() {
+	for _, eventType := range []string{BooksPut, BooksTransferred, BooksReturnedFromClass, StockCounted, StockCorrected, BooksWrittenOff} {
+		domain.RegisterUpcaster(eventType, domain.IsbnUpcaster("isbn"))
+	}
+}
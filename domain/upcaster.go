@@ -0,0 +1,85 @@
+package domain
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// Upcaster migrates the data of a stored event to the current shape of its
+// event type. It must leave data that is already current untouched.
+type Upcaster func(data map[string]json.RawMessage) error
+
+var upcasters = map[string][]Upcaster{}
+
+// RegisterUpcaster adds an upcaster for the event type. Upcasters of a type
+// run in the order they were registered whenever the data of an event is read.
+func RegisterUpcaster(eventType string, upcaster Upcaster) {
+	upcasters[eventType] = append(upcasters[eventType], upcaster)
+}
+
+func upcast(eventType string, data []byte) ([]byte, error) {
+	eventUpcasters, ok := upcasters[eventType]
+	if !ok {
+		return data, nil
+	}
+	fields := map[string]json.RawMessage{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	for _, upcaster := range eventUpcasters {
+		if err := upcaster(fields); err != nil {
+			return nil, err
+		}
+	}
+	return json.Marshal(fields)
+}
+
+// MoneyUpcaster converts the given fields from plain numbers in major units to
+// Money in the default currency.
+func MoneyUpcaster(fields ...string) Upcaster {
+	return func(data map[string]json.RawMessage) error {
+		for _, field := range fields {
+			value, ok := data[field]
+			if !ok || bytes.HasPrefix(bytes.TrimSpace(value), []byte("{")) {
+				continue
+			}
+			var amount float64
+			if err := json.Unmarshal(value, &amount); err != nil {
+				return err
+			}
+			money, err := json.Marshal(MoneyFromFloat(amount, DefaultCurrency))
+			if err != nil {
+				return err
+			}
+			data[field] = money
+		}
+		return nil
+	}
+}
+
+// IsbnUpcaster normalizes the ISBNs in the given fields. Values that are no
+// valid ISBN are kept as they are.
+func IsbnUpcaster(fields ...string) Upcaster {
+	return func(data map[string]json.RawMessage) error {
+		for _, field := range fields {
+			value, ok := data[field]
+			if !ok {
+				continue
+			}
+			var raw string
+			if err := json.Unmarshal(value, &raw); err != nil {
+				return err
+			}
+			isbn, err := ParseIsbn(raw)
+			if err != nil {
+				continue
+			}
+			normalized, err := json.Marshal(isbn)
+			if err != nil {
+				return err
+			}
+			data[field] = normalized
+		}
+		return nil
+	}
+}
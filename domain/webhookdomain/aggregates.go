@@ -0,0 +1,92 @@
+package webhookdomain
+
+import (
+	"github.com/kammeph/school-book-storage-service/domain"
+	"github.com/kammeph/school-book-storage-service/fp"
+)
+
+type SchoolWebhookAggregate struct {
+	*domain.AggregateModel
+	Webhooks []Webhook
+}
+
+func NewSchoolWebhookAggregate() *SchoolWebhookAggregate {
+	aggregate := &SchoolWebhookAggregate{
+		Webhooks: []Webhook{},
+	}
+	model := domain.NewAggregateModel(aggregate.On)
+	aggregate.AggregateModel = &model
+	return aggregate
+}
+
+func NewSchoolWebhookAggregateWithID(id string) *SchoolWebhookAggregate {
+	aggregate := NewSchoolWebhookAggregate()
+	aggregate.ID = id
+	return aggregate
+}
+
+func (a *SchoolWebhookAggregate) On(event domain.Event) error {
+	switch event.EventType() {
+	case WebhookRegistered:
+		return a.onWebhookRegistered(event)
+	case WebhookRemoved:
+		return a.onWebhookRemoved(event)
+	case WebhookDisabled:
+		return a.onWebhookDisabled(event)
+	case WebhookEnabled:
+		return a.onWebhookEnabled(event)
+	default:
+		return domain.ErrUnknownEvent(event)
+	}
+}
+
+func (a *SchoolWebhookAggregate) onWebhookRegistered(event domain.Event) error {
+	eventData := WebhookRegisteredEvent{}
+	if err := event.GetJsonData(&eventData); err != nil {
+		return err
+	}
+	if fp.Some(a.Webhooks, func(w Webhook) bool { return w.ID == eventData.WebhookID }) {
+		return ErrApplyEventWebhookAlreadyExists(event.EventType(), eventData.WebhookID)
+	}
+	webhook := NewWebhook(eventData.WebhookID, eventData.URL, eventData.EventTypes, event.EventAt())
+	a.Version = event.EventVersion()
+	a.Webhooks = append(a.Webhooks, webhook)
+	return nil
+}
+
+func (a *SchoolWebhookAggregate) onWebhookRemoved(event domain.Event) error {
+	eventData := WebhookRemovedEvent{}
+	if err := event.GetJsonData(&eventData); err != nil {
+		return err
+	}
+	a.Webhooks = fp.Remove(a.Webhooks, func(w Webhook) bool { return w.ID == eventData.WebhookID })
+	a.Version = event.EventVersion()
+	return nil
+}
+
+func (a *SchoolWebhookAggregate) onWebhookDisabled(event domain.Event) error {
+	eventData := WebhookDisabledEvent{}
+	if err := event.GetJsonData(&eventData); err != nil {
+		return err
+	}
+	return a.setActive(event, eventData.WebhookID, false)
+}
+
+func (a *SchoolWebhookAggregate) onWebhookEnabled(event domain.Event) error {
+	eventData := WebhookEnabledEvent{}
+	if err := event.GetJsonData(&eventData); err != nil {
+		return err
+	}
+	return a.setActive(event, eventData.WebhookID, true)
+}
+
+func (a *SchoolWebhookAggregate) setActive(event domain.Event, webhookID string, active bool) error {
+	webhook := fp.Find(a.Webhooks, func(w Webhook) bool { return w.ID == webhookID })
+	if webhook == nil {
+		return ErrApplyEventWebhookNotFound(event.EventType(), webhookID)
+	}
+	a.Version = event.EventVersion()
+	webhook.Active = active
+	webhook.UpdatedAt = event.EventAt()
+	return nil
+}
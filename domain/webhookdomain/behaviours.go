@@ -0,0 +1,97 @@
+package webhookdomain
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net/url"
+
+	"github.com/google/uuid"
+	"github.com/kammeph/school-book-storage-service/domain"
+	"github.com/kammeph/school-book-storage-service/fp"
+)
+
+// RegisterWebhook registers the webhook and returns its ID together with a new
+// secret. The secret is not recorded in the events, the caller keeps it.
+func (a *SchoolWebhookAggregate) RegisterWebhook(webhookURL string, eventTypes []string) (string, string, error) {
+	if webhookURL == "" {
+		return "", "", ErrWebhookURLNotSet
+	}
+	parsedURL, err := url.ParseRequestURI(webhookURL)
+	if err != nil || (parsedURL.Scheme != "http" && parsedURL.Scheme != "https") || parsedURL.Host == "" {
+		return "", "", ErrWebhookURLInvalid
+	}
+	if len(eventTypes) == 0 {
+		return "", "", ErrEventTypesNotSet
+	}
+	if fp.Some(a.Webhooks, func(w Webhook) bool { return w.URL == webhookURL }) {
+		return "", "", ErrWebhookAlreadyExists(webhookURL)
+	}
+	secret, err := newSecret()
+	if err != nil {
+		return "", "", err
+	}
+	webhookID := uuid.NewString()
+	event, err := NewWebhookRegistered(a, webhookID, webhookURL, eventTypes)
+	if err != nil {
+		return "", "", err
+	}
+	if err := a.Apply(event); err != nil {
+		return "", "", err
+	}
+	return webhookID, secret, nil
+}
+
+func (a *SchoolWebhookAggregate) RemoveWebhook(webhookID, reason string) error {
+	if !fp.Some(a.Webhooks, func(w Webhook) bool { return w.ID == webhookID }) {
+		return ErrWebhookWithIDNotFound(webhookID)
+	}
+	if reason == "" {
+		return domain.ErrReasonNotSpecified
+	}
+	event, err := NewWebhookRemoved(a, webhookID, reason)
+	if err != nil {
+		return err
+	}
+	return a.Apply(event)
+}
+
+func (a *SchoolWebhookAggregate) DisableWebhook(webhookID, reason string) error {
+	webhook := fp.Find(a.Webhooks, func(w Webhook) bool { return w.ID == webhookID })
+	if webhook == nil {
+		return ErrWebhookWithIDNotFound(webhookID)
+	}
+	if !webhook.Active {
+		return ErrWebhookNotActive
+	}
+	if reason == "" {
+		return domain.ErrReasonNotSpecified
+	}
+	event, err := NewWebhookDisabled(a, webhookID, reason)
+	if err != nil {
+		return err
+	}
+	return a.Apply(event)
+}
+
+func (a *SchoolWebhookAggregate) EnableWebhook(webhookID string) error {
+	webhook := fp.Find(a.Webhooks, func(w Webhook) bool { return w.ID == webhookID })
+	if webhook == nil {
+		return ErrWebhookWithIDNotFound(webhookID)
+	}
+	if webhook.Active {
+		return ErrWebhookAlreadyActive
+	}
+	event, err := NewWebhookEnabled(a, webhookID)
+	if err != nil {
+		return err
+	}
+	return a.Apply(event)
+}
+
+func newSecret() (string, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(secret), nil
+}
@@ -0,0 +1,200 @@
+package webhookdomain_test
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/kammeph/school-book-storage-service/domain"
+	"github.com/kammeph/school-book-storage-service/domain/webhookdomain"
+	"github.com/stretchr/testify/assert"
+)
+
+func initWebhookAggregate(webhooks []webhookdomain.Webhook) *webhookdomain.SchoolWebhookAggregate {
+	aggregate := webhookdomain.NewSchoolWebhookAggregateWithID(uuid.NewString())
+	aggregate.Webhooks = webhooks
+	return aggregate
+}
+
+func TestRegisterWebhook(t *testing.T) {
+	tests := []struct {
+		name        string
+		webhooks    []webhookdomain.Webhook
+		url         string
+		eventTypes  []string
+		err         error
+		expectError bool
+	}{
+		{
+			name:        "register webhook",
+			webhooks:    []webhookdomain.Webhook{},
+			url:         "https://example.com/hook",
+			eventTypes:  []string{"STORAGE_ADDED"},
+			err:         nil,
+			expectError: false,
+		},
+		{
+			name:        "register webhook without URL",
+			webhooks:    []webhookdomain.Webhook{},
+			url:         "",
+			eventTypes:  []string{"STORAGE_ADDED"},
+			err:         webhookdomain.ErrWebhookURLNotSet,
+			expectError: true,
+		},
+		{
+			name:        "register webhook with invalid URL",
+			webhooks:    []webhookdomain.Webhook{},
+			url:         "ftp://example.com/hook",
+			eventTypes:  []string{"STORAGE_ADDED"},
+			err:         webhookdomain.ErrWebhookURLInvalid,
+			expectError: true,
+		},
+		{
+			name:        "register webhook without event types",
+			webhooks:    []webhookdomain.Webhook{},
+			url:         "https://example.com/hook",
+			eventTypes:  []string{},
+			err:         webhookdomain.ErrEventTypesNotSet,
+			expectError: true,
+		},
+		{
+			name:        "webhook already exists",
+			webhooks:    []webhookdomain.Webhook{{ID: uuid.NewString(), URL: "https://example.com/hook", Active: true}},
+			url:         "https://example.com/hook",
+			eventTypes:  []string{"STORAGE_ADDED"},
+			err:         webhookdomain.ErrWebhookAlreadyExists("https://example.com/hook"),
+			expectError: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			aggregate := initWebhookAggregate(test.webhooks)
+			webhookID, secret, err := aggregate.RegisterWebhook(test.url, test.eventTypes)
+			if test.expectError {
+				assert.Error(t, err)
+				assert.Equal(t, test.err, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.NotEqual(t, "", webhookID)
+			assert.Len(t, secret, 64)
+			assert.Len(t, aggregate.DomainEvents(), 1)
+			assert.Equal(t, webhookdomain.WebhookRegistered, aggregate.DomainEvents()[0].EventType())
+			assert.NotContains(t, aggregate.DomainEvents()[0].EventData(), secret)
+			assert.True(t, aggregate.Webhooks[0].Active)
+		})
+	}
+}
+
+func TestRemoveWebhook(t *testing.T) {
+	webhookID := uuid.NewString()
+	tests := []struct {
+		name        string
+		webhooks    []webhookdomain.Webhook
+		reason      string
+		err         error
+		expectError bool
+	}{
+		{
+			name:        "remove webhook",
+			webhooks:    []webhookdomain.Webhook{{ID: webhookID, Active: true}},
+			reason:      "test",
+			err:         nil,
+			expectError: false,
+		},
+		{
+			name:        "remove not existing webhook",
+			webhooks:    []webhookdomain.Webhook{},
+			reason:      "test",
+			err:         webhookdomain.ErrWebhookWithIDNotFound(webhookID),
+			expectError: true,
+		},
+		{
+			name:        "remove webhook without reason",
+			webhooks:    []webhookdomain.Webhook{{ID: webhookID, Active: true}},
+			reason:      "",
+			err:         domain.ErrReasonNotSpecified,
+			expectError: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			aggregate := initWebhookAggregate(test.webhooks)
+			err := aggregate.RemoveWebhook(webhookID, test.reason)
+			if test.expectError {
+				assert.Error(t, err)
+				assert.Equal(t, test.err, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Len(t, aggregate.Webhooks, 0)
+			assert.Equal(t, webhookdomain.WebhookRemoved, aggregate.DomainEvents()[0].EventType())
+		})
+	}
+}
+
+func TestDisableAndEnableWebhook(t *testing.T) {
+	webhookID := uuid.NewString()
+	tests := []struct {
+		name        string
+		active      bool
+		operation   string
+		err         error
+		expectError bool
+	}{
+		{
+			name:        "disable webhook",
+			active:      true,
+			operation:   "disable",
+			err:         nil,
+			expectError: false,
+		},
+		{
+			name:        "disable disabled webhook",
+			active:      false,
+			operation:   "disable",
+			err:         webhookdomain.ErrWebhookNotActive,
+			expectError: true,
+		},
+		{
+			name:        "enable webhook",
+			active:      false,
+			operation:   "enable",
+			err:         nil,
+			expectError: false,
+		},
+		{
+			name:        "enable active webhook",
+			active:      true,
+			operation:   "enable",
+			err:         webhookdomain.ErrWebhookAlreadyActive,
+			expectError: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			aggregate := initWebhookAggregate([]webhookdomain.Webhook{{ID: webhookID, Active: test.active}})
+			var err error
+			switch test.operation {
+			case "disable":
+				err = aggregate.DisableWebhook(webhookID, "too many failed deliveries")
+			case "enable":
+				err = aggregate.EnableWebhook(webhookID)
+			}
+			if test.expectError {
+				assert.Error(t, err)
+				assert.Equal(t, test.err, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, !test.active, aggregate.Webhooks[0].Active)
+		})
+	}
+}
+
+func TestWebhookSubscribes(t *testing.T) {
+	webhook := webhookdomain.NewWebhookProjection("school", "webhook", "https://example.com", []string{"STORAGE_ADDED"}, 1)
+	assert.True(t, webhook.Subscribes("STORAGE_ADDED"))
+	assert.False(t, webhook.Subscribes("STORAGE_REMOVED"))
+	webhook.EventTypes = []string{webhookdomain.AllEvents}
+	assert.True(t, webhook.Subscribes("STORAGE_REMOVED"))
+}
@@ -0,0 +1,24 @@
+package webhookdomain
+
+import "time"
+
+const AllEvents = "*"
+
+type Webhook struct {
+	ID         string
+	URL        string
+	EventTypes []string
+	Active     bool
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+func NewWebhook(id, url string, eventTypes []string, timestamp time.Time) Webhook {
+	return Webhook{
+		ID:         id,
+		URL:        url,
+		EventTypes: eventTypes,
+		Active:     true,
+		CreatedAt:  timestamp,
+	}
+}
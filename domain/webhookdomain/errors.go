@@ -0,0 +1,34 @@
+package webhookdomain
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrWebhookURLNotSet     = errors.New("webhook URL not set")
+	ErrWebhookURLInvalid    = errors.New("webhook URL must be an absolute http or https URL")
+	ErrEventTypesNotSet     = errors.New("at least one event type must be specified")
+	ErrWebhookAlreadyActive = errors.New("webhook is already active")
+	ErrWebhookNotActive     = errors.New("webhook is already disabled")
+)
+
+func ErrApplyEventWebhookAlreadyExists(eventType, id string) error {
+	return fmt.Errorf("can not apply %s: Webhook with ID %s already exists", eventType, id)
+}
+
+func ErrApplyEventWebhookNotFound(eventType, id string) error {
+	return fmt.Errorf("can not apply %s: Webhook with ID %s not found", eventType, id)
+}
+
+func ErrWebhookWithIDNotFound(id string) error {
+	return fmt.Errorf("webhook with ID %s not found", id)
+}
+
+func ErrWebhookAlreadyExists(url string) error {
+	return fmt.Errorf("there is already a webhook for the URL %s", url)
+}
+
+func ErrWebhookSecretNotFound(id string) error {
+	return fmt.Errorf("secret of webhook with ID %s not found", id)
+}
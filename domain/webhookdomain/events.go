@@ -0,0 +1,85 @@
+package webhookdomain
+
+import "github.com/kammeph/school-book-storage-service/domain"
+
+var (
+	WebhookRegistered = "WEBHOOK_REGISTERED"
+	WebhookRemoved    = "WEBHOOK_REMOVED"
+	WebhookDisabled   = "WEBHOOK_DISABLED"
+	WebhookEnabled    = "WEBHOOK_ENABLED"
+)
+
+// WebhookRegisteredEvent does not carry the secret of the webhook, it is kept
+// in the secret store under the ID of the webhook.
+type WebhookRegisteredEvent struct {
+	SchoolID   string   `json:"schoolId"`
+	WebhookID  string   `json:"webhookId"`
+	URL        string   `json:"url"`
+	EventTypes []string `json:"eventTypes"`
+	// Deprecated: only events of webhooks registered before the secret store
+	// carry the secret.
+	Secret string `json:"secret,omitempty"`
+}
+
+func NewWebhookRegistered(aggregate *SchoolWebhookAggregate, webhookID, url string, eventTypes []string) (domain.Event, error) {
+	eventData := WebhookRegisteredEvent{
+		SchoolID:   aggregate.AggregateID(),
+		WebhookID:  webhookID,
+		URL:        url,
+		EventTypes: eventTypes,
+	}
+	event := domain.NewEvent(aggregate, WebhookRegistered)
+	if err := event.SetJsonData(eventData); err != nil {
+		return nil, err
+	}
+	return event, nil
+}
+
+type WebhookRemovedEvent struct {
+	WebhookID string `json:"webhookId"`
+	Reason    string `json:"reason"`
+}
+
+func NewWebhookRemoved(aggregate *SchoolWebhookAggregate, webhookID, reason string) (domain.Event, error) {
+	eventData := WebhookRemovedEvent{
+		WebhookID: webhookID,
+		Reason:    reason,
+	}
+	event := domain.NewEvent(aggregate, WebhookRemoved)
+	if err := event.SetJsonData(eventData); err != nil {
+		return nil, err
+	}
+	return event, nil
+}
+
+type WebhookDisabledEvent struct {
+	WebhookID string `json:"webhookId"`
+	Reason    string `json:"reason"`
+}
+
+func NewWebhookDisabled(aggregate *SchoolWebhookAggregate, webhookID, reason string) (domain.Event, error) {
+	eventData := WebhookDisabledEvent{
+		WebhookID: webhookID,
+		Reason:    reason,
+	}
+	event := domain.NewEvent(aggregate, WebhookDisabled)
+	if err := event.SetJsonData(eventData); err != nil {
+		return nil, err
+	}
+	return event, nil
+}
+
+type WebhookEnabledEvent struct {
+	WebhookID string `json:"webhookId"`
+}
+
+func NewWebhookEnabled(aggregate *SchoolWebhookAggregate, webhookID string) (domain.Event, error) {
+	eventData := WebhookEnabledEvent{
+		WebhookID: webhookID,
+	}
+	event := domain.NewEvent(aggregate, WebhookEnabled)
+	if err := event.SetJsonData(eventData); err != nil {
+		return nil, err
+	}
+	return event, nil
+}
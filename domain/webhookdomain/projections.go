@@ -0,0 +1,42 @@
+package webhookdomain
+
+import "time"
+
+type WebhookProjection struct {
+	SchoolID   string   `json:"schoolId" bson:"schoolId"`
+	WebhookID  string   `json:"webhookId" bson:"webhookId"`
+	URL        string   `json:"url" bson:"url"`
+	EventTypes []string `json:"eventTypes" bson:"eventTypes"`
+	Active     bool     `json:"active" bson:"active"`
+	Failures   int      `json:"failures" bson:"failures"`
+	Version    int      `json:"version" bson:"version"`
+}
+
+func NewWebhookProjection(schoolID, webhookID, url string, eventTypes []string, version int) WebhookProjection {
+	return WebhookProjection{schoolID, webhookID, url, eventTypes, true, 0, version}
+}
+
+// Subscribes reports whether the webhook wants to receive events of the given
+// type.
+func (w WebhookProjection) Subscribes(eventType string) bool {
+	for _, t := range w.EventTypes {
+		if t == eventType || t == AllEvents {
+			return true
+		}
+	}
+	return false
+}
+
+type Delivery struct {
+	DeliveryID  string    `json:"deliveryId" bson:"deliveryId"`
+	SchoolID    string    `json:"schoolId" bson:"schoolId"`
+	WebhookID   string    `json:"webhookId" bson:"webhookId"`
+	EventType   string    `json:"eventType" bson:"eventType"`
+	AggregateID string    `json:"aggregateId" bson:"aggregateId"`
+	Version     int       `json:"version" bson:"version"`
+	Attempt     int       `json:"attempt" bson:"attempt"`
+	StatusCode  int       `json:"statusCode" bson:"statusCode"`
+	Error       string    `json:"error,omitempty" bson:"error,omitempty"`
+	Success     bool      `json:"success" bson:"success"`
+	DeliveredAt time.Time `json:"deliveredAt" bson:"deliveredAt"`
+}
@@ -0,0 +1,84 @@
+package httpclient
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/kammeph/school-book-storage-service/application/bookapp"
+	"github.com/kammeph/school-book-storage-service/domain"
+)
+
+const OpenLibraryURL = "https://openlibrary.org"
+
+type openLibraryBook struct {
+	Details struct {
+		Title    string `json:"title"`
+		Subtitle string `json:"subtitle"`
+		Authors  []struct {
+			Name string `json:"name"`
+		} `json:"authors"`
+		Publishers  []string `json:"publishers"`
+		EditionName string   `json:"edition_name"`
+		Covers      []int    `json:"covers"`
+	} `json:"details"`
+}
+
+// OpenLibraryMetadataProvider looks up books with the books API of Open
+// Library.
+type OpenLibraryMetadataProvider struct {
+	baseURL string
+	client  *http.Client
+}
+
+func NewOpenLibraryMetadataProvider(baseURL string, timeout time.Duration) bookapp.MetadataProvider {
+	return &OpenLibraryMetadataProvider{strings.TrimSuffix(baseURL, "/"), &http.Client{Timeout: timeout}}
+}
+
+func (p *OpenLibraryMetadataProvider) Lookup(ctx context.Context, isbn domain.Isbn) (bookapp.BookMetadata, error) {
+	key := "ISBN:" + isbn.String()
+	query := url.Values{"bibkeys": {key}, "format": {"json"}, "jscmd": {"details"}}
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, p.baseURL+"/api/books?"+query.Encode(), nil)
+	if err != nil {
+		return bookapp.BookMetadata{}, err
+	}
+	response, err := p.client.Do(request)
+	if err != nil {
+		return bookapp.BookMetadata{}, err
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return bookapp.BookMetadata{}, fmt.Errorf("metadata lookup for isbn %s failed with status %d", isbn, response.StatusCode)
+	}
+	books := map[string]openLibraryBook{}
+	if err := json.NewDecoder(response.Body).Decode(&books); err != nil {
+		return bookapp.BookMetadata{}, err
+	}
+	book, ok := books[key]
+	if !ok {
+		return bookapp.BookMetadata{}, bookapp.ErrMetadataWithIsbnNotFound(isbn)
+	}
+	metadata := bookapp.BookMetadata{
+		Isbn:    isbn,
+		Title:   book.Details.Title,
+		Authors: []string{},
+		Edition: book.Details.EditionName,
+	}
+	if book.Details.Subtitle != "" {
+		metadata.Title += ": " + book.Details.Subtitle
+	}
+	for _, author := range book.Details.Authors {
+		metadata.Authors = append(metadata.Authors, author.Name)
+	}
+	if len(book.Details.Publishers) > 0 {
+		metadata.Publisher = book.Details.Publishers[0]
+	}
+	if len(book.Details.Covers) > 0 {
+		metadata.CoverURL = fmt.Sprintf("https://covers.openlibrary.org/b/id/%d-M.jpg", book.Details.Covers[0])
+	}
+	return metadata, nil
+}
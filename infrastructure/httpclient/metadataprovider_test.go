@@ -0,0 +1,54 @@
+package httpclient_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/kammeph/school-book-storage-service/application/bookapp"
+	"github.com/kammeph/school-book-storage-service/infrastructure/httpclient"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOpenLibraryLookup(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/books" || r.URL.Query().Get("jscmd") != "details" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		switch r.URL.Query().Get("bibkeys") {
+		case "ISBN:9783127323207":
+			w.Write([]byte(`{"ISBN:9783127323207":{"details":{
+				"title":"Green Line 1","subtitle":"Schulbuch",
+				"authors":[{"key":"/authors/OL1A","name":"Harald Weisshaar"}],
+				"publishers":["Klett"],"edition_name":"1. Auflage","covers":[42]}}}`))
+		case "ISBN:9783161484100":
+			w.WriteHeader(http.StatusServiceUnavailable)
+		default:
+			w.Write([]byte(`{}`))
+		}
+	}))
+	defer server.Close()
+	provider := httpclient.NewOpenLibraryMetadataProvider(server.URL+"/", time.Second)
+
+	metadata, err := provider.Lookup(context.Background(), "9783127323207")
+	assert.NoError(t, err)
+	assert.Equal(t, bookapp.BookMetadata{
+		Isbn:      "9783127323207",
+		Title:     "Green Line 1: Schulbuch",
+		Authors:   []string{"Harald Weisshaar"},
+		Publisher: "Klett",
+		Edition:   "1. Auflage",
+		CoverURL:  "https://covers.openlibrary.org/b/id/42-M.jpg",
+	}, metadata)
+
+	_, err = provider.Lookup(context.Background(), "9783123456787")
+	assert.True(t, errors.Is(err, bookapp.ErrMetadataNotFound))
+
+	_, err = provider.Lookup(context.Background(), "9783161484100")
+	assert.Error(t, err)
+	assert.False(t, errors.Is(err, bookapp.ErrMetadataNotFound))
+}
@@ -0,0 +1,40 @@
+package httpclient_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/kammeph/school-book-storage-service/infrastructure/httpclient"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSend(t *testing.T) {
+	payload := []byte(`{"eventType":"STORAGE_ADDED"}`)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if !httpclient.Verify("secret", body, r.Header.Get(httpclient.SignatureHeader)) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+	sender := httpclient.NewHttpWebhookSender(time.Second)
+
+	statusCode, err := sender.Send(context.Background(), server.URL, "secret", payload)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusAccepted, statusCode)
+
+	statusCode, err = sender.Send(context.Background(), server.URL, "other", payload)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusUnauthorized, statusCode)
+}
+
+func TestSign(t *testing.T) {
+	signature := httpclient.Sign("key", []byte("The quick brown fox jumps over the lazy dog"))
+	assert.Equal(t, "sha256=f7bc83f430538424b13298e6aa6fb143ef4d59a14946175997479dbc2d1a3cd8", signature)
+}
@@ -0,0 +1,96 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kammeph/school-book-storage-service/domain"
+	"github.com/kammeph/school-book-storage-service/domain/bookdomain"
+)
+
+type MemoryBookRepository struct {
+	books []bookdomain.BookProjection
+}
+
+func NewMemoryBookRepository() *MemoryBookRepository {
+	return &MemoryBookRepository{books: []bookdomain.BookProjection{}}
+}
+
+func (r *MemoryBookRepository) GetBooksBySchoolID(ctx context.Context, schoolID string) ([]bookdomain.BookProjection, error) {
+	books := []bookdomain.BookProjection{}
+	for _, book := range r.books {
+		if book.SchoolID == schoolID {
+			books = append(books, book)
+		}
+	}
+	return books, nil
+}
+
+func (r *MemoryBookRepository) GetBooksByGrade(ctx context.Context, schoolID string, grade int) ([]bookdomain.BookProjection, error) {
+	books := []bookdomain.BookProjection{}
+	for _, book := range r.books {
+		if book.SchoolID != schoolID {
+			continue
+		}
+		for _, g := range book.Grades {
+			if g == grade {
+				books = append(books, book)
+				break
+			}
+		}
+	}
+	return books, nil
+}
+
+func (r *MemoryBookRepository) GetBookByID(ctx context.Context, schoolID, bookID string) (bookdomain.BookProjection, error) {
+	for _, book := range r.books {
+		if book.SchoolID == schoolID && book.BookID == bookID {
+			return book, nil
+		}
+	}
+	return bookdomain.BookProjection{}, fmt.Errorf("no book with ID %s found", bookID)
+}
+
+func (r *MemoryBookRepository) UpsertBook(ctx context.Context, book bookdomain.BookProjection) error {
+	for idx, b := range r.books {
+		if b.BookID == book.BookID {
+			if b.Version < book.Version {
+				r.books[idx] = book
+			}
+			return nil
+		}
+	}
+	r.books = append(r.books, book)
+	return nil
+}
+
+func (r *MemoryBookRepository) UpdateBookMeta(ctx context.Context, bookID, name, description string, grades []int, version int) error {
+	for idx, book := range r.books {
+		if book.BookID == bookID && book.Version < version {
+			r.books[idx].Name = name
+			r.books[idx].Description = description
+			r.books[idx].Grades = grades
+			r.books[idx].Version = version
+			return nil
+		}
+	}
+	return nil
+}
+
+func (r *MemoryBookRepository) UpdateBookPrice(
+	ctx context.Context,
+	bookID string,
+	price domain.Money,
+	history []bookdomain.PriceChange,
+	version int,
+) error {
+	for idx, book := range r.books {
+		if book.BookID == bookID && book.Version < version {
+			r.books[idx].Price = price
+			r.books[idx].PriceHistory = history
+			r.books[idx].Version = version
+			return nil
+		}
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kammeph/school-book-storage-service/domain/chargedomain"
+)
+
+type MemoryChargeRepository struct {
+	charges []chargedomain.ChargeProjection
+}
+
+func NewMemoryChargeRepository() *MemoryChargeRepository {
+	return &MemoryChargeRepository{charges: []chargedomain.ChargeProjection{}}
+}
+
+func (r *MemoryChargeRepository) GetChargesBySchoolID(ctx context.Context, schoolID string) ([]chargedomain.ChargeProjection, error) {
+	return r.filter(func(c chargedomain.ChargeProjection) bool { return c.SchoolID == schoolID }), nil
+}
+
+func (r *MemoryChargeRepository) GetOpenCharges(ctx context.Context, schoolID string) ([]chargedomain.ChargeProjection, error) {
+	return r.filter(func(c chargedomain.ChargeProjection) bool {
+		return c.SchoolID == schoolID && c.Status == chargedomain.Open
+	}), nil
+}
+
+func (r *MemoryChargeRepository) GetChargeByID(ctx context.Context, schoolID, chargeID string) (chargedomain.ChargeProjection, error) {
+	for _, charge := range r.charges {
+		if charge.SchoolID == schoolID && charge.ChargeID == chargeID {
+			return charge, nil
+		}
+	}
+	return chargedomain.ChargeProjection{}, fmt.Errorf("no charge with ID %s found", chargeID)
+}
+
+func (r *MemoryChargeRepository) filter(predicate func(chargedomain.ChargeProjection) bool) []chargedomain.ChargeProjection {
+	charges := []chargedomain.ChargeProjection{}
+	for _, charge := range r.charges {
+		if predicate(charge) {
+			charges = append(charges, charge)
+		}
+	}
+	return charges
+}
+
+func (r *MemoryChargeRepository) UpsertCharge(ctx context.Context, charge chargedomain.ChargeProjection) error {
+	for idx, c := range r.charges {
+		if c.ChargeID == charge.ChargeID {
+			if c.Version < charge.Version {
+				r.charges[idx] = charge
+			}
+			return nil
+		}
+	}
+	r.charges = append(r.charges, charge)
+	return nil
+}
+
+func (r *MemoryChargeRepository) UpdateChargeStatus(ctx context.Context, chargeID string, status chargedomain.ChargeStatus, version int) error {
+	for idx, charge := range r.charges {
+		if charge.ChargeID == chargeID && charge.Version < version {
+			r.charges[idx].Status = status
+			r.charges[idx].Version = version
+			return nil
+		}
+	}
+	return nil
+}
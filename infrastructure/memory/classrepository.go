@@ -0,0 +1,108 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kammeph/school-book-storage-service/domain/classdomain"
+)
+
+type MemoryClassRepository struct {
+	classes []classdomain.ClassWithBooks
+}
+
+func NewMemoryClassRepository() *MemoryClassRepository {
+	return &MemoryClassRepository{classes: []classdomain.ClassWithBooks{}}
+}
+
+func NewMemoryClassRepositoryWithClasses(classes []classdomain.ClassWithBooks) *MemoryClassRepository {
+	return &MemoryClassRepository{classes: classes}
+}
+
+func (r *MemoryClassRepository) GetClassesBySchoolID(ctx context.Context, schoolID string) ([]classdomain.ClassWithBooks, error) {
+	classes := []classdomain.ClassWithBooks{}
+	for _, class := range r.classes {
+		if class.SchoolID == schoolID {
+			classes = append(classes, class)
+		}
+	}
+	return classes, nil
+}
+
+func (r *MemoryClassRepository) GetClassesBySchoolYear(ctx context.Context, schoolID string, year int) ([]classdomain.ClassWithBooks, error) {
+	classes := []classdomain.ClassWithBooks{}
+	for _, class := range r.classes {
+		if class.SchoolID == schoolID && class.DateFrom.Year() == year {
+			classes = append(classes, class)
+		}
+	}
+	return classes, nil
+}
+
+func (r *MemoryClassRepository) GetClassByID(ctx context.Context, schoolID, classID string) (classdomain.ClassWithBooks, error) {
+	for _, class := range r.classes {
+		if class.SchoolID == schoolID && class.ClassID == classID {
+			return class, nil
+		}
+	}
+	return classdomain.ClassWithBooks{}, fmt.Errorf("no class with ID %s found", classID)
+}
+
+func (r *MemoryClassRepository) UpsertClass(ctx context.Context, class classdomain.ClassWithBooks) error {
+	for idx, c := range r.classes {
+		if c.ClassID == class.ClassID {
+			if c.Version < class.Version {
+				r.classes[idx] = class
+			}
+			return nil
+		}
+	}
+	r.classes = append(r.classes, class)
+	return nil
+}
+
+func (r *MemoryClassRepository) UpdateClassNumberOfPupils(ctx context.Context, classID string, numberOfPupils, version int) error {
+	for idx, class := range r.classes {
+		if class.ClassID == classID && class.Version < version {
+			r.classes[idx].NumberOfPupils = numberOfPupils
+			r.classes[idx].Version = version
+			return nil
+		}
+	}
+	return nil
+}
+
+func (r *MemoryClassRepository) UpdateClassBooks(ctx context.Context, classID string, books []classdomain.BookInClass, version int) error {
+	for idx, class := range r.classes {
+		if class.ClassID == classID && class.Version < version {
+			r.classes[idx].Books = books
+			r.classes[idx].Version = version
+			return nil
+		}
+	}
+	return nil
+}
+
+func (r *MemoryClassRepository) UpdateClassPupils(ctx context.Context, classID string, pupils []string, version int) error {
+	for idx, class := range r.classes {
+		if class.ClassID == classID && class.Version < version {
+			r.classes[idx].Pupils = pupils
+			r.classes[idx].NumberOfPupils = len(pupils)
+			r.classes[idx].Version = version
+			return nil
+		}
+	}
+	return nil
+}
+
+func (r *MemoryClassRepository) UpdateClassClosed(ctx context.Context, classID, successorID string, version int) error {
+	for idx, class := range r.classes {
+		if class.ClassID == classID && class.Version < version {
+			r.classes[idx].Closed = true
+			r.classes[idx].SuccessorID = successorID
+			r.classes[idx].Version = version
+			return nil
+		}
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kammeph/school-book-storage-service/domain/copydomain"
+)
+
+type MemoryCopyRepository struct {
+	copies []copydomain.CopyProjection
+}
+
+func NewMemoryCopyRepository() *MemoryCopyRepository {
+	return &MemoryCopyRepository{copies: []copydomain.CopyProjection{}}
+}
+
+func (r *MemoryCopyRepository) GetCopiesBySchoolID(ctx context.Context, schoolID string) ([]copydomain.CopyProjection, error) {
+	return r.filter(func(c copydomain.CopyProjection) bool { return c.SchoolID == schoolID }), nil
+}
+
+func (r *MemoryCopyRepository) GetCopiesByLocation(ctx context.Context, schoolID string, location copydomain.Location) ([]copydomain.CopyProjection, error) {
+	return r.filter(func(c copydomain.CopyProjection) bool {
+		return c.SchoolID == schoolID && c.Location == location && !c.WrittenOff
+	}), nil
+}
+
+func (r *MemoryCopyRepository) GetCopiesByBookID(ctx context.Context, schoolID, bookID string) ([]copydomain.CopyProjection, error) {
+	return r.filter(func(c copydomain.CopyProjection) bool { return c.SchoolID == schoolID && c.BookID == bookID }), nil
+}
+
+func (r *MemoryCopyRepository) GetCopyByInventoryNumber(ctx context.Context, schoolID, inventoryNumber string) (copydomain.CopyProjection, error) {
+	for _, bookCopy := range r.copies {
+		if bookCopy.SchoolID == schoolID && bookCopy.InventoryNumber == inventoryNumber {
+			return bookCopy, nil
+		}
+	}
+	return copydomain.CopyProjection{}, fmt.Errorf("no copy with inventory number %s found", inventoryNumber)
+}
+
+func (r *MemoryCopyRepository) filter(predicate func(copydomain.CopyProjection) bool) []copydomain.CopyProjection {
+	copies := []copydomain.CopyProjection{}
+	for _, bookCopy := range r.copies {
+		if predicate(bookCopy) {
+			copies = append(copies, bookCopy)
+		}
+	}
+	return copies
+}
+
+func (r *MemoryCopyRepository) UpsertCopy(ctx context.Context, bookCopy copydomain.CopyProjection) error {
+	for idx, c := range r.copies {
+		if c.SchoolID == bookCopy.SchoolID && c.InventoryNumber == bookCopy.InventoryNumber {
+			if c.Version < bookCopy.Version {
+				r.copies[idx] = bookCopy
+			}
+			return nil
+		}
+	}
+	r.copies = append(r.copies, bookCopy)
+	return nil
+}
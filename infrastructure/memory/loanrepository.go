@@ -0,0 +1,86 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/kammeph/school-book-storage-service/domain/loandomain"
+)
+
+type MemoryLoanRepository struct {
+	loans []loandomain.LoanProjection
+}
+
+func NewMemoryLoanRepository() *MemoryLoanRepository {
+	return &MemoryLoanRepository{loans: []loandomain.LoanProjection{}}
+}
+
+func (r *MemoryLoanRepository) GetLoansBySchoolID(ctx context.Context, schoolID string) ([]loandomain.LoanProjection, error) {
+	return r.filter(func(l loandomain.LoanProjection) bool { return l.SchoolID == schoolID }), nil
+}
+
+func (r *MemoryLoanRepository) GetLoanByID(ctx context.Context, schoolID, loanID string) (loandomain.LoanProjection, error) {
+	for _, loan := range r.loans {
+		if loan.SchoolID == schoolID && loan.LoanID == loanID {
+			return loan, nil
+		}
+	}
+	return loandomain.LoanProjection{}, fmt.Errorf("no loan with ID %s found", loanID)
+}
+
+func (r *MemoryLoanRepository) GetLoansByPupilID(ctx context.Context, schoolID, pupilID string) ([]loandomain.LoanProjection, error) {
+	return r.filter(func(l loandomain.LoanProjection) bool { return l.SchoolID == schoolID && l.PupilID == pupilID }), nil
+}
+
+func (r *MemoryLoanRepository) GetOverdueLoans(ctx context.Context, schoolID, classID string, at time.Time) ([]loandomain.LoanProjection, error) {
+	return r.filter(func(l loandomain.LoanProjection) bool {
+		return l.SchoolID == schoolID && (classID == "" || l.ClassID == classID) && l.IsOverdue(at)
+	}), nil
+}
+
+func (r *MemoryLoanRepository) filter(predicate func(loandomain.LoanProjection) bool) []loandomain.LoanProjection {
+	loans := []loandomain.LoanProjection{}
+	for _, loan := range r.loans {
+		if predicate(loan) {
+			loans = append(loans, loan)
+		}
+	}
+	return loans
+}
+
+func (r *MemoryLoanRepository) UpsertLoan(ctx context.Context, loan loandomain.LoanProjection) error {
+	for idx, l := range r.loans {
+		if l.LoanID == loan.LoanID {
+			if l.Version < loan.Version {
+				r.loans[idx] = loan
+			}
+			return nil
+		}
+	}
+	r.loans = append(r.loans, loan)
+	return nil
+}
+
+func (r *MemoryLoanRepository) UpdateLoanDueDate(ctx context.Context, loanID string, dueDate time.Time, extensions, version int) error {
+	for idx, loan := range r.loans {
+		if loan.LoanID == loanID && loan.Version < version {
+			r.loans[idx].DueDate = dueDate
+			r.loans[idx].Extensions = extensions
+			r.loans[idx].Version = version
+			return nil
+		}
+	}
+	return nil
+}
+
+func (r *MemoryLoanRepository) UpdateLoanReturned(ctx context.Context, loanID string, returnedAt time.Time, version int) error {
+	for idx, loan := range r.loans {
+		if loan.LoanID == loanID && loan.Version < version {
+			r.loans[idx].ReturnedAt = &returnedAt
+			r.loans[idx].Version = version
+			return nil
+		}
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/kammeph/school-book-storage-service/domain/storagedomain"
+)
+
+type MemoryLocationRepository struct {
+	locations []storagedomain.LocationProjection
+}
+
+func NewMemoryLocationRepository() *MemoryLocationRepository {
+	return &MemoryLocationRepository{locations: []storagedomain.LocationProjection{}}
+}
+
+func (r *MemoryLocationRepository) GetLocationsBySchoolID(ctx context.Context, schoolID string) ([]storagedomain.LocationProjection, error) {
+	locations := []storagedomain.LocationProjection{}
+	for _, location := range r.locations {
+		if location.SchoolID == schoolID {
+			locations = append(locations, location)
+		}
+	}
+	return locations, nil
+}
+
+func (r *MemoryLocationRepository) UpsertLocation(ctx context.Context, location storagedomain.LocationProjection) error {
+	for idx, l := range r.locations {
+		if l.LocationID == location.LocationID {
+			if l.Version < location.Version {
+				r.locations[idx] = location
+			}
+			return nil
+		}
+	}
+	r.locations = append(r.locations, location)
+	return nil
+}
+
+func (r *MemoryLocationRepository) UpdateLocationName(ctx context.Context, locationID, name string, version int) error {
+	for idx, location := range r.locations {
+		if location.LocationID == locationID && location.Version < version {
+			r.locations[idx].Name = name
+			r.locations[idx].Version = version
+			return nil
+		}
+	}
+	return nil
+}
+
+func (r *MemoryLocationRepository) DeleteLocation(ctx context.Context, locationID string, version int) error {
+	for idx, location := range r.locations {
+		if location.LocationID == locationID && location.Version < version {
+			r.locations = append(r.locations[:idx], r.locations[idx+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
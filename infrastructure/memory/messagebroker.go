@@ -3,13 +3,28 @@ package memory
 import (
 	"context"
 	"encoding/json"
+	"log"
 
 	"github.com/kammeph/school-book-storage-service/application"
 	"github.com/kammeph/school-book-storage-service/domain"
 )
 
+type subscription struct {
+	exchange string
+	handler  application.EventHandler
+	policy   application.FailurePolicy
+	stopped  bool
+}
+
+type DeadLetter struct {
+	Exchange  string
+	EventData []byte
+	Error     string
+}
+
 type MemoryMessageBroker struct {
-	eventHandlers []application.EventHandler
+	subscriptions []*subscription
+	deadLetters   []DeadLetter
 }
 
 func NewMemoryMessageBroker() *MemoryMessageBroker {
@@ -22,14 +37,35 @@ func (m *MemoryMessageBroker) Publish(ctx context.Context, events []domain.Event
 		if err != nil {
 			return err
 		}
-		for idx := range m.eventHandlers {
-			m.eventHandlers[idx].Handle(ctx, []byte(eventBytes))
+		for _, s := range m.subscriptions {
+			if s.stopped {
+				continue
+			}
+			m.handle(ctx, s, eventBytes)
 		}
 	}
 	return nil
 }
 
-func (m *MemoryMessageBroker) Subscribe(exchange string, handler application.EventHandler) error {
-	m.eventHandlers = append(m.eventHandlers, handler)
+func (m *MemoryMessageBroker) handle(ctx context.Context, s *subscription, eventBytes []byte) {
+	err := s.policy.Handle(ctx, s.handler, eventBytes)
+	if err == nil {
+		return
+	}
+	log.Printf("error while handling event from exchange %s: %s", s.exchange, err)
+	switch s.policy.Mode {
+	case application.StopOnFailure:
+		s.stopped = true
+	case application.DeadLetterOnFailure:
+		m.deadLetters = append(m.deadLetters, DeadLetter{s.exchange, eventBytes, err.Error()})
+	}
+}
+
+func (m *MemoryMessageBroker) Subscribe(exchange string, handler application.EventHandler, policy application.FailurePolicy) error {
+	m.subscriptions = append(m.subscriptions, &subscription{exchange: exchange, handler: handler, policy: policy})
 	return nil
 }
+
+func (m *MemoryMessageBroker) DeadLetters() []DeadLetter {
+	return m.deadLetters
+}
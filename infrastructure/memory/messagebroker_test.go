@@ -0,0 +1,86 @@
+package memory_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/kammeph/school-book-storage-service/application"
+	"github.com/kammeph/school-book-storage-service/domain"
+	"github.com/kammeph/school-book-storage-service/infrastructure/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+type failingHandler struct {
+	failures int
+	calls    int
+}
+
+func (h *failingHandler) Handle(ctx context.Context, eventData []byte) error {
+	h.calls++
+	if h.calls <= h.failures {
+		return errors.New("handler error")
+	}
+	return nil
+}
+
+func TestPublishWithFailurePolicy(t *testing.T) {
+	tests := []struct {
+		name          string
+		policy        application.FailurePolicy
+		failures      int
+		events        int
+		expectedCalls int
+		deadLetters   int
+	}{
+		{
+			name:          "retry until success",
+			policy:        application.NewRetryPolicy(3, time.Millisecond),
+			failures:      2,
+			events:        1,
+			expectedCalls: 3,
+			deadLetters:   0,
+		},
+		{
+			name:          "skip failing event",
+			policy:        application.NewSkipPolicy(),
+			failures:      1,
+			events:        2,
+			expectedCalls: 2,
+			deadLetters:   0,
+		},
+		{
+			name:          "stop after failing event",
+			policy:        application.NewStopPolicy(),
+			failures:      1,
+			events:        3,
+			expectedCalls: 1,
+			deadLetters:   0,
+		},
+		{
+			name:          "dead letter after retries",
+			policy:        application.NewDeadLetterPolicy(1, time.Millisecond),
+			failures:      2,
+			events:        2,
+			expectedCalls: 3,
+			deadLetters:   1,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			broker := memory.NewMemoryMessageBroker()
+			handler := &failingHandler{failures: test.failures}
+			err := broker.Subscribe("test", handler, test.policy)
+			assert.NoError(t, err)
+			events := []domain.Event{}
+			for version := 1; version <= test.events; version++ {
+				events = append(events, &domain.EventModel{ID: "aggregate", Version: version, Type: "TEST"})
+			}
+			err = broker.Publish(context.Background(), events)
+			assert.NoError(t, err)
+			assert.Equal(t, test.expectedCalls, handler.calls)
+			assert.Len(t, broker.DeadLetters(), test.deadLetters)
+		})
+	}
+}
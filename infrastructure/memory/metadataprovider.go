@@ -0,0 +1,52 @@
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/kammeph/school-book-storage-service/application/bookapp"
+	"github.com/kammeph/school-book-storage-service/domain"
+)
+
+// MemoryMetadataProvider serves book metadata from a fixed set of entries. It is
+// used offline and in tests instead of a remote provider.
+type MemoryMetadataProvider struct {
+	entries map[domain.Isbn]bookapp.BookMetadata
+}
+
+func NewMemoryMetadataProvider(entries ...bookapp.BookMetadata) (*MemoryMetadataProvider, error) {
+	provider := &MemoryMetadataProvider{entries: map[domain.Isbn]bookapp.BookMetadata{}}
+	for _, entry := range entries {
+		isbn, err := domain.ParseIsbn(entry.Isbn.String())
+		if err != nil {
+			return nil, fmt.Errorf("metadata for %s: %w", entry.Isbn, err)
+		}
+		entry.Isbn = isbn
+		provider.entries[isbn] = entry
+	}
+	return provider, nil
+}
+
+// NewFileMetadataProvider reads the entries from a JSON file holding an array
+// of book metadata.
+func NewFileMetadataProvider(path string) (*MemoryMetadataProvider, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	entries := []bookapp.BookMetadata{}
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, err
+	}
+	return NewMemoryMetadataProvider(entries...)
+}
+
+func (p *MemoryMetadataProvider) Lookup(ctx context.Context, isbn domain.Isbn) (bookapp.BookMetadata, error) {
+	metadata, ok := p.entries[isbn]
+	if !ok {
+		return bookapp.BookMetadata{}, bookapp.ErrMetadataWithIsbnNotFound(isbn)
+	}
+	return metadata, nil
+}
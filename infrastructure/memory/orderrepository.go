@@ -0,0 +1,85 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kammeph/school-book-storage-service/domain/orderdomain"
+)
+
+type MemoryOrderRepository struct {
+	orders []orderdomain.PurchaseOrderProjection
+}
+
+func NewMemoryOrderRepository() *MemoryOrderRepository {
+	return &MemoryOrderRepository{orders: []orderdomain.PurchaseOrderProjection{}}
+}
+
+func (r *MemoryOrderRepository) GetOrdersBySchoolID(ctx context.Context, schoolID string) ([]orderdomain.PurchaseOrderProjection, error) {
+	return r.filter(func(o orderdomain.PurchaseOrderProjection) bool { return o.SchoolID == schoolID }), nil
+}
+
+func (r *MemoryOrderRepository) GetOpenOrders(ctx context.Context, schoolID string) ([]orderdomain.PurchaseOrderProjection, error) {
+	return r.filter(func(o orderdomain.PurchaseOrderProjection) bool { return o.SchoolID == schoolID && o.IsOpen() }), nil
+}
+
+func (r *MemoryOrderRepository) GetOrderByID(ctx context.Context, schoolID, orderID string) (orderdomain.PurchaseOrderProjection, error) {
+	for _, order := range r.orders {
+		if order.SchoolID == schoolID && order.OrderID == orderID {
+			return order, nil
+		}
+	}
+	return orderdomain.PurchaseOrderProjection{}, fmt.Errorf("no purchase order with ID %s found", orderID)
+}
+
+func (r *MemoryOrderRepository) filter(predicate func(orderdomain.PurchaseOrderProjection) bool) []orderdomain.PurchaseOrderProjection {
+	orders := []orderdomain.PurchaseOrderProjection{}
+	for _, order := range r.orders {
+		if predicate(order) {
+			orders = append(orders, order)
+		}
+	}
+	return orders
+}
+
+func (r *MemoryOrderRepository) UpsertOrder(ctx context.Context, order orderdomain.PurchaseOrderProjection) error {
+	for idx, o := range r.orders {
+		if o.OrderID == order.OrderID {
+			if o.Version < order.Version {
+				r.orders[idx] = order
+			}
+			return nil
+		}
+	}
+	r.orders = append(r.orders, order)
+	return nil
+}
+
+func (r *MemoryOrderRepository) UpdateOrderStatus(ctx context.Context, orderID string, status orderdomain.OrderStatus, version int) error {
+	for idx, order := range r.orders {
+		if order.OrderID == orderID && order.Version < version {
+			r.orders[idx].Status = status
+			r.orders[idx].Version = version
+			return nil
+		}
+	}
+	return nil
+}
+
+func (r *MemoryOrderRepository) UpdateOrderLines(
+	ctx context.Context,
+	orderID string,
+	lines []orderdomain.OrderLine,
+	status orderdomain.OrderStatus,
+	version int,
+) error {
+	for idx, order := range r.orders {
+		if order.OrderID == orderID && order.Version < version {
+			r.orders[idx].Lines = lines
+			r.orders[idx].Status = status
+			r.orders[idx].Version = version
+			return nil
+		}
+	}
+	return nil
+}
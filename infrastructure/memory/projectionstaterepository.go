@@ -0,0 +1,50 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/kammeph/school-book-storage-service/application"
+)
+
+type MemoryProjectionStateRepository struct {
+	states []application.ProjectionState
+}
+
+func NewMemoryProjectionStateRepository() *MemoryProjectionStateRepository {
+	return &MemoryProjectionStateRepository{states: []application.ProjectionState{}}
+}
+
+func (r *MemoryProjectionStateRepository) GetProjectionState(ctx context.Context, projection, aggregateID string) (application.ProjectionState, error) {
+	for _, state := range r.states {
+		if state.Projection == projection && state.AggregateID == aggregateID {
+			return copyProjectionState(state), nil
+		}
+	}
+	return application.NewProjectionState(projection, aggregateID), nil
+}
+
+func (r *MemoryProjectionStateRepository) GetProjectionStatesWithGaps(ctx context.Context, projection string) ([]application.ProjectionState, error) {
+	states := []application.ProjectionState{}
+	for _, state := range r.states {
+		if state.Projection == projection && state.HasGaps() {
+			states = append(states, copyProjectionState(state))
+		}
+	}
+	return states, nil
+}
+
+func (r *MemoryProjectionStateRepository) SaveProjectionState(ctx context.Context, state application.ProjectionState) error {
+	for idx, s := range r.states {
+		if s.Projection == state.Projection && s.AggregateID == state.AggregateID {
+			r.states[idx] = copyProjectionState(state)
+			return nil
+		}
+	}
+	r.states = append(r.states, copyProjectionState(state))
+	return nil
+}
+
+func copyProjectionState(state application.ProjectionState) application.ProjectionState {
+	state.MissingVersions = append([]int{}, state.MissingVersions...)
+	return state
+}
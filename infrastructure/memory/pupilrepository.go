@@ -0,0 +1,81 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/kammeph/school-book-storage-service/domain/pupildomain"
+)
+
+type MemoryPupilRepository struct {
+	pupils []pupildomain.PupilProjection
+}
+
+func NewMemoryPupilRepository() *MemoryPupilRepository {
+	return &MemoryPupilRepository{pupils: []pupildomain.PupilProjection{}}
+}
+
+func (r *MemoryPupilRepository) GetPupilsBySchoolID(ctx context.Context, schoolID string) ([]pupildomain.PupilProjection, error) {
+	pupils := []pupildomain.PupilProjection{}
+	for _, pupil := range r.pupils {
+		if pupil.SchoolID == schoolID {
+			pupils = append(pupils, pupil)
+		}
+	}
+	return pupils, nil
+}
+
+func (r *MemoryPupilRepository) GetPupilsByClassID(ctx context.Context, schoolID, classID string) ([]pupildomain.PupilProjection, error) {
+	pupils := []pupildomain.PupilProjection{}
+	for _, pupil := range r.pupils {
+		if pupil.SchoolID == schoolID && pupil.ClassID == classID && pupil.ExitDate == nil {
+			pupils = append(pupils, pupil)
+		}
+	}
+	return pupils, nil
+}
+
+func (r *MemoryPupilRepository) GetPupilByID(ctx context.Context, schoolID, pupilID string) (pupildomain.PupilProjection, error) {
+	for _, pupil := range r.pupils {
+		if pupil.SchoolID == schoolID && pupil.PupilID == pupilID {
+			return pupil, nil
+		}
+	}
+	return pupildomain.PupilProjection{}, fmt.Errorf("no pupil with ID %s found", pupilID)
+}
+
+func (r *MemoryPupilRepository) UpsertPupil(ctx context.Context, pupil pupildomain.PupilProjection) error {
+	for idx, p := range r.pupils {
+		if p.PupilID == pupil.PupilID {
+			if p.Version < pupil.Version {
+				r.pupils[idx] = pupil
+			}
+			return nil
+		}
+	}
+	r.pupils = append(r.pupils, pupil)
+	return nil
+}
+
+func (r *MemoryPupilRepository) UpdatePupilClass(ctx context.Context, pupilID, classID string, version int) error {
+	for idx, pupil := range r.pupils {
+		if pupil.PupilID == pupilID && pupil.Version < version {
+			r.pupils[idx].ClassID = classID
+			r.pupils[idx].Version = version
+			return nil
+		}
+	}
+	return nil
+}
+
+func (r *MemoryPupilRepository) UpdatePupilExitDate(ctx context.Context, pupilID string, exitDate time.Time, version int) error {
+	for idx, pupil := range r.pupils {
+		if pupil.PupilID == pupilID && pupil.Version < version {
+			r.pupils[idx].ExitDate = &exitDate
+			r.pupils[idx].Version = version
+			return nil
+		}
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"github.com/kammeph/school-book-storage-service/domain/reservationdomain"
+)
+
+type MemoryReservationRepository struct {
+	reservations []reservationdomain.ReservationProjection
+}
+
+func NewMemoryReservationRepository() *MemoryReservationRepository {
+	return &MemoryReservationRepository{reservations: []reservationdomain.ReservationProjection{}}
+}
+
+func (r *MemoryReservationRepository) GetReservationsBySchoolID(
+	ctx context.Context,
+	schoolID string,
+) ([]reservationdomain.ReservationProjection, error) {
+	reservations := []reservationdomain.ReservationProjection{}
+	for _, reservation := range r.reservations {
+		if reservation.SchoolID == schoolID {
+			reservations = append(reservations, reservation)
+		}
+	}
+	return reservations, nil
+}
+
+func (r *MemoryReservationRepository) GetReservationByID(
+	ctx context.Context,
+	schoolID, reservationID string,
+) (reservationdomain.ReservationProjection, error) {
+	for _, reservation := range r.reservations {
+		if reservation.SchoolID == schoolID && reservation.ReservationID == reservationID {
+			return reservation, nil
+		}
+	}
+	return reservationdomain.ReservationProjection{}, reservationdomain.ErrReservationWithIDNotFound(reservationID)
+}
+
+func (r *MemoryReservationRepository) UpsertReservation(ctx context.Context, reservation reservationdomain.ReservationProjection) error {
+	for idx, res := range r.reservations {
+		if res.ReservationID == reservation.ReservationID {
+			if res.Version < reservation.Version {
+				r.reservations[idx] = reservation
+			}
+			return nil
+		}
+	}
+	r.reservations = append(r.reservations, reservation)
+	return nil
+}
+
+func (r *MemoryReservationRepository) UpdateReservationCancelled(
+	ctx context.Context,
+	reservationID string,
+	cancelledAt time.Time,
+	version int,
+) error {
+	for idx, reservation := range r.reservations {
+		if reservation.ReservationID == reservationID && reservation.Version < version {
+			r.reservations[idx].CancelledAt = &cancelledAt
+			r.reservations[idx].Version = version
+			return nil
+		}
+	}
+	return nil
+}
+
+func (r *MemoryReservationRepository) UpdateReservationFulfilled(ctx context.Context, reservationID string, fulfilled, version int) error {
+	for idx, reservation := range r.reservations {
+		if reservation.ReservationID == reservationID && reservation.Version < version {
+			r.reservations[idx].Fulfilled = fulfilled
+			r.reservations[idx].Version = version
+			return nil
+		}
+	}
+	return nil
+}
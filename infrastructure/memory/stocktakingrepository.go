@@ -0,0 +1,70 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kammeph/school-book-storage-service/domain/storagedomain"
+)
+
+type MemoryStocktakingRepository struct {
+	stocktakings []storagedomain.StocktakingProjection
+}
+
+func NewMemoryStocktakingRepository() *MemoryStocktakingRepository {
+	return &MemoryStocktakingRepository{stocktakings: []storagedomain.StocktakingProjection{}}
+}
+
+func (r *MemoryStocktakingRepository) GetStocktakingsBySchoolID(ctx context.Context, schoolID string) ([]storagedomain.StocktakingProjection, error) {
+	stocktakings := []storagedomain.StocktakingProjection{}
+	for _, stocktaking := range r.stocktakings {
+		if stocktaking.SchoolID == schoolID {
+			stocktakings = append(stocktakings, stocktaking)
+		}
+	}
+	return stocktakings, nil
+}
+
+func (r *MemoryStocktakingRepository) GetStocktakingByID(ctx context.Context, schoolID, stocktakingID string) (storagedomain.StocktakingProjection, error) {
+	for _, stocktaking := range r.stocktakings {
+		if stocktaking.SchoolID == schoolID && stocktaking.StocktakingID == stocktakingID {
+			return stocktaking, nil
+		}
+	}
+	return storagedomain.StocktakingProjection{}, fmt.Errorf("no stocktaking with ID %s found", stocktakingID)
+}
+
+func (r *MemoryStocktakingRepository) UpsertStocktaking(ctx context.Context, stocktaking storagedomain.StocktakingProjection) error {
+	for idx, s := range r.stocktakings {
+		if s.StocktakingID == stocktaking.StocktakingID {
+			if s.Version < stocktaking.Version {
+				r.stocktakings[idx] = stocktaking
+			}
+			return nil
+		}
+	}
+	r.stocktakings = append(r.stocktakings, stocktaking)
+	return nil
+}
+
+func (r *MemoryStocktakingRepository) UpdateStocktakingCounts(ctx context.Context, stocktakingID string, counts []storagedomain.StockCount, version int) error {
+	for idx, stocktaking := range r.stocktakings {
+		if stocktaking.StocktakingID == stocktakingID && stocktaking.Version < version {
+			r.stocktakings[idx].Counts = counts
+			r.stocktakings[idx].Version = version
+			return nil
+		}
+	}
+	return nil
+}
+
+func (r *MemoryStocktakingRepository) UpdateStocktakingClosed(ctx context.Context, stocktakingID string, version int) error {
+	for idx, stocktaking := range r.stocktakings {
+		if stocktaking.StocktakingID == stocktakingID && stocktaking.Version < version {
+			r.stocktakings[idx].Closed = true
+			r.stocktakings[idx].Version = version
+			return nil
+		}
+	}
+	return nil
+}
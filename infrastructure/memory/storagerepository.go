@@ -6,6 +6,7 @@ import (
 	"fmt"
 
 	"github.com/kammeph/school-book-storage-service/domain/storagedomain"
+	"github.com/kammeph/school-book-storage-service/fp"
 )
 
 type MemoryRepository struct {
@@ -71,14 +72,22 @@ func (r *MemoryRepository) GetStorageByName(ctx context.Context, schoolID, name
 	return storages[0], nil
 }
 
-func (r *MemoryRepository) InsertStorage(ctx context.Context, storage storagedomain.StorageWithBooks) error {
+func (r *MemoryRepository) UpsertStorage(ctx context.Context, storage storagedomain.StorageWithBooks) error {
+	for idx, s := range r.storages {
+		if s.StorageID == storage.StorageID {
+			if s.Version < storage.Version {
+				r.storages[idx] = storage
+			}
+			return nil
+		}
+	}
 	r.storages = append(r.storages, storage)
 	return nil
 }
 
-func (r *MemoryRepository) DeleteStorage(ctx context.Context, storageID string) error {
+func (r *MemoryRepository) DeleteStorage(ctx context.Context, storageID string, version int) error {
 	for idx, storage := range r.storages {
-		if storage.StorageID == storageID {
+		if storage.StorageID == storageID && storage.Version < version {
 			r.storages = append(r.storages[:idx], r.storages[idx+1:]...)
 			return nil
 		}
@@ -86,20 +95,58 @@ func (r *MemoryRepository) DeleteStorage(ctx context.Context, storageID string)
 	return nil
 }
 
-func (r *MemoryRepository) UpdateStorageName(ctx context.Context, storageID, name string) error {
+func (r *MemoryRepository) UpdateStorageName(ctx context.Context, storageID, name string, version int) error {
 	for idx, storage := range r.storages {
-		if storage.StorageID == storageID {
+		if storage.StorageID == storageID && storage.Version < version {
 			r.storages[idx].Name = name
+			r.storages[idx].Version = version
 			return nil
 		}
 	}
 	return nil
 }
 
-func (r *MemoryRepository) UpdateStorageLocation(ctx context.Context, storageID, location string) error {
+func (r *MemoryRepository) GetStoragesByLocationIDs(ctx context.Context, schoolID string, locationIDs []string) ([]storagedomain.StorageWithBooks, error) {
+	if r.storages == nil {
+		return nil, errors.New("repository is not initialized")
+	}
+	storages := []storagedomain.StorageWithBooks{}
+	for _, storage := range r.storages {
+		if storage.SchoolID == schoolID && storage.LocationID != "" && fp.Some(locationIDs, func(id string) bool { return id == storage.LocationID }) {
+			storages = append(storages, storage)
+		}
+	}
+	return storages, nil
+}
+
+func (r *MemoryRepository) UpdateStorageLocation(ctx context.Context, storageID, location, locationID string, version int) error {
 	for idx, storage := range r.storages {
-		if storage.StorageID == storageID {
+		if storage.StorageID == storageID && storage.Version < version {
 			r.storages[idx].Location = location
+			r.storages[idx].LocationID = locationID
+			r.storages[idx].Version = version
+			return nil
+		}
+	}
+	return nil
+}
+
+func (r *MemoryRepository) UpdateStorageCapacity(ctx context.Context, storageID string, capacity, version int) error {
+	for idx, storage := range r.storages {
+		if storage.StorageID == storageID && storage.Version < version {
+			r.storages[idx].Capacity = capacity
+			r.storages[idx].Version = version
+			return nil
+		}
+	}
+	return nil
+}
+
+func (r *MemoryRepository) UpdateStorageBooks(ctx context.Context, storageID string, books []storagedomain.BookInStorage, version int) error {
+	for idx, storage := range r.storages {
+		if storage.StorageID == storageID && storage.Version < version {
+			r.storages[idx].Books = books
+			r.storages[idx].Version = version
 			return nil
 		}
 	}
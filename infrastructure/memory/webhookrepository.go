@@ -0,0 +1,89 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/kammeph/school-book-storage-service/domain/webhookdomain"
+)
+
+type MemoryWebhookRepository struct {
+	webhooks   []webhookdomain.WebhookProjection
+	deliveries []webhookdomain.Delivery
+}
+
+func NewMemoryWebhookRepository() *MemoryWebhookRepository {
+	return &MemoryWebhookRepository{
+		webhooks:   []webhookdomain.WebhookProjection{},
+		deliveries: []webhookdomain.Delivery{},
+	}
+}
+
+func (r *MemoryWebhookRepository) GetWebhooksBySchoolID(ctx context.Context, schoolID string) ([]webhookdomain.WebhookProjection, error) {
+	webhooks := []webhookdomain.WebhookProjection{}
+	for _, webhook := range r.webhooks {
+		if webhook.SchoolID == schoolID {
+			webhooks = append(webhooks, webhook)
+		}
+	}
+	return webhooks, nil
+}
+
+func (r *MemoryWebhookRepository) UpsertWebhook(ctx context.Context, webhook webhookdomain.WebhookProjection) error {
+	for idx, w := range r.webhooks {
+		if w.WebhookID == webhook.WebhookID {
+			if w.Version < webhook.Version {
+				r.webhooks[idx] = webhook
+			}
+			return nil
+		}
+	}
+	r.webhooks = append(r.webhooks, webhook)
+	return nil
+}
+
+func (r *MemoryWebhookRepository) DeleteWebhook(ctx context.Context, webhookID string, version int) error {
+	for idx, webhook := range r.webhooks {
+		if webhook.WebhookID == webhookID && webhook.Version < version {
+			r.webhooks = append(r.webhooks[:idx], r.webhooks[idx+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
+
+func (r *MemoryWebhookRepository) UpdateWebhookActive(ctx context.Context, webhookID string, active bool, version int) error {
+	for idx, webhook := range r.webhooks {
+		if webhook.WebhookID == webhookID && webhook.Version < version {
+			r.webhooks[idx].Active = active
+			r.webhooks[idx].Failures = 0
+			r.webhooks[idx].Version = version
+			return nil
+		}
+	}
+	return nil
+}
+
+func (r *MemoryWebhookRepository) UpdateWebhookFailures(ctx context.Context, webhookID string, failures int) error {
+	for idx, webhook := range r.webhooks {
+		if webhook.WebhookID == webhookID {
+			r.webhooks[idx].Failures = failures
+			return nil
+		}
+	}
+	return nil
+}
+
+func (r *MemoryWebhookRepository) GetDeliveriesByWebhookID(ctx context.Context, schoolID, webhookID string) ([]webhookdomain.Delivery, error) {
+	deliveries := []webhookdomain.Delivery{}
+	for _, delivery := range r.deliveries {
+		if delivery.SchoolID == schoolID && delivery.WebhookID == webhookID {
+			deliveries = append(deliveries, delivery)
+		}
+	}
+	return deliveries, nil
+}
+
+func (r *MemoryWebhookRepository) InsertDelivery(ctx context.Context, delivery webhookdomain.Delivery) error {
+	r.deliveries = append(r.deliveries, delivery)
+	return nil
+}
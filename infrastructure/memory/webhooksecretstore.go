@@ -0,0 +1,41 @@
+package memory
+
+import (
+	"context"
+	"sync"
+
+	"github.com/kammeph/school-book-storage-service/domain/webhookdomain"
+)
+
+type MemoryWebhookSecretStore struct {
+	mutex   sync.Mutex
+	secrets map[string]string
+}
+
+func NewMemoryWebhookSecretStore() *MemoryWebhookSecretStore {
+	return &MemoryWebhookSecretStore{secrets: map[string]string{}}
+}
+
+func (s *MemoryWebhookSecretStore) SaveSecret(ctx context.Context, webhookID, secret string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.secrets[webhookID] = secret
+	return nil
+}
+
+func (s *MemoryWebhookSecretStore) GetSecret(ctx context.Context, webhookID string) (string, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	secret, ok := s.secrets[webhookID]
+	if !ok {
+		return "", webhookdomain.ErrWebhookSecretNotFound(webhookID)
+	}
+	return secret, nil
+}
+
+func (s *MemoryWebhookSecretStore) DeleteSecret(ctx context.Context, webhookID string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	delete(s.secrets, webhookID)
+	return nil
+}
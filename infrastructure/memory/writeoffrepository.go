@@ -0,0 +1,38 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"github.com/kammeph/school-book-storage-service/domain/storagedomain"
+)
+
+type MemoryWriteOffRepository struct {
+	writeOffs []storagedomain.WriteOffProjection
+}
+
+func NewMemoryWriteOffRepository() *MemoryWriteOffRepository {
+	return &MemoryWriteOffRepository{writeOffs: []storagedomain.WriteOffProjection{}}
+}
+
+func (r *MemoryWriteOffRepository) GetWriteOffs(ctx context.Context, schoolID string, from, to time.Time) ([]storagedomain.WriteOffProjection, error) {
+	writeOffs := []storagedomain.WriteOffProjection{}
+	for _, writeOff := range r.writeOffs {
+		if writeOff.SchoolID == schoolID && !writeOff.WrittenOffAt.Before(from) && writeOff.WrittenOffAt.Before(to) {
+			writeOffs = append(writeOffs, writeOff)
+		}
+	}
+	return writeOffs, nil
+}
+
+// InsertWriteOff stores the write-off once. A write-off is identified by the
+// school and the version of the event that recorded it.
+func (r *MemoryWriteOffRepository) InsertWriteOff(ctx context.Context, writeOff storagedomain.WriteOffProjection) error {
+	for _, w := range r.writeOffs {
+		if w.SchoolID == writeOff.SchoolID && w.Version == writeOff.Version {
+			return nil
+		}
+	}
+	r.writeOffs = append(r.writeOffs, writeOff)
+	return nil
+}
@@ -0,0 +1,102 @@
+package mongodb
+
+import (
+	"context"
+
+	"github.com/kammeph/school-book-storage-service/application/bookapp"
+	"github.com/kammeph/school-book-storage-service/domain"
+	"github.com/kammeph/school-book-storage-service/domain/bookdomain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type BookRepository struct {
+	collection Collection
+}
+
+func NewBookRepository(client Client, dbName, tableName string) bookapp.BookRepository {
+	collection := client.Database(dbName).Collection(tableName)
+	return &BookRepository{collection}
+}
+
+func (r *BookRepository) GetBooksBySchoolID(ctx context.Context, schoolID string) ([]bookdomain.BookProjection, error) {
+	return r.find(ctx, bson.D{{Key: "schoolId", Value: schoolID}})
+}
+
+func (r *BookRepository) GetBooksByGrade(ctx context.Context, schoolID string, grade int) ([]bookdomain.BookProjection, error) {
+	return r.find(ctx, bson.D{
+		{Key: "schoolId", Value: schoolID},
+		{Key: "grades", Value: grade},
+	})
+}
+
+func (r *BookRepository) GetBookByID(ctx context.Context, schoolID, bookID string) (bookdomain.BookProjection, error) {
+	filter := bson.D{
+		{Key: "schoolId", Value: schoolID},
+		{Key: "bookId", Value: bookID},
+	}
+	result := r.collection.FindOne(ctx, filter)
+	if result.Err() != nil {
+		return bookdomain.BookProjection{}, result.Err()
+	}
+	book := bookdomain.BookProjection{}
+	if err := result.Decode(&book); err != nil {
+		return book, err
+	}
+	return book, nil
+}
+
+func (r *BookRepository) find(ctx context.Context, filter bson.D) ([]bookdomain.BookProjection, error) {
+	cursor, err := r.collection.Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "name", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+	books := []bookdomain.BookProjection{}
+	if err := cursor.All(ctx, &books); err != nil {
+		return nil, err
+	}
+	return books, nil
+}
+
+func (r *BookRepository) UpsertBook(ctx context.Context, book bookdomain.BookProjection) error {
+	filter := bson.D{{Key: "bookId", Value: book.BookID}}
+	update := setIfNewer(book.Version, bson.D{
+		{Key: "bookId", Value: book.BookID},
+		{Key: "schoolId", Value: book.SchoolID},
+		{Key: "isbn", Value: book.Isbn},
+		{Key: "name", Value: book.Name},
+		{Key: "description", Value: book.Description},
+		{Key: "price", Value: book.Price},
+		{Key: "priceHistory", Value: book.PriceHistory},
+		{Key: "grades", Value: book.Grades},
+	})
+	_, err := r.collection.UpdateOne(ctx, filter, update, options.Update().SetUpsert(true))
+	return err
+}
+
+func (r *BookRepository) UpdateBookMeta(ctx context.Context, bookID, name, description string, grades []int, version int) error {
+	filter := bson.D{{Key: "bookId", Value: bookID}}
+	update := setIfNewer(version, bson.D{
+		{Key: "name", Value: name},
+		{Key: "description", Value: description},
+		{Key: "grades", Value: grades},
+	})
+	_, err := r.collection.UpdateOne(ctx, filter, update)
+	return err
+}
+
+func (r *BookRepository) UpdateBookPrice(
+	ctx context.Context,
+	bookID string,
+	price domain.Money,
+	history []bookdomain.PriceChange,
+	version int,
+) error {
+	filter := bson.D{{Key: "bookId", Value: bookID}}
+	update := setIfNewer(version, bson.D{
+		{Key: "price", Value: price},
+		{Key: "priceHistory", Value: history},
+	})
+	_, err := r.collection.UpdateOne(ctx, filter, update)
+	return err
+}
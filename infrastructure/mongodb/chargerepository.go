@@ -0,0 +1,86 @@
+package mongodb
+
+import (
+	"context"
+
+	"github.com/kammeph/school-book-storage-service/application/chargeapp"
+	"github.com/kammeph/school-book-storage-service/domain/chargedomain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type ChargeRepository struct {
+	collection Collection
+}
+
+func NewChargeRepository(client Client, dbName, tableName string) chargeapp.ChargeRepository {
+	collection := client.Database(dbName).Collection(tableName)
+	return &ChargeRepository{collection}
+}
+
+func (r *ChargeRepository) GetChargesBySchoolID(ctx context.Context, schoolID string) ([]chargedomain.ChargeProjection, error) {
+	return r.find(ctx, bson.D{{Key: "schoolId", Value: schoolID}})
+}
+
+func (r *ChargeRepository) GetOpenCharges(ctx context.Context, schoolID string) ([]chargedomain.ChargeProjection, error) {
+	return r.find(ctx, bson.D{
+		{Key: "schoolId", Value: schoolID},
+		{Key: "status", Value: chargedomain.Open},
+	})
+}
+
+func (r *ChargeRepository) GetChargeByID(ctx context.Context, schoolID, chargeID string) (chargedomain.ChargeProjection, error) {
+	filter := bson.D{
+		{Key: "schoolId", Value: schoolID},
+		{Key: "chargeId", Value: chargeID},
+	}
+	result := r.collection.FindOne(ctx, filter)
+	if result.Err() != nil {
+		return chargedomain.ChargeProjection{}, result.Err()
+	}
+	charge := chargedomain.ChargeProjection{}
+	if err := result.Decode(&charge); err != nil {
+		return charge, err
+	}
+	return charge, nil
+}
+
+func (r *ChargeRepository) find(ctx context.Context, filter bson.D) ([]chargedomain.ChargeProjection, error) {
+	cursor, err := r.collection.Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "createdAt", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+	charges := []chargedomain.ChargeProjection{}
+	if err := cursor.All(ctx, &charges); err != nil {
+		return nil, err
+	}
+	return charges, nil
+}
+
+func (r *ChargeRepository) UpsertCharge(ctx context.Context, charge chargedomain.ChargeProjection) error {
+	filter := bson.D{{Key: "chargeId", Value: charge.ChargeID}}
+	update := setIfNewer(charge.Version, bson.D{
+		{Key: "chargeId", Value: charge.ChargeID},
+		{Key: "schoolId", Value: charge.SchoolID},
+		{Key: "pupilId", Value: charge.PupilID},
+		{Key: "bookId", Value: charge.BookID},
+		{Key: "isbn", Value: charge.Isbn},
+		{Key: "title", Value: charge.Title},
+		{Key: "incident", Value: charge.Incident},
+		{Key: "loanId", Value: charge.LoanID},
+		{Key: "description", Value: charge.Description},
+		{Key: "price", Value: charge.Price},
+		{Key: "amount", Value: charge.Amount},
+		{Key: "status", Value: charge.Status},
+		{Key: "createdAt", Value: charge.CreatedAt},
+	})
+	_, err := r.collection.UpdateOne(ctx, filter, update, options.Update().SetUpsert(true))
+	return err
+}
+
+func (r *ChargeRepository) UpdateChargeStatus(ctx context.Context, chargeID string, status chargedomain.ChargeStatus, version int) error {
+	filter := bson.D{{Key: "chargeId", Value: chargeID}}
+	update := setIfNewer(version, bson.D{{Key: "status", Value: status}})
+	_, err := r.collection.UpdateOne(ctx, filter, update)
+	return err
+}
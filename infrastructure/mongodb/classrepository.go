@@ -0,0 +1,120 @@
+package mongodb
+
+import (
+	"context"
+	"time"
+
+	"github.com/kammeph/school-book-storage-service/application/classapp"
+	"github.com/kammeph/school-book-storage-service/domain/classdomain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type ClassWithBooksRepository struct {
+	collection Collection
+}
+
+func NewClassWithBooksRepository(client Client, dbName, tableName string) classapp.ClassWithBooksRepository {
+	collection := client.Database(dbName).Collection(tableName)
+	return &ClassWithBooksRepository{collection}
+}
+
+func (r *ClassWithBooksRepository) GetClassesBySchoolID(ctx context.Context, schoolID string) ([]classdomain.ClassWithBooks, error) {
+	filter := bson.D{{Key: "schoolId", Value: schoolID}}
+	cursor, err := r.collection.Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	classes := []classdomain.ClassWithBooks{}
+	if err := cursor.All(ctx, &classes); err != nil {
+		return nil, err
+	}
+	return classes, nil
+}
+
+func (r *ClassWithBooksRepository) GetClassesBySchoolYear(ctx context.Context, schoolID string, year int) ([]classdomain.ClassWithBooks, error) {
+	filter := bson.D{
+		{Key: "schoolId", Value: schoolID},
+		{Key: "dateFrom", Value: bson.D{
+			{Key: "$gte", Value: time.Date(year, 1, 1, 0, 0, 0, 0, time.UTC)},
+			{Key: "$lt", Value: time.Date(year+1, 1, 1, 0, 0, 0, 0, time.UTC)},
+		}},
+	}
+	sort := bson.D{{Key: "grade", Value: 1}, {Key: "letter", Value: 1}}
+	cursor, err := r.collection.Find(ctx, filter, options.Find().SetSort(sort))
+	if err != nil {
+		return nil, err
+	}
+	classes := []classdomain.ClassWithBooks{}
+	if err := cursor.All(ctx, &classes); err != nil {
+		return nil, err
+	}
+	return classes, nil
+}
+
+func (r *ClassWithBooksRepository) GetClassByID(ctx context.Context, schoolID, classID string) (classdomain.ClassWithBooks, error) {
+	filter := bson.D{
+		{Key: "schoolId", Value: schoolID},
+		{Key: "classId", Value: classID},
+	}
+	result := r.collection.FindOne(ctx, filter)
+	if result.Err() != nil {
+		return classdomain.ClassWithBooks{}, result.Err()
+	}
+	class := classdomain.ClassWithBooks{}
+	if err := result.Decode(&class); err != nil {
+		return class, err
+	}
+	return class, nil
+}
+
+func (r *ClassWithBooksRepository) UpsertClass(ctx context.Context, class classdomain.ClassWithBooks) error {
+	filter := bson.D{{Key: "classId", Value: class.ClassID}}
+	update := setIfNewer(class.Version, bson.D{
+		{Key: "classId", Value: class.ClassID},
+		{Key: "schoolId", Value: class.SchoolID},
+		{Key: "grade", Value: class.Grade},
+		{Key: "letter", Value: class.Letter},
+		{Key: "numberOfPupils", Value: class.NumberOfPupils},
+		{Key: "dateFrom", Value: class.DateFrom},
+		{Key: "dateTo", Value: class.DateTo},
+		{Key: "books", Value: class.Books},
+		{Key: "pupils", Value: class.Pupils},
+	})
+	_, err := r.collection.UpdateOne(ctx, filter, update, options.Update().SetUpsert(true))
+	return err
+}
+
+func (r *ClassWithBooksRepository) UpdateClassNumberOfPupils(ctx context.Context, classID string, numberOfPupils, version int) error {
+	filter := bson.D{{Key: "classId", Value: classID}}
+	update := setIfNewer(version, bson.D{{Key: "numberOfPupils", Value: numberOfPupils}})
+	_, err := r.collection.UpdateOne(ctx, filter, update)
+	return err
+}
+
+func (r *ClassWithBooksRepository) UpdateClassBooks(ctx context.Context, classID string, books []classdomain.BookInClass, version int) error {
+	filter := bson.D{{Key: "classId", Value: classID}}
+	update := setIfNewer(version, bson.D{{Key: "books", Value: books}})
+	_, err := r.collection.UpdateOne(ctx, filter, update)
+	return err
+}
+
+func (r *ClassWithBooksRepository) UpdateClassPupils(ctx context.Context, classID string, pupils []string, version int) error {
+	filter := bson.D{{Key: "classId", Value: classID}}
+	update := setIfNewer(version, bson.D{
+		{Key: "pupils", Value: pupils},
+		{Key: "numberOfPupils", Value: len(pupils)},
+	})
+	_, err := r.collection.UpdateOne(ctx, filter, update)
+	return err
+}
+
+func (r *ClassWithBooksRepository) UpdateClassClosed(ctx context.Context, classID, successorID string, version int) error {
+	filter := bson.D{{Key: "classId", Value: classID}}
+	update := setIfNewer(version, bson.D{
+		{Key: "closed", Value: true},
+		{Key: "successorId", Value: successorID},
+	})
+	_, err := r.collection.UpdateOne(ctx, filter, update)
+	return err
+}
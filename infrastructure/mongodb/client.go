@@ -64,6 +64,10 @@ func (c *CollectionWrapper) UpdateOne(ctx context.Context, filter interface{}, d
 	return c.Collection.UpdateOne(ctx, filter, document, opts...)
 }
 
+func (c *CollectionWrapper) UpdateMany(ctx context.Context, filter interface{}, document interface{}, opts ...*options.UpdateOptions) (UpdateResult, error) {
+	return c.Collection.UpdateMany(ctx, filter, document, opts...)
+}
+
 func NewMongoClient() Client {
 	uri := fmt.Sprintf("mongodb://%s:%s@%s:%s", user, password, host, port)
 	client, err := mongo.Connect(context.TODO(), options.Client().ApplyURI(uri))
@@ -92,6 +96,7 @@ type Collection interface {
 	InsertOne(ctx context.Context, document interface{}, opts ...*options.InsertOneOptions) (InsertResult, error)
 	DeleteOne(ctx context.Context, filter interface{}, opts ...*options.DeleteOptions) (DeleteResult, error)
 	UpdateOne(ctx context.Context, filter interface{}, update interface{}, opts ...*options.UpdateOptions) (UpdateResult, error)
+	UpdateMany(ctx context.Context, filter interface{}, update interface{}, opts ...*options.UpdateOptions) (UpdateResult, error)
 }
 
 type Cursor interface {
@@ -0,0 +1,88 @@
+package mongodb
+
+import (
+	"context"
+
+	"github.com/kammeph/school-book-storage-service/application/copyapp"
+	"github.com/kammeph/school-book-storage-service/domain/copydomain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type CopyRepository struct {
+	collection Collection
+}
+
+func NewCopyRepository(client Client, dbName, tableName string) copyapp.CopyRepository {
+	collection := client.Database(dbName).Collection(tableName)
+	return &CopyRepository{collection}
+}
+
+func (r *CopyRepository) GetCopiesBySchoolID(ctx context.Context, schoolID string) ([]copydomain.CopyProjection, error) {
+	return r.find(ctx, bson.D{{Key: "schoolId", Value: schoolID}})
+}
+
+func (r *CopyRepository) GetCopiesByLocation(ctx context.Context, schoolID string, location copydomain.Location) ([]copydomain.CopyProjection, error) {
+	return r.find(ctx, bson.D{
+		{Key: "schoolId", Value: schoolID},
+		{Key: "location.kind", Value: location.Kind},
+		{Key: "location.id", Value: location.ID},
+		{Key: "writtenOff", Value: false},
+	})
+}
+
+func (r *CopyRepository) GetCopiesByBookID(ctx context.Context, schoolID, bookID string) ([]copydomain.CopyProjection, error) {
+	return r.find(ctx, bson.D{
+		{Key: "schoolId", Value: schoolID},
+		{Key: "bookId", Value: bookID},
+	})
+}
+
+func (r *CopyRepository) GetCopyByInventoryNumber(ctx context.Context, schoolID, inventoryNumber string) (copydomain.CopyProjection, error) {
+	filter := bson.D{
+		{Key: "schoolId", Value: schoolID},
+		{Key: "inventoryNumber", Value: inventoryNumber},
+	}
+	result := r.collection.FindOne(ctx, filter)
+	if result.Err() != nil {
+		return copydomain.CopyProjection{}, result.Err()
+	}
+	bookCopy := copydomain.CopyProjection{}
+	if err := result.Decode(&bookCopy); err != nil {
+		return bookCopy, err
+	}
+	return bookCopy, nil
+}
+
+func (r *CopyRepository) find(ctx context.Context, filter bson.D) ([]copydomain.CopyProjection, error) {
+	cursor, err := r.collection.Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "inventoryNumber", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+	copies := []copydomain.CopyProjection{}
+	if err := cursor.All(ctx, &copies); err != nil {
+		return nil, err
+	}
+	return copies, nil
+}
+
+func (r *CopyRepository) UpsertCopy(ctx context.Context, bookCopy copydomain.CopyProjection) error {
+	filter := bson.D{
+		{Key: "schoolId", Value: bookCopy.SchoolID},
+		{Key: "inventoryNumber", Value: bookCopy.InventoryNumber},
+	}
+	update := setIfNewer(bookCopy.Version, bson.D{
+		{Key: "schoolId", Value: bookCopy.SchoolID},
+		{Key: "inventoryNumber", Value: bookCopy.InventoryNumber},
+		{Key: "bookId", Value: bookCopy.BookID},
+		{Key: "isbn", Value: bookCopy.Isbn},
+		{Key: "title", Value: bookCopy.Title},
+		{Key: "location", Value: bookCopy.Location},
+		{Key: "condition", Value: bookCopy.Condition},
+		{Key: "writtenOff", Value: bookCopy.WrittenOff},
+		{Key: "registeredAt", Value: bookCopy.RegisteredAt},
+		{Key: "history", Value: bookCopy.History},
+	})
+	_, err := r.collection.UpdateOne(ctx, filter, update, options.Update().SetUpsert(true))
+	return err
+}
@@ -0,0 +1,105 @@
+package mongodb
+
+import (
+	"context"
+	"time"
+
+	"github.com/kammeph/school-book-storage-service/application/loanapp"
+	"github.com/kammeph/school-book-storage-service/domain/loandomain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type LoanRepository struct {
+	collection Collection
+}
+
+func NewLoanRepository(client Client, dbName, tableName string) loanapp.LoanRepository {
+	collection := client.Database(dbName).Collection(tableName)
+	return &LoanRepository{collection}
+}
+
+func (r *LoanRepository) GetLoansBySchoolID(ctx context.Context, schoolID string) ([]loandomain.LoanProjection, error) {
+	return r.find(ctx, bson.D{{Key: "schoolId", Value: schoolID}})
+}
+
+func (r *LoanRepository) GetLoanByID(ctx context.Context, schoolID, loanID string) (loandomain.LoanProjection, error) {
+	filter := bson.D{
+		{Key: "schoolId", Value: schoolID},
+		{Key: "loanId", Value: loanID},
+	}
+	result := r.collection.FindOne(ctx, filter)
+	if result.Err() != nil {
+		return loandomain.LoanProjection{}, result.Err()
+	}
+	loan := loandomain.LoanProjection{}
+	if err := result.Decode(&loan); err != nil {
+		return loan, err
+	}
+	return loan, nil
+}
+
+func (r *LoanRepository) GetLoansByPupilID(ctx context.Context, schoolID, pupilID string) ([]loandomain.LoanProjection, error) {
+	return r.find(ctx, bson.D{
+		{Key: "schoolId", Value: schoolID},
+		{Key: "pupilId", Value: pupilID},
+	})
+}
+
+func (r *LoanRepository) GetOverdueLoans(ctx context.Context, schoolID, classID string, at time.Time) ([]loandomain.LoanProjection, error) {
+	filter := bson.D{
+		{Key: "schoolId", Value: schoolID},
+		{Key: "returnedAt", Value: bson.D{{Key: "$exists", Value: false}}},
+		{Key: "dueDate", Value: bson.D{{Key: "$lt", Value: at}}},
+	}
+	if classID != "" {
+		filter = append(filter, bson.E{Key: "classId", Value: classID})
+	}
+	return r.find(ctx, filter)
+}
+
+func (r *LoanRepository) find(ctx context.Context, filter bson.D) ([]loandomain.LoanProjection, error) {
+	cursor, err := r.collection.Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "dueDate", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+	loans := []loandomain.LoanProjection{}
+	if err := cursor.All(ctx, &loans); err != nil {
+		return nil, err
+	}
+	return loans, nil
+}
+
+func (r *LoanRepository) UpsertLoan(ctx context.Context, loan loandomain.LoanProjection) error {
+	filter := bson.D{{Key: "loanId", Value: loan.LoanID}}
+	update := setIfNewer(loan.Version, bson.D{
+		{Key: "loanId", Value: loan.LoanID},
+		{Key: "schoolId", Value: loan.SchoolID},
+		{Key: "pupilId", Value: loan.PupilID},
+		{Key: "classId", Value: loan.ClassID},
+		{Key: "bookId", Value: loan.BookID},
+		{Key: "inventoryNumber", Value: loan.InventoryNumber},
+		{Key: "issuedAt", Value: loan.IssuedAt},
+		{Key: "dueDate", Value: loan.DueDate},
+		{Key: "extensions", Value: loan.Extensions},
+	})
+	_, err := r.collection.UpdateOne(ctx, filter, update, options.Update().SetUpsert(true))
+	return err
+}
+
+func (r *LoanRepository) UpdateLoanDueDate(ctx context.Context, loanID string, dueDate time.Time, extensions, version int) error {
+	filter := bson.D{{Key: "loanId", Value: loanID}}
+	update := setIfNewer(version, bson.D{
+		{Key: "dueDate", Value: dueDate},
+		{Key: "extensions", Value: extensions},
+	})
+	_, err := r.collection.UpdateOne(ctx, filter, update)
+	return err
+}
+
+func (r *LoanRepository) UpdateLoanReturned(ctx context.Context, loanID string, returnedAt time.Time, version int) error {
+	filter := bson.D{{Key: "loanId", Value: loanID}}
+	update := setIfNewer(version, bson.D{{Key: "returnedAt", Value: returnedAt}})
+	_, err := r.collection.UpdateOne(ctx, filter, update)
+	return err
+}
@@ -0,0 +1,58 @@
+package mongodb
+
+import (
+	"context"
+
+	"github.com/kammeph/school-book-storage-service/application/storageapp"
+	"github.com/kammeph/school-book-storage-service/domain/storagedomain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type LocationRepository struct {
+	collection Collection
+}
+
+func NewLocationRepository(client Client, dbName, tableName string) storageapp.LocationRepository {
+	collection := client.Database(dbName).Collection(tableName)
+	return &LocationRepository{collection}
+}
+
+func (r *LocationRepository) GetLocationsBySchoolID(ctx context.Context, schoolID string) ([]storagedomain.LocationProjection, error) {
+	filter := bson.D{{Key: "schoolId", Value: schoolID}}
+	cursor, err := r.collection.Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	locations := []storagedomain.LocationProjection{}
+	if err := cursor.All(ctx, &locations); err != nil {
+		return nil, err
+	}
+	return locations, nil
+}
+
+func (r *LocationRepository) UpsertLocation(ctx context.Context, location storagedomain.LocationProjection) error {
+	filter := bson.D{{Key: "locationId", Value: location.LocationID}}
+	update := setIfNewer(location.Version, bson.D{
+		{Key: "locationId", Value: location.LocationID},
+		{Key: "schoolId", Value: location.SchoolID},
+		{Key: "kind", Value: location.Kind},
+		{Key: "name", Value: location.Name},
+		{Key: "parentId", Value: location.ParentID},
+	})
+	_, err := r.collection.UpdateOne(ctx, filter, update, options.Update().SetUpsert(true))
+	return err
+}
+
+func (r *LocationRepository) UpdateLocationName(ctx context.Context, locationID, name string, version int) error {
+	filter := bson.D{{Key: "locationId", Value: locationID}}
+	update := setIfNewer(version, bson.D{{Key: "name", Value: name}})
+	_, err := r.collection.UpdateOne(ctx, filter, update)
+	return err
+}
+
+func (r *LocationRepository) DeleteLocation(ctx context.Context, locationID string, version int) error {
+	filter := olderThan("locationId", locationID, version)
+	_, err := r.collection.DeleteOne(ctx, filter)
+	return err
+}
@@ -0,0 +1,81 @@
+package mongodb
+
+import (
+	"context"
+
+	"github.com/kammeph/school-book-storage-service/domain"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// MigrateMoney converts amounts that projections stored as plain numbers into
+// money in the default currency. Documents that are already migrated are left
+// untouched, so it is safe to run on every start.
+func MigrateMoney(ctx context.Context, client Client, dbName string) error {
+	db := client.Database(dbName)
+	migrations := []struct {
+		collection string
+		fields     []string
+		update     bson.D
+	}{
+		{
+			collection: "books",
+			fields:     []string{"price", "priceHistory.price"},
+			update: bson.D{
+				{Key: "price", Value: moneyFromNumber("$price")},
+				{Key: "priceHistory", Value: mapMoney("$priceHistory", "change", "price")},
+			},
+		},
+		{
+			collection: "purchase_orders",
+			fields:     []string{"total", "lines.unitPrice"},
+			update: bson.D{
+				{Key: "total", Value: moneyFromNumber("$total")},
+				{Key: "lines", Value: mapMoney("$lines", "line", "unitPrice")},
+			},
+		},
+		{
+			collection: "charges",
+			fields:     []string{"price", "amount"},
+			update: bson.D{
+				{Key: "price", Value: moneyFromNumber("$price")},
+				{Key: "amount", Value: moneyFromNumber("$amount")},
+			},
+		},
+	}
+	for _, migration := range migrations {
+		legacy := bson.A{}
+		for _, field := range migration.fields {
+			legacy = append(legacy, bson.D{{Key: field, Value: bson.D{{Key: "$type", Value: "number"}}}})
+		}
+		filter := bson.D{{Key: "$or", Value: legacy}}
+		update := bson.A{bson.D{{Key: "$set", Value: migration.update}}}
+		if _, err := db.Collection(migration.collection).UpdateMany(ctx, filter, update); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func moneyFromNumber(expression string) bson.D {
+	cents := bson.D{{Key: "$toLong", Value: bson.D{{Key: "$round", Value: bson.A{
+		bson.D{{Key: "$multiply", Value: bson.A{expression, 100}}},
+		0,
+	}}}}}
+	return bson.D{{Key: "$cond", Value: bson.A{
+		bson.D{{Key: "$isNumber", Value: expression}},
+		bson.D{{Key: "amount", Value: cents}, {Key: "currency", Value: domain.DefaultCurrency}},
+		expression,
+	}}}
+}
+
+// mapMoney converts the field of every element of the array.
+func mapMoney(array, element, field string) bson.D {
+	return bson.D{{Key: "$map", Value: bson.D{
+		{Key: "input", Value: array},
+		{Key: "as", Value: element},
+		{Key: "in", Value: bson.D{{Key: "$mergeObjects", Value: bson.A{
+			"$$" + element,
+			bson.D{{Key: field, Value: moneyFromNumber("$$" + element + "." + field)}},
+		}}}},
+	}}}
+}
@@ -0,0 +1,100 @@
+package mongodb
+
+import (
+	"context"
+
+	"github.com/kammeph/school-book-storage-service/application/orderapp"
+	"github.com/kammeph/school-book-storage-service/domain/orderdomain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type PurchaseOrderRepository struct {
+	collection Collection
+}
+
+func NewPurchaseOrderRepository(client Client, dbName, tableName string) orderapp.PurchaseOrderRepository {
+	collection := client.Database(dbName).Collection(tableName)
+	return &PurchaseOrderRepository{collection}
+}
+
+func (r *PurchaseOrderRepository) GetOrdersBySchoolID(ctx context.Context, schoolID string) ([]orderdomain.PurchaseOrderProjection, error) {
+	return r.find(ctx, bson.D{{Key: "schoolId", Value: schoolID}})
+}
+
+func (r *PurchaseOrderRepository) GetOpenOrders(ctx context.Context, schoolID string) ([]orderdomain.PurchaseOrderProjection, error) {
+	return r.find(ctx, bson.D{
+		{Key: "schoolId", Value: schoolID},
+		{Key: "status", Value: bson.D{{Key: "$in", Value: bson.A{
+			orderdomain.Draft,
+			orderdomain.Sent,
+			orderdomain.PartiallyReceived,
+		}}}},
+	})
+}
+
+func (r *PurchaseOrderRepository) GetOrderByID(ctx context.Context, schoolID, orderID string) (orderdomain.PurchaseOrderProjection, error) {
+	filter := bson.D{
+		{Key: "schoolId", Value: schoolID},
+		{Key: "orderId", Value: orderID},
+	}
+	result := r.collection.FindOne(ctx, filter)
+	if result.Err() != nil {
+		return orderdomain.PurchaseOrderProjection{}, result.Err()
+	}
+	order := orderdomain.PurchaseOrderProjection{}
+	if err := result.Decode(&order); err != nil {
+		return order, err
+	}
+	return order, nil
+}
+
+func (r *PurchaseOrderRepository) find(ctx context.Context, filter bson.D) ([]orderdomain.PurchaseOrderProjection, error) {
+	cursor, err := r.collection.Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "createdAt", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+	orders := []orderdomain.PurchaseOrderProjection{}
+	if err := cursor.All(ctx, &orders); err != nil {
+		return nil, err
+	}
+	return orders, nil
+}
+
+func (r *PurchaseOrderRepository) UpsertOrder(ctx context.Context, order orderdomain.PurchaseOrderProjection) error {
+	filter := bson.D{{Key: "orderId", Value: order.OrderID}}
+	update := setIfNewer(order.Version, bson.D{
+		{Key: "orderId", Value: order.OrderID},
+		{Key: "schoolId", Value: order.SchoolID},
+		{Key: "supplier", Value: order.Supplier},
+		{Key: "lines", Value: order.Lines},
+		{Key: "status", Value: order.Status},
+		{Key: "total", Value: order.Total},
+		{Key: "createdAt", Value: order.CreatedAt},
+	})
+	_, err := r.collection.UpdateOne(ctx, filter, update, options.Update().SetUpsert(true))
+	return err
+}
+
+func (r *PurchaseOrderRepository) UpdateOrderStatus(ctx context.Context, orderID string, status orderdomain.OrderStatus, version int) error {
+	filter := bson.D{{Key: "orderId", Value: orderID}}
+	update := setIfNewer(version, bson.D{{Key: "status", Value: status}})
+	_, err := r.collection.UpdateOne(ctx, filter, update)
+	return err
+}
+
+func (r *PurchaseOrderRepository) UpdateOrderLines(
+	ctx context.Context,
+	orderID string,
+	lines []orderdomain.OrderLine,
+	status orderdomain.OrderStatus,
+	version int,
+) error {
+	filter := bson.D{{Key: "orderId", Value: orderID}}
+	update := setIfNewer(version, bson.D{
+		{Key: "lines", Value: lines},
+		{Key: "status", Value: status},
+	})
+	_, err := r.collection.UpdateOne(ctx, filter, update)
+	return err
+}
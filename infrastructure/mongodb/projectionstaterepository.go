@@ -0,0 +1,68 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+
+	"github.com/kammeph/school-book-storage-service/application"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type ProjectionStateRepository struct {
+	collection Collection
+}
+
+func NewProjectionStateRepository(client Client, dbName, tableName string) application.ProjectionStateRepository {
+	collection := client.Database(dbName).Collection(tableName)
+	return &ProjectionStateRepository{collection}
+}
+
+func (r *ProjectionStateRepository) GetProjectionState(ctx context.Context, projection, aggregateID string) (application.ProjectionState, error) {
+	filter := bson.D{
+		{Key: "projection", Value: projection},
+		{Key: "aggregateId", Value: aggregateID},
+	}
+	result := r.collection.FindOne(ctx, filter)
+	if errors.Is(result.Err(), mongo.ErrNoDocuments) {
+		return application.NewProjectionState(projection, aggregateID), nil
+	}
+	if result.Err() != nil {
+		return application.ProjectionState{}, result.Err()
+	}
+	state := application.ProjectionState{}
+	if err := result.Decode(&state); err != nil {
+		return application.ProjectionState{}, err
+	}
+	return state, nil
+}
+
+func (r *ProjectionStateRepository) GetProjectionStatesWithGaps(ctx context.Context, projection string) ([]application.ProjectionState, error) {
+	filter := bson.D{
+		{Key: "projection", Value: projection},
+		{Key: "missingVersions.0", Value: bson.D{{Key: "$exists", Value: true}}},
+	}
+	cursor, err := r.collection.Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	states := []application.ProjectionState{}
+	if err := cursor.All(ctx, &states); err != nil {
+		return nil, err
+	}
+	return states, nil
+}
+
+func (r *ProjectionStateRepository) SaveProjectionState(ctx context.Context, state application.ProjectionState) error {
+	filter := bson.D{
+		{Key: "projection", Value: state.Projection},
+		{Key: "aggregateId", Value: state.AggregateID},
+	}
+	update := bson.D{{Key: "$set", Value: bson.D{
+		{Key: "version", Value: state.Version},
+		{Key: "missingVersions", Value: state.MissingVersions},
+	}}}
+	_, err := r.collection.UpdateOne(ctx, filter, update, options.Update().SetUpsert(true))
+	return err
+}
@@ -0,0 +1,91 @@
+package mongodb
+
+import (
+	"context"
+	"time"
+
+	"github.com/kammeph/school-book-storage-service/application/pupilapp"
+	"github.com/kammeph/school-book-storage-service/domain/pupildomain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type PupilRepository struct {
+	collection Collection
+}
+
+func NewPupilRepository(client Client, dbName, tableName string) pupilapp.PupilRepository {
+	collection := client.Database(dbName).Collection(tableName)
+	return &PupilRepository{collection}
+}
+
+func (r *PupilRepository) GetPupilsBySchoolID(ctx context.Context, schoolID string) ([]pupildomain.PupilProjection, error) {
+	return r.find(ctx, bson.D{{Key: "schoolId", Value: schoolID}})
+}
+
+// GetPupilsByClassID only returns the pupils that are currently in the class.
+func (r *PupilRepository) GetPupilsByClassID(ctx context.Context, schoolID, classID string) ([]pupildomain.PupilProjection, error) {
+	return r.find(ctx, bson.D{
+		{Key: "schoolId", Value: schoolID},
+		{Key: "classId", Value: classID},
+		{Key: "exitDate", Value: bson.D{{Key: "$exists", Value: false}}},
+	})
+}
+
+func (r *PupilRepository) GetPupilByID(ctx context.Context, schoolID, pupilID string) (pupildomain.PupilProjection, error) {
+	filter := bson.D{
+		{Key: "schoolId", Value: schoolID},
+		{Key: "pupilId", Value: pupilID},
+	}
+	result := r.collection.FindOne(ctx, filter)
+	if result.Err() != nil {
+		return pupildomain.PupilProjection{}, result.Err()
+	}
+	pupil := pupildomain.PupilProjection{}
+	if err := result.Decode(&pupil); err != nil {
+		return pupil, err
+	}
+	return pupil, nil
+}
+
+func (r *PupilRepository) find(ctx context.Context, filter bson.D) ([]pupildomain.PupilProjection, error) {
+	sort := bson.D{{Key: "lastName", Value: 1}, {Key: "firstName", Value: 1}}
+	cursor, err := r.collection.Find(ctx, filter, options.Find().SetSort(sort))
+	if err != nil {
+		return nil, err
+	}
+	pupils := []pupildomain.PupilProjection{}
+	if err := cursor.All(ctx, &pupils); err != nil {
+		return nil, err
+	}
+	return pupils, nil
+}
+
+func (r *PupilRepository) UpsertPupil(ctx context.Context, pupil pupildomain.PupilProjection) error {
+	filter := bson.D{{Key: "pupilId", Value: pupil.PupilID}}
+	update := setIfNewer(pupil.Version, bson.D{
+		{Key: "pupilId", Value: pupil.PupilID},
+		{Key: "schoolId", Value: pupil.SchoolID},
+		{Key: "firstName", Value: pupil.FirstName},
+		{Key: "lastName", Value: pupil.LastName},
+		{Key: "dateOfBirth", Value: pupil.DateOfBirth},
+		{Key: "classId", Value: pupil.ClassID},
+		{Key: "entryDate", Value: pupil.EntryDate},
+	})
+	_, err := r.collection.UpdateOne(ctx, filter, update, options.Update().SetUpsert(true))
+	return err
+}
+
+func (r *PupilRepository) UpdatePupilClass(ctx context.Context, pupilID, classID string, version int) error {
+	filter := bson.D{{Key: "pupilId", Value: pupilID}}
+	update := setIfNewer(version, bson.D{{Key: "classId", Value: classID}})
+	_, err := r.collection.UpdateOne(ctx, filter, update)
+	return err
+}
+
+func (r *PupilRepository) UpdatePupilExitDate(ctx context.Context, pupilID string, exitDate time.Time, version int) error {
+	filter := bson.D{{Key: "pupilId", Value: pupilID}}
+	update := setIfNewer(version, bson.D{{Key: "exitDate", Value: exitDate}})
+	_, err := r.collection.UpdateOne(ctx, filter, update)
+	return err
+}
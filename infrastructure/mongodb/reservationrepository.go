@@ -0,0 +1,92 @@
+package mongodb
+
+import (
+	"context"
+	"time"
+
+	"github.com/kammeph/school-book-storage-service/application/reservationapp"
+	"github.com/kammeph/school-book-storage-service/domain/reservationdomain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type ReservationRepository struct {
+	collection Collection
+}
+
+func NewReservationRepository(client Client, dbName, tableName string) reservationapp.ReservationRepository {
+	collection := client.Database(dbName).Collection(tableName)
+	return &ReservationRepository{collection}
+}
+
+func (r *ReservationRepository) GetReservationsBySchoolID(
+	ctx context.Context,
+	schoolID string,
+) ([]reservationdomain.ReservationProjection, error) {
+	filter := bson.D{{Key: "schoolId", Value: schoolID}}
+	cursor, err := r.collection.Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "from", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+	reservations := []reservationdomain.ReservationProjection{}
+	if err := cursor.All(ctx, &reservations); err != nil {
+		return nil, err
+	}
+	return reservations, nil
+}
+
+func (r *ReservationRepository) GetReservationByID(
+	ctx context.Context,
+	schoolID, reservationID string,
+) (reservationdomain.ReservationProjection, error) {
+	filter := bson.D{
+		{Key: "schoolId", Value: schoolID},
+		{Key: "reservationId", Value: reservationID},
+	}
+	result := r.collection.FindOne(ctx, filter)
+	if result.Err() != nil {
+		return reservationdomain.ReservationProjection{}, result.Err()
+	}
+	reservation := reservationdomain.ReservationProjection{}
+	if err := result.Decode(&reservation); err != nil {
+		return reservation, err
+	}
+	return reservation, nil
+}
+
+func (r *ReservationRepository) UpsertReservation(ctx context.Context, reservation reservationdomain.ReservationProjection) error {
+	filter := bson.D{{Key: "reservationId", Value: reservation.ReservationID}}
+	update := setIfNewer(reservation.Version, bson.D{
+		{Key: "reservationId", Value: reservation.ReservationID},
+		{Key: "schoolId", Value: reservation.SchoolID},
+		{Key: "classId", Value: reservation.ClassID},
+		{Key: "teacherId", Value: reservation.TeacherID},
+		{Key: "bookId", Value: reservation.BookID},
+		{Key: "storageId", Value: reservation.StorageID},
+		{Key: "quantity", Value: reservation.Quantity},
+		{Key: "fulfilled", Value: reservation.Fulfilled},
+		{Key: "from", Value: reservation.From},
+		{Key: "until", Value: reservation.Until},
+	})
+	_, err := r.collection.UpdateOne(ctx, filter, update, options.Update().SetUpsert(true))
+	return err
+}
+
+func (r *ReservationRepository) UpdateReservationCancelled(
+	ctx context.Context,
+	reservationID string,
+	cancelledAt time.Time,
+	version int,
+) error {
+	filter := bson.D{{Key: "reservationId", Value: reservationID}}
+	update := setIfNewer(version, bson.D{{Key: "cancelledAt", Value: cancelledAt}})
+	_, err := r.collection.UpdateOne(ctx, filter, update)
+	return err
+}
+
+func (r *ReservationRepository) UpdateReservationFulfilled(ctx context.Context, reservationID string, fulfilled, version int) error {
+	filter := bson.D{{Key: "reservationId", Value: reservationID}}
+	update := setIfNewer(version, bson.D{{Key: "fulfilled", Value: fulfilled}})
+	_, err := r.collection.UpdateOne(ctx, filter, update)
+	return err
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/kammeph/school-book-storage-service/application/schoolapp"
 	"github.com/kammeph/school-book-storage-service/domain/schooldomain"
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 type SchoolRepository struct {
@@ -45,24 +46,25 @@ func (r *SchoolRepository) GetSchoolByID(ctx context.Context, schoolID string) (
 	return school, nil
 }
 
-func (r *SchoolRepository) InsertSchool(ctx context.Context, school schooldomain.SchoolProjection) error {
-	doc := bson.D{
+func (r *SchoolRepository) UpsertSchool(ctx context.Context, school schooldomain.SchoolProjection) error {
+	filter := bson.D{{Key: "schoolId", Value: school.SchoolID}}
+	update := setIfNewer(school.Version, bson.D{
 		{Key: "schoolId", Value: school.SchoolID},
 		{Key: "name", Value: school.Name},
-	}
-	_, err := r.collection.InsertOne(ctx, doc)
+	})
+	_, err := r.collection.UpdateOne(ctx, filter, update, options.Update().SetUpsert(true))
 	return err
 }
 
-func (r *SchoolRepository) DeleteSchool(ctx context.Context, schoolID string) error {
-	filter := bson.D{{Key: "schoolId", Value: schoolID}}
+func (r *SchoolRepository) DeleteSchool(ctx context.Context, schoolID string, version int) error {
+	filter := olderThan("schoolId", schoolID, version)
 	_, err := r.collection.DeleteOne(ctx, filter)
 	return err
 }
 
-func (r *SchoolRepository) UpdateSchoolName(ctx context.Context, schoolID, name string) error {
+func (r *SchoolRepository) UpdateSchoolName(ctx context.Context, schoolID, name string, version int) error {
 	filter := bson.D{{Key: "schoolId", Value: schoolID}}
-	update := bson.D{{Key: "$set", Value: bson.D{{Key: "name", Value: name}}}}
+	update := setIfNewer(version, bson.D{{Key: "name", Value: name}})
 	_, err := r.collection.UpdateOne(ctx, filter, update)
 	return err
 }
@@ -0,0 +1,76 @@
+package mongodb
+
+import (
+	"context"
+
+	"github.com/kammeph/school-book-storage-service/application/storageapp"
+	"github.com/kammeph/school-book-storage-service/domain/storagedomain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type StocktakingRepository struct {
+	collection Collection
+}
+
+func NewStocktakingRepository(client Client, dbName, tableName string) storageapp.StocktakingRepository {
+	collection := client.Database(dbName).Collection(tableName)
+	return &StocktakingRepository{collection}
+}
+
+func (r *StocktakingRepository) GetStocktakingsBySchoolID(ctx context.Context, schoolID string) ([]storagedomain.StocktakingProjection, error) {
+	filter := bson.D{{Key: "schoolId", Value: schoolID}}
+	cursor, err := r.collection.Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "openedAt", Value: -1}}))
+	if err != nil {
+		return nil, err
+	}
+	stocktakings := []storagedomain.StocktakingProjection{}
+	if err := cursor.All(ctx, &stocktakings); err != nil {
+		return nil, err
+	}
+	return stocktakings, nil
+}
+
+func (r *StocktakingRepository) GetStocktakingByID(ctx context.Context, schoolID, stocktakingID string) (storagedomain.StocktakingProjection, error) {
+	filter := bson.D{
+		{Key: "schoolId", Value: schoolID},
+		{Key: "stocktakingId", Value: stocktakingID},
+	}
+	result := r.collection.FindOne(ctx, filter)
+	if result.Err() != nil {
+		return storagedomain.StocktakingProjection{}, result.Err()
+	}
+	stocktaking := storagedomain.StocktakingProjection{}
+	if err := result.Decode(&stocktaking); err != nil {
+		return stocktaking, err
+	}
+	return stocktaking, nil
+}
+
+func (r *StocktakingRepository) UpsertStocktaking(ctx context.Context, stocktaking storagedomain.StocktakingProjection) error {
+	filter := bson.D{{Key: "stocktakingId", Value: stocktaking.StocktakingID}}
+	update := setIfNewer(stocktaking.Version, bson.D{
+		{Key: "stocktakingId", Value: stocktaking.StocktakingID},
+		{Key: "schoolId", Value: stocktaking.SchoolID},
+		{Key: "storageIds", Value: stocktaking.StorageIDs},
+		{Key: "counts", Value: stocktaking.Counts},
+		{Key: "closed", Value: stocktaking.Closed},
+		{Key: "openedAt", Value: stocktaking.OpenedAt},
+	})
+	_, err := r.collection.UpdateOne(ctx, filter, update, options.Update().SetUpsert(true))
+	return err
+}
+
+func (r *StocktakingRepository) UpdateStocktakingCounts(ctx context.Context, stocktakingID string, counts []storagedomain.StockCount, version int) error {
+	filter := bson.D{{Key: "stocktakingId", Value: stocktakingID}}
+	update := setIfNewer(version, bson.D{{Key: "counts", Value: counts}})
+	_, err := r.collection.UpdateOne(ctx, filter, update)
+	return err
+}
+
+func (r *StocktakingRepository) UpdateStocktakingClosed(ctx context.Context, stocktakingID string, version int) error {
+	filter := bson.D{{Key: "stocktakingId", Value: stocktakingID}}
+	update := setIfNewer(version, bson.D{{Key: "closed", Value: true}})
+	_, err := r.collection.UpdateOne(ctx, filter, update)
+	return err
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/kammeph/school-book-storage-service/application/storageapp"
 	"github.com/kammeph/school-book-storage-service/domain/storagedomain"
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 type StorageWithBookRepository struct {
@@ -59,34 +60,71 @@ func (c *StorageWithBookRepository) GetStorageByName(ctx context.Context, school
 	return storage, nil
 }
 
-func (c *StorageWithBookRepository) InsertStorage(ctx context.Context, storage storagedomain.StorageWithBooks) error {
-	doc := bson.D{
+func (c *StorageWithBookRepository) GetStoragesByLocationIDs(ctx context.Context, schoolID string, locationIDs []string) ([]storagedomain.StorageWithBooks, error) {
+	filter := bson.D{
+		{Key: "schoolId", Value: schoolID},
+		{Key: "locationId", Value: bson.D{{Key: "$in", Value: locationIDs}}},
+	}
+	cursor, err := c.collection.Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	storages := []storagedomain.StorageWithBooks{}
+	if err := cursor.All(ctx, &storages); err != nil {
+		return nil, err
+	}
+	return storages, nil
+}
+
+func (c *StorageWithBookRepository) UpsertStorage(ctx context.Context, storage storagedomain.StorageWithBooks) error {
+	filter := bson.D{{Key: "storageId", Value: storage.StorageID}}
+	update := setIfNewer(storage.Version, bson.D{
 		{Key: "storageId", Value: storage.StorageID},
 		{Key: "schoolId", Value: storage.SchoolID},
 		{Key: "name", Value: storage.Name},
 		{Key: "location", Value: storage.Location},
+		{Key: "locationId", Value: storage.LocationID},
+		{Key: "capacity", Value: storage.Capacity},
 		{Key: "books", Value: storage.Books},
-	}
-	_, err := c.collection.InsertOne(ctx, doc)
+	})
+	_, err := c.collection.UpdateOne(ctx, filter, update, options.Update().SetUpsert(true))
 	return err
 }
 
-func (c *StorageWithBookRepository) DeleteStorage(ctx context.Context, storageID string) error {
-	filter := bson.D{{Key: "storageId", Value: storageID}}
+func (c *StorageWithBookRepository) DeleteStorage(ctx context.Context, storageID string, version int) error {
+	filter := olderThan("storageId", storageID, version)
 	_, err := c.collection.DeleteOne(ctx, filter)
 	return err
 }
 
-func (c *StorageWithBookRepository) UpdateStorageName(ctx context.Context, storageID, name string) error {
+func (c *StorageWithBookRepository) UpdateStorageName(ctx context.Context, storageID, name string, version int) error {
+	filter := bson.D{{Key: "storageId", Value: storageID}}
+	update := setIfNewer(version, bson.D{{Key: "name", Value: name}})
+	_, err := c.collection.UpdateOne(ctx, filter, update)
+	return err
+}
+
+func (c *StorageWithBookRepository) UpdateStorageLocation(ctx context.Context, storageID, location, locationID string, version int) error {
+	filter := bson.D{{Key: "storageId", Value: storageID}}
+	update := setIfNewer(version, bson.D{
+		{Key: "location", Value: location},
+		{Key: "locationId", Value: locationID},
+	})
+	_, err := c.collection.UpdateOne(ctx, filter, update)
+	return err
+}
+
+func (c *StorageWithBookRepository) UpdateStorageCapacity(ctx context.Context, storageID string, capacity, version int) error {
 	filter := bson.D{{Key: "storageId", Value: storageID}}
-	update := bson.D{{Key: "$set", Value: bson.D{{Key: "name", Value: name}}}}
+	update := setIfNewer(version, bson.D{{Key: "capacity", Value: capacity}})
 	_, err := c.collection.UpdateOne(ctx, filter, update)
 	return err
 }
 
-func (c *StorageWithBookRepository) UpdateStorageLocation(ctx context.Context, storageID, location string) error {
+func (c *StorageWithBookRepository) UpdateStorageBooks(ctx context.Context, storageID string, books []storagedomain.BookInStorage, version int) error {
 	filter := bson.D{{Key: "storageId", Value: storageID}}
-	update := bson.D{{Key: "$set", Value: bson.D{{Key: "location", Value: location}}}}
+	update := setIfNewer(version, bson.D{{Key: "books", Value: books}})
 	_, err := c.collection.UpdateOne(ctx, filter, update)
 	return err
 }
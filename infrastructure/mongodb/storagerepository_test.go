@@ -12,6 +12,20 @@ import (
 	"go.mongodb.org/mongo-driver/bson"
 )
 
+// ifNewer is the update pipeline a projection sends to only overwrite the
+// fields if the stored document is older than the version.
+func ifNewer(version int, fields bson.D) bson.A {
+	set := bson.D{}
+	for _, field := range append(fields, bson.E{Key: "version", Value: version}) {
+		set = append(set, bson.E{Key: field.Key, Value: bson.D{{Key: "$cond", Value: bson.A{
+			bson.D{{Key: "$lt", Value: bson.A{"$version", version}}},
+			bson.D{{Key: "$literal", Value: field.Value}},
+			"$" + field.Key,
+		}}}})
+	}
+	return bson.A{bson.D{{Key: "$set", Value: set}}}
+}
+
 func TestGetAllStoragesBySchoolID(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -224,7 +238,7 @@ func TestGetStoragesByName(t *testing.T) {
 	}
 }
 
-func TestInsertStorage(t *testing.T) {
+func TestUpsertStorage(t *testing.T) {
 	tests := []struct {
 		name            string
 		database        string
@@ -237,7 +251,7 @@ func TestInsertStorage(t *testing.T) {
 		err             error
 	}{
 		{
-			name:            "insert storage",
+			name:            "upsert storage",
 			database:        "testdb",
 			collection:      "testcollection",
 			schoolID:        "school",
@@ -248,15 +262,15 @@ func TestInsertStorage(t *testing.T) {
 			err:             nil,
 		},
 		{
-			name:            "insert storage error",
+			name:            "upsert storage error",
 			database:        "testdb",
 			collection:      "testcollection",
-			schoolID:        "error",
-			storageID:       "storage1",
+			schoolID:        "school",
+			storageID:       "error",
 			storageName:     "Closet 1",
 			storageLocation: "Room 101",
 			expectError:     true,
-			err:             errors.New("mock-insert-error"),
+			err:             errors.New("mock-upsert-error"),
 		},
 	}
 	for _, test := range tests {
@@ -265,23 +279,27 @@ func TestInsertStorage(t *testing.T) {
 			database := client.Database(test.database)
 			collection := database.Collection(test.collection)
 			collection.(*mocks.MockCollection).
-				On("InsertOne", context.Background(), bson.D{
+				On("UpdateOne", context.Background(), bson.D{{Key: "storageId", Value: "storage1"}}, ifNewer(1, bson.D{
 					{Key: "storageId", Value: "storage1"},
 					{Key: "schoolId", Value: "school"},
 					{Key: "name", Value: "Closet 1"},
 					{Key: "location", Value: "Room 101"},
+					{Key: "locationId", Value: ""},
+					{Key: "capacity", Value: 0},
 					{Key: "books", Value: []storagedomain.BookInStorage{}},
-				}).
+				})).
 				Return(nil, nil)
 			collection.(*mocks.MockCollection).
-				On("InsertOne", context.Background(), bson.D{
-					{Key: "storageId", Value: "storage1"},
-					{Key: "schoolId", Value: "error"},
+				On("UpdateOne", context.Background(), bson.D{{Key: "storageId", Value: "error"}}, ifNewer(1, bson.D{
+					{Key: "storageId", Value: "error"},
+					{Key: "schoolId", Value: "school"},
 					{Key: "name", Value: "Closet 1"},
 					{Key: "location", Value: "Room 101"},
+					{Key: "locationId", Value: ""},
+					{Key: "capacity", Value: 0},
 					{Key: "books", Value: []storagedomain.BookInStorage{}},
-				}).
-				Return(nil, errors.New("mock-insert-error"))
+				})).
+				Return(nil, errors.New("mock-upsert-error"))
 			repository := mongodb.NewStorageWithBookRepository(client, test.database, test.collection)
 			storage := storagedomain.StorageWithBooks{
 				StorageID: test.storageID,
@@ -289,8 +307,9 @@ func TestInsertStorage(t *testing.T) {
 				Name:      test.storageName,
 				Location:  test.storageLocation,
 				Books:     []storagedomain.BookInStorage{},
+				Version:   1,
 			}
-			err := repository.InsertStorage(context.Background(), storage)
+			err := repository.UpsertStorage(context.Background(), storage)
 			if test.expectError {
 				assert.Error(t, err)
 				assert.Equal(t, test.err, err)
@@ -333,13 +352,19 @@ func TestDeleteStorage(t *testing.T) {
 			database := client.Database(test.database)
 			collection := database.Collection(test.collection)
 			collection.(*mocks.MockCollection).
-				On("DeleteOne", context.Background(), bson.D{{Key: "storageId", Value: "storage1"}}).
+				On("DeleteOne", context.Background(), bson.D{
+					{Key: "storageId", Value: "storage1"},
+					{Key: "version", Value: bson.D{{Key: "$lt", Value: 2}}},
+				}).
 				Return(nil, nil)
 			collection.(*mocks.MockCollection).
-				On("DeleteOne", context.Background(), bson.D{{Key: "storageId", Value: "error"}}).
+				On("DeleteOne", context.Background(), bson.D{
+					{Key: "storageId", Value: "error"},
+					{Key: "version", Value: bson.D{{Key: "$lt", Value: 2}}},
+				}).
 				Return(nil, errors.New("mock-delete-error"))
 			repository := mongodb.NewStorageWithBookRepository(client, test.database, test.collection)
-			err := repository.DeleteStorage(context.Background(), test.storageID)
+			err := repository.DeleteStorage(context.Background(), test.storageID, 2)
 			if test.expectError {
 				assert.Error(t, err)
 				assert.Equal(t, test.err, err)
@@ -385,17 +410,35 @@ func TestUpdateStorageName(t *testing.T) {
 			database := client.Database(test.database)
 			collection := database.Collection(test.collection)
 			collection.(*mocks.MockCollection).
-				On("UpdateOne", context.Background(),
-					bson.D{{Key: "storageId", Value: "storage1"}},
-					bson.D{{Key: "$set", Value: bson.D{{Key: "name", Value: "renamed"}}}}).
+				On("UpdateOne", context.Background(), bson.D{{Key: "storageId", Value: "storage1"}}, bson.A{bson.D{{Key: "$set", Value: bson.D{
+					{Key: "name", Value: bson.D{{Key: "$cond", Value: bson.A{
+						bson.D{{Key: "$lt", Value: bson.A{"$version", 2}}},
+						bson.D{{Key: "$literal", Value: "renamed"}},
+						"$name",
+					}}}},
+					{Key: "version", Value: bson.D{{Key: "$cond", Value: bson.A{
+						bson.D{{Key: "$lt", Value: bson.A{"$version", 2}}},
+						bson.D{{Key: "$literal", Value: 2}},
+						"$version",
+					}}}},
+				}}}}).
 				Return(nil, nil)
 			collection.(*mocks.MockCollection).
-				On("UpdateOne", context.Background(),
-					bson.D{{Key: "storageId", Value: "error"}},
-					bson.D{{Key: "$set", Value: bson.D{{Key: "name", Value: "error"}}}}).
+				On("UpdateOne", context.Background(), bson.D{{Key: "storageId", Value: "error"}}, bson.A{bson.D{{Key: "$set", Value: bson.D{
+					{Key: "name", Value: bson.D{{Key: "$cond", Value: bson.A{
+						bson.D{{Key: "$lt", Value: bson.A{"$version", 2}}},
+						bson.D{{Key: "$literal", Value: "error"}},
+						"$name",
+					}}}},
+					{Key: "version", Value: bson.D{{Key: "$cond", Value: bson.A{
+						bson.D{{Key: "$lt", Value: bson.A{"$version", 2}}},
+						bson.D{{Key: "$literal", Value: 2}},
+						"$version",
+					}}}},
+				}}}}).
 				Return(nil, errors.New("mock-update-error"))
 			repository := mongodb.NewStorageWithBookRepository(client, test.database, test.collection)
-			err := repository.UpdateStorageName(context.Background(), test.storageID, test.storageName)
+			err := repository.UpdateStorageName(context.Background(), test.storageID, test.storageName, 2)
 			if test.expectError {
 				assert.Error(t, err)
 				assert.Equal(t, test.err, err)
@@ -441,17 +484,63 @@ func TestUpdateStorageLocation(t *testing.T) {
 			database := client.Database(test.database)
 			collection := database.Collection(test.collection)
 			collection.(*mocks.MockCollection).
-				On("UpdateOne", context.Background(),
-					bson.D{{Key: "storageId", Value: "storage1"}},
-					bson.D{{Key: "$set", Value: bson.D{{Key: "location", Value: "relocated"}}}}).
+				On("UpdateOne", context.Background(), bson.D{{Key: "storageId", Value: "storage1"}}, ifNewer(2, bson.D{{Key: "location", Value: "relocated"}, {Key: "locationId", Value: ""}})).
+				Return(nil, nil)
+			collection.(*mocks.MockCollection).
+				On("UpdateOne", context.Background(), bson.D{{Key: "storageId", Value: "error"}}, ifNewer(2, bson.D{{Key: "location", Value: "error"}, {Key: "locationId", Value: ""}})).
+				Return(nil, errors.New("mock-update-error"))
+			repository := mongodb.NewStorageWithBookRepository(client, test.database, test.collection)
+			err := repository.UpdateStorageLocation(context.Background(), test.storageID, test.storageName, "", 2)
+			if test.expectError {
+				assert.Error(t, err)
+				assert.Equal(t, test.err, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestUpdateStorageBooks(t *testing.T) {
+	tests := []struct {
+		name        string
+		database    string
+		collection  string
+		storageID   string
+		expectError bool
+		err         error
+	}{
+		{
+			name:        "update storage books",
+			database:    "testdb",
+			collection:  "testcollection",
+			storageID:   "storage1",
+			expectError: false,
+			err:         nil,
+		},
+		{
+			name:        "update storage books error",
+			database:    "testdb",
+			collection:  "testcollection",
+			storageID:   "error",
+			expectError: true,
+			err:         errors.New("mock-update-error"),
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			client := mocks.NewMockClient()
+			database := client.Database(test.database)
+			collection := database.Collection(test.collection)
+			collection.(*mocks.MockCollection).
+				On("UpdateOne", context.Background(), bson.D{{Key: "storageId", Value: "storage1"}}, ifNewer(2, bson.D{{Key: "books", Value: []storagedomain.BookInStorage{{BookID: "book1", Quantity: 3}}}})).
 				Return(nil, nil)
 			collection.(*mocks.MockCollection).
-				On("UpdateOne", context.Background(),
-					bson.D{{Key: "storageId", Value: "error"}},
-					bson.D{{Key: "$set", Value: bson.D{{Key: "location", Value: "error"}}}}).
+				On("UpdateOne", context.Background(), bson.D{{Key: "storageId", Value: "error"}}, ifNewer(2, bson.D{{Key: "books", Value: []storagedomain.BookInStorage{{BookID: "book1", Quantity: 3}}}})).
 				Return(nil, errors.New("mock-update-error"))
 			repository := mongodb.NewStorageWithBookRepository(client, test.database, test.collection)
-			err := repository.UpdateStorageLocation(context.Background(), test.storageID, test.storageName)
+			books := []storagedomain.BookInStorage{{BookID: "book1", Quantity: 3}}
+			err := repository.UpdateStorageBooks(context.Background(), test.storageID, books, 2)
 			if test.expectError {
 				assert.Error(t, err)
 				assert.Equal(t, test.err, err)
@@ -0,0 +1,29 @@
+package mongodb
+
+import "go.mongodb.org/mongo-driver/bson"
+
+// setIfNewer builds an update pipeline that only overwrites the given fields if
+// the stored document reflects an older aggregate version than the event. A
+// missing version (e.g. on upsert) is always older.
+func setIfNewer(version int, fields bson.D) bson.A {
+	isNewer := bson.D{{Key: "$lt", Value: bson.A{"$version", version}}}
+	set := bson.D{}
+	for _, field := range append(fields, bson.E{Key: "version", Value: version}) {
+		set = append(set, bson.E{
+			Key: field.Key,
+			Value: bson.D{{Key: "$cond", Value: bson.A{
+				isNewer,
+				bson.D{{Key: "$literal", Value: field.Value}},
+				"$" + field.Key,
+			}}},
+		})
+	}
+	return bson.A{bson.D{{Key: "$set", Value: set}}}
+}
+
+func olderThan(key, value string, version int) bson.D {
+	return bson.D{
+		{Key: key, Value: value},
+		{Key: "version", Value: bson.D{{Key: "$lt", Value: version}}},
+	}
+}
@@ -0,0 +1,102 @@
+package mongodb
+
+import (
+	"context"
+
+	"github.com/kammeph/school-book-storage-service/application/webhookapp"
+	"github.com/kammeph/school-book-storage-service/domain/webhookdomain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type WebhookRepository struct {
+	collection Collection
+}
+
+func NewWebhookRepository(client Client, dbName, tableName string) webhookapp.WebhookRepository {
+	collection := client.Database(dbName).Collection(tableName)
+	return &WebhookRepository{collection}
+}
+
+func (r *WebhookRepository) GetWebhooksBySchoolID(ctx context.Context, schoolID string) ([]webhookdomain.WebhookProjection, error) {
+	filter := bson.D{{Key: "schoolId", Value: schoolID}}
+	cursor, err := r.collection.Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	webhooks := []webhookdomain.WebhookProjection{}
+	if err := cursor.All(ctx, &webhooks); err != nil {
+		return nil, err
+	}
+	return webhooks, nil
+}
+
+func (r *WebhookRepository) UpsertWebhook(ctx context.Context, webhook webhookdomain.WebhookProjection) error {
+	filter := bson.D{{Key: "webhookId", Value: webhook.WebhookID}}
+	update := setIfNewer(webhook.Version, bson.D{
+		{Key: "webhookId", Value: webhook.WebhookID},
+		{Key: "schoolId", Value: webhook.SchoolID},
+		{Key: "url", Value: webhook.URL},
+		{Key: "eventTypes", Value: webhook.EventTypes},
+		{Key: "active", Value: webhook.Active},
+		{Key: "failures", Value: webhook.Failures},
+	})
+	// Projections of webhooks registered before the secret store held the
+	// secret.
+	update = append(update, bson.D{{Key: "$unset", Value: "secret"}})
+	_, err := r.collection.UpdateOne(ctx, filter, update, options.Update().SetUpsert(true))
+	return err
+}
+
+func (r *WebhookRepository) DeleteWebhook(ctx context.Context, webhookID string, version int) error {
+	filter := olderThan("webhookId", webhookID, version)
+	_, err := r.collection.DeleteOne(ctx, filter)
+	return err
+}
+
+func (r *WebhookRepository) UpdateWebhookActive(ctx context.Context, webhookID string, active bool, version int) error {
+	filter := bson.D{{Key: "webhookId", Value: webhookID}}
+	update := setIfNewer(version, bson.D{
+		{Key: "active", Value: active},
+		{Key: "failures", Value: 0},
+	})
+	_, err := r.collection.UpdateOne(ctx, filter, update)
+	return err
+}
+
+func (r *WebhookRepository) UpdateWebhookFailures(ctx context.Context, webhookID string, failures int) error {
+	filter := bson.D{{Key: "webhookId", Value: webhookID}}
+	update := bson.D{{Key: "$set", Value: bson.D{{Key: "failures", Value: failures}}}}
+	_, err := r.collection.UpdateOne(ctx, filter, update)
+	return err
+}
+
+type DeliveryRepository struct {
+	collection Collection
+}
+
+func NewDeliveryRepository(client Client, dbName, tableName string) webhookapp.DeliveryRepository {
+	collection := client.Database(dbName).Collection(tableName)
+	return &DeliveryRepository{collection}
+}
+
+func (r *DeliveryRepository) GetDeliveriesByWebhookID(ctx context.Context, schoolID, webhookID string) ([]webhookdomain.Delivery, error) {
+	filter := bson.D{
+		{Key: "schoolId", Value: schoolID},
+		{Key: "webhookId", Value: webhookID},
+	}
+	cursor, err := r.collection.Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "deliveredAt", Value: -1}}))
+	if err != nil {
+		return nil, err
+	}
+	deliveries := []webhookdomain.Delivery{}
+	if err := cursor.All(ctx, &deliveries); err != nil {
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+func (r *DeliveryRepository) InsertDelivery(ctx context.Context, delivery webhookdomain.Delivery) error {
+	_, err := r.collection.InsertOne(ctx, delivery)
+	return err
+}
@@ -0,0 +1,48 @@
+package mongodb
+
+import (
+	"context"
+	"time"
+
+	"github.com/kammeph/school-book-storage-service/application/storageapp"
+	"github.com/kammeph/school-book-storage-service/domain/storagedomain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type WriteOffRepository struct {
+	collection Collection
+}
+
+func NewWriteOffRepository(client Client, dbName, tableName string) storageapp.WriteOffRepository {
+	collection := client.Database(dbName).Collection(tableName)
+	return &WriteOffRepository{collection}
+}
+
+func (r *WriteOffRepository) GetWriteOffs(ctx context.Context, schoolID string, from, to time.Time) ([]storagedomain.WriteOffProjection, error) {
+	filter := bson.D{
+		{Key: "schoolId", Value: schoolID},
+		{Key: "writtenOffAt", Value: bson.D{{Key: "$gte", Value: from}, {Key: "$lt", Value: to}}},
+	}
+	cursor, err := r.collection.Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "writtenOffAt", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+	writeOffs := []storagedomain.WriteOffProjection{}
+	if err := cursor.All(ctx, &writeOffs); err != nil {
+		return nil, err
+	}
+	return writeOffs, nil
+}
+
+// InsertWriteOff stores the write-off once. A write-off is identified by the
+// school and the version of the event that recorded it.
+func (r *WriteOffRepository) InsertWriteOff(ctx context.Context, writeOff storagedomain.WriteOffProjection) error {
+	filter := bson.D{
+		{Key: "schoolId", Value: writeOff.SchoolID},
+		{Key: "version", Value: writeOff.Version},
+	}
+	update := bson.D{{Key: "$setOnInsert", Value: writeOff}}
+	_, err := r.collection.UpdateOne(ctx, filter, update, options.Update().SetUpsert(true))
+	return err
+}
@@ -19,11 +19,14 @@ var (
 	pgsslmode  = utils.GetenvOrFallback("PG_SSLMODE", "disable")
 )
 
-func NewPostgresDB() *sql.DB {
-	connStr := fmt.Sprintf(
+func connectionString() string {
+	return fmt.Sprintf(
 		"user=%s password=%s host=%s port=%s dbname=%s sslmode=%s",
 		pguser, pgpassword, pghost, pgport, pgdbname, pgsslmode)
-	db, err := sql.Open(pgdriver, connStr)
+}
+
+func NewPostgresDB() *sql.DB {
+	db, err := sql.Open(pgdriver, connectionString())
 	if err != nil {
 		panic(err)
 	}
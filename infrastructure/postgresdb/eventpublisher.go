@@ -0,0 +1,31 @@
+package postgresdb
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/kammeph/school-book-storage-service/application"
+	"github.com/kammeph/school-book-storage-service/domain"
+)
+
+const notifySql = "SELECT pg_notify($1, $2)"
+
+// PostgresEventPublisher wakes up the subscribers of an exchange. The events
+// itself are already stored in the event table by the store, so the
+// notification only carries the ID of the changed aggregate.
+type PostgresEventPublisher struct {
+	db       *sql.DB
+	exchange string
+}
+
+func NewPostgresEventPublisher(db *sql.DB, exchange string) application.EventPublisher {
+	return &PostgresEventPublisher{db, exchange}
+}
+
+func (p *PostgresEventPublisher) Publish(ctx context.Context, events []domain.Event) error {
+	if len(events) == 0 {
+		return nil
+	}
+	_, err := p.db.ExecContext(ctx, notifySql, p.exchange, events[0].AggregateID())
+	return err
+}
@@ -0,0 +1,24 @@
+package postgresdb_test
+
+import (
+	"context"
+	"database/sql/driver"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/kammeph/school-book-storage-service/domain"
+	"github.com/kammeph/school-book-storage-service/infrastructure/postgresdb"
+	"github.com/stretchr/testify/assert"
+)
+
+const notifySql = "SELECT pg_notify\\(\\$1, \\$2\\)"
+
+func TestPublishNotifiesExchange(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	publisher := postgresdb.NewPostgresEventPublisher(db, "storage")
+	mock.ExpectExec(notifySql).WithArgs("storage", "school").WillReturnResult(driver.RowsAffected(1))
+	events := []domain.Event{&domain.EventModel{ID: "school", Version: 1, Type: "TEST"}}
+	err := publisher.Publish(context.Background(), events)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,263 @@
+package postgresdb
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kammeph/school-book-storage-service/application"
+	"github.com/kammeph/school-book-storage-service/domain"
+	"github.com/lib/pq"
+)
+
+const (
+	selectNewEventsSql  = "SELECT sequence, aggregate_id, type, version, timestamp, data FROM ${TABLE} WHERE sequence > $1 ORDER BY sequence ASC"
+	maxSequenceSql      = "SELECT COALESCE(MAX(sequence), 0) FROM ${TABLE}"
+	selectSequencesSql  = "SELECT sequence FROM ${TABLE} WHERE sequence > $1"
+	insertDeadLetterSql = "INSERT INTO dead_letters (id, exchange, data, error, timestamp) VALUES (gen_random_uuid(), $1, $2, $3, $4)"
+)
+
+// ExchangeTables maps the exchanges to the event tables the events of the
+// exchange are stored in.
+var ExchangeTables = map[string]string{
+	"storage":     "storages",
+	"school":      "schools",
+	"book":        "books",
+	"class":       "school_classes",
+	"webhook":     "webhooks",
+	"loan":        "loans",
+	"pupil":       "pupils",
+	"order":       "purchase_orders",
+	"charge":      "charges",
+	"copy":        "copies",
+	"reservation": "reservations",
+}
+
+// Sequences are drawn before a transaction commits, so an event can show up
+// after events with a higher sequence. A missing sequence is waited for until
+// commitTimeout passes, as rolled back transactions leave gaps for good. A new
+// subscription looks back startWindow sequences for events still in flight.
+const (
+	commitTimeout       = time.Minute
+	startWindow   int64 = 100
+)
+
+func ErrUnknownExchange(exchange string) error {
+	return fmt.Errorf("no event table configured for exchange %s", exchange)
+}
+
+type subscription struct {
+	exchange string
+	table    string
+	handler  application.EventHandler
+	policy   application.FailurePolicy
+	position int64
+	handled  map[int64]bool
+	gaps     map[int64]time.Time
+	stopped  bool
+}
+
+// advance moves the position past the handled sequences and the gaps that are
+// not filled within the commit timeout. Events above the position that were
+// already handled are remembered, so they are not handled twice.
+func (s *subscription) advance(now time.Time) {
+	last := s.position
+	for sequence := range s.handled {
+		if sequence > last {
+			last = sequence
+		}
+	}
+	for sequence := s.position + 1; sequence < last; sequence++ {
+		if _, ok := s.gaps[sequence]; !ok && !s.handled[sequence] {
+			s.gaps[sequence] = now
+		}
+	}
+	for next := s.position + 1; next <= last; next++ {
+		if seen, ok := s.gaps[next]; ok && !s.handled[next] && now.Sub(seen) < commitTimeout {
+			return
+		}
+		delete(s.gaps, next)
+		delete(s.handled, next)
+		s.position = next
+	}
+}
+
+type PostgresEventSubscriber struct {
+	db            *sql.DB
+	listener      Listener
+	tables        map[string]string
+	pollInterval  time.Duration
+	mutex         sync.Mutex
+	subscriptions []*subscription
+}
+
+func NewPostgresEventSubscriber(db *sql.DB, listener Listener, tables map[string]string, pollInterval time.Duration) application.EventSubscriber {
+	subscriber := &PostgresEventSubscriber{
+		db:            db,
+		listener:      listener,
+		tables:        tables,
+		pollInterval:  pollInterval,
+		subscriptions: []*subscription{},
+	}
+	go subscriber.run()
+	return subscriber
+}
+
+func (s *PostgresEventSubscriber) Subscribe(exchange string, handler application.EventHandler, policy application.FailurePolicy) error {
+	table, ok := s.tables[exchange]
+	if !ok {
+		return ErrUnknownExchange(exchange)
+	}
+	subscription, err := s.newSubscription(exchange, table, handler, policy)
+	if err != nil {
+		return err
+	}
+	if err := s.listener.Listen(exchange); err != nil && err != pq.ErrChannelAlreadyOpen {
+		return err
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.subscriptions = append(s.subscriptions, subscription)
+	return nil
+}
+
+// newSubscription starts after the events that are in the table already. The
+// sequences missing within the start window may belong to events that are not
+// committed yet, so they are handled once they show up.
+func (s *PostgresEventSubscriber) newSubscription(
+	exchange, table string,
+	handler application.EventHandler,
+	policy application.FailurePolicy,
+) (*subscription, error) {
+	maxSequence, err := s.maxSequence(table)
+	if err != nil {
+		return nil, err
+	}
+	position := maxSequence - startWindow
+	if position < 0 {
+		position = 0
+	}
+	subscription := &subscription{
+		exchange: exchange,
+		table:    table,
+		handler:  handler,
+		policy:   policy,
+		position: position,
+		handled:  map[int64]bool{},
+		gaps:     map[int64]time.Time{},
+	}
+	rows, err := s.db.Query(expand(selectSequencesSql, table), position)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var sequence int64
+		if err := rows.Scan(&sequence); err != nil {
+			return nil, err
+		}
+		subscription.handled[sequence] = true
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	subscription.advance(time.Now())
+	return subscription, nil
+}
+
+// run waits for notifications and reads the new events of the notified exchange
+// from its event table. As notifications get lost while the connection is
+// down, all subscriptions catch up after a reconnect and in a regular interval.
+func (s *PostgresEventSubscriber) run() {
+	for {
+		select {
+		case notification, ok := <-s.listener.Notifications():
+			if !ok {
+				return
+			}
+			if notification == nil {
+				s.catchUp("")
+				continue
+			}
+			s.catchUp(notification.Channel)
+		case <-time.After(s.pollInterval):
+			if err := s.listener.Ping(); err != nil {
+				log.Printf("postgres listener ping failed: %s", err)
+			}
+			s.catchUp("")
+		}
+	}
+}
+
+func (s *PostgresEventSubscriber) catchUp(exchange string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	for _, subscription := range s.subscriptions {
+		if subscription.stopped || (exchange != "" && subscription.exchange != exchange) {
+			continue
+		}
+		if err := s.handleNewEvents(subscription); err != nil {
+			log.Printf("error while reading events of exchange %s: %s", subscription.exchange, err)
+		}
+	}
+}
+
+func (s *PostgresEventSubscriber) handleNewEvents(subscription *subscription) error {
+	ctx := context.Background()
+	rows, err := s.db.QueryContext(ctx, expand(selectNewEventsSql, subscription.table), subscription.position)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	defer subscription.advance(time.Now())
+	for rows.Next() {
+		var sequence int64
+		event := domain.EventModel{}
+		if err := rows.Scan(&sequence, &event.ID, &event.Type, &event.Version, &event.At, &event.Data); err != nil {
+			return err
+		}
+		if subscription.handled[sequence] {
+			continue
+		}
+		eventBytes, err := json.Marshal(event)
+		if err != nil {
+			return err
+		}
+		if err := subscription.policy.Handle(ctx, subscription.handler, eventBytes); err != nil {
+			log.Printf("error while handling event from exchange %s: %s", subscription.exchange, err)
+			switch subscription.policy.Mode {
+			case application.StopOnFailure:
+				subscription.stopped = true
+				return nil
+			case application.DeadLetterOnFailure:
+				if err := s.insertDeadLetter(ctx, subscription.exchange, eventBytes, err); err != nil {
+					return err
+				}
+			}
+		}
+		subscription.handled[sequence] = true
+	}
+	return rows.Err()
+}
+
+func (s *PostgresEventSubscriber) insertDeadLetter(ctx context.Context, exchange string, eventBytes []byte, handlerErr error) error {
+	_, err := s.db.ExecContext(ctx, insertDeadLetterSql, exchange, string(eventBytes), handlerErr.Error(), time.Now())
+	return err
+}
+
+func (s *PostgresEventSubscriber) maxSequence(table string) (int64, error) {
+	var sequence int64
+	if err := s.db.QueryRow(expand(maxSequenceSql, table)).Scan(&sequence); err != nil {
+		return 0, err
+	}
+	return sequence, nil
+}
+
+func expand(stmt, table string) string {
+	return strings.Replace(stmt, "${TABLE}", table, -1)
+}
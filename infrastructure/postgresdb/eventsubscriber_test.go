@@ -0,0 +1,178 @@
+package postgresdb_test
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/kammeph/school-book-storage-service/application"
+	"github.com/kammeph/school-book-storage-service/domain"
+	"github.com/kammeph/school-book-storage-service/infrastructure/postgresdb"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	maxSequenceSql     = "SELECT COALESCE\\(MAX\\(sequence\\), 0\\) FROM storages"
+	selectSequencesSql = "SELECT sequence FROM storages WHERE sequence > \\$1"
+	selectNewEventsSql = "SELECT sequence, aggregate_id, type, version, timestamp, data FROM storages WHERE sequence > \\$1 ORDER BY sequence ASC"
+	insertDeadLetter   = "INSERT INTO dead_letters"
+)
+
+type mockListener struct {
+	channels      []string
+	notifications chan *pq.Notification
+}
+
+func newMockListener() *mockListener {
+	return &mockListener{notifications: make(chan *pq.Notification)}
+}
+
+func (l *mockListener) Listen(channel string) error {
+	l.channels = append(l.channels, channel)
+	return nil
+}
+
+func (l *mockListener) Notifications() <-chan *pq.Notification {
+	return l.notifications
+}
+
+func (l *mockListener) Ping() error {
+	return nil
+}
+
+func (l *mockListener) Close() error {
+	close(l.notifications)
+	return nil
+}
+
+type receivingHandler struct {
+	err    error
+	events chan domain.EventModel
+}
+
+func (h *receivingHandler) Handle(ctx context.Context, eventBytes []byte) error {
+	event := domain.EventModel{}
+	if err := json.Unmarshal(eventBytes, &event); err != nil {
+		return err
+	}
+	h.events <- event
+	return h.err
+}
+
+func expectSubscribe(mock sqlmock.Sqlmock, sequences ...int64) {
+	rows := sqlmock.NewRows([]string{"sequence"})
+	for _, sequence := range sequences {
+		rows.AddRow(sequence)
+	}
+	mock.ExpectQuery(maxSequenceSql).WillReturnRows(sqlmock.NewRows([]string{"max"}).AddRow(sequences[len(sequences)-1]))
+	mock.ExpectQuery(selectSequencesSql).WithArgs(0).WillReturnRows(rows)
+}
+
+func eventRows(sequences ...int64) *sqlmock.Rows {
+	rows := sqlmock.NewRows([]string{"sequence", "aggregate_id", "type", "version", "timestamp", "data"})
+	for _, sequence := range sequences {
+		rows.AddRow(sequence, "school", "TEST", sequence, time.Now(), "{}")
+	}
+	return rows
+}
+
+func TestSubscribeUnknownExchange(t *testing.T) {
+	db, _, _ := sqlmock.New()
+	listener := newMockListener()
+	defer listener.Close()
+	subscriber := postgresdb.NewPostgresEventSubscriber(db, listener, postgresdb.ExchangeTables, time.Hour)
+	err := subscriber.Subscribe("unknown", &receivingHandler{}, application.NewSkipPolicy())
+	assert.Equal(t, postgresdb.ErrUnknownExchange("unknown"), err)
+}
+
+func TestSubscriberReadsEventsFromTable(t *testing.T) {
+	tests := []struct {
+		name       string
+		policy     application.FailurePolicy
+		handlerErr error
+		deadLetter bool
+	}{
+		{
+			name:       "handle notified events",
+			policy:     application.NewSkipPolicy(),
+			handlerErr: nil,
+			deadLetter: false,
+		},
+		{
+			name:       "dead letter failing events",
+			policy:     application.NewDeadLetterPolicy(0, time.Millisecond),
+			handlerErr: errors.New("handler error"),
+			deadLetter: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			db, mock, _ := sqlmock.New()
+			listener := newMockListener()
+			defer listener.Close()
+			subscriber := postgresdb.NewPostgresEventSubscriber(db, listener, postgresdb.ExchangeTables, time.Hour)
+			handler := &receivingHandler{err: test.handlerErr, events: make(chan domain.EventModel, 1)}
+
+			expectSubscribe(mock, 1, 2, 3, 4)
+			err := subscriber.Subscribe("storage", handler, test.policy)
+			assert.NoError(t, err)
+			assert.Equal(t, []string{"storage"}, listener.channels)
+
+			rows := sqlmock.
+				NewRows([]string{"sequence", "aggregate_id", "type", "version", "timestamp", "data"}).
+				AddRow(5, "school", "TEST", 3, time.Now(), "{}")
+			mock.ExpectQuery(selectNewEventsSql).WithArgs(4).WillReturnRows(rows)
+			if test.deadLetter {
+				mock.ExpectExec(insertDeadLetter).WillReturnResult(driver.RowsAffected(1))
+			}
+			listener.notifications <- &pq.Notification{Channel: "storage", Extra: "school"}
+
+			select {
+			case event := <-handler.events:
+				assert.Equal(t, "school", event.AggregateID())
+				assert.Equal(t, 3, event.EventVersion())
+			case <-time.After(time.Second):
+				t.Fatal("event was not handled")
+			}
+			assert.Eventually(t, func() bool { return mock.ExpectationsWereMet() == nil }, time.Second, 10*time.Millisecond)
+		})
+	}
+}
+
+func TestSubscriberHandlesEventsCommittedOutOfOrder(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	listener := newMockListener()
+	defer listener.Close()
+	subscriber := postgresdb.NewPostgresEventSubscriber(db, listener, postgresdb.ExchangeTables, time.Hour)
+	handler := &receivingHandler{events: make(chan domain.EventModel, 10)}
+
+	// event 3 is still in flight when subscribing
+	expectSubscribe(mock, 1, 2, 4)
+	assert.NoError(t, subscriber.Subscribe("storage", handler, application.NewSkipPolicy()))
+
+	// event 6 commits after event 7
+	mock.ExpectQuery(selectNewEventsSql).WithArgs(2).WillReturnRows(eventRows(4, 5, 7))
+	mock.ExpectQuery(selectNewEventsSql).WithArgs(2).WillReturnRows(eventRows(3, 4, 5, 6, 7))
+	mock.ExpectQuery(selectNewEventsSql).WithArgs(7).WillReturnRows(eventRows())
+	for idx := 0; idx < 3; idx++ {
+		listener.notifications <- &pq.Notification{Channel: "storage", Extra: "school"}
+	}
+
+	versions := []int{}
+	for len(versions) < 4 {
+		select {
+		case event := <-handler.events:
+			versions = append(versions, event.EventVersion())
+		case <-time.After(time.Second):
+			t.Fatalf("events were not handled, got %v", versions)
+		}
+	}
+	assert.Equal(t, []int{5, 7, 3, 6}, versions)
+	assert.Eventually(t, func() bool { return mock.ExpectationsWereMet() == nil }, time.Second, 10*time.Millisecond)
+	assert.Empty(t, handler.events)
+}
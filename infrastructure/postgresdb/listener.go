@@ -0,0 +1,33 @@
+package postgresdb
+
+import (
+	"log"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+type Listener interface {
+	Listen(channel string) error
+	Notifications() <-chan *pq.Notification
+	Ping() error
+	Close() error
+}
+
+type ListenerWrapper struct {
+	*pq.Listener
+}
+
+func (l ListenerWrapper) Notifications() <-chan *pq.Notification {
+	return l.Notify
+}
+
+func NewPostgresListener() Listener {
+	listener := pq.NewListener(connectionString(), 10*time.Second, time.Minute, func(event pq.ListenerEventType, err error) {
+		if err != nil {
+			log.Printf("postgres listener: %s", err)
+		}
+	})
+	log.Println("Successfully created postgres listener.")
+	return ListenerWrapper{listener}
+}
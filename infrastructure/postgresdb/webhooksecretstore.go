@@ -0,0 +1,42 @@
+package postgresdb
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/kammeph/school-book-storage-service/application/webhookapp"
+	"github.com/kammeph/school-book-storage-service/domain/webhookdomain"
+)
+
+const (
+	upsertSecretSql = "INSERT INTO webhook_secrets (webhook_id, secret) VALUES ($1, $2) ON CONFLICT (webhook_id) DO UPDATE SET secret = EXCLUDED.secret"
+	selectSecretSql = "SELECT secret FROM webhook_secrets WHERE webhook_id = $1"
+	deleteSecretSql = "DELETE FROM webhook_secrets WHERE webhook_id = $1"
+)
+
+type PostgresWebhookSecretStore struct {
+	db *sql.DB
+}
+
+func NewPostgresWebhookSecretStore(db *sql.DB) webhookapp.WebhookSecretStore {
+	return &PostgresWebhookSecretStore{db}
+}
+
+func (s *PostgresWebhookSecretStore) SaveSecret(ctx context.Context, webhookID, secret string) error {
+	_, err := s.db.ExecContext(ctx, upsertSecretSql, webhookID, secret)
+	return err
+}
+
+func (s *PostgresWebhookSecretStore) GetSecret(ctx context.Context, webhookID string) (string, error) {
+	var secret string
+	err := s.db.QueryRowContext(ctx, selectSecretSql, webhookID).Scan(&secret)
+	if err == sql.ErrNoRows {
+		return "", webhookdomain.ErrWebhookSecretNotFound(webhookID)
+	}
+	return secret, err
+}
+
+func (s *PostgresWebhookSecretStore) DeleteSecret(ctx context.Context, webhookID string) error {
+	_, err := s.db.ExecContext(ctx, deleteSecretSql, webhookID)
+	return err
+}
@@ -0,0 +1,38 @@
+package postgresdb_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/kammeph/school-book-storage-service/infrastructure/postgresdb"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	upsertSecretSql = "INSERT INTO webhook_secrets \\(webhook_id, secret\\) VALUES \\(\\$1, \\$2\\) ON CONFLICT \\(webhook_id\\) DO UPDATE SET secret = EXCLUDED.secret"
+	selectSecretSql = "SELECT secret FROM webhook_secrets WHERE webhook_id = \\$1"
+	deleteSecretSql = "DELETE FROM webhook_secrets WHERE webhook_id = \\$1"
+)
+
+func TestWebhookSecretStore(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	secrets := postgresdb.NewPostgresWebhookSecretStore(db)
+	ctx := context.Background()
+
+	mock.ExpectExec(upsertSecretSql).WithArgs("webhook", "secret").WillReturnResult(sqlmock.NewResult(0, 1))
+	assert.NoError(t, secrets.SaveSecret(ctx, "webhook", "secret"))
+
+	mock.ExpectQuery(selectSecretSql).WithArgs("webhook").WillReturnRows(sqlmock.NewRows([]string{"secret"}).AddRow("secret"))
+	secret, err := secrets.GetSecret(ctx, "webhook")
+	assert.NoError(t, err)
+	assert.Equal(t, "secret", secret)
+
+	mock.ExpectExec(deleteSecretSql).WithArgs("webhook").WillReturnResult(sqlmock.NewResult(0, 1))
+	assert.NoError(t, secrets.DeleteSecret(ctx, "webhook"))
+
+	mock.ExpectQuery(selectSecretSql).WithArgs("webhook").WillReturnRows(sqlmock.NewRows([]string{"secret"}))
+	_, err = secrets.GetSecret(ctx, "webhook")
+	assert.Error(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
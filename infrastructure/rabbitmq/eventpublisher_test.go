@@ -18,8 +18,9 @@ type EntityEvent struct {
 
 type EntityEvenHandler struct{}
 
-func (h EntityEvenHandler) Handle(ctx context.Context, eventData []byte) {
+func (h EntityEvenHandler) Handle(ctx context.Context, eventData []byte) error {
 	fmt.Printf("%v", eventData)
+	return nil
 }
 
 func TestNewEventPublisher(t *testing.T) {
@@ -2,21 +2,44 @@ package rabbitmq
 
 import (
 	"context"
+	"log"
 
+	"github.com/google/uuid"
 	"github.com/kammeph/school-book-storage-service/application"
+	amqp "github.com/rabbitmq/amqp091-go"
 )
 
 type Subscription struct {
-	channel AmqpChannel
-	handler application.EventHandler
+	channel  AmqpChannel
+	handler  application.EventHandler
+	policy   application.FailurePolicy
+	consumer string
 }
 
-func NewSubscription(channel AmqpChannel, handler application.EventHandler) *Subscription {
-	return &Subscription{channel, handler}
+func NewSubscription(channel AmqpChannel, handler application.EventHandler, policy application.FailurePolicy) *Subscription {
+	return &Subscription{channel, handler, policy, uuid.NewString()}
+}
+
+func DeadLetterExchange(exchange string) string {
+	return exchange + ".dead-letter"
 }
 
 func (s *Subscription) Consume(exchange string) error {
-	q, err := s.channel.QueueDeclare("", false, false, true, false, nil)
+	// The exchange is declared like the publisher does, so subscribing does not
+	// depend on the publisher being set up first.
+	if err := s.channel.ExchangeDeclare(exchange, "fanout", true, false, false, false, nil); err != nil {
+		return err
+	}
+	// The queue is deleted with the subscription, so events a stopped
+	// subscription did not handle are parked in the dead letter queue.
+	var args amqp.Table
+	if s.policy.Mode == application.DeadLetterOnFailure || s.policy.Mode == application.StopOnFailure {
+		if err := s.declareDeadLetterQueue(exchange); err != nil {
+			return err
+		}
+		args = amqp.Table{"x-dead-letter-exchange": DeadLetterExchange(exchange)}
+	}
+	q, err := s.channel.QueueDeclare("", false, false, true, false, args)
 	if err != nil {
 		return err
 	}
@@ -24,20 +47,60 @@ func (s *Subscription) Consume(exchange string) error {
 		return err
 	}
 
-	msgs, err := s.channel.Consume(q.Name, "", true, false, false, false, nil)
+	msgs, err := s.channel.Consume(q.Name, s.consumer, false, false, false, false, nil)
 	if err != nil {
 		return err
 	}
 	go func() {
 		for msg := range msgs {
-			if s.handler != nil {
-				s.handler.Handle(context.Background(), msg.Body)
+			if !s.handle(exchange, msg) {
+				return
 			}
 		}
 	}()
 	return nil
 }
 
+func (s *Subscription) declareDeadLetterQueue(exchange string) error {
+	deadLetterExchange := DeadLetterExchange(exchange)
+	if err := s.channel.ExchangeDeclare(deadLetterExchange, "fanout", true, false, false, false, nil); err != nil {
+		return err
+	}
+	q, err := s.channel.QueueDeclare(deadLetterExchange, true, false, false, false, nil)
+	if err != nil {
+		return err
+	}
+	return s.channel.QueueBind(q.Name, "", deadLetterExchange, false, nil)
+}
+
+// handle acknowledges the message according to the failure policy and reports
+// whether the subscription should keep on consuming.
+func (s *Subscription) handle(exchange string, msg amqp.Delivery) bool {
+	if s.handler == nil {
+		msg.Ack(false)
+		return true
+	}
+	err := s.policy.Handle(context.Background(), s.handler, msg.Body)
+	if err == nil {
+		msg.Ack(false)
+		return true
+	}
+	log.Printf("error while handling event from exchange %s: %s", exchange, err)
+	switch s.policy.Mode {
+	case application.StopOnFailure:
+		msg.Nack(false, false)
+		if err := s.channel.Cancel(s.consumer, false); err != nil {
+			log.Printf("error while stopping subscription to exchange %s: %s", exchange, err)
+		}
+		return false
+	case application.DeadLetterOnFailure:
+		msg.Nack(false, false)
+	default:
+		msg.Ack(false)
+	}
+	return true
+}
+
 type RabbitEventSubscriber struct {
 	channel       AmqpChannel
 	subscriptions []*Subscription
@@ -51,8 +114,8 @@ func NewRabbitEventSubscriber(connection AmqpConnection) (application.EventSubsc
 	return &RabbitEventSubscriber{channel, []*Subscription{}}, nil
 }
 
-func (s *RabbitEventSubscriber) Subscribe(exchange string, handler application.EventHandler) error {
-	subscription := NewSubscription(s.channel, handler)
+func (s *RabbitEventSubscriber) Subscribe(exchange string, handler application.EventHandler, policy application.FailurePolicy) error {
+	subscription := NewSubscription(s.channel, handler, policy)
 	if err := subscription.Consume(exchange); err != nil {
 		return err
 	}
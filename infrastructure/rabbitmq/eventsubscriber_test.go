@@ -2,7 +2,9 @@ package rabbitmq_test
 
 import (
 	"testing"
+	"time"
 
+	"github.com/kammeph/school-book-storage-service/application"
 	"github.com/kammeph/school-book-storage-service/infrastructure/rabbitmq"
 	"github.com/stretchr/testify/assert"
 )
@@ -44,30 +46,63 @@ func TestSubscribe(t *testing.T) {
 	tests := []struct {
 		name         string
 		connection   MockConntection
+		policy       application.FailurePolicy
 		err          error
 		exspectError bool
 	}{
 		{
 			name:         "subscribe",
 			connection:   NewMockConnection(false, false, false, false, false, false, false),
+			policy:       application.NewSkipPolicy(),
 			err:          nil,
 			exspectError: false,
 		},
+		{
+			name:         "subscribe with dead letter policy",
+			connection:   NewMockConnection(false, false, false, false, false, false, false),
+			policy:       application.NewDeadLetterPolicy(3, time.Millisecond),
+			err:          nil,
+			exspectError: false,
+		},
+		{
+			name:         "subscribe with stop policy",
+			connection:   NewMockConnection(false, false, false, false, false, false, false),
+			policy:       application.NewStopPolicy(),
+			err:          nil,
+			exspectError: false,
+		},
+		{
+			name:         "subscribe exchange declare error",
+			connection:   NewMockConnection(false, true, false, false, false, false, false),
+			policy:       application.NewSkipPolicy(),
+			err:          errExchangeDeclare,
+			exspectError: true,
+		},
+		{
+			name:         "subscribe dead letter exchange declare error",
+			connection:   NewMockConnection(false, true, false, false, false, false, false),
+			policy:       application.NewDeadLetterPolicy(3, time.Millisecond),
+			err:          errExchangeDeclare,
+			exspectError: true,
+		},
 		{
 			name:         "subscribe queue declare error",
 			connection:   NewMockConnection(false, false, true, false, false, false, false),
+			policy:       application.NewRetryPolicy(3, time.Millisecond),
 			err:          errQueueDeclare,
 			exspectError: true,
 		},
 		{
 			name:         "subscribe queue bind error",
 			connection:   NewMockConnection(false, false, false, true, false, false, false),
+			policy:       application.NewStopPolicy(),
 			err:          errQueueBind,
 			exspectError: true,
 		},
 		{
 			name:         "subscribe consume error",
 			connection:   NewMockConnection(false, false, false, false, true, false, false),
+			policy:       application.NewSkipPolicy(),
 			err:          errConsume,
 			exspectError: true,
 		},
@@ -77,7 +112,13 @@ func TestSubscribe(t *testing.T) {
 			broker, err := rabbitmq.NewRabbitEventSubscriber(test.connection)
 			assert.Nil(t, err)
 			assert.NotNil(t, broker)
-			broker.Subscribe("test", EntityEvenHandler{})
+			err = broker.Subscribe("test", EntityEvenHandler{}, test.policy)
+			if test.exspectError {
+				assert.Error(t, err)
+				assert.Equal(t, test.err, err)
+				return
+			}
+			assert.NoError(t, err)
 		})
 	}
 }
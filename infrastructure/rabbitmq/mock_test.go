@@ -105,3 +105,7 @@ func (ch *MockChannel) Publish(exchange, key string, mandatory, immediate bool,
 	}
 	return nil
 }
+
+func (ch *MockChannel) Cancel(consumer string, noWait bool) error {
+	return nil
+}
@@ -9,6 +9,7 @@ psql -v ON_ERROR_STOP=1 --username "$POSTGRES_USER" --dbname "$POSTGRES_DB" <<-E
 		version INTEGER NOT NULL,
 		timestamp TIMESTAMP NOT NULL,
 		data VARCHAR(255) NOT NULL,
+		sequence BIGSERIAL NOT NULL,
 		PRIMARY KEY (id)
 	);
 	CREATE TABLE IF NOT EXISTS storages (
@@ -17,7 +18,8 @@ psql -v ON_ERROR_STOP=1 --username "$POSTGRES_USER" --dbname "$POSTGRES_DB" <<-E
 		type VARCHAR(100) NOT NULL,
 		version INTEGER NOT NULL,
 		timestamp TIMESTAMP NOT NULL,
-		data VARCHAR(255) NOT NULL,
+		data TEXT NOT NULL,
+		sequence BIGSERIAL NOT NULL,
 		PRIMARY KEY (id)
 	);
 	CREATE TABLE IF NOT EXISTS school_classes (
@@ -26,7 +28,68 @@ psql -v ON_ERROR_STOP=1 --username "$POSTGRES_USER" --dbname "$POSTGRES_DB" <<-E
 		type VARCHAR(100) NOT NULL,
 		version INTEGER NOT NULL,
 		timestamp TIMESTAMP NOT NULL,
-		data VARCHAR(255) NOT NULL,
+		data TEXT NOT NULL,
+		sequence BIGSERIAL NOT NULL,
+		PRIMARY KEY (id)
+	);
+	CREATE TABLE IF NOT EXISTS loans (
+		id VARCHAR(100) NOT NULL,
+		aggregate_id VARCHAR(100) NOT NULL,
+		type VARCHAR(100) NOT NULL,
+		version INTEGER NOT NULL,
+		timestamp TIMESTAMP NOT NULL,
+		data TEXT NOT NULL,
+		sequence BIGSERIAL NOT NULL,
+		PRIMARY KEY (id)
+	);
+	CREATE TABLE IF NOT EXISTS pupils (
+		id VARCHAR(100) NOT NULL,
+		aggregate_id VARCHAR(100) NOT NULL,
+		type VARCHAR(100) NOT NULL,
+		version INTEGER NOT NULL,
+		timestamp TIMESTAMP NOT NULL,
+		data TEXT NOT NULL,
+		sequence BIGSERIAL NOT NULL,
+		PRIMARY KEY (id)
+	);
+	CREATE TABLE IF NOT EXISTS purchase_orders (
+		id VARCHAR(100) NOT NULL,
+		aggregate_id VARCHAR(100) NOT NULL,
+		type VARCHAR(100) NOT NULL,
+		version INTEGER NOT NULL,
+		timestamp TIMESTAMP NOT NULL,
+		data TEXT NOT NULL,
+		sequence BIGSERIAL NOT NULL,
+		PRIMARY KEY (id)
+	);
+	CREATE TABLE IF NOT EXISTS charges (
+		id VARCHAR(100) NOT NULL,
+		aggregate_id VARCHAR(100) NOT NULL,
+		type VARCHAR(100) NOT NULL,
+		version INTEGER NOT NULL,
+		timestamp TIMESTAMP NOT NULL,
+		data TEXT NOT NULL,
+		sequence BIGSERIAL NOT NULL,
+		PRIMARY KEY (id)
+	);
+	CREATE TABLE IF NOT EXISTS copies (
+		id VARCHAR(100) NOT NULL,
+		aggregate_id VARCHAR(100) NOT NULL,
+		type VARCHAR(100) NOT NULL,
+		version INTEGER NOT NULL,
+		timestamp TIMESTAMP NOT NULL,
+		data TEXT NOT NULL,
+		sequence BIGSERIAL NOT NULL,
+		PRIMARY KEY (id)
+	);
+	CREATE TABLE IF NOT EXISTS reservations (
+		id VARCHAR(100) NOT NULL,
+		aggregate_id VARCHAR(100) NOT NULL,
+		type VARCHAR(100) NOT NULL,
+		version INTEGER NOT NULL,
+		timestamp TIMESTAMP NOT NULL,
+		data TEXT NOT NULL,
+		sequence BIGSERIAL NOT NULL,
 		PRIMARY KEY (id)
 	);
 	CREATE TABLE IF NOT EXISTS books (
@@ -35,7 +98,8 @@ psql -v ON_ERROR_STOP=1 --username "$POSTGRES_USER" --dbname "$POSTGRES_DB" <<-E
 		type VARCHAR(100) NOT NULL,
 		version INTEGER NOT NULL,
 		timestamp TIMESTAMP NOT NULL,
-		data VARCHAR(255) NOT NULL,
+		data TEXT NOT NULL,
+		sequence BIGSERIAL NOT NULL,
 		PRIMARY KEY (id)
 	);
 	CREATE TABLE IF NOT EXISTS users (
@@ -45,6 +109,42 @@ psql -v ON_ERROR_STOP=1 --username "$POSTGRES_USER" --dbname "$POSTGRES_DB" <<-E
 		version INTEGER NOT NULL,
 		timestamp TIMESTAMP NOT NULL,
 		data VARCHAR(255) NOT NULL,
+		sequence BIGSERIAL NOT NULL,
+		PRIMARY KEY (id)
+	);
+	CREATE TABLE IF NOT EXISTS webhooks (
+		id VARCHAR(100) NOT NULL,
+		aggregate_id VARCHAR(100) NOT NULL,
+		type VARCHAR(100) NOT NULL,
+		version INTEGER NOT NULL,
+		timestamp TIMESTAMP NOT NULL,
+		data TEXT NOT NULL,
+		sequence BIGSERIAL NOT NULL,
+		PRIMARY KEY (id)
+	);
+	ALTER TABLE schools ADD COLUMN IF NOT EXISTS sequence BIGSERIAL NOT NULL;
+	ALTER TABLE storages ADD COLUMN IF NOT EXISTS sequence BIGSERIAL NOT NULL;
+	ALTER TABLE school_classes ADD COLUMN IF NOT EXISTS sequence BIGSERIAL NOT NULL;
+	ALTER TABLE loans ADD COLUMN IF NOT EXISTS sequence BIGSERIAL NOT NULL;
+	ALTER TABLE pupils ADD COLUMN IF NOT EXISTS sequence BIGSERIAL NOT NULL;
+	ALTER TABLE purchase_orders ADD COLUMN IF NOT EXISTS sequence BIGSERIAL NOT NULL;
+	ALTER TABLE charges ADD COLUMN IF NOT EXISTS sequence BIGSERIAL NOT NULL;
+	ALTER TABLE copies ADD COLUMN IF NOT EXISTS sequence BIGSERIAL NOT NULL;
+	ALTER TABLE reservations ADD COLUMN IF NOT EXISTS sequence BIGSERIAL NOT NULL;
+	ALTER TABLE books ADD COLUMN IF NOT EXISTS sequence BIGSERIAL NOT NULL;
+	ALTER TABLE users ADD COLUMN IF NOT EXISTS sequence BIGSERIAL NOT NULL;
+	ALTER TABLE webhooks ADD COLUMN IF NOT EXISTS sequence BIGSERIAL NOT NULL;
+	CREATE TABLE IF NOT EXISTS webhook_secrets (
+		webhook_id VARCHAR(100) NOT NULL,
+		secret VARCHAR(100) NOT NULL,
+		PRIMARY KEY (webhook_id)
+	);
+	CREATE TABLE IF NOT EXISTS dead_letters (
+		id VARCHAR(100) NOT NULL,
+		exchange VARCHAR(100) NOT NULL,
+		data TEXT NOT NULL,
+		error TEXT NOT NULL,
+		timestamp TIMESTAMP NOT NULL,
 		PRIMARY KEY (id)
 	);
 EOSQL
\ No newline at end of file
@@ -0,0 +1,8 @@
+package fixtures
+
+import "github.com/kammeph/school-book-storage-service/domain"
+
+// Euro returns the amount in cents in the default currency.
+func Euro(cents int64) domain.Money {
+	return domain.NewMoney(cents, domain.DefaultCurrency)
+}
@@ -112,6 +112,19 @@ func (c *MockCollection) UpdateOne(ctx context.Context, filter interface{}, docu
 	return result, err
 }
 
+func (c *MockCollection) UpdateMany(ctx context.Context, filter interface{}, document interface{}, opts ...*options.UpdateOptions) (mongodb.UpdateResult, error) {
+	ret := c.Called(ctx, filter, document)
+
+	var result mongodb.UpdateResult
+	if ret.Get(0) != nil {
+		result = ret.Get(0).(mongodb.UpdateResult)
+	}
+
+	err := ret.Error(1)
+
+	return result, err
+}
+
 type MockCursor struct {
 	data []byte
 }
@@ -0,0 +1,131 @@
+package books
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/kammeph/school-book-storage-service/application"
+	"github.com/kammeph/school-book-storage-service/application/bookapp"
+	"github.com/kammeph/school-book-storage-service/domain/userdomain"
+	"github.com/kammeph/school-book-storage-service/infrastructure/httpclient"
+	"github.com/kammeph/school-book-storage-service/infrastructure/memory"
+	"github.com/kammeph/school-book-storage-service/infrastructure/mongodb"
+	"github.com/kammeph/school-book-storage-service/infrastructure/postgresdb"
+	"github.com/kammeph/school-book-storage-service/infrastructure/rabbitmq"
+	"github.com/kammeph/school-book-storage-service/infrastructure/utils"
+	"github.com/kammeph/school-book-storage-service/web"
+)
+
+func PostgresMongoRabbitConfig(postgresDB *sql.DB, mongoClient mongodb.Client, rabbit rabbitmq.AmqpConnection) {
+	publisher, err := rabbitmq.NewRabbitEventPublisher(rabbit, "book")
+	if err != nil {
+		panic(err)
+	}
+	subscriber, err := rabbitmq.NewRabbitEventSubscriber(rabbit)
+	if err != nil {
+		panic(err)
+	}
+	postgresMongoConfig(postgresDB, mongoClient, publisher, subscriber)
+}
+
+func PostgresMongoConfig(postgresDB *sql.DB, mongoClient mongodb.Client, subscriber application.EventSubscriber) {
+	publisher := postgresdb.NewPostgresEventPublisher(postgresDB, "book")
+	postgresMongoConfig(postgresDB, mongoClient, publisher, subscriber)
+}
+
+func postgresMongoConfig(
+	postgresDB *sql.DB,
+	mongoClient mongodb.Client,
+	publisher application.EventPublisher,
+	subscriber application.EventSubscriber,
+) {
+	store := postgresdb.NewPostgresStore("books", postgresDB)
+	repository := mongodb.NewBookRepository(mongoClient, "school_book_storage", "books")
+	states := mongodb.NewProjectionStateRepository(mongoClient, "school_book_storage", "projection_states")
+
+	eventHandler := application.NewGapDetector("books", states, bookapp.NewBookEventHandler(repository))
+	if err := subscriber.Subscribe("book", eventHandler, application.NewDeadLetterPolicy(3, time.Second)); err != nil {
+		panic(err)
+	}
+
+	commandHandlers := bookapp.NewBookCommandHandlers(store, publisher)
+	queryHandlers := bookapp.NewBookQueryHandlers(repository, metadataProvider())
+
+	controller := NewBookController(commandHandlers, queryHandlers)
+	configureEndpoints(controller)
+}
+
+// metadataProvider reads the book metadata from the JSON file in
+// BOOK_METADATA_FILE if it is set and asks Open Library otherwise.
+func metadataProvider() bookapp.MetadataProvider {
+	var provider bookapp.MetadataProvider
+	if path := utils.GetenvOrFallback("BOOK_METADATA_FILE", ""); path != "" {
+		fileProvider, err := memory.NewFileMetadataProvider(path)
+		if err != nil {
+			panic(err)
+		}
+		provider = fileProvider
+	} else {
+		provider = httpclient.NewOpenLibraryMetadataProvider(
+			utils.GetenvOrFallback("BOOK_METADATA_URL", httpclient.OpenLibraryURL),
+			5*time.Second)
+	}
+	return bookapp.NewCachingMetadataProvider(provider, 24*time.Hour)
+}
+
+func configureEndpoints(controller *BookController) {
+	web.Get(
+		"/api/books/get-all/",
+		web.IsAllowed(
+			controller.GetAllBooks,
+			[]userdomain.Role{userdomain.User, userdomain.Superuser, userdomain.Admin},
+		))
+	web.Get(
+		"/api/books/get-by-grade/",
+		web.IsAllowed(
+			controller.GetBooksByGrade,
+			[]userdomain.Role{userdomain.User, userdomain.Superuser, userdomain.Admin},
+		))
+	web.Get(
+		"/api/books/get-by-id/",
+		web.IsAllowed(
+			controller.GetBookByID,
+			[]userdomain.Role{userdomain.User, userdomain.Superuser, userdomain.Admin},
+		))
+	web.Get(
+		"/api/books/labels/",
+		web.IsAllowed(
+			controller.GetBookLabels,
+			[]userdomain.Role{userdomain.User, userdomain.Superuser, userdomain.Admin},
+		))
+	web.Get(
+		"/api/books/get-add-template/",
+		web.IsAllowed(
+			controller.GetAddBookTemplate,
+			[]userdomain.Role{userdomain.Admin},
+		))
+	web.Post(
+		"/api/books/add",
+		web.IsAllowed(
+			controller.AddBook,
+			[]userdomain.Role{userdomain.Admin},
+		))
+	web.Post(
+		"/api/books/adjust-meta",
+		web.IsAllowed(
+			controller.AdjustBookMeta,
+			[]userdomain.Role{userdomain.Admin},
+		))
+	web.Post(
+		"/api/books/increase-price",
+		web.IsAllowed(
+			controller.IncreaseBookPrice,
+			[]userdomain.Role{userdomain.Admin},
+		))
+	web.Post(
+		"/api/books/decrease-price",
+		web.IsAllowed(
+			controller.DecreaseBookPrice,
+			[]userdomain.Role{userdomain.Admin},
+		))
+}
@@ -0,0 +1,151 @@
+package books
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/kammeph/school-book-storage-service/application/bookapp"
+	"github.com/kammeph/school-book-storage-service/web"
+)
+
+type BookController struct {
+	commandHandlers bookapp.BookCommandHandlers
+	queryHandlers   bookapp.BookQueryHandlers
+}
+
+func NewBookController(commandHandlers bookapp.BookCommandHandlers, queryHandlers bookapp.BookQueryHandlers) *BookController {
+	return &BookController{commandHandlers, queryHandlers}
+}
+
+func (c BookController) AddBook(w http.ResponseWriter, r *http.Request) {
+	var command bookapp.AddBookCommand
+	json.NewDecoder(r.Body).Decode(&command)
+	ctx := context.Background()
+	defer ctx.Done()
+	bookID, err := c.commandHandlers.AddBookHandler.Handle(ctx, command)
+	if err != nil {
+		web.HttpErrorResponse(w, err.Error())
+		return
+	}
+	web.HttpResponse(w, bookID)
+}
+
+func (c BookController) AdjustBookMeta(w http.ResponseWriter, r *http.Request) {
+	var command bookapp.AdjustBookMetaCommand
+	json.NewDecoder(r.Body).Decode(&command)
+	ctx := context.Background()
+	defer ctx.Done()
+	if err := c.commandHandlers.AdjustBookMetaHandler.Handle(ctx, command); err != nil {
+		web.HttpErrorResponse(w, err.Error())
+	}
+}
+
+func (c BookController) IncreaseBookPrice(w http.ResponseWriter, r *http.Request) {
+	var command bookapp.IncreaseBookPriceCommand
+	json.NewDecoder(r.Body).Decode(&command)
+	ctx := context.Background()
+	defer ctx.Done()
+	if err := c.commandHandlers.IncreaseBookPriceHandler.Handle(ctx, command); err != nil {
+		web.HttpErrorResponse(w, err.Error())
+	}
+}
+
+func (c BookController) DecreaseBookPrice(w http.ResponseWriter, r *http.Request) {
+	var command bookapp.DecreaseBookPriceCommand
+	json.NewDecoder(r.Body).Decode(&command)
+	ctx := context.Background()
+	defer ctx.Done()
+	if err := c.commandHandlers.DecreaseBookPriceHandler.Handle(ctx, command); err != nil {
+		web.HttpErrorResponse(w, err.Error())
+	}
+}
+
+func (c BookController) GetAllBooks(w http.ResponseWriter, r *http.Request) {
+	ctx := context.Background()
+	defer ctx.Done()
+	path := strings.Split(r.URL.Path, "/")
+	aggregateID := path[len(path)-1]
+	query := bookapp.NewGetAllBooks(aggregateID)
+	books, err := c.queryHandlers.GetAllHandler.Handle(ctx, query)
+	if err != nil {
+		web.HttpErrorResponse(w, err.Error())
+		return
+	}
+	web.HttpResponse(w, books)
+}
+
+func (c BookController) GetBooksByGrade(w http.ResponseWriter, r *http.Request) {
+	ctx := context.Background()
+	defer ctx.Done()
+	path := strings.Split(r.URL.Path, "/")
+	aggregateID := path[len(path)-2]
+	grade, err := strconv.Atoi(path[len(path)-1])
+	if err != nil {
+		web.HttpErrorResponse(w, err.Error())
+		return
+	}
+	query := bookapp.NewGetBooksByGrade(aggregateID, grade)
+	books, err := c.queryHandlers.GetByGradeHandler.Handle(ctx, query)
+	if err != nil {
+		web.HttpErrorResponse(w, err.Error())
+		return
+	}
+	web.HttpResponse(w, books)
+}
+
+func (c BookController) GetBookByID(w http.ResponseWriter, r *http.Request) {
+	ctx := context.Background()
+	defer ctx.Done()
+	path := strings.Split(r.URL.Path, "/")
+	aggregateID := path[len(path)-2]
+	bookID := path[len(path)-1]
+	query := bookapp.NewGetBookByID(aggregateID, bookID)
+	book, err := c.queryHandlers.GetBookByIDHandler.Handle(ctx, query)
+	if err != nil {
+		web.HttpErrorResponse(w, err.Error())
+		return
+	}
+	web.HttpResponse(w, book)
+}
+
+func (c BookController) GetAddBookTemplate(w http.ResponseWriter, r *http.Request) {
+	ctx := context.Background()
+	defer ctx.Done()
+	path := strings.Split(r.URL.Path, "/")
+	aggregateID := path[len(path)-2]
+	isbn := path[len(path)-1]
+	query := bookapp.NewGetAddBookTemplate(aggregateID, isbn)
+	template, err := c.queryHandlers.GetTemplateHandler.Handle(ctx, query)
+	if err != nil {
+		web.HttpErrorResponse(w, err.Error())
+		return
+	}
+	web.HttpResponse(w, template)
+}
+
+// GetBookLabels serves /api/books/labels/{schoolId}?ids={bookIds}&copies={n}
+// and responds with printable EAN-13 labels of the ISBNs of the selected books.
+func (c BookController) GetBookLabels(w http.ResponseWriter, r *http.Request) {
+	ctx := context.Background()
+	defer ctx.Done()
+	path := strings.Split(r.URL.Path, "/")
+	aggregateID := path[len(path)-1]
+	copies := 1
+	if value := r.URL.Query().Get("copies"); value != "" {
+		var err error
+		if copies, err = strconv.Atoi(value); err != nil {
+			web.HttpErrorResponse(w, err.Error())
+			return
+		}
+	}
+	query := bookapp.NewGetBookLabels(aggregateID, web.QueryList(r, "ids"), copies)
+	labels, err := c.queryHandlers.GetLabelsHandler.Handle(ctx, query)
+	if err != nil {
+		web.HttpErrorResponse(w, err.Error())
+		return
+	}
+	web.LabelResponse(w, r, "book-labels", labels)
+}
@@ -0,0 +1,103 @@
+package charges
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/kammeph/school-book-storage-service/application"
+	"github.com/kammeph/school-book-storage-service/application/chargeapp"
+	"github.com/kammeph/school-book-storage-service/domain/userdomain"
+	"github.com/kammeph/school-book-storage-service/infrastructure/mongodb"
+	"github.com/kammeph/school-book-storage-service/infrastructure/postgresdb"
+	"github.com/kammeph/school-book-storage-service/infrastructure/rabbitmq"
+	"github.com/kammeph/school-book-storage-service/web"
+)
+
+func PostgresMongoRabbitConfig(postgresDB *sql.DB, mongoClient mongodb.Client, rabbit rabbitmq.AmqpConnection) {
+	publisher, err := rabbitmq.NewRabbitEventPublisher(rabbit, "charge")
+	if err != nil {
+		panic(err)
+	}
+	subscriber, err := rabbitmq.NewRabbitEventSubscriber(rabbit)
+	if err != nil {
+		panic(err)
+	}
+	postgresMongoConfig(postgresDB, mongoClient, publisher, subscriber)
+}
+
+func PostgresMongoConfig(postgresDB *sql.DB, mongoClient mongodb.Client, subscriber application.EventSubscriber) {
+	publisher := postgresdb.NewPostgresEventPublisher(postgresDB, "charge")
+	postgresMongoConfig(postgresDB, mongoClient, publisher, subscriber)
+}
+
+func postgresMongoConfig(
+	postgresDB *sql.DB,
+	mongoClient mongodb.Client,
+	publisher application.EventPublisher,
+	subscriber application.EventSubscriber,
+) {
+	store := postgresdb.NewPostgresStore("charges", postgresDB)
+	bookStore := postgresdb.NewPostgresStore("books", postgresDB)
+	pupilStore := postgresdb.NewPostgresStore("pupils", postgresDB)
+	loanStore := postgresdb.NewPostgresStore("loans", postgresDB)
+	repository := mongodb.NewChargeRepository(mongoClient, "school_book_storage", "charges")
+	pupils := mongodb.NewPupilRepository(mongoClient, "school_book_storage", "pupils")
+	schools := mongodb.NewSchoolRepository(mongoClient, "school_book_storage", "schools")
+	states := mongodb.NewProjectionStateRepository(mongoClient, "school_book_storage", "projection_states")
+
+	eventHandler := application.NewGapDetector("charges", states, chargeapp.NewChargeEventHandler(repository))
+	if err := subscriber.Subscribe("charge", eventHandler, application.NewDeadLetterPolicy(3, time.Second)); err != nil {
+		panic(err)
+	}
+
+	commandHandlers := chargeapp.NewChargeCommandHandlers(store, publisher, bookStore, pupilStore, loanStore)
+	queryHandlers := chargeapp.NewChargeQueryHandlers(repository, pupils, schools)
+
+	controller := NewChargeController(commandHandlers, queryHandlers)
+	configureEndpoints(controller)
+}
+
+func configureEndpoints(controller *ChargeController) {
+	web.Get(
+		"/api/charges/get-all/",
+		web.IsAllowed(
+			controller.GetAllCharges,
+			[]userdomain.Role{userdomain.User, userdomain.Superuser, userdomain.Admin},
+		))
+	web.Get(
+		"/api/charges/get-open/",
+		web.IsAllowed(
+			controller.GetOpenCharges,
+			[]userdomain.Role{userdomain.User, userdomain.Superuser, userdomain.Admin},
+		))
+	web.Get(
+		"/api/charges/get-payment-notice/",
+		web.IsAllowed(
+			controller.GetPaymentNotice,
+			[]userdomain.Role{userdomain.User, userdomain.Superuser, userdomain.Admin},
+		))
+	web.Post(
+		"/api/charges/create",
+		web.IsAllowed(
+			controller.CreateCharge,
+			[]userdomain.Role{userdomain.Superuser, userdomain.Admin},
+		))
+	web.Post(
+		"/api/charges/pay",
+		web.IsAllowed(
+			controller.PayCharge,
+			[]userdomain.Role{userdomain.Superuser, userdomain.Admin},
+		))
+	web.Post(
+		"/api/charges/waive",
+		web.IsAllowed(
+			controller.WaiveCharge,
+			[]userdomain.Role{userdomain.Admin},
+		))
+	web.Post(
+		"/api/charges/configure-depreciation",
+		web.IsAllowed(
+			controller.ConfigureDepreciation,
+			[]userdomain.Role{userdomain.Admin},
+		))
+}
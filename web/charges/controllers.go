@@ -0,0 +1,116 @@
+package charges
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/kammeph/school-book-storage-service/application/chargeapp"
+	"github.com/kammeph/school-book-storage-service/web"
+)
+
+type ChargeController struct {
+	commandHandlers chargeapp.ChargeCommandHandlers
+	queryHandlers   chargeapp.ChargeQueryHandlers
+}
+
+func NewChargeController(commandHandlers chargeapp.ChargeCommandHandlers, queryHandlers chargeapp.ChargeQueryHandlers) *ChargeController {
+	return &ChargeController{commandHandlers, queryHandlers}
+}
+
+func (c ChargeController) ConfigureDepreciation(w http.ResponseWriter, r *http.Request) {
+	var command chargeapp.ConfigureDepreciationCommand
+	json.NewDecoder(r.Body).Decode(&command)
+	ctx := context.Background()
+	defer ctx.Done()
+	if err := c.commandHandlers.ConfigureDepreciationHandler.Handle(ctx, command); err != nil {
+		web.HttpErrorResponse(w, err.Error())
+	}
+}
+
+func (c ChargeController) CreateCharge(w http.ResponseWriter, r *http.Request) {
+	var command chargeapp.CreateChargeCommand
+	json.NewDecoder(r.Body).Decode(&command)
+	ctx := context.Background()
+	defer ctx.Done()
+	chargeID, err := c.commandHandlers.CreateChargeHandler.Handle(ctx, command)
+	if err != nil {
+		web.HttpErrorResponse(w, err.Error())
+		return
+	}
+	web.HttpResponse(w, chargeID)
+}
+
+func (c ChargeController) PayCharge(w http.ResponseWriter, r *http.Request) {
+	var command chargeapp.PayChargeCommand
+	json.NewDecoder(r.Body).Decode(&command)
+	ctx := context.Background()
+	defer ctx.Done()
+	if err := c.commandHandlers.PayChargeHandler.Handle(ctx, command); err != nil {
+		web.HttpErrorResponse(w, err.Error())
+	}
+}
+
+func (c ChargeController) WaiveCharge(w http.ResponseWriter, r *http.Request) {
+	var command chargeapp.WaiveChargeCommand
+	json.NewDecoder(r.Body).Decode(&command)
+	ctx := context.Background()
+	defer ctx.Done()
+	if err := c.commandHandlers.WaiveChargeHandler.Handle(ctx, command); err != nil {
+		web.HttpErrorResponse(w, err.Error())
+	}
+}
+
+func (c ChargeController) GetAllCharges(w http.ResponseWriter, r *http.Request) {
+	ctx := context.Background()
+	defer ctx.Done()
+	path := strings.Split(r.URL.Path, "/")
+	aggregateID := path[len(path)-1]
+	query := chargeapp.NewGetAllCharges(aggregateID)
+	charges, err := c.queryHandlers.GetAllHandler.Handle(ctx, query)
+	if err != nil {
+		web.HttpErrorResponse(w, err.Error())
+		return
+	}
+	web.HttpResponse(w, charges)
+}
+
+func (c ChargeController) GetOpenCharges(w http.ResponseWriter, r *http.Request) {
+	ctx := context.Background()
+	defer ctx.Done()
+	path := strings.Split(r.URL.Path, "/")
+	aggregateID := path[len(path)-1]
+	query := chargeapp.NewGetOpenCharges(aggregateID)
+	charges, err := c.queryHandlers.GetOpenHandler.Handle(ctx, query)
+	if err != nil {
+		web.HttpErrorResponse(w, err.Error())
+		return
+	}
+	web.HttpResponse(w, charges)
+}
+
+// GetPaymentNotice serves /api/charges/get-payment-notice/{schoolId}/{chargeId}
+// and responds with a printable plain text notice if the format query
+// parameter is text.
+func (c ChargeController) GetPaymentNotice(w http.ResponseWriter, r *http.Request) {
+	ctx := context.Background()
+	defer ctx.Done()
+	path := strings.Split(r.URL.Path, "/")
+	aggregateID := path[len(path)-2]
+	chargeID := path[len(path)-1]
+	query := chargeapp.NewGetPaymentNotice(aggregateID, chargeID)
+	notice, err := c.queryHandlers.GetPaymentNoticeHandler.Handle(ctx, query)
+	if err != nil {
+		web.HttpErrorResponse(w, err.Error())
+		return
+	}
+	if r.URL.Query().Get("format") != "text" {
+		web.HttpResponse(w, notice)
+		return
+	}
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	if err := chargeapp.WritePaymentNotice(w, notice); err != nil {
+		web.HttpErrorResponse(w, err.Error())
+	}
+}
@@ -0,0 +1,144 @@
+package classes
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/kammeph/school-book-storage-service/application"
+	"github.com/kammeph/school-book-storage-service/application/classapp"
+	"github.com/kammeph/school-book-storage-service/domain/userdomain"
+	"github.com/kammeph/school-book-storage-service/infrastructure/mongodb"
+	"github.com/kammeph/school-book-storage-service/infrastructure/postgresdb"
+	"github.com/kammeph/school-book-storage-service/infrastructure/rabbitmq"
+	"github.com/kammeph/school-book-storage-service/web"
+)
+
+func PostgresMongoRabbitConfig(postgresDB *sql.DB, mongoClient mongodb.Client, rabbit rabbitmq.AmqpConnection) {
+	publisher, err := rabbitmq.NewRabbitEventPublisher(rabbit, "class")
+	if err != nil {
+		panic(err)
+	}
+	storagePublisher, err := rabbitmq.NewRabbitEventPublisher(rabbit, "storage")
+	if err != nil {
+		panic(err)
+	}
+	pupilPublisher, err := rabbitmq.NewRabbitEventPublisher(rabbit, "pupil")
+	if err != nil {
+		panic(err)
+	}
+	subscriber, err := rabbitmq.NewRabbitEventSubscriber(rabbit)
+	if err != nil {
+		panic(err)
+	}
+	postgresMongoConfig(postgresDB, mongoClient, publisher, storagePublisher, pupilPublisher, subscriber)
+}
+
+func PostgresMongoConfig(postgresDB *sql.DB, mongoClient mongodb.Client, subscriber application.EventSubscriber) {
+	publisher := postgresdb.NewPostgresEventPublisher(postgresDB, "class")
+	storagePublisher := postgresdb.NewPostgresEventPublisher(postgresDB, "storage")
+	pupilPublisher := postgresdb.NewPostgresEventPublisher(postgresDB, "pupil")
+	postgresMongoConfig(postgresDB, mongoClient, publisher, storagePublisher, pupilPublisher, subscriber)
+}
+
+func postgresMongoConfig(
+	postgresDB *sql.DB,
+	mongoClient mongodb.Client,
+	publisher application.EventPublisher,
+	storagePublisher application.EventPublisher,
+	pupilPublisher application.EventPublisher,
+	subscriber application.EventSubscriber,
+) {
+	store := postgresdb.NewPostgresStore("school_classes", postgresDB)
+	storageStore := postgresdb.NewPostgresStore("storages", postgresDB)
+	pupilStore := postgresdb.NewPostgresStore("pupils", postgresDB)
+	reservationStore := postgresdb.NewPostgresStore("reservations", postgresDB)
+	repository := mongodb.NewClassWithBooksRepository(mongoClient, "school_book_storage", "classes")
+	states := mongodb.NewProjectionStateRepository(mongoClient, "school_book_storage", "projection_states")
+
+	eventHandler := application.NewGapDetector("classes", states, classapp.NewClassEventHandler(repository))
+	if err := subscriber.Subscribe("class", eventHandler, application.NewDeadLetterPolicy(3, time.Second)); err != nil {
+		panic(err)
+	}
+
+	commandHandlers := classapp.NewClassCommandHandlers(
+		store,
+		publisher,
+		storageStore,
+		storagePublisher,
+		pupilStore,
+		pupilPublisher,
+		reservationStore)
+	queryHandlers := classapp.NewClassQueryHandlers(repository)
+
+	controller := NewClassController(commandHandlers, queryHandlers)
+	configureEndpoints(controller)
+}
+
+func configureEndpoints(controller *ClassController) {
+	web.Get(
+		"/api/classes/get-all/",
+		web.IsAllowed(
+			controller.GetAllClasses,
+			[]userdomain.Role{userdomain.User, userdomain.Superuser, userdomain.Admin},
+		))
+	web.Get(
+		"/api/classes/get-by-school-year/",
+		web.IsAllowed(
+			controller.GetClassesBySchoolYear,
+			[]userdomain.Role{userdomain.User, userdomain.Superuser, userdomain.Admin},
+		))
+	web.Get(
+		"/api/classes/get-by-id/",
+		web.IsAllowed(
+			controller.GetClassByID,
+			[]userdomain.Role{userdomain.User, userdomain.Superuser, userdomain.Admin},
+		))
+	web.Post(
+		"/api/classes/create",
+		web.IsAllowed(
+			controller.CreateClass,
+			[]userdomain.Role{userdomain.Admin},
+		))
+	web.Post(
+		"/api/classes/increase-pupils",
+		web.IsAllowed(
+			controller.IncreaseNumberOfPupils,
+			[]userdomain.Role{userdomain.Superuser, userdomain.Admin},
+		))
+	web.Post(
+		"/api/classes/decrease-pupils",
+		web.IsAllowed(
+			controller.DecreaseNumberOfPupils,
+			[]userdomain.Role{userdomain.Superuser, userdomain.Admin},
+		))
+	web.Post(
+		"/api/classes/preview-rollover",
+		web.IsAllowed(
+			controller.PreviewRollover,
+			[]userdomain.Role{userdomain.Superuser, userdomain.Admin},
+		))
+	web.Post(
+		"/api/classes/rollover",
+		web.IsAllowed(
+			controller.Rollover,
+			[]userdomain.Role{userdomain.Admin},
+		))
+	web.Post(
+		"/api/classes/lend-books",
+		web.IsAllowed(
+			controller.LendBooks,
+			[]userdomain.Role{userdomain.Superuser, userdomain.Admin},
+		))
+	web.Post(
+		"/api/classes/return-books",
+		web.IsAllowed(
+			controller.ReturnBooks,
+			[]userdomain.Role{userdomain.Superuser, userdomain.Admin},
+		))
+	web.Post(
+		"/api/classes/return-all-books",
+		web.IsAllowed(
+			controller.ReturnAllBooks,
+			[]userdomain.Role{userdomain.Superuser, userdomain.Admin},
+		))
+}
@@ -0,0 +1,156 @@
+package classes
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/kammeph/school-book-storage-service/application/classapp"
+	"github.com/kammeph/school-book-storage-service/web"
+)
+
+type ClassController struct {
+	commandHandlers classapp.ClassCommandHandlers
+	queryHandlers   classapp.ClassQueryHandlers
+}
+
+func NewClassController(commandHandlers classapp.ClassCommandHandlers, queryHandlers classapp.ClassQueryHandlers) *ClassController {
+	return &ClassController{commandHandlers, queryHandlers}
+}
+
+func (c ClassController) CreateClass(w http.ResponseWriter, r *http.Request) {
+	var command classapp.CreateClassCommand
+	json.NewDecoder(r.Body).Decode(&command)
+	ctx := context.Background()
+	defer ctx.Done()
+	classID, err := c.commandHandlers.CreateClassHandler.Handle(ctx, command)
+	if err != nil {
+		web.HttpErrorResponse(w, err.Error())
+		return
+	}
+	web.HttpResponse(w, classID)
+}
+
+func (c ClassController) IncreaseNumberOfPupils(w http.ResponseWriter, r *http.Request) {
+	var command classapp.IncreaseNumberOfPupilsCommand
+	json.NewDecoder(r.Body).Decode(&command)
+	ctx := context.Background()
+	defer ctx.Done()
+	if err := c.commandHandlers.IncreaseNumberOfPupilsHandler.Handle(ctx, command); err != nil {
+		web.HttpErrorResponse(w, err.Error())
+	}
+}
+
+func (c ClassController) DecreaseNumberOfPupils(w http.ResponseWriter, r *http.Request) {
+	var command classapp.DecreaseNumberOfPupilsCommand
+	json.NewDecoder(r.Body).Decode(&command)
+	ctx := context.Background()
+	defer ctx.Done()
+	if err := c.commandHandlers.DecreaseNumberOfPupilsHandler.Handle(ctx, command); err != nil {
+		web.HttpErrorResponse(w, err.Error())
+	}
+}
+
+func (c ClassController) PreviewRollover(w http.ResponseWriter, r *http.Request) {
+	var command classapp.RolloverCommand
+	json.NewDecoder(r.Body).Decode(&command)
+	command.DryRun = true
+	c.rollover(w, command)
+}
+
+func (c ClassController) Rollover(w http.ResponseWriter, r *http.Request) {
+	var command classapp.RolloverCommand
+	json.NewDecoder(r.Body).Decode(&command)
+	c.rollover(w, command)
+}
+
+func (c ClassController) rollover(w http.ResponseWriter, command classapp.RolloverCommand) {
+	ctx := context.Background()
+	defer ctx.Done()
+	report, err := c.commandHandlers.RolloverHandler.Handle(ctx, command)
+	if err != nil {
+		web.HttpErrorResponse(w, err.Error())
+		return
+	}
+	web.HttpResponse(w, report)
+}
+
+func (c ClassController) LendBooks(w http.ResponseWriter, r *http.Request) {
+	var command classapp.LendBooksCommand
+	json.NewDecoder(r.Body).Decode(&command)
+	ctx := context.Background()
+	defer ctx.Done()
+	if err := c.commandHandlers.LendBooksHandler.Handle(ctx, command); err != nil {
+		web.HttpErrorResponse(w, err.Error())
+	}
+}
+
+func (c ClassController) ReturnBooks(w http.ResponseWriter, r *http.Request) {
+	var command classapp.ReturnBooksCommand
+	json.NewDecoder(r.Body).Decode(&command)
+	ctx := context.Background()
+	defer ctx.Done()
+	if err := c.commandHandlers.ReturnBooksHandler.Handle(ctx, command); err != nil {
+		web.HttpErrorResponse(w, err.Error())
+	}
+}
+
+func (c ClassController) ReturnAllBooks(w http.ResponseWriter, r *http.Request) {
+	var command classapp.ReturnAllBooksCommand
+	json.NewDecoder(r.Body).Decode(&command)
+	ctx := context.Background()
+	defer ctx.Done()
+	if err := c.commandHandlers.ReturnAllBooksHandler.Handle(ctx, command); err != nil {
+		web.HttpErrorResponse(w, err.Error())
+	}
+}
+
+func (c ClassController) GetAllClasses(w http.ResponseWriter, r *http.Request) {
+	ctx := context.Background()
+	defer ctx.Done()
+	path := strings.Split(r.URL.Path, "/")
+	aggregateID := path[len(path)-1]
+	query := classapp.NewGetAllClasses(aggregateID)
+	classes, err := c.queryHandlers.GetAllHandler.Handle(ctx, query)
+	if err != nil {
+		web.HttpErrorResponse(w, err.Error())
+		return
+	}
+	web.HttpResponse(w, classes)
+}
+
+func (c ClassController) GetClassesBySchoolYear(w http.ResponseWriter, r *http.Request) {
+	ctx := context.Background()
+	defer ctx.Done()
+	path := strings.Split(r.URL.Path, "/")
+	aggregateID := path[len(path)-2]
+	year, err := strconv.Atoi(path[len(path)-1])
+	if err != nil {
+		web.HttpErrorResponse(w, err.Error())
+		return
+	}
+	query := classapp.NewGetClassesBySchoolYear(aggregateID, year)
+	classes, err := c.queryHandlers.GetBySchoolYearHandler.Handle(ctx, query)
+	if err != nil {
+		web.HttpErrorResponse(w, err.Error())
+		return
+	}
+	web.HttpResponse(w, classes)
+}
+
+func (c ClassController) GetClassByID(w http.ResponseWriter, r *http.Request) {
+	ctx := context.Background()
+	defer ctx.Done()
+	path := strings.Split(r.URL.Path, "/")
+	aggregateID := path[len(path)-2]
+	classID := path[len(path)-1]
+	query := classapp.NewGetClassByID(aggregateID, classID)
+	class, err := c.queryHandlers.GetClassByIDHandler.Handle(ctx, query)
+	if err != nil {
+		web.HttpErrorResponse(w, err.Error())
+		return
+	}
+	web.HttpResponse(w, class)
+}
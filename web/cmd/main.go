@@ -4,24 +4,32 @@ import (
 	"context"
 	"log"
 	"net/http"
+	"time"
 
 	"github.com/kammeph/school-book-storage-service/infrastructure/mongodb"
 	"github.com/kammeph/school-book-storage-service/infrastructure/postgresdb"
 	"github.com/kammeph/school-book-storage-service/infrastructure/rabbitmq"
+	"github.com/kammeph/school-book-storage-service/infrastructure/utils"
 	"github.com/kammeph/school-book-storage-service/web/auth"
+	"github.com/kammeph/school-book-storage-service/web/books"
+	"github.com/kammeph/school-book-storage-service/web/charges"
+	"github.com/kammeph/school-book-storage-service/web/classes"
+	"github.com/kammeph/school-book-storage-service/web/copies"
+	"github.com/kammeph/school-book-storage-service/web/events"
+	"github.com/kammeph/school-book-storage-service/web/loans"
+	"github.com/kammeph/school-book-storage-service/web/orders"
+	"github.com/kammeph/school-book-storage-service/web/planning"
+	"github.com/kammeph/school-book-storage-service/web/pupils"
+	"github.com/kammeph/school-book-storage-service/web/reservations"
 	"github.com/kammeph/school-book-storage-service/web/school"
 	"github.com/kammeph/school-book-storage-service/web/storages"
 	"github.com/kammeph/school-book-storage-service/web/users"
+	"github.com/kammeph/school-book-storage-service/web/webhooks"
 )
 
+var eventBroker = utils.GetenvOrFallback("EVENT_BROKER", "rabbitmq")
+
 func main() {
-	connection := rabbitmq.NewRabbitMQConnection()
-	defer func() {
-		if err := connection.Close(); err != nil {
-			panic(err)
-		}
-		log.Println("Connection to rabbit mq closed.")
-	}()
 	db := postgresdb.NewPostgresDB()
 	defer func() {
 		if err := db.Close(); err != nil {
@@ -36,9 +44,53 @@ func main() {
 		}
 		log.Println("Connection to mongo db closed.")
 	}()
+	if err := mongodb.MigrateMoney(context.TODO(), client, "school_book_storage"); err != nil {
+		panic(err)
+	}
 	auth.PostgresConfig(db)
 	users.PostgresConfig(db)
-	school.PostgresMongoRabbitConfig(db, client, connection)
-	storages.PostgresMongoRabbitConfig(db, client, connection)
+	planning.MongoConfig(client)
+	if eventBroker == "postgres" {
+		listener := postgresdb.NewPostgresListener()
+		defer func() {
+			if err := listener.Close(); err != nil {
+				panic(err)
+			}
+			log.Println("Postgres listener closed.")
+		}()
+		subscriber := postgresdb.NewPostgresEventSubscriber(db, listener, postgresdb.ExchangeTables, time.Minute)
+		school.PostgresMongoConfig(db, client, subscriber)
+		storages.PostgresMongoConfig(db, client, subscriber)
+		books.PostgresMongoConfig(db, client, subscriber)
+		classes.PostgresMongoConfig(db, client, subscriber)
+		loans.PostgresMongoConfig(db, client, subscriber)
+		pupils.PostgresMongoConfig(db, client, subscriber)
+		orders.PostgresMongoConfig(db, client, subscriber)
+		charges.PostgresMongoConfig(db, client, subscriber)
+		copies.PostgresMongoConfig(db, client, subscriber)
+		reservations.PostgresMongoConfig(db, client, subscriber)
+		webhooks.PostgresMongoConfig(db, client, subscriber)
+		events.SubscriberConfig(subscriber)
+	} else {
+		connection := rabbitmq.NewRabbitMQConnection()
+		defer func() {
+			if err := connection.Close(); err != nil {
+				panic(err)
+			}
+			log.Println("Connection to rabbit mq closed.")
+		}()
+		school.PostgresMongoRabbitConfig(db, client, connection)
+		storages.PostgresMongoRabbitConfig(db, client, connection)
+		books.PostgresMongoRabbitConfig(db, client, connection)
+		classes.PostgresMongoRabbitConfig(db, client, connection)
+		loans.PostgresMongoRabbitConfig(db, client, connection)
+		pupils.PostgresMongoRabbitConfig(db, client, connection)
+		orders.PostgresMongoRabbitConfig(db, client, connection)
+		charges.PostgresMongoRabbitConfig(db, client, connection)
+		copies.PostgresMongoRabbitConfig(db, client, connection)
+		reservations.PostgresMongoRabbitConfig(db, client, connection)
+		webhooks.PostgresMongoRabbitConfig(db, client, connection)
+		events.RabbitConfig(connection)
+	}
 	http.ListenAndServe(":9090", nil)
 }
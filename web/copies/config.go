@@ -0,0 +1,142 @@
+package copies
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/kammeph/school-book-storage-service/application"
+	"github.com/kammeph/school-book-storage-service/application/copyapp"
+	"github.com/kammeph/school-book-storage-service/domain/userdomain"
+	"github.com/kammeph/school-book-storage-service/infrastructure/mongodb"
+	"github.com/kammeph/school-book-storage-service/infrastructure/postgresdb"
+	"github.com/kammeph/school-book-storage-service/infrastructure/rabbitmq"
+	"github.com/kammeph/school-book-storage-service/web"
+)
+
+func PostgresMongoRabbitConfig(postgresDB *sql.DB, mongoClient mongodb.Client, rabbit rabbitmq.AmqpConnection) {
+	publisher, err := rabbitmq.NewRabbitEventPublisher(rabbit, "copy")
+	if err != nil {
+		panic(err)
+	}
+	storagePublisher, err := rabbitmq.NewRabbitEventPublisher(rabbit, "storage")
+	if err != nil {
+		panic(err)
+	}
+	classPublisher, err := rabbitmq.NewRabbitEventPublisher(rabbit, "class")
+	if err != nil {
+		panic(err)
+	}
+	subscriber, err := rabbitmq.NewRabbitEventSubscriber(rabbit)
+	if err != nil {
+		panic(err)
+	}
+	postgresMongoConfig(postgresDB, mongoClient, publisher, storagePublisher, classPublisher, subscriber)
+}
+
+func PostgresMongoConfig(postgresDB *sql.DB, mongoClient mongodb.Client, subscriber application.EventSubscriber) {
+	publisher := postgresdb.NewPostgresEventPublisher(postgresDB, "copy")
+	storagePublisher := postgresdb.NewPostgresEventPublisher(postgresDB, "storage")
+	classPublisher := postgresdb.NewPostgresEventPublisher(postgresDB, "class")
+	postgresMongoConfig(postgresDB, mongoClient, publisher, storagePublisher, classPublisher, subscriber)
+}
+
+func postgresMongoConfig(
+	postgresDB *sql.DB,
+	mongoClient mongodb.Client,
+	publisher application.EventPublisher,
+	storagePublisher application.EventPublisher,
+	classPublisher application.EventPublisher,
+	subscriber application.EventSubscriber,
+) {
+	store := postgresdb.NewPostgresStore("copies", postgresDB)
+	storageStore := postgresdb.NewPostgresStore("storages", postgresDB)
+	classStore := postgresdb.NewPostgresStore("school_classes", postgresDB)
+	bookStore := postgresdb.NewPostgresStore("books", postgresDB)
+	pupilStore := postgresdb.NewPostgresStore("pupils", postgresDB)
+	reservationStore := postgresdb.NewPostgresStore("reservations", postgresDB)
+	repository := mongodb.NewCopyRepository(mongoClient, "school_book_storage", "copies")
+	states := mongodb.NewProjectionStateRepository(mongoClient, "school_book_storage", "projection_states")
+
+	eventHandler := application.NewGapDetector("copies", states, copyapp.NewCopyEventHandler(repository))
+	if err := subscriber.Subscribe("copy", eventHandler, application.NewDeadLetterPolicy(3, time.Second)); err != nil {
+		panic(err)
+	}
+
+	commandHandlers := copyapp.NewCopyCommandHandlers(
+		store,
+		publisher,
+		storageStore,
+		storagePublisher,
+		classStore,
+		classPublisher,
+		bookStore,
+		pupilStore,
+		reservationStore)
+	queryHandlers := copyapp.NewCopyQueryHandlers(repository)
+
+	controller := NewCopyController(commandHandlers, queryHandlers)
+	configureEndpoints(controller)
+}
+
+func configureEndpoints(controller *CopyController) {
+	web.Get(
+		"/api/copies/get-all/",
+		web.IsAllowed(
+			controller.GetAllCopies,
+			[]userdomain.Role{userdomain.User, userdomain.Superuser, userdomain.Admin},
+		))
+	web.Get(
+		"/api/copies/get-by-location/",
+		web.IsAllowed(
+			controller.GetCopiesByLocation,
+			[]userdomain.Role{userdomain.User, userdomain.Superuser, userdomain.Admin},
+		))
+	web.Get(
+		"/api/copies/get-by-book/",
+		web.IsAllowed(
+			controller.GetCopiesByBookID,
+			[]userdomain.Role{userdomain.User, userdomain.Superuser, userdomain.Admin},
+		))
+	web.Get(
+		"/api/copies/get-by-inventory-number/",
+		web.IsAllowed(
+			controller.GetCopyByInventoryNumber,
+			[]userdomain.Role{userdomain.User, userdomain.Superuser, userdomain.Admin},
+		))
+	web.Get(
+		"/api/copies/labels/",
+		web.IsAllowed(
+			controller.GetCopyLabels,
+			[]userdomain.Role{userdomain.User, userdomain.Superuser, userdomain.Admin},
+		))
+	web.Post(
+		"/api/copies/configure-numbering",
+		web.IsAllowed(
+			controller.ConfigureNumbering,
+			[]userdomain.Role{userdomain.Admin},
+		))
+	web.Post(
+		"/api/copies/register",
+		web.IsAllowed(
+			controller.RegisterCopies,
+			[]userdomain.Role{userdomain.Superuser, userdomain.Admin},
+		))
+	web.Post(
+		"/api/copies/move",
+		web.IsAllowed(
+			controller.MoveCopy,
+			[]userdomain.Role{userdomain.User, userdomain.Superuser, userdomain.Admin},
+		))
+	web.Post(
+		"/api/copies/grade",
+		web.IsAllowed(
+			controller.GradeCopy,
+			[]userdomain.Role{userdomain.User, userdomain.Superuser, userdomain.Admin},
+		))
+	web.Post(
+		"/api/copies/write-off",
+		web.IsAllowed(
+			controller.WriteOffCopy,
+			[]userdomain.Role{userdomain.Superuser, userdomain.Admin},
+		))
+}
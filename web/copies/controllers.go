@@ -0,0 +1,151 @@
+package copies
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/kammeph/school-book-storage-service/application/copyapp"
+	"github.com/kammeph/school-book-storage-service/domain/copydomain"
+	"github.com/kammeph/school-book-storage-service/web"
+)
+
+type CopyController struct {
+	commandHandlers copyapp.CopyCommandHandlers
+	queryHandlers   copyapp.CopyQueryHandlers
+}
+
+func NewCopyController(commandHandlers copyapp.CopyCommandHandlers, queryHandlers copyapp.CopyQueryHandlers) *CopyController {
+	return &CopyController{commandHandlers, queryHandlers}
+}
+
+func (c CopyController) ConfigureNumbering(w http.ResponseWriter, r *http.Request) {
+	var command copyapp.ConfigureNumberingCommand
+	json.NewDecoder(r.Body).Decode(&command)
+	ctx := context.Background()
+	defer ctx.Done()
+	if err := c.commandHandlers.ConfigureNumberingHandler.Handle(ctx, command); err != nil {
+		web.HttpErrorResponse(w, err.Error())
+	}
+}
+
+func (c CopyController) RegisterCopies(w http.ResponseWriter, r *http.Request) {
+	var command copyapp.RegisterCopiesCommand
+	json.NewDecoder(r.Body).Decode(&command)
+	ctx := context.Background()
+	defer ctx.Done()
+	inventoryNumbers, err := c.commandHandlers.RegisterCopiesHandler.Handle(ctx, command)
+	if err != nil {
+		web.HttpErrorResponse(w, err.Error())
+		return
+	}
+	web.HttpResponse(w, inventoryNumbers)
+}
+
+func (c CopyController) MoveCopy(w http.ResponseWriter, r *http.Request) {
+	var command copyapp.MoveCopyCommand
+	json.NewDecoder(r.Body).Decode(&command)
+	ctx := context.Background()
+	defer ctx.Done()
+	if err := c.commandHandlers.MoveCopyHandler.Handle(ctx, command); err != nil {
+		web.HttpErrorResponse(w, err.Error())
+	}
+}
+
+func (c CopyController) GradeCopy(w http.ResponseWriter, r *http.Request) {
+	var command copyapp.GradeCopyCommand
+	json.NewDecoder(r.Body).Decode(&command)
+	ctx := context.Background()
+	defer ctx.Done()
+	if err := c.commandHandlers.GradeCopyHandler.Handle(ctx, command); err != nil {
+		web.HttpErrorResponse(w, err.Error())
+	}
+}
+
+func (c CopyController) WriteOffCopy(w http.ResponseWriter, r *http.Request) {
+	var command copyapp.WriteOffCopyCommand
+	json.NewDecoder(r.Body).Decode(&command)
+	ctx := context.Background()
+	defer ctx.Done()
+	if err := c.commandHandlers.WriteOffCopyHandler.Handle(ctx, command); err != nil {
+		web.HttpErrorResponse(w, err.Error())
+	}
+}
+
+func (c CopyController) GetAllCopies(w http.ResponseWriter, r *http.Request) {
+	ctx := context.Background()
+	defer ctx.Done()
+	path := strings.Split(r.URL.Path, "/")
+	aggregateID := path[len(path)-1]
+	query := copyapp.NewGetAllCopies(aggregateID)
+	copies, err := c.queryHandlers.GetAllHandler.Handle(ctx, query)
+	if err != nil {
+		web.HttpErrorResponse(w, err.Error())
+		return
+	}
+	web.HttpResponse(w, copies)
+}
+
+// GetCopiesByLocation serves /api/copies/get-by-location/{schoolId}/{kind}/{id}
+// where kind is storage, class or pupil.
+func (c CopyController) GetCopiesByLocation(w http.ResponseWriter, r *http.Request) {
+	ctx := context.Background()
+	defer ctx.Done()
+	path := strings.Split(r.URL.Path, "/")
+	aggregateID := path[len(path)-3]
+	location := copydomain.Location{Kind: copydomain.LocationKind(path[len(path)-2]), ID: path[len(path)-1]}
+	query := copyapp.NewGetCopiesByLocation(aggregateID, location)
+	copies, err := c.queryHandlers.GetByLocationHandler.Handle(ctx, query)
+	if err != nil {
+		web.HttpErrorResponse(w, err.Error())
+		return
+	}
+	web.HttpResponse(w, copies)
+}
+
+func (c CopyController) GetCopiesByBookID(w http.ResponseWriter, r *http.Request) {
+	ctx := context.Background()
+	defer ctx.Done()
+	path := strings.Split(r.URL.Path, "/")
+	aggregateID := path[len(path)-2]
+	bookID := path[len(path)-1]
+	query := copyapp.NewGetCopiesByBookID(aggregateID, bookID)
+	copies, err := c.queryHandlers.GetByBookIDHandler.Handle(ctx, query)
+	if err != nil {
+		web.HttpErrorResponse(w, err.Error())
+		return
+	}
+	web.HttpResponse(w, copies)
+}
+
+func (c CopyController) GetCopyByInventoryNumber(w http.ResponseWriter, r *http.Request) {
+	ctx := context.Background()
+	defer ctx.Done()
+	path := strings.Split(r.URL.Path, "/")
+	aggregateID := path[len(path)-2]
+	inventoryNumber := path[len(path)-1]
+	query := copyapp.NewGetCopyByInventoryNumber(aggregateID, inventoryNumber)
+	bookCopy, err := c.queryHandlers.GetByInventoryNumberHandler.Handle(ctx, query)
+	if err != nil {
+		web.HttpErrorResponse(w, err.Error())
+		return
+	}
+	web.HttpResponse(w, bookCopy)
+}
+
+// GetCopyLabels serves /api/copies/labels/{schoolId}?ids={inventoryNumbers}
+// and responds with printable QR code labels of the inventory numbers.
+func (c CopyController) GetCopyLabels(w http.ResponseWriter, r *http.Request) {
+	ctx := context.Background()
+	defer ctx.Done()
+	path := strings.Split(r.URL.Path, "/")
+	aggregateID := path[len(path)-1]
+	query := copyapp.NewGetCopyLabels(aggregateID, web.QueryList(r, "ids"))
+	labels, err := c.queryHandlers.GetLabelsHandler.Handle(ctx, query)
+	if err != nil {
+		web.HttpErrorResponse(w, err.Error())
+		return
+	}
+	web.LabelResponse(w, r, "copy-labels", labels)
+}
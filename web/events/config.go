@@ -0,0 +1,60 @@
+package events
+
+import (
+	"time"
+
+	"github.com/kammeph/school-book-storage-service/application"
+	"github.com/kammeph/school-book-storage-service/application/streamapp"
+	"github.com/kammeph/school-book-storage-service/domain/userdomain"
+	"github.com/kammeph/school-book-storage-service/infrastructure/rabbitmq"
+	"github.com/kammeph/school-book-storage-service/web"
+)
+
+// Exchanges lists the exchanges whose events are streamed to all users.
+var Exchanges = []string{"storage", "school", "book", "class", "order", "copy", "reservation"}
+
+// PersonalExchanges lists the exchanges whose events carry personal data of
+// pupils or their fees. They are streamed to superusers and admins only.
+var PersonalExchanges = []string{"pupil", "loan", "charge"}
+
+func RabbitConfig(rabbit rabbitmq.AmqpConnection) {
+	subscriber, err := rabbitmq.NewRabbitEventSubscriber(rabbit)
+	if err != nil {
+		panic(err)
+	}
+	SubscriberConfig(subscriber)
+}
+
+func SubscriberConfig(subscriber application.EventSubscriber) {
+	stream := subscribeStream(subscriber, Exchanges)
+	personalStream := subscribeStream(subscriber, PersonalExchanges)
+
+	controller := NewEventController(stream, 30*time.Second)
+	personalController := NewEventController(personalStream, 30*time.Second)
+	configureEndpoints(controller, personalController)
+}
+
+func subscribeStream(subscriber application.EventSubscriber, exchanges []string) *streamapp.EventStream {
+	stream := streamapp.NewEventStream(1000, 100)
+	for _, exchange := range exchanges {
+		if err := subscriber.Subscribe(exchange, stream, application.NewSkipPolicy()); err != nil {
+			panic(err)
+		}
+	}
+	return stream
+}
+
+func configureEndpoints(controller *EventController, personalController *EventController) {
+	web.Get(
+		"/api/events/stream",
+		web.IsAllowedWithClaims(
+			controller.StreamEvents,
+			[]userdomain.Role{userdomain.User, userdomain.Superuser, userdomain.Admin},
+		))
+	web.Get(
+		"/api/events/personal/stream",
+		web.IsAllowedWithClaims(
+			personalController.StreamEvents,
+			[]userdomain.Role{userdomain.Superuser, userdomain.Admin},
+		))
+}
@@ -0,0 +1,77 @@
+package events
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/kammeph/school-book-storage-service/application/streamapp"
+	"github.com/kammeph/school-book-storage-service/web"
+)
+
+type EventController struct {
+	stream    *streamapp.EventStream
+	heartbeat time.Duration
+}
+
+func NewEventController(stream *streamapp.EventStream, heartbeat time.Duration) *EventController {
+	return &EventController{stream, heartbeat}
+}
+
+func (c EventController) StreamEvents(w http.ResponseWriter, r *http.Request, claims web.AccessClaims) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		web.HttpErrorResponseWithStatusCode(w, "streaming is not supported", http.StatusInternalServerError)
+		return
+	}
+	lastEventID := r.Header.Get("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = r.URL.Query().Get("lastEventId")
+	}
+	client, missed, resumed := c.stream.Connect(claims.SchoolID, lastEventID)
+	defer c.stream.Disconnect(client)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	if !resumed {
+		fmt.Fprint(w, "event: reset\ndata: {}\n\n")
+	}
+	for _, event := range missed {
+		if err := writeEvent(w, event); err != nil {
+			return
+		}
+	}
+	flusher.Flush()
+
+	ticker := time.NewTicker(c.heartbeat)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-ticker.C:
+			fmt.Fprint(w, ": heartbeat\n\n")
+			flusher.Flush()
+		case event, ok := <-client.Events:
+			if !ok {
+				return
+			}
+			if err := writeEvent(w, event); err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
+
+func writeEvent(w http.ResponseWriter, event streamapp.StreamEvent) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "id: %s\nevent: %s\ndata: %s\n\n", event.ID, event.Type, data)
+	return err
+}
@@ -0,0 +1,48 @@
+package web
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/kammeph/school-book-storage-service/application/labelapp"
+)
+
+// QueryList splits a comma separated query parameter.
+func QueryList(r *http.Request, key string) []string {
+	values := []string{}
+	for _, value := range strings.Split(r.URL.Query().Get(key), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// LabelResponse renders the labels on the sheet format given by the sheet query
+// parameter, as SVG if the output parameter is svg and as PDF otherwise.
+func LabelResponse(w http.ResponseWriter, r *http.Request, name string, labels []labelapp.Label) {
+	format, err := labelapp.GetSheetFormat(r.URL.Query().Get("sheet"))
+	if err != nil {
+		HttpErrorResponse(w, err.Error())
+		return
+	}
+	output := r.URL.Query().Get("output")
+	if output == "" {
+		output = labelapp.PDF
+	}
+	contentType, err := labelapp.ContentType(output)
+	if err != nil {
+		HttpErrorResponse(w, err.Error())
+		return
+	}
+	var buffer bytes.Buffer
+	if err := labelapp.WriteLabels(&buffer, output, format, labels); err != nil {
+		HttpErrorResponse(w, err.Error())
+		return
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s.%s\"", name, output))
+	w.Write(buffer.Bytes())
+}
@@ -0,0 +1,102 @@
+package loans
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/kammeph/school-book-storage-service/application"
+	"github.com/kammeph/school-book-storage-service/application/loanapp"
+	"github.com/kammeph/school-book-storage-service/domain/userdomain"
+	"github.com/kammeph/school-book-storage-service/infrastructure/mongodb"
+	"github.com/kammeph/school-book-storage-service/infrastructure/postgresdb"
+	"github.com/kammeph/school-book-storage-service/infrastructure/rabbitmq"
+	"github.com/kammeph/school-book-storage-service/web"
+)
+
+func PostgresMongoRabbitConfig(postgresDB *sql.DB, mongoClient mongodb.Client, rabbit rabbitmq.AmqpConnection) {
+	publisher, err := rabbitmq.NewRabbitEventPublisher(rabbit, "loan")
+	if err != nil {
+		panic(err)
+	}
+	copyPublisher, err := rabbitmq.NewRabbitEventPublisher(rabbit, "copy")
+	if err != nil {
+		panic(err)
+	}
+	subscriber, err := rabbitmq.NewRabbitEventSubscriber(rabbit)
+	if err != nil {
+		panic(err)
+	}
+	postgresMongoConfig(postgresDB, mongoClient, publisher, copyPublisher, subscriber)
+}
+
+func PostgresMongoConfig(postgresDB *sql.DB, mongoClient mongodb.Client, subscriber application.EventSubscriber) {
+	publisher := postgresdb.NewPostgresEventPublisher(postgresDB, "loan")
+	copyPublisher := postgresdb.NewPostgresEventPublisher(postgresDB, "copy")
+	postgresMongoConfig(postgresDB, mongoClient, publisher, copyPublisher, subscriber)
+}
+
+func postgresMongoConfig(
+	postgresDB *sql.DB,
+	mongoClient mongodb.Client,
+	publisher application.EventPublisher,
+	copyPublisher application.EventPublisher,
+	subscriber application.EventSubscriber,
+) {
+	store := postgresdb.NewPostgresStore("loans", postgresDB)
+	classStore := postgresdb.NewPostgresStore("school_classes", postgresDB)
+	bookStore := postgresdb.NewPostgresStore("books", postgresDB)
+	pupilStore := postgresdb.NewPostgresStore("pupils", postgresDB)
+	copyStore := postgresdb.NewPostgresStore("copies", postgresDB)
+	repository := mongodb.NewLoanRepository(mongoClient, "school_book_storage", "loans")
+	states := mongodb.NewProjectionStateRepository(mongoClient, "school_book_storage", "projection_states")
+
+	eventHandler := application.NewGapDetector("loans", states, loanapp.NewLoanEventHandler(repository))
+	if err := subscriber.Subscribe("loan", eventHandler, application.NewDeadLetterPolicy(3, time.Second)); err != nil {
+		panic(err)
+	}
+
+	commandHandlers := loanapp.NewLoanCommandHandlers(store, publisher, classStore, bookStore, pupilStore, copyStore, copyPublisher)
+	queryHandlers := loanapp.NewLoanQueryHandlers(repository)
+
+	controller := NewLoanController(commandHandlers, queryHandlers)
+	configureEndpoints(controller)
+}
+
+func configureEndpoints(controller *LoanController) {
+	web.Get(
+		"/api/loans/get-all/",
+		web.IsAllowed(
+			controller.GetAllLoans,
+			[]userdomain.Role{userdomain.User, userdomain.Superuser, userdomain.Admin},
+		))
+	web.Get(
+		"/api/loans/get-by-pupil/",
+		web.IsAllowed(
+			controller.GetLoansByPupil,
+			[]userdomain.Role{userdomain.User, userdomain.Superuser, userdomain.Admin},
+		))
+	web.Get(
+		"/api/loans/get-overdue/",
+		web.IsAllowed(
+			controller.GetOverdueLoans,
+			[]userdomain.Role{userdomain.User, userdomain.Superuser, userdomain.Admin},
+		))
+	web.Post(
+		"/api/loans/issue",
+		web.IsAllowed(
+			controller.IssueLoan,
+			[]userdomain.Role{userdomain.Superuser, userdomain.Admin},
+		))
+	web.Post(
+		"/api/loans/extend",
+		web.IsAllowed(
+			controller.ExtendLoan,
+			[]userdomain.Role{userdomain.Superuser, userdomain.Admin},
+		))
+	web.Post(
+		"/api/loans/return",
+		web.IsAllowed(
+			controller.ReturnLoan,
+			[]userdomain.Role{userdomain.Superuser, userdomain.Admin},
+		))
+}
@@ -0,0 +1,99 @@
+package loans
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/kammeph/school-book-storage-service/application/loanapp"
+	"github.com/kammeph/school-book-storage-service/web"
+)
+
+type LoanController struct {
+	commandHandlers loanapp.LoanCommandHandlers
+	queryHandlers   loanapp.LoanQueryHandlers
+}
+
+func NewLoanController(commandHandlers loanapp.LoanCommandHandlers, queryHandlers loanapp.LoanQueryHandlers) *LoanController {
+	return &LoanController{commandHandlers, queryHandlers}
+}
+
+func (c LoanController) IssueLoan(w http.ResponseWriter, r *http.Request) {
+	var command loanapp.IssueLoanCommand
+	json.NewDecoder(r.Body).Decode(&command)
+	ctx := context.Background()
+	defer ctx.Done()
+	loanID, err := c.commandHandlers.IssueLoanHandler.Handle(ctx, command)
+	if err != nil {
+		web.HttpErrorResponse(w, err.Error())
+		return
+	}
+	web.HttpResponse(w, loanID)
+}
+
+func (c LoanController) ExtendLoan(w http.ResponseWriter, r *http.Request) {
+	var command loanapp.ExtendLoanCommand
+	json.NewDecoder(r.Body).Decode(&command)
+	ctx := context.Background()
+	defer ctx.Done()
+	if err := c.commandHandlers.ExtendLoanHandler.Handle(ctx, command); err != nil {
+		web.HttpErrorResponse(w, err.Error())
+	}
+}
+
+func (c LoanController) ReturnLoan(w http.ResponseWriter, r *http.Request) {
+	var command loanapp.ReturnLoanCommand
+	json.NewDecoder(r.Body).Decode(&command)
+	ctx := context.Background()
+	defer ctx.Done()
+	if err := c.commandHandlers.ReturnLoanHandler.Handle(ctx, command); err != nil {
+		web.HttpErrorResponse(w, err.Error())
+	}
+}
+
+func (c LoanController) GetAllLoans(w http.ResponseWriter, r *http.Request) {
+	ctx := context.Background()
+	defer ctx.Done()
+	path := strings.Split(r.URL.Path, "/")
+	aggregateID := path[len(path)-1]
+	query := loanapp.NewGetAllLoans(aggregateID)
+	loans, err := c.queryHandlers.GetAllHandler.Handle(ctx, query)
+	if err != nil {
+		web.HttpErrorResponse(w, err.Error())
+		return
+	}
+	web.HttpResponse(w, loans)
+}
+
+func (c LoanController) GetLoansByPupil(w http.ResponseWriter, r *http.Request) {
+	ctx := context.Background()
+	defer ctx.Done()
+	path := strings.Split(r.URL.Path, "/")
+	aggregateID := path[len(path)-2]
+	pupilID := path[len(path)-1]
+	query := loanapp.NewGetLoansByPupil(aggregateID, pupilID)
+	loans, err := c.queryHandlers.GetByPupilHandler.Handle(ctx, query)
+	if err != nil {
+		web.HttpErrorResponse(w, err.Error())
+		return
+	}
+	web.HttpResponse(w, loans)
+}
+
+// GetOverdueLoans serves /api/loans/get-overdue/{schoolId} and narrows the
+// result down to a class if the classId query parameter is set.
+func (c LoanController) GetOverdueLoans(w http.ResponseWriter, r *http.Request) {
+	ctx := context.Background()
+	defer ctx.Done()
+	path := strings.Split(r.URL.Path, "/")
+	aggregateID := path[len(path)-1]
+	query := loanapp.NewGetOverdueLoans(aggregateID, r.URL.Query().Get("classId"), time.Now())
+	loans, err := c.queryHandlers.GetOverdueHandler.Handle(ctx, query)
+	if err != nil {
+		web.HttpErrorResponse(w, err.Error())
+		return
+	}
+	web.HttpResponse(w, loans)
+}
@@ -0,0 +1,106 @@
+package orders
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/kammeph/school-book-storage-service/application"
+	"github.com/kammeph/school-book-storage-service/application/orderapp"
+	"github.com/kammeph/school-book-storage-service/domain/userdomain"
+	"github.com/kammeph/school-book-storage-service/infrastructure/mongodb"
+	"github.com/kammeph/school-book-storage-service/infrastructure/postgresdb"
+	"github.com/kammeph/school-book-storage-service/infrastructure/rabbitmq"
+	"github.com/kammeph/school-book-storage-service/web"
+)
+
+func PostgresMongoRabbitConfig(postgresDB *sql.DB, mongoClient mongodb.Client, rabbit rabbitmq.AmqpConnection) {
+	publisher, err := rabbitmq.NewRabbitEventPublisher(rabbit, "order")
+	if err != nil {
+		panic(err)
+	}
+	storagePublisher, err := rabbitmq.NewRabbitEventPublisher(rabbit, "storage")
+	if err != nil {
+		panic(err)
+	}
+	subscriber, err := rabbitmq.NewRabbitEventSubscriber(rabbit)
+	if err != nil {
+		panic(err)
+	}
+	postgresMongoConfig(postgresDB, mongoClient, publisher, storagePublisher, subscriber)
+}
+
+func PostgresMongoConfig(postgresDB *sql.DB, mongoClient mongodb.Client, subscriber application.EventSubscriber) {
+	publisher := postgresdb.NewPostgresEventPublisher(postgresDB, "order")
+	storagePublisher := postgresdb.NewPostgresEventPublisher(postgresDB, "storage")
+	postgresMongoConfig(postgresDB, mongoClient, publisher, storagePublisher, subscriber)
+}
+
+func postgresMongoConfig(
+	postgresDB *sql.DB,
+	mongoClient mongodb.Client,
+	publisher application.EventPublisher,
+	storagePublisher application.EventPublisher,
+	subscriber application.EventSubscriber,
+) {
+	store := postgresdb.NewPostgresStore("purchase_orders", postgresDB)
+	bookStore := postgresdb.NewPostgresStore("books", postgresDB)
+	storageStore := postgresdb.NewPostgresStore("storages", postgresDB)
+	repository := mongodb.NewPurchaseOrderRepository(mongoClient, "school_book_storage", "purchase_orders")
+	states := mongodb.NewProjectionStateRepository(mongoClient, "school_book_storage", "projection_states")
+
+	eventHandler := application.NewGapDetector("purchase_orders", states, orderapp.NewOrderEventHandler(repository))
+	if err := subscriber.Subscribe("order", eventHandler, application.NewDeadLetterPolicy(3, time.Second)); err != nil {
+		panic(err)
+	}
+
+	commandHandlers := orderapp.NewOrderCommandHandlers(store, publisher, bookStore, storageStore, storagePublisher)
+	queryHandlers := orderapp.NewOrderQueryHandlers(repository)
+
+	controller := NewOrderController(commandHandlers, queryHandlers)
+	configureEndpoints(controller)
+}
+
+func configureEndpoints(controller *OrderController) {
+	web.Get(
+		"/api/orders/get-all/",
+		web.IsAllowed(
+			controller.GetAllOrders,
+			[]userdomain.Role{userdomain.User, userdomain.Superuser, userdomain.Admin},
+		))
+	web.Get(
+		"/api/orders/get-open/",
+		web.IsAllowed(
+			controller.GetOpenOrders,
+			[]userdomain.Role{userdomain.User, userdomain.Superuser, userdomain.Admin},
+		))
+	web.Get(
+		"/api/orders/get-by-id/",
+		web.IsAllowed(
+			controller.GetOrderByID,
+			[]userdomain.Role{userdomain.User, userdomain.Superuser, userdomain.Admin},
+		))
+	web.Post(
+		"/api/orders/create",
+		web.IsAllowed(
+			controller.CreateOrder,
+			[]userdomain.Role{userdomain.Admin},
+		))
+	web.Post(
+		"/api/orders/send",
+		web.IsAllowed(
+			controller.SendOrder,
+			[]userdomain.Role{userdomain.Admin},
+		))
+	web.Post(
+		"/api/orders/receive-goods",
+		web.IsAllowed(
+			controller.ReceiveGoods,
+			[]userdomain.Role{userdomain.Superuser, userdomain.Admin},
+		))
+	web.Post(
+		"/api/orders/cancel",
+		web.IsAllowed(
+			controller.CancelOrder,
+			[]userdomain.Role{userdomain.Admin},
+		))
+}
@@ -0,0 +1,106 @@
+package orders
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/kammeph/school-book-storage-service/application/orderapp"
+	"github.com/kammeph/school-book-storage-service/web"
+)
+
+type OrderController struct {
+	commandHandlers orderapp.OrderCommandHandlers
+	queryHandlers   orderapp.OrderQueryHandlers
+}
+
+func NewOrderController(commandHandlers orderapp.OrderCommandHandlers, queryHandlers orderapp.OrderQueryHandlers) *OrderController {
+	return &OrderController{commandHandlers, queryHandlers}
+}
+
+func (c OrderController) CreateOrder(w http.ResponseWriter, r *http.Request) {
+	var command orderapp.CreateOrderCommand
+	json.NewDecoder(r.Body).Decode(&command)
+	ctx := context.Background()
+	defer ctx.Done()
+	orderID, err := c.commandHandlers.CreateOrderHandler.Handle(ctx, command)
+	if err != nil {
+		web.HttpErrorResponse(w, err.Error())
+		return
+	}
+	web.HttpResponse(w, orderID)
+}
+
+func (c OrderController) SendOrder(w http.ResponseWriter, r *http.Request) {
+	var command orderapp.SendOrderCommand
+	json.NewDecoder(r.Body).Decode(&command)
+	ctx := context.Background()
+	defer ctx.Done()
+	if err := c.commandHandlers.SendOrderHandler.Handle(ctx, command); err != nil {
+		web.HttpErrorResponse(w, err.Error())
+	}
+}
+
+func (c OrderController) ReceiveGoods(w http.ResponseWriter, r *http.Request) {
+	var command orderapp.ReceiveGoodsCommand
+	json.NewDecoder(r.Body).Decode(&command)
+	ctx := context.Background()
+	defer ctx.Done()
+	if err := c.commandHandlers.ReceiveGoodsHandler.Handle(ctx, command); err != nil {
+		web.HttpErrorResponse(w, err.Error())
+	}
+}
+
+func (c OrderController) CancelOrder(w http.ResponseWriter, r *http.Request) {
+	var command orderapp.CancelOrderCommand
+	json.NewDecoder(r.Body).Decode(&command)
+	ctx := context.Background()
+	defer ctx.Done()
+	if err := c.commandHandlers.CancelOrderHandler.Handle(ctx, command); err != nil {
+		web.HttpErrorResponse(w, err.Error())
+	}
+}
+
+func (c OrderController) GetAllOrders(w http.ResponseWriter, r *http.Request) {
+	ctx := context.Background()
+	defer ctx.Done()
+	path := strings.Split(r.URL.Path, "/")
+	aggregateID := path[len(path)-1]
+	query := orderapp.NewGetAllOrders(aggregateID)
+	orders, err := c.queryHandlers.GetAllHandler.Handle(ctx, query)
+	if err != nil {
+		web.HttpErrorResponse(w, err.Error())
+		return
+	}
+	web.HttpResponse(w, orders)
+}
+
+func (c OrderController) GetOpenOrders(w http.ResponseWriter, r *http.Request) {
+	ctx := context.Background()
+	defer ctx.Done()
+	path := strings.Split(r.URL.Path, "/")
+	aggregateID := path[len(path)-1]
+	query := orderapp.NewGetOpenOrders(aggregateID)
+	orders, err := c.queryHandlers.GetOpenHandler.Handle(ctx, query)
+	if err != nil {
+		web.HttpErrorResponse(w, err.Error())
+		return
+	}
+	web.HttpResponse(w, orders)
+}
+
+func (c OrderController) GetOrderByID(w http.ResponseWriter, r *http.Request) {
+	ctx := context.Background()
+	defer ctx.Done()
+	path := strings.Split(r.URL.Path, "/")
+	aggregateID := path[len(path)-2]
+	orderID := path[len(path)-1]
+	query := orderapp.NewGetOrderByID(aggregateID, orderID)
+	order, err := c.queryHandlers.GetOrderByIDHandler.Handle(ctx, query)
+	if err != nil {
+		web.HttpErrorResponse(w, err.Error())
+		return
+	}
+	web.HttpResponse(w, order)
+}
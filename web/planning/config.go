@@ -0,0 +1,28 @@
+package planning
+
+import (
+	"github.com/kammeph/school-book-storage-service/application/planningapp"
+	"github.com/kammeph/school-book-storage-service/domain/userdomain"
+	"github.com/kammeph/school-book-storage-service/infrastructure/mongodb"
+	"github.com/kammeph/school-book-storage-service/web"
+)
+
+func MongoConfig(mongoClient mongodb.Client) {
+	books := mongodb.NewBookRepository(mongoClient, "school_book_storage", "books")
+	classes := mongodb.NewClassWithBooksRepository(mongoClient, "school_book_storage", "classes")
+	storages := mongodb.NewStorageWithBookRepository(mongoClient, "school_book_storage", "storages")
+
+	queryHandlers := planningapp.NewPlanningQueryHandlers(books, classes, storages)
+
+	controller := NewPlanningController(queryHandlers)
+	configureEndpoints(controller)
+}
+
+func configureEndpoints(controller *PlanningController) {
+	web.Get(
+		"/api/planning/get-book-demand/",
+		web.IsAllowed(
+			controller.GetBookDemand,
+			[]userdomain.Role{userdomain.User, userdomain.Superuser, userdomain.Admin},
+		))
+}
@@ -0,0 +1,49 @@
+package planning
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/kammeph/school-book-storage-service/application/planningapp"
+	"github.com/kammeph/school-book-storage-service/web"
+)
+
+type PlanningController struct {
+	queryHandlers planningapp.PlanningQueryHandlers
+}
+
+func NewPlanningController(queryHandlers planningapp.PlanningQueryHandlers) *PlanningController {
+	return &PlanningController{queryHandlers}
+}
+
+// GetBookDemand responds with CSV instead of JSON if the format query
+// parameter is csv.
+func (c PlanningController) GetBookDemand(w http.ResponseWriter, r *http.Request) {
+	ctx := context.Background()
+	defer ctx.Done()
+	path := strings.Split(r.URL.Path, "/")
+	aggregateID := path[len(path)-2]
+	year, err := strconv.Atoi(path[len(path)-1])
+	if err != nil {
+		web.HttpErrorResponse(w, err.Error())
+		return
+	}
+	query := planningapp.NewGetBookDemand(aggregateID, year)
+	demands, err := c.queryHandlers.GetBookDemandHandler.Handle(ctx, query)
+	if err != nil {
+		web.HttpErrorResponse(w, err.Error())
+		return
+	}
+	if r.URL.Query().Get("format") != "csv" {
+		web.HttpResponse(w, demands)
+		return
+	}
+	w.Header().Set("Content-Type", "text/csv")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"book-demand-%d.csv\"", year))
+	if err := planningapp.WriteBookDemandCSV(w, demands); err != nil {
+		web.HttpErrorResponse(w, err.Error())
+	}
+}
@@ -0,0 +1,99 @@
+package pupils
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/kammeph/school-book-storage-service/application"
+	"github.com/kammeph/school-book-storage-service/application/pupilapp"
+	"github.com/kammeph/school-book-storage-service/domain/userdomain"
+	"github.com/kammeph/school-book-storage-service/infrastructure/mongodb"
+	"github.com/kammeph/school-book-storage-service/infrastructure/postgresdb"
+	"github.com/kammeph/school-book-storage-service/infrastructure/rabbitmq"
+	"github.com/kammeph/school-book-storage-service/web"
+)
+
+func PostgresMongoRabbitConfig(postgresDB *sql.DB, mongoClient mongodb.Client, rabbit rabbitmq.AmqpConnection) {
+	publisher, err := rabbitmq.NewRabbitEventPublisher(rabbit, "pupil")
+	if err != nil {
+		panic(err)
+	}
+	classPublisher, err := rabbitmq.NewRabbitEventPublisher(rabbit, "class")
+	if err != nil {
+		panic(err)
+	}
+	subscriber, err := rabbitmq.NewRabbitEventSubscriber(rabbit)
+	if err != nil {
+		panic(err)
+	}
+	postgresMongoConfig(postgresDB, mongoClient, publisher, classPublisher, subscriber)
+}
+
+func PostgresMongoConfig(postgresDB *sql.DB, mongoClient mongodb.Client, subscriber application.EventSubscriber) {
+	publisher := postgresdb.NewPostgresEventPublisher(postgresDB, "pupil")
+	classPublisher := postgresdb.NewPostgresEventPublisher(postgresDB, "class")
+	postgresMongoConfig(postgresDB, mongoClient, publisher, classPublisher, subscriber)
+}
+
+func postgresMongoConfig(
+	postgresDB *sql.DB,
+	mongoClient mongodb.Client,
+	publisher application.EventPublisher,
+	classPublisher application.EventPublisher,
+	subscriber application.EventSubscriber,
+) {
+	store := postgresdb.NewPostgresStore("pupils", postgresDB)
+	classStore := postgresdb.NewPostgresStore("school_classes", postgresDB)
+	repository := mongodb.NewPupilRepository(mongoClient, "school_book_storage", "pupils")
+	states := mongodb.NewProjectionStateRepository(mongoClient, "school_book_storage", "projection_states")
+
+	eventHandler := application.NewGapDetector("pupils", states, pupilapp.NewPupilEventHandler(repository))
+	if err := subscriber.Subscribe("pupil", eventHandler, application.NewDeadLetterPolicy(3, time.Second)); err != nil {
+		panic(err)
+	}
+
+	commandHandlers := pupilapp.NewPupilCommandHandlers(store, publisher, classStore, classPublisher)
+	queryHandlers := pupilapp.NewPupilQueryHandlers(repository)
+
+	controller := NewPupilController(commandHandlers, queryHandlers)
+	configureEndpoints(controller)
+}
+
+func configureEndpoints(controller *PupilController) {
+	web.Get(
+		"/api/pupils/get-all/",
+		web.IsAllowed(
+			controller.GetAllPupils,
+			[]userdomain.Role{userdomain.User, userdomain.Superuser, userdomain.Admin},
+		))
+	web.Get(
+		"/api/pupils/get-by-class/",
+		web.IsAllowed(
+			controller.GetPupilsByClass,
+			[]userdomain.Role{userdomain.User, userdomain.Superuser, userdomain.Admin},
+		))
+	web.Get(
+		"/api/pupils/get-by-id/",
+		web.IsAllowed(
+			controller.GetPupilByID,
+			[]userdomain.Role{userdomain.User, userdomain.Superuser, userdomain.Admin},
+		))
+	web.Post(
+		"/api/pupils/enrol",
+		web.IsAllowed(
+			controller.EnrolPupil,
+			[]userdomain.Role{userdomain.Superuser, userdomain.Admin},
+		))
+	web.Post(
+		"/api/pupils/move",
+		web.IsAllowed(
+			controller.MovePupil,
+			[]userdomain.Role{userdomain.Superuser, userdomain.Admin},
+		))
+	web.Post(
+		"/api/pupils/leave",
+		web.IsAllowed(
+			controller.LeaveSchool,
+			[]userdomain.Role{userdomain.Superuser, userdomain.Admin},
+		))
+}
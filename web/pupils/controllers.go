@@ -0,0 +1,97 @@
+package pupils
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/kammeph/school-book-storage-service/application/pupilapp"
+	"github.com/kammeph/school-book-storage-service/web"
+)
+
+type PupilController struct {
+	commandHandlers pupilapp.PupilCommandHandlers
+	queryHandlers   pupilapp.PupilQueryHandlers
+}
+
+func NewPupilController(commandHandlers pupilapp.PupilCommandHandlers, queryHandlers pupilapp.PupilQueryHandlers) *PupilController {
+	return &PupilController{commandHandlers, queryHandlers}
+}
+
+func (c PupilController) EnrolPupil(w http.ResponseWriter, r *http.Request) {
+	var command pupilapp.EnrolPupilCommand
+	json.NewDecoder(r.Body).Decode(&command)
+	ctx := context.Background()
+	defer ctx.Done()
+	pupilID, err := c.commandHandlers.EnrolPupilHandler.Handle(ctx, command)
+	if err != nil {
+		web.HttpErrorResponse(w, err.Error())
+		return
+	}
+	web.HttpResponse(w, pupilID)
+}
+
+func (c PupilController) MovePupil(w http.ResponseWriter, r *http.Request) {
+	var command pupilapp.MovePupilCommand
+	json.NewDecoder(r.Body).Decode(&command)
+	ctx := context.Background()
+	defer ctx.Done()
+	if err := c.commandHandlers.MovePupilHandler.Handle(ctx, command); err != nil {
+		web.HttpErrorResponse(w, err.Error())
+	}
+}
+
+func (c PupilController) LeaveSchool(w http.ResponseWriter, r *http.Request) {
+	var command pupilapp.LeaveSchoolCommand
+	json.NewDecoder(r.Body).Decode(&command)
+	ctx := context.Background()
+	defer ctx.Done()
+	if err := c.commandHandlers.LeaveSchoolHandler.Handle(ctx, command); err != nil {
+		web.HttpErrorResponse(w, err.Error())
+	}
+}
+
+func (c PupilController) GetAllPupils(w http.ResponseWriter, r *http.Request) {
+	ctx := context.Background()
+	defer ctx.Done()
+	path := strings.Split(r.URL.Path, "/")
+	aggregateID := path[len(path)-1]
+	query := pupilapp.NewGetAllPupils(aggregateID)
+	pupils, err := c.queryHandlers.GetAllHandler.Handle(ctx, query)
+	if err != nil {
+		web.HttpErrorResponse(w, err.Error())
+		return
+	}
+	web.HttpResponse(w, pupils)
+}
+
+func (c PupilController) GetPupilsByClass(w http.ResponseWriter, r *http.Request) {
+	ctx := context.Background()
+	defer ctx.Done()
+	path := strings.Split(r.URL.Path, "/")
+	aggregateID := path[len(path)-2]
+	classID := path[len(path)-1]
+	query := pupilapp.NewGetPupilsByClass(aggregateID, classID)
+	pupils, err := c.queryHandlers.GetByClassHandler.Handle(ctx, query)
+	if err != nil {
+		web.HttpErrorResponse(w, err.Error())
+		return
+	}
+	web.HttpResponse(w, pupils)
+}
+
+func (c PupilController) GetPupilByID(w http.ResponseWriter, r *http.Request) {
+	ctx := context.Background()
+	defer ctx.Done()
+	path := strings.Split(r.URL.Path, "/")
+	aggregateID := path[len(path)-2]
+	pupilID := path[len(path)-1]
+	query := pupilapp.NewGetPupilByID(aggregateID, pupilID)
+	pupil, err := c.queryHandlers.GetPupilByIDHandler.Handle(ctx, query)
+	if err != nil {
+		web.HttpErrorResponse(w, err.Error())
+		return
+	}
+	web.HttpResponse(w, pupil)
+}
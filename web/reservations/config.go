@@ -0,0 +1,88 @@
+package reservations
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/kammeph/school-book-storage-service/application"
+	"github.com/kammeph/school-book-storage-service/application/reservationapp"
+	"github.com/kammeph/school-book-storage-service/domain/userdomain"
+	"github.com/kammeph/school-book-storage-service/infrastructure/mongodb"
+	"github.com/kammeph/school-book-storage-service/infrastructure/postgresdb"
+	"github.com/kammeph/school-book-storage-service/infrastructure/rabbitmq"
+	"github.com/kammeph/school-book-storage-service/web"
+)
+
+func PostgresMongoRabbitConfig(postgresDB *sql.DB, mongoClient mongodb.Client, rabbit rabbitmq.AmqpConnection) {
+	publisher, err := rabbitmq.NewRabbitEventPublisher(rabbit, "reservation")
+	if err != nil {
+		panic(err)
+	}
+	subscriber, err := rabbitmq.NewRabbitEventSubscriber(rabbit)
+	if err != nil {
+		panic(err)
+	}
+	postgresMongoConfig(postgresDB, mongoClient, publisher, subscriber)
+}
+
+func PostgresMongoConfig(postgresDB *sql.DB, mongoClient mongodb.Client, subscriber application.EventSubscriber) {
+	publisher := postgresdb.NewPostgresEventPublisher(postgresDB, "reservation")
+	postgresMongoConfig(postgresDB, mongoClient, publisher, subscriber)
+}
+
+func postgresMongoConfig(
+	postgresDB *sql.DB,
+	mongoClient mongodb.Client,
+	publisher application.EventPublisher,
+	subscriber application.EventSubscriber,
+) {
+	store := postgresdb.NewPostgresStore("reservations", postgresDB)
+	storageStore := postgresdb.NewPostgresStore("storages", postgresDB)
+	classStore := postgresdb.NewPostgresStore("school_classes", postgresDB)
+	repository := mongodb.NewReservationRepository(mongoClient, "school_book_storage", "reservations")
+	storages := mongodb.NewStorageWithBookRepository(mongoClient, "school_book_storage", "storages")
+	states := mongodb.NewProjectionStateRepository(mongoClient, "school_book_storage", "projection_states")
+
+	eventHandler := application.NewGapDetector("reservations", states, reservationapp.NewReservationEventHandler(repository))
+	if err := subscriber.Subscribe("reservation", eventHandler, application.NewDeadLetterPolicy(3, time.Second)); err != nil {
+		panic(err)
+	}
+
+	fulfilmentHandler := reservationapp.NewFulfilmentEventHandler(store, publisher)
+	if err := subscriber.Subscribe("storage", fulfilmentHandler, application.NewDeadLetterPolicy(3, time.Second)); err != nil {
+		panic(err)
+	}
+
+	commandHandlers := reservationapp.NewReservationCommandHandlers(store, publisher, storageStore, classStore)
+	queryHandlers := reservationapp.NewReservationQueryHandlers(repository, storages)
+
+	controller := NewReservationController(commandHandlers, queryHandlers)
+	configureEndpoints(controller)
+}
+
+func configureEndpoints(controller *ReservationController) {
+	web.Get(
+		"/api/reservations/get-all/",
+		web.IsAllowed(
+			controller.GetAllReservations,
+			[]userdomain.Role{userdomain.User, userdomain.Superuser, userdomain.Admin},
+		))
+	web.Get(
+		"/api/reservations/get-conflicts/",
+		web.IsAllowed(
+			controller.GetReservationConflicts,
+			[]userdomain.Role{userdomain.User, userdomain.Superuser, userdomain.Admin},
+		))
+	web.Post(
+		"/api/reservations/reserve",
+		web.IsAllowed(
+			controller.ReserveBooks,
+			[]userdomain.Role{userdomain.User, userdomain.Superuser, userdomain.Admin},
+		))
+	web.Post(
+		"/api/reservations/cancel",
+		web.IsAllowed(
+			controller.CancelReservation,
+			[]userdomain.Role{userdomain.User, userdomain.Superuser, userdomain.Admin},
+		))
+}
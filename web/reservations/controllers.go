@@ -0,0 +1,74 @@
+package reservations
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/kammeph/school-book-storage-service/application/reservationapp"
+	"github.com/kammeph/school-book-storage-service/web"
+)
+
+type ReservationController struct {
+	commandHandlers reservationapp.ReservationCommandHandlers
+	queryHandlers   reservationapp.ReservationQueryHandlers
+}
+
+func NewReservationController(
+	commandHandlers reservationapp.ReservationCommandHandlers,
+	queryHandlers reservationapp.ReservationQueryHandlers,
+) *ReservationController {
+	return &ReservationController{commandHandlers, queryHandlers}
+}
+
+func (c ReservationController) ReserveBooks(w http.ResponseWriter, r *http.Request) {
+	var command reservationapp.ReserveBooksCommand
+	json.NewDecoder(r.Body).Decode(&command)
+	ctx := context.Background()
+	defer ctx.Done()
+	reservationID, err := c.commandHandlers.ReserveBooksHandler.Handle(ctx, command)
+	if err != nil {
+		web.HttpErrorResponse(w, err.Error())
+		return
+	}
+	web.HttpResponse(w, reservationID)
+}
+
+func (c ReservationController) CancelReservation(w http.ResponseWriter, r *http.Request) {
+	var command reservationapp.CancelReservationCommand
+	json.NewDecoder(r.Body).Decode(&command)
+	ctx := context.Background()
+	defer ctx.Done()
+	if err := c.commandHandlers.CancelReservationHandler.Handle(ctx, command); err != nil {
+		web.HttpErrorResponse(w, err.Error())
+	}
+}
+
+func (c ReservationController) GetAllReservations(w http.ResponseWriter, r *http.Request) {
+	ctx := context.Background()
+	defer ctx.Done()
+	path := strings.Split(r.URL.Path, "/")
+	aggregateID := path[len(path)-1]
+	query := reservationapp.NewGetAllReservations(aggregateID)
+	reservations, err := c.queryHandlers.GetAllHandler.Handle(ctx, query)
+	if err != nil {
+		web.HttpErrorResponse(w, err.Error())
+		return
+	}
+	web.HttpResponse(w, reservations)
+}
+
+func (c ReservationController) GetReservationConflicts(w http.ResponseWriter, r *http.Request) {
+	ctx := context.Background()
+	defer ctx.Done()
+	path := strings.Split(r.URL.Path, "/")
+	aggregateID := path[len(path)-1]
+	query := reservationapp.NewGetReservationConflicts(aggregateID)
+	conflicts, err := c.queryHandlers.GetConflictsHandler.Handle(ctx, query)
+	if err != nil {
+		web.HttpErrorResponse(w, err.Error())
+		return
+	}
+	web.HttpResponse(w, conflicts)
+}
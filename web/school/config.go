@@ -2,7 +2,9 @@ package school
 
 import (
 	"database/sql"
+	"time"
 
+	"github.com/kammeph/school-book-storage-service/application"
 	"github.com/kammeph/school-book-storage-service/application/schoolapp"
 	"github.com/kammeph/school-book-storage-service/domain/userdomain"
 	"github.com/kammeph/school-book-storage-service/infrastructure/mongodb"
@@ -20,12 +22,28 @@ func PostgresMongoRabbitConfig(postgresDB *sql.DB, mongoClient mongodb.Client, r
 	if err != nil {
 		panic(err)
 	}
+	postgresMongoConfig(postgresDB, mongoClient, publisher, subscriber)
+}
+
+func PostgresMongoConfig(postgresDB *sql.DB, mongoClient mongodb.Client, subscriber application.EventSubscriber) {
+	publisher := postgresdb.NewPostgresEventPublisher(postgresDB, "school")
+	postgresMongoConfig(postgresDB, mongoClient, publisher, subscriber)
+}
 
+func postgresMongoConfig(
+	postgresDB *sql.DB,
+	mongoClient mongodb.Client,
+	publisher application.EventPublisher,
+	subscriber application.EventSubscriber,
+) {
 	store := postgresdb.NewPostgresStore("schools", postgresDB)
 	repository := mongodb.NewSchoolRepository(mongoClient, "school_book_storage", "schools")
+	states := mongodb.NewProjectionStateRepository(mongoClient, "school_book_storage", "projection_states")
 
-	eventHandler := schoolapp.NewSchoolEventHandler(repository)
-	subscriber.Subscribe("school", eventHandler)
+	eventHandler := application.NewGapDetector("schools", states, schoolapp.NewSchoolEventHandler(repository))
+	if err := subscriber.Subscribe("school", eventHandler, application.NewDeadLetterPolicy(3, time.Second)); err != nil {
+		panic(err)
+	}
 
 	commandHandlers := schoolapp.NewSchoolCommandHandlers(store, publisher)
 	queryHandlers := schoolapp.NewSchoolQueryHandlers(repository)
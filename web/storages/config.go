@@ -2,7 +2,9 @@ package storages
 
 import (
 	"database/sql"
+	"time"
 
+	"github.com/kammeph/school-book-storage-service/application"
 	"github.com/kammeph/school-book-storage-service/application/storageapp"
 	"github.com/kammeph/school-book-storage-service/domain/userdomain"
 	"github.com/kammeph/school-book-storage-service/infrastructure/memory"
@@ -15,14 +17,26 @@ import (
 func InMemoryConfig() {
 	broker := memory.NewMemoryMessageBroker()
 	store := memory.NewMemoryStore()
+	reservationStore := memory.NewMemoryStore()
 	repository := memory.NewMemoryRepository()
+	stocktakings := memory.NewMemoryStocktakingRepository()
+	locations := memory.NewMemoryLocationRepository()
+	writeOffs := memory.NewMemoryWriteOffRepository()
+	books := memory.NewMemoryBookRepository()
+	states := memory.NewMemoryProjectionStateRepository()
 
-	eventHandler := storageapp.NewStorageEventHandler(repository)
-	broker.Subscribe("storage", eventHandler)
-	broker.Subscribe("storage", &storageapp.TestHandler{})
+	eventHandler := application.NewGapDetector("storages", states, storageapp.NewStorageEventHandler(repository))
+	broker.Subscribe("storage", eventHandler, application.NewRetryPolicy(3, time.Second))
+	stocktakingHandler := application.NewGapDetector("stocktakings", states, storageapp.NewStocktakingEventHandler(stocktakings))
+	broker.Subscribe("storage", stocktakingHandler, application.NewRetryPolicy(3, time.Second))
+	locationHandler := application.NewGapDetector("storage_locations", states, storageapp.NewLocationEventHandler(locations))
+	broker.Subscribe("storage", locationHandler, application.NewRetryPolicy(3, time.Second))
+	writeOffHandler := application.NewGapDetector("write_offs", states, storageapp.NewWriteOffEventHandler(writeOffs))
+	broker.Subscribe("storage", writeOffHandler, application.NewRetryPolicy(3, time.Second))
+	broker.Subscribe("storage", &storageapp.TestHandler{}, application.NewSkipPolicy())
 
-	commandHandlers := storageapp.NewStorageCommandHandlers(store, broker)
-	queryHandlers := storageapp.NewStorageQueryHandlers(repository)
+	commandHandlers := storageapp.NewStorageCommandHandlers(store, broker, reservationStore)
+	queryHandlers := storageapp.NewStorageQueryHandlers(repository, stocktakings, locations, writeOffs, books)
 
 	controller := NewStorageController(commandHandlers, queryHandlers)
 	configureEndpoints(controller)
@@ -37,15 +51,51 @@ func PostgresMongoRabbitConfig(postgresDB *sql.DB, mongoClient mongodb.Client, r
 	if err != nil {
 		panic(err)
 	}
+	postgresMongoConfig(postgresDB, mongoClient, publisher, subscriber)
+}
+
+func PostgresMongoConfig(postgresDB *sql.DB, mongoClient mongodb.Client, subscriber application.EventSubscriber) {
+	publisher := postgresdb.NewPostgresEventPublisher(postgresDB, "storage")
+	postgresMongoConfig(postgresDB, mongoClient, publisher, subscriber)
+}
+
+func postgresMongoConfig(
+	postgresDB *sql.DB,
+	mongoClient mongodb.Client,
+	publisher application.EventPublisher,
+	subscriber application.EventSubscriber,
+) {
 	store := postgresdb.NewPostgresStore("storages", postgresDB)
+	reservationStore := postgresdb.NewPostgresStore("reservations", postgresDB)
 	repository := mongodb.NewStorageWithBookRepository(mongoClient, "school_book_storage", "storages")
+	stocktakings := mongodb.NewStocktakingRepository(mongoClient, "school_book_storage", "stocktakings")
+	locations := mongodb.NewLocationRepository(mongoClient, "school_book_storage", "storage_locations")
+	writeOffs := mongodb.NewWriteOffRepository(mongoClient, "school_book_storage", "write_offs")
+	books := mongodb.NewBookRepository(mongoClient, "school_book_storage", "books")
+	states := mongodb.NewProjectionStateRepository(mongoClient, "school_book_storage", "projection_states")
 
-	eventHandler := storageapp.NewStorageEventHandler(repository)
-	subscriber.Subscribe("storage", eventHandler)
-	subscriber.Subscribe("storage", &storageapp.TestHandler{})
+	eventHandler := application.NewGapDetector("storages", states, storageapp.NewStorageEventHandler(repository))
+	if err := subscriber.Subscribe("storage", eventHandler, application.NewDeadLetterPolicy(3, time.Second)); err != nil {
+		panic(err)
+	}
+	stocktakingHandler := application.NewGapDetector("stocktakings", states, storageapp.NewStocktakingEventHandler(stocktakings))
+	if err := subscriber.Subscribe("storage", stocktakingHandler, application.NewDeadLetterPolicy(3, time.Second)); err != nil {
+		panic(err)
+	}
+	locationHandler := application.NewGapDetector("storage_locations", states, storageapp.NewLocationEventHandler(locations))
+	if err := subscriber.Subscribe("storage", locationHandler, application.NewDeadLetterPolicy(3, time.Second)); err != nil {
+		panic(err)
+	}
+	writeOffHandler := application.NewGapDetector("write_offs", states, storageapp.NewWriteOffEventHandler(writeOffs))
+	if err := subscriber.Subscribe("storage", writeOffHandler, application.NewDeadLetterPolicy(3, time.Second)); err != nil {
+		panic(err)
+	}
+	if err := subscriber.Subscribe("storage", &storageapp.TestHandler{}, application.NewSkipPolicy()); err != nil {
+		panic(err)
+	}
 
-	commandHandlers := storageapp.NewStorageCommandHandlers(store, publisher)
-	queryHandlers := storageapp.NewStorageQueryHandlers(repository)
+	commandHandlers := storageapp.NewStorageCommandHandlers(store, publisher, reservationStore)
+	queryHandlers := storageapp.NewStorageQueryHandlers(repository, stocktakings, locations, writeOffs, books)
 
 	controller := NewStorageController(commandHandlers, queryHandlers)
 	configureEndpoints(controller)
@@ -70,6 +120,42 @@ func configureEndpoints(controller *StorageController) {
 			controller.GetStorageByName,
 			[]userdomain.Role{userdomain.User, userdomain.Superuser, userdomain.Admin},
 		))
+	web.Get(
+		"/api/storages/get-stocktakings/",
+		web.IsAllowed(
+			controller.GetStocktakings,
+			[]userdomain.Role{userdomain.User, userdomain.Superuser, userdomain.Admin},
+		))
+	web.Get(
+		"/api/storages/get-discrepancies/",
+		web.IsAllowed(
+			controller.GetDiscrepancies,
+			[]userdomain.Role{userdomain.User, userdomain.Superuser, userdomain.Admin},
+		))
+	web.Get(
+		"/api/storages/labels/",
+		web.IsAllowed(
+			controller.GetStorageLabels,
+			[]userdomain.Role{userdomain.User, userdomain.Superuser, userdomain.Admin},
+		))
+	web.Get(
+		"/api/storages/get-locations/",
+		web.IsAllowed(
+			controller.GetLocations,
+			[]userdomain.Role{userdomain.User, userdomain.Superuser, userdomain.Admin},
+		))
+	web.Get(
+		"/api/storages/get-location-contents/",
+		web.IsAllowed(
+			controller.GetLocationContents,
+			[]userdomain.Role{userdomain.User, userdomain.Superuser, userdomain.Admin},
+		))
+	web.Get(
+		"/api/storages/get-write-offs/",
+		web.IsAllowed(
+			controller.GetWriteOffReport,
+			[]userdomain.Role{userdomain.User, userdomain.Superuser, userdomain.Admin},
+		))
 	web.Post(
 		"/api/storages/add",
 		web.IsAllowed(
@@ -94,4 +180,76 @@ func configureEndpoints(controller *StorageController) {
 			controller.RelocateStorage,
 			[]userdomain.Role{userdomain.Admin},
 		))
+	web.Post(
+		"/api/storages/change-capacity",
+		web.IsAllowed(
+			controller.ChangeCapacity,
+			[]userdomain.Role{userdomain.Admin},
+		))
+	web.Post(
+		"/api/storages/add-location",
+		web.IsAllowed(
+			controller.AddLocation,
+			[]userdomain.Role{userdomain.Admin},
+		))
+	web.Post(
+		"/api/storages/rename-location",
+		web.IsAllowed(
+			controller.RenameLocation,
+			[]userdomain.Role{userdomain.Admin},
+		))
+	web.Post(
+		"/api/storages/remove-location",
+		web.IsAllowed(
+			controller.RemoveLocation,
+			[]userdomain.Role{userdomain.Admin},
+		))
+	web.Post(
+		"/api/storages/put-books",
+		web.IsAllowed(
+			controller.PutBooks,
+			[]userdomain.Role{userdomain.Superuser, userdomain.Admin},
+		))
+	web.Post(
+		"/api/storages/take-books",
+		web.IsAllowed(
+			controller.TakeBooks,
+			[]userdomain.Role{userdomain.Superuser, userdomain.Admin},
+		))
+	web.Post(
+		"/api/storages/transfer-books",
+		web.IsAllowed(
+			controller.TransferBooks,
+			[]userdomain.Role{userdomain.Superuser, userdomain.Admin},
+		))
+	web.Post(
+		"/api/storages/open-stocktaking",
+		web.IsAllowed(
+			controller.OpenStocktaking,
+			[]userdomain.Role{userdomain.Superuser, userdomain.Admin},
+		))
+	web.Post(
+		"/api/storages/count-stock",
+		web.IsAllowed(
+			controller.CountStock,
+			[]userdomain.Role{userdomain.Superuser, userdomain.Admin},
+		))
+	web.Post(
+		"/api/storages/approve-stocktaking",
+		web.IsAllowed(
+			controller.ApproveStocktaking,
+			[]userdomain.Role{userdomain.Admin},
+		))
+	web.Post(
+		"/api/storages/record-damage",
+		web.IsAllowed(
+			controller.RecordDamage,
+			[]userdomain.Role{userdomain.Superuser, userdomain.Admin},
+		))
+	web.Post(
+		"/api/storages/write-off",
+		web.IsAllowed(
+			controller.WriteOffBooks,
+			[]userdomain.Role{userdomain.Admin},
+		))
 }
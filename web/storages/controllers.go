@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"net/http"
+	"strconv"
 	"strings"
 
 	"github.com/kammeph/school-book-storage-service/application/storageapp"
@@ -65,6 +66,39 @@ func (c StorageController) RelocateStorage(w http.ResponseWriter, r *http.Reques
 	}
 }
 
+func (c StorageController) PutBooks(w http.ResponseWriter, r *http.Request) {
+	var command storageapp.PutBooksCommand
+	json.NewDecoder(r.Body).Decode(&command)
+	ctx := context.Background()
+	defer ctx.Done()
+	err := c.commmandHandlers.PutBooksHandler.Handle(ctx, command)
+	if err != nil {
+		web.HttpErrorResponse(w, err.Error())
+	}
+}
+
+func (c StorageController) TakeBooks(w http.ResponseWriter, r *http.Request) {
+	var command storageapp.TakeBooksCommand
+	json.NewDecoder(r.Body).Decode(&command)
+	ctx := context.Background()
+	defer ctx.Done()
+	err := c.commmandHandlers.TakeBooksHandler.Handle(ctx, command)
+	if err != nil {
+		web.HttpErrorResponse(w, err.Error())
+	}
+}
+
+func (c StorageController) TransferBooks(w http.ResponseWriter, r *http.Request) {
+	var command storageapp.TransferBooksCommand
+	json.NewDecoder(r.Body).Decode(&command)
+	ctx := context.Background()
+	defer ctx.Done()
+	err := c.commmandHandlers.TransferBooksHandler.Handle(ctx, command)
+	if err != nil {
+		web.HttpErrorResponse(w, err.Error())
+	}
+}
+
 func (c StorageController) GetAllStorages(w http.ResponseWriter, r *http.Request) {
 	ctx := context.Background()
 	defer ctx.Done()
@@ -108,3 +142,201 @@ func (c StorageController) GetStorageByName(w http.ResponseWriter, r *http.Reque
 	}
 	web.HttpResponse(w, storage)
 }
+
+func (c StorageController) OpenStocktaking(w http.ResponseWriter, r *http.Request) {
+	var command storageapp.OpenStocktakingCommand
+	json.NewDecoder(r.Body).Decode(&command)
+	ctx := context.Background()
+	defer ctx.Done()
+	stocktakingID, err := c.commmandHandlers.OpenStocktakingHandler.Handle(ctx, command)
+	if err != nil {
+		web.HttpErrorResponse(w, err.Error())
+		return
+	}
+	web.HttpResponse(w, stocktakingID)
+}
+
+func (c StorageController) CountStock(w http.ResponseWriter, r *http.Request) {
+	var command storageapp.CountStockCommand
+	json.NewDecoder(r.Body).Decode(&command)
+	ctx := context.Background()
+	defer ctx.Done()
+	err := c.commmandHandlers.CountStockHandler.Handle(ctx, command)
+	if err != nil {
+		web.HttpErrorResponse(w, err.Error())
+	}
+}
+
+func (c StorageController) ApproveStocktaking(w http.ResponseWriter, r *http.Request) {
+	var command storageapp.ApproveStocktakingCommand
+	json.NewDecoder(r.Body).Decode(&command)
+	ctx := context.Background()
+	defer ctx.Done()
+	discrepancies, err := c.commmandHandlers.ApproveStocktakingHandler.Handle(ctx, command)
+	if err != nil {
+		web.HttpErrorResponse(w, err.Error())
+		return
+	}
+	web.HttpResponse(w, discrepancies)
+}
+
+func (c StorageController) GetStocktakings(w http.ResponseWriter, r *http.Request) {
+	ctx := context.Background()
+	defer ctx.Done()
+	path := strings.Split(r.URL.Path, "/")
+	aggregateID := path[len(path)-1]
+	query := storageapp.NewGetStocktakings(aggregateID)
+	stocktakings, err := c.queryHandlers.GetStocktakingsHandler.Handle(ctx, query)
+	if err != nil {
+		web.HttpErrorResponse(w, err.Error())
+		return
+	}
+	web.HttpResponse(w, stocktakings)
+}
+
+func (c StorageController) GetDiscrepancies(w http.ResponseWriter, r *http.Request) {
+	ctx := context.Background()
+	defer ctx.Done()
+	path := strings.Split(r.URL.Path, "/")
+	aggregateID := path[len(path)-2]
+	stocktakingID := path[len(path)-1]
+	query := storageapp.NewGetDiscrepancies(aggregateID, stocktakingID)
+	discrepancies, err := c.queryHandlers.GetDiscrepanciesHandler.Handle(ctx, query)
+	if err != nil {
+		web.HttpErrorResponse(w, err.Error())
+		return
+	}
+	web.HttpResponse(w, discrepancies)
+}
+
+func (c StorageController) RecordDamage(w http.ResponseWriter, r *http.Request) {
+	var command storageapp.RecordDamageCommand
+	json.NewDecoder(r.Body).Decode(&command)
+	ctx := context.Background()
+	defer ctx.Done()
+	err := c.commmandHandlers.RecordDamageHandler.Handle(ctx, command)
+	if err != nil {
+		web.HttpErrorResponse(w, err.Error())
+	}
+}
+
+func (c StorageController) WriteOffBooks(w http.ResponseWriter, r *http.Request) {
+	var command storageapp.WriteOffBooksCommand
+	json.NewDecoder(r.Body).Decode(&command)
+	ctx := context.Background()
+	defer ctx.Done()
+	err := c.commmandHandlers.WriteOffBooksHandler.Handle(ctx, command)
+	if err != nil {
+		web.HttpErrorResponse(w, err.Error())
+	}
+}
+
+func (c StorageController) GetWriteOffReport(w http.ResponseWriter, r *http.Request) {
+	ctx := context.Background()
+	defer ctx.Done()
+	path := strings.Split(r.URL.Path, "/")
+	aggregateID := path[len(path)-2]
+	year, err := strconv.Atoi(path[len(path)-1])
+	if err != nil {
+		web.HttpErrorResponse(w, err.Error())
+		return
+	}
+	query := storageapp.NewGetWriteOffReport(aggregateID, year)
+	report, err := c.queryHandlers.GetWriteOffsHandler.Handle(ctx, query)
+	if err != nil {
+		web.HttpErrorResponse(w, err.Error())
+		return
+	}
+	web.HttpResponse(w, report)
+}
+
+// GetStorageLabels serves /api/storages/labels/{schoolId}?ids={storageIds} and
+// responds with printable QR code labels for the selected storages.
+func (c StorageController) GetStorageLabels(w http.ResponseWriter, r *http.Request) {
+	ctx := context.Background()
+	defer ctx.Done()
+	path := strings.Split(r.URL.Path, "/")
+	aggregateID := path[len(path)-1]
+	query := storageapp.NewGetStorageLabels(aggregateID, web.QueryList(r, "ids"))
+	labels, err := c.queryHandlers.GetLabelsHandler.Handle(ctx, query)
+	if err != nil {
+		web.HttpErrorResponse(w, err.Error())
+		return
+	}
+	web.LabelResponse(w, r, "storage-labels", labels)
+}
+
+func (c StorageController) ChangeCapacity(w http.ResponseWriter, r *http.Request) {
+	var command storageapp.ChangeCapacityCommand
+	json.NewDecoder(r.Body).Decode(&command)
+	ctx := context.Background()
+	defer ctx.Done()
+	err := c.commmandHandlers.ChangeCapacityHandler.Handle(ctx, command)
+	if err != nil {
+		web.HttpErrorResponse(w, err.Error())
+	}
+}
+
+func (c StorageController) AddLocation(w http.ResponseWriter, r *http.Request) {
+	var command storageapp.AddLocationCommand
+	json.NewDecoder(r.Body).Decode(&command)
+	ctx := context.Background()
+	defer ctx.Done()
+	locationID, err := c.commmandHandlers.AddLocationHandler.Handle(ctx, command)
+	if err != nil {
+		web.HttpErrorResponse(w, err.Error())
+		return
+	}
+	web.HttpResponse(w, locationID)
+}
+
+func (c StorageController) RenameLocation(w http.ResponseWriter, r *http.Request) {
+	var command storageapp.RenameLocationCommand
+	json.NewDecoder(r.Body).Decode(&command)
+	ctx := context.Background()
+	defer ctx.Done()
+	err := c.commmandHandlers.RenameLocationHandler.Handle(ctx, command)
+	if err != nil {
+		web.HttpErrorResponse(w, err.Error())
+	}
+}
+
+func (c StorageController) RemoveLocation(w http.ResponseWriter, r *http.Request) {
+	var command storageapp.RemoveLocationCommand
+	json.NewDecoder(r.Body).Decode(&command)
+	ctx := context.Background()
+	defer ctx.Done()
+	err := c.commmandHandlers.RemoveLocationHandler.Handle(ctx, command)
+	if err != nil {
+		web.HttpErrorResponse(w, err.Error())
+	}
+}
+
+func (c StorageController) GetLocations(w http.ResponseWriter, r *http.Request) {
+	ctx := context.Background()
+	defer ctx.Done()
+	path := strings.Split(r.URL.Path, "/")
+	aggregateID := path[len(path)-1]
+	query := storageapp.NewGetLocations(aggregateID)
+	locations, err := c.queryHandlers.GetLocationsHandler.Handle(ctx, query)
+	if err != nil {
+		web.HttpErrorResponse(w, err.Error())
+		return
+	}
+	web.HttpResponse(w, locations)
+}
+
+func (c StorageController) GetLocationContents(w http.ResponseWriter, r *http.Request) {
+	ctx := context.Background()
+	defer ctx.Done()
+	path := strings.Split(r.URL.Path, "/")
+	aggregateID := path[len(path)-2]
+	locationID := path[len(path)-1]
+	query := storageapp.NewGetLocationContents(aggregateID, locationID)
+	contents, err := c.queryHandlers.GetLocationContentsHandler.Handle(ctx, query)
+	if err != nil {
+		web.HttpErrorResponse(w, err.Error())
+		return
+	}
+	web.HttpResponse(w, contents)
+}
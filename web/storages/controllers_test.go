@@ -39,13 +39,18 @@ func getStorageController() *storages.StorageController {
 		Data:    string(eventDataForUpdate),
 	}
 	store := memory.NewMemoryStoreWithEvents([]domain.Event{&eventForRemove, &eventForUpdate})
-	storage1School1 := storagedomain.NewStorageWithBooks("school1", "storage1School1", "Closet 1", "Room 101")
-	storage2School1 := storagedomain.NewStorageWithBooks("school1", "storage2School1", "Closet 2", "Room 101")
-	storage1School2 := storagedomain.NewStorageWithBooks("school2", "storage1School2", "Closet 1", "Room 203")
+	storage1School1 := storagedomain.NewStorageWithBooks("school1", "storage1School1", "Closet 1", "Room 101", 1)
+	storage2School1 := storagedomain.NewStorageWithBooks("school1", "storage2School1", "Closet 2", "Room 101", 1)
+	storage1School2 := storagedomain.NewStorageWithBooks("school2", "storage1School2", "Closet 1", "Room 203", 1)
 	repository := memory.NewMemoryRepositoryWithStorages(
 		[]storagedomain.StorageWithBooks{storage1School1, storage2School1, storage1School2})
-	commandHandlers := storageapp.NewStorageCommandHandlers(store, nil)
-	queryHandlers := storageapp.NewStorageQueryHandlers(repository)
+	commandHandlers := storageapp.NewStorageCommandHandlers(store, nil, memory.NewMemoryStore())
+	queryHandlers := storageapp.NewStorageQueryHandlers(
+		repository,
+		memory.NewMemoryStocktakingRepository(),
+		memory.NewMemoryLocationRepository(),
+		memory.NewMemoryWriteOffRepository(),
+		memory.NewMemoryBookRepository())
 	return storages.NewStorageController(commandHandlers, queryHandlers)
 }
 
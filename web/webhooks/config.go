@@ -0,0 +1,110 @@
+package webhooks
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/kammeph/school-book-storage-service/application"
+	"github.com/kammeph/school-book-storage-service/application/webhookapp"
+	"github.com/kammeph/school-book-storage-service/domain/userdomain"
+	"github.com/kammeph/school-book-storage-service/infrastructure/httpclient"
+	"github.com/kammeph/school-book-storage-service/infrastructure/mongodb"
+	"github.com/kammeph/school-book-storage-service/infrastructure/postgresdb"
+	"github.com/kammeph/school-book-storage-service/infrastructure/rabbitmq"
+	"github.com/kammeph/school-book-storage-service/web"
+)
+
+// Exchanges lists the exchanges whose events are delivered to the webhooks.
+var Exchanges = []string{"storage", "school", "book"}
+
+func PostgresMongoRabbitConfig(postgresDB *sql.DB, mongoClient mongodb.Client, rabbit rabbitmq.AmqpConnection) {
+	publisher, err := rabbitmq.NewRabbitEventPublisher(rabbit, "webhook")
+	if err != nil {
+		panic(err)
+	}
+	subscriber, err := rabbitmq.NewRabbitEventSubscriber(rabbit)
+	if err != nil {
+		panic(err)
+	}
+	postgresMongoConfig(postgresDB, mongoClient, publisher, subscriber)
+}
+
+func PostgresMongoConfig(postgresDB *sql.DB, mongoClient mongodb.Client, subscriber application.EventSubscriber) {
+	publisher := postgresdb.NewPostgresEventPublisher(postgresDB, "webhook")
+	postgresMongoConfig(postgresDB, mongoClient, publisher, subscriber)
+}
+
+func postgresMongoConfig(
+	postgresDB *sql.DB,
+	mongoClient mongodb.Client,
+	publisher application.EventPublisher,
+	subscriber application.EventSubscriber,
+) {
+	store := postgresdb.NewPostgresStore("webhooks", postgresDB)
+	webhooks := mongodb.NewWebhookRepository(mongoClient, "school_book_storage", "webhooks")
+	deliveries := mongodb.NewDeliveryRepository(mongoClient, "school_book_storage", "webhook_deliveries")
+	states := mongodb.NewProjectionStateRepository(mongoClient, "school_book_storage", "projection_states")
+	secrets := postgresdb.NewPostgresWebhookSecretStore(postgresDB)
+
+	commandHandlers := webhookapp.NewWebhookCommandHandlers(store, publisher, secrets)
+	queryHandlers := webhookapp.NewWebhookQueryHandlers(webhooks, deliveries)
+
+	eventHandler := application.NewGapDetector("webhooks", states, webhookapp.NewWebhookEventHandler(webhooks, secrets))
+	if err := subscriber.Subscribe("webhook", eventHandler, application.NewDeadLetterPolicy(3, time.Second)); err != nil {
+		panic(err)
+	}
+	dispatcher := webhookapp.NewWebhookDispatcher(
+		webhooks,
+		deliveries,
+		secrets,
+		httpclient.NewHttpWebhookSender(10*time.Second),
+		commandHandlers.DisableWebhookHandler,
+		webhookapp.DefaultDispatcherOptions())
+	for _, exchange := range Exchanges {
+		if err := subscriber.Subscribe(exchange, dispatcher, application.NewSkipPolicy()); err != nil {
+			panic(err)
+		}
+	}
+
+	controller := NewWebhookController(commandHandlers, queryHandlers)
+	configureEndpoints(controller)
+}
+
+func configureEndpoints(controller *WebhookController) {
+	web.Get(
+		"/api/webhooks/get-all/",
+		web.IsAllowed(
+			controller.GetWebhooks,
+			[]userdomain.Role{userdomain.Admin},
+		))
+	web.Get(
+		"/api/webhooks/deliveries/",
+		web.IsAllowed(
+			controller.GetDeliveries,
+			[]userdomain.Role{userdomain.Admin},
+		))
+	web.Post(
+		"/api/webhooks/register",
+		web.IsAllowed(
+			controller.RegisterWebhook,
+			[]userdomain.Role{userdomain.Admin},
+		))
+	web.Post(
+		"/api/webhooks/remove",
+		web.IsAllowed(
+			controller.RemoveWebhook,
+			[]userdomain.Role{userdomain.Admin},
+		))
+	web.Post(
+		"/api/webhooks/disable",
+		web.IsAllowed(
+			controller.DisableWebhook,
+			[]userdomain.Role{userdomain.Admin},
+		))
+	web.Post(
+		"/api/webhooks/enable",
+		web.IsAllowed(
+			controller.EnableWebhook,
+			[]userdomain.Role{userdomain.Admin},
+		))
+}
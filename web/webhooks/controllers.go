@@ -0,0 +1,97 @@
+package webhooks
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/kammeph/school-book-storage-service/application/webhookapp"
+	"github.com/kammeph/school-book-storage-service/web"
+)
+
+type RegisteredWebhook struct {
+	WebhookID string `json:"webhookId"`
+	Secret    string `json:"secret"`
+}
+
+type WebhookController struct {
+	commandHandlers webhookapp.WebhookCommandHandlers
+	queryHandlers   webhookapp.WebhookQueryHandlers
+}
+
+func NewWebhookController(commandHandlers webhookapp.WebhookCommandHandlers, queryHandlers webhookapp.WebhookQueryHandlers) *WebhookController {
+	return &WebhookController{commandHandlers, queryHandlers}
+}
+
+func (c WebhookController) RegisterWebhook(w http.ResponseWriter, r *http.Request) {
+	var command webhookapp.RegisterWebhookCommand
+	json.NewDecoder(r.Body).Decode(&command)
+	ctx := context.Background()
+	defer ctx.Done()
+	webhookID, secret, err := c.commandHandlers.RegisterWebhookHandler.Handle(ctx, command)
+	if err != nil {
+		web.HttpErrorResponse(w, err.Error())
+		return
+	}
+	web.HttpResponse(w, RegisteredWebhook{webhookID, secret})
+}
+
+func (c WebhookController) RemoveWebhook(w http.ResponseWriter, r *http.Request) {
+	var command webhookapp.RemoveWebhookCommand
+	json.NewDecoder(r.Body).Decode(&command)
+	ctx := context.Background()
+	defer ctx.Done()
+	if err := c.commandHandlers.RemoveWebhookHandler.Handle(ctx, command); err != nil {
+		web.HttpErrorResponse(w, err.Error())
+	}
+}
+
+func (c WebhookController) DisableWebhook(w http.ResponseWriter, r *http.Request) {
+	var command webhookapp.DisableWebhookCommand
+	json.NewDecoder(r.Body).Decode(&command)
+	ctx := context.Background()
+	defer ctx.Done()
+	if err := c.commandHandlers.DisableWebhookHandler.Handle(ctx, command); err != nil {
+		web.HttpErrorResponse(w, err.Error())
+	}
+}
+
+func (c WebhookController) EnableWebhook(w http.ResponseWriter, r *http.Request) {
+	var command webhookapp.EnableWebhookCommand
+	json.NewDecoder(r.Body).Decode(&command)
+	ctx := context.Background()
+	defer ctx.Done()
+	if err := c.commandHandlers.EnableWebhookHandler.Handle(ctx, command); err != nil {
+		web.HttpErrorResponse(w, err.Error())
+	}
+}
+
+func (c WebhookController) GetWebhooks(w http.ResponseWriter, r *http.Request) {
+	ctx := context.Background()
+	defer ctx.Done()
+	path := strings.Split(r.URL.Path, "/")
+	aggregateID := path[len(path)-1]
+	query := webhookapp.NewGetWebhooks(aggregateID)
+	webhooks, err := c.queryHandlers.GetWebhooksHandler.Handle(ctx, query)
+	if err != nil {
+		web.HttpErrorResponse(w, err.Error())
+		return
+	}
+	web.HttpResponse(w, webhooks)
+}
+
+func (c WebhookController) GetDeliveries(w http.ResponseWriter, r *http.Request) {
+	ctx := context.Background()
+	defer ctx.Done()
+	path := strings.Split(r.URL.Path, "/")
+	aggregateID := path[len(path)-2]
+	webhookID := path[len(path)-1]
+	query := webhookapp.NewGetDeliveries(aggregateID, webhookID)
+	deliveries, err := c.queryHandlers.GetDeliveriesHandler.Handle(ctx, query)
+	if err != nil {
+		web.HttpErrorResponse(w, err.Error())
+		return
+	}
+	web.HttpResponse(w, deliveries)
+}